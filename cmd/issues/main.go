@@ -0,0 +1,282 @@
+// Command issues is a command-line client for the issues API, for
+// terminal-first users and CI scripts.
+//
+// It reads the server URL from ISSUES_URL and a personal access token,
+// created with POST /api/v1/me/tokens, from ISSUES_TOKEN.
+//
+//	issues list -project 1 -status open,in_progress
+//	issues create -project 1 -title "Flaky test" -body-file report.md
+//	issues run -project 1 -issue 42 -watch
+//	issues logs -project 1 -issue 42
+//	issues mcp
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+const usage = `Usage: issues <command> [flags]
+
+Commands:
+  list     list issues in a project
+  create   create an issue
+  run      run AI on an issue
+  logs     follow the output of an issue's AI job
+  mcp      serve issues to AI agents over the Model Context Protocol
+
+Environment:
+  ISSUES_URL    server URL, such as https://issues.example.com
+  ISSUES_TOKEN  personal access token
+
+Run "issues <command> -h" for the flags of a command.
+`
+
+// errJobFailed makes the exit status non-zero when a watched job fails.
+var errJobFailed = errors.New("ai job did not complete")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(context.Context, *apiclient.Client, []string) error{
+		"list":   listIssues,
+		"create": createIssue,
+		"run":    runAI,
+		"logs":   followLogs,
+		"mcp":    serveMCP,
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+			fmt.Fprintf(os.Stderr, "issues: unknown command %q\n\n", os.Args[1])
+		}
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	baseURL, token := os.Getenv("ISSUES_URL"), os.Getenv("ISSUES_TOKEN")
+	if baseURL == "" || token == "" {
+		fmt.Fprintln(os.Stderr, "issues: ISSUES_URL and ISSUES_TOKEN must be set")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := apiclient.NewClient(baseURL, token,
+		apiclient.WithRetries(3), apiclient.WithUserAgent("issues-cli/"+apiclient.Version))
+	if err := cmd(ctx, client, os.Args[2:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "issues:", err)
+		}
+		stop()
+		os.Exit(1)
+	}
+}
+
+func listIssues(ctx context.Context, client *apiclient.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	projectID := fs.Int64("project", 0, "project ID (required)")
+	status := fs.String("status", "", "comma-separated statuses to include")
+	labels := fs.String("labels", "", "comma-separated labels the issues must have")
+	labelMode := fs.String("label-mode", "", "all (default) to require every label, any to require one")
+	limit := fs.Int("limit", 0, "issues per page")
+	all := fs.Bool("all", false, "fetch every page")
+	asJSON := fs.Bool("json", false, "print issues as JSON lines")
+	if err := parse(fs, args, "-project is required", func() bool { return *projectID != 0 }); err != nil {
+		return err
+	}
+
+	opts := apiclient.IssueListOptions{Statuses: splitList(*status), Labels: splitList(*labels), LabelMode: *labelMode, Limit: *limit}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if !*asJSON {
+		fmt.Fprintln(w, "ID\tSTATUS\tTITLE")
+	}
+	show := func(i apiclient.Issue) error {
+		if *asJSON {
+			return printJSON(i)
+		}
+		_, err := fmt.Fprintf(w, "%d\t%s\t%s\n", i.ID, i.Status, i.Title)
+		return err
+	}
+
+	if *all {
+		for i, err := range client.Issues(ctx, *projectID, opts) {
+			if err != nil {
+				return err
+			}
+			if err := show(i); err != nil {
+				return err
+			}
+		}
+	} else {
+		issues, _, err := client.ListIssues(ctx, *projectID, opts)
+		if err != nil {
+			return err
+		}
+		for _, i := range issues {
+			if err := show(i); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Flush()
+}
+
+func createIssue(ctx context.Context, client *apiclient.Client, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	projectID := fs.Int64("project", 0, "project ID (required)")
+	title := fs.String("title", "", "issue title (required)")
+	body := fs.String("body", "", "issue body")
+	bodyFile := fs.String("body-file", "", `file to read the body from, or "-" for standard input`)
+	status := fs.String("status", "", "initial status")
+	asJSON := fs.Bool("json", false, "print the issue as JSON")
+	if err := parse(fs, args, "-project and -title are required", func() bool { return *projectID != 0 && *title != "" }); err != nil {
+		return err
+	}
+
+	req := apiclient.CreateIssueRequest{Title: *title, Status: *status}
+	if *body != "" {
+		req.Body = body
+	}
+	if *bodyFile != "" {
+		text, err := readFile(*bodyFile)
+		if err != nil {
+			return err
+		}
+		req.Body = &text
+	}
+
+	issue, err := client.CreateIssue(ctx, *projectID, req)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(issue)
+	}
+	fmt.Printf("Created issue #%d: %s\n", issue.ID, issue.Title)
+	return nil
+}
+
+func runAI(ctx context.Context, client *apiclient.Client, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	projectID := fs.Int64("project", 0, "project ID (required)")
+	issueID := fs.Int64("issue", 0, "issue ID (required)")
+	instructions := fs.String("instructions", "", "extra instructions for this run")
+	resume := fs.Bool("resume", false, "continue the issue's previous AI session")
+	timeout := fs.Duration("timeout", 0, "time limit of the run")
+	watch := fs.Bool("watch", false, "follow the job's output until it finishes")
+	if err := parse(fs, args, "-project and -issue are required", func() bool { return *projectID != 0 && *issueID != 0 }); err != nil {
+		return err
+	}
+
+	req := apiclient.RunAIRequest{Instructions: *instructions, Resume: *resume}
+	if *timeout > 0 {
+		secs := int((*timeout + time.Second - 1) / time.Second)
+		req.TimeoutSeconds = &secs
+	}
+	job, err := client.RunAI(ctx, *projectID, *issueID, req)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Queued AI job %d on issue #%d\n", job.ID, *issueID)
+	if !*watch {
+		return nil
+	}
+	return watchJob(ctx, client, *projectID, *issueID, job.ID)
+}
+
+func followLogs(ctx context.Context, client *apiclient.Client, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	projectID := fs.Int64("project", 0, "project ID (required)")
+	issueID := fs.Int64("issue", 0, "issue ID (required)")
+	jobID := fs.Int64("job", 0, "job ID; defaults to the issue's latest job")
+	if err := parse(fs, args, "-project and -issue are required", func() bool { return *projectID != 0 && *issueID != 0 }); err != nil {
+		return err
+	}
+	return watchJob(ctx, client, *projectID, *issueID, *jobID)
+}
+
+// watchJob copies a job's output to stdout and stderr until it finishes.
+// It returns errJobFailed unless the job completed.
+func watchJob(ctx context.Context, client *apiclient.Client, projectID, issueID, jobID int64) error {
+	job, err := client.FollowLogs(ctx, projectID, issueID, jobID, func(l apiclient.AIJobLog) error {
+		out := os.Stdout
+		if l.Stream == "stderr" {
+			out = os.Stderr
+		}
+		_, err := io.WriteString(out, l.Data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "AI job %d %s\n", job.ID, job.Status)
+	if job.Status != "completed" {
+		if job.ErrorMsg != nil {
+			fmt.Fprintln(os.Stderr, *job.ErrorMsg)
+		}
+		return errJobFailed
+	}
+	return nil
+}
+
+// parse parses a command's flags and fails with missing unless the
+// required flags were given, as reported by given.
+func parse(fs *flag.FlagSet, args []string, missing string, given func() bool) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !given() {
+		fs.Usage()
+		return errors.New(missing)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func readFile(name string) (string, error) {
+	var b []byte
+	var err error
+	if name == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read body: %w", err)
+	}
+	return string(b), nil
+}
+
+func printJSON(v any) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
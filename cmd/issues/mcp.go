@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/sumire/issues/internal/mcp"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+const mcpInstructions = `Tools for the issue tracker. Issues belong to projects: find the project ID with list_projects first. ` +
+	`Issue statuses move open -> in_progress -> completed, and issues can be closed or reopened; set_issue_status rejects other moves.`
+
+// serveMCP runs a Model Context Protocol server on stdin and stdout, so
+// agents such as Claude Code can work with issues as the token's user:
+//
+//	claude mcp add issues --env ISSUES_URL=... --env ISSUES_TOKEN=... -- issues mcp
+//
+// With a read-scoped token, -read-only leaves out the tools that would be
+// refused.
+func serveMCP(ctx context.Context, client *apiclient.Client, args []string) error {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	readOnly := fs.Bool("read-only", false, "only offer tools that read")
+	if err := parse(fs, args, "", func() bool { return true }); err != nil {
+		return err
+	}
+
+	server := mcp.NewServer("issues", apiclient.Version, mcpInstructions)
+	for _, t := range issueTools(client) {
+		if t.ReadOnly || !*readOnly {
+			server.AddTool(t)
+		}
+	}
+	err := server.Serve(ctx, os.Stdin, os.Stdout)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// page is a page of a listing, with the cursor of the next one if there is
+// one.
+type page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func newPage[T any](items []T, meta *apiclient.Meta) page[T] {
+	p := page[T]{Items: items}
+	if meta != nil && meta.HasNext {
+		p.NextCursor = meta.NextCursor
+	}
+	return p
+}
+
+// Argument schemas shared between tools.
+var (
+	projectIDArg = &mcp.Schema{Type: "integer", Description: "project ID"}
+	issueIDArg   = &mcp.Schema{Type: "integer", Description: "issue ID"}
+	cursorArg    = &mcp.Schema{Type: "string", Description: "next_cursor of the previous page"}
+	statusArg    = &mcp.Schema{Type: "string", Enum: []string{"open", "in_progress", "completed", "closed"}}
+)
+
+type issueRef struct {
+	ProjectID int64 `json:"project_id"`
+	IssueID   int64 `json:"issue_id"`
+}
+
+func (r issueRef) check() error {
+	if r.ProjectID == 0 || r.IssueID == 0 {
+		return errors.New("project_id and issue_id are required")
+	}
+	return nil
+}
+
+// issueTools are the tools the MCP server offers.
+func issueTools(client *apiclient.Client) []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "list_projects",
+			Description: "List the projects the user can access.",
+			Input: &mcp.Schema{Type: "object", Properties: map[string]*mcp.Schema{
+				"query":  {Type: "string", Description: "filter by name"},
+				"cursor": cursorArg,
+			}},
+			ReadOnly: true,
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				Query  string `json:"query"`
+				Cursor string `json:"cursor"`
+			}) (any, error) {
+				projects, meta, err := client.ListProjects(ctx, apiclient.ProjectListOptions{Query: args.Query, Cursor: args.Cursor})
+				if err != nil {
+					return nil, err
+				}
+				return newPage(projects, meta), nil
+			}),
+		},
+		{
+			Name:        "list_issues",
+			Description: "List issues in a project, pinned issues first, optionally filtered by status, labels or assignee.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id"}, Properties: map[string]*mcp.Schema{
+				"project_id":  projectIDArg,
+				"status":      {Type: "array", Items: statusArg, Description: "statuses to include"},
+				"labels":      {Type: "array", Items: &mcp.Schema{Type: "string"}, Description: "labels the issues must have"},
+				"label_mode":  {Type: "string", Enum: []string{"all", "any"}, Description: "whether issues need all the labels (default) or any of them"},
+				"assignee_id": {Type: "integer", Description: "only issues assigned to this user"},
+				"cursor":      cursorArg,
+			}},
+			ReadOnly: true,
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				ProjectID  int64    `json:"project_id"`
+				Status     []string `json:"status"`
+				Labels     []string `json:"labels"`
+				LabelMode  string   `json:"label_mode"`
+				AssigneeID int64    `json:"assignee_id"`
+				Cursor     string   `json:"cursor"`
+			}) (any, error) {
+				if args.ProjectID == 0 {
+					return nil, errors.New("project_id is required")
+				}
+				issues, meta, err := client.ListIssues(ctx, args.ProjectID, apiclient.IssueListOptions{
+					Statuses:   args.Status,
+					Labels:     args.Labels,
+					LabelMode:  args.LabelMode,
+					AssigneeID: args.AssigneeID,
+					Cursor:     args.Cursor,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return newPage(issues, meta), nil
+			}),
+		},
+		{
+			Name:        "search_issues",
+			Description: "Search a project's issues by text, best match first.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id", "query"}, Properties: map[string]*mcp.Schema{
+				"project_id": projectIDArg,
+				"query":      {Type: "string"},
+				"cursor":     cursorArg,
+			}},
+			ReadOnly: true,
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				ProjectID int64  `json:"project_id"`
+				Query     string `json:"query"`
+				Cursor    string `json:"cursor"`
+			}) (any, error) {
+				if args.ProjectID == 0 || strings.TrimSpace(args.Query) == "" {
+					return nil, errors.New("project_id and query are required")
+				}
+				issues, meta, err := client.SearchIssues(ctx, args.ProjectID, args.Query, args.Cursor, 0)
+				if err != nil {
+					return nil, err
+				}
+				return newPage(issues, meta), nil
+			}),
+		},
+		{
+			Name:        "get_issue",
+			Description: "Get an issue with its comments.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id", "issue_id"}, Properties: map[string]*mcp.Schema{
+				"project_id": projectIDArg,
+				"issue_id":   issueIDArg,
+			}},
+			ReadOnly: true,
+			Call: mcp.Typed(func(ctx context.Context, args issueRef) (any, error) {
+				if err := args.check(); err != nil {
+					return nil, err
+				}
+				issue, err := client.GetIssue(ctx, args.ProjectID, args.IssueID)
+				if err != nil {
+					return nil, err
+				}
+				comments := []apiclient.Comment{}
+				for comment, err := range client.Comments(ctx, args.ProjectID, args.IssueID) {
+					if err != nil {
+						return nil, err
+					}
+					comments = append(comments, comment)
+				}
+				return struct {
+					*apiclient.Issue
+					Comments []apiclient.Comment `json:"comments"`
+				}{issue, comments}, nil
+			}),
+		},
+		{
+			Name:        "create_issue",
+			Description: "Create an issue in a project.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id", "title"}, Properties: map[string]*mcp.Schema{
+				"project_id": projectIDArg,
+				"title":      {Type: "string"},
+				"body":       {Type: "string", Description: "Markdown description"},
+			}},
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				ProjectID int64   `json:"project_id"`
+				Title     string  `json:"title"`
+				Body      *string `json:"body"`
+			}) (any, error) {
+				if args.ProjectID == 0 || args.Title == "" {
+					return nil, errors.New("project_id and title are required")
+				}
+				return client.CreateIssue(ctx, args.ProjectID, apiclient.CreateIssueRequest{Title: args.Title, Body: args.Body})
+			}),
+		},
+		{
+			Name:        "update_issue",
+			Description: "Change an issue's title, body or assignee. Omitted fields are left unchanged.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id", "issue_id"}, Properties: map[string]*mcp.Schema{
+				"project_id":  projectIDArg,
+				"issue_id":    issueIDArg,
+				"title":       {Type: "string"},
+				"body":        {Type: "string", Description: "Markdown description"},
+				"assignee_id": {Type: "integer"},
+			}},
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				issueRef
+				Title      *string `json:"title"`
+				Body       *string `json:"body"`
+				AssigneeID *int64  `json:"assignee_id"`
+			}) (any, error) {
+				if err := args.check(); err != nil {
+					return nil, err
+				}
+				return client.UpdateIssue(ctx, args.ProjectID, args.IssueID, apiclient.UpdateIssueRequest{
+					Title:      args.Title,
+					Body:       args.Body,
+					AssigneeID: args.AssigneeID,
+				})
+			}),
+		},
+		{
+			Name:        "set_issue_status",
+			Description: "Move an issue to another status. Allowed moves: open to in_progress or closed; in_progress to open, completed or closed; completed or closed back to open.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id", "issue_id", "status"}, Properties: map[string]*mcp.Schema{
+				"project_id": projectIDArg,
+				"issue_id":   issueIDArg,
+				"status":     statusArg,
+			}},
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				issueRef
+				Status string `json:"status"`
+			}) (any, error) {
+				if err := args.check(); err != nil {
+					return nil, err
+				}
+				return client.SetIssueStatus(ctx, args.ProjectID, args.IssueID, args.Status)
+			}),
+		},
+		{
+			Name:        "comment_on_issue",
+			Description: "Add a comment to an issue.",
+			Input: &mcp.Schema{Type: "object", Required: []string{"project_id", "issue_id", "body"}, Properties: map[string]*mcp.Schema{
+				"project_id": projectIDArg,
+				"issue_id":   issueIDArg,
+				"body":       {Type: "string", Description: "Markdown comment"},
+			}},
+			Call: mcp.Typed(func(ctx context.Context, args struct {
+				issueRef
+				Body string `json:"body"`
+			}) (any, error) {
+				if err := args.check(); err != nil {
+					return nil, err
+				}
+				if strings.TrimSpace(args.Body) == "" {
+					return nil, errors.New("body is required")
+				}
+				return client.CreateComment(ctx, args.ProjectID, args.IssueID, apiclient.CreateCommentRequest{Body: args.Body})
+			}),
+		},
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -9,16 +11,39 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Quiet hours are evaluated in users' time zones, which must resolve even
+	// where the host has no zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/jmoiron/sqlx"
 
+	"github.com/sumire/issues/internal/aiworker"
+	"github.com/sumire/issues/internal/antivirus"
+	"github.com/sumire/issues/internal/chat"
 	"github.com/sumire/issues/internal/config"
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/embedding"
 	"github.com/sumire/issues/internal/handler"
+	"github.com/sumire/issues/internal/imaging"
+	"github.com/sumire/issues/internal/listener"
+	"github.com/sumire/issues/internal/locking"
+	"github.com/sumire/issues/internal/mailer"
+	"github.com/sumire/issues/internal/metrics"
+	"github.com/sumire/issues/internal/openapi"
+	"github.com/sumire/issues/internal/paging"
+	"github.com/sumire/issues/internal/push"
+	"github.com/sumire/issues/internal/ratelimit"
+	"github.com/sumire/issues/internal/realtime"
 	"github.com/sumire/issues/internal/repository"
+	"github.com/sumire/issues/internal/scheduler"
+	"github.com/sumire/issues/internal/search"
 	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/internal/signedurl"
+	"github.com/sumire/issues/internal/slack"
+	"github.com/sumire/issues/internal/storage"
+	"github.com/sumire/issues/internal/tracing"
+	"github.com/sumire/issues/internal/webhook"
 )
 
 func main() {
@@ -34,21 +59,114 @@ func run() error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	db, err := sqlx.Connect("pgx", cfg.DatabaseURL)
+	if cfg.OTLPEndpoint != "" {
+		shutdownTracing, err := tracing.Setup(context.Background(), cfg.OTLPEndpoint, cfg.OTELServiceName)
+		if err != nil {
+			return fmt.Errorf("set up tracing: %w", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				slog.Error("failed to flush traces", "error", err)
+			}
+		}()
+		slog.Info("tracing enabled", "endpoint", cfg.OTLPEndpoint)
+	}
+
+	pool, err := repository.Open(context.Background(), cfg.DatabaseURL)
 	if err != nil {
 		return fmt.Errorf("connect database: %w", err)
 	}
-	defer db.Close()
+	defer pool.Close()
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	poolStats := func() metrics.PoolStats { return metrics.NewPoolStats(pool.Stat()) }
+	metrics.PublishPoolStats(poolStats)
 
 	slog.Info("database connected")
 
-	userRepo := repository.NewUserRepository(db)
+	limiter, err := ratelimit.NewStore(context.Background(), cfg.RateLimitStore, cfg.RedisURL)
+	if err != nil {
+		return fmt.Errorf("create rate limit store: %w", err)
+	}
+	defer limiter.Close()
+
+	userRepo := repository.NewUserRepository(pool)
+	projectRepo := repository.NewProjectRepository(pool)
+	issueRepo := repository.NewIssueRepository(pool)
+	quickAccessRepo := repository.NewQuickAccessRepository(pool)
+	auditRepo := repository.NewAuditRepository(pool)
+	commentRepo := repository.NewCommentRepository(pool)
+	moderationRepo := repository.NewModerationRepository(pool)
+	labelRepo := repository.NewLabelRepository(pool)
+	milestoneRepo := repository.NewMilestoneRepository(pool)
+	releaseRepo := repository.NewReleaseRepository(pool)
+	wikiRepo := repository.NewWikiRepository(pool)
+	issueLinkRepo := repository.NewIssueLinkRepository(pool)
+	savedFilterRepo := repository.NewSavedFilterRepository(pool)
+	templateRepo := repository.NewTemplateRepository(pool)
+	statsRepo := repository.NewStatsRepository(pool)
+	eventRepo := repository.NewEventRepository(pool)
+	notificationRepo := repository.NewNotificationRepository(pool)
+	cursorRepo := repository.NewCursorRepository(pool)
+	partitionRepo := repository.NewPartitionRepository(pool)
+	orgRepo := repository.NewOrganizationRepository(pool)
+	aiJobRepo := repository.NewAIJobRepository(pool)
+	embeddingRepo := repository.NewEmbeddingRepository(pool)
+	duplicationRepo := repository.NewDuplicationRepository(pool)
+	labelSyncRepo := repository.NewLabelSyncRepository(pool)
+	flagRepo := repository.NewFlagRepository(pool)
+	searchRepo := repository.NewSearchRepository(pool)
+	referenceRepo := repository.NewReferenceRepository(pool)
+	webhookRepo := repository.NewWebhookRepository(pool)
+	slackRepo := repository.NewSlackRepository(pool)
+	deviceRepo := repository.NewDeviceRepository(pool)
+	accessTokenRepo := repository.NewAccessTokenRepository(pool)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(pool)
+	attachmentRepo := repository.NewAttachmentRepository(pool)
+	freezeRepo := repository.NewFreezeRepository(pool)
+	closeRequestRepo := repository.NewCloseRequestRepository(pool)
+	routeRepo := repository.NewRouteRepository(pool)
+
+	var objects storage.Storage
+	if cfg.StorageBackend == "s3" {
+		objects, err = storage.NewS3(storage.S3Config{
+			Bucket:          cfg.S3Bucket,
+			Region:          cfg.S3Region,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			Endpoint:        cfg.S3Endpoint,
+			PathStyle:       cfg.S3PathStyle,
+		})
+	} else {
+		objects, err = storage.NewLocal(cfg.StorageDir)
+	}
+	if err != nil {
+		return fmt.Errorf("open storage: %w", err)
+	}
+	// Download links are signed with their own key, so a leaked link key
+	// cannot forge sessions. Without one, a key is derived from the JWT key.
+	signedURLKey := []byte(cfg.SignedURLSecret)
+	if len(signedURLKey) == 0 {
+		if signedURLKey, err = signedurl.DeriveKey([]byte(cfg.JWTSecret)); err != nil {
+			return err
+		}
+	}
+	signedURLs := signedurl.New(signedURLKey, "/api/v1", cfg.SignedURLTTL)
 
-	authSvc := service.NewAuthService(userRepo, service.AuthConfig{
+	var indexer service.Indexer = searchRepo
+	if cfg.SearchBackend == "meilisearch" {
+		meili := search.NewMeilisearch(cfg.SearchURL, cfg.SearchAPIKey, cfg.SearchIndex)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := meili.Setup(ctx)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("set up search index: %w", err)
+		}
+		indexer = meili
+	}
+
+	authSvc := service.NewAuthService(userRepo, refreshTokenRepo, service.AuthConfig{
 		GoogleClientID:     cfg.GoogleClientID,
 		GoogleClientSecret: cfg.GoogleClientSecret,
 		GitHubClientID:     cfg.GitHubClientID,
@@ -57,21 +175,260 @@ func run() error {
 		FrontendURL:        cfg.FrontendURL,
 	})
 
+	// Issue events recorded by request handlers and AI workers are also
+	// pushed to the WebSocket streams of their project.
+	hub := realtime.New(pool)
+	liveEvents := service.PublishEvents(eventRepo, hub)
+
+	projectSvc := service.NewProjectService(projectRepo, orgRepo, auditRepo)
+	labelSvc := service.NewLabelService(projectRepo, issueRepo, labelRepo, liveEvents, auditRepo)
+	milestoneSvc := service.NewMilestoneService(projectRepo, issueRepo, milestoneRepo)
+	wikiSvc := service.NewWikiService(projectRepo, wikiRepo, referenceRepo)
+	issueLinkSvc := service.NewIssueLinkService(projectRepo, issueRepo, issueLinkRepo)
+	memberSvc := service.NewMemberService(projectRepo, projectRepo, userRepo, auditRepo)
+	duplicationSvc := service.NewDuplicationService(projectRepo, orgRepo, duplicationRepo, 100, 2*time.Second)
+	issueSvc := service.NewIssueService(projectRepo, issueRepo, auditRepo, liveEvents, referenceRepo,
+		service.WithPinLimit(cfg.PinnedIssueLimit),
+		service.WithFreezeWindows(freezeRepo),
+	)
+	savedFilterSvc := service.NewSavedFilterService(projectRepo, savedFilterRepo, issueSvc)
+	closeRequestSvc := service.NewCloseRequestService(projectRepo, projectRepo, issueRepo, issueSvc, closeRequestRepo, notificationRepo)
+	quickAccessSvc := service.NewQuickAccessService(projectRepo, issueRepo, quickAccessRepo)
+	commentSvc := service.NewCommentService(projectRepo, issueRepo, commentRepo, liveEvents, referenceRepo,
+		service.WithRestoreWindow(cfg.CommentRestoreWindow),
+	)
+	attachmentOpts := []service.AttachmentOption{service.WithAttachmentLimit(int64(cfg.AttachmentMaxBytes))}
+	if cfg.ImageStripMetadata || len(cfg.ImageVariants) > 0 {
+		attachmentOpts = append(attachmentOpts,
+			service.WithImageProcessor(imaging.New(cfg.ImageStripMetadata, cfg.ImageVariants)))
+	}
+	if cfg.ClamAVAddr != "" {
+		attachmentOpts = append(attachmentOpts,
+			service.WithAttachmentScanner(antivirus.NewClamAV(cfg.ClamAVAddr, cfg.ClamAVTimeout)))
+	}
+	attachmentSvc := service.NewAttachmentService(projectRepo, issueRepo, attachmentRepo, objects, signedURLs, attachmentOpts...)
+	trashSvc := service.NewTrashService(projectRepo, projectRepo, issueRepo, auditRepo, cfg.TrashRetention)
+	moderationSvc := service.NewModerationService(projectRepo, issueRepo, commentRepo, moderationRepo, auditRepo)
+	templateSvc := service.NewTemplateService(projectRepo, labelRepo, templateRepo, orgRepo)
+	statsSvc := service.NewStatsService(projectRepo, milestoneRepo, statsRepo)
+	activitySvc := service.NewActivityService(eventRepo)
+	timelineSvc := service.NewTimelineService(projectRepo, issueRepo, eventRepo, commentRepo)
+	auditSvc := service.NewAuditService(projectRepo, auditRepo)
+	freezeSvc := service.NewFreezeService(projectRepo, freezeRepo, auditRepo)
+	routeSvc := service.NewNotificationRouteService(projectRepo, routeRepo, auditRepo)
+	orgSvc := service.NewOrganizationService(orgRepo)
+	labelSyncSvc := service.NewLabelSyncService(orgRepo, labelSyncRepo, 20, 2*time.Second)
+	aiJobSvc := service.NewAIJobService(projectRepo, issueRepo, aiJobRepo, liveEvents, objects, cfg.ClaudeCodeTimeout,
+		service.WithAIFreezeWindows(freezeRepo),
+		service.WithArtifactURLs(signedURLs),
+	)
+	secretPatterns, err := aiworker.LoadPatterns(cfg.AISecretsFile)
+	if err != nil {
+		return err
+	}
+	guard, err := aiworker.NewGuard(secretPatterns)
+	if err != nil {
+		return err
+	}
+	aiBot := service.NewAIBot(userRepo, commentRepo, liveEvents)
+	aiRunner := aiworker.NewRunner(cfg.ClaudeCodeBinary, cfg.ClaudeCodeTimeout, aiJobRepo, issueRepo, projectRepo, liveEvents,
+		aiworker.NewCollector(objects, aiJobRepo), aiBot, guard,
+		aiworker.WithWorkDir(cfg.AIWorkspaceDir), aiworker.WithPublisher(hub))
+	aiPool := aiworker.New(aiRunner, cfg.AIWorkerCount, 2*time.Second)
+	metrics.PublishAIWorkers(aiPool.Stats)
+	completer := aiworker.NewCompleter(cfg.ClaudeCodeBinary, cfg.AICompletionTimeout, cfg.AIWorkspaceDir, guard)
+	releaseSvc := service.NewReleaseService(projectRepo, milestoneRepo, releaseRepo, completer)
+
+	adminSvc := service.NewAdminService(userRepo, aiJobRepo, flagRepo, service.WithWorkerPool(aiPool))
+	dispatcher := webhook.New(cfg.WebhookURL, []byte(cfg.WebhookSecret), eventRepo, webhookRepo, webhookRepo, cursorRepo,
+		webhook.WithMaxAttempts(cfg.WebhookMaxAttempts), webhook.WithLinkBase(cfg.FrontendURL),
+		webhook.WithAlerter(service.NewWebhookAlerter(projectRepo, notificationRepo)))
+	webhookSvc := service.NewWebhookService(userRepo, projectRepo, webhookRepo, webhookRepo, auditRepo, dispatcher)
+	notificationSvc := service.NewNotificationService(notificationRepo, hub)
+	liveSvc := service.NewLiveService(projectRepo, hub)
+	snoozeScheduler := service.NewSnoozeScheduler(notificationSvc, 30*time.Second)
+	deviceSvc := service.NewDeviceService(deviceRepo)
+	accessTokenSvc := service.NewAccessTokenService(accessTokenRepo)
+	senders, err := pushSenders(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	pushDispatcher := service.NewPushDispatcher(notificationRepo, deviceRepo, notificationSvc, cursorRepo, senders, 2*time.Second)
+	var emailSender mailer.Sender
+	var emailDispatcher *service.EmailDispatcher
+	if cfg.SMTPHost != "" {
+		smtp, err := mailer.New(mailer.Config{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			return fmt.Errorf("configure smtp: %w", err)
+		}
+		emailSender = smtp
+		var emailOpts []service.EmailOption
+		if cfg.EmailReplyDomain != "" {
+			emailOpts = append(emailOpts, service.WithReplyTo([]byte(cfg.JWTSecret), cfg.EmailReplyDomain))
+		}
+		emailDispatcher = service.NewEmailDispatcher(notificationRepo, notificationSvc, userRepo, issueRepo, cursorRepo, smtp,
+			cfg.FrontendURL, 2*time.Second, emailOpts...)
+	}
+	var chatNotifiers []chat.Notifier
+	if cfg.MatrixHomeserverURL != "" {
+		chatNotifiers = append(chatNotifiers, chat.NewMatrix(cfg.MatrixHomeserverURL, cfg.MatrixAccessToken, cfg.MatrixRoomID))
+	}
+	chatRelay := service.NewChatRelay(eventRepo, cursorRepo, chatNotifiers, cfg.FrontendURL, 5*time.Second)
+	var pagers []paging.Pager
+	if cfg.PagerDutyRoutingKey != "" {
+		pagers = append(pagers, paging.NewPagerDuty(cfg.PagerDutyRoutingKey, "issues"))
+	}
+	if cfg.OpsgenieAPIKey != "" {
+		pagers = append(pagers, paging.NewOpsgenie(cfg.OpsgenieAPIKey, "issues"))
+	}
+	escalator := service.NewEscalator(eventRepo, projectRepo, aiJobRepo, cursorRepo, pagers, cfg.FrontendURL,
+		30*time.Second, cfg.AIQueueAlertDepth, cfg.AIQueueAlertAfter)
+	searchSvc := service.NewSearchService(projectRepo, searchRepo, indexer, 2*time.Second)
+	var embeddingSvc *service.EmbeddingService
+	if cfg.EmbeddingURL != "" {
+		embedder := embedding.NewOpenAI(cfg.EmbeddingURL, cfg.EmbeddingAPIKey, cfg.EmbeddingModel)
+		embeddingSvc = service.NewEmbeddingService(userRepo, embeddingRepo, embedder, cfg.EmbeddingBatchSize, cfg.EmbeddingInterval)
+	}
+	metrics.PublishAIQueueDepth(func() (int, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return adminSvc.QueueDepth(ctx)
+	})
+	importSvc := service.NewImportService(projectRepo, issueRepo, notificationRepo)
+	var slackPoster service.SlackPoster
+	if cfg.SlackBotToken != "" {
+		slackPoster = slack.NewClient(cfg.SlackBotToken)
+	}
+	router := service.NewNotificationRouter(emailSender, slackPoster, cfg.FrontendURL)
+	notifier := service.NewNotifier(eventRepo, projectRepo, issueRepo, notificationRepo, cursorRepo, cfg.NotifierInterval,
+		service.WithRouting(routeRepo, labelRepo, router),
+	)
+	archiver := service.NewArchiver(issueRepo, cfg.ArchiveInterval)
+	maintenance := scheduler.New()
+	for _, task := range maintenanceTasks(cfg, authSvc, accessTokenSvc, aiJobSvc, trashSvc, attachmentSvc, dispatcher) {
+		maintenance.Add(task)
+	}
+	partitionMaintainer := service.NewPartitionMaintainer(partitionRepo, map[string]time.Duration{
+		"audit_logs":         cfg.AuditLogRetention,
+		"issue_events":       cfg.EventRetention,
+		"webhook_deliveries": cfg.DeliveryRetention,
+	}, 6*time.Hour)
+
+	// Background workers run until shutdown. Singletons coordinate through
+	// advisory locks so each runs on one replica at a time.
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	locker := locking.New(pool)
+	go locker.Singleton(bgCtx, "notification-fanout", 30*time.Second, notifier.Run)
+	go locker.Singleton(bgCtx, "issue-archiver", 30*time.Second, archiver.Run)
+	go locker.Singleton(bgCtx, "maintenance-scheduler", 30*time.Second, maintenance.Run)
+	go locker.Singleton(bgCtx, "partition-maintenance", 30*time.Second, partitionMaintainer.Run)
+	if embeddingSvc != nil {
+		go locker.Singleton(bgCtx, "embedding-backfill", 30*time.Second, embeddingSvc.Run)
+	}
+	go locker.Singleton(bgCtx, "search-indexer", 30*time.Second, searchSvc.Run)
+	go locker.Singleton(bgCtx, "project-duplication", 30*time.Second, duplicationSvc.Run)
+	go locker.Singleton(bgCtx, "label-sync", 30*time.Second, labelSyncSvc.Run)
+	go locker.Singleton(bgCtx, "notification-snoozes", 30*time.Second, snoozeScheduler.Run)
+	if len(senders) > 0 {
+		go locker.Singleton(bgCtx, "push-dispatch", 30*time.Second, pushDispatcher.Run)
+	}
+	if emailDispatcher != nil {
+		go locker.Singleton(bgCtx, "email-dispatch", 30*time.Second, emailDispatcher.Run)
+	}
+	go locker.Singleton(bgCtx, "webhook-dispatch", 30*time.Second, dispatcher.Run)
+	if len(chatNotifiers) > 0 {
+		go locker.Singleton(bgCtx, "chat-relay", 30*time.Second, chatRelay.Run)
+	}
+	if len(pagers) > 0 {
+		go locker.Singleton(bgCtx, "escalation", 30*time.Second, escalator.Run)
+	}
+	if cfg.ClamAVAddr != "" {
+		rescanner := service.NewAttachmentRescanner(attachmentSvc, cfg.ScanRetryInterval)
+		go locker.Singleton(bgCtx, "attachment-rescan", 30*time.Second, rescanner.Run)
+	}
+	// Every replica listens for realtime events to serve its own streams.
+	go hub.Run(bgCtx)
+	// AI workers run on every replica; jobs are claimed with SKIP LOCKED.
+	aiDone := make(chan struct{})
+	go func() {
+		defer close(aiDone)
+		aiPool.Run(bgCtx)
+	}()
+
 	authHandler := handler.NewAuthHandler(authSvc)
+	projectHandler := handler.NewProjectHandler(projectSvc)
+	duplicationHandler := handler.NewDuplicationHandler(duplicationSvc)
+	memberHandler := handler.NewMemberHandler(memberSvc)
+	labelHandler := handler.NewLabelHandler(labelSvc)
+	milestoneHandler := handler.NewMilestoneHandler(milestoneSvc)
+	wikiHandler := handler.NewWikiHandler(wikiSvc)
+	issueLinkHandler := handler.NewIssueLinkHandler(issueLinkSvc)
+	releaseHandler := handler.NewReleaseHandler(releaseSvc)
+	savedFilterHandler := handler.NewSavedFilterHandler(savedFilterSvc)
+	issueHandler := handler.NewIssueHandler(issueSvc)
+	quickAccessHandler := handler.NewQuickAccessHandler(quickAccessSvc)
+	commentHandler := handler.NewCommentHandler(commentSvc)
+	closeRequestHandler := handler.NewCloseRequestHandler(closeRequestSvc)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentSvc)
+	trashHandler := handler.NewTrashHandler(trashSvc)
+	moderationHandler := handler.NewModerationHandler(moderationSvc)
+	templateHandler := handler.NewTemplateHandler(templateSvc)
+	statsHandler := handler.NewStatsHandler(statsSvc)
+	activityHandler := handler.NewActivityHandler(activitySvc)
+	timelineHandler := handler.NewTimelineHandler(timelineSvc)
+	auditHandler := handler.NewAuditHandler(auditSvc)
+	freezeHandler := handler.NewFreezeHandler(freezeSvc)
+	routeHandler := handler.NewRouteHandler(routeSvc)
+	importHandler := handler.NewImportHandler(importSvc)
+	orgHandler := handler.NewOrganizationHandler(orgSvc)
+	labelSyncHandler := handler.NewLabelSyncHandler(labelSyncSvc)
+	adminHandler := handler.NewAdminHandler(adminSvc)
+	webhookHandler := handler.NewWebhookHandler(webhookSvc)
+	searchHandler := handler.NewSearchHandler(searchSvc)
+	notificationHandler := handler.NewNotificationHandler(notificationSvc)
+	liveHandler := handler.NewLiveHandler(liveSvc)
+	deviceHandler := handler.NewDeviceHandler(deviceSvc)
+	accessTokenHandler := handler.NewAccessTokenHandler(accessTokenSvc)
+	aiJobHandler := handler.NewAIJobHandler(aiJobSvc)
+	slackHandler := handler.NewSlackHandler(service.NewSlackService(slackRepo, issueSvc, aiJobSvc,
+		[]byte(cfg.SlackSigningSecret), cfg.FrontendURL))
+	diagnosticsHandler := handler.NewDiagnosticsHandler(poolStats)
+	emailReplyHandler := handler.NewEmailReplyHandler(service.NewEmailReplyService(userRepo, issueRepo, commentSvc,
+		[]byte(cfg.JWTSecret)))
+
+	profile, err := handler.ParseSerializationProfile(cfg.JSONKeyCasing, cfg.JSONTimeFormat)
+	if err != nil {
+		return fmt.Errorf("parse serialization profile: %w", err)
+	}
 
 	e := echo.New()
 	e.HideBanner = true
+	e.JSONSerializer = handler.NewProfileSerializer(profile)
 	e.Validator = handler.NewAppValidator()
 	e.HTTPErrorHandler = handler.HTTPErrorHandler
+	e.IPExtractor = handler.ClientIPExtractor(cfg.TrustedProxies)
 
+	e.Pre(handler.MethodSupport())
 	e.Use(middleware.RequestID())
+	e.Use(handler.Tracing(cfg.OTELServiceName))
 	e.Use(handler.RequestLogger())
-	e.Use(middleware.Recover())
+	e.Use(handler.Recover())
+	streams := handler.NewStreams()
+	e.Server.RegisterOnShutdown(streams.Close)
+	e.Use(handler.Deadline(cfg.RequestReadTimeout, cfg.RequestWriteTimeout, streams))
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     []string{cfg.FrontendURL},
-		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete, http.MethodOptions},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
-		ExposeHeaders:    []string{echo.HeaderXRequestID},
+		AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "If-Unmodified-Since", "traceparent"},
+		ExposeHeaders:    []string{echo.HeaderXRequestID, "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -80,46 +437,392 @@ func run() error {
 		return handler.JSON(c, http.StatusOK, map[string]string{"status": "ok"})
 	})
 
+	// Operational endpoints, on their own listener so they are never
+	// exposed with the public API.
+	internal := echo.New()
+	internal.HideBanner = true
+	internal.HidePort = true
+	internal.HTTPErrorHandler = handler.HTTPErrorHandler
+	internal.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	internal.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+	internal.GET("/debug/db", diagnosticsHandler.DBPool)
+
 	v1 := e.Group("/api/v1")
 
 	// Auth routes (public)
 	auth := v1.Group("/auth")
+	if cfg.AuthRateLimit > 0 {
+		auth.Use(handler.RateLimit(limiter, "auth", ratelimit.Limit{Rate: float64(cfg.AuthRateLimit) / 60, Burst: cfg.AuthRateBurst}))
+	}
 	auth.GET("/google", authHandler.GoogleRedirect)
 	auth.GET("/google/callback", authHandler.GoogleCallback)
 	auth.GET("/github", authHandler.GitHubRedirect)
 	auth.GET("/github/callback", authHandler.GitHubCallback)
 	auth.POST("/refresh", authHandler.Refresh)
 
+	// Slack app routes (signed by Slack)
+	if cfg.SlackSigningSecret != "" {
+		slackApp := v1.Group("/slack", handler.SlackSignature([]byte(cfg.SlackSigningSecret)))
+		slackApp.POST("/commands", slackHandler.Command)
+		slackApp.POST("/interactions", slackHandler.Interaction)
+	}
+
+	// Inbound email (posted by the mail relay)
+	if cfg.EmailReplyDomain != "" {
+		inbound := v1.Group("/email", handler.BearerSecret(cfg.EmailInboundSecret))
+		inbound.POST("/inbound", emailReplyHandler.Receive)
+	}
+
+	// Private object downloads (authorized by a signed URL)
+	v1.GET("/attachments/:aid", attachmentHandler.Download)
+	v1.GET("/artifacts/:aid", aiJobHandler.DownloadSignedArtifact)
+
 	// Protected routes
 	protected := v1.Group("")
-	protected.Use(handler.JWTAuth(authSvc))
+	protected.Use(handler.JWTAuth(authSvc, accessTokenSvc))
+	if cfg.APIRateLimit > 0 {
+		protected.Use(handler.RateLimit(limiter, "api", ratelimit.Limit{Rate: float64(cfg.APIRateLimit) / 60, Burst: cfg.APIRateBurst}))
+	}
 
 	protected.GET("/auth/me", authHandler.Me)
 
-	// TODO: project routes
-	// TODO: issue routes
-	// TODO: notification routes
+	// Quick-access routes
+	protected.GET("/me/recent", quickAccessHandler.Recent)
+	protected.POST("/me/recent", quickAccessHandler.RecordView)
+	protected.GET("/me/starred", quickAccessHandler.Starred)
+	protected.PUT("/me/starred/:type/:id", quickAccessHandler.Star)
+	protected.DELETE("/me/starred/:type/:id", quickAccessHandler.Unstar)
+	protected.GET("/me/activity", activityHandler.Feed)
+	protected.GET("/search", searchHandler.All)
+	protected.GET("/me/devices", deviceHandler.List)
+	protected.POST("/me/devices", deviceHandler.Register)
+	if cfg.SlackSigningSecret != "" {
+		protected.POST("/me/slack", slackHandler.Link)
+	}
+	protected.DELETE("/me/devices/:did", deviceHandler.Unregister)
+	protected.GET("/me/tokens", accessTokenHandler.List)
+	protected.POST("/me/tokens", accessTokenHandler.Create)
+	protected.DELETE("/me/tokens/:tid", accessTokenHandler.Delete)
+
+	// Organization routes
+	protected.POST("/orgs", orgHandler.Create)
+	protected.GET("/orgs", orgHandler.List)
+	protected.GET("/orgs/:oid", orgHandler.Get)
+	protected.PUT("/orgs/:oid/defaults", orgHandler.UpdateDefaults)
+	protected.POST("/orgs/:oid/label-syncs", labelSyncHandler.Start)
+	protected.GET("/orgs/:oid/label-syncs", labelSyncHandler.List)
+	protected.GET("/orgs/:oid/label-syncs/:sid", labelSyncHandler.Get)
+
+	// Project routes
+	protected.GET("/projects", projectHandler.List)
+	protected.POST("/projects", projectHandler.Create)
+	protected.GET("/projects/:pid", projectHandler.Get)
+	protected.PATCH("/projects/:pid", projectHandler.Update)
+	protected.DELETE("/projects/:pid", projectHandler.Delete)
+	protected.GET("/trash", trashHandler.Projects)
+	protected.POST("/projects/:pid/restore", trashHandler.RestoreProject)
+	protected.GET("/projects/:pid/trash", trashHandler.Issues)
+	protected.POST("/projects/:pid/duplicate", duplicationHandler.Duplicate)
+	protected.GET("/projects/:pid/duplication", duplicationHandler.Duplication)
+	protected.POST("/projects/:pid/members/bulk", memberHandler.Add)
+	protected.POST("/projects/:pid/members/bulk-remove", memberHandler.Remove)
+	protected.GET("/projects/:pid/labels", labelHandler.List)
+	protected.POST("/projects/:pid/labels", labelHandler.Create)
+	protected.PATCH("/projects/:pid/labels/:lid", labelHandler.Update)
+	protected.DELETE("/projects/:pid/labels/:lid", labelHandler.Delete)
+	protected.POST("/projects/:pid/labels/:lid/rename", labelHandler.Rename)
+	protected.POST("/projects/:pid/labels/:lid/merge", labelHandler.Merge)
+	protected.PUT("/projects/:pid/labels/:lid/restricted", labelHandler.Restrict)
+	protected.DELETE("/projects/:pid/labels/:lid/restricted", labelHandler.Unrestrict)
+	protected.GET("/projects/:pid/milestones", milestoneHandler.List)
+	protected.POST("/projects/:pid/milestones", milestoneHandler.Create)
+	protected.GET("/projects/:pid/milestones/:mid", milestoneHandler.Get)
+	protected.PATCH("/projects/:pid/milestones/:mid", milestoneHandler.Update)
+	protected.DELETE("/projects/:pid/milestones/:mid", milestoneHandler.Delete)
+	protected.GET("/projects/:pid/milestones/:mid/progress", milestoneHandler.Progress)
+	protected.GET("/projects/:pid/release-notes", releaseHandler.Notes, handler.Timeout(cfg.AICompletionTimeout+cfg.RequestReadTimeout))
+	protected.GET("/projects/:pid/graph", issueLinkHandler.Graph)
+	protected.GET("/projects/:pid/wiki", wikiHandler.List)
+	protected.POST("/projects/:pid/wiki", wikiHandler.Create)
+	protected.GET("/projects/:pid/wiki/:slug", wikiHandler.Get)
+	protected.PATCH("/projects/:pid/wiki/:slug", wikiHandler.Update)
+	protected.DELETE("/projects/:pid/wiki/:slug", wikiHandler.Delete)
+	protected.GET("/projects/:pid/wiki/:slug/revisions", wikiHandler.Revisions)
+	protected.GET("/projects/:pid/wiki/:slug/revisions/:rev", wikiHandler.Revision)
+	protected.POST("/projects/:pid/wiki/:slug/revisions/:rev/restore", wikiHandler.Restore)
+	protected.GET("/projects/:pid/filters", savedFilterHandler.List)
+	protected.POST("/projects/:pid/filters", savedFilterHandler.Create)
+	protected.GET("/projects/:pid/filters/:fid", savedFilterHandler.Get)
+	protected.PATCH("/projects/:pid/filters/:fid", savedFilterHandler.Update)
+	protected.DELETE("/projects/:pid/filters/:fid", savedFilterHandler.Delete)
+	protected.GET("/projects/:pid/filters/:fid/issues", savedFilterHandler.Issues)
+	protected.GET("/projects/:pid/webhooks", webhookHandler.List)
+	protected.POST("/projects/:pid/webhooks", webhookHandler.Create)
+	protected.PATCH("/projects/:pid/webhooks/:wid", webhookHandler.Update)
+	protected.DELETE("/projects/:pid/webhooks/:wid", webhookHandler.Delete)
+	protected.GET("/projects/:pid/webhooks/:wid/deliveries", webhookHandler.WebhookDeliveries)
+	protected.POST("/projects/:pid/webhooks/:wid/test", webhookHandler.Test)
+	protected.GET("/webhooks/event-types", webhookHandler.EventTypes)
+	protected.GET("/projects/:pid/notification-routes", routeHandler.List)
+	protected.POST("/projects/:pid/notification-routes", routeHandler.Create)
+	protected.PATCH("/projects/:pid/notification-routes/:rid", routeHandler.Update)
+	protected.DELETE("/projects/:pid/notification-routes/:rid", routeHandler.Delete)
+	protected.GET("/projects/:pid/freezes", freezeHandler.List)
+	protected.POST("/projects/:pid/freezes", freezeHandler.Create)
+	protected.DELETE("/projects/:pid/freezes/:fid", freezeHandler.Delete)
+	protected.PUT("/projects/:pid/ai/paused", projectHandler.PauseAI)
+	protected.DELETE("/projects/:pid/ai/paused", projectHandler.ResumeAI)
+	protected.POST("/projects/from-template", templateHandler.CreateProject)
+	protected.POST("/projects/:pid/save-as-template", templateHandler.SaveProject)
+	protected.GET("/projects/:pid/contributors", statsHandler.Contributors)
+	protected.GET("/projects/:pid/audit-log", auditHandler.List)
+
+	// Project template routes
+	protected.GET("/project-templates", templateHandler.List)
+
+	// Issue routes
+	protected.GET("/projects/:pid/issues", issueHandler.List)
+	protected.POST("/projects/:pid/issues", issueHandler.Create)
+	protected.GET("/projects/:pid/issues/search", searchHandler.Issues)
+	protected.GET("/projects/:pid/quick-search", searchHandler.QuickSearch)
+	protected.GET("/projects/:pid/issues/stats", statsHandler.IssueCounts)
+	protected.GET("/projects/:pid/issues/flow", statsHandler.Flow)
+	protected.GET("/projects/:pid/issues/cycle-times", statsHandler.CycleTimes)
+	protected.GET("/projects/:pid/issues/:id", issueHandler.Get)
+	protected.PATCH("/projects/:pid/issues/:id", issueHandler.Update)
+	protected.PATCH("/projects/:pid/issues/:id/status", issueHandler.SetStatus)
+	protected.DELETE("/projects/:pid/issues/:id", issueHandler.Delete)
+	protected.POST("/projects/:pid/issues/:id/restore", trashHandler.RestoreIssue)
+	protected.POST("/projects/:pid/issues/:id/clone", issueHandler.Clone)
+	protected.GET("/projects/:pid/issues/:id/close-requests", closeRequestHandler.List)
+	protected.POST("/projects/:pid/issues/:id/close-requests", closeRequestHandler.Create)
+	protected.POST("/projects/:pid/issues/:id/close-requests/approve", closeRequestHandler.Approve)
+	protected.POST("/projects/:pid/issues/:id/close-requests/reject", closeRequestHandler.Reject)
+	protected.POST("/projects/:pid/issues/import", importHandler.Issues,
+		middleware.BodyLimit("32M"), handler.Timeout(cfg.ImportTimeout))
+	protected.PUT("/projects/:pid/issues/:id/pin", issueHandler.Pin)
+	protected.DELETE("/projects/:pid/issues/:id/pin", issueHandler.Unpin)
+	protected.GET("/projects/:pid/issues/:id/labels", labelHandler.ListForIssue)
+	protected.GET("/projects/:pid/issues/:id/attachments", attachmentHandler.List)
+	protected.POST("/projects/:pid/issues/:id/attachments", attachmentHandler.Upload, handler.Timeout(cfg.UploadTimeout))
+	protected.GET("/projects/:pid/issues/:id/attachments/:aid/url", attachmentHandler.URL)
+	protected.DELETE("/projects/:pid/issues/:id/attachments/:aid", attachmentHandler.Delete)
+	protected.PUT("/projects/:pid/issues/:id/labels/:lid", labelHandler.Attach)
+	protected.DELETE("/projects/:pid/issues/:id/labels/:lid", labelHandler.Detach)
+	protected.PUT("/projects/:pid/issues/:id/milestone", milestoneHandler.Assign)
+	protected.DELETE("/projects/:pid/issues/:id/milestone", milestoneHandler.Unassign)
+	protected.POST("/projects/:pid/issues/:id/ai/run", aiJobHandler.Run)
+	protected.POST("/projects/:pid/issues/:id/ai/cancel", aiJobHandler.Cancel)
+	protected.POST("/projects/:pid/issues/:id/ai/retry", aiJobHandler.Retry)
+	protected.GET("/projects/:pid/issues/:id/ai/runs", aiJobHandler.Runs)
+	protected.GET("/projects/:pid/issues/:id/ai/logs/stream", aiJobHandler.LogStream)
+	protected.POST("/projects/:pid/issues/:id/ai/review", aiJobHandler.Review)
+	protected.GET("/projects/:pid/issues/:id/ai/review-comments", aiJobHandler.ReviewComments)
+	protected.POST("/projects/:pid/ai-jobs/:jid/approve", aiJobHandler.Approve)
+	protected.GET("/projects/:pid/ai-jobs/:jid/artifacts", aiJobHandler.Artifacts)
+	protected.GET("/projects/:pid/ai-jobs/:jid/artifacts/:aid", aiJobHandler.DownloadArtifact)
+	protected.GET("/projects/:pid/ai-jobs/:jid/artifacts/:aid/url", aiJobHandler.ArtifactURL)
+
+	// Comment routes
+	protected.GET("/projects/:pid/issues/:id/timeline", timelineHandler.List)
+	protected.GET("/projects/:pid/issues/:id/links", issueLinkHandler.List)
+	protected.POST("/projects/:pid/issues/:id/links", issueLinkHandler.Create)
+	protected.DELETE("/projects/:pid/issues/:id/links/:lid", issueLinkHandler.Delete)
+	protected.GET("/projects/:pid/issues/:id/comments", commentHandler.List)
+	protected.POST("/projects/:pid/issues/:id/comments", commentHandler.Create)
+	protected.DELETE("/projects/:pid/comments/:cid", commentHandler.Delete)
+	protected.POST("/projects/:pid/comments/:cid/restore", commentHandler.Restore)
+
+	// Moderation routes
+	protected.PUT("/projects/:pid/comments/:cid/hidden", moderationHandler.HideComment)
+	protected.DELETE("/projects/:pid/comments/:cid/hidden", moderationHandler.UnhideComment)
+	protected.GET("/projects/:pid/blocks", moderationHandler.ListBlocks)
+	protected.PUT("/projects/:pid/blocks/:uid", moderationHandler.Block)
+	protected.DELETE("/projects/:pid/blocks/:uid", moderationHandler.Unblock)
+	protected.GET("/projects/:pid/reports", moderationHandler.ListReports)
+	protected.POST("/projects/:pid/reports", moderationHandler.Report)
+	protected.POST("/projects/:pid/reports/:rid/resolve", moderationHandler.ResolveReport)
+
+	// Admin routes
+	protected.GET("/admin/ai-jobs", adminHandler.AIJobs)
+	protected.GET("/admin/ai-jobs/stats", adminHandler.AIJobStats)
+	protected.GET("/admin/ai-workers", adminHandler.Workers)
+	protected.PUT("/admin/ai-workers", adminHandler.ResizeWorkers)
+	protected.PUT("/admin/ai/paused", adminHandler.PauseAI)
+	protected.DELETE("/admin/ai/paused", adminHandler.ResumeAI)
+	protected.GET("/admin/webhook-deliveries", webhookHandler.Deliveries)
+	if embeddingSvc != nil {
+		embeddingHandler := handler.NewEmbeddingHandler(embeddingSvc)
+		protected.POST("/admin/embeddings/backfill", embeddingHandler.StartBackfill)
+		protected.GET("/admin/embeddings/backfill", embeddingHandler.Backfill)
+		protected.DELETE("/admin/embeddings/backfill", embeddingHandler.CancelBackfill)
+	}
+
+	protected.GET("/notifications", notificationHandler.List)
+	protected.POST("/notifications/:nid/read", notificationHandler.MarkRead)
+	protected.POST("/notifications/:nid/snooze", notificationHandler.Snooze)
+	protected.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+	protected.POST("/notifications/read-up-to", notificationHandler.MarkReadUpTo)
+	protected.GET("/notifications/stream", notificationHandler.Stream)
+	protected.GET("/notifications/preferences", notificationHandler.Preferences)
+	protected.PUT("/notifications/preferences", notificationHandler.UpdatePreferences)
+
+	protected.GET("/ws", liveHandler.Serve)
+
+	// API description, built last so it sees every route
+	spec := openapi.New(openapi.Info{Title: "Issues API", Version: "1"}, "/api/v1")
+	handler.DescribeAPI(spec)
+	openAPIHandler, err := handler.NewOpenAPIHandler(spec.Build(e.Routes()))
+	if err != nil {
+		return err
+	}
+	e.GET("/openapi.json", openAPIHandler.Spec)
+	e.GET("/docs", openAPIHandler.Docs)
+
+	ln, err := listener.Listen(context.Background(), fmt.Sprintf(":%d", cfg.Port), cfg.ReusePort)
+	if err != nil {
+		return fmt.Errorf("create listener: %w", err)
+	}
+	e.Listener = ln
 
 	go func() {
-		slog.Info("server starting", "port", cfg.Port)
-		if err := e.Start(fmt.Sprintf(":%d", cfg.Port)); err != nil && err != http.ErrServerClosed {
+		slog.Info("server starting", "addr", ln.Addr().String(), "reuse_port", cfg.ReusePort)
+		if err := e.Start(""); err != nil && err != http.ErrServerClosed {
 			slog.Error("server error", "error", err)
 		}
 	}()
 
+	if cfg.InternalAddr != "" {
+		go func() {
+			slog.Info("internal server starting", "addr", cfg.InternalAddr)
+			if err := internal.Start(cfg.InternalAddr); err != nil && err != http.ErrServerClosed {
+				slog.Error("internal server error", "error", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	slog.Info("shutdown signal received")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
+	// Finish the requests in progress first, ending streams so that their
+	// clients reconnect elsewhere. AI jobs get the rest of the timeout to
+	// finish; those still running are then interrupted and queued again.
+	var errs []error
 	if err := e.Shutdown(ctx); err != nil {
-		return fmt.Errorf("server shutdown: %w", err)
+		errs = append(errs, fmt.Errorf("server shutdown: %w", err))
+	}
+	if err := internal.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("internal server shutdown: %w", err))
+	}
+	if err := aiPool.Drain(ctx); err != nil {
+		slog.Warn("ai jobs interrupted by shutdown", "error", err)
+	}
+	stopBackground()
+	<-aiDone
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
 
 	slog.Info("server stopped gracefully")
 	return nil
 }
+
+// pushSenders returns a push sender for each configured platform.
+func pushSenders(ctx context.Context, cfg config.Config) (map[domain.PushPlatform]push.Sender, error) {
+	senders := make(map[domain.PushPlatform]push.Sender)
+	if cfg.FCMCredentialsFile != "" {
+		creds, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("read fcm credentials: %w", err)
+		}
+		fcm, err := push.NewFCM(ctx, creds)
+		if err != nil {
+			return nil, err
+		}
+		senders[domain.PushPlatformFCM] = fcm
+	}
+	if cfg.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read apns key: %w", err)
+		}
+		apns, err := push.NewAPNs(key, cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic, cfg.APNsSandbox)
+		if err != nil {
+			return nil, err
+		}
+		senders[domain.PushPlatformAPNs] = apns
+	}
+	return senders, nil
+}
+
+// maintenanceTasks returns the periodic maintenance tasks and their
+// schedules.
+func maintenanceTasks(cfg config.Config, auth *service.AuthService, tokens *service.AccessTokenService, jobs *service.AIJobService,
+	trash *service.TrashService, attachments *service.AttachmentService, dispatcher *webhook.Dispatcher) []scheduler.Task {
+	return []scheduler.Task{
+		{
+			Name:     "token-purge",
+			Schedule: cfg.TokenPurgeSchedule,
+			Timeout:  5 * time.Minute,
+			Run: func(ctx context.Context) error {
+				refreshed, refreshErr := auth.PurgeExpiredRefreshTokens(ctx)
+				if refreshed > 0 {
+					slog.Info("expired refresh tokens purged", "tokens", refreshed)
+				}
+				n, err := tokens.PurgeExpired(ctx)
+				if n > 0 {
+					slog.Info("expired access tokens purged", "tokens", n)
+				}
+				return errors.Join(refreshErr, err)
+			},
+		},
+		{
+			Name:     "ai-requeue",
+			Schedule: cfg.AIRequeueSchedule,
+			Timeout:  time.Minute,
+			Run: func(ctx context.Context) error {
+				n, err := jobs.RequeueStuck(ctx)
+				if n > 0 {
+					slog.Warn("stuck ai jobs requeued", "jobs", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "trash-purge",
+			Schedule: cfg.TrashPurgeSchedule,
+			Timeout:  30 * time.Minute,
+			Run: func(ctx context.Context) error {
+				issues, projects, err := trash.Purge(ctx)
+				if issues > 0 || projects > 0 {
+					slog.Info("trash purged", "issues", issues, "projects", projects)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "attachment-gc",
+			Schedule: cfg.AttachmentGCSchedule,
+			Timeout:  30 * time.Minute,
+			Run: func(ctx context.Context) error {
+				n, err := attachments.CollectBlobs(ctx, cfg.SignedURLTTL)
+				if n > 0 {
+					slog.Info("unreferenced attachment contents deleted", "blobs", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "webhook-retries",
+			Schedule: cfg.WebhookRetrySchedule,
+			Timeout:  10 * time.Minute,
+			Run:      dispatcher.SweepRetries,
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package aiworker
+
+import (
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// permissionArgs returns the Claude Code flags for a project's tool
+// permissions. The settings are validated again so that settings stored
+// before an allowlist change cannot widen an agent's access; denied tools are
+// always disallowed explicitly.
+func permissionArgs(settings *domain.AISettings) ([]string, error) {
+	args := []string{"--disallowedTools", strings.Join(domain.DeniedAITools, ",")}
+	if settings == nil {
+		return args, nil
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if len(settings.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(settings.AllowedTools, ","))
+	}
+	if settings.PermissionMode != "" {
+		args = append(args, "--permission-mode", string(settings.PermissionMode))
+	}
+	return args, nil
+}
@@ -0,0 +1,138 @@
+package aiworker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/storage"
+)
+
+const (
+	maxArtifactSize  = 50 << 20
+	maxArtifactCount = 20
+)
+
+// ArtifactStore records artifacts collected from job workspaces.
+type ArtifactStore interface {
+	AddArtifact(ctx context.Context, a domain.AIJobArtifact) (*domain.AIJobArtifact, error)
+}
+
+// Collector copies declared artifacts out of a finished job's workspace into
+// object storage.
+type Collector struct {
+	objects   storage.Storage
+	artifacts ArtifactStore
+}
+
+// NewCollector creates a Collector.
+func NewCollector(objects storage.Storage, artifacts ArtifactStore) *Collector {
+	return &Collector{objects: objects, artifacts: artifacts}
+}
+
+// Collect stores every regular file in workspace matching one of the glob
+// patterns. Files larger than 50 MiB, files outside the workspace and
+// matches beyond the first 20 are skipped and logged.
+func (c *Collector) Collect(ctx context.Context, job domain.AIJob, workspace string, patterns []string) ([]domain.AIJobArtifact, error) {
+	root, err := filepath.Abs(workspace)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve workspace: %w", err)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			slog.Warn("invalid artifact pattern", append(job.LogAttrs(), "pattern", pattern, "error", err)...)
+			continue
+		}
+		for _, m := range matches {
+			// Resolve symlinks so a link cannot expose files outside the workspace.
+			resolved, err := filepath.EvalSymlinks(m)
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(root, resolved)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				slog.Warn("artifact outside workspace", append(job.LogAttrs(), "artifact", m)...)
+				continue
+			}
+			if !seen[rel] {
+				seen[rel] = true
+				names = append(names, rel)
+			}
+		}
+	}
+
+	artifacts := []domain.AIJobArtifact{}
+	for _, rel := range names {
+		if len(artifacts) == maxArtifactCount {
+			slog.Warn("artifact limit reached", append(job.LogAttrs(), "skipped", len(names)-maxArtifactCount)...)
+			break
+		}
+		a, err := c.store(ctx, job, filepath.Join(root, rel), filepath.ToSlash(rel))
+		if err != nil {
+			return artifacts, err
+		}
+		if a != nil {
+			artifacts = append(artifacts, *a)
+		}
+	}
+	return artifacts, nil
+}
+
+// store uploads one file and records it. It returns nil for files that are
+// skipped.
+func (c *Collector) store(ctx context.Context, job domain.AIJob, path, name string) (*domain.AIJobArtifact, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat artifact %q: %w", name, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, nil
+	}
+	if info.Size() > maxArtifactSize {
+		slog.Warn("artifact too large", append(job.LogAttrs(), "artifact", name, "size", info.Size())...)
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open artifact %q: %w", name, err)
+	}
+	defer f.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(f, head)
+		contentType = http.DetectContentType(head[:n])
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("rewind artifact %q: %w", name, err)
+		}
+	}
+
+	key := fmt.Sprintf("ai-jobs/%d/%s", job.ID, name)
+	if err := c.objects.Put(ctx, key, f); err != nil {
+		return nil, fmt.Errorf("upload artifact %q: %w", name, err)
+	}
+
+	return c.artifacts.AddArtifact(ctx, domain.AIJobArtifact{
+		JobID:       job.ID,
+		Name:        name,
+		StorageKey:  key,
+		ContentType: contentType,
+		SizeBytes:   info.Size(),
+	})
+}
@@ -0,0 +1,27 @@
+package aiworker
+
+import "os"
+
+// agentEnvAllowlist names the server environment variables passed on to
+// Claude Code. The agent acts on user-written text, so it must not see the
+// server's own secrets: only what it needs to run and to authenticate with
+// Anthropic is passed.
+var agentEnvAllowlist = []string{
+	"PATH",
+	"HOME",
+	"ANTHROPIC_API_KEY",
+	"ANTHROPIC_AUTH_TOKEN",
+	"CLAUDE_CODE_OAUTH_TOKEN",
+}
+
+// agentEnv returns the environment for a Claude Code subprocess: the
+// allowlisted server variables that are set, followed by extra.
+func agentEnv(extra ...string) []string {
+	env := make([]string, 0, len(agentEnvAllowlist)+len(extra))
+	for _, name := range agentEnvAllowlist {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return append(env, extra...)
+}
@@ -0,0 +1,29 @@
+package aiworker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAgentEnv(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-test")
+	t.Setenv("JWT_SECRET", "server-secret")
+	t.Setenv("DATABASE_URL", "postgres://user:pass@db/issues")
+
+	env := agentEnv("ISSUES_JOB_ID=1")
+
+	for _, want := range []string{"PATH=/usr/bin", "ANTHROPIC_API_KEY=sk-ant-test", "ISSUES_JOB_ID=1"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("agentEnv() = %q, missing %q", env, want)
+		}
+	}
+	for _, kv := range env {
+		for _, secret := range []string{"JWT_SECRET=", "DATABASE_URL="} {
+			if strings.HasPrefix(kv, secret) {
+				t.Errorf("agentEnv() leaks %q", kv)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package aiworker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	// logFlushInterval is how often captured output is saved for live
+	// viewers.
+	logFlushInterval = 500 * time.Millisecond
+	// maxStreamedLog bounds how much output of one run is saved for live
+	// viewers. The full stderr log is still collected as an artifact.
+	maxStreamedLog = 5 << 20
+)
+
+// logTruncated is appended once a run's streamed output reaches its limit.
+const logTruncated = "\n[output truncated]\n"
+
+// logStreamer saves a run's output in chunks while the run is in progress,
+// and reports each save to the publisher if there is one. Failures to save
+// are logged and end streaming for the run, never the run itself.
+type logStreamer struct {
+	jobs      JobStore
+	publisher Publisher
+	job       domain.AIJob
+	runID     int64
+
+	mu        sync.Mutex
+	pending   []domain.AIJobLog
+	size      int
+	truncated bool
+	failed    bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newLogStreamer starts saving output of the run every logFlushInterval
+// until Close is called.
+func newLogStreamer(ctx context.Context, jobs JobStore, publisher Publisher, job domain.AIJob, runID int64) *logStreamer {
+	s := &logStreamer{
+		jobs:      jobs,
+		publisher: publisher,
+		job:       job,
+		runID:     runID,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// Writer returns a writer that captures output of the given stream. Its
+// writes never fail.
+func (s *logStreamer) Writer(stream domain.AILogStream) io.Writer {
+	return streamWriter{s: s, stream: stream}
+}
+
+type streamWriter struct {
+	s      *logStreamer
+	stream domain.AILogStream
+}
+
+func (w streamWriter) Write(p []byte) (int, error) {
+	w.s.append(w.stream, string(p))
+	return len(p), nil
+}
+
+func (s *logStreamer) append(stream domain.AILogStream, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.truncated || s.failed {
+		return
+	}
+	// Postgres text holds neither NUL bytes nor invalid UTF-8.
+	data = strings.ToValidUTF8(strings.ReplaceAll(data, "\x00", ""), "\uFFFD")
+	if room := maxStreamedLog - s.size; len(data) > room {
+		for room > 0 && !utf8.RuneStart(data[room]) {
+			room--
+		}
+		data = data[:room] + logTruncated
+		s.truncated = true
+	}
+	s.size += len(data)
+
+	if n := len(s.pending); n > 0 && s.pending[n-1].Stream == stream {
+		s.pending[n-1].Data += data
+		return
+	}
+	s.pending = append(s.pending, domain.AIJobLog{JobID: s.job.ID, RunID: s.runID, Stream: stream, Data: data})
+}
+
+func (s *logStreamer) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			// The run may have ended because ctx was cancelled; the last
+			// output is still worth saving.
+			s.flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+func (s *logStreamer) flush(ctx context.Context) {
+	s.mu.Lock()
+	logs := s.pending
+	s.pending = nil
+	size := s.size
+	s.mu.Unlock()
+	if len(logs) == 0 {
+		return
+	}
+
+	if err := s.jobs.AppendLogs(ctx, logs); err != nil {
+		slog.Error("ai job output not streamed", append(s.job.LogAttrs(), "error", err)...)
+		s.mu.Lock()
+		s.failed = true
+		s.mu.Unlock()
+		return
+	}
+	s.progress(ctx, size)
+}
+
+// progress publishes how much output the run has saved. Viewers that miss
+// an update catch up with the next one.
+func (s *logStreamer) progress(ctx context.Context, size int) {
+	if s.publisher == nil {
+		return
+	}
+	data, err := json.Marshal(domain.AIProgress{IssueID: s.job.IssueID, JobID: s.job.ID, RunID: s.runID, Output: size})
+	if err == nil {
+		err = s.publisher.Publish(ctx, domain.RealtimeEvent{ProjectID: s.job.ProjectID, Type: domain.RealtimeAIProgress, Data: data})
+	}
+	if err != nil {
+		slog.Warn("ai job progress not published", append(s.job.LogAttrs(), "error", err)...)
+	}
+}
+
+// Close saves the remaining output and stops streaming.
+func (s *logStreamer) Close() {
+	close(s.stop)
+	<-s.done
+}
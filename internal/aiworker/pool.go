@@ -0,0 +1,212 @@
+// Package aiworker runs AI jobs on a pool of workers whose size can be
+// changed while the server is running.
+package aiworker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// MaxWorkers bounds the pool size so a mistaken resize cannot exhaust the host.
+const MaxWorkers = 64
+
+// Processor claims and runs AI jobs.
+type Processor interface {
+	// Process claims at most one pending job and runs it to completion,
+	// reporting whether a job was claimed.
+	Process(ctx context.Context) (bool, error)
+}
+
+// Pool runs a resizable set of workers that call a Processor in a loop,
+// sleeping for the poll interval whenever the queue is empty.
+type Pool struct {
+	proc Processor
+	poll time.Duration
+
+	mu      sync.Mutex
+	ctx     context.Context
+	size    int
+	nextID  int
+	workers []*worker
+	wg      sync.WaitGroup
+}
+
+// worker tracks one worker goroutine. Closing stop asks it to exit after its
+// current job, so shrinking the pool never interrupts a running job.
+type worker struct {
+	id      int
+	started time.Time
+	stop    chan struct{}
+
+	busySince atomic.Int64 // unix nanoseconds, zero when idle
+	busyTotal atomic.Int64 // nanoseconds spent on finished jobs
+	jobs      atomic.Int64
+}
+
+// New creates a Pool of size workers. Workers start when Run is called.
+func New(proc Processor, size int, poll time.Duration) *Pool {
+	return &Pool{proc: proc, size: min(max(size, 0), MaxWorkers), poll: poll}
+}
+
+// Run starts the workers and blocks until ctx is cancelled, then waits for
+// the workers to exit. Cancelling ctx interrupts running jobs; call Drain
+// first to let them finish.
+func (p *Pool) Run(ctx context.Context) error {
+	p.mu.Lock()
+	p.ctx = ctx
+	for len(p.workers) < p.size {
+		p.spawn()
+	}
+	p.mu.Unlock()
+
+	<-ctx.Done()
+	p.wg.Wait()
+
+	p.mu.Lock()
+	p.ctx = nil
+	p.workers = nil
+	p.mu.Unlock()
+	return nil
+}
+
+// Drain stops every worker after its current job and waits until they
+// have all exited or ctx is done, returning ctx's error in that case. The
+// pool takes no new jobs afterwards, though Run still has to be stopped.
+func (p *Pool) Drain(ctx context.Context) error {
+	p.mu.Lock()
+	for _, w := range p.workers {
+		close(w.stop)
+	}
+	p.workers = nil
+	p.ctx = nil
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Size returns the desired number of workers.
+func (p *Pool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// Resize changes the number of workers. Removed workers finish their current
+// job before exiting.
+func (p *Pool) Resize(n int) error {
+	if n < 0 || n > MaxWorkers {
+		return fmt.Errorf("%w: worker count must be between 0 and %d", domain.ErrInvalidInput, MaxWorkers)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.size = n
+	if p.ctx == nil {
+		return nil
+	}
+	for len(p.workers) < n {
+		p.spawn()
+	}
+	for len(p.workers) > n {
+		last := p.workers[len(p.workers)-1]
+		close(last.stop)
+		p.workers = p.workers[:len(p.workers)-1]
+	}
+	slog.Info("ai worker pool resized", "workers", n)
+	return nil
+}
+
+// Stats returns a snapshot of every running worker.
+func (p *Pool) Stats() []domain.WorkerStats {
+	p.mu.Lock()
+	workers := append([]*worker(nil), p.workers...)
+	p.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]domain.WorkerStats, len(workers))
+	for i, w := range workers {
+		busy := time.Duration(w.busyTotal.Load())
+		since := w.busySince.Load()
+		if since != 0 {
+			busy += now.Sub(time.Unix(0, since))
+		}
+		var utilization float64
+		if up := now.Sub(w.started); up > 0 {
+			utilization = min(busy.Seconds()/up.Seconds(), 1)
+		}
+		stats[i] = domain.WorkerStats{
+			ID:          w.id,
+			Busy:        since != 0,
+			JobsRun:     w.jobs.Load(),
+			Utilization: utilization,
+			StartedAt:   w.started,
+		}
+	}
+	return stats
+}
+
+// spawn starts one worker. p.mu must be held and p.ctx set.
+func (p *Pool) spawn() {
+	p.nextID++
+	w := &worker{id: p.nextID, started: time.Now(), stop: make(chan struct{})}
+	p.workers = append(p.workers, w)
+
+	p.wg.Add(1)
+	go func(ctx context.Context) {
+		defer p.wg.Done()
+		p.work(ctx, w)
+	}(p.ctx)
+}
+
+// work runs jobs until the worker is stopped or ctx is cancelled.
+func (p *Pool) work(ctx context.Context, w *worker) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		default:
+		}
+
+		start := time.Now()
+		w.busySince.Store(start.UnixNano())
+		claimed, err := p.proc.Process(ctx)
+		w.busySince.Store(0)
+		if claimed {
+			w.busyTotal.Add(int64(time.Since(start)))
+			w.jobs.Add(1)
+		}
+		if err != nil && ctx.Err() == nil {
+			slog.Error("ai worker failed", "worker", w.id, "error", err)
+		}
+		if claimed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		case <-time.After(p.poll):
+		}
+	}
+}
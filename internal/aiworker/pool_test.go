@@ -0,0 +1,80 @@
+package aiworker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingProcessor runs one job that lasts until release is closed, and
+// reports whether the job's context was cancelled.
+type blockingProcessor struct {
+	started     chan struct{}
+	release     chan struct{}
+	interrupted chan bool
+}
+
+func (p *blockingProcessor) Process(ctx context.Context) (bool, error) {
+	select {
+	case <-p.started:
+		return false, nil
+	default:
+		close(p.started)
+	}
+	select {
+	case <-p.release:
+		p.interrupted <- false
+		return true, nil
+	case <-ctx.Done():
+		p.interrupted <- true
+		return true, ctx.Err()
+	}
+}
+
+func TestPoolDrain(t *testing.T) {
+	tests := []struct {
+		name            string
+		finish          bool
+		wantErr         error
+		wantInterrupted bool
+	}{
+		{name: "job finishes", finish: true},
+		{name: "drain times out", wantErr: context.DeadlineExceeded, wantInterrupted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc := &blockingProcessor{
+				started:     make(chan struct{}),
+				release:     make(chan struct{}),
+				interrupted: make(chan bool, 1),
+			}
+			pool := New(proc, 1, time.Millisecond)
+			ctx, stop := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				pool.Run(ctx)
+			}()
+			<-proc.started
+
+			drainCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if tt.finish {
+				close(proc.release)
+			}
+			if err := pool.Drain(drainCtx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Drain() error = %v, want %v", err, tt.wantErr)
+			}
+			stop()
+			<-done
+
+			if got := <-proc.interrupted; got != tt.wantInterrupted {
+				t.Errorf("job interrupted = %v, want %v", got, tt.wantInterrupted)
+			}
+			if n := len(pool.Stats()); n != 0 {
+				t.Errorf("Stats() after drain has %d workers, want 0", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package aiworker
+
+import (
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// Prompt builds the Claude Code prompt for a job: the project's standing
+// instructions, then the issue, then, for review jobs, the diff to review and
+// the expected reply format, then any instructions given for this run.
+func Prompt(job domain.AIJob, issue domain.Issue, settings *domain.AISettings) string {
+	var b strings.Builder
+	if settings != nil && strings.TrimSpace(settings.Instructions) != "" {
+		b.WriteString(strings.TrimSpace(settings.Instructions))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("# ")
+	b.WriteString(issue.Title)
+	b.WriteString("\n")
+	if issue.Body != nil && strings.TrimSpace(*issue.Body) != "" {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimSpace(*issue.Body))
+		b.WriteString("\n")
+	}
+
+	if job.Mode == domain.AIJobModeReview && job.Diff != nil {
+		b.WriteString(reviewInstructions)
+		b.WriteString("\n```diff\n")
+		b.WriteString(strings.TrimRight(*job.Diff, "\n"))
+		b.WriteString("\n```\n")
+	}
+
+	if job.Instructions != nil {
+		b.WriteString("\n## Additional instructions\n\n")
+		b.WriteString(*job.Instructions)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
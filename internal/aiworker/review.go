@@ -0,0 +1,69 @@
+package aiworker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// maxReviewComments bounds how many findings one review may store.
+const maxReviewComments = 200
+
+// reviewInstructions asks the agent to review rather than implement, and to
+// answer in the shape ParseReview reads.
+const reviewInstructions = `
+## Review
+
+Do not change any files. Review the following change made for this issue.
+Reply with only a JSON array of findings, each of the form
+{"path": "file path", "line": line number in the new version or null,
+"severity": "info" | "suggestion" | "blocking", "body": "the comment"}.
+Reply with [] if there is nothing to report.
+`
+
+// ParseReview extracts review comments from the agent's reply to a review
+// job. The JSON array may be wrapped in a Markdown code fence or surrounded
+// by prose.
+func ParseReview(output string) ([]domain.AIReviewComment, error) {
+	start := strings.Index(output, "[")
+	end := strings.LastIndex(output, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("review output contains no JSON array")
+	}
+
+	var findings []struct {
+		Path     string                `json:"path"`
+		Line     *int                  `json:"line"`
+		Severity domain.ReviewSeverity `json:"severity"`
+		Body     string                `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &findings); err != nil {
+		return nil, fmt.Errorf("parse review output: %w", err)
+	}
+	if len(findings) > maxReviewComments {
+		findings = findings[:maxReviewComments]
+	}
+
+	comments := make([]domain.AIReviewComment, 0, len(findings))
+	for _, f := range findings {
+		body := strings.TrimSpace(f.Body)
+		if body == "" {
+			continue
+		}
+		if !f.Severity.Valid() {
+			f.Severity = domain.ReviewSeverityInfo
+		}
+		if f.Line != nil && *f.Line <= 0 {
+			f.Line = nil
+		}
+		comments = append(comments, domain.AIReviewComment{
+			Path:     strings.TrimSpace(f.Path),
+			Line:     f.Line,
+			Severity: f.Severity,
+			Body:     body,
+		})
+	}
+	return comments, nil
+}
@@ -0,0 +1,526 @@
+package aiworker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/tracing"
+)
+
+// tracer records the spans of AI jobs and completions.
+var tracer = otel.Tracer("github.com/sumire/issues/internal/aiworker")
+
+const (
+	// maxOutputSize bounds how much of the agent's standard output is kept.
+	maxOutputSize = 10 << 20
+	// cancelPollInterval is how often a running job checks whether it has
+	// been cancelled.
+	cancelPollInterval = 2 * time.Second
+	// releaseTimeout bounds queueing a job again when a shutdown
+	// interrupts it.
+	releaseTimeout = 5 * time.Second
+	// logName is the file in the workspace that receives the agent's
+	// standard error, redacted. It is always collected as an artifact.
+	logName = "claude.log"
+)
+
+// JobStore is the AI job queue consumed by the Runner.
+type JobStore interface {
+	Claim(ctx context.Context) (*domain.AIJob, error)
+	Complete(ctx context.Context, jobID int64) error
+	Fail(ctx context.Context, jobID int64, reason string, retry bool) (domain.JobStatus, error)
+	AppendLogs(ctx context.Context, logs []domain.AIJobLog) error
+	CancelRequested(ctx context.Context, jobID int64) (bool, error)
+	MarkCancelled(ctx context.Context, jobID int64) (*int64, error)
+	Release(ctx context.Context, jobID int64) error
+	StartRun(ctx context.Context, run domain.AIRun) (*domain.AIRun, error)
+	FinishRun(ctx context.Context, runID int64, status domain.JobStatus, sessionID, result *string) error
+	AddReviewComments(ctx context.Context, jobID, issueID int64, comments []domain.AIReviewComment) error
+}
+
+// IssueStore is the issue data consumed by the Runner.
+type IssueStore interface {
+	FindByID(ctx context.Context, id int64) (*domain.Issue, error)
+	SetAIResult(ctx context.Context, issueID int64, sessionID, result *string) error
+	TransitionStatus(ctx context.Context, issueID int64, from, to domain.IssueStatus) (bool, error)
+}
+
+// ProjectStore is the project data consumed by the Runner.
+type ProjectStore interface {
+	FindByID(ctx context.Context, id int64) (*domain.Project, error)
+}
+
+// Publisher pushes realtime events to the streams watching a project.
+type Publisher interface {
+	Publish(ctx context.Context, event domain.RealtimeEvent) error
+}
+
+// EventRecorder records issue events.
+type EventRecorder interface {
+	Record(ctx context.Context, event domain.IssueEvent) error
+}
+
+// ResultPoster reports a finished job on its issue.
+type ResultPoster interface {
+	PostResult(ctx context.Context, job domain.AIJob, summary string, artifacts []domain.AIJobArtifact) (*domain.Comment, error)
+}
+
+// Runner claims AI jobs and runs Claude Code for them, one job per call to
+// Process. It implements Processor.
+type Runner struct {
+	binary    string
+	timeout   time.Duration
+	workDir   string
+	jobs      JobStore
+	issues    IssueStore
+	projects  ProjectStore
+	events    EventRecorder
+	collector *Collector
+	poster    ResultPoster
+	guard     *Guard
+	publisher Publisher
+}
+
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithWorkDir sets the directory job workspaces are created in. By default
+// they are created in the system temporary directory.
+func WithWorkDir(dir string) RunnerOption {
+	return func(r *Runner) {
+		r.workDir = dir
+	}
+}
+
+// WithPublisher reports the output of running jobs as ai.progress events
+// through p each time it is saved.
+func WithPublisher(p Publisher) RunnerOption {
+	return func(r *Runner) {
+		r.publisher = p
+	}
+}
+
+// NewRunner creates a Runner that runs binary with at most timeout per run.
+func NewRunner(binary string, timeout time.Duration, jobs JobStore, issues IssueStore, projects ProjectStore, events EventRecorder,
+	collector *Collector, poster ResultPoster, guard *Guard, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		binary:    binary,
+		timeout:   timeout,
+		jobs:      jobs,
+		issues:    issues,
+		projects:  projects,
+		events:    events,
+		collector: collector,
+		poster:    poster,
+		guard:     guard,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// outcome is what one run of Claude Code produced.
+type outcome struct {
+	sessionID *string
+	result    string
+	err       error
+	// retry reports whether the failure may succeed on another attempt.
+	retry bool
+	// cancelled reports whether the run was stopped on request.
+	cancelled bool
+}
+
+// Process claims one job and runs it. Failures of the job itself are
+// recorded on the job and not returned.
+func (r *Runner) Process(ctx context.Context) (bool, error) {
+	job, err := r.jobs.Claim(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	log := slog.With(job.LogAttrs()...)
+	log.Info("ai job started", "mode", job.Mode)
+	ctx, span := tracer.Start(ctx, "ai.job", trace.WithAttributes(jobAttrs(*job)...))
+	defer span.End()
+	if job.CancelRequestedAt != nil {
+		// A lost run of a job cancelled meanwhile was reclaimed.
+		return true, r.cancel(ctx, *job, nil)
+	}
+
+	issue, err := r.issues.FindByID(ctx, job.IssueID)
+	if err != nil {
+		return true, r.fail(ctx, *job, fmt.Errorf("load issue: %w", err), true)
+	}
+	project, err := r.projects.FindByID(ctx, job.ProjectID)
+	if err != nil {
+		return true, r.fail(ctx, *job, fmt.Errorf("load project: %w", err), true)
+	}
+	settings := project.Settings.AI
+
+	if job.Attempts > job.MaxAttempts {
+		// A lost run was reclaimed after its last attempt.
+		return true, r.fail(ctx, *job, errors.New("worker lost the job on its last attempt"), false)
+	}
+
+	prompt, findings := r.guard.Prompt(*job, *issue, settings)
+	if findings.Redactions > 0 || len(findings.Injections) > 0 {
+		log.Warn("ai prompt sanitized", "redactions", findings.Redactions, "injections", len(findings.Injections))
+	}
+
+	run, err := r.jobs.StartRun(ctx, domain.AIRun{
+		JobID:       job.ID,
+		IssueID:     job.IssueID,
+		Attempt:     job.Attempts,
+		Prompt:      prompt,
+		SessionID:   job.ResumeSessionID,
+		TriggeredBy: job.TriggeredBy,
+	})
+	if err != nil {
+		return true, r.fail(ctx, *job, err, true)
+	}
+
+	if job.Mode == domain.AIJobModeImplement {
+		r.transition(ctx, *job, domain.IssueStatusOpen, domain.IssueStatusInProgress)
+	}
+
+	workspace, err := os.MkdirTemp(r.workDir, fmt.Sprintf("ai-job-%d-", job.ID))
+	if err != nil {
+		return true, r.finish(ctx, *job, run.ID, nil, outcome{err: fmt.Errorf("create workspace: %w", err), retry: true})
+	}
+	defer os.RemoveAll(workspace)
+
+	out := r.execute(ctx, *job, run.ID, prompt, settings, workspace)
+	if ctx.Err() != nil {
+		// Shutting down: queue the job again at once rather than leaving it
+		// running until it is presumed lost.
+		r.release(context.WithoutCancel(ctx), *job, run.ID)
+		return true, ctx.Err()
+	}
+
+	patterns := []string{logName}
+	if settings != nil {
+		patterns = append(patterns, settings.Artifacts...)
+	}
+	artifacts, err := r.collector.Collect(ctx, *job, workspace, patterns)
+	if err != nil {
+		log.Error("ai artifacts not collected", "error", err)
+	}
+	return true, r.finish(ctx, *job, run.ID, artifacts, out)
+}
+
+// execute runs Claude Code for the job in workspace. Its output is streamed
+// to the run's log as it is produced, with secrets redacted.
+func (r *Runner) execute(ctx context.Context, job domain.AIJob, runID int64, prompt string, settings *domain.AISettings, workspace string) outcome {
+	perms, err := permissionArgs(settings)
+	if err != nil {
+		return outcome{err: fmt.Errorf("invalid ai settings: %w", err)}
+	}
+	args := append([]string{"-p", prompt, "--output-format", "json"}, perms...)
+	if job.ResumeSessionID != nil {
+		args = append(args, "--resume", *job.ResumeSessionID)
+	}
+
+	logFile, err := os.Create(filepath.Join(workspace, logName))
+	if err != nil {
+		return outcome{err: fmt.Errorf("create log: %w", err), retry: true}
+	}
+	defer logFile.Close()
+	live := newLogStreamer(ctx, r.jobs, r.publisher, job, runID)
+	defer live.Close()
+	stderr := r.guard.Writer(io.MultiWriter(logFile, live.Writer(domain.AILogStderr)))
+	defer stderr.Close()
+	liveStdout := r.guard.Writer(live.Writer(domain.AILogStdout))
+	defer liveStdout.Close()
+
+	timeout := job.Remaining(time.Now(), r.timeout)
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cancelled atomic.Bool
+	go r.watchCancellation(runCtx, job, func() {
+		cancelled.Store(true)
+		cancel()
+	})
+
+	runCtx, span := tracer.Start(runCtx, "ai.claude_code")
+	defer span.End()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(runCtx, r.binary, args...)
+	cmd.Dir = workspace
+	cmd.Env = agentEnv(job.TraceEnv()...)
+	if tp := tracing.Traceparent(runCtx); tp != "" {
+		// Lets tools run by Claude Code continue the job's trace.
+		cmd.Env = append(cmd.Env, "TRACEPARENT="+tp)
+	}
+	cmd.Stdout = io.MultiWriter(&limitedWriter{w: &stdout, n: maxOutputSize}, liveStdout)
+	cmd.Stderr = stderr
+
+	runErr := cmd.Run()
+	if cancelled.Load() {
+		span.SetAttributes(attribute.Bool("ai.cancelled", true))
+		return outcome{cancelled: true}
+	}
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		err := fmt.Errorf("timed out after %s", timeout)
+		tracing.SetError(span, err)
+		return outcome{err: err, retry: true}
+	}
+	out := parseOutput(stdout.Bytes(), runErr)
+	tracing.SetError(span, out.err)
+	return out
+}
+
+// watchCancellation calls stop once the job is asked to stop, checking
+// every cancelPollInterval until ctx is done.
+func (r *Runner) watchCancellation(ctx context.Context, job domain.AIJob, stop func()) {
+	ticker := time.NewTicker(cancelPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		requested, err := r.jobs.CancelRequested(ctx, job.ID)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("ai job cancellation not checked", append(job.LogAttrs(), "error", err)...)
+			}
+			continue
+		}
+		if requested {
+			stop()
+			return
+		}
+	}
+}
+
+// parseOutput reads Claude Code's JSON result.
+func parseOutput(stdout []byte, runErr error) outcome {
+	var res struct {
+		Result    string `json:"result"`
+		SessionID string `json:"session_id"`
+		IsError   bool   `json:"is_error"`
+		Subtype   string `json:"subtype"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		if runErr != nil {
+			return outcome{err: fmt.Errorf("claude code failed: %w", runErr), retry: true}
+		}
+		return outcome{err: fmt.Errorf("parse claude code output: %w", err), retry: true}
+	}
+
+	out := outcome{result: res.Result}
+	if res.SessionID != "" {
+		out.sessionID = &res.SessionID
+	}
+	switch {
+	case res.IsError:
+		out.err = fmt.Errorf("claude code reported %s", res.Subtype)
+		out.retry = true
+	case runErr != nil:
+		out.err = fmt.Errorf("claude code failed: %w", runErr)
+		out.retry = true
+	}
+	return out
+}
+
+// finish records the outcome of a run on the run, the job and the issue,
+// and posts it on the issue once the job is done.
+func (r *Runner) finish(ctx context.Context, job domain.AIJob, runID int64, artifacts []domain.AIJobArtifact, out outcome) error {
+	if out.cancelled {
+		if err := r.jobs.FinishRun(ctx, runID, domain.JobStatusCancelled, nil, nil); err != nil {
+			slog.Error("ai run not recorded", append(job.LogAttrs(), "error", err)...)
+		}
+		return r.cancel(ctx, job, artifacts)
+	}
+
+	result, _ := r.guard.Redact(out.result)
+	var resultPtr *string
+	if result != "" {
+		resultPtr = &result
+	}
+
+	if out.err == nil && job.Mode == domain.AIJobModeReview {
+		comments, err := ParseReview(result)
+		if err == nil {
+			err = r.jobs.AddReviewComments(ctx, job.ID, job.IssueID, comments)
+		}
+		if err != nil {
+			out = outcome{sessionID: out.sessionID, result: out.result, err: err, retry: true}
+		} else {
+			result = fmt.Sprintf("Review finished with %d comments.", len(comments))
+		}
+	}
+
+	runStatus := domain.JobStatusCompleted
+	if out.err != nil {
+		runStatus = domain.JobStatusFailed
+	}
+	if err := r.jobs.FinishRun(ctx, runID, runStatus, out.sessionID, resultPtr); err != nil {
+		slog.Error("ai run not recorded", append(job.LogAttrs(), "error", err)...)
+	}
+
+	if out.err != nil {
+		return r.fail(ctx, job, out.err, out.retry, artifacts...)
+	}
+
+	if job.Mode == domain.AIJobModeImplement {
+		if err := r.issues.SetAIResult(ctx, job.IssueID, out.sessionID, resultPtr); err != nil {
+			return r.fail(ctx, job, err, true, artifacts...)
+		}
+		r.transition(ctx, job, domain.IssueStatusInProgress, domain.IssueStatusCompleted)
+	}
+	if err := r.jobs.Complete(ctx, job.ID); err != nil {
+		return err
+	}
+	job.Status = domain.JobStatusCompleted
+	slog.Info("ai job completed", job.LogAttrs()...)
+	r.record(ctx, job, domain.EventAIJobCompleted, nil, domain.EventData{"job_id": job.ID, "mode": string(job.Mode)})
+	r.post(ctx, job, result, artifacts)
+	return nil
+}
+
+// fail records a failed attempt. Once the job has no attempts left, an
+// issue the job started is reopened and the failure is posted on it.
+func (r *Runner) fail(ctx context.Context, job domain.AIJob, cause error, retry bool, artifacts ...domain.AIJobArtifact) error {
+	tracing.SetError(trace.SpanFromContext(ctx), cause)
+	status, err := r.jobs.Fail(ctx, job.ID, cause.Error(), retry)
+	if err != nil {
+		return err
+	}
+	slog.Warn("ai job attempt failed", append(job.LogAttrs(), "status", status, "error", cause)...)
+	if status != domain.JobStatusFailed {
+		return nil
+	}
+
+	if job.Mode == domain.AIJobModeImplement {
+		r.transition(ctx, job, domain.IssueStatusInProgress, domain.IssueStatusOpen)
+	}
+	job.Status = status
+	r.record(ctx, job, domain.EventAIJobFailed, nil, domain.EventData{"job_id": job.ID, "mode": string(job.Mode), "error": cause.Error()})
+	r.post(ctx, job, "The job failed: "+cause.Error(), artifacts)
+	return nil
+}
+
+// cancel records that the job stopped on request. An issue the job started
+// is reopened and the cancellation is posted on it.
+func (r *Runner) cancel(ctx context.Context, job domain.AIJob, artifacts []domain.AIJobArtifact) error {
+	cancelledBy, err := r.jobs.MarkCancelled(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	slog.Info("ai job cancelled", job.LogAttrs()...)
+
+	if job.Mode == domain.AIJobModeImplement {
+		r.transition(ctx, job, domain.IssueStatusInProgress, domain.IssueStatusOpen)
+	}
+	job.Status = domain.JobStatusCancelled
+	r.record(ctx, job, domain.EventAIJobCancelled, cancelledBy, domain.EventData{"job_id": job.ID, "mode": string(job.Mode)})
+	r.post(ctx, job, "The job was cancelled.", artifacts)
+	return nil
+}
+
+// release ends a run interrupted by shutdown and queues its job again
+// without counting the attempt.
+func (r *Runner) release(ctx context.Context, job domain.AIJob, runID int64) {
+	ctx, cancel := context.WithTimeout(ctx, releaseTimeout)
+	defer cancel()
+	log := slog.With(job.LogAttrs()...)
+	if err := r.jobs.FinishRun(ctx, runID, domain.JobStatusCancelled, nil, nil); err != nil {
+		log.Error("interrupted ai run not recorded", "error", err)
+	}
+	if err := r.jobs.Release(ctx, job.ID); err != nil {
+		log.Error("interrupted ai job not queued again", "error", err)
+		return
+	}
+	log.Info("ai job queued again after shutdown")
+}
+
+// transition moves the job's issue between statuses if it is still in from
+// and records the change. Failures are logged: the job's outcome matters more
+// than the issue's status.
+func (r *Runner) transition(ctx context.Context, job domain.AIJob, from, to domain.IssueStatus) {
+	moved, err := r.issues.TransitionStatus(ctx, job.IssueID, from, to)
+	if err != nil {
+		slog.Error("issue status not updated", append(job.LogAttrs(), "to", to, "error", err)...)
+		return
+	}
+	if !moved {
+		return
+	}
+	r.record(ctx, job, domain.EventStatusChanged, nil, domain.EventData{"from": from, "to": to, "job_id": job.ID})
+}
+
+// record records an event on the job's issue. Failures are logged.
+func (r *Runner) record(ctx context.Context, job domain.AIJob, typ domain.EventType, actorID *int64, data domain.EventData) {
+	err := r.events.Record(ctx, domain.IssueEvent{
+		ProjectID: job.ProjectID,
+		IssueID:   job.IssueID,
+		ActorID:   actorID,
+		Type:      typ,
+		Data:      data,
+	})
+	if err != nil {
+		slog.Error("failed to record issue event", append(job.LogAttrs(), "type", typ, "error", err)...)
+	}
+}
+
+// post reports the job on its issue. Failures are logged.
+func (r *Runner) post(ctx context.Context, job domain.AIJob, summary string, artifacts []domain.AIJobArtifact) {
+	if _, err := r.poster.PostResult(ctx, job, summary, artifacts); err != nil {
+		slog.Error("ai result not posted", append(job.LogAttrs(), "error", err)...)
+	}
+}
+
+// limitedWriter keeps the first n bytes written to it and discards the rest,
+// so a runaway agent cannot exhaust memory.
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		k := min(int64(len(p)), l.n)
+		if _, err := l.w.Write(p[:k]); err != nil {
+			return 0, err
+		}
+		l.n -= k
+	}
+	return len(p), nil
+}
+
+// jobAttrs describes a job on its trace span. The request ID links the
+// span to the trace of the request that queued the job.
+func jobAttrs(job domain.AIJob) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.Int64("ai.job.id", job.ID),
+		attribute.Int64("ai.issue.id", job.IssueID),
+		attribute.String("ai.job.mode", string(job.Mode)),
+		attribute.Int("ai.job.attempt", job.Attempts),
+	}
+	if job.RequestID != nil {
+		attrs = append(attrs, attribute.String("request_id", *job.RequestID))
+	}
+	return attrs
+}
@@ -0,0 +1,25 @@
+// Package antivirus scans uploaded files for malware before they are
+// served to anyone, using a ClamAV daemon or any other Scanner.
+package antivirus
+
+import (
+	"context"
+	"io"
+)
+
+// Verdict is the outcome of scanning a file.
+type Verdict struct {
+	// Infected reports whether malware was found.
+	Infected bool
+	// Signature names the malware found, as in "Eicar-Test-Signature".
+	Signature string
+}
+
+// Scanner checks files for malware.
+type Scanner interface {
+	// Name identifies the scanner in logs and on scanned records.
+	Name() string
+	// Scan reads r to the end and reports what was found. An error means
+	// the file could not be scanned, not that it is infected.
+	Scan(ctx context.Context, r io.Reader) (Verdict, error)
+}
@@ -0,0 +1,51 @@
+// Package chat posts issue and AI job events to chat rooms, for
+// deployments that run their own chat server rather than a hosted service.
+package chat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// Message is an event as posted to a chat room. ID identifies the event, so
+// a notifier can make resending the same message harmless.
+type Message struct {
+	ID      string
+	Summary string
+	Link    string
+	Facts   []Fact
+}
+
+// Fact is a labelled detail shown under a message's summary.
+type Fact struct {
+	Label string
+	Value string
+}
+
+// Notifier posts messages to a chat room.
+type Notifier interface {
+	// Name identifies the notifier in logs.
+	Name() string
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Summary returns a one-line description of an event on an issue.
+func Summary(typ domain.EventType, issueID int64, data domain.EventData) string {
+	switch typ {
+	case domain.EventIssueCreated:
+		return fmt.Sprintf("Issue #%d created", issueID)
+	case domain.EventStatusChanged:
+		return fmt.Sprintf("Issue #%d moved from %v to %v", issueID, data["from"], data["to"])
+	case domain.EventAIRun:
+		return fmt.Sprintf("AI job queued for issue #%d", issueID)
+	case domain.EventAIJobCompleted:
+		return fmt.Sprintf("AI job completed on issue #%d", issueID)
+	case domain.EventAIJobFailed:
+		return fmt.Sprintf("AI job failed on issue #%d", issueID)
+	case domain.EventAIJobCancelled:
+		return fmt.Sprintf("AI job cancelled on issue #%d", issueID)
+	}
+	return fmt.Sprintf("%s on issue #%d", typ, issueID)
+}
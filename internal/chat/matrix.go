@@ -0,0 +1,117 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxErrorBody bounds how much of an error response is kept.
+const maxErrorBody = 1 << 10
+
+// Matrix posts messages to a Matrix room as notices, through the
+// client-server API of the homeserver the bot account lives on.
+type Matrix struct {
+	homeserver  string
+	accessToken string
+	roomID      string
+	client      *http.Client
+}
+
+// NewMatrix creates a Matrix notifier that posts to roomID as the account
+// accessToken belongs to. The account must already have joined the room.
+func NewMatrix(homeserver, accessToken, roomID string) *Matrix {
+	return &Matrix{
+		homeserver:  strings.TrimRight(homeserver, "/"),
+		accessToken: accessToken,
+		roomID:      roomID,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name identifies the notifier in logs.
+func (m *Matrix) Name() string {
+	return "matrix"
+}
+
+// matrixEvent is the content of an m.room.message event.
+type matrixEvent struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// Notify posts a message. The message ID is the transaction ID, so the
+// homeserver ignores a message sent again after a lost response.
+func (m *Matrix) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(matrixEvent{
+		MsgType:       "m.notice",
+		Body:          matrixText(msg),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: matrixHTML(msg),
+	})
+	if err != nil {
+		return fmt.Errorf("encode matrix message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		m.homeserver, url.PathEscape(m.roomID), url.PathEscape("issues-"+msg.ID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build matrix request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send matrix message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("matrix returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// matrixText renders a message as plain text, for clients without HTML.
+func matrixText(msg Message) string {
+	var b strings.Builder
+	b.WriteString(msg.Summary)
+	for _, f := range msg.Facts {
+		fmt.Fprintf(&b, "\n%s: %s", f.Label, f.Value)
+	}
+	if msg.Link != "" {
+		b.WriteString("\n" + msg.Link)
+	}
+	return b.String()
+}
+
+// matrixHTML renders a message in the HTML subset Matrix clients display.
+func matrixHTML(msg Message) string {
+	var b strings.Builder
+	summary := html.EscapeString(msg.Summary)
+	if msg.Link != "" {
+		summary = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(msg.Link), summary)
+	}
+	fmt.Fprintf(&b, "<strong>%s</strong>", summary)
+	if len(msg.Facts) > 0 {
+		b.WriteString("<ul>")
+		for _, f := range msg.Facts {
+			fmt.Fprintf(&b, "<li>%s: %s</li>", html.EscapeString(f.Label), html.EscapeString(f.Value))
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}
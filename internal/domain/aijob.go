@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // JobStatus represents the state of an AI job.
 type JobStatus string
@@ -10,17 +13,261 @@ const (
 	JobStatusRunning   JobStatus = "running"
 	JobStatusCompleted JobStatus = "completed"
 	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
 )
 
-// AIJob represents a background job for Claude Code execution.
+// Valid reports whether s is a known job status.
+func (s JobStatus) Valid() bool {
+	switch s {
+	case JobStatusPending, JobStatusRunning, JobStatusCompleted, JobStatusFailed, JobStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether a job may move from s to next. A running
+// job goes back to pending when a failed attempt is retried, and failed or
+// cancelled jobs can be retried by hand. Completed jobs are final.
+func (s JobStatus) CanTransition(next JobStatus) bool {
+	switch s {
+	case JobStatusPending:
+		return next == JobStatusRunning || next == JobStatusCancelled
+	case JobStatusRunning:
+		return next == JobStatusCompleted || next == JobStatusFailed || next == JobStatusPending || next == JobStatusCancelled
+	case JobStatusFailed, JobStatusCancelled:
+		return next == JobStatusPending
+	}
+	return false
+}
+
+// Retryable reports whether a job in status s can be queued again by hand.
+func (s JobStatus) Retryable() bool {
+	return s == JobStatusFailed || s == JobStatusCancelled
+}
+
+// AIJobMode is what an AI job asks the agent to do.
+type AIJobMode string
+
+const (
+	// AIJobModeImplement works on the issue itself.
+	AIJobModeImplement AIJobMode = "implement"
+	// AIJobModeReview reviews a diff submitted for the issue.
+	AIJobModeReview AIJobMode = "review"
+)
+
+// AIJob represents a background job for Claude Code execution. Instructions
+// are appended to the prompt built from the issue, and ResumeSessionID
+// continues an earlier Claude Code session instead of starting a new one.
+// Review jobs carry the Diff under review. CancelRequestedAt is set while a
+// cancelled running job waits for its worker to stop.
 type AIJob struct {
+	ID                int64      `json:"id" db:"id"`
+	IssueID           int64      `json:"issue_id" db:"issue_id"`
+	ProjectID         int64      `json:"project_id" db:"project_id"`
+	Mode              AIJobMode  `json:"mode" db:"mode"`
+	Diff              *string    `json:"-" db:"diff"`
+	Status            JobStatus  `json:"status" db:"status"`
+	Attempts          int        `json:"attempts" db:"attempts"`
+	MaxAttempts       int        `json:"max_attempts" db:"max_attempts"`
+	TimeoutSeconds    *int       `json:"timeout_seconds,omitempty" db:"timeout_seconds"`
+	Instructions      *string    `json:"instructions,omitempty" db:"instructions"`
+	ResumeSessionID   *string    `json:"resume_session_id,omitempty" db:"resume_session_id"`
+	RequestID         *string    `json:"request_id,omitempty" db:"request_id"`
+	TriggeredBy       *int64     `json:"triggered_by,omitempty" db:"triggered_by"`
+	StartedAt         *time.Time `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	ErrorMsg          *string    `json:"error_msg,omitempty" db:"error_msg"`
+	CancelRequestedAt *time.Time `json:"cancel_requested_at,omitempty" db:"cancel_requested_at"`
+	CancelledBy       *int64     `json:"cancelled_by,omitempty" db:"cancelled_by"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Timeout returns how long one run of the job may take: its own timeout if
+// set, capped at the server limit.
+func (j AIJob) Timeout(limit time.Duration) time.Duration {
+	if j.TimeoutSeconds == nil {
+		return limit
+	}
+	return min(time.Duration(*j.TimeoutSeconds)*time.Second, limit)
+}
+
+// Remaining returns how much of the current run's timeout is left at now,
+// or the whole timeout if the job has not started.
+func (j AIJob) Remaining(now time.Time, limit time.Duration) time.Duration {
+	timeout := j.Timeout(limit)
+	if j.StartedAt == nil {
+		return timeout
+	}
+	return max(j.StartedAt.Add(timeout).Sub(now), 0)
+}
+
+// LogAttrs returns structured log fields identifying the job and its trigger.
+func (j AIJob) LogAttrs() []any {
+	attrs := []any{"job_id", j.ID, "issue_id", j.IssueID, "attempt", j.Attempts}
+	if j.RequestID != nil {
+		attrs = append(attrs, "request_id", *j.RequestID)
+	}
+	if j.TriggeredBy != nil {
+		attrs = append(attrs, "triggered_by", *j.TriggeredBy)
+	}
+	return attrs
+}
+
+// TraceEnv returns environment variables that let a Claude Code run be traced
+// back to the job and request that triggered it.
+func (j AIJob) TraceEnv() []string {
+	env := []string{
+		"ISSUES_JOB_ID=" + strconv.FormatInt(j.ID, 10),
+		"ISSUES_ISSUE_ID=" + strconv.FormatInt(j.IssueID, 10),
+	}
+	if j.RequestID != nil {
+		env = append(env, "ISSUES_REQUEST_ID="+*j.RequestID)
+	}
+	if j.TriggeredBy != nil {
+		env = append(env, "ISSUES_TRIGGERED_BY="+strconv.FormatInt(*j.TriggeredBy, 10))
+	}
+	return env
+}
+
+// AIJobFilter narrows an AI job listing. Zero-valued fields are not applied.
+// Ages are measured from when the job was created.
+type AIJobFilter struct {
+	Statuses  []JobStatus
+	ProjectID *int64
+	MinAge    time.Duration
+	MaxAge    time.Duration
+	Cursor    ListPosition
+	Limit     int
+}
+
+// FlagAIPaused is the system flag that stops AI jobs being claimed in every project.
+const FlagAIPaused = "ai_paused"
+
+// AIJobBacklog is the claimable pending AI jobs of one project.
+type AIJobBacklog struct {
+	ProjectID            int64   `json:"project_id" db:"project_id"`
+	Pending              int     `json:"pending" db:"pending"`
+	OldestPendingSeconds float64 `json:"oldest_pending_seconds" db:"oldest_pending_seconds"`
+}
+
+// AIJobQueueStats summarises the AI job queue. Paused counts pending jobs
+// that cannot be claimed because AI processing is paused. Wait and failure figures cover
+// jobs started or finished within Window and are nil when there were none.
+type AIJobQueueStats struct {
+	Pending              int      `json:"pending" db:"pending"`
+	Running              int      `json:"running" db:"running"`
+	Paused               int      `json:"paused" db:"paused"`
+	GloballyPaused       bool     `json:"globally_paused" db:"globally_paused"`
+	OldestPendingSeconds *float64 `json:"oldest_pending_seconds" db:"oldest_pending_seconds"`
+	AvgWaitSeconds       *float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"`
+	Completed            int      `json:"completed" db:"completed"`
+	Failed               int      `json:"failed" db:"failed"`
+	FailureRate          *float64 `json:"failure_rate" db:"failure_rate"`
+	WindowSeconds        float64  `json:"window_seconds" db:"-"`
+}
+
+// WorkerStats describes one AI worker on this instance. Utilization is the
+// fraction of the worker's lifetime spent running jobs.
+type WorkerStats struct {
+	ID          int       `json:"id"`
+	Busy        bool      `json:"busy"`
+	JobsRun     int64     `json:"jobs_run"`
+	Utilization float64   `json:"utilization"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// WorkerPoolStatus is a machine-readable view of the AI job backlog and this
+// instance's worker pool, for autoscalers.
+type WorkerPoolStatus struct {
+	QueueDepth  int           `json:"queue_depth"`
+	Running     int           `json:"running"`
+	Size        int           `json:"size"`
+	Utilization float64       `json:"utilization"`
+	Workers     []WorkerStats `json:"workers"`
+}
+
+// AIJobArtifact is a file collected from a job's workspace after it ran, such
+// as a test report. The contents live in object storage under StorageKey.
+type AIJobArtifact struct {
 	ID          int64     `json:"id" db:"id"`
-	IssueID     int64     `json:"issue_id" db:"issue_id"`
-	Status      JobStatus `json:"status" db:"status"`
-	Attempts    int       `json:"attempts" db:"attempts"`
-	MaxAttempts int       `json:"max_attempts" db:"max_attempts"`
-	StartedAt   *time.Time `json:"started_at,omitempty" db:"started_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
-	ErrorMsg    *string   `json:"error_msg,omitempty" db:"error_msg"`
+	JobID       int64     `json:"job_id" db:"job_id"`
+	Name        string    `json:"name" db:"name"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	SizeBytes   int64     `json:"size_bytes" db:"size_bytes"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
+
+// AIRun is one attempt of an AI job on an issue: the prompt sent, the
+// session it ran in and what came back. An issue's runs form its AI
+// conversation history. ApprovedBy is the user who approved the result, if
+// anyone has.
+type AIRun struct {
+	ID          int64      `json:"id" db:"id"`
+	JobID       int64      `json:"job_id" db:"job_id"`
+	IssueID     int64      `json:"issue_id" db:"issue_id"`
+	Attempt     int        `json:"attempt" db:"attempt"`
+	Prompt      string     `json:"prompt" db:"prompt"`
+	SessionID   *string    `json:"session_id,omitempty" db:"session_id"`
+	Result      *string    `json:"result,omitempty" db:"result"`
+	Status      JobStatus  `json:"status" db:"status"`
+	TriggeredBy *int64     `json:"triggered_by,omitempty" db:"triggered_by"`
+	ApprovedBy  *int64     `json:"approved_by,omitempty" db:"approved_by"`
+	StartedAt   time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// AILogStream names the output stream a log chunk came from.
+type AILogStream string
+
+const (
+	AILogStdout AILogStream = "stdout"
+	AILogStderr AILogStream = "stderr"
+)
+
+// AIJobLog is a chunk of output from one run of an AI job, with secrets
+// redacted.
+type AIJobLog struct {
+	ID        int64       `json:"id" db:"id"`
+	JobID     int64       `json:"job_id" db:"job_id"`
+	RunID     int64       `json:"run_id" db:"run_id"`
+	Stream    AILogStream `json:"stream" db:"stream"`
+	Data      string      `json:"data" db:"data"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j AIJob) Done() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// ReviewSeverity ranks an AI review comment.
+type ReviewSeverity string
+
+const (
+	ReviewSeverityInfo       ReviewSeverity = "info"
+	ReviewSeveritySuggestion ReviewSeverity = "suggestion"
+	ReviewSeverityBlocking   ReviewSeverity = "blocking"
+)
+
+// Valid reports whether s is a known review severity.
+func (s ReviewSeverity) Valid() bool {
+	switch s {
+	case ReviewSeverityInfo, ReviewSeveritySuggestion, ReviewSeverityBlocking:
+		return true
+	}
+	return false
+}
+
+// AIReviewComment is one finding of an AI review job, anchored to a file
+// and, optionally, a line of the reviewed diff's new version.
+type AIReviewComment struct {
+	ID        int64          `json:"id" db:"id"`
+	JobID     int64          `json:"job_id" db:"job_id"`
+	IssueID   int64          `json:"issue_id" db:"issue_id"`
+	Path      string         `json:"path" db:"path"`
+	Line      *int           `json:"line,omitempty" db:"line"`
+	Severity  ReviewSeverity `json:"severity" db:"severity"`
+	Body      string         `json:"body" db:"body"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AIPermissionMode is a Claude Code permission mode a project may choose.
+type AIPermissionMode string
+
+const (
+	AIPermissionDefault     AIPermissionMode = "default"
+	AIPermissionAcceptEdits AIPermissionMode = "acceptEdits"
+	AIPermissionPlan        AIPermissionMode = "plan"
+)
+
+// DeniedAITools are always passed to Claude Code as disallowed. Projects
+// can only allow tools in AllowedAITools, so this is a second line of
+// defence should Claude Code's own defaults allow any of them.
+var DeniedAITools = []string{
+	"WebFetch",
+	"WebSearch",
+	"Bash(curl:*)",
+	"Bash(wget:*)",
+	"Bash(nc:*)",
+	"Bash(ncat:*)",
+	"Bash(ssh:*)",
+	"Bash(scp:*)",
+	"Bash(rsync:*)",
+	"Bash(git push:*)",
+	"Bash(rm -rf /:*)",
+	"Bash(rm -rf ~:*)",
+	"Bash(rm -rf ..:*)",
+	"Bash(sudo:*)",
+}
+
+// AllowedAITools are the tool patterns a project may allow. Anything else,
+// such as shells, interpreters or Bash rules matching any command, could
+// reach the network or delete outside the workspace, so only file tools
+// and commands that build, test or inspect the workspace are listed. Test
+// and build commands run the repository's own code; the agent is trusted
+// with that, not with arbitrary commands.
+var AllowedAITools = []string{
+	"Read",
+	"Edit",
+	"MultiEdit",
+	"Write",
+	"Glob",
+	"Grep",
+	"LS",
+	"NotebookRead",
+	"NotebookEdit",
+	"TodoWrite",
+	"Bash(go build:*)",
+	"Bash(go test:*)",
+	"Bash(go vet:*)",
+	"Bash(gofmt:*)",
+	"Bash(npm test:*)",
+	"Bash(npm run build:*)",
+	"Bash(npm run lint:*)",
+	"Bash(npm run test:*)",
+	"Bash(npm run typecheck:*)",
+	"Bash(cargo build:*)",
+	"Bash(cargo test:*)",
+	"Bash(pytest:*)",
+	"Bash(git status:*)",
+	"Bash(git diff:*)",
+	"Bash(git log:*)",
+	"Bash(git show:*)",
+	"Bash(git add:*)",
+	"Bash(git commit:*)",
+}
+
+// Validate reports the first problem with the settings' tool permissions.
+// Allowed tools must be in AllowedAITools, and only the listed permission
+// modes may be used.
+func (s AISettings) Validate() error {
+	switch s.PermissionMode {
+	case "", AIPermissionDefault, AIPermissionAcceptEdits, AIPermissionPlan:
+	default:
+		return &ValidationError{Field: "ai.permission_mode", Message: fmt.Sprintf("unsupported permission mode %q", s.PermissionMode)}
+	}
+
+	for _, tool := range s.AllowedTools {
+		if err := validateAllowedTool(strings.TrimSpace(tool)); err != nil {
+			return &ValidationError{Field: "ai.allowed_tools", Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+func validateAllowedTool(tool string) error {
+	if tool == "" || strings.ContainsAny(tool, ",\n") {
+		return fmt.Errorf("invalid tool %q", tool)
+	}
+	if !slices.Contains(AllowedAITools, tool) {
+		return fmt.Errorf("tool %q is not allowed; allowed tools are %s", tool, strings.Join(AllowedAITools, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAISettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings AISettings
+		wantErr  bool
+	}{
+		{"empty", AISettings{}, false},
+		{"file tools", AISettings{AllowedTools: []string{"Read", "Edit", "Grep"}}, false},
+		{"listed command", AISettings{AllowedTools: []string{"Bash(go test:*)"}, PermissionMode: AIPermissionAcceptEdits}, false},
+		{"unrestricted bash", AISettings{AllowedTools: []string{"Bash"}}, true},
+		{"shell", AISettings{AllowedTools: []string{"Bash(bash:*)"}}, true},
+		{"sh", AISettings{AllowedTools: []string{"Bash(sh:*)"}}, true},
+		{"interpreter", AISettings{AllowedTools: []string{"Bash(python3:*)"}}, true},
+		{"node", AISettings{AllowedTools: []string{"Bash(node:*)"}}, true},
+		{"any git", AISettings{AllowedTools: []string{"Bash(git:*)"}}, true},
+		{"rm", AISettings{AllowedTools: []string{"Bash(rm:*)"}}, true},
+		{"network", AISettings{AllowedTools: []string{"WebFetch"}}, true},
+		{"listed command with suffix", AISettings{AllowedTools: []string{"Bash(go test:*) && curl"}}, true},
+		{"comma", AISettings{AllowedTools: []string{"Read,Bash"}}, true},
+		{"unknown mode", AISettings{PermissionMode: "bypassPermissions"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+			var verr *ValidationError
+			if err != nil && !errors.As(err, &verr) {
+				t.Errorf("Validate() error = %T, want *ValidationError", err)
+			}
+		})
+	}
+}
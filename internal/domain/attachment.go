@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// AttachmentTypes are the media types accepted for issue attachments. Types
+// are sniffed from the contents rather than trusted from the client, and
+// HTML, which browsers could run, is never accepted.
+var AttachmentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"application/zip",
+	"application/x-gzip",
+}
+
+// ScanStatus is where an attachment is in malware scanning.
+type ScanStatus string
+
+const (
+	// ScanUnscanned attachments were uploaded while no scanner was
+	// configured and are served as they are.
+	ScanUnscanned ScanStatus = "unscanned"
+	// ScanPending attachments are quarantined until a scan succeeds.
+	ScanPending  ScanStatus = "pending"
+	ScanClean    ScanStatus = "clean"
+	ScanInfected ScanStatus = "infected"
+)
+
+// Attachment is a file uploaded to an issue. The contents live in object
+// storage under StorageKey, shared with every attachment whose contents
+// have the same SHA256; images may have smaller Variants for the web. Only
+// attachments that are clean or unscanned may be downloaded; the contents
+// of infected ones are deleted.
+type Attachment struct {
+	ID            int64              `json:"id" db:"id"`
+	IssueID       int64              `json:"issue_id" db:"issue_id"`
+	Name          string             `json:"name" db:"name"`
+	StorageKey    string             `json:"-" db:"storage_key"`
+	ContentType   string             `json:"content_type" db:"content_type"`
+	SizeBytes     int64              `json:"size_bytes" db:"size_bytes"`
+	SHA256        *string            `json:"sha256,omitempty" db:"blob_sha256"`
+	Variants      AttachmentVariants `json:"variants,omitempty" db:"variants"`
+	UploadedBy    *int64             `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	ScanStatus    ScanStatus         `json:"scan_status" db:"scan_status"`
+	ScanSignature *string            `json:"scan_signature,omitempty" db:"scan_signature"`
+	ScannedBy     *string            `json:"scanned_by,omitempty" db:"scanned_by"`
+	ScannedAt     *time.Time         `json:"scanned_at,omitempty" db:"scanned_at"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+}
+
+// Downloadable reports whether the attachment's contents may be served.
+func (a *Attachment) Downloadable() bool {
+	return a.ScanStatus == ScanClean || a.ScanStatus == ScanUnscanned
+}
+
+// AttachmentBlob is the stored contents of one or more attachments, keyed by
+// the hex SHA-256 of the contents, and the variants made of them.
+type AttachmentBlob struct {
+	SHA256     string             `db:"sha256"`
+	StorageKey string             `db:"storage_key"`
+	SizeBytes  int64              `db:"size_bytes"`
+	Variants   AttachmentVariants `db:"variants"`
+}
+
+// AttachmentVariant is a smaller version of an image attachment, stored
+// next to its contents.
+type AttachmentVariant struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// AttachmentVariants is a list of variants stored as a JSONB column.
+type AttachmentVariants []AttachmentVariant
+
+// Scan implements sql.Scanner for JSONB columns.
+func (v *AttachmentVariants) Scan(src any) error {
+	return scanJSON(src, v)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (v AttachmentVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(v)
+}
+
+// Find returns the variant named name, if there is one.
+func (v AttachmentVariants) Find(name string) (AttachmentVariant, bool) {
+	for _, variant := range v {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return AttachmentVariant{}, false
+}
+
+// ScanResult is the outcome of scanning an attachment.
+type ScanResult struct {
+	Status    ScanStatus
+	Signature *string
+	Scanner   string
+}
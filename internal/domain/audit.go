@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// AuditAction names an administrative action recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditIssuePinned       AuditAction = "issue.pinned"
+	AuditIssueUnpinned     AuditAction = "issue.unpinned"
+	AuditIssueDeleted      AuditAction = "issue.deleted"
+	AuditIssueRestored     AuditAction = "issue.restored"
+	AuditProjectDeleted    AuditAction = "project.deleted"
+	AuditProjectRestored   AuditAction = "project.restored"
+	AuditCommentHidden     AuditAction = "comment.hidden"
+	AuditCommentShown      AuditAction = "comment.unhidden"
+	AuditUserBlocked       AuditAction = "user.blocked"
+	AuditUserUnblocked     AuditAction = "user.unblocked"
+	AuditReportResolved    AuditAction = "report.resolved"
+	AuditAIPaused          AuditAction = "ai.paused"
+	AuditAIResumed         AuditAction = "ai.resumed"
+	AuditMemberAdded       AuditAction = "member.added"
+	AuditMemberRemoved     AuditAction = "member.removed"
+	AuditLabelRestricted   AuditAction = "label.restricted"
+	AuditLabelUnrestricted AuditAction = "label.unrestricted"
+	AuditLabelRenamed      AuditAction = "label.renamed"
+	AuditLabelMerged       AuditAction = "label.merged"
+	AuditWebhookCreated    AuditAction = "webhook.created"
+	AuditWebhookUpdated    AuditAction = "webhook.updated"
+	AuditWebhookDeleted    AuditAction = "webhook.deleted"
+	AuditFreezeScheduled   AuditAction = "freeze.scheduled"
+	AuditFreezeCancelled   AuditAction = "freeze.cancelled"
+	AuditRouteCreated      AuditAction = "route.created"
+	AuditRouteUpdated      AuditAction = "route.updated"
+	AuditRouteDeleted      AuditAction = "route.deleted"
+)
+
+// AuditTarget identifies the kind of resource an audited action applies to.
+type AuditTarget string
+
+const (
+	AuditTargetIssue   AuditTarget = "issue"
+	AuditTargetComment AuditTarget = "comment"
+	AuditTargetUser    AuditTarget = "user"
+	AuditTargetReport  AuditTarget = "report"
+	AuditTargetProject AuditTarget = "project"
+	AuditTargetLabel   AuditTarget = "label"
+	AuditTargetWebhook AuditTarget = "webhook"
+	AuditTargetFreeze  AuditTarget = "freeze"
+	AuditTargetRoute   AuditTarget = "route"
+)
+
+// AuditEntry records an administrative action taken within a project.
+type AuditEntry struct {
+	ID         int64       `json:"id" db:"id"`
+	ProjectID  int64       `json:"project_id" db:"project_id"`
+	ActorID    int64       `json:"actor_id" db:"actor_id"`
+	Action     AuditAction `json:"action" db:"action"`
+	TargetType AuditTarget `json:"target_type" db:"target_type"`
+	TargetID   int64       `json:"target_id" db:"target_id"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// CloseRequestState is the lifecycle state of a close request.
+type CloseRequestState string
+
+const (
+	CloseRequestPending  CloseRequestState = "pending"
+	CloseRequestApproved CloseRequestState = "approved"
+	CloseRequestRejected CloseRequestState = "rejected"
+)
+
+// CloseRequest asks for an issue to be moved to a done status in a project
+// whose issues may only be closed with approval. Status is the done status
+// the issue moves to once approved.
+type CloseRequest struct {
+	ID           int64             `json:"id" db:"id"`
+	IssueID      int64             `json:"issue_id" db:"issue_id"`
+	Status       IssueStatus       `json:"status" db:"status"`
+	Note         string            `json:"note" db:"note"`
+	RequestedBy  *int64            `json:"requested_by,omitempty" db:"requested_by"`
+	State        CloseRequestState `json:"state" db:"state"`
+	DecidedBy    *int64            `json:"decided_by,omitempty" db:"decided_by"`
+	DecisionNote string            `json:"decision_note" db:"decision_note"`
+	DecidedAt    *time.Time        `json:"decided_at,omitempty" db:"decided_at"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Comment represents a user comment on an issue.
+type Comment struct {
+	ID        int64      `json:"id" db:"id"`
+	IssueID   int64      `json:"issue_id" db:"issue_id"`
+	AuthorID  int64      `json:"author_id" db:"author_id"`
+	Body      string     `json:"body" db:"body"`
+	Hidden    bool       `json:"hidden" db:"-"`
+	HiddenAt  *time.Time `json:"-" db:"hidden_at"`
+	HiddenBy  *int64     `json:"-" db:"hidden_by"`
+	Tombstone *string    `json:"tombstone,omitempty" db:"-"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy *int64     `json:"-" db:"deleted_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Deleted reports whether the comment has been soft-deleted.
+func (c Comment) Deleted() bool {
+	return c.DeletedAt != nil
+}
+
+// Masked returns a copy of the comment suitable for display to viewers who
+// may not see hidden content. Deleted comments are reduced to a tombstone.
+// Visible comments are returned unchanged.
+func (c Comment) Masked() Comment {
+	masked := c
+	masked.Hidden = c.HiddenAt != nil
+	if masked.Hidden {
+		masked.Body = ""
+	}
+	if c.Deleted() {
+		masked.Body = ""
+		tombstone := "comment deleted by moderator"
+		if c.DeletedBy != nil && *c.DeletedBy == c.AuthorID {
+			tombstone = "comment deleted by author"
+		}
+		masked.Tombstone = &tombstone
+	}
+	return masked
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// PushPlatform is the push service a device receives notifications through.
+type PushPlatform string
+
+const (
+	PushPlatformFCM  PushPlatform = "fcm"
+	PushPlatformAPNs PushPlatform = "apns"
+)
+
+// Valid reports whether p is a known push platform.
+func (p PushPlatform) Valid() bool {
+	return p == PushPlatformFCM || p == PushPlatformAPNs
+}
+
+// Device is a user's device registered for push notifications.
+type Device struct {
+	ID        int64        `json:"id" db:"id"`
+	UserID    int64        `json:"user_id" db:"user_id"`
+	Platform  PushPlatform `json:"platform" db:"platform"`
+	Token     string       `json:"token" db:"token"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// PushItem is a notification queued to be pushed at DeliverAt.
+type PushItem struct {
+	NotificationID int64
+	DeliverAt      time.Time
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// DuplicationStatus represents the state of a project duplication.
+type DuplicationStatus string
+
+const (
+	DuplicationStatusRunning   DuplicationStatus = "running"
+	DuplicationStatusCompleted DuplicationStatus = "completed"
+)
+
+// IssueCopy says whether a duplicated project receives its source's open
+// issues, and when.
+type IssueCopy int
+
+const (
+	IssueCopyNone IssueCopy = iota
+	// IssueCopyInline copies the issues while creating the project.
+	IssueCopyInline
+	// IssueCopyBackground leaves the issues to a ProjectDuplication.
+	IssueCopyBackground
+)
+
+// ProjectDuplication copies the open issues of SourceProjectID into the
+// duplicate ProjectID in the background, in issue ID order. LastIssueID is
+// how far it has got, so it resumes there after a restart. Total is the
+// number of open issues when it started.
+type ProjectDuplication struct {
+	ID              int64             `json:"id" db:"id"`
+	SourceProjectID int64             `json:"source_project_id" db:"source_project_id"`
+	ProjectID       int64             `json:"project_id" db:"project_id"`
+	Status          DuplicationStatus `json:"status" db:"status"`
+	LastIssueID     int64             `json:"last_issue_id" db:"last_issue_id"`
+	Copied          int               `json:"copied" db:"copied"`
+	Total           int               `json:"total" db:"total"`
+	RequestedBy     *int64            `json:"requested_by,omitempty" db:"requested_by"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+	FinishedAt      *time.Time        `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Progress returns the fraction of the duplication done, between 0 and 1.
+func (d ProjectDuplication) Progress() float64 {
+	if d.Status == DuplicationStatusCompleted || d.Total == 0 {
+		return 1
+	}
+	return min(float64(d.Copied)/float64(d.Total), 1)
+}
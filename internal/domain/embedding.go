@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// BackfillStatus represents the state of an embedding backfill.
+type BackfillStatus string
+
+const (
+	BackfillStatusRunning   BackfillStatus = "running"
+	BackfillStatusCompleted BackfillStatus = "completed"
+	BackfillStatusCancelled BackfillStatus = "cancelled"
+)
+
+// EmbeddingBackfill computes embeddings for every existing issue, in issue ID
+// order. LastIssueID is how far it has got, so it resumes there after a
+// restart. Total is the number of issues when it started; issues created
+// since are embedded too, so Processed may exceed it. Failures counts
+// batches that had to be retried and LastError is the most recent reason.
+type EmbeddingBackfill struct {
+	ID          int64          `json:"id" db:"id"`
+	Model       string         `json:"model" db:"model"`
+	Status      BackfillStatus `json:"status" db:"status"`
+	LastIssueID int64          `json:"last_issue_id" db:"last_issue_id"`
+	Processed   int            `json:"processed" db:"processed"`
+	Total       int            `json:"total" db:"total"`
+	Failures    int            `json:"failures" db:"failures"`
+	LastError   *string        `json:"last_error,omitempty" db:"last_error"`
+	StartedBy   *int64         `json:"started_by,omitempty" db:"started_by"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Progress returns the fraction of the backfill done, between 0 and 1.
+func (b EmbeddingBackfill) Progress() float64 {
+	if b.Status == BackfillStatusCompleted || b.Total == 0 {
+		return 1
+	}
+	return min(float64(b.Processed)/float64(b.Total), 1)
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
 	ErrNotFound     = errors.New("resource not found")
@@ -8,6 +11,24 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 	ErrInvalidInput = errors.New("invalid input")
 	ErrConflict     = errors.New("resource conflict")
+
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrRestrictedLabel is returned when a non-admin applies or removes a
+	// restricted label.
+	ErrRestrictedLabel = errors.New("label is restricted to project admins")
+
+	// ErrProjectFrozen is returned, wrapped in a FrozenError, when a
+	// non-admin changes an issue's status or runs AI during a freeze window.
+	ErrProjectFrozen = errors.New("project is frozen")
+
+	// ErrApprovalRequired is returned when a user closes an issue in a
+	// project that requires a close request to be approved first.
+	ErrApprovalRequired = errors.New("closing requires approval")
+
+	// ErrAttachmentInfected is returned when an uploaded file, or one being
+	// downloaded, was found to contain malware.
+	ErrAttachmentInfected = errors.New("attachment is infected")
 )
 
 // ValidationError represents a field-level validation failure.
@@ -19,3 +40,14 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return e.Field + ": " + e.Message
 }
+
+// ValidationErrors collects several field-level validation failures.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ve := range e {
+		msgs[i] = ve.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// EventType names something that happened to an issue.
+type EventType string
+
+const (
+	EventIssueCreated   EventType = "issue.created"
+	EventIssueUpdated   EventType = "issue.updated"
+	EventStatusChanged  EventType = "issue.status_changed"
+	EventIssueAssigned  EventType = "issue.assigned"
+	EventLabelAdded     EventType = "label.added"
+	EventLabelRemoved   EventType = "label.removed"
+	EventCommentCreated EventType = "comment.created"
+	EventAIRun          EventType = "ai.run"
+	EventAIJobCompleted EventType = "ai.completed"
+	EventAIJobFailed    EventType = "ai.failed"
+	EventAIJobCancelled EventType = "ai.cancelled"
+)
+
+// EventData holds event-specific details.
+type EventData map[string]any
+
+// Scan implements sql.Scanner for JSONB columns.
+func (d *EventData) Scan(src any) error {
+	return scanJSON(src, d)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (d EventData) Value() (driver.Value, error) {
+	if d == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(d)
+}
+
+// IssueEvent records an action taken on an issue, used for activity feeds
+// and timelines.
+type IssueEvent struct {
+	ID        int64     `json:"id" db:"id"`
+	ProjectID int64     `json:"project_id" db:"project_id"`
+	IssueID   int64     `json:"issue_id" db:"issue_id"`
+	ActorID   *int64    `json:"actor_id,omitempty" db:"actor_id"`
+	Type      EventType `json:"type" db:"type"`
+	Data      EventData `json:"data" db:"data"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Xact      int64     `json:"-" db:"xact_id"`
+}
+
+// Position returns the event's place in the event log.
+func (e IssueEvent) Position() LogPosition {
+	return LogPosition{Xact: e.Xact, ID: e.ID}
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// FormFieldType is the kind of input a form field asks for.
+type FormFieldType string
+
+const (
+	FormFieldInput      FormFieldType = "input"
+	FormFieldTextarea   FormFieldType = "textarea"
+	FormFieldDropdown   FormFieldType = "dropdown"
+	FormFieldCheckboxes FormFieldType = "checkboxes"
+)
+
+const (
+	maxFormFields     = 30
+	maxFormOptions    = 50
+	maxInputAnswer    = 1000
+	maxTextareaAnswer = 65536
+)
+
+// FormField is one question of an issue form. Input and textarea fields
+// are answered with a string, dropdowns with one of Options, and checkboxes
+// with any number of Options. A required checkboxes field needs at least
+// one ticked.
+type FormField struct {
+	ID          string        `json:"id"`
+	Label       string        `json:"label"`
+	Type        FormFieldType `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Options     []string      `json:"options,omitempty"`
+}
+
+// FormData holds the answers to an issue form by field ID: a string for
+// input, textarea and dropdown fields and a []string for checkboxes.
+type FormData map[string]any
+
+// Scan implements sql.Scanner for JSONB columns.
+func (d *FormData) Scan(src any) error {
+	return scanJSON(src, d)
+}
+
+// Value implements driver.Valuer for JSONB columns. Empty form data is
+// stored as NULL.
+func (d FormData) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+// Validate checks the template's form definition.
+func (t IssueTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return &ValidationError{Field: "issue_templates.name", Message: "is required"}
+	}
+	if len(t.Fields) > maxFormFields {
+		return &ValidationError{Field: "issue_templates.fields", Message: fmt.Sprintf("at most %d fields per form", maxFormFields)}
+	}
+	seen := make(map[string]bool)
+	for _, f := range t.Fields {
+		field := "issue_templates.fields." + f.ID
+		switch {
+		case f.ID == "":
+			return &ValidationError{Field: "issue_templates.fields.id", Message: "is required"}
+		case seen[f.ID]:
+			return &ValidationError{Field: field, Message: "duplicate field id"}
+		case strings.TrimSpace(f.Label) == "":
+			return &ValidationError{Field: field, Message: "label is required"}
+		}
+		seen[f.ID] = true
+
+		switch f.Type {
+		case FormFieldInput, FormFieldTextarea:
+			if len(f.Options) > 0 {
+				return &ValidationError{Field: field, Message: fmt.Sprintf("%s fields take no options", f.Type)}
+			}
+		case FormFieldDropdown, FormFieldCheckboxes:
+			if len(f.Options) == 0 || len(f.Options) > maxFormOptions {
+				return &ValidationError{Field: field, Message: fmt.Sprintf("needs 1 to %d options", maxFormOptions)}
+			}
+		default:
+			return &ValidationError{Field: field, Message: fmt.Sprintf("unknown field type %q", f.Type)}
+		}
+	}
+	return nil
+}
+
+// Answer validates answers to the template's form and returns them
+// normalized, with strings trimmed and unanswered fields left out. Every
+// invalid answer is reported, as a field error named form.<field id>.
+func (t IssueTemplate) Answer(answers FormData) (FormData, error) {
+	var errs ValidationErrors
+	fail := func(id, msg string) {
+		errs = append(errs, &ValidationError{Field: "form." + id, Message: msg})
+	}
+	for _, id := range slices.Sorted(maps.Keys(answers)) {
+		if !slices.ContainsFunc(t.Fields, func(f FormField) bool { return f.ID == id }) {
+			fail(id, "unknown field")
+		}
+	}
+
+	data := make(FormData)
+	for _, f := range t.Fields {
+		raw, given := answers[f.ID]
+		if !given || raw == nil {
+			if f.Required {
+				fail(f.ID, "is required")
+			}
+			continue
+		}
+
+		switch f.Type {
+		case FormFieldInput, FormFieldTextarea, FormFieldDropdown:
+			s, ok := raw.(string)
+			if !ok {
+				fail(f.ID, "must be a string")
+				continue
+			}
+			s = strings.TrimSpace(s)
+			limit := maxInputAnswer
+			if f.Type == FormFieldTextarea {
+				limit = maxTextareaAnswer
+			}
+			switch {
+			case s == "" && f.Required:
+				fail(f.ID, "is required")
+			case len(s) > limit:
+				fail(f.ID, fmt.Sprintf("must be at most %d characters", limit))
+			case s != "" && f.Type == FormFieldDropdown && !slices.Contains(f.Options, s):
+				fail(f.ID, fmt.Sprintf("must be one of %s", strings.Join(f.Options, ", ")))
+			case s != "":
+				data[f.ID] = s
+			}
+		case FormFieldCheckboxes:
+			list, ok := raw.([]any)
+			if !ok {
+				fail(f.ID, "must be a list of options")
+				continue
+			}
+			var ticked []string
+			for _, v := range list {
+				s, ok := v.(string)
+				if !ok || !slices.Contains(f.Options, s) {
+					fail(f.ID, fmt.Sprintf("options must be among %s", strings.Join(f.Options, ", ")))
+					ticked = nil
+					break
+				}
+				if !slices.Contains(ticked, s) {
+					ticked = append(ticked, s)
+				}
+			}
+			if len(ticked) == 0 {
+				if f.Required && len(list) == 0 {
+					fail(f.ID, "at least one option is required")
+				}
+				continue
+			}
+			data[f.ID] = ticked
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return data, nil
+}
+
+// Render writes form answers as the Markdown body of an issue, a heading
+// per field in the form's order.
+func (t IssueTemplate) Render(data FormData) string {
+	var b strings.Builder
+	for i, f := range t.Fields {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", f.Label)
+		if f.Type == FormFieldCheckboxes {
+			ticked, _ := data[f.ID].([]string)
+			for _, opt := range f.Options {
+				mark := " "
+				if slices.Contains(ticked, opt) {
+					mark = "x"
+				}
+				fmt.Fprintf(&b, "- [%s] %s\n", mark, opt)
+			}
+			continue
+		}
+		if s, ok := data[f.ID].(string); ok {
+			b.WriteString(s)
+			b.WriteString("\n")
+		} else {
+			b.WriteString("_No response_\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// FreezeWindow is a scheduled period, such as a release week, during which
+// only project admins may change issue statuses or run AI.
+type FreezeWindow struct {
+	ID        int64     `json:"id" db:"id"`
+	ProjectID int64     `json:"project_id" db:"project_id"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedBy *int64    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// FrozenError reports the freeze window that blocked an action.
+type FrozenError struct {
+	Window FreezeWindow
+}
+
+func (e *FrozenError) Error() string {
+	msg := "the project is frozen until " + e.Window.EndsAt.UTC().Format(time.RFC3339)
+	if e.Window.Reason != "" {
+		msg += " (" + e.Window.Reason + ")"
+	}
+	return msg + "; only project admins may change issue statuses or run AI"
+}
+
+func (e *FrozenError) Unwrap() error {
+	return ErrProjectFrozen
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // IssueStatus represents the lifecycle state of an issue.
 type IssueStatus string
@@ -12,17 +15,67 @@ const (
 	IssueStatusClosed     IssueStatus = "closed"
 )
 
-// Issue represents a task within a project.
+// Valid reports whether s is a known issue status.
+func (s IssueStatus) Valid() bool {
+	switch s {
+	case IssueStatusOpen, IssueStatusInProgress, IssueStatusCompleted, IssueStatusClosed:
+		return true
+	}
+	return false
+}
+
+// Done reports whether s ends an issue's lifecycle.
+func (s IssueStatus) Done() bool {
+	return s == IssueStatusCompleted || s == IssueStatusClosed
+}
+
+// issueTransitions lists the statuses each status may move to. Work moves
+// forward from open through in progress to completed; an issue can be
+// closed without being done, and done issues can only be reopened.
+var issueTransitions = map[IssueStatus][]IssueStatus{
+	IssueStatusOpen:       {IssueStatusInProgress, IssueStatusClosed},
+	IssueStatusInProgress: {IssueStatusOpen, IssueStatusCompleted, IssueStatusClosed},
+	IssueStatusCompleted:  {IssueStatusOpen},
+	IssueStatusClosed:     {IssueStatusOpen},
+}
+
+// Transitions returns the statuses an issue in s may move to.
+func (s IssueStatus) Transitions() []IssueStatus {
+	return issueTransitions[s]
+}
+
+// CanTransitionTo reports whether an issue in s may move to status to.
+// Staying in the same status is always allowed.
+func (s IssueStatus) CanTransitionTo(to IssueStatus) bool {
+	return s == to || slices.Contains(issueTransitions[s], to)
+}
+
+// Issue represents a task within a project. Number identifies it within
+// the project and is shown after the project key, as in KEY-123.
 type Issue struct {
-	ID           int64       `json:"id" db:"id"`
-	ProjectID    int64       `json:"project_id" db:"project_id"`
-	Title        string      `json:"title" db:"title"`
-	Body         *string     `json:"body,omitempty" db:"body"`
-	Status       IssueStatus `json:"status" db:"status"`
-	AISessionID  *string     `json:"ai_session_id,omitempty" db:"ai_session_id"`
-	AIResult     *string     `json:"ai_result,omitempty" db:"ai_result"`
-	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+	ID          int64       `json:"id" db:"id"`
+	ProjectID   int64       `json:"project_id" db:"project_id"`
+	Number      int64       `json:"number" db:"number"`
+	Title       string      `json:"title" db:"title"`
+	Body        *string     `json:"body,omitempty" db:"body"`
+	Status      IssueStatus `json:"status" db:"status"`
+	CreatedBy   *int64      `json:"created_by,omitempty" db:"created_by"`
+	AssigneeID  *int64      `json:"assignee_id,omitempty" db:"assignee_id"`
+	MilestoneID *int64      `json:"milestone_id,omitempty" db:"milestone_id"`
+	AISessionID *string     `json:"ai_session_id,omitempty" db:"ai_session_id"`
+	AIResult    *string     `json:"ai_result,omitempty" db:"ai_result"`
+	PinnedAt    *time.Time  `json:"pinned_at,omitempty" db:"pinned_at"`
+	ClosedBy    *int64      `json:"closed_by,omitempty" db:"closed_by"`
+	ClosedAt    *time.Time  `json:"closed_at,omitempty" db:"closed_at"`
+	ArchivedAt  *time.Time  `json:"archived_at,omitempty" db:"archived_at"`
+	// Template names the form the issue was created from, and Form holds
+	// its answers.
+	Template  *string    `json:"template,omitempty" db:"template"`
+	Form      FormData   `json:"form,omitempty" db:"form_data"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy *int64     `json:"deleted_by,omitempty" db:"deleted_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // WithStatus returns a new Issue with the given status.
@@ -33,9 +86,65 @@ func (i Issue) WithStatus(status IssueStatus) Issue {
 		Title:       i.Title,
 		Body:        i.Body,
 		Status:      status,
+		CreatedBy:   i.CreatedBy,
+		AssigneeID:  i.AssigneeID,
+		MilestoneID: i.MilestoneID,
 		AISessionID: i.AISessionID,
 		AIResult:    i.AIResult,
+		PinnedAt:    i.PinnedAt,
+		ClosedBy:    i.ClosedBy,
+		ClosedAt:    i.ClosedAt,
+		ArchivedAt:  i.ArchivedAt,
+		Template:    i.Template,
+		Form:        i.Form,
 		CreatedAt:   i.CreatedAt,
 		UpdatedAt:   time.Now(),
 	}
 }
+
+// LabelMode says how the labels of an issue filter combine.
+type LabelMode string
+
+const (
+	// LabelModeAll matches issues that have every label.
+	LabelModeAll LabelMode = "all"
+	// LabelModeAny matches issues that have at least one of the labels.
+	LabelModeAny LabelMode = "any"
+)
+
+// Valid reports whether m is a known label mode.
+func (m LabelMode) Valid() bool {
+	return m == LabelModeAll || m == LabelModeAny
+}
+
+// IssueFilter narrows an issue listing. Zero-valued fields are not applied,
+// except Archived: listings contain either archived or unarchived issues.
+// Labels are label names, all of which an issue must have, or one of which
+// when LabelMode is LabelModeAny; Text are words or phrases, all of which
+// its title or body must contain.
+type IssueFilter struct {
+	Statuses      []IssueStatus
+	Labels        []string
+	LabelMode     LabelMode
+	Text          []string
+	AssigneeID    *int64
+	MilestoneID   *int64
+	CreatedBy     *int64
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	HasAIResult   bool
+	Pinned        *bool
+	Archived      bool
+	Cursor        ListPosition
+	Limit         int
+}
+
+// IssuePatch describes a partial update to an issue. Nil fields are left unchanged.
+type IssuePatch struct {
+	Title      *string
+	Body       *string
+	Status     *IssueStatus
+	AssigneeID *int64
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Label is a project-scoped tag that can be applied to issues. Restricted
+// labels can only be applied or removed by project admins.
+type Label struct {
+	ID         int64     `json:"id" db:"id"`
+	ProjectID  int64     `json:"project_id" db:"project_id"`
+	Name       string    `json:"name" db:"name"`
+	Color      string    `json:"color" db:"color"`
+	Restricted bool      `json:"restricted" db:"restricted"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// LabelPatch describes a partial update to a label. Nil fields are left unchanged.
+type LabelPatch struct {
+	Name  *string
+	Color *string
+}
+
+// LabelMerge is the result of merging one label into another: the label
+// kept and how many issues gained it.
+type LabelMerge struct {
+	Label       Label `json:"label"`
+	IssuesMoved int64 `json:"issues_moved"`
+}
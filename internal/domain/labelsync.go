@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// LabelSyncStatus represents the state of a label sync.
+type LabelSyncStatus string
+
+const (
+	LabelSyncStatusRunning   LabelSyncStatus = "running"
+	LabelSyncStatusCompleted LabelSyncStatus = "completed"
+)
+
+// LabelSet is a list of labels stored as a JSONB column.
+type LabelSet []LabelSpec
+
+// Scan implements sql.Scanner for JSONB columns.
+func (s *LabelSet) Scan(src any) error {
+	return scanJSON(src, s)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (s LabelSet) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+// LabelSync pushes an organization's canonical labels, taken from its
+// defaults when the sync starts, to a set of its projects in the
+// background. Labels missing from a project are created and those of a
+// different color recolored; with Prune set, labels not in the set are
+// deleted. Total is the number of projects targeted and Synced how many are
+// done. Report is filled in when a single sync is requested.
+type LabelSync struct {
+	ID             int64             `json:"id" db:"id"`
+	OrganizationID int64             `json:"organization_id" db:"organization_id"`
+	Labels         LabelSet          `json:"labels" db:"labels"`
+	Prune          bool              `json:"prune" db:"prune"`
+	Status         LabelSyncStatus   `json:"status" db:"status"`
+	Total          int               `json:"total" db:"total"`
+	Synced         int               `json:"synced" db:"synced"`
+	RequestedBy    *int64            `json:"requested_by,omitempty" db:"requested_by"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+	FinishedAt     *time.Time        `json:"finished_at,omitempty" db:"finished_at"`
+	Report         []LabelSyncResult `json:"report,omitempty" db:"-"`
+}
+
+// LabelSyncResult is what a label sync did to one project. Changes is nil
+// until the project is synced.
+type LabelSyncResult struct {
+	ProjectID int64             `json:"project_id" db:"project_id"`
+	Changes   *LabelSyncChanges `json:"changes" db:"changes"`
+	SyncedAt  *time.Time        `json:"synced_at" db:"synced_at"`
+}
+
+// LabelSyncChanges lists the names of the labels a sync created, recolored
+// and pruned in a project. Skipped is set when the project had left the
+// organization before its turn came and was left alone.
+type LabelSyncChanges struct {
+	Created   []string `json:"created"`
+	Recolored []string `json:"recolored"`
+	Pruned    []string `json:"pruned"`
+	Skipped   bool     `json:"skipped,omitempty"`
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (c *LabelSyncChanges) Scan(src any) error {
+	return scanJSON(src, c)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (c LabelSyncChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
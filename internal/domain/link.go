@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// IssueLinkType is the kind of relationship an issue link records.
+type IssueLinkType string
+
+const (
+	// IssueLinkBlocks means the source must be done before the target.
+	IssueLinkBlocks IssueLinkType = "blocks"
+	// IssueLinkRelates means the issues are related, in no direction.
+	IssueLinkRelates IssueLinkType = "relates"
+	// IssueLinkParent means the source is the parent of the target.
+	IssueLinkParent IssueLinkType = "parent"
+)
+
+// Valid reports whether t is a known link type.
+func (t IssueLinkType) Valid() bool {
+	return t == IssueLinkBlocks || t == IssueLinkRelates || t == IssueLinkParent
+}
+
+// Directed reports whether links of type t have a direction, and so must
+// not form cycles.
+func (t IssueLinkType) Directed() bool {
+	return t != IssueLinkRelates
+}
+
+// IssueLink is a typed relationship between two issues of a project.
+type IssueLink struct {
+	ID            int64         `json:"id" db:"id"`
+	SourceIssueID int64         `json:"source_issue_id" db:"source_issue_id"`
+	TargetIssueID int64         `json:"target_issue_id" db:"target_issue_id"`
+	Type          IssueLinkType `json:"type" db:"type"`
+	CreatedBy     *int64        `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+}
+
+// GraphNode is an issue in an issue graph.
+type GraphNode struct {
+	ID         int64       `json:"id" db:"id"`
+	Number     int64       `json:"number" db:"number"`
+	Title      string      `json:"title" db:"title"`
+	Status     IssueStatus `json:"status" db:"status"`
+	AssigneeID *int64      `json:"assignee_id,omitempty" db:"assignee_id"`
+}
+
+// GraphEdge is a link in an issue graph, from Source to Target.
+type GraphEdge struct {
+	ID     int64         `json:"id"`
+	Source int64         `json:"source"`
+	Target int64         `json:"target"`
+	Type   IssueLinkType `json:"type"`
+}
+
+// IssueGraph is a set of issues and the links between them. Truncated is
+// set when issues were left out to keep the graph within its size limit.
+type IssueGraph struct {
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+	Truncated bool        `json:"truncated"`
+}
+
+// GraphQuery selects the part of a project's issue graph to return. With a
+// RootID, the graph holds the issues within Depth links of the root, in
+// either direction; without one, every linked issue. Statuses, if set,
+// leave out other issues, except the root, and the links through them.
+type GraphQuery struct {
+	RootID   *int64
+	Depth    int
+	Statuses []IssueStatus
+	MaxNodes int
+}
+
+// BuildIssueGraph selects the nodes and links matching q from a project's
+// linked issues.
+func BuildIssueGraph(nodes []GraphNode, links []IssueLink, q GraphQuery) IssueGraph {
+	byID := make(map[int64]GraphNode, len(nodes))
+	for _, n := range nodes {
+		if (q.RootID != nil && n.ID == *q.RootID) || len(q.Statuses) == 0 || slices.Contains(q.Statuses, n.Status) {
+			byID[n.ID] = n
+		}
+	}
+	neighbours := make(map[int64][]int64)
+	for _, l := range links {
+		_, okSource := byID[l.SourceIssueID]
+		_, okTarget := byID[l.TargetIssueID]
+		if okSource && okTarget {
+			neighbours[l.SourceIssueID] = append(neighbours[l.SourceIssueID], l.TargetIssueID)
+			neighbours[l.TargetIssueID] = append(neighbours[l.TargetIssueID], l.SourceIssueID)
+		}
+	}
+
+	graph := IssueGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	selected := make(map[int64]bool)
+	add := func(id int64) bool {
+		if len(graph.Nodes) >= q.MaxNodes {
+			graph.Truncated = true
+			return false
+		}
+		selected[id] = true
+		graph.Nodes = append(graph.Nodes, byID[id])
+		return true
+	}
+
+	if q.RootID != nil {
+		if _, ok := byID[*q.RootID]; !ok {
+			return graph
+		}
+		add(*q.RootID)
+		frontier := []int64{*q.RootID}
+		for depth := 0; depth < q.Depth && len(frontier) > 0 && !graph.Truncated; depth++ {
+			var next []int64
+			for _, id := range frontier {
+				for _, n := range neighbours[id] {
+					if !selected[n] {
+						if !add(n) {
+							break
+						}
+						next = append(next, n)
+					}
+				}
+			}
+			frontier = next
+		}
+	} else {
+		for _, n := range nodes {
+			if _, ok := byID[n.ID]; ok && len(neighbours[n.ID]) > 0 && !selected[n.ID] {
+				if !add(n.ID) {
+					break
+				}
+			}
+		}
+	}
+
+	for _, l := range links {
+		if selected[l.SourceIssueID] && selected[l.TargetIssueID] {
+			graph.Edges = append(graph.Edges, GraphEdge{ID: l.ID, Source: l.SourceIssueID, Target: l.TargetIssueID, Type: l.Type})
+		}
+	}
+	return graph
+}
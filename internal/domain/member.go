@@ -0,0 +1,29 @@
+package domain
+
+// MemberStatus is the outcome of adding or removing one project member.
+type MemberStatus string
+
+const (
+	MemberStatusAdded         MemberStatus = "added"
+	MemberStatusAlreadyMember MemberStatus = "already_member"
+	MemberStatusBlocked       MemberStatus = "blocked"
+	MemberStatusRemoved       MemberStatus = "removed"
+	MemberStatusNotMember     MemberStatus = "not_member"
+	MemberStatusOwner         MemberStatus = "owner"
+	MemberStatusUnknownUser   MemberStatus = "unknown_user"
+)
+
+// ProjectMember is a user's membership of a project.
+type ProjectMember struct {
+	UserID int64       `json:"user_id" db:"user_id"`
+	Role   ProjectRole `json:"role" db:"role"`
+}
+
+// MemberResult reports what happened to one entry of a bulk member change.
+// Email is set for entries given by email; an email shared by several
+// accounts yields a result for each.
+type MemberResult struct {
+	Email  string       `json:"email,omitempty"`
+	UserID *int64       `json:"user_id,omitempty"`
+	Status MemberStatus `json:"status" db:"status"`
+}
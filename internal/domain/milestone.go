@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// MilestoneState is whether a milestone is still being worked towards.
+type MilestoneState string
+
+const (
+	MilestoneOpen   MilestoneState = "open"
+	MilestoneClosed MilestoneState = "closed"
+)
+
+// Valid reports whether s is a known milestone state.
+func (s MilestoneState) Valid() bool {
+	return s == MilestoneOpen || s == MilestoneClosed
+}
+
+// Milestone groups a project's issues towards a target. DueDate is a
+// calendar date, stored at midnight UTC.
+type Milestone struct {
+	ID          int64          `json:"id" db:"id"`
+	ProjectID   int64          `json:"project_id" db:"project_id"`
+	Title       string         `json:"title" db:"title"`
+	Description string         `json:"description" db:"description"`
+	DueDate     *time.Time     `json:"due_date,omitempty" db:"due_date"`
+	State       MilestoneState `json:"state" db:"state"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// MilestonePatch describes a partial update to a milestone. Nil fields are
+// left unchanged; ClearDueDate removes the due date.
+type MilestonePatch struct {
+	Title        *string
+	Description  *string
+	DueDate      *time.Time
+	ClearDueDate bool
+	State        *MilestoneState
+}
+
+// MilestoneProgress counts the issues in a milestone by whether they are
+// done.
+type MilestoneProgress struct {
+	MilestoneID int64 `json:"milestone_id" db:"milestone_id"`
+	Open        int64 `json:"open" db:"open"`
+	Closed      int64 `json:"closed" db:"closed"`
+}
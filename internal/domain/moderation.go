@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ProjectBlock bars a user from participating in a project.
+type ProjectBlock struct {
+	ProjectID int64     `json:"project_id" db:"project_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	BlockedBy int64     `json:"blocked_by" db:"blocked_by"`
+	Reason    *string   `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReportStatus represents the review state of a content report.
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// ContentReport is a user report of content awaiting moderator review.
+type ContentReport struct {
+	ID         int64        `json:"id" db:"id"`
+	ProjectID  int64        `json:"project_id" db:"project_id"`
+	ReporterID int64        `json:"reporter_id" db:"reporter_id"`
+	TargetType AuditTarget  `json:"target_type" db:"target_type"`
+	TargetID   int64        `json:"target_id" db:"target_id"`
+	Reason     string       `json:"reason" db:"reason"`
+	Status     ReportStatus `json:"status" db:"status"`
+	ResolvedBy *int64       `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+}
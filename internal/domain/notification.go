@@ -6,20 +6,47 @@ import "time"
 type NotificationType string
 
 const (
-	NotificationIssueCreated   NotificationType = "issue_created"
-	NotificationIssueCompleted NotificationType = "issue_completed"
-	NotificationIssueFailed    NotificationType = "issue_failed"
-	NotificationAIStarted      NotificationType = "ai_started"
+	NotificationIssueCreated    NotificationType = "issue_created"
+	NotificationIssueCompleted  NotificationType = "issue_completed"
+	NotificationIssueFailed     NotificationType = "issue_failed"
+	NotificationAIStarted       NotificationType = "ai_started"
+	NotificationCloseRequested  NotificationType = "close_requested"
+	NotificationCloseApproved   NotificationType = "close_approved"
+	NotificationCloseRejected   NotificationType = "close_rejected"
+	NotificationWebhookDisabled NotificationType = "webhook_disabled"
 )
 
-// Notification represents an in-app notification for a user.
+// Notification represents an in-app notification for a user. A snoozed
+// notification is hidden until SnoozedUntil.
 type Notification struct {
-	ID        int64            `json:"id" db:"id"`
-	UserID    int64            `json:"user_id" db:"user_id"`
-	IssueID   *int64           `json:"issue_id,omitempty" db:"issue_id"`
-	Type      NotificationType `json:"type" db:"type"`
-	Title     string           `json:"title" db:"title"`
-	Message   string           `json:"message" db:"message"`
-	Read      bool             `json:"read" db:"read"`
-	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	ID           int64            `json:"id" db:"id"`
+	UserID       int64            `json:"user_id" db:"user_id"`
+	IssueID      *int64           `json:"issue_id,omitempty" db:"issue_id"`
+	Type         NotificationType `json:"type" db:"type"`
+	Title        string           `json:"title" db:"title"`
+	Message      string           `json:"message" db:"message"`
+	Read         bool             `json:"read" db:"read"`
+	SnoozedUntil *time.Time       `json:"snoozed_until,omitempty" db:"snoozed_until"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	Xact         int64            `json:"-" db:"xact_id"`
+}
+
+// Position returns the notification's place in commit order.
+func (n Notification) Position() LogPosition {
+	return LogPosition{Xact: n.Xact, ID: n.ID}
+}
+
+// EmailItem is a notification queued to be emailed at DeliverAt.
+type EmailItem struct {
+	NotificationID int64
+	DeliverAt      time.Time
+}
+
+// NotificationFilter narrows a notification listing. Snoozed lists only
+// snoozed notifications; otherwise they are left out.
+type NotificationFilter struct {
+	UnreadOnly bool
+	Snoozed    bool
+	Cursor     ListPosition
+	Limit      int
 }
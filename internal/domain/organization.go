@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// OrganizationRole represents a user's role within an organization.
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleAdmin  OrganizationRole = "admin"
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+// CanAdmin reports whether the role grants organization administration rights.
+func (r OrganizationRole) CanAdmin() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// OrganizationDefaults are the labels and settings new projects in an
+// organization start from.
+type OrganizationDefaults struct {
+	Labels   []LabelSpec     `json:"labels,omitempty"`
+	Settings ProjectSettings `json:"settings"`
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (d *OrganizationDefaults) Scan(src any) error {
+	return scanJSON(src, d)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (d OrganizationDefaults) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Organization groups projects that share defaults.
+type Organization struct {
+	ID        int64                `json:"id" db:"id"`
+	Name      string               `json:"name" db:"name"`
+	OwnerID   int64                `json:"owner_id" db:"owner_id"`
+	Defaults  OrganizationDefaults `json:"defaults" db:"defaults"`
+	CreatedAt time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// MergeLabels returns base followed by the labels in overrides, with an
+// override replacing a base label of the same name.
+func MergeLabels(base, overrides []LabelSpec) []LabelSpec {
+	merged := make([]LabelSpec, 0, len(base)+len(overrides))
+	index := make(map[string]int)
+	for _, l := range append(append([]LabelSpec{}, base...), overrides...) {
+		if i, ok := index[l.Name]; ok {
+			merged[i] = l
+			continue
+		}
+		index[l.Name] = len(merged)
+		merged = append(merged, l)
+	}
+	return merged
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// ListPosition places an item in a list ordered by a time, usually its
+// creation time, then by ID. A page of such a list starts after a
+// position; the zero position is the start of the list.
+type ListPosition struct {
+	At time.Time
+	ID int64
+}
+
+// IsZero reports whether p is the start of the list.
+func (p ListPosition) IsZero() bool {
+	return p.ID == 0
+}
+
+// LogPosition places a row in a table read in commit order, such as the
+// issue event log: by the transaction that inserted the row, then by its
+// ID. IDs are handed out before their transaction commits, so a reader
+// following IDs alone could pass a row that commits late.
+type LogPosition struct {
+	Xact int64
+	ID   int64
+}
+
+// IsZero reports whether p has never been set.
+func (p LogPosition) IsZero() bool {
+	return p.ID == 0
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Precondition carries the conditions a client attached to a write request.
+// A zero Precondition imposes no conditions.
+type Precondition struct {
+	// UnmodifiedSince requires the resource not to have been updated after
+	// this time. Comparison happens at one-second resolution, matching HTTP dates.
+	UnmodifiedSince *time.Time
+}
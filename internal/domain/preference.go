@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ClockTime is a time of day in minutes since midnight. It is written as
+// "HH:MM" in JSON.
+type ClockTime int
+
+// Valid reports whether t is within a day.
+func (t ClockTime) Valid() bool {
+	return t >= 0 && t < 24*60
+}
+
+func (t ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t ClockTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *ClockTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse("15:04", s)
+	if err != nil {
+		return fmt.Errorf("clock time %q must be HH:MM", s)
+	}
+	*t = ClockTime(parsed.Hour()*60 + parsed.Minute())
+	return nil
+}
+
+// QuietHours is a daily window during which a user is not sent email or push
+// notifications. A window whose Start is after its End spans midnight.
+type QuietHours struct {
+	Start ClockTime `json:"start"`
+	End   ClockTime `json:"end"`
+}
+
+// contains reports whether the time of day m falls inside the window.
+func (q QuietHours) contains(m ClockTime) bool {
+	if q.Start <= q.End {
+		return m >= q.Start && m < q.End
+	}
+	return m >= q.Start || m < q.End
+}
+
+// NotificationPreferences control when a user's notifications are delivered
+// outside the app. In-app notifications are never held back.
+type NotificationPreferences struct {
+	UserID            int64            `json:"user_id"`
+	Timezone          string           `json:"timezone"`
+	QuietHours        *QuietHours      `json:"quiet_hours,omitempty"`
+	DoNotDisturbUntil *time.Time       `json:"do_not_disturb_until,omitempty"`
+	Email             EmailPreferences `json:"email"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// EmailPreferences choose which notifications are also sent by email.
+type EmailPreferences struct {
+	IssueCompleted bool `json:"issue_completed"`
+	IssueFailed    bool `json:"issue_failed"`
+}
+
+// Wants reports whether notifications of type t are to be emailed.
+func (e EmailPreferences) Wants(t NotificationType) bool {
+	switch t {
+	case NotificationIssueCompleted:
+		return e.IssueCompleted
+	case NotificationIssueFailed:
+		return e.IssueFailed
+	default:
+		return false
+	}
+}
+
+// DefaultNotificationPreferences returns the preferences of a user who has
+// not set any.
+func DefaultNotificationPreferences(userID int64) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, Timezone: "UTC"}
+}
+
+// DeliverAt returns the earliest time from now at which an email or push
+// notification may be sent: the end of do-not-disturb, and then the end of
+// any quiet hours that time falls in.
+func (p NotificationPreferences) DeliverAt(now time.Time) time.Time {
+	at := now
+	if p.DoNotDisturbUntil != nil && p.DoNotDisturbUntil.After(at) {
+		at = *p.DoNotDisturbUntil
+	}
+	if p.QuietHours == nil || p.QuietHours.Start == p.QuietHours.End {
+		return at
+	}
+
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := at.In(loc)
+	m := ClockTime(local.Hour()*60 + local.Minute())
+	if !p.QuietHours.contains(m) {
+		return at
+	}
+
+	day := local
+	if p.QuietHours.Start > p.QuietHours.End && m >= p.QuietHours.Start {
+		day = local.AddDate(0, 0, 1)
+	}
+	end := p.QuietHours.End
+	return time.Date(day.Year(), day.Month(), day.Day(), int(end)/60, int(end)%60, 0, 0, loc)
+}
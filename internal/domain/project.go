@@ -2,12 +2,76 @@ package domain
 
 import "time"
 
-// Project represents a project that contains issues.
+// ProjectRole represents a user's role within a project.
+type ProjectRole string
+
+const (
+	ProjectRoleOwner  ProjectRole = "owner"
+	ProjectRoleAdmin  ProjectRole = "admin"
+	ProjectRoleMember ProjectRole = "member"
+)
+
+// CanAdmin reports whether the role grants project administration rights.
+func (r ProjectRole) CanAdmin() bool {
+	return r == ProjectRoleOwner || r == ProjectRoleAdmin
+}
+
+// Satisfies reports whether the role is at least as privileged as required.
+func (r ProjectRole) Satisfies(required ProjectRole) bool {
+	switch required {
+	case ProjectRoleOwner:
+		return r == ProjectRoleOwner
+	case ProjectRoleAdmin:
+		return r.CanAdmin()
+	default:
+		return r != ""
+	}
+}
+
+// Project represents a project that contains issues. Key, if set, prefixes
+// references to the project's issues by number, as in KEY-123.
+// NextIssueNumber is the number the next issue created will get.
 type Project struct {
-	ID          int64   `json:"id" db:"id"`
-	Name        string  `json:"name" db:"name"`
-	Description *string `json:"description,omitempty" db:"description"`
-	OwnerID     int64   `json:"owner_id" db:"owner_id"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID              int64           `json:"id" db:"id"`
+	Name            string          `json:"name" db:"name"`
+	Key             *string         `json:"key,omitempty" db:"key"`
+	Description     *string         `json:"description,omitempty" db:"description"`
+	OwnerID         int64           `json:"owner_id" db:"owner_id"`
+	OrganizationID  *int64          `json:"organization_id,omitempty" db:"organization_id"`
+	Settings        ProjectSettings `json:"settings" db:"settings"`
+	NextIssueNumber int64           `json:"next_issue_number" db:"next_issue_number"`
+	AIPausedAt      *time.Time      `json:"ai_paused_at,omitempty" db:"ai_paused_at"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy       *int64          `json:"deleted_by,omitempty" db:"deleted_by"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectPatch describes a partial update to a project. Nil fields are left unchanged.
+type ProjectPatch struct {
+	Name             *string
+	Key              *string
+	Description      *string
+	ArchiveAfterDays *int
+	AI               *AISettings
+	Critical         *bool
+	IssueTemplates   *[]IssueTemplate
+	NextIssueNumber  *int64
+	// CloseApproval sets the role whose approval closing an issue requires;
+	// empty removes the requirement.
+	CloseApproval *ProjectRole
+}
+
+// ProjectSummary is a project as seen by a particular user in listings.
+type ProjectSummary struct {
+	Project
+	Role           ProjectRole `json:"role" db:"role"`
+	OpenIssueCount int         `json:"open_issue_count" db:"open_issue_count"`
+}
+
+// ProjectFilter narrows a project listing. Zero-valued fields are not applied.
+type ProjectFilter struct {
+	Query  string
+	Cursor ListPosition
+	Limit  int
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ItemType identifies the kind of resource a quick-access entry points to.
+type ItemType string
+
+const (
+	ItemTypeProject ItemType = "project"
+	ItemTypeIssue   ItemType = "issue"
+)
+
+// Valid reports whether t is a known item type.
+func (t ItemType) Valid() bool {
+	return t == ItemTypeProject || t == ItemTypeIssue
+}
+
+// QuickAccessItem is a recently viewed or starred project or issue.
+type QuickAccessItem struct {
+	ItemType  ItemType  `json:"item_type" db:"item_type"`
+	ItemID    int64     `json:"item_id" db:"item_id"`
+	ProjectID int64     `json:"project_id" db:"project_id"`
+	Title     string    `json:"title" db:"title"`
+	At        time.Time `json:"at" db:"at"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "encoding/json"
+
+// RealtimeEventType names an event pushed to open streams.
+type RealtimeEventType string
+
+const (
+	// RealtimeUnreadCount carries the user's unread notification count when
+	// a stream opens, so the client starts in sync.
+	RealtimeUnreadCount       RealtimeEventType = "notifications.unread_count"
+	RealtimeNotificationsRead RealtimeEventType = "notifications.read"
+	// RealtimeNotificationSnoozed and RealtimeNotificationResurfaced carry a
+	// NotificationChange when a snooze starts or ends.
+	RealtimeNotificationSnoozed    RealtimeEventType = "notification.snoozed"
+	RealtimeNotificationResurfaced RealtimeEventType = "notification.resurfaced"
+
+	// RealtimeIssueChanged and RealtimeCommentAdded carry an IssueChange
+	// when an issue changes or is commented on.
+	RealtimeIssueChanged RealtimeEventType = "issue.changed"
+	RealtimeCommentAdded RealtimeEventType = "comment.added"
+	// RealtimeAIProgress carries an AIProgress as an AI job is queued, runs
+	// and ends.
+	RealtimeAIProgress RealtimeEventType = "ai.progress"
+)
+
+// RealtimeEvent is an event for one user's streams or, if ProjectID is
+// set, for the streams watching a project. Data is its payload.
+type RealtimeEvent struct {
+	UserID    int64             `json:"user_id,omitempty"`
+	ProjectID int64             `json:"project_id,omitempty"`
+	Type      RealtimeEventType `json:"type"`
+	Data      json.RawMessage   `json:"data"`
+}
+
+// NotificationsRead reports notifications marked read on another device:
+// the IDs given, every one up to and including UpTo, or All of them.
+// UnreadCount is what remains unread afterwards.
+type NotificationsRead struct {
+	IDs         []int64 `json:"ids,omitempty"`
+	UpTo        *int64  `json:"up_to,omitempty"`
+	All         bool    `json:"all,omitempty"`
+	UnreadCount int     `json:"unread_count"`
+}
+
+// UnreadCount is the payload of a RealtimeUnreadCount event.
+type UnreadCount struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// NotificationChange reports a notification whose visibility changed, with
+// the user's unread count afterwards.
+type NotificationChange struct {
+	Notification Notification `json:"notification"`
+	UnreadCount  int          `json:"unread_count"`
+}
+
+// IssueChange is the payload of RealtimeIssueChanged and
+// RealtimeCommentAdded events: the issue event that was recorded, without
+// the issue itself, which clients fetch if they show it.
+type IssueChange struct {
+	IssueID int64     `json:"issue_id"`
+	ActorID *int64    `json:"actor_id,omitempty"`
+	Event   EventType `json:"event"`
+	Data    EventData `json:"data,omitempty"`
+}
+
+// AIProgress reports how far an AI job on an issue has come. Event is set
+// when the job was queued or ended, with its details in Data; otherwise the
+// job is running and Output is how many bytes of output it has streamed so
+// far, which clients follow on the job's log stream.
+type AIProgress struct {
+	IssueID int64     `json:"issue_id"`
+	JobID   int64     `json:"job_id"`
+	RunID   int64     `json:"run_id,omitempty"`
+	Event   EventType `json:"event,omitempty"`
+	Data    EventData `json:"data,omitempty"`
+	Output  int       `json:"output,omitempty"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// projectKeyPattern matches a whole project key.
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// referencePattern matches issue references such as PROJ-123: a project key
+// followed by an issue number.
+var referencePattern = regexp.MustCompile(`\b([A-Z][A-Z0-9]{1,9})-([1-9][0-9]{0,17})\b`)
+
+// ValidProjectKey reports whether key can be used as a project key: two to
+// ten upper-case letters and digits, starting with a letter.
+func ValidProjectKey(key string) bool {
+	return projectKeyPattern.MatchString(key)
+}
+
+// IssueKey identifies an issue as written in a reference.
+type IssueKey struct {
+	ProjectKey string
+	Number     int64
+}
+
+// ParseReferences returns the distinct issue references in texts, in order
+// of first appearance.
+func ParseReferences(texts ...string) []IssueKey {
+	var keys []IssueKey
+	seen := make(map[IssueKey]bool)
+	for _, text := range texts {
+		for _, m := range referencePattern.FindAllStringSubmatch(text, -1) {
+			number, err := strconv.ParseInt(m[2], 10, 64)
+			if err != nil {
+				continue
+			}
+			key := IssueKey{ProjectKey: m[1], Number: number}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// IssueReference is an issue linked to another by a reference, as shown on
+// the other issue. CommentID is set when the reference was made in a comment.
+type IssueReference struct {
+	IssueID    int64       `json:"issue_id" db:"issue_id"`
+	ProjectID  int64       `json:"project_id" db:"project_id"`
+	ProjectKey *string     `json:"project_key,omitempty" db:"project_key"`
+	Number     int64       `json:"number" db:"number"`
+	Title      string      `json:"title" db:"title"`
+	Status     IssueStatus `json:"status" db:"status"`
+	CommentID  *int64      `json:"comment_id,omitempty" db:"comment_id"`
+}
+
+// IssueDetail is a single issue with the issues it references, the issues
+// that reference it and the wiki pages it links to.
+type IssueDetail struct {
+	Issue
+	References   []IssueReference  `json:"references"`
+	ReferencedBy []IssueReference  `json:"referenced_by"`
+	WikiPages    []WikiPageSummary `json:"wiki_pages"`
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// otherChanges is the section of release notes for issues without a label
+// of their own section.
+const otherChanges = "Other changes"
+
+// ReleaseScope selects the completed issues release notes cover: those in
+// a milestone, or those completed from From up to but excluding To.
+type ReleaseScope struct {
+	MilestoneID *int64
+	From        time.Time
+	To          time.Time
+}
+
+// ReleaseIssue is a completed issue as listed in release notes, with the
+// names of its labels.
+type ReleaseIssue struct {
+	ID         int64      `db:"id"`
+	ProjectKey *string    `db:"project_key"`
+	Number     int64      `db:"number"`
+	Title      string     `db:"title"`
+	ClosedAt   *time.Time `db:"closed_at"`
+	Labels     []string   `db:"-"`
+}
+
+// Ref is how the issue is referred to: KEY-123 in projects with a key, #123
+// in others.
+func (i ReleaseIssue) Ref() string {
+	if i.ProjectKey != nil {
+		return fmt.Sprintf("%s-%d", *i.ProjectKey, i.Number)
+	}
+	return fmt.Sprintf("#%d", i.Number)
+}
+
+// ReleaseSection is a group of release notes under a heading.
+type ReleaseSection struct {
+	Heading string
+	Issues  []ReleaseIssue
+}
+
+// ReleaseNotes are the completed issues of a release grouped into sections.
+type ReleaseNotes struct {
+	Title    string
+	Sections []ReleaseSection
+}
+
+// GroupReleaseNotes groups issues into a section per label. Each issue is
+// listed once, under the first of groups it carries; without groups, every
+// label of the issues gets a section, in name order. Issues with none of
+// the labels come last, as other changes.
+func GroupReleaseNotes(title string, issues []ReleaseIssue, groups []string) ReleaseNotes {
+	if len(groups) == 0 {
+		for _, issue := range issues {
+			for _, label := range issue.Labels {
+				if !slices.Contains(groups, label) {
+					groups = append(groups, label)
+				}
+			}
+		}
+		slices.Sort(groups)
+	}
+
+	sections := make([]ReleaseSection, len(groups)+1)
+	for i, g := range groups {
+		sections[i].Heading = g
+	}
+	sections[len(groups)].Heading = otherChanges
+	for _, issue := range issues {
+		i := slices.IndexFunc(groups, func(g string) bool { return slices.Contains(issue.Labels, g) })
+		if i < 0 {
+			i = len(groups)
+		}
+		sections[i].Issues = append(sections[i].Issues, issue)
+	}
+
+	notes := ReleaseNotes{Title: title}
+	for _, s := range sections {
+		if len(s.Issues) > 0 {
+			notes.Sections = append(notes.Sections, s)
+		}
+	}
+	return notes
+}
+
+// Markdown renders the notes as a Markdown document.
+func (n ReleaseNotes) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", n.Title)
+	if len(n.Sections) == 0 {
+		b.WriteString("\nNo issues were completed.\n")
+	}
+	for _, s := range n.Sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", s.Heading)
+		for _, issue := range s.Issues {
+			fmt.Fprintf(&b, "- %s (%s)\n", strings.TrimSpace(issue.Title), issue.Ref())
+		}
+	}
+	return b.String()
+}
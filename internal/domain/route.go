@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RoutableNotificationTypes are the notification types routing rules can
+// match.
+var RoutableNotificationTypes = []NotificationType{
+	NotificationIssueCreated,
+	NotificationIssueCompleted,
+	NotificationIssueFailed,
+}
+
+// NotificationRoute is a project's rule sending the notifications that
+// match it to email addresses and Slack channels, in addition to the
+// project's members.
+type NotificationRoute struct {
+	ID        int64        `json:"id" db:"id"`
+	ProjectID int64        `json:"project_id" db:"project_id"`
+	Name      string       `json:"name" db:"name"`
+	Match     RouteMatch   `json:"match" db:"match"`
+	Targets   RouteTargets `json:"targets" db:"targets"`
+	Active    bool         `json:"active" db:"active"`
+	CreatedBy *int64       `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// RouteMatch selects notifications. An empty Types matches every type and
+// an empty Labels every issue; otherwise the issue needs one of the labels.
+type RouteMatch struct {
+	Types  []NotificationType `json:"types,omitempty"`
+	Labels []string           `json:"labels,omitempty"`
+}
+
+// Matches reports whether a notification of type t about an issue with the
+// given labels is selected. Labels compare case-insensitively.
+func (m RouteMatch) Matches(t NotificationType, labels []string) bool {
+	if len(m.Types) > 0 && !slices.Contains(m.Types, t) {
+		return false
+	}
+	if len(m.Labels) == 0 {
+		return true
+	}
+	for _, want := range m.Labels {
+		for _, have := range labels {
+			if strings.EqualFold(want, have) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (m *RouteMatch) Scan(src any) error {
+	return scanJSON(src, m)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (m RouteMatch) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// RouteTargets are where a routing rule sends notifications. Slack channels
+// are channel IDs or names such as "#infra".
+type RouteTargets struct {
+	Emails        []string `json:"emails,omitempty"`
+	SlackChannels []string `json:"slack_channels,omitempty"`
+}
+
+// Empty reports whether there are no targets.
+func (t RouteTargets) Empty() bool {
+	return len(t.Emails) == 0 && len(t.SlackChannels) == 0
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (t *RouteTargets) Scan(src any) error {
+	return scanJSON(src, t)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (t RouteTargets) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+// NotificationRoutePatch describes a partial update to a routing rule. Nil
+// fields are left unchanged.
+type NotificationRoutePatch struct {
+	Name    *string
+	Match   *RouteMatch
+	Targets *RouteTargets
+	Active  *bool
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// SavedFilter is a user's named view of a project's issues. Query is a
+// filter expression, as parsed by the filterquery package.
+type SavedFilter struct {
+	ID        int64     `json:"id" db:"id"`
+	ProjectID int64     `json:"project_id" db:"project_id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Query     string    `json:"query" db:"query"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SavedFilterPatch describes a partial update to a saved filter. Nil fields
+// are left unchanged.
+type SavedFilterPatch struct {
+	Name  *string
+	Query *string
+}
@@ -0,0 +1,30 @@
+package domain
+
+// SearchChange records that an issue's searchable fields changed, or that
+// the issue was deleted, and its search index entry must be refreshed.
+type SearchChange struct {
+	ID      int64 `db:"id"`
+	IssueID int64 `db:"issue_id"`
+}
+
+// IssueSuggestion is a compact issue reference offered while typing, for
+// example when linking an issue from a comment.
+type IssueSuggestion struct {
+	ID     int64       `json:"id" db:"id"`
+	Number int64       `json:"number" db:"number"`
+	Title  string      `json:"title" db:"title"`
+	Status IssueStatus `json:"status" db:"status"`
+}
+
+// SearchHit is an issue matching a search across the user's projects.
+// Snippet is an excerpt of the issue with the matched terms marked by
+// <b> tags; Rank orders hits, higher first.
+type SearchHit struct {
+	IssueID   int64       `json:"issue_id" db:"issue_id"`
+	ProjectID int64       `json:"project_id" db:"project_id"`
+	Number    int64       `json:"number" db:"number"`
+	Title     string      `json:"title" db:"title"`
+	Status    IssueStatus `json:"status" db:"status"`
+	Snippet   string      `json:"snippet" db:"snippet"`
+	Rank      float64     `json:"rank" db:"rank"`
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// SignedURL is a URL that downloads a private object, such as an
+// attachment or an AI job artifact, without further authentication until
+// ExpiresAt.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
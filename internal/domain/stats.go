@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+// TimeWindow is a half-open time range [Since, Until).
+type TimeWindow struct {
+	Since time.Time
+	Until time.Time
+}
+
+// ContributorStats summarises one member's activity in a project over a window.
+type ContributorStats struct {
+	UserID          int64  `json:"user_id" db:"user_id"`
+	DisplayName     string `json:"display_name" db:"display_name"`
+	IssuesCreated   int    `json:"issues_created" db:"issues_created"`
+	IssuesClosed    int    `json:"issues_closed" db:"issues_closed"`
+	Comments        int    `json:"comments" db:"comments"`
+	AIJobsTriggered int    `json:"ai_jobs_triggered" db:"ai_jobs_triggered"`
+}
+
+// IssueGrouping is the dimension issue counts are broken down by.
+type IssueGrouping string
+
+const (
+	IssueGroupingStatus   IssueGrouping = "status"
+	IssueGroupingLabel    IssueGrouping = "label"
+	IssueGroupingAssignee IssueGrouping = "assignee"
+)
+
+// Valid reports whether g is a known grouping.
+func (g IssueGrouping) Valid() bool {
+	switch g {
+	case IssueGroupingStatus, IssueGroupingLabel, IssueGroupingAssignee:
+		return true
+	}
+	return false
+}
+
+// StatsInterval is the width of the time buckets in a statistics series.
+type StatsInterval string
+
+const (
+	StatsIntervalDay   StatsInterval = "day"
+	StatsIntervalWeek  StatsInterval = "week"
+	StatsIntervalMonth StatsInterval = "month"
+)
+
+// Valid reports whether i is a known interval.
+func (i StatsInterval) Valid() bool {
+	switch i {
+	case StatsIntervalDay, StatsIntervalWeek, StatsIntervalMonth:
+		return true
+	}
+	return false
+}
+
+// IssueCount is the number of issues created in one time bucket that share a
+// group key. Key is the status, label name or assignee ID depending on the
+// grouping, and nil for unlabelled or unassigned issues. An issue with
+// several labels is counted once per label.
+type IssueCount struct {
+	Bucket time.Time `json:"bucket" db:"bucket"`
+	Key    *string   `json:"key" db:"key"`
+	Count  int       `json:"count" db:"count"`
+}
+
+// FlowPoint is the number of issues in each status at the end of one UTC
+// day. A series of points gives cumulative flow directly; Remaining is the
+// burndown line and Done against Scope the burnup.
+type FlowPoint struct {
+	Day        time.Time `json:"day" db:"day"`
+	Open       int       `json:"open" db:"open"`
+	InProgress int       `json:"in_progress" db:"in_progress"`
+	Completed  int       `json:"completed" db:"completed"`
+	Closed     int       `json:"closed" db:"closed"`
+	Remaining  int       `json:"remaining" db:"remaining"`
+	Done       int       `json:"done" db:"done"`
+	Scope      int       `json:"scope" db:"scope"`
+}
+
+// DurationPercentiles summarises a distribution of durations in seconds.
+// Percentiles are nil when Count is zero.
+type DurationPercentiles struct {
+	Count int      `json:"count"`
+	P50   *float64 `json:"p50"`
+	P75   *float64 `json:"p75"`
+	P90   *float64 `json:"p90"`
+	P95   *float64 `json:"p95"`
+}
+
+// CycleTimeStats describes how long issues completed in a window took.
+// Lead time runs from creation to completion; cycle time runs from the first
+// move to in_progress to completion and only covers issues that were ever in
+// progress.
+type CycleTimeStats struct {
+	LeadTime  DurationPercentiles `json:"lead_time"`
+	CycleTime DurationPercentiles `json:"cycle_time"`
+}
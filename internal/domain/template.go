@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LabelSpec describes a label to create when a template is applied.
+type LabelSpec struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// IssueTemplate is a prefilled title and body offered when creating an
+// issue. A template with Fields is a form: issues created from it answer the
+// fields, and their body is rendered from the answers.
+type IssueTemplate struct {
+	Name   string      `json:"name"`
+	Title  string      `json:"title"`
+	Body   string      `json:"body"`
+	Fields []FormField `json:"fields,omitempty"`
+}
+
+// IssueTemplate returns the template named name.
+func (s ProjectSettings) IssueTemplate(name string) (IssueTemplate, bool) {
+	for _, t := range s.IssueTemplates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return IssueTemplate{}, false
+}
+
+// AutomationRule describes an action to take when a project event occurs.
+type AutomationRule struct {
+	Trigger string `json:"trigger"`
+	Action  string `json:"action"`
+	Value   string `json:"value"`
+	Match   string `json:"match,omitempty"`
+}
+
+// ProjectSettings holds per-project configuration stored alongside the project.
+type ProjectSettings struct {
+	IssueTemplates  []IssueTemplate  `json:"issue_templates,omitempty"`
+	AutomationRules []AutomationRule `json:"automation_rules,omitempty"`
+	// ArchiveAfterDays archives issues that have been closed this many days.
+	// Zero disables archival.
+	ArchiveAfterDays int         `json:"archive_after_days,omitempty"`
+	AI               *AISettings `json:"ai,omitempty"`
+	// Critical pages the on-call rotation when the project's AI jobs fail
+	// for good or wait too long in the queue.
+	Critical bool `json:"critical,omitempty"`
+	// CloseApproval, if set, is the role a user needs to move an issue to a
+	// done status. Others must request the close and have it approved.
+	CloseApproval ProjectRole `json:"close_approval,omitempty"`
+}
+
+// AISettings configures how the AI assistant works on a project's issues.
+type AISettings struct {
+	AutoRun      bool   `json:"auto_run"`
+	Instructions string `json:"instructions,omitempty"`
+	// Artifacts are glob patterns, relative to the job workspace, of files
+	// to keep after a run.
+	Artifacts []string `json:"artifacts,omitempty"`
+	// AllowedTools and PermissionMode are passed to Claude Code; see Validate.
+	AllowedTools   []string         `json:"allowed_tools,omitempty"`
+	PermissionMode AIPermissionMode `json:"permission_mode,omitempty"`
+}
+
+// Inherit returns s with every unset setting taken from defaults, so a
+// project keeps its own values and falls back to its organization's.
+func (s ProjectSettings) Inherit(defaults ProjectSettings) ProjectSettings {
+	if len(s.IssueTemplates) == 0 {
+		s.IssueTemplates = defaults.IssueTemplates
+	}
+	if len(s.AutomationRules) == 0 {
+		s.AutomationRules = defaults.AutomationRules
+	}
+	if s.ArchiveAfterDays == 0 {
+		s.ArchiveAfterDays = defaults.ArchiveAfterDays
+	}
+	if s.AI == nil {
+		s.AI = defaults.AI
+	}
+	if !s.Critical {
+		s.Critical = defaults.Critical
+	}
+	if s.CloseApproval == "" {
+		s.CloseApproval = defaults.CloseApproval
+	}
+	return s
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (s *ProjectSettings) Scan(src any) error {
+	return scanJSON(src, s)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (s ProjectSettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// TemplateDefinition is the content a project template applies to a new project.
+type TemplateDefinition struct {
+	Labels          []LabelSpec      `json:"labels,omitempty"`
+	IssueTemplates  []IssueTemplate  `json:"issue_templates,omitempty"`
+	AutomationRules []AutomationRule `json:"automation_rules,omitempty"`
+}
+
+// Settings returns the project settings portion of the definition.
+func (d TemplateDefinition) Settings() ProjectSettings {
+	return ProjectSettings{
+		IssueTemplates:  d.IssueTemplates,
+		AutomationRules: d.AutomationRules,
+	}
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (d *TemplateDefinition) Scan(src any) error {
+	return scanJSON(src, d)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (d TemplateDefinition) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// ProjectTemplate is a reusable starting point for new projects. Built-in
+// templates have no owner.
+type ProjectTemplate struct {
+	ID          int64              `json:"id" db:"id"`
+	OwnerID     *int64             `json:"owner_id,omitempty" db:"owner_id"`
+	Name        string             `json:"name" db:"name"`
+	Description *string            `json:"description,omitempty" db:"description"`
+	Definition  TemplateDefinition `json:"definition" db:"definition"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+}
+
+// BuiltIn reports whether the template ships with the application.
+func (t ProjectTemplate) BuiltIn() bool {
+	return t.OwnerID == nil
+}
+
+func scanJSON(src, dst any) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// TimelineKind is the source of a timeline item.
+type TimelineKind string
+
+const (
+	TimelineEvent   TimelineKind = "event"
+	TimelineComment TimelineKind = "comment"
+)
+
+// TimelinePosition places an item in an issue's timeline, which is ordered
+// by creation time, then kind, then ID. The zero position comes before
+// every item.
+type TimelinePosition struct {
+	At   time.Time
+	Kind TimelineKind
+	ID   int64
+}
+
+// Before reports whether p comes before q in a timeline.
+func (p TimelinePosition) Before(q TimelinePosition) bool {
+	if !p.At.Equal(q.At) {
+		return p.At.Before(q.At)
+	}
+	if p.Kind != q.Kind {
+		return p.Kind < q.Kind
+	}
+	return p.ID < q.ID
+}
+
+// TimelineItem is an entry in an issue's timeline: one of its events, such
+// as a status change, or one of its comments.
+type TimelineItem struct {
+	Kind      TimelineKind `json:"kind"`
+	CreatedAt time.Time    `json:"created_at"`
+	Event     *IssueEvent  `json:"event,omitempty"`
+	Comment   *Comment     `json:"comment,omitempty"`
+}
+
+// Position returns where the item is in the timeline.
+func (i TimelineItem) Position() TimelinePosition {
+	p := TimelinePosition{At: i.CreatedAt, Kind: i.Kind}
+	switch {
+	case i.Event != nil:
+		p.ID = i.Event.ID
+	case i.Comment != nil:
+		p.ID = i.Comment.ID
+	}
+	return p
+}
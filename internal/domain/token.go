@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// AccessTokenPrefix starts every personal access token, so they can be told
+// apart from session JWTs and recognised by secret scanners.
+const AccessTokenPrefix = "iss_pat_"
+
+// AccessTokenScope limits what a personal access token may do.
+type AccessTokenScope string
+
+const (
+	// AccessTokenScopeRead tokens may only read, for tools that should not
+	// change anything.
+	AccessTokenScopeRead AccessTokenScope = "read"
+	// AccessTokenScopeWrite tokens act with their user's full access.
+	AccessTokenScopeWrite AccessTokenScope = "write"
+)
+
+// Valid reports whether s is a known scope.
+func (s AccessTokenScope) Valid() bool {
+	return s == AccessTokenScopeRead || s == AccessTokenScopeWrite
+}
+
+// AccessToken is a personal access token a user created to call the API
+// from scripts. The token itself is only returned when it is created.
+type AccessToken struct {
+	ID         int64            `json:"id" db:"id"`
+	UserID     int64            `json:"user_id" db:"user_id"`
+	Name       string           `json:"name" db:"name"`
+	Scope      AccessTokenScope `json:"scope" db:"scope"`
+	ExpiresAt  *time.Time       `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time       `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+}
@@ -8,8 +8,13 @@ type AuthProvider string
 const (
 	AuthProviderGoogle AuthProvider = "google"
 	AuthProviderGitHub AuthProvider = "github"
+	// AuthProviderSystem identifies built-in accounts that cannot sign in.
+	AuthProviderSystem AuthProvider = "system"
 )
 
+// AIBotProviderID is the system account that posts AI job results.
+const AIBotProviderID = "ai-bot"
+
 // User represents an authenticated user.
 type User struct {
 	ID          int64        `json:"id" db:"id"`
@@ -18,6 +23,7 @@ type User struct {
 	Email       string       `json:"email" db:"email"`
 	DisplayName string       `json:"display_name" db:"display_name"`
 	AvatarURL   *string      `json:"avatar_url,omitempty" db:"avatar_url"`
+	IsAdmin     bool         `json:"is_admin" db:"is_admin"`
 	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
 }
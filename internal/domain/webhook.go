@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// WebhookDeliveryStatus is the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Valid reports whether s is a known delivery status.
+func (s WebhookDeliveryStatus) Valid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookFormat is the shape of the requests a webhook is sent.
+type WebhookFormat string
+
+const (
+	// WebhookFormatJSON sends the event payload, or the webhook's template
+	// rendered from it.
+	WebhookFormatJSON WebhookFormat = "json"
+	// WebhookFormatTeams sends a Microsoft Teams message with an Adaptive
+	// Card describing the event.
+	WebhookFormatTeams WebhookFormat = "teams"
+)
+
+// Valid reports whether f is a known webhook format.
+func (f WebhookFormat) Valid() bool {
+	switch f {
+	case WebhookFormatJSON, WebhookFormatTeams:
+		return true
+	}
+	return false
+}
+
+// WebhookRetryPolicy overrides how failed deliveries to a webhook are
+// retried. Nil fields use the installation's defaults. The delay before a
+// retry starts at BackoffSeconds and doubles with each failed attempt;
+// Jitter is the fraction of it, from 0 to 1, that is randomized so that
+// retries to a recovering endpoint are spread out.
+type WebhookRetryPolicy struct {
+	MaxAttempts    *int     `json:"max_attempts,omitempty"`
+	BackoffSeconds *int     `json:"backoff_seconds,omitempty"`
+	Jitter         *float64 `json:"jitter,omitempty"`
+}
+
+// Scan implements sql.Scanner for JSONB columns.
+func (p *WebhookRetryPolicy) Scan(src any) error {
+	return scanJSON(src, p)
+}
+
+// Value implements driver.Valuer for JSONB columns.
+func (p WebhookRetryPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Webhook is a project's own endpoint for its events. In the JSON format,
+// payloads are sent as JSON unless Template is set, in which case each
+// payload is rendered through it and sent with ContentType. FailingSince is
+// when the endpoint's current run of failed attempts began; a webhook that
+// keeps failing is deactivated, as of DisabledAt.
+type Webhook struct {
+	ID           int64              `json:"id" db:"id"`
+	ProjectID    int64              `json:"project_id" db:"project_id"`
+	URL          string             `json:"url" db:"url"`
+	Secret       string             `json:"-" db:"secret"`
+	Format       WebhookFormat      `json:"format" db:"format"`
+	Template     *string            `json:"template,omitempty" db:"template"`
+	ContentType  string             `json:"content_type" db:"content_type"`
+	Active       bool               `json:"active" db:"active"`
+	Retry        WebhookRetryPolicy `json:"retry" db:"retry_policy"`
+	FailingSince *time.Time         `json:"failing_since,omitempty" db:"failing_since"`
+	DisabledAt   *time.Time         `json:"disabled_at,omitempty" db:"disabled_at"`
+	CreatedBy    *int64             `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookPatch describes a partial update to a webhook. Nil fields are left
+// unchanged; an empty Template removes the template.
+type WebhookPatch struct {
+	URL         *string
+	Format      *WebhookFormat
+	Template    *string
+	ContentType *string
+	Active      *bool
+	Retry       *WebhookRetryPolicy
+}
+
+// WebhookDelivery is one issue event sent, or to be sent, to a webhook
+// endpoint. WebhookID is nil for the endpoint configured for the whole
+// installation. LastStatusCode and LastError describe the latest attempt; a
+// pending delivery is next attempted at NextAttemptAt. Secret and Retry,
+// the webhook's retry policy, are only loaded for deliveries about to be
+// sent.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id" db:"id"`
+	EventID        int64                 `json:"event_id" db:"event_id"`
+	EventType      EventType             `json:"event_type" db:"event_type"`
+	ProjectID      int64                 `json:"project_id" db:"project_id"`
+	WebhookID      *int64                `json:"webhook_id,omitempty" db:"webhook_id"`
+	URL            string                `json:"url" db:"url"`
+	Body           string                `json:"body" db:"body"`
+	ContentType    string                `json:"content_type" db:"content_type"`
+	Secret         string                `json:"-" db:"secret"`
+	Retry          WebhookRetryPolicy    `json:"-" db:"retry_policy"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookAttempt is the outcome of one attempt to send a delivery. Status
+// is pending, with NextAttemptAt set, when the delivery will be retried.
+type WebhookAttempt struct {
+	Status        WebhookDeliveryStatus
+	StatusCode    *int
+	Error         *string
+	NextAttemptAt *time.Time
+}
+
+// WebhookTestResult is the outcome of sending a sample payload to a webhook.
+// Body is the request body that was sent; Duration is in milliseconds.
+type WebhookTestResult struct {
+	EventType  EventType `json:"event_type"`
+	Delivered  bool      `json:"delivered"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	Body       string    `json:"body"`
+	Duration   int64     `json:"duration_ms"`
+}
+
+// WebhookDeliveryFilter narrows a delivery listing. Zero-valued fields are
+// not applied.
+type WebhookDeliveryFilter struct {
+	Statuses  []WebhookDeliveryStatus
+	ProjectID *int64
+	WebhookID *int64
+	EventType EventType
+	Cursor    ListPosition
+	Limit     int
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxWikiSlug is the longest a wiki page slug may be.
+const maxWikiSlug = 100
+
+// wikiSlugPattern matches a whole wiki page slug.
+var wikiSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// wikiLinkPattern matches links to wiki pages such as [[release-process]]
+// or [[release-process|how we release]].
+var wikiLinkPattern = regexp.MustCompile(`\[\[([a-z0-9]+(?:-[a-z0-9]+)*)(?:\|[^\]\n]*)?\]\]`)
+
+// ValidWikiSlug reports whether slug can address a wiki page: lowercase
+// letters and digits in words joined by single dashes, at most 100
+// characters.
+func ValidWikiSlug(slug string) bool {
+	return len(slug) <= maxWikiSlug && wikiSlugPattern.MatchString(slug)
+}
+
+// WikiSlug derives a slug from a page title, such as release-process from
+// "Release Process". It returns "" if the title has no letters or digits.
+func WikiSlug(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	slug := b.String()
+	if len(slug) > maxWikiSlug {
+		slug = slug[:maxWikiSlug]
+	}
+	return strings.Trim(slug, "-")
+}
+
+// ParseWikiLinks returns the distinct wiki page slugs linked from texts, in
+// order of first appearance.
+func ParseWikiLinks(texts ...string) []string {
+	var slugs []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, m := range wikiLinkPattern.FindAllStringSubmatch(text, -1) {
+			if slug := m[1]; len(slug) <= maxWikiSlug && !seen[slug] {
+				seen[slug] = true
+				slugs = append(slugs, slug)
+			}
+		}
+	}
+	return slugs
+}
+
+// WikiPageSummary describes a wiki page without its body, as listed.
+// Revision is the number of the page's current revision.
+type WikiPageSummary struct {
+	ID        int64     `json:"id" db:"id"`
+	ProjectID int64     `json:"project_id" db:"project_id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Title     string    `json:"title" db:"title"`
+	Revision  int       `json:"revision" db:"revision"`
+	UpdatedBy *int64    `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WikiPage is a Markdown page in a project's wiki.
+type WikiPage struct {
+	WikiPageSummary
+	Body      string `json:"body" db:"body"`
+	CreatedBy *int64 `json:"created_by,omitempty" db:"created_by"`
+}
+
+// WikiPageDetail is a wiki page with the issues that link to it.
+type WikiPageDetail struct {
+	WikiPage
+	LinkedFrom []IssueReference `json:"linked_from"`
+}
+
+// WikiPagePatch describes an edit to a wiki page. Nil fields are left
+// unchanged; Message says what the edit was for.
+type WikiPagePatch struct {
+	Title   *string
+	Body    *string
+	Message string
+}
+
+// WikiRevisionSummary describes a revision of a wiki page without its body.
+type WikiRevisionSummary struct {
+	PageID    int64     `json:"page_id" db:"page_id"`
+	Revision  int       `json:"revision" db:"revision"`
+	Title     string    `json:"title" db:"title"`
+	Message   string    `json:"message" db:"message"`
+	AuthorID  *int64    `json:"author_id,omitempty" db:"author_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WikiRevision is a wiki page's title and body as of one edit.
+type WikiRevision struct {
+	WikiRevisionSummary
+	Body string `json:"body" db:"body"`
+}
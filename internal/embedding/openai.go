@@ -0,0 +1,89 @@
+// Package embedding provides embedding model backends for issue texts.
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// OpenAI computes embeddings with the OpenAI embeddings API, or any server
+// offering the same /embeddings endpoint, such as Ollama or vLLM.
+type OpenAI struct {
+	url    string
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAI creates an OpenAI backend for model at the API base url, for
+// example https://api.openai.com/v1. apiKey may be empty for servers that
+// do not require one.
+func NewOpenAI(url, apiKey, model string) *OpenAI {
+	return &OpenAI{
+		url:    strings.TrimRight(url, "/"),
+		apiKey: apiKey,
+		model:  model,
+		client: &http.Client{Timeout: 60 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+}
+
+// Model returns the name of the model embeddings are computed with.
+func (o *OpenAI) Model() string {
+	return o.model
+}
+
+// Embed returns the embeddings of texts, in the same order.
+func (o *OpenAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	payload, err := json.Marshal(map[string]any{"model": o.model, "input": texts})
+	if err != nil {
+		return nil, fmt.Errorf("encode embeddings request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("build embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("embeddings request: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	var body struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode embeddings response: %w", err)
+	}
+	if len(body.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings response has %d embeddings for %d texts", len(body.Data), len(texts))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, d := range body.Data {
+		if d.Index < 0 || d.Index >= len(texts) || embeddings[d.Index] != nil {
+			return nil, fmt.Errorf("embeddings response has an unexpected index %d", d.Index)
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+	return embeddings, nil
+}
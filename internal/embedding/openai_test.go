@@ -0,0 +1,84 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestOpenAIEmbed(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     [][]float32
+		wantErr  bool
+	}{
+		{
+			name:     "in order",
+			status:   http.StatusOK,
+			response: `{"data":[{"index":0,"embedding":[1,2]},{"index":1,"embedding":[3,4]}]}`,
+			want:     [][]float32{{1, 2}, {3, 4}},
+		},
+		{
+			name:     "out of order",
+			status:   http.StatusOK,
+			response: `{"data":[{"index":1,"embedding":[3,4]},{"index":0,"embedding":[1,2]}]}`,
+			want:     [][]float32{{1, 2}, {3, 4}},
+		},
+		{
+			name:     "too few",
+			status:   http.StatusOK,
+			response: `{"data":[{"index":0,"embedding":[1,2]}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "repeated index",
+			status:   http.StatusOK,
+			response: `{"data":[{"index":0,"embedding":[1,2]},{"index":0,"embedding":[3,4]}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "error status",
+			status:   http.StatusTooManyRequests,
+			response: `{"error":{"message":"rate limited"}}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/embeddings" {
+					t.Errorf("path = %q, want /v1/embeddings", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer key" {
+					t.Errorf("Authorization = %q, want Bearer key", got)
+				}
+				var req struct {
+					Model string   `json:"model"`
+					Input []string `json:"input"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				if req.Model != "small" || !slices.Equal(req.Input, []string{"a", "b"}) {
+					t.Errorf("request = %+v, want model small and input [a b]", req)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			got, err := NewOpenAI(srv.URL+"/v1/", "key", "small").Embed(context.Background(), []string{"a", "b"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Embed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]float32]) {
+				t.Errorf("Embed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
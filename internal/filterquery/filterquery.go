@@ -0,0 +1,149 @@
+// Package filterquery parses the filter expressions of saved issue views
+// into issue filters. An expression is a space-separated list of terms:
+//
+//	status:open,in_progress label:bug label:"needs triage" assignee:me crash
+//
+// A key:value term filters on a field, with comma-separated values; words
+// and "quoted phrases" without a key must appear in the title or body.
+// Statuses match any of the values given, and every label given must be
+// present, whether listed in one term or several.
+package filterquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// MaxLength bounds the length of an expression.
+const MaxLength = 1000
+
+// Me is the user value that stands for whoever applies the filter.
+const Me = "me"
+
+// Keys are the fields an expression can filter on.
+var Keys = []string{"status", "label", "assignee", "creator", "milestone"}
+
+// Parse parses an expression into a filter for userID, the user applying
+// it. An empty expression matches every unarchived issue. Errors are
+// *domain.ValidationError for the field "query".
+func Parse(expr string, userID int64) (domain.IssueFilter, error) {
+	var f domain.IssueFilter
+	if len(expr) > MaxLength {
+		return f, invalid(fmt.Sprintf("must be at most %d characters", MaxLength))
+	}
+	terms, err := split(expr, ' ')
+	if err != nil {
+		return f, err
+	}
+
+	seenLabels := make(map[string]bool)
+	for _, term := range terms {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || strings.HasPrefix(term, `"`) {
+			f.Text = append(f.Text, unquote(term))
+			continue
+		}
+		values, err := split(value, ',')
+		if err != nil {
+			return f, err
+		}
+		if len(values) == 0 {
+			return f, invalid(fmt.Sprintf("%s: needs a value", key))
+		}
+
+		switch strings.ToLower(key) {
+		case "status":
+			for _, v := range values {
+				status := domain.IssueStatus(unquote(v))
+				if !status.Valid() {
+					return f, invalid(fmt.Sprintf("unknown status %q", status))
+				}
+				f.Statuses = append(f.Statuses, status)
+			}
+		case "label":
+			for _, v := range values {
+				if name := unquote(v); !seenLabels[name] {
+					seenLabels[name] = true
+					f.Labels = append(f.Labels, name)
+				}
+			}
+		case "assignee":
+			if f.AssigneeID, err = user(key, values, userID); err != nil {
+				return f, err
+			}
+		case "creator":
+			if f.CreatedBy, err = user(key, values, userID); err != nil {
+				return f, err
+			}
+		case "milestone":
+			if f.MilestoneID, err = id(key, values); err != nil {
+				return f, err
+			}
+		default:
+			return f, invalid(fmt.Sprintf("unknown field %q; use one of %s, or quote the text", key, strings.Join(Keys, ", ")))
+		}
+	}
+	return f, nil
+}
+
+// user parses the single user ID, or Me, of a term.
+func user(key string, values []string, userID int64) (*int64, error) {
+	if len(values) == 1 && strings.EqualFold(unquote(values[0]), Me) {
+		return &userID, nil
+	}
+	return id(key, values)
+}
+
+// id parses the single ID of a term.
+func id(key string, values []string) (*int64, error) {
+	if len(values) != 1 {
+		return nil, invalid(fmt.Sprintf("%s: takes a single value", key))
+	}
+	n, err := strconv.ParseInt(unquote(values[0]), 10, 64)
+	if err != nil || n <= 0 {
+		return nil, invalid(fmt.Sprintf("%s: %q is not an ID", key, values[0]))
+	}
+	return &n, nil
+}
+
+// split splits s at sep outside double quotes, dropping empty parts. Quotes
+// are kept, to be removed by unquote.
+func split(s string, sep rune) ([]string, error) {
+	var parts []string
+	var b strings.Builder
+	quoted := false
+	flush := func() {
+		if b.Len() > 0 {
+			parts = append(parts, b.String())
+			b.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case !quoted && (r == sep || sep == ' ' && (r == '\t' || r == '\n')):
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, invalid("has an unterminated quote")
+	}
+	flush()
+	return parts, nil
+}
+
+// unquote removes the double quotes from a term or value.
+func unquote(s string) string {
+	return strings.ReplaceAll(s, `"`, "")
+}
+
+func invalid(message string) error {
+	return &domain.ValidationError{Field: "query", Message: message}
+}
@@ -0,0 +1,106 @@
+package filterquery
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+func ptr(n int64) *int64 { return &n }
+
+func TestParse(t *testing.T) {
+	const userID = 9
+
+	tests := []struct {
+		name string
+		expr string
+		want domain.IssueFilter
+	}{
+		{name: "empty", expr: "", want: domain.IssueFilter{}},
+		{name: "blank", expr: " \t\n ", want: domain.IssueFilter{}},
+		{
+			name: "statuses",
+			expr: "status:open,in_progress",
+			want: domain.IssueFilter{Statuses: []domain.IssueStatus{domain.IssueStatusOpen, domain.IssueStatusInProgress}},
+		},
+		{name: "key case", expr: "Status:open", want: domain.IssueFilter{Statuses: []domain.IssueStatus{domain.IssueStatusOpen}}},
+		{name: "quoted label", expr: `label:"needs triage"`, want: domain.IssueFilter{Labels: []string{"needs triage"}}},
+		{
+			name: "quoted label with comma",
+			expr: `label:"a,b",c`,
+			want: domain.IssueFilter{Labels: []string{"a,b", "c"}},
+		},
+		{
+			name: "duplicate labels",
+			expr: "label:bug label:bug,ui label:ui",
+			want: domain.IssueFilter{Labels: []string{"bug", "ui"}},
+		},
+		{name: "assignee me", expr: "assignee:me", want: domain.IssueFilter{AssigneeID: ptr(userID)}},
+		{name: "assignee me any case", expr: `assignee:"ME"`, want: domain.IssueFilter{AssigneeID: ptr(userID)}},
+		{name: "creator me", expr: "creator:me", want: domain.IssueFilter{CreatedBy: ptr(userID)}},
+		{name: "assignee id", expr: "assignee:3", want: domain.IssueFilter{AssigneeID: ptr(3)}},
+		{name: "milestone", expr: "milestone:12", want: domain.IssueFilter{MilestoneID: ptr(12)}},
+		{name: "words", expr: "crash  login", want: domain.IssueFilter{Text: []string{"crash", "login"}}},
+		{name: "quoted phrase", expr: `"out of memory"`, want: domain.IssueFilter{Text: []string{"out of memory"}}},
+		{name: "quoted colon", expr: `"error: timeout"`, want: domain.IssueFilter{Text: []string{"error: timeout"}}},
+		{
+			name: "combined",
+			expr: `status:open label:bug assignee:me "null pointer"`,
+			want: domain.IssueFilter{
+				Statuses:   []domain.IssueStatus{domain.IssueStatusOpen},
+				Labels:     []string{"bug"},
+				AssigneeID: ptr(userID),
+				Text:       []string{"null pointer"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.expr, userID)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantMsg string
+	}{
+		{name: "unknown key", expr: "priority:high", wantMsg: `unknown field "priority"`},
+		{name: "unterminated quote", expr: `label:"needs triage`, wantMsg: "unterminated quote"},
+		{name: "unterminated value quote", expr: `"crash`, wantMsg: "unterminated quote"},
+		{name: "unknown status", expr: "status:open,stale", wantMsg: `unknown status "stale"`},
+		{name: "missing value", expr: "label:", wantMsg: "label: needs a value"},
+		{name: "only commas", expr: "label:,,", wantMsg: "label: needs a value"},
+		{name: "several users", expr: "assignee:1,2", wantMsg: "takes a single value"},
+		{name: "me among several", expr: "assignee:me,2", wantMsg: "takes a single value"},
+		{name: "not an id", expr: "milestone:next", wantMsg: "is not an ID"},
+		{name: "zero id", expr: "creator:0", wantMsg: "is not an ID"},
+		{name: "too long", expr: strings.Repeat("a", MaxLength+1), wantMsg: "at most"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr, 1)
+			var verr *domain.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Parse(%q) error = %v, want a validation error", tt.expr, err)
+			}
+			if verr.Field != "query" {
+				t.Errorf("error field = %q, want query", verr.Field)
+			}
+			if !strings.Contains(verr.Message, tt.wantMsg) {
+				t.Errorf("error message = %q, want it to contain %q", verr.Message, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// ActivityHandler handles activity feed endpoints.
+type ActivityHandler struct {
+	activity *service.ActivityService
+}
+
+// NewActivityHandler creates a new ActivityHandler.
+func NewActivityHandler(activity *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activity: activity}
+}
+
+// Feed returns the caller's recent actions across all their projects.
+func (h *ActivityHandler) Feed(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.activity.Feed(c.Request().Context(), userID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Events, pageMeta(page.HasNext, page.NextCursor))
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// AdminHandler handles system administration endpoints.
+type AdminHandler struct {
+	admin *service.AdminService
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(admin *service.AdminService) *AdminHandler {
+	return &AdminHandler{admin: admin}
+}
+
+// AIJobs lists AI jobs across all projects, filtered by repeated status
+// parameters, project_id, and min_age/max_age durations.
+func (h *AdminHandler) AIJobs(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	filter, err := parseAIJobFilter(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.admin.ListAIJobs(c.Request().Context(), userID, filter)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Jobs, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// AIJobStats returns AI job queue aggregates over the trailing window.
+func (h *AdminHandler) AIJobStats(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	p := newQueryParser(c)
+	window := p.duration("window")
+	if err := p.err(); err != nil {
+		return err
+	}
+
+	var d time.Duration
+	if window != nil {
+		d = *window
+	}
+	stats, err := h.admin.AIJobStats(c.Request().Context(), userID, d)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, stats)
+}
+
+// Workers returns the AI job queue depth and this instance's worker pool.
+func (h *AdminHandler) Workers(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	status, err := h.admin.Workers(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, status)
+}
+
+// resizeWorkersRequest is the request body for changing the worker count.
+type resizeWorkersRequest struct {
+	Count *int `json:"count" validate:"required,min=0"`
+}
+
+// ResizeWorkers changes the number of AI workers on this instance.
+func (h *AdminHandler) ResizeWorkers(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body resizeWorkersRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	status, err := h.admin.ResizeWorkers(c.Request().Context(), userID, *body.Count)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, status)
+}
+
+// PauseAI stops AI jobs from being claimed in every project.
+func (h *AdminHandler) PauseAI(c echo.Context) error {
+	return h.setAIPaused(c, true)
+}
+
+// ResumeAI lets queued AI jobs run again, except in individually paused projects.
+func (h *AdminHandler) ResumeAI(c echo.Context) error {
+	return h.setAIPaused(c, false)
+}
+
+func (h *AdminHandler) setAIPaused(c echo.Context, paused bool) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if err := h.admin.SetAIPaused(c.Request().Context(), userID, paused); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseAIJobFilter reads AI job list filters from the query string.
+func parseAIJobFilter(c echo.Context) (domain.AIJobFilter, error) {
+	p := newQueryParser(c)
+
+	var f domain.AIJobFilter
+	for _, s := range p.values("status") {
+		status := domain.JobStatus(s)
+		if !status.Valid() {
+			p.fail("status", fmt.Sprintf("unknown status %q", s))
+			continue
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	f.ProjectID = p.int64("project_id")
+	if d := p.duration("min_age"); d != nil {
+		f.MinAge = *d
+	}
+	if d := p.duration("max_age"); d != nil {
+		f.MaxAge = *d
+	}
+	if f.MinAge > 0 && f.MaxAge > 0 && f.MinAge >= f.MaxAge {
+		p.fail("min_age", "must be less than max_age")
+	}
+
+	page := p.page()
+	f.Cursor = p.listPosition(page)
+	f.Limit = page.Limit
+
+	return f, p.err()
+}
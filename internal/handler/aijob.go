@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+// AIJobHandler handles AI run endpoints.
+type AIJobHandler struct {
+	jobs *service.AIJobService
+}
+
+// NewAIJobHandler creates a new AIJobHandler.
+func NewAIJobHandler(jobs *service.AIJobService) *AIJobHandler {
+	return &AIJobHandler{jobs: jobs}
+}
+
+// Run queues an AI job for the issue in the path.
+func (h *AIJobHandler) Run(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body apiclient.RunAIRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	opts := service.RunOptions{Instructions: strings.TrimSpace(body.Instructions), Resume: body.Resume}
+	if body.TimeoutSeconds != nil {
+		opts.Timeout = time.Duration(*body.TimeoutSeconds) * time.Second
+	}
+
+	job, err := h.jobs.Run(c.Request().Context(), userID, projectID, issueID, opts)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusAccepted, job)
+}
+
+// Cancel stops the latest AI job of the issue in the path. A running job
+// stops shortly after the response, which is 202 until then.
+func (h *AIJobHandler) Cancel(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	job, err := h.jobs.Cancel(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	if job.Status != domain.JobStatusCancelled {
+		return JSON(c, http.StatusAccepted, job)
+	}
+	return JSON(c, http.StatusOK, job)
+}
+
+// Retry queues the failed or cancelled latest AI job of the issue in the
+// path again.
+func (h *AIJobHandler) Retry(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	job, err := h.jobs.Retry(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusAccepted, job)
+}
+
+// reviewRequest is the request body for an AI review of a diff.
+type reviewRequest struct {
+	Diff           string `json:"diff" validate:"required,max=500000"`
+	TimeoutSeconds *int   `json:"timeout_seconds" validate:"omitempty,gt=0"`
+	Instructions   string `json:"instructions" validate:"max=4000"`
+}
+
+// Review queues an AI review of a diff for the issue in the path.
+func (h *AIJobHandler) Review(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body reviewRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	opts := service.RunOptions{Instructions: strings.TrimSpace(body.Instructions)}
+	if body.TimeoutSeconds != nil {
+		opts.Timeout = time.Duration(*body.TimeoutSeconds) * time.Second
+	}
+
+	job, err := h.jobs.Review(c.Request().Context(), userID, projectID, issueID, body.Diff, opts)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusAccepted, job)
+}
+
+// ReviewComments returns a page of the AI review comments on the issue in
+// the path.
+func (h *AIJobHandler) ReviewComments(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.jobs.ReviewComments(c.Request().Context(), userID, projectID, issueID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Comments, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// Runs returns a page of the AI runs on the issue in the path.
+func (h *AIJobHandler) Runs(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.jobs.Runs(c.Request().Context(), userID, projectID, issueID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Runs, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// Approve approves the result of the job in the path.
+func (h *AIJobHandler) Approve(c echo.Context) error {
+	userID, projectID, jobID, err := jobRoute(c)
+	if err != nil {
+		return err
+	}
+
+	run, err := h.jobs.Approve(c.Request().Context(), userID, projectID, jobID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, run)
+}
+
+// Artifacts lists the files collected from the job in the path.
+func (h *AIJobHandler) Artifacts(c echo.Context) error {
+	userID, projectID, jobID, err := jobRoute(c)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := h.jobs.Artifacts(c.Request().Context(), userID, projectID, jobID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, artifacts)
+}
+
+// DownloadArtifact streams one artifact as an attachment. Downloads are
+// exempt from the request deadline.
+func (h *AIJobHandler) DownloadArtifact(c echo.Context) error {
+	userID, projectID, jobID, err := jobRoute(c)
+	if err != nil {
+		return err
+	}
+	artifactID, err := pathID(c, "aid")
+	if err != nil {
+		return err
+	}
+
+	artifact, r, err := h.jobs.OpenArtifact(c.Request().Context(), userID, projectID, jobID, artifactID)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	clearDeadline(c)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename=%q`, path.Base(artifact.Name)))
+	c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(artifact.SizeBytes, 10))
+	return c.Stream(http.StatusOK, artifact.ContentType, r)
+}
+
+// ArtifactURL returns a short-lived URL downloading the artifact in the path.
+func (h *AIJobHandler) ArtifactURL(c echo.Context) error {
+	userID, projectID, jobID, err := jobRoute(c)
+	if err != nil {
+		return err
+	}
+	artifactID, err := pathID(c, "aid")
+	if err != nil {
+		return err
+	}
+
+	u, err := h.jobs.ArtifactURL(c.Request().Context(), userID, projectID, jobID, artifactID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, u)
+}
+
+// DownloadSignedArtifact streams the artifact in the path to anyone holding
+// a valid signed URL for it. Downloads are exempt from the request deadline.
+func (h *AIJobHandler) DownloadSignedArtifact(c echo.Context) error {
+	artifactID, err := pathID(c, "aid")
+	if err != nil {
+		return err
+	}
+	expires, signature, err := querySignature(c)
+	if err != nil {
+		return err
+	}
+
+	artifact, r, err := h.jobs.OpenSignedArtifact(c.Request().Context(), artifactID, expires, signature)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	clearDeadline(c)
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename=%q`, path.Base(artifact.Name)))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(artifact.SizeBytes, 10))
+	header.Set("X-Content-Type-Options", "nosniff")
+	return c.Stream(http.StatusOK, artifact.ContentType, r)
+}
+
+// jobRoute extracts the caller and the project and job IDs from the path.
+func jobRoute(c echo.Context) (userID, projectID, jobID int64, err error) {
+	if userID, projectID, err = projectRoute(c); err != nil {
+		return 0, 0, 0, err
+	}
+	if jobID, err = pathID(c, "jid"); err != nil {
+		return 0, 0, 0, err
+	}
+	return userID, projectID, jobID, nil
+}
+
+// LogStream streams the output of the issue's latest AI job, or of job_id,
+// as server-sent "log" events while it runs, followed by an "end" event with
+// the finished job. Event IDs are log chunk IDs, so a reconnecting client
+// resumes after Last-Event-ID.
+func (h *AIJobHandler) LogStream(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	p := newQueryParser(c)
+	jobID := p.int64("job_id")
+	if err := p.err(); err != nil {
+		return err
+	}
+	var after int64
+	if v := c.Request().Header.Get("Last-Event-ID"); v != "" {
+		if after, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("%w: invalid Last-Event-ID", domain.ErrInvalidInput)
+		}
+	}
+
+	res := c.Response()
+	started := false
+	lastWrite := time.Now()
+	emit := func(logs []domain.AIJobLog) error {
+		if !started {
+			clearDeadline(c)
+			res.Header().Set(echo.HeaderContentType, "text/event-stream")
+			res.Header().Set(echo.HeaderCacheControl, "no-cache")
+			res.Header().Set(echo.HeaderConnection, "keep-alive")
+			res.WriteHeader(http.StatusOK)
+			res.Flush()
+			started = true
+		}
+		if len(logs) == 0 {
+			if time.Since(lastWrite) < streamHeartbeat {
+				return nil
+			}
+			if _, err := fmt.Fprint(res, ":\n\n"); err != nil {
+				return err
+			}
+		}
+		for _, l := range logs {
+			data, err := json.Marshal(l)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "id: %d\nevent: log\ndata: %s\n\n", l.ID, data); err != nil {
+				return err
+			}
+		}
+		res.Flush()
+		lastWrite = time.Now()
+		return nil
+	}
+
+	job, err := h.jobs.FollowLogs(c.Request().Context(), userID, projectID, issueID, jobID, after, emit)
+	if err != nil {
+		if started {
+			// The stream has begun; the client sees it end and reconnects.
+			return nil
+		}
+		return err
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: end\ndata: %s\n\n", data); err != nil {
+		return nil
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// attachmentField is the multipart form field carrying an uploaded file.
+const attachmentField = "file"
+
+// AttachmentHandler handles issue attachment endpoints.
+type AttachmentHandler struct {
+	attachments *service.AttachmentService
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler.
+func NewAttachmentHandler(attachments *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachments: attachments}
+}
+
+// Upload attaches the file in the "file" field of a multipart form to the
+// issue in the path. The file is streamed to storage rather than buffered.
+func (h *AttachmentHandler) Upload(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	form, err := c.Request().MultipartReader()
+	if err != nil {
+		return fmt.Errorf("%w: expected a multipart form", domain.ErrInvalidInput)
+	}
+	for {
+		part, err := form.NextPart()
+		if errors.Is(err, io.EOF) {
+			return &domain.ValidationError{Field: attachmentField, Message: "file is required"}
+		}
+		if err != nil {
+			return fmt.Errorf("%w: invalid multipart form", domain.ErrInvalidInput)
+		}
+		if part.FormName() != attachmentField || part.FileName() == "" {
+			continue
+		}
+
+		attachment, err := h.attachments.Upload(c.Request().Context(), userID, projectID, issueID, part.FileName(), part)
+		if err != nil {
+			return err
+		}
+		return JSON(c, http.StatusCreated, attachment)
+	}
+}
+
+// List returns the files attached to the issue in the path.
+func (h *AttachmentHandler) List(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := h.attachments.List(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, attachments)
+}
+
+// URL returns a short-lived signed URL downloading the attachment in the
+// path, or the image variant named by the variant query parameter.
+func (h *AttachmentHandler) URL(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+	attachmentID, err := pathID(c, "aid")
+	if err != nil {
+		return err
+	}
+
+	u, err := h.attachments.URL(c.Request().Context(), userID, projectID, issueID, attachmentID, c.QueryParam("variant"))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, u)
+}
+
+// Download streams the attachment in the path to anyone holding a valid
+// signed URL for it. Downloads are exempt from the request deadline.
+func (h *AttachmentHandler) Download(c echo.Context) error {
+	attachmentID, err := pathID(c, "aid")
+	if err != nil {
+		return err
+	}
+	expires, signature, err := querySignature(c)
+	if err != nil {
+		return err
+	}
+
+	attachment, r, err := h.attachments.OpenSigned(c.Request().Context(), attachmentID, c.QueryParam("variant"), expires, signature)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	clearDeadline(c)
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename=%q`, attachment.Name))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(attachment.SizeBytes, 10))
+	header.Set("X-Content-Type-Options", "nosniff")
+	return c.Stream(http.StatusOK, attachment.ContentType, r)
+}
+
+// Delete removes the attachment in the path.
+func (h *AttachmentHandler) Delete(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+	attachmentID, err := pathID(c, "aid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.attachments.Delete(c.Request().Context(), userID, projectID, issueID, attachmentID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
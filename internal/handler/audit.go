@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// AuditHandler handles audit log endpoints.
+type AuditHandler struct {
+	audit *service.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(audit *service.AuditService) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+var auditCSVHeader = []string{"id", "project_id", "actor_id", "action", "target_type", "target_id", "created_at"}
+
+// List returns the project's audit log as paginated JSON, or as a CSV
+// download when the client accepts text/csv.
+func (h *AuditHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if wantsCSV(c) {
+		filename := fmt.Sprintf("project-%d-audit-log.csv", projectID)
+		return streamCSV(c, filename, auditCSVHeader, func(write func([]string) error) error {
+			return h.audit.Export(c.Request().Context(), userID, projectID, func(e domain.AuditEntry) error {
+				return write([]string{
+					csvInt(e.ID),
+					csvInt(e.ProjectID),
+					csvInt(e.ActorID),
+					string(e.Action),
+					string(e.TargetType),
+					csvInt(e.TargetID),
+					csvTime(e.CreatedAt),
+				})
+			})
+		})
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.audit.List(c.Request().Context(), userID, projectID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Entries, pageMeta(page.HasNext, page.NextCursor))
+}
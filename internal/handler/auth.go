@@ -124,7 +124,7 @@ func (h *AuthHandler) Refresh(c echo.Context) error {
 		return err
 	}
 
-	tokens, err := h.auth.RefreshAccessToken(body.RefreshToken)
+	tokens, err := h.auth.RefreshAccessToken(c.Request().Context(), body.RefreshToken)
 	if err != nil {
 		return err
 	}
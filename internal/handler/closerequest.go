@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// CloseRequestHandler handles issue close approval endpoints.
+type CloseRequestHandler struct {
+	requests *service.CloseRequestService
+}
+
+// NewCloseRequestHandler creates a new CloseRequestHandler.
+func NewCloseRequestHandler(requests *service.CloseRequestService) *CloseRequestHandler {
+	return &CloseRequestHandler{requests: requests}
+}
+
+// createCloseRequestRequest is the request body for requesting an issue be
+// closed.
+type createCloseRequestRequest struct {
+	Status domain.IssueStatus `json:"status" validate:"required,oneof=completed closed"`
+	Note   string             `json:"note" validate:"max=2000"`
+}
+
+// decideCloseRequestRequest is the request body for approving or rejecting
+// a close request.
+type decideCloseRequestRequest struct {
+	Note string `json:"note" validate:"max=2000"`
+}
+
+// Create requests that the issue in the path be closed.
+func (h *CloseRequestHandler) Create(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createCloseRequestRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	req, err := h.requests.Request(c.Request().Context(), userID, projectID, issueID, body.Status, body.Note)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, req)
+}
+
+// List returns the close requests of the issue in the path, newest first.
+func (h *CloseRequestHandler) List(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	requests, err := h.requests.List(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, requests)
+}
+
+// Approve approves the pending close request of the issue in the path,
+// closing the issue.
+func (h *CloseRequestHandler) Approve(c echo.Context) error {
+	return h.decide(c, h.requests.Approve)
+}
+
+// Reject rejects the pending close request of the issue in the path.
+func (h *CloseRequestHandler) Reject(c echo.Context) error {
+	return h.decide(c, h.requests.Reject)
+}
+
+func (h *CloseRequestHandler) decide(c echo.Context,
+	fn func(ctx context.Context, userID, projectID, issueID int64, note string) (*domain.CloseRequest, error)) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body decideCloseRequestRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	req, err := fn(c.Request().Context(), userID, projectID, issueID, body.Note)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, req)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+// CommentHandler handles issue comment endpoints.
+type CommentHandler struct {
+	comments *service.CommentService
+}
+
+// NewCommentHandler creates a new CommentHandler.
+func NewCommentHandler(comments *service.CommentService) *CommentHandler {
+	return &CommentHandler{comments: comments}
+}
+
+// List returns a paginated list of comments on an issue.
+func (h *CommentHandler) List(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+	includeDeleted := c.QueryParam("include_deleted") == "true"
+
+	page, err := h.comments.List(c.Request().Context(), userID, projectID, issueID, after, limit, includeDeleted)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Comments, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// Create adds a comment to an issue.
+func (h *CommentHandler) Create(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body apiclient.CreateCommentRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	comment, err := h.comments.Create(c.Request().Context(), userID, projectID, issueID, body.Body)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, comment)
+}
+
+// Delete soft-deletes a comment.
+func (h *CommentHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := pathID(c, "cid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.comments.Delete(c.Request().Context(), userID, projectID, commentID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Restore undoes a comment deletion within the restore window.
+func (h *CommentHandler) Restore(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := pathID(c, "cid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.comments.Restore(c.Request().Context(), userID, projectID, commentID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
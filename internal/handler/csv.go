@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const mimeTextCSV = "text/csv"
+
+// wantsCSV reports whether the client prefers CSV over JSON. The first
+// recognised media type in the Accept header wins.
+func wantsCSV(c echo.Context) bool {
+	for _, part := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case mimeTextCSV:
+			return true
+		case echo.MIMEApplicationJSON, "*/*":
+			return false
+		}
+	}
+	return false
+}
+
+// streamCSV writes a CSV attachment, flushing rows to the client as they are
+// produced by each. The response is only committed once each writes its
+// first row or returns without error, so errors from authorization or the
+// start of the query still get their own status. Streams are exempt from
+// the request deadline. Errors after that are logged by the caller's error
+// handler but cannot change the response status.
+func streamCSV(c echo.Context, filename string, header []string, each func(write func([]string) error) error) error {
+	clearDeadline(c)
+
+	res := c.Response()
+	var w *csv.Writer
+	start := func() error {
+		if w != nil {
+			return nil
+		}
+		res.Header().Set(echo.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename=%q`, filename))
+		res.WriteHeader(http.StatusOK)
+		w = csv.NewWriter(res)
+		return w.Write(header)
+	}
+
+	rows := 0
+	err := each(func(record []string) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if err := w.Write(csvSafe(record)); err != nil {
+			return err
+		}
+		rows++
+		if rows%100 == 0 {
+			w.Flush()
+			res.Flush()
+		}
+		return w.Error()
+	})
+	if err != nil {
+		if w != nil {
+			w.Flush()
+		}
+		return err
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvSafe returns record with each cell that a spreadsheet would read as a
+// formula prefixed with an apostrophe, so user-written text such as an issue
+// title of "=HYPERLINK(...)" is shown rather than evaluated.
+func csvSafe(record []string) []string {
+	safe := make([]string, len(record))
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+		safe[i] = cell
+	}
+	return safe
+}
+
+func csvInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func csvOptInt(n *int64) string {
+	if n == nil {
+		return ""
+	}
+	return csvInt(*n)
+}
+
+func csvOptString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func csvOptTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return csvTime(*t)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+func TestStreamCSVErrorBeforeFirstRow(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err := streamCSV(c, "x.csv", []string{"id"}, func(write func([]string) error) error {
+		return domain.ErrForbidden
+	})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("streamCSV() error = %v, want %v", err, domain.ErrForbidden)
+	}
+	if c.Response().Committed {
+		t.Error("streamCSV() committed the response before any row was written")
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+		want string
+	}{
+		{"empty", nil, "id,title\n"},
+		{"rows", [][]string{{"1", "first"}, {"2", "second"}}, "id,title\n1,first\n2,second\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			err := streamCSV(c, "x.csv", []string{"id", "title"}, func(write func([]string) error) error {
+				for _, row := range tt.rows {
+					if err := write(row); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("streamCSV() error = %v", err)
+			}
+			if rec.Code != http.StatusOK || rec.Body.String() != tt.want {
+				t.Errorf("streamCSV() = %d %q, want 200 %q", rec.Code, rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	record := []string{"1", "=HYPERLINK(\"http://evil\")", "+1", "-2", "@SUM(A1)", "\tx", "plain", ""}
+	want := []string{"1", "'=HYPERLINK(\"http://evil\")", "'+1", "'-2", "'@SUM(A1)", "'\tx", "plain", ""}
+	got := csvSafe(record)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("csvSafe()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if record[1] != "=HYPERLINK(\"http://evil\")" {
+		t.Error("csvSafe() modified its argument")
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const contextKeyDeadline = "deadline"
+
+// errDeadlineExceeded is the cancellation cause of a request that ran past
+// its deadline.
+var errDeadlineExceeded = errors.New("request deadline exceeded")
+
+// errShuttingDown is the cancellation cause of a stream ended because the
+// server is shutting down.
+var errShuttingDown = errors.New("server shutting down")
+
+// deadline cancels a request context when its timer fires. Unlike
+// context.WithTimeout, the timer can be replaced or stopped once the handler
+// knows more about the request, for example that it is streaming.
+type deadline struct {
+	timer   *time.Timer
+	cancel  context.CancelCauseFunc
+	streams *Streams
+}
+
+func (d *deadline) reset(timeout time.Duration) {
+	d.stop()
+	if timeout > 0 {
+		d.timer = time.AfterFunc(timeout, func() { d.cancel(errDeadlineExceeded) })
+	}
+}
+
+func (d *deadline) stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
+// Streams ends streaming responses when the server shuts down.
+// http.Server.Shutdown waits for active requests without interrupting them,
+// and a stream never finishes on its own, so Close cancels every open
+// stream instead; clients reconnect to another instance.
+type Streams struct {
+	mu     sync.Mutex
+	open   map[*deadline]struct{}
+	closed bool
+}
+
+// NewStreams creates an empty Streams.
+func NewStreams() *Streams {
+	return &Streams{open: make(map[*deadline]struct{})}
+}
+
+// Close ends every open stream and any started later. Register it with
+// http.Server.RegisterOnShutdown.
+func (s *Streams) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for d := range s.open {
+		d.cancel(errShuttingDown)
+	}
+	clear(s.open)
+}
+
+func (s *Streams) add(d *deadline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		d.cancel(errShuttingDown)
+		return
+	}
+	s.open[d] = struct{}{}
+}
+
+func (s *Streams) remove(d *deadline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.open, d)
+}
+
+// Deadline cancels the request context once the request has run longer than
+// read (GET and HEAD) or write (other methods), and responds with 504.
+// A zero duration disables the deadline. Routes can adjust it with Timeout.
+// Requests that turn out to be streams are ended by streams instead.
+func Deadline(read, write time.Duration, streams *Streams) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithCancelCause(c.Request().Context())
+			defer cancel(nil)
+
+			timeout := write
+			if m := c.Request().Method; m == http.MethodGet || m == http.MethodHead {
+				timeout = read
+			}
+
+			d := &deadline{cancel: cancel, streams: streams}
+			d.reset(timeout)
+			defer d.stop()
+			defer streams.remove(d)
+
+			c.Set(contextKeyDeadline, d)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if errors.Is(context.Cause(ctx), errDeadlineExceeded) {
+				return errDeadlineExceeded
+			}
+			return err
+		}
+	}
+}
+
+// Timeout is route middleware that replaces the request deadline set by
+// Deadline, e.g. to allow slow imports more time. Zero removes it.
+func Timeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if d, ok := c.Get(contextKeyDeadline).(*deadline); ok {
+				d.reset(timeout)
+			}
+			return next(c)
+		}
+	}
+}
+
+// clearDeadline removes the request deadline. Streaming handlers call it
+// once they start writing, since their duration depends on the data size.
+// The request context is then cancelled when the server shuts down.
+func clearDeadline(c echo.Context) {
+	if d, ok := c.Get(contextKeyDeadline).(*deadline); ok {
+		d.stop()
+		d.streams.add(d)
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestStreamsClose(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    bool
+		wantCause error
+	}{
+		{name: "stream", stream: true, wantCause: errShuttingDown},
+		{name: "plain request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams := NewStreams()
+			var cause error
+			h := func(c echo.Context) error {
+				if tt.stream {
+					clearDeadline(c)
+				}
+				streams.Close()
+				ctx := c.Request().Context()
+				select {
+				case <-ctx.Done():
+				case <-time.After(20 * time.Millisecond):
+				}
+				cause = context.Cause(ctx)
+				return nil
+			}
+
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+			if err := Deadline(time.Minute, time.Minute, streams)(h)(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if !errors.Is(cause, tt.wantCause) {
+				t.Errorf("context cause = %v, want %v", cause, tt.wantCause)
+			}
+		})
+	}
+}
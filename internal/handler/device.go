@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// DeviceHandler handles push device registration endpoints.
+type DeviceHandler struct {
+	devices *service.DeviceService
+}
+
+// NewDeviceHandler creates a new DeviceHandler.
+func NewDeviceHandler(devices *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{devices: devices}
+}
+
+// registerDeviceRequest is the request body for registering a device.
+type registerDeviceRequest struct {
+	Platform domain.PushPlatform `json:"platform" validate:"required,oneof=fcm apns"`
+	Token    string              `json:"token" validate:"required"`
+}
+
+// Register registers a device of the caller for push notifications.
+func (h *DeviceHandler) Register(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body registerDeviceRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	device, err := h.devices.Register(c.Request().Context(), userID, body.Platform, body.Token)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, device)
+}
+
+// List returns the caller's registered devices.
+func (h *DeviceHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	devices, err := h.devices.List(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, devices)
+}
+
+// Unregister stops push notifications to one of the caller's devices.
+func (h *DeviceHandler) Unregister(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	id, err := pathID(c, "did")
+	if err != nil {
+		return err
+	}
+
+	if err := h.devices.Unregister(c.Request().Context(), userID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/metrics"
+)
+
+// DiagnosticsHandler serves operational state for debugging.
+type DiagnosticsHandler struct {
+	pool func() metrics.PoolStats
+}
+
+// NewDiagnosticsHandler creates a new DiagnosticsHandler.
+func NewDiagnosticsHandler(pool func() metrics.PoolStats) *DiagnosticsHandler {
+	return &DiagnosticsHandler{pool: pool}
+}
+
+// DBPool returns the current database connection pool state.
+func (h *DiagnosticsHandler) DBPool(c echo.Context) error {
+	return JSON(c, http.StatusOK, h.pool())
+}
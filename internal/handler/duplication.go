@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// DuplicationHandler handles project duplication endpoints.
+type DuplicationHandler struct {
+	duplications *service.DuplicationService
+}
+
+// NewDuplicationHandler creates a new DuplicationHandler.
+func NewDuplicationHandler(duplications *service.DuplicationService) *DuplicationHandler {
+	return &DuplicationHandler{duplications: duplications}
+}
+
+// duplicateProjectRequest is the request body for duplicating a project.
+type duplicateProjectRequest struct {
+	Name          string  `json:"name" validate:"required,max=200"`
+	Key           *string `json:"key"`
+	IncludeIssues bool    `json:"include_issues"`
+}
+
+// duplicationResponse is a project duplication with its progress.
+type duplicationResponse struct {
+	*domain.ProjectDuplication
+	Progress float64 `json:"progress"`
+}
+
+// duplicateProjectResponse is a duplicated project and, while its issues are
+// being copied in the background, the duplication doing so.
+type duplicateProjectResponse struct {
+	Project     *domain.Project      `json:"project"`
+	Duplication *duplicationResponse `json:"duplication,omitempty"`
+}
+
+// Duplicate copies the project in the path into a new project. It responds
+// 202 Accepted when the project's issues are still being copied.
+func (h *DuplicationHandler) Duplicate(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body duplicateProjectRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	project, duplication, err := h.duplications.Duplicate(c.Request().Context(), userID, projectID, body.Name, body.Key, body.IncludeIssues)
+	if err != nil {
+		return err
+	}
+	if duplication == nil {
+		return JSON(c, http.StatusCreated, duplicateProjectResponse{Project: project})
+	}
+	return JSON(c, http.StatusAccepted, duplicateProjectResponse{
+		Project:     project,
+		Duplication: &duplicationResponse{duplication, duplication.Progress()},
+	})
+}
+
+// Duplication returns the progress of copying issues into the project in the
+// path.
+func (h *DuplicationHandler) Duplication(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	duplication, err := h.duplications.Duplication(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, duplicationResponse{duplication, duplication.Progress()})
+}
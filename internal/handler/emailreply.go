@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// EmailReplyHandler handles email forwarded by the inbound mail relay.
+type EmailReplyHandler struct {
+	replies *service.EmailReplyService
+}
+
+// NewEmailReplyHandler creates a new EmailReplyHandler.
+func NewEmailReplyHandler(replies *service.EmailReplyService) *EmailReplyHandler {
+	return &EmailReplyHandler{replies: replies}
+}
+
+// inboundEmailRequest is an email as posted by the mail relay.
+type inboundEmailRequest struct {
+	From string   `json:"from" validate:"required"`
+	To   []string `json:"to" validate:"required,min=1"`
+	Text string   `json:"text"`
+}
+
+// Receive posts a reply to a notification email as a comment.
+func (h *EmailReplyHandler) Receive(c echo.Context) error {
+	var body inboundEmailRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	comment, err := h.replies.Receive(c.Request().Context(), service.InboundEmail{
+		From: body.From,
+		To:   body.To,
+		Text: body.Text,
+	})
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, comment)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// EmbeddingHandler handles issue embedding administration endpoints.
+type EmbeddingHandler struct {
+	embeddings *service.EmbeddingService
+}
+
+// NewEmbeddingHandler creates a new EmbeddingHandler.
+func NewEmbeddingHandler(embeddings *service.EmbeddingService) *EmbeddingHandler {
+	return &EmbeddingHandler{embeddings: embeddings}
+}
+
+// backfillResponse is an embedding backfill with its progress.
+type backfillResponse struct {
+	*domain.EmbeddingBackfill
+	Progress float64 `json:"progress"`
+}
+
+// StartBackfill starts computing embeddings for every existing issue.
+func (h *EmbeddingHandler) StartBackfill(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	backfill, err := h.embeddings.StartBackfill(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusAccepted, backfillResponse{backfill, backfill.Progress()})
+}
+
+// Backfill returns the most recent backfill and its progress.
+func (h *EmbeddingHandler) Backfill(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	backfill, err := h.embeddings.Backfill(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, backfillResponse{backfill, backfill.Progress()})
+}
+
+// CancelBackfill stops the running backfill.
+func (h *EmbeddingHandler) CancelBackfill(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	backfill, err := h.embeddings.CancelBackfill(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, backfillResponse{backfill, backfill.Progress()})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// FreezeHandler handles project freeze window endpoints.
+type FreezeHandler struct {
+	freezes *service.FreezeService
+}
+
+// NewFreezeHandler creates a new FreezeHandler.
+func NewFreezeHandler(freezes *service.FreezeService) *FreezeHandler {
+	return &FreezeHandler{freezes: freezes}
+}
+
+// createFreezeRequest is the request body for scheduling a freeze window.
+type createFreezeRequest struct {
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+	Reason   string    `json:"reason" validate:"max=500"`
+}
+
+// Create schedules a freeze window for the project in the path.
+func (h *FreezeHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createFreezeRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	window, err := h.freezes.Create(c.Request().Context(), userID, projectID, domain.FreezeWindow{
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+		Reason:   body.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, window)
+}
+
+// List returns the project's current and upcoming freeze windows.
+func (h *FreezeHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	windows, err := h.freezes.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, windows)
+}
+
+// Delete cancels the freeze window in the path.
+func (h *FreezeHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	freezeID, err := pathID(c, "fid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.freezes.Delete(c.Request().Context(), userID, projectID, freezeID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// ImportHandler handles bulk import endpoints.
+type ImportHandler struct {
+	imports *service.ImportService
+}
+
+// NewImportHandler creates a new ImportHandler.
+func NewImportHandler(imports *service.ImportService) *ImportHandler {
+	return &ImportHandler{imports: imports}
+}
+
+// importIssuesRequest is the request body for importing issues.
+type importIssuesRequest struct {
+	Issues []importIssue `json:"issues" validate:"required,min=1,dive"`
+}
+
+type importIssue struct {
+	Title      string             `json:"title" validate:"required,max=500"`
+	Body       *string            `json:"body" validate:"omitempty,max=65536"`
+	Status     domain.IssueStatus `json:"status" validate:"omitempty,oneof=open in_progress completed closed"`
+	AssigneeID *int64             `json:"assignee_id" validate:"omitempty,gt=0"`
+}
+
+// importIssuesResponse reports the outcome of an import.
+type importIssuesResponse struct {
+	Imported int64 `json:"imported"`
+}
+
+// Issues bulk-creates issues in a project.
+func (h *ImportHandler) Issues(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body importIssuesRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	issues := make([]domain.Issue, len(body.Issues))
+	for i, in := range body.Issues {
+		issues[i] = domain.Issue{
+			Title:      in.Title,
+			Body:       in.Body,
+			Status:     in.Status,
+			AssigneeID: in.AssigneeID,
+		}
+	}
+
+	n, err := h.imports.Import(c.Request().Context(), userID, projectID, issues)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, importIssuesResponse{Imported: n})
+}
@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+// IssueHandler handles issue endpoints.
+type IssueHandler struct {
+	issues *service.IssueService
+}
+
+// NewIssueHandler creates a new IssueHandler.
+func NewIssueHandler(issues *service.IssueService) *IssueHandler {
+	return &IssueHandler{issues: issues}
+}
+
+// List returns a filtered, paginated list of issues in a project.
+func (h *IssueHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	projectID, err := pathID(c, "pid")
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseIssueFilter(c)
+	if err != nil {
+		return err
+	}
+
+	if wantsCSV(c) {
+		return h.exportCSV(c, userID, projectID, filter)
+	}
+
+	page, err := h.issues.List(c.Request().Context(), userID, projectID, filter)
+	if err != nil {
+		return err
+	}
+
+	return JSONList(c, http.StatusOK, append(page.Pinned, page.Issues...), pageMeta(page.HasNext, page.NextCursor))
+}
+
+var issueCSVHeader = []string{
+	"id", "project_id", "title", "status", "created_by", "assignee_id",
+	"pinned_at", "closed_at", "archived_at", "created_at", "updated_at",
+}
+
+// exportCSV streams every issue matching the filter as CSV.
+func (h *IssueHandler) exportCSV(c echo.Context, userID, projectID int64, filter domain.IssueFilter) error {
+	filename := fmt.Sprintf("project-%d-issues.csv", projectID)
+	return streamCSV(c, filename, issueCSVHeader, func(write func([]string) error) error {
+		return h.issues.Export(c.Request().Context(), userID, projectID, filter, func(i domain.Issue) error {
+			return write([]string{
+				csvInt(i.ID),
+				csvInt(i.ProjectID),
+				i.Title,
+				string(i.Status),
+				csvOptInt(i.CreatedBy),
+				csvOptInt(i.AssigneeID),
+				csvOptTime(i.PinnedAt),
+				csvOptTime(i.ClosedAt),
+				csvOptTime(i.ArchivedAt),
+				csvTime(i.CreatedAt),
+				csvTime(i.UpdatedAt),
+			})
+		})
+	})
+}
+
+// Create creates an issue in the project in the path.
+func (h *IssueHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body apiclient.CreateIssueRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+	status := domain.IssueStatus(body.Status)
+	if status != "" && !status.Valid() {
+		return &domain.ValidationError{Field: "status", Message: fmt.Sprintf("unknown status %q", body.Status)}
+	}
+
+	issue := domain.Issue{Title: body.Title, Body: body.Body, Status: status, AssigneeID: body.AssigneeID, Form: body.Form}
+	if body.Template != "" {
+		issue.Template = &body.Template
+	}
+	created, err := h.issues.Create(c.Request().Context(), userID, projectID, issue)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// Get returns the issue in the path.
+func (h *IssueHandler) Get(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	issue, err := h.issues.Get(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, issue)
+}
+
+// cloneIssueRequest is the request body for cloning an issue.
+type cloneIssueRequest struct {
+	ProjectID *int64 `json:"project_id" validate:"omitempty,gt=0"`
+}
+
+// Clone copies the issue in the path into a new issue, optionally in another
+// project.
+func (h *IssueHandler) Clone(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body cloneIssueRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	clone, err := h.issues.Clone(c.Request().Context(), userID, projectID, issueID, body.ProjectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, clone)
+}
+
+// Update partially updates an issue. It honors If-Unmodified-Since.
+func (h *IssueHandler) Update(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body apiclient.UpdateIssueRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+	status := (*domain.IssueStatus)(body.Status)
+	if status != nil && !status.Valid() {
+		return &domain.ValidationError{Field: "status", Message: fmt.Sprintf("unknown status %q", *status)}
+	}
+
+	patch := domain.IssuePatch{
+		Title:      body.Title,
+		Body:       body.Body,
+		Status:     status,
+		AssigneeID: body.AssigneeID,
+	}
+	issue, err := h.issues.Update(c.Request().Context(), userID, projectID, issueID, patch, preconditions(c))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, issue)
+}
+
+// SetStatus moves an issue to another status. Transitions the status
+// machine does not allow are rejected. It honors If-Unmodified-Since.
+func (h *IssueHandler) SetStatus(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body apiclient.UpdateIssueStatusRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+	status := domain.IssueStatus(body.Status)
+	if !status.Valid() {
+		return &domain.ValidationError{Field: "status", Message: fmt.Sprintf("unknown status %q", status)}
+	}
+
+	issue, err := h.issues.SetStatus(c.Request().Context(), userID, projectID, issueID, status, preconditions(c))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, issue)
+}
+
+// Delete moves an issue to its project's trash. It honors
+// If-Unmodified-Since.
+func (h *IssueHandler) Delete(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.issues.Delete(c.Request().Context(), userID, projectID, issueID, preconditions(c)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Pin pins an issue to the top of the project's issue list.
+func (h *IssueHandler) Pin(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.issues.Pin(c.Request().Context(), userID, projectID, issueID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unpin removes an issue from the project's pinned issues.
+func (h *IssueHandler) Unpin(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.issues.Unpin(c.Request().Context(), userID, projectID, issueID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// issueRoute extracts the caller and the project and issue IDs from the path.
+func issueRoute(c echo.Context) (userID, projectID, issueID int64, err error) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return 0, 0, 0, domain.ErrUnauthorized
+	}
+	if projectID, err = pathID(c, "pid"); err != nil {
+		return 0, 0, 0, err
+	}
+	if issueID, err = pathID(c, "id"); err != nil {
+		return 0, 0, 0, err
+	}
+	return userID, projectID, issueID, nil
+}
+
+// parseIssueFilter reads issue list filters from the query string.
+// Repeated status parameters are combined with OR semantics; labels, given
+// comma-separated or repeated, must all be present unless label_mode is
+// any, in which case one of them must be. Every invalid
+// parameter is reported as a field error naming that parameter.
+func parseIssueFilter(c echo.Context) (domain.IssueFilter, error) {
+	p := newQueryParser(c)
+
+	var f domain.IssueFilter
+	for _, s := range p.values("status") {
+		status := domain.IssueStatus(s)
+		if !status.Valid() {
+			p.fail("status", fmt.Sprintf("unknown status %q", s))
+			continue
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range p.values("labels") {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				f.Labels = append(f.Labels, name)
+			}
+		}
+	}
+
+	for _, v := range p.values("label_mode") {
+		mode := domain.LabelMode(v)
+		if !mode.Valid() {
+			p.fail("label_mode", "must be all or any")
+			continue
+		}
+		f.LabelMode = mode
+	}
+
+	f.AssigneeID = p.int64("assignee")
+	f.MilestoneID = p.int64("milestone")
+	f.CreatedBy = p.int64("creator")
+	f.CreatedAfter = p.time("created_after")
+	f.CreatedBefore = p.time("created_before")
+	f.UpdatedAfter = p.time("updated_after")
+	f.UpdatedBefore = p.time("updated_before")
+
+	for _, v := range p.values("pinned") {
+		pinned, err := strconv.ParseBool(v)
+		if err != nil {
+			p.fail("pinned", "must be true or false")
+			continue
+		}
+		f.Pinned = &pinned
+	}
+
+	for _, v := range p.values("archived") {
+		archived, err := strconv.ParseBool(v)
+		if err != nil {
+			p.fail("archived", "must be true or false")
+			continue
+		}
+		f.Archived = archived
+	}
+
+	for _, h := range p.values("has") {
+		switch h {
+		case "ai_result":
+			f.HasAIResult = true
+		default:
+			p.fail("has", fmt.Sprintf("unknown value %q", h))
+		}
+	}
+
+	page := p.page()
+	f.Cursor = p.listPosition(page)
+	f.Limit = page.Limit
+
+	return f, p.err()
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// LabelHandler handles label endpoints.
+type LabelHandler struct {
+	labels *service.LabelService
+}
+
+// NewLabelHandler creates a new LabelHandler.
+func NewLabelHandler(labels *service.LabelService) *LabelHandler {
+	return &LabelHandler{labels: labels}
+}
+
+// List returns the labels of the project in the path.
+func (h *LabelHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	labels, err := h.labels.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, labels)
+}
+
+// ListForIssue returns the labels applied to the issue in the path.
+func (h *LabelHandler) ListForIssue(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	labels, err := h.labels.ListForIssue(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, labels)
+}
+
+// createLabelRequest is the request body for creating a label.
+type createLabelRequest struct {
+	Name       string `json:"name" validate:"required,max=50"`
+	Color      string `json:"color" validate:"required,hexcolor"`
+	Restricted bool   `json:"restricted"`
+}
+
+// Create adds a label to the project in the path.
+func (h *LabelHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createLabelRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	label := domain.Label{Name: body.Name, Color: body.Color, Restricted: body.Restricted}
+	created, err := h.labels.Create(c.Request().Context(), userID, projectID, label)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// updateLabelRequest is the request body for partially updating a label.
+type updateLabelRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// Update renames or recolors the label in the path.
+func (h *LabelHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	var body updateLabelRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	patch := domain.LabelPatch{Name: body.Name, Color: body.Color}
+	label, err := h.labels.Update(c.Request().Context(), userID, projectID, labelID, patch)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, label)
+}
+
+// renameLabelRequest is the request body for renaming a label.
+type renameLabelRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// Rename renames the label in the path, with an audit entry.
+func (h *LabelHandler) Rename(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	var body renameLabelRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	label, err := h.labels.Rename(c.Request().Context(), userID, projectID, labelID, body.Name)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, label)
+}
+
+// mergeLabelRequest is the request body for merging a label into another.
+type mergeLabelRequest struct {
+	Into int64 `json:"into" validate:"required"`
+}
+
+// Merge moves the issues of the label in the path to the label given in
+// the body and deletes the label in the path.
+func (h *LabelHandler) Merge(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	var body mergeLabelRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	merge, err := h.labels.Merge(c.Request().Context(), userID, projectID, labelID, body.Into)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, merge)
+}
+
+// Delete removes the label in the path from the project and its issues.
+func (h *LabelHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.labels.Delete(c.Request().Context(), userID, projectID, labelID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Restrict limits the label in the path to project admins.
+func (h *LabelHandler) Restrict(c echo.Context) error {
+	return h.setRestricted(c, true)
+}
+
+// Unrestrict lets any project member apply the label in the path.
+func (h *LabelHandler) Unrestrict(c echo.Context) error {
+	return h.setRestricted(c, false)
+}
+
+func (h *LabelHandler) setRestricted(c echo.Context, restricted bool) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	label, err := h.labels.SetRestricted(c.Request().Context(), userID, projectID, labelID, restricted)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, label)
+}
+
+// Attach applies the label in the path to the issue in the path.
+func (h *LabelHandler) Attach(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.labels.Attach(c.Request().Context(), userID, projectID, issueID, labelID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Detach removes the label in the path from the issue in the path.
+func (h *LabelHandler) Detach(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+	labelID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.labels.Detach(c.Request().Context(), userID, projectID, issueID, labelID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// LabelSyncHandler handles organization label sync endpoints.
+type LabelSyncHandler struct {
+	syncs *service.LabelSyncService
+}
+
+// NewLabelSyncHandler creates a new LabelSyncHandler.
+func NewLabelSyncHandler(syncs *service.LabelSyncService) *LabelSyncHandler {
+	return &LabelSyncHandler{syncs: syncs}
+}
+
+// startLabelSyncRequest is the request body for starting a label sync.
+// Without project IDs every project of the organization is synced.
+type startLabelSyncRequest struct {
+	ProjectIDs []int64 `json:"project_ids" validate:"max=1000"`
+	Prune      bool    `json:"prune"`
+}
+
+// Start begins pushing the organization's default labels to its projects
+// and responds 202 Accepted with the running sync.
+func (h *LabelSyncHandler) Start(c echo.Context) error {
+	userID, orgID, err := organizationRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body startLabelSyncRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	sync, err := h.syncs.Start(c.Request().Context(), userID, orgID, body.ProjectIDs, body.Prune)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusAccepted, sync)
+}
+
+// List returns the organization's label syncs, newest first.
+func (h *LabelSyncHandler) List(c echo.Context) error {
+	userID, orgID, err := organizationRoute(c)
+	if err != nil {
+		return err
+	}
+
+	syncs, err := h.syncs.List(c.Request().Context(), userID, orgID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, syncs)
+}
+
+// Get returns a label sync with its report of the changes in each project.
+func (h *LabelSyncHandler) Get(c echo.Context) error {
+	userID, orgID, err := organizationRoute(c)
+	if err != nil {
+		return err
+	}
+	syncID, err := pathID(c, "sid")
+	if err != nil {
+		return err
+	}
+
+	sync, err := h.syncs.Get(c.Request().Context(), userID, orgID, syncID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, sync)
+}
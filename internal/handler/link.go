@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// IssueLinkHandler handles issue link and issue graph endpoints.
+type IssueLinkHandler struct {
+	links *service.IssueLinkService
+}
+
+// NewIssueLinkHandler creates a new IssueLinkHandler.
+func NewIssueLinkHandler(links *service.IssueLinkService) *IssueLinkHandler {
+	return &IssueLinkHandler{links: links}
+}
+
+// List returns the links from and to the issue in the path.
+func (h *IssueLinkHandler) List(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	links, err := h.links.List(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, links)
+}
+
+// createIssueLinkRequest is the request body for linking an issue to
+// another: the issue blocks the target, relates to it, or is its parent.
+type createIssueLinkRequest struct {
+	Type     domain.IssueLinkType `json:"type" validate:"required,oneof=blocks relates parent"`
+	TargetID int64                `json:"target_id" validate:"required,gt=0"`
+}
+
+// Create links the issue in the path to another issue of its project.
+func (h *IssueLinkHandler) Create(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createIssueLinkRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	link, err := h.links.Create(c.Request().Context(), userID, projectID, issueID, body.Type, body.TargetID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, link)
+}
+
+// Delete removes the link in the path from or to the issue in the path.
+func (h *IssueLinkHandler) Delete(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+	linkID, err := pathID(c, "lid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.links.Delete(c.Request().Context(), userID, projectID, issueID, linkID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Graph returns the issue graph of the project in the path, optionally
+// around a root issue and limited to issues with some statuses.
+func (h *IssueLinkHandler) Graph(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	q, err := parseGraphQuery(c)
+	if err != nil {
+		return err
+	}
+
+	graph, err := h.links.Graph(c.Request().Context(), userID, projectID, q)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, graph)
+}
+
+// parseGraphQuery reads the root, depth and status parameters of an issue
+// graph request. Repeated status parameters are combined with OR semantics.
+func parseGraphQuery(c echo.Context) (domain.GraphQuery, error) {
+	p := newQueryParser(c)
+
+	var q domain.GraphQuery
+	q.RootID = p.int64("root")
+	if depth := p.int64("depth"); depth != nil {
+		switch {
+		case q.RootID == nil:
+			p.fail("depth", "requires root")
+		case *depth < 1:
+			p.fail("depth", "must be at least 1")
+		default:
+			q.Depth = int(*depth)
+		}
+	}
+	for _, s := range p.values("status") {
+		status := domain.IssueStatus(s)
+		if !status.Valid() {
+			p.fail("status", fmt.Sprintf("unknown status %q", s))
+			continue
+		}
+		q.Statuses = append(q.Statuses, status)
+	}
+	return q, p.err()
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+const (
+	// liveWriteTimeout bounds how long one message may take to reach a
+	// client before the connection is dropped.
+	liveWriteTimeout = 10 * time.Second
+	// maxLiveMessage bounds what clients may send. They have nothing to say
+	// beyond closing the connection.
+	maxLiveMessage = 4 << 10
+)
+
+// Messages the WebSocket sends besides project events.
+const (
+	// liveReady is sent first, with the IDs of the projects watched.
+	liveReady domain.RealtimeEventType = "ready"
+	// liveHeartbeat keeps idle connections open through proxies.
+	liveHeartbeat domain.RealtimeEventType = "heartbeat"
+)
+
+// liveMessage is one message on the WebSocket.
+type liveMessage struct {
+	Type      domain.RealtimeEventType `json:"type"`
+	ProjectID int64                    `json:"project_id,omitempty"`
+	Data      any                      `json:"data,omitempty"`
+}
+
+// LiveHandler pushes changes in the caller's projects over a WebSocket.
+type LiveHandler struct {
+	live *service.LiveService
+}
+
+// NewLiveHandler creates a new LiveHandler.
+func NewLiveHandler(live *service.LiveService) *LiveHandler {
+	return &LiveHandler{live: live}
+}
+
+// Serve upgrades the request to a WebSocket that sends a ready message and
+// then issue.changed, comment.added and ai.progress events of every project
+// the caller belongs to, as JSON text messages. It is exempt from the
+// request deadline.
+//
+// Each connection has a bounded queue. A client that falls behind, or
+// joins or leaves a project, is disconnected; clients reconnect and refetch
+// what they show.
+func (h *LiveHandler) Serve(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	if !c.IsWebSocket() {
+		return fmt.Errorf("%w: expected a websocket upgrade", domain.ErrInvalidInput)
+	}
+
+	ctx := c.Request().Context()
+	projectIDs, events, stop, err := h.live.Watch(ctx, userID)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	clearDeadline(c)
+
+	server := websocket.Server{
+		// Connections are authenticated by token, never by cookie, so
+		// cross-origin pages gain nothing and any origin is accepted.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			h.serve(ctx, ws, userID, projectIDs, events)
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+func (h *LiveHandler) serve(ctx context.Context, ws *websocket.Conn, userID int64, projectIDs []int64, events <-chan domain.RealtimeEvent) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = maxLiveMessage
+
+	// Reading is how a closed connection is noticed.
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var msg []byte
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+	}()
+
+	send := func(m liveMessage) bool {
+		if err := ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout)); err != nil {
+			return false
+		}
+		return websocket.JSON.Send(ws, m) == nil
+	}
+	if !send(liveMessage{Type: liveReady, Data: map[string][]int64{"project_ids": projectIDs}}) {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-gone:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if !send(liveMessage{Type: e.Type, ProjectID: e.ProjectID, Data: json.RawMessage(e.Data)}) {
+				return
+			}
+		case <-heartbeat.C:
+			current, err := h.live.Projects(ctx, userID)
+			if err != nil {
+				slog.Error("failed to check live projects", "user_id", userID, "error", err)
+			} else if !slices.Equal(current, projectIDs) {
+				return
+			}
+			if !send(liveMessage{Type: liveHeartbeat}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// MemberHandler handles project member endpoints.
+type MemberHandler struct {
+	members *service.MemberService
+}
+
+// NewMemberHandler creates a new MemberHandler.
+func NewMemberHandler(members *service.MemberService) *MemberHandler {
+	return &MemberHandler{members: members}
+}
+
+// addMembersRequest is the request body for adding members in bulk.
+type addMembersRequest struct {
+	Emails        []string           `json:"emails" validate:"max=500,dive,email"`
+	Role          domain.ProjectRole `json:"role"`
+	FromProjectID *int64             `json:"from_project_id" validate:"omitempty,gt=0"`
+}
+
+// removeMembersRequest is the request body for removing members in bulk.
+type removeMembersRequest struct {
+	Emails []string `json:"emails" validate:"required,max=500,dive,email"`
+}
+
+// Add adds members to the project in the path by email and by copying
+// another project's members, and returns a result per entry.
+func (h *MemberHandler) Add(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body addMembersRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+	if len(body.Emails) == 0 && body.FromProjectID == nil {
+		return &domain.ValidationError{Field: "emails", Message: "emails or from_project_id is required"}
+	}
+
+	results, err := h.members.Add(c.Request().Context(), userID, projectID, body.Emails, body.Role, body.FromProjectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, results)
+}
+
+// Remove removes members from the project in the path by email and returns a
+// result per entry.
+func (h *MemberHandler) Remove(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body removeMembersRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	results, err := h.members.Remove(c.Request().Context(), userID, projectID, body.Emails)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, results)
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// routedMethods are the methods checked when building an Allow header.
+var routedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// MethodSupport answers OPTIONS requests and unsupported methods with an
+// Allow header listing the methods routed for the path, and serves HEAD
+// requests with the matching GET handler, sending its status and headers with
+// an accurate Content-Length but no body. A handler that flushes is streaming:
+// its headers are sent at the first flush and its context is cancelled, so
+// HEAD on an event stream returns at once rather than holding the request
+// open until the stream ends. CORS preflight requests are left to
+// the CORS middleware. It must be registered with Echo.Pre so it runs before
+// routing; group middleware otherwise turns method mismatches into 404s.
+func MethodSupport() echo.MiddlewareFunc {
+	var (
+		once   sync.Once
+		routes map[string]bool
+	)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			e := c.Echo()
+			once.Do(func() { routes = routeSet(e) })
+
+			req := c.Request()
+			method := req.Method
+			if method == http.MethodHead {
+				method = http.MethodGet
+			}
+
+			if !isPreflight(req) && !routes[routeKey(method, routePath(e, method, req))] {
+				if allow := allowedMethods(e, routes, req); allow != "" {
+					c.Response().Header().Set(echo.HeaderAllow, allow)
+					if req.Method == http.MethodOptions || req.Method == http.MethodHead {
+						return c.NoContent(statusForMethod(req.Method))
+					}
+					return echo.ErrMethodNotAllowed
+				}
+			}
+
+			if req.Method != http.MethodHead {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithCancel(req.Context())
+			defer cancel()
+			req.Method = http.MethodGet
+			c.SetRequest(req.WithContext(ctx))
+			res := c.Response()
+			hw := &headWriter{ResponseWriter: res.Writer, cancel: cancel}
+			res.Writer = hw
+
+			// Errors must be rendered before the deferred header is sent, so
+			// they are handled here rather than by Echo after Pre middleware.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			hw.finish()
+			return nil
+		}
+	}
+}
+
+func statusForMethod(method string) int {
+	if method == http.MethodOptions {
+		return http.StatusNoContent
+	}
+	return http.StatusMethodNotAllowed
+}
+
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// routeSet indexes the application's routes by method and path template.
+func routeSet(e *echo.Echo) map[string]bool {
+	routes := make(map[string]bool)
+	for _, r := range e.Routes() {
+		routes[routeKey(r.Method, r.Path)] = true
+	}
+	return routes
+}
+
+// routePath returns the route template the router would match for method,
+// which may be a not-found catch-all rather than a real route.
+func routePath(e *echo.Echo, method string, req *http.Request) string {
+	c := e.AcquireContext()
+	defer e.ReleaseContext(c)
+	c.Reset(req, nil)
+	e.Router().Find(method, echo.GetPath(req), c)
+	return c.Path()
+}
+
+// allowedMethods builds the Allow header value for the request path, or ""
+// if no method is routed for it.
+func allowedMethods(e *echo.Echo, routes map[string]bool, req *http.Request) string {
+	var allow []string
+	for _, m := range routedMethods {
+		if !routes[routeKey(m, routePath(e, m, req))] {
+			continue
+		}
+		allow = append(allow, m)
+		if m == http.MethodGet {
+			allow = append(allow, http.MethodHead)
+		}
+	}
+	if len(allow) == 0 {
+		return ""
+	}
+	return strings.Join(append(allow, http.MethodOptions), ", ")
+}
+
+// headWriter discards the body of a GET response, counting its length, and
+// holds back the status line until the handler finishes so the count can be
+// sent as Content-Length. A flush sends the status line straight away, with
+// no length, and cancels the handler.
+type headWriter struct {
+	http.ResponseWriter
+	cancel context.CancelFunc
+	status int
+	size   int64
+	sent   bool
+}
+
+func (w *headWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *headWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size += int64(len(b))
+	return len(b), nil
+}
+
+// Flush sends the headers of a streaming response, whose length is never
+// known, and stops the handler since nothing more needs to be generated.
+func (w *headWriter) Flush() {
+	if w.sent {
+		return
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.sent = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+	w.cancel()
+}
+
+func (w *headWriter) finish() {
+	if w.sent {
+		return
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	h := w.Header()
+	if h.Get(echo.HeaderContentLength) == "" && bodyAllowed(w.status) {
+		h.Set(echo.HeaderContentLength, strconv.FormatInt(w.size, 10))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMethodSupportHead(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       echo.HandlerFunc
+		wantType      string
+		wantLength    string
+		wantCancelled bool
+	}{
+		{
+			name: "plain response",
+			handler: func(c echo.Context) error {
+				return c.String(http.StatusOK, "hello")
+			},
+			wantType:   echo.MIMETextPlainCharsetUTF8,
+			wantLength: "5",
+		},
+		{
+			name: "event stream",
+			handler: func(c echo.Context) error {
+				res := c.Response()
+				res.Header().Set(echo.HeaderContentType, "text/event-stream")
+				res.WriteHeader(http.StatusOK)
+				res.Flush()
+				select {
+				case <-c.Request().Context().Done():
+					return nil
+				case <-time.After(time.Second):
+					return c.String(http.StatusOK, "timed out")
+				}
+			},
+			wantType:      "text/event-stream",
+			wantCancelled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Pre(MethodSupport())
+			var cancelled bool
+			e.GET("/r", func(c echo.Context) error {
+				err := tt.handler(c)
+				cancelled = c.Request().Context().Err() != nil
+				return err
+			})
+
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/r", nil))
+			if d := time.Since(start); d > 500*time.Millisecond {
+				t.Fatalf("HEAD took %v", d)
+			}
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := rec.Header().Get(echo.HeaderContentLength); got != tt.wantLength {
+				t.Errorf("Content-Length = %q, want %q", got, tt.wantLength)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("body = %q, want empty", rec.Body.String())
+			}
+			if cancelled != tt.wantCancelled {
+				t.Errorf("cancelled = %v, want %v", cancelled, tt.wantCancelled)
+			}
+		})
+	}
+}
@@ -1,18 +1,33 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"runtime/debug"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/metrics"
 	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/internal/slack"
 )
 
 const (
 	contextKeyUserID = "user_id"
+
+	// maxSlackBody bounds the requests Slack sends the app.
+	maxSlackBody = 1 << 20
 )
 
 // RequestLogger logs each HTTP request with structured fields.
@@ -36,11 +51,72 @@ func RequestLogger() echo.MiddlewareFunc {
 	}
 }
 
-// JWTAuth validates the Bearer token and injects the user ID into echo context.
-func JWTAuth(auth *service.AuthService) echo.MiddlewareFunc {
+// Tracing records a server span for each request of the named service,
+// continuing the trace of an incoming traceparent header. Handlers,
+// services and repositories receive it through the request context, so
+// their spans nest under it. Responses of 500 and above mark the span as
+// failed.
+func Tracing(service string) echo.MiddlewareFunc {
+	traced := otelecho.Middleware(service)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return traced(func(c echo.Context) error {
+			trace.SpanFromContext(c.Request().Context()).SetAttributes(
+				attribute.String("request_id", c.Response().Header().Get(echo.HeaderXRequestID)))
+			return next(c)
+		})
+	}
+}
+
+// Recover recovers from panics in downstream handlers, logs the stack trace,
+// and responds with the standard error envelope.
+func Recover() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				metrics.PanicsTotal.Inc()
+				slog.Error("panic recovered",
+					"panic", fmt.Sprint(r),
+					"method", c.Request().Method,
+					"path", c.Request().URL.Path,
+					"request_id", c.Response().Header().Get(echo.HeaderXRequestID),
+					"stack", string(debug.Stack()),
+				)
+
+				WriteError(c, fmt.Errorf("panic: %v", r))
+				err = nil
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// errReadOnlyToken is returned for requests that would change something
+// made with a read-scoped personal access token.
+var errReadOnlyToken = errors.New("read-only access token")
+
+// JWTAuth validates the Bearer token and injects the user ID into echo
+// context. The token is either a session JWT or a personal access token;
+// read-scoped access tokens may only make GET and HEAD requests. WebSocket
+// handshakes may pass the token in the access_token query parameter instead.
+func JWTAuth(auth *service.AuthService, tokens *service.AccessTokenService) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			header := c.Request().Header.Get("Authorization")
+			if header == "" && c.IsWebSocket() {
+				// Browsers cannot set headers on WebSocket handshakes.
+				if token := c.QueryParam("access_token"); token != "" {
+					header = "Bearer " + token
+				}
+			}
 			if header == "" {
 				return domain.ErrUnauthorized
 			}
@@ -50,12 +126,71 @@ func JWTAuth(auth *service.AuthService) echo.MiddlewareFunc {
 				return domain.ErrUnauthorized
 			}
 
-			userID, err := auth.ValidateToken(parts[1])
+			var userID int64
+			if strings.HasPrefix(parts[1], domain.AccessTokenPrefix) {
+				token, err := tokens.Authenticate(c.Request().Context(), parts[1])
+				if err != nil {
+					return err
+				}
+				if token.Scope == domain.AccessTokenScopeRead && !safeMethod(c.Request().Method) {
+					return errReadOnlyToken
+				}
+				userID = token.UserID
+			} else {
+				id, err := auth.ValidateToken(parts[1])
+				if err != nil {
+					return domain.ErrUnauthorized
+				}
+				userID = id
+			}
+
+			c.Set(contextKeyUserID, userID)
+
+			ctx := service.WithTrace(c.Request().Context(), service.Trace{
+				RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+				UserID:    userID,
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// safeMethod reports whether requests with method only read.
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// SlackSignature rejects requests not signed with the Slack app's signing
+// secret. The body is read to verify it and then restored for the handler.
+func SlackSignature(secret []byte) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			body, err := io.ReadAll(io.LimitReader(req.Body, maxSlackBody))
+			if err != nil {
+				return fmt.Errorf("%w: unreadable request body", domain.ErrInvalidInput)
+			}
+			err = slack.Verify(secret, req.Header.Get(slack.HeaderTimestamp), req.Header.Get(slack.HeaderSignature), body, time.Now())
 			if err != nil {
 				return domain.ErrUnauthorized
 			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			return next(c)
+		}
+	}
+}
 
-			c.Set(contextKeyUserID, userID)
+// BearerSecret rejects requests that do not carry secret as a bearer
+// token. It guards endpoints called by trusted relays rather than users.
+func BearerSecret(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !ok || !hmac.Equal([]byte(token), []byte(secret)) {
+				return domain.ErrUnauthorized
+			}
 			return next(c)
 		}
 	}
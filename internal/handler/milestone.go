@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// MilestoneHandler handles milestone endpoints.
+type MilestoneHandler struct {
+	milestones *service.MilestoneService
+}
+
+// NewMilestoneHandler creates a new MilestoneHandler.
+func NewMilestoneHandler(milestones *service.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{milestones: milestones}
+}
+
+// List returns the milestones of the project in the path.
+func (h *MilestoneHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	milestones, err := h.milestones.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, milestones)
+}
+
+// Get returns the milestone in the path.
+func (h *MilestoneHandler) Get(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	milestoneID, err := pathID(c, "mid")
+	if err != nil {
+		return err
+	}
+
+	milestone, err := h.milestones.Get(c.Request().Context(), userID, projectID, milestoneID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, milestone)
+}
+
+// createMilestoneRequest is the request body for creating a milestone.
+// DueDate is a date such as 2026-12-31.
+type createMilestoneRequest struct {
+	Title       string  `json:"title" validate:"required,max=100"`
+	Description string  `json:"description" validate:"max=5000"`
+	DueDate     *string `json:"due_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// Create adds a milestone to the project in the path.
+func (h *MilestoneHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createMilestoneRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	milestone := domain.Milestone{Title: body.Title, Description: body.Description, DueDate: parseDueDate(body.DueDate)}
+	created, err := h.milestones.Create(c.Request().Context(), userID, projectID, milestone)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// updateMilestoneRequest is the request body for partially updating a
+// milestone. An empty due_date removes the due date.
+type updateMilestoneRequest struct {
+	Title       *string                `json:"title" validate:"omitempty,min=1,max=100"`
+	Description *string                `json:"description" validate:"omitempty,max=5000"`
+	DueDate     *string                `json:"due_date" validate:"omitempty,datetime=2006-01-02"`
+	State       *domain.MilestoneState `json:"state" validate:"omitempty,oneof=open closed"`
+}
+
+// Update edits, closes or reopens the milestone in the path.
+func (h *MilestoneHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	milestoneID, err := pathID(c, "mid")
+	if err != nil {
+		return err
+	}
+
+	var body updateMilestoneRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	patch := domain.MilestonePatch{
+		Title:        body.Title,
+		Description:  body.Description,
+		DueDate:      parseDueDate(body.DueDate),
+		ClearDueDate: body.DueDate != nil && *body.DueDate == "",
+		State:        body.State,
+	}
+	milestone, err := h.milestones.Update(c.Request().Context(), userID, projectID, milestoneID, patch)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, milestone)
+}
+
+// Delete removes the milestone in the path.
+func (h *MilestoneHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	milestoneID, err := pathID(c, "mid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.milestones.Delete(c.Request().Context(), userID, projectID, milestoneID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Progress returns the open and closed issue counts of the milestone in
+// the path.
+func (h *MilestoneHandler) Progress(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	milestoneID, err := pathID(c, "mid")
+	if err != nil {
+		return err
+	}
+
+	progress, err := h.milestones.Progress(c.Request().Context(), userID, projectID, milestoneID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, progress)
+}
+
+// assignMilestoneRequest is the request body for putting an issue in a
+// milestone.
+type assignMilestoneRequest struct {
+	MilestoneID int64 `json:"milestone_id" validate:"required"`
+}
+
+// Assign puts the issue in the path in a milestone.
+func (h *MilestoneHandler) Assign(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body assignMilestoneRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	if err := h.milestones.Assign(c.Request().Context(), userID, projectID, issueID, body.MilestoneID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unassign takes the issue in the path out of its milestone.
+func (h *MilestoneHandler) Unassign(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.milestones.Unassign(c.Request().Context(), userID, projectID, issueID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseDueDate parses a due date already validated as YYYY-MM-DD. It
+// returns nil for a missing or empty date.
+func parseDueDate(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+	date, err := time.Parse(time.DateOnly, *s)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
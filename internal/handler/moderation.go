@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// ModerationHandler handles project moderation endpoints.
+type ModerationHandler struct {
+	moderation *service.ModerationService
+}
+
+// NewModerationHandler creates a new ModerationHandler.
+func NewModerationHandler(moderation *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderation: moderation}
+}
+
+// HideComment masks a comment in responses.
+func (h *ModerationHandler) HideComment(c echo.Context) error {
+	return h.setCommentHidden(c, true)
+}
+
+// UnhideComment restores a hidden comment.
+func (h *ModerationHandler) UnhideComment(c echo.Context) error {
+	return h.setCommentHidden(c, false)
+}
+
+func (h *ModerationHandler) setCommentHidden(c echo.Context, hidden bool) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	commentID, err := pathID(c, "cid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.moderation.SetCommentHidden(c.Request().Context(), userID, projectID, commentID, hidden); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// blockRequest is the request body for blocking a user.
+type blockRequest struct {
+	Reason *string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+// Block bars the user in the path from the project.
+func (h *ModerationHandler) Block(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	targetID, err := pathID(c, "uid")
+	if err != nil {
+		return err
+	}
+
+	var body blockRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	if err := h.moderation.Block(c.Request().Context(), userID, projectID, targetID, body.Reason); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unblock lifts the block on the user in the path.
+func (h *ModerationHandler) Unblock(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	targetID, err := pathID(c, "uid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.moderation.Unblock(c.Request().Context(), userID, projectID, targetID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListBlocks returns the users blocked from the project.
+func (h *ModerationHandler) ListBlocks(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := h.moderation.ListBlocks(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, blocks)
+}
+
+// reportRequest is the request body for reporting content.
+type reportRequest struct {
+	TargetType domain.AuditTarget `json:"target_type" validate:"required,oneof=issue comment"`
+	TargetID   int64              `json:"target_id" validate:"required,gt=0"`
+	Reason     string             `json:"reason" validate:"required,max=1000"`
+}
+
+// Report files a content report for moderator review.
+func (h *ModerationHandler) Report(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body reportRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	report, err := h.moderation.Report(c.Request().Context(), userID, projectID, body.TargetType, body.TargetID, body.Reason)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, report)
+}
+
+// ListReports returns the project's review queue, filtered by status (default open).
+func (h *ModerationHandler) ListReports(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	status := domain.ReportStatus(c.QueryParam("status"))
+	switch status {
+	case "":
+		status = domain.ReportStatusOpen
+	case domain.ReportStatusOpen, domain.ReportStatusResolved, domain.ReportStatusDismissed:
+	default:
+		return domain.ValidationErrors{{Field: "status", Message: fmt.Sprintf("unknown status %q", status)}}
+	}
+
+	page, err := h.moderation.ListReports(c.Request().Context(), userID, projectID, status, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Reports, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// resolveReportRequest is the request body for resolving a report.
+type resolveReportRequest struct {
+	Status domain.ReportStatus `json:"status" validate:"required,oneof=resolved dismissed"`
+}
+
+// ResolveReport closes a report as resolved or dismissed.
+func (h *ModerationHandler) ResolveReport(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	reportID, err := pathID(c, "rid")
+	if err != nil {
+		return err
+	}
+
+	var body resolveReportRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	report, err := h.moderation.ResolveReport(c.Request().Context(), userID, projectID, reportID, body.Status)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, report)
+}
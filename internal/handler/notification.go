@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// NotificationHandler handles notification endpoints.
+type NotificationHandler struct {
+	notifications *service.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(notifications *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// List returns a page of the caller's notifications, newest first. With
+// unread=true only unread notifications are returned. Snoozed notifications
+// are left out unless snoozed=true, which returns only them.
+func (h *NotificationHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+	filter := domain.NotificationFilter{Cursor: after, Limit: limit}
+	if v := c.QueryParam("unread"); v != "" {
+		if filter.UnreadOnly, err = strconv.ParseBool(v); err != nil {
+			return &domain.ValidationError{Field: "unread", Message: "must be true or false"}
+		}
+	}
+	if v := c.QueryParam("snoozed"); v != "" {
+		if filter.Snoozed, err = strconv.ParseBool(v); err != nil {
+			return &domain.ValidationError{Field: "snoozed", Message: "must be true or false"}
+		}
+	}
+
+	page, err := h.notifications.List(c.Request().Context(), userID, filter)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Notifications, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// MarkRead marks the notification in the path as read.
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	id, err := pathID(c, "nid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.notifications.MarkRead(c.Request().Context(), userID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MarkAllRead marks all of the caller's notifications as read.
+func (h *NotificationHandler) MarkAllRead(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	if _, err := h.notifications.MarkAllRead(c.Request().Context(), userID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// preferencesRequest is the request body for replacing notification
+// preferences. Omitting quiet_hours or do_not_disturb_until turns them off,
+// as omitting email turns off every email.
+type preferencesRequest struct {
+	Timezone          string                  `json:"timezone"`
+	QuietHours        *domain.QuietHours      `json:"quiet_hours"`
+	DoNotDisturbUntil *time.Time              `json:"do_not_disturb_until"`
+	Email             domain.EmailPreferences `json:"email"`
+}
+
+// Preferences returns the caller's notification preferences.
+func (h *NotificationHandler) Preferences(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	p, err := h.notifications.Preferences(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, p)
+}
+
+// UpdatePreferences replaces the caller's notification preferences. Email
+// and push notifications that fall in quiet hours or do-not-disturb are held
+// until it ends.
+func (h *NotificationHandler) UpdatePreferences(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body preferencesRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+
+	p, err := h.notifications.UpdatePreferences(c.Request().Context(), userID, domain.NotificationPreferences{
+		Timezone:          body.Timezone,
+		QuietHours:        body.QuietHours,
+		DoNotDisturbUntil: body.DoNotDisturbUntil,
+		Email:             body.Email,
+	})
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, p)
+}
+
+// maxSnooze is the longest a notification can be snoozed.
+const maxSnooze = 30 * 24 * time.Hour
+
+// snoozeRequest is the request body for snoozing a notification. Duration is
+// a Go duration string such as "30m" or "24h".
+type snoozeRequest struct {
+	Duration string `json:"duration" validate:"required"`
+}
+
+// Snooze hides the notification in the path until the duration elapses.
+func (h *NotificationHandler) Snooze(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	id, err := pathID(c, "nid")
+	if err != nil {
+		return err
+	}
+
+	var body snoozeRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(body.Duration)
+	if err != nil || d < time.Minute || d > maxSnooze {
+		return &domain.ValidationError{Field: "duration", Message: "must be a duration between 1m and 720h"}
+	}
+
+	n, err := h.notifications.Snooze(c.Request().Context(), userID, id, d)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, n)
+}
+
+// markReadUpToRequest is the request body for marking notifications read up
+// to a cursor.
+type markReadUpToRequest struct {
+	Cursor int64 `json:"cursor" validate:"required,gt=0"`
+}
+
+// MarkReadUpTo marks the caller's notifications with IDs up to and including
+// the cursor as read, leaving newer ones unread.
+func (h *NotificationHandler) MarkReadUpTo(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body markReadUpToRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	if _, err := h.notifications.MarkReadUpTo(c.Request().Context(), userID, body.Cursor); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// streamHeartbeat is how often an idle stream sends a comment, so proxies
+// keep the connection open.
+const streamHeartbeat = 30 * time.Second
+
+// Stream sends the caller's notification events as server-sent events,
+// starting with their unread count, until the client disconnects. Streams
+// are exempt from the request deadline. The stream ends if the client falls
+// behind; clients should reconnect, which resends the unread count.
+func (h *NotificationHandler) Stream(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	ctx := c.Request().Context()
+	unread, events, stop, err := h.notifications.Watch(ctx, userID)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	clearDeadline(c)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	count, err := json.Marshal(domain.UnreadCount{UnreadCount: unread})
+	if err != nil {
+		return err
+	}
+	if err := writeEvent(res, domain.RealtimeUnreadCount, count); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeEvent(res, e.Type, e.Data); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ":\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// writeEvent writes one server-sent event and flushes it to the client.
+func writeEvent(res *echo.Response, typ domain.RealtimeEventType, data json.RawMessage) error {
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", typ, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
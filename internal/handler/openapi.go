@@ -0,0 +1,436 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/openapi"
+	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+// DescribeAPI documents the request and response bodies of the routes
+// clients use most. Routes left out are still listed, with untyped bodies.
+func DescribeAPI(spec *openapi.Spec) {
+	spec.Public("/auth/google", "/auth/github", "/auth/refresh")
+	spec.Hide("/slack")
+	spec.Hide("/email")
+
+	spec.Describe(http.MethodPost, "/auth/refresh", openapi.Op{Request: refreshRequest{}, Response: service.TokenPair{}})
+	spec.Describe(http.MethodGet, "/auth/me", openapi.Op{Summary: "Current user", Response: domain.User{}})
+
+	spec.Describe(http.MethodGet, "/me/tokens", openapi.Op{Summary: "List personal access tokens", Response: []apiclient.AccessToken{}})
+	spec.Describe(http.MethodPost, "/me/tokens", openapi.Op{
+		Summary:     "Create a personal access token",
+		Description: "The token is only returned in this response.",
+		Request:     apiclient.CreateAccessTokenRequest{},
+		Response:    createdAccessToken{},
+		Status:      http.StatusCreated,
+	})
+	spec.Describe(http.MethodDelete, "/me/tokens/:tid", openapi.Op{Summary: "Revoke a personal access token"})
+
+	spec.Describe(http.MethodGet, "/projects", openapi.Op{Summary: "List projects", Response: domain.ProjectSummary{}, List: true})
+	spec.Describe(http.MethodPost, "/projects", openapi.Op{Summary: "Create a project", Request: createProjectRequest{}, Response: domain.Project{}, Status: http.StatusCreated})
+	spec.Describe(http.MethodGet, "/projects/:pid", openapi.Op{Summary: "Get a project", Response: domain.Project{}})
+	spec.Describe(http.MethodPatch, "/projects/:pid", openapi.Op{Summary: "Update a project", Request: updateProjectRequest{}, Response: domain.Project{}})
+	spec.Describe(http.MethodDelete, "/projects/:pid", openapi.Op{
+		Summary:     "Delete a project",
+		Description: "Moves the project to the owner's trash, from which it can be restored until it is purged.",
+	})
+	spec.Describe(http.MethodGet, "/trash", openapi.Op{Summary: "List your deleted projects", Response: []domain.Project{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/restore", openapi.Op{Summary: "Restore a deleted project", Response: domain.Project{}})
+	spec.Describe(http.MethodGet, "/projects/:pid/trash", openapi.Op{Summary: "List deleted issues", Response: []domain.Issue{}})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/labels", openapi.Op{Summary: "List labels", Response: []domain.Label{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/labels", openapi.Op{Summary: "Create a label", Request: createLabelRequest{}, Response: domain.Label{}, Status: http.StatusCreated})
+	spec.Describe(http.MethodPatch, "/projects/:pid/labels/:lid", openapi.Op{Summary: "Update a label", Request: updateLabelRequest{}, Response: domain.Label{}})
+	spec.Describe(http.MethodDelete, "/projects/:pid/labels/:lid", openapi.Op{Summary: "Delete a label"})
+	spec.Describe(http.MethodPost, "/projects/:pid/labels/:lid/rename", openapi.Op{
+		Summary:     "Rename a label",
+		Description: "Fails with 409 if another label has the name; merge the labels instead.",
+		Request:     renameLabelRequest{},
+		Response:    domain.Label{},
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/labels/:lid/merge", openapi.Op{
+		Summary:     "Merge a label into another",
+		Description: "Moves the label's issues to the label into and deletes it, atomically.",
+		Request:     mergeLabelRequest{},
+		Response:    domain.LabelMerge{},
+	})
+
+	spec.Describe(http.MethodPost, "/orgs/:oid/label-syncs", openapi.Op{
+		Summary: "Sync default labels to projects",
+		Description: "Pushes the organization's default labels to the listed projects, or all of its projects, in the background. " +
+			"Missing labels are created and differently colored ones recolored; prune deletes labels outside the defaults. Organization admins only.",
+		Request:  startLabelSyncRequest{},
+		Response: domain.LabelSync{},
+		Status:   http.StatusAccepted,
+	})
+	spec.Describe(http.MethodGet, "/orgs/:oid/label-syncs", openapi.Op{Summary: "List label syncs", Response: []domain.LabelSync{}})
+	spec.Describe(http.MethodGet, "/orgs/:oid/label-syncs/:sid", openapi.Op{Summary: "Get a label sync and its report", Response: domain.LabelSync{}})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/webhooks", openapi.Op{Summary: "List webhooks", Response: []domain.Webhook{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/webhooks", openapi.Op{
+		Summary:     "Create a webhook",
+		Description: "The signing secret is only returned in this response.",
+		Request:     createWebhookRequest{},
+		Response:    createdWebhook{},
+		Status:      http.StatusCreated,
+	})
+	spec.Describe(http.MethodPatch, "/projects/:pid/webhooks/:wid", openapi.Op{
+		Summary: "Update a webhook",
+		Description: "A retry policy replaces the current one; fields left out of it use the installation's defaults. " +
+			"Webhooks whose endpoint fails continuously for 24 hours are deactivated until they are updated to be active again.",
+		Request:  updateWebhookRequest{},
+		Response: domain.Webhook{},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/webhooks/:wid", openapi.Op{Summary: "Delete a webhook"})
+	spec.Describe(http.MethodGet, "/projects/:pid/notification-routes", openapi.Op{Summary: "List notification routing rules", Response: []domain.NotificationRoute{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/notification-routes", openapi.Op{
+		Summary: "Create a notification routing rule",
+		Description: "Notifications of the listed types on issues with any of the listed labels are also sent to the target email addresses " +
+			"and Slack channels. Empty types or labels match everything. Project admins only.",
+		Request:  createRouteRequest{},
+		Response: domain.NotificationRoute{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(http.MethodPatch, "/projects/:pid/notification-routes/:rid", openapi.Op{Summary: "Update a notification routing rule", Request: updateRouteRequest{}, Response: domain.NotificationRoute{}})
+	spec.Describe(http.MethodDelete, "/projects/:pid/notification-routes/:rid", openapi.Op{Summary: "Delete a notification routing rule"})
+	spec.Describe(http.MethodPost, "/projects/:pid/webhooks/:wid/test", openapi.Op{Summary: "Send a test event", Request: testWebhookRequest{}, Response: domain.WebhookTestResult{}})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/freezes", openapi.Op{Summary: "List current and upcoming freeze windows", Response: []domain.FreezeWindow{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/freezes", openapi.Op{
+		Summary: "Schedule a freeze window",
+		Description: "While a window is in effect only project admins may change issue statuses or run AI; " +
+			"others get 423 Locked with code project_frozen. Project admins only.",
+		Request:  createFreezeRequest{},
+		Response: domain.FreezeWindow{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/freezes/:fid", openapi.Op{Summary: "Cancel a freeze window"})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/milestones", openapi.Op{
+		Summary:  "List milestones",
+		Response: []domain.Milestone{},
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/milestones", openapi.Op{
+		Summary:     "Create a milestone",
+		Description: "Project admins only.",
+		Request:     createMilestoneRequest{},
+		Response:    domain.Milestone{},
+		Status:      http.StatusCreated,
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/milestones/:mid", openapi.Op{Summary: "Get a milestone", Response: domain.Milestone{}})
+	spec.Describe(http.MethodPatch, "/projects/:pid/milestones/:mid", openapi.Op{
+		Summary:     "Update, close or reopen a milestone",
+		Description: "An empty due_date removes the due date. Project admins only.",
+		Request:     updateMilestoneRequest{},
+		Response:    domain.Milestone{},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/milestones/:mid", openapi.Op{
+		Summary:     "Delete a milestone",
+		Description: "Its issues are left without a milestone. Project admins only.",
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/milestones/:mid/progress", openapi.Op{
+		Summary:     "Milestone progress",
+		Description: "Counts the milestone's issues; completed and closed issues count as closed.",
+		Response:    domain.MilestoneProgress{},
+	})
+	spec.Describe(http.MethodPut, "/projects/:pid/issues/:id/milestone", openapi.Op{
+		Summary:     "Put an issue in a milestone",
+		Description: "Moves the issue out of any other milestone. The milestone must be open.",
+		Request:     assignMilestoneRequest{},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/issues/:id/milestone", openapi.Op{Summary: "Take an issue out of its milestone"})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/release-notes", openapi.Op{
+		Summary: "Generate release notes",
+		Description: "Lists the issues completed in a milestone, or closed as completed between two dates, " +
+			"as Markdown with a section per label. With polish=true the AI backend rewrites the notes; " +
+			"if it fails, the plain notes are returned and X-Release-Notes-Polished is false.",
+		ContentType: mimeTextMarkdown,
+		Query: []openapi.Param{
+			{Name: "milestone_id", Description: "milestone whose completed issues to list", Type: "integer"},
+			{Name: "from", Description: "first day, such as 2026-01-01, in UTC"},
+			{Name: "to", Description: "last day, inclusive"},
+			{Name: "group", Description: "comma-separated labels to make sections of, in order; issues go under the first they carry. " +
+				"Defaults to every label", Repeated: true},
+			{Name: "polish", Description: "rewrite the notes with the AI backend", Type: "boolean"},
+		},
+	})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/graph", openapi.Op{
+		Summary: "Get the issue graph",
+		Description: "The project's linked issues as nodes and their blocks, relates and parent links as edges, from source to target. " +
+			"With a root, only issues within depth links of it in either direction. Truncated is set if the graph exceeded 500 issues.",
+		Response: domain.IssueGraph{},
+		Query: []openapi.Param{
+			{Name: "root", Description: "ID of the issue to build the graph around", Type: "integer"},
+			{Name: "depth", Description: "links to follow from the root, 1 to 10; defaults to 3", Type: "integer"},
+			{Name: "status", Description: "only issues with one of these statuses; the root is always included", Repeated: true},
+		},
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/wiki", openapi.Op{Summary: "List wiki pages", Response: []domain.WikiPageSummary{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/wiki", openapi.Op{
+		Summary: "Create a wiki page",
+		Description: "The body is Markdown. Without a slug, one is derived from the title. " +
+			"Issues and comments link to the page by writing [[slug]] or [[slug|text]].",
+		Request:  createWikiPageRequest{},
+		Response: domain.WikiPage{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/wiki/:slug", openapi.Op{
+		Summary:     "Get a wiki page",
+		Description: "Includes the project's issues that link to the page.",
+		Response:    domain.WikiPageDetail{},
+	})
+	spec.Describe(http.MethodPatch, "/projects/:pid/wiki/:slug", openapi.Op{
+		Summary: "Edit a wiki page",
+		Description: "Records the edit as a new revision. Send If-Unmodified-Since with the page's updated_at " +
+			"to fail with 412 if someone else edited it since it was read.",
+		Request:  updateWikiPageRequest{},
+		Response: domain.WikiPage{},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/wiki/:slug", openapi.Op{
+		Summary:     "Delete a wiki page",
+		Description: "Deletes the page with its revisions. Project admins only.",
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/wiki/:slug/revisions", openapi.Op{
+		Summary:  "List a wiki page's revisions",
+		Response: domain.WikiRevisionSummary{},
+		List:     true,
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/wiki/:slug/revisions/:rev", openapi.Op{Summary: "Get a wiki page revision", Response: domain.WikiRevision{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/wiki/:slug/revisions/:rev/restore", openapi.Op{
+		Summary:     "Restore a wiki page revision",
+		Description: "Makes the revision's title and body the page's content again, as a new revision.",
+		Response:    domain.WikiPage{},
+	})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/filters", openapi.Op{Summary: "List your saved filters", Response: []domain.SavedFilter{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/filters", openapi.Op{
+		Summary: "Save a filter",
+		Description: `The query is a space-separated list of terms such as status:open,in_progress label:bug label:"needs triage" ` +
+			`assignee:me milestone:3 crash. Keyed terms filter on status, label, assignee, creator and milestone; ` +
+			`other words and quoted phrases must appear in the title or body. Saved filters are private.`,
+		Request:  createSavedFilterRequest{},
+		Response: domain.SavedFilter{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/filters/:fid", openapi.Op{Summary: "Get a saved filter", Response: domain.SavedFilter{}})
+	spec.Describe(http.MethodPatch, "/projects/:pid/filters/:fid", openapi.Op{
+		Summary:  "Update a saved filter",
+		Request:  updateSavedFilterRequest{},
+		Response: domain.SavedFilter{},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/filters/:fid", openapi.Op{Summary: "Delete a saved filter"})
+	spec.Describe(http.MethodGet, "/projects/:pid/filters/:fid/issues", openapi.Op{
+		Summary:     "List issues matching a saved filter",
+		Description: "Pinned issues come first on the first page.",
+		Response:    domain.Issue{},
+		List:        true,
+	})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/issues", openapi.Op{
+		Summary:     "List issues",
+		Description: "Pinned issues come first on the first page. Send Accept: text/csv to export every matching issue.",
+		Response:    domain.Issue{},
+		List:        true,
+		Query: []openapi.Param{
+			{Name: "status", Description: "statuses to include", Repeated: true},
+			{Name: "labels", Description: "comma-separated labels the issues must have", Repeated: true},
+			{Name: "label_mode", Description: "all (default): issues have every label; any: issues have at least one"},
+			{Name: "assignee", Type: "integer"},
+			{Name: "milestone", Type: "integer"},
+			{Name: "creator", Type: "integer"},
+			{Name: "created_after", Description: "RFC 3339 time"},
+			{Name: "created_before", Description: "RFC 3339 time"},
+			{Name: "updated_after", Description: "RFC 3339 time"},
+			{Name: "updated_before", Description: "RFC 3339 time"},
+			{Name: "pinned", Type: "boolean"},
+			{Name: "archived", Type: "boolean"},
+			{Name: "has", Description: "ai_result", Repeated: true},
+		},
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues", openapi.Op{Summary: "Create an issue", Request: apiclient.CreateIssueRequest{}, Response: domain.Issue{}, Status: http.StatusCreated})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/search", openapi.Op{
+		Summary:  "Search issues",
+		Response: domain.Issue{},
+		List:     true,
+		Query:    []openapi.Param{{Name: "q", Description: "search query"}},
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/quick-search", openapi.Op{
+		Summary:  "Suggest issues",
+		Response: []domain.IssueSuggestion{},
+		Query:    []openapi.Param{{Name: "q", Description: "search query"}},
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id", openapi.Op{
+		Summary:     "Get an issue",
+		Description: "Includes the issues it references and that reference it, and the wiki pages it links to with [[slug]].",
+		Response:    domain.IssueDetail{},
+	})
+	spec.Describe(http.MethodPatch, "/projects/:pid/issues/:id", openapi.Op{
+		Summary:     "Update an issue",
+		Description: "Send If-Unmodified-Since with the issue's updated_at to fail with 412 if it changed since it was read.",
+		Request:     apiclient.UpdateIssueRequest{},
+		Response:    domain.Issue{},
+	})
+	spec.Describe(http.MethodPatch, "/projects/:pid/issues/:id/status", openapi.Op{
+		Summary:     "Change an issue's status",
+		Description: "Transitions the status machine does not allow fail with a validation error on status.",
+		Request:     apiclient.UpdateIssueStatusRequest{},
+		Response:    domain.Issue{},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/issues/:id", openapi.Op{
+		Summary:     "Delete an issue",
+		Description: "Moves the issue to the project's trash, from which it can be restored until it is purged.",
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/restore", openapi.Op{Summary: "Restore a deleted issue", Response: domain.Issue{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/clone", openapi.Op{Summary: "Clone an issue", Request: cloneIssueRequest{}, Response: domain.Issue{}, Status: http.StatusCreated})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/labels", openapi.Op{Summary: "List an issue's labels", Response: []domain.Label{}})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/close-requests", openapi.Op{Summary: "List close requests", Response: []domain.CloseRequest{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/close-requests", openapi.Op{
+		Summary: "Request an issue be closed",
+		Description: "In projects whose settings.close_approval names a role, only users with that role may close issues; " +
+			"others get 409 approval_required and request the close here. Approvers are notified.",
+		Request:  createCloseRequestRequest{},
+		Response: domain.CloseRequest{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/close-requests/approve", openapi.Op{
+		Summary:     "Approve the pending close request",
+		Description: "Moves the issue to the requested status on the approver's behalf.",
+		Request:     decideCloseRequestRequest{},
+		Response:    domain.CloseRequest{},
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/close-requests/reject", openapi.Op{
+		Summary:  "Reject the pending close request",
+		Request:  decideCloseRequestRequest{},
+		Response: domain.CloseRequest{},
+	})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/attachments", openapi.Op{Summary: "List attachments", Response: []domain.Attachment{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/attachments", openapi.Op{
+		Summary: "Attach a file",
+		Description: "Multipart form with the file in the file field. The type is detected from the contents; " +
+			"images, PDF, plain text, zip and gzip files up to the server's size limit are accepted. " +
+			"Image metadata such as EXIF and GPS position may be stripped, and smaller image variants made, " +
+			"depending on the server's configuration.",
+		Response: domain.Attachment{},
+		Status:   http.StatusCreated,
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/attachments/:aid/url", openapi.Op{
+		Summary:     "Get a download URL",
+		Description: "Returns a short-lived URL that downloads the file without further authentication.",
+		Response:    domain.SignedURL{},
+		Query:       []openapi.Param{{Name: "variant", Description: "name of an image variant to download instead of the file"}},
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/issues/:id/attachments/:aid", openapi.Op{Summary: "Delete an attachment"})
+
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/timeline", openapi.Op{
+		Summary:     "List an issue's timeline",
+		Description: "The issue's events, such as status changes, assignments, labels and AI runs, merged with its comments, oldest first.",
+		Response:    domain.TimelineItem{},
+		List:        true,
+	})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/links", openapi.Op{Summary: "List an issue's links", Response: []domain.IssueLink{}})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/links", openapi.Op{
+		Summary:     "Link an issue",
+		Description: "Links the issue to another issue of the project. Returns 409 if they are already linked, if a parent link's target already has a parent, or if a blocks or parent link would close a cycle.",
+		Request:     createIssueLinkRequest{},
+		Response:    domain.IssueLink{},
+		Status:      http.StatusCreated,
+	})
+	spec.Describe(http.MethodDelete, "/projects/:pid/issues/:id/links/:lid", openapi.Op{Summary: "Remove an issue link"})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/comments", openapi.Op{Summary: "List comments", Response: domain.Comment{}, List: true})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/comments", openapi.Op{Summary: "Comment on an issue", Request: apiclient.CreateCommentRequest{}, Response: domain.Comment{}, Status: http.StatusCreated})
+	spec.Describe(http.MethodDelete, "/projects/:pid/comments/:cid", openapi.Op{Summary: "Delete a comment"})
+
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/ai/run", openapi.Op{Summary: "Run AI on an issue", Request: apiclient.RunAIRequest{}, Response: domain.AIJob{}, Status: http.StatusAccepted})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/ai/review", openapi.Op{Summary: "Review a diff with AI", Request: reviewRequest{}, Response: domain.AIJob{}, Status: http.StatusAccepted})
+	spec.Describe(http.MethodPost, "/projects/:pid/issues/:id/ai/retry", openapi.Op{Summary: "Retry the last AI job", Response: domain.AIJob{}, Status: http.StatusAccepted})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/ai/runs", openapi.Op{Summary: "List AI runs", Response: domain.AIRun{}, List: true})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/ai/review-comments", openapi.Op{Summary: "List AI review comments", Response: domain.AIReviewComment{}, List: true})
+	spec.Describe(http.MethodGet, "/projects/:pid/issues/:id/ai/logs/stream", openapi.Op{
+		Summary: "Follow AI job output",
+		Description: "Server-sent events: a log event with each chunk of output and an end event with the finished job. " +
+			"Send Last-Event-ID to resume after a chunk.",
+		ContentType: "text/event-stream",
+		Query:       []openapi.Param{{Name: "job_id", Description: "defaults to the issue's latest job", Type: "integer"}},
+	})
+	spec.Describe(http.MethodPost, "/projects/:pid/ai-jobs/:jid/approve", openapi.Op{Summary: "Approve an AI job's changes", Response: domain.AIRun{}})
+	spec.Describe(http.MethodGet, "/projects/:pid/ai-jobs/:jid/artifacts", openapi.Op{Summary: "List AI job artifacts", Response: []domain.AIJobArtifact{}})
+	spec.Describe(http.MethodGet, "/projects/:pid/ai-jobs/:jid/artifacts/:aid/url", openapi.Op{
+		Summary:     "Get an artifact download URL",
+		Description: "Returns a short-lived URL that downloads the artifact without further authentication.",
+		Response:    domain.SignedURL{},
+	})
+
+	spec.Describe(http.MethodGet, "/notifications", openapi.Op{
+		Summary:  "List notifications",
+		Response: domain.Notification{},
+		List:     true,
+		Query:    []openapi.Param{{Name: "unread", Type: "boolean"}, {Name: "snoozed", Type: "boolean"}},
+	})
+	spec.Describe(http.MethodPost, "/notifications/:nid/snooze", openapi.Op{Summary: "Snooze a notification", Request: snoozeRequest{}, Response: domain.Notification{}})
+	spec.Describe(http.MethodPost, "/notifications/read-up-to", openapi.Op{Summary: "Mark notifications read up to one", Request: markReadUpToRequest{}})
+	spec.Describe(http.MethodGet, "/notifications/preferences", openapi.Op{Summary: "Get notification preferences", Response: domain.NotificationPreferences{}})
+	spec.Describe(http.MethodPut, "/notifications/preferences", openapi.Op{Summary: "Update notification preferences", Request: preferencesRequest{}, Response: domain.NotificationPreferences{}})
+
+	spec.Describe(http.MethodGet, "/ws", openapi.Op{
+		Summary: "Follow changes in your projects",
+		Description: "WebSocket. Sends a ready message with the project IDs watched, then issue.changed, comment.added " +
+			"and ai.progress events as JSON text messages. Browsers pass the token in access_token. " +
+			"Slow clients and clients whose memberships change are disconnected and should reconnect.",
+		Query: []openapi.Param{{Name: "access_token", Description: "bearer token, for clients that cannot set headers"}},
+	})
+}
+
+// OpenAPIHandler serves the API description and a browsable view of it.
+type OpenAPIHandler struct {
+	doc []byte
+}
+
+// NewOpenAPIHandler creates an OpenAPIHandler serving doc. The document is
+// encoded once, and without the response serializer, whose key rewriting
+// would mangle schema names.
+func NewOpenAPIHandler(doc *openapi.Document) (*OpenAPIHandler, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi document: %w", err)
+	}
+	return &OpenAPIHandler{doc: b}, nil
+}
+
+// Spec returns the OpenAPI document.
+func (h *OpenAPIHandler) Spec(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, h.doc)
+}
+
+// swaggerUI loads Swagger UI from a CDN and points it at /openapi.json.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Issues API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`
+
+// Docs serves Swagger UI for the OpenAPI document.
+func (h *OpenAPIHandler) Docs(c echo.Context) error {
+	return c.HTML(http.StatusOK, swaggerUI)
+}
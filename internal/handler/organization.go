@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// OrganizationHandler handles organization endpoints.
+type OrganizationHandler struct {
+	orgs *service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler.
+func NewOrganizationHandler(orgs *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgs: orgs}
+}
+
+// createOrganizationRequest is the request body for creating an organization.
+type createOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+// Create creates an organization owned by the caller.
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body createOrganizationRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	org, err := h.orgs.Create(c.Request().Context(), userID, body.Name)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, org)
+}
+
+// List returns the organizations the caller belongs to.
+func (h *OrganizationHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	orgs, err := h.orgs.List(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, orgs)
+}
+
+// Get returns the organization in the path.
+func (h *OrganizationHandler) Get(c echo.Context) error {
+	userID, orgID, err := organizationRoute(c)
+	if err != nil {
+		return err
+	}
+
+	org, err := h.orgs.Get(c.Request().Context(), userID, orgID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, org)
+}
+
+// labelSpecRequest is a default label in an organization defaults request.
+type labelSpecRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
+
+// updateDefaultsRequest is the request body for replacing organization defaults.
+type updateDefaultsRequest struct {
+	Labels   []labelSpecRequest     `json:"labels" validate:"max=100,dive"`
+	Settings domain.ProjectSettings `json:"settings"`
+}
+
+// UpdateDefaults replaces the defaults new projects in the organization inherit.
+func (h *OrganizationHandler) UpdateDefaults(c echo.Context) error {
+	userID, orgID, err := organizationRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body updateDefaultsRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	defaults := domain.OrganizationDefaults{Settings: body.Settings}
+	for _, l := range body.Labels {
+		defaults.Labels = append(defaults.Labels, domain.LabelSpec{Name: l.Name, Color: l.Color})
+	}
+
+	org, err := h.orgs.UpdateDefaults(c.Request().Context(), userID, orgID, defaults)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, org)
+}
+
+// organizationRoute returns the authenticated user and the organization ID in the path.
+func organizationRoute(c echo.Context) (userID, orgID int64, err error) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return 0, 0, domain.ErrUnauthorized
+	}
+	if orgID, err = pathID(c, "oid"); err != nil {
+		return 0, 0, err
+	}
+	return userID, orgID, nil
+}
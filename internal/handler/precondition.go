@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const headerIfUnmodifiedSince = "If-Unmodified-Since"
+
+// preconditions reads conditional-request headers. Per RFC 9110 an
+// If-Unmodified-Since value that is not a valid HTTP date is ignored.
+func preconditions(c echo.Context) domain.Precondition {
+	var pre domain.Precondition
+	if v := c.Request().Header.Get(headerIfUnmodifiedSince); v != "" {
+		if t, err := http.ParseTime(v); err == nil {
+			pre.UnmodifiedSince = &t
+		}
+	}
+	return pre
+}
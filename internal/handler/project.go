@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// ProjectHandler handles project endpoints.
+type ProjectHandler struct {
+	projects *service.ProjectService
+}
+
+// NewProjectHandler creates a new ProjectHandler.
+func NewProjectHandler(projects *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{projects: projects}
+}
+
+// List returns the projects the caller owns or is a member of.
+func (h *ProjectHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+	filter := domain.ProjectFilter{Query: c.QueryParam("q"), Cursor: after, Limit: limit}
+
+	page, err := h.projects.List(c.Request().Context(), userID, filter)
+	if err != nil {
+		return err
+	}
+
+	return JSONList(c, http.StatusOK, page.Projects, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// createProjectRequest is the request body for creating a project.
+type createProjectRequest struct {
+	OrganizationID *int64  `json:"organization_id" validate:"omitempty,gt=0"`
+	Name           string  `json:"name" validate:"required,max=200"`
+	Key            *string `json:"key"`
+	Description    *string `json:"description" validate:"omitempty,max=2000"`
+}
+
+// Create creates a project owned by the caller.
+func (h *ProjectHandler) Create(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body createProjectRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	project := domain.Project{Name: body.Name, Key: body.Key, Description: body.Description}
+	created, err := h.projects.Create(c.Request().Context(), userID, body.OrganizationID, project)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// Get returns the project in the path.
+func (h *ProjectHandler) Get(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	project, err := h.projects.Get(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, project)
+}
+
+// updateProjectRequest is the request body for partially updating a project.
+type updateProjectRequest struct {
+	Name             *string                 `json:"name" validate:"omitempty,min=1,max=200"`
+	Key              *string                 `json:"key"`
+	Description      *string                 `json:"description" validate:"omitempty,max=2000"`
+	ArchiveAfterDays *int                    `json:"archive_after_days" validate:"omitempty,min=0,max=3650"`
+	AI               *domain.AISettings      `json:"ai"`
+	Critical         *bool                   `json:"critical"`
+	IssueTemplates   *[]domain.IssueTemplate `json:"issue_templates" validate:"omitempty,max=50"`
+	NextIssueNumber  *int64                  `json:"next_issue_number" validate:"omitempty,min=1"`
+	CloseApproval    *domain.ProjectRole     `json:"close_approval"`
+}
+
+// Update partially updates a project. It honors If-Unmodified-Since.
+func (h *ProjectHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body updateProjectRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	patch := domain.ProjectPatch{
+		Name:             body.Name,
+		Key:              body.Key,
+		Description:      body.Description,
+		ArchiveAfterDays: body.ArchiveAfterDays,
+		AI:               body.AI,
+		Critical:         body.Critical,
+		IssueTemplates:   body.IssueTemplates,
+		NextIssueNumber:  body.NextIssueNumber,
+		CloseApproval:    body.CloseApproval,
+	}
+	project, err := h.projects.Update(c.Request().Context(), userID, projectID, patch, preconditions(c))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, project)
+}
+
+// Delete moves a project and everything in it to the trash. It honors
+// If-Unmodified-Since.
+func (h *ProjectHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.projects.Delete(c.Request().Context(), userID, projectID, preconditions(c)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PauseAI stops AI jobs in the project from being claimed.
+func (h *ProjectHandler) PauseAI(c echo.Context) error {
+	return h.setAIPaused(c, true)
+}
+
+// ResumeAI lets queued AI jobs in the project run again.
+func (h *ProjectHandler) ResumeAI(c echo.Context) error {
+	return h.setAIPaused(c, false)
+}
+
+func (h *ProjectHandler) setAIPaused(c echo.Context, paused bool) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.projects.SetAIPaused(c.Request().Context(), userID, projectID, paused); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// projectRoute extracts the caller and the project ID from the path.
+func projectRoute(c echo.Context) (userID, projectID int64, err error) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return 0, 0, domain.ErrUnauthorized
+	}
+	if projectID, err = pathID(c, "pid"); err != nil {
+		return 0, 0, err
+	}
+	return userID, projectID, nil
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/pagination"
+)
+
+// queryParser reads typed query parameters and collects a field error for
+// every parameter that fails to parse.
+type queryParser struct {
+	c    echo.Context
+	errs domain.ValidationErrors
+}
+
+func newQueryParser(c echo.Context) *queryParser {
+	return &queryParser{c: c}
+}
+
+func (p *queryParser) values(name string) []string {
+	return p.c.QueryParams()[name]
+}
+
+func (p *queryParser) fail(name, message string) {
+	p.errs = append(p.errs, &domain.ValidationError{Field: name, Message: message})
+}
+
+func (p *queryParser) int64(name string) *int64 {
+	v := p.c.QueryParam(name)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		p.fail(name, "must be an integer")
+		return nil
+	}
+	return &n
+}
+
+func (p *queryParser) time(name string) *time.Time {
+	v := p.c.QueryParam(name)
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		p.fail(name, "must be an RFC 3339 timestamp")
+		return nil
+	}
+	return &t
+}
+
+func (p *queryParser) date(name string) *time.Time {
+	v := p.c.QueryParam(name)
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		p.fail(name, "must be a date such as 2026-12-31")
+		return nil
+	}
+	return &t
+}
+
+func (p *queryParser) duration(name string) *time.Duration {
+	v := p.c.QueryParam(name)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		p.fail(name, "must be a positive duration such as 90m or 24h")
+		return nil
+	}
+	return &d
+}
+
+// err returns the collected validation errors, or nil if there were none.
+func (p *queryParser) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
+}
+
+func pathID(c echo.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid %s", domain.ErrInvalidInput, name)
+	}
+	return id, nil
+}
+
+// querySignature reads the expires and signature query parameters of a
+// signed download URL.
+func querySignature(c echo.Context) (expires int64, signature string, err error) {
+	expires, err = strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	if err != nil {
+		return 0, "", &domain.ValidationError{Field: "expires", Message: "must be a Unix time"}
+	}
+	return expires, c.QueryParam("signature"), nil
+}
+
+// queryLimit reads the optional limit query parameter.
+func queryLimit(c echo.Context) (int, error) {
+	p := newQueryParser(c)
+	limit := p.int64("limit")
+	if err := p.err(); err != nil {
+		return 0, err
+	}
+	if limit == nil {
+		return 0, nil
+	}
+	return int(*limit), nil
+}
+
+// page reads the cursor and limit parameters shared by list endpoints.
+func (p *queryParser) page() pagination.Page {
+	page, err := pagination.Parse(p.c.QueryParams())
+	var errs domain.ValidationErrors
+	if errors.As(err, &errs) {
+		p.errs = append(p.errs, errs...)
+	}
+	return page
+}
+
+// listPosition returns the position a page of a list ordered by time and
+// ID starts after. Cursors of other kinds of lists are rejected.
+func (p *queryParser) listPosition(page pagination.Page) domain.ListPosition {
+	if c := page.Cursor; c.Offset != 0 || c.Kind != "" || (c.ID == 0) != (c.At == 0) {
+		p.fail("cursor", "is not a cursor returned by this API")
+		return domain.ListPosition{}
+	}
+	return domain.ListPosition{At: page.Cursor.Time(), ID: page.Cursor.ID}
+}
+
+// queryPage reads the optional cursor and limit query parameters of a list
+// ordered by time and ID, returning the position the page starts after.
+func queryPage(c echo.Context) (after domain.ListPosition, limit int, err error) {
+	p := newQueryParser(c)
+	page := p.page()
+	return p.listPosition(page), page.Limit, p.err()
+}
+
+// queryOffsetPage reads the optional cursor and limit query parameters of
+// ranked results, returning the offset the page starts at.
+func queryOffsetPage(c echo.Context) (offset int64, limit int, err error) {
+	p := newQueryParser(c)
+	page := p.page()
+	return page.Cursor.Offset, page.Limit, p.err()
+}
+
+// queryTimelinePage reads the optional cursor and limit query parameters of
+// an issue timeline, returning the position the page starts after.
+func queryTimelinePage(c echo.Context) (after domain.TimelinePosition, limit int, err error) {
+	p := newQueryParser(c)
+	page := p.page()
+	after = domain.TimelinePosition{At: page.Cursor.Time(), Kind: domain.TimelineKind(page.Cursor.Kind), ID: page.Cursor.ID}
+	return after, page.Limit, p.err()
+}
+
+// pageMeta builds pagination metadata for a list ordered by time and ID.
+func pageMeta(hasNext bool, next domain.ListPosition) PaginationMeta {
+	return pagination.Next(hasNext, pagination.After(next.At, next.ID))
+}
+
+// timelinePageMeta builds pagination metadata for an issue timeline.
+func timelinePageMeta(hasNext bool, next domain.TimelinePosition) PaginationMeta {
+	return pagination.Next(hasNext, pagination.AfterItem(next.At, string(next.Kind), next.ID))
+}
+
+// offsetPageMeta builds pagination metadata for ranked results.
+func offsetPageMeta(hasNext bool, nextOffset int64) PaginationMeta {
+	return pagination.Next(hasNext, pagination.AtOffset(nextOffset))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/pagination"
+)
+
+func TestQueryPage(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		name      string
+		cursor    string
+		wantAfter domain.ListPosition
+		wantErr   bool
+	}{
+		{name: "none"},
+		{name: "position", cursor: pagination.After(at, 42).Encode(), wantAfter: domain.ListPosition{At: at, ID: 42}},
+		{name: "id only", cursor: pagination.Cursor{ID: 42}.Encode(), wantErr: true},
+		{name: "offset", cursor: pagination.AtOffset(20).Encode(), wantErr: true},
+		{name: "timeline", cursor: pagination.AfterItem(at, "comment", 42).Encode(), wantErr: true},
+		{name: "malformed", cursor: "not a cursor", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/?" + url.Values{"cursor": {tt.cursor}}.Encode()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), httptest.NewRecorder())
+
+			after, limit, err := queryPage(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("queryPage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !after.At.Equal(tt.wantAfter.At) || after.ID != tt.wantAfter.ID {
+				t.Errorf("queryPage() after = %+v, want %+v", after, tt.wantAfter)
+			}
+			if limit != pagination.DefaultLimit {
+				t.Errorf("queryPage() limit = %d, want %d", limit, pagination.DefaultLimit)
+			}
+		})
+	}
+}
+
+func TestPageMetaRoundTrip(t *testing.T) {
+	next := domain.ListPosition{At: time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC), ID: 7}
+	meta := pageMeta(true, next)
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/?cursor="+meta.NextCursor, nil), httptest.NewRecorder())
+	after, _, err := queryPage(c)
+	if err != nil {
+		t.Fatalf("queryPage() error = %v", err)
+	}
+	if !after.At.Equal(next.At) || after.ID != next.ID {
+		t.Errorf("queryPage() after = %+v, want %+v", after, next)
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// QuickAccessHandler handles recently viewed and starred item endpoints.
+type QuickAccessHandler struct {
+	items *service.QuickAccessService
+}
+
+// NewQuickAccessHandler creates a new QuickAccessHandler.
+func NewQuickAccessHandler(items *service.QuickAccessService) *QuickAccessHandler {
+	return &QuickAccessHandler{items: items}
+}
+
+// recordViewRequest is the request body for recording a view.
+type recordViewRequest struct {
+	ItemType domain.ItemType `json:"item_type" validate:"required,oneof=project issue"`
+	ItemID   int64           `json:"item_id" validate:"required,gt=0"`
+}
+
+// RecordView records that the caller viewed a project or issue.
+func (h *QuickAccessHandler) RecordView(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body recordViewRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	if err := h.items.RecordView(c.Request().Context(), userID, body.ItemType, body.ItemID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Recent returns the caller's recently viewed items.
+func (h *QuickAccessHandler) Recent(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	limit, err := queryLimit(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.items.Recent(c.Request().Context(), userID, limit)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, items)
+}
+
+// Starred returns the caller's starred items.
+func (h *QuickAccessHandler) Starred(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	limit, err := queryLimit(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.items.Starred(c.Request().Context(), userID, limit)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, items)
+}
+
+// Star adds the item in the path to the caller's starred items.
+func (h *QuickAccessHandler) Star(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	itemID, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.items.Star(c.Request().Context(), userID, domain.ItemType(c.Param("type")), itemID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Unstar removes the item in the path from the caller's starred items.
+func (h *QuickAccessHandler) Unstar(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	itemID, err := pathID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.items.Unstar(c.Request().Context(), userID, domain.ItemType(c.Param("type")), itemID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/netip"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/ratelimit"
+)
+
+// errRateLimited is returned for requests over their rate limit.
+var errRateLimited = errors.New("rate limit exceeded")
+
+// ClientIPExtractor returns how echo finds a request's client IP, which
+// per-IP rate limits are keyed by. Without trusted proxies the client is
+// the connecting peer, and forwarding headers, which any client can set,
+// are ignored. Otherwise X-Forwarded-For is followed back through the
+// trusted networks only.
+func ClientIPExtractor(trusted []netip.Prefix) echo.IPExtractor {
+	if len(trusted) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, p := range trusted {
+		opts = append(opts, echo.TrustIPRange(&net.IPNet{
+			IP:   p.Addr().AsSlice(),
+			Mask: net.CIDRMask(p.Bits(), p.Addr().BitLen()),
+		}))
+	}
+	return echo.ExtractIPFromXFFHeader(opts...)
+}
+
+// RateLimit limits requests with a token bucket per client. Requests are
+// counted against the authenticated user when there is one, so it should
+// run after JWTAuth on protected routes, and against the client IP
+// otherwise. Buckets are namespaced by scope so that limits applied to
+// different route groups do not share tokens.
+//
+// A request over the limit gets 429 with Retry-After. If the store fails,
+// the request is let through rather than taking the API down with it.
+func RateLimit(store ratelimit.Store, scope string, limit ratelimit.Limit) echo.MiddlewareFunc {
+	burst := strconv.Itoa(limit.Burst)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := scope + ":ip:" + c.RealIP()
+			if userID, ok := GetUserID(c); ok {
+				key = scope + ":user:" + strconv.FormatInt(userID, 10)
+			}
+
+			res, err := store.Take(c.Request().Context(), key, limit)
+			if err != nil {
+				slog.Error("rate limit store failed", "scope", scope, "error", err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("X-RateLimit-Limit", burst)
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			if !res.Allowed {
+				header.Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+				return errRateLimited
+			}
+			return next(c)
+		}
+	}
+}
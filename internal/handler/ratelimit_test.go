@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIPExtractor(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	tests := []struct {
+		name    string
+		trusted []netip.Prefix
+		remote  string
+		xff     string
+		want    string
+	}{
+		{"direct ignores forwarded header", nil, "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		{"trusted proxy is followed", proxies, "10.0.0.2:5000", "198.51.100.1", "198.51.100.1"},
+		{"spoofed hops before the proxy are ignored", proxies, "10.0.0.2:5000", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"untrusted peer is the client", proxies, "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", tt.xff)
+			req.Header.Set("X-Real-IP", "192.0.2.99")
+			if got := ClientIPExtractor(tt.trusted)(req); got != tt.want {
+				t.Errorf("client IP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
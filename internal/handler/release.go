@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/service"
+)
+
+const (
+	mimeTextMarkdown = "text/markdown"
+	// headerPolished reports whether requested polishing took place.
+	headerPolished = "X-Release-Notes-Polished"
+)
+
+// ReleaseHandler handles release notes endpoints.
+type ReleaseHandler struct {
+	releases *service.ReleaseService
+}
+
+// NewReleaseHandler creates a new ReleaseHandler.
+func NewReleaseHandler(releases *service.ReleaseService) *ReleaseHandler {
+	return &ReleaseHandler{releases: releases}
+}
+
+// Notes returns Markdown release notes for the project in the path, from
+// the issues completed in the milestone_id milestone or between the from
+// and to dates, inclusive, in UTC.
+func (h *ReleaseHandler) Notes(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	req, err := parseReleaseNotesRequest(c)
+	if err != nil {
+		return err
+	}
+
+	notes, err := h.releases.Generate(c.Request().Context(), userID, projectID, req)
+	if err != nil {
+		return err
+	}
+	if req.Polish {
+		c.Response().Header().Set(headerPolished, strconv.FormatBool(notes.Polished))
+	}
+	return c.Blob(http.StatusOK, mimeTextMarkdown+"; charset=utf-8", []byte(notes.Markdown))
+}
+
+// parseReleaseNotesRequest reads the scope, label groups and polish
+// parameters of a release notes request. Groups are given comma-separated
+// or repeated.
+func parseReleaseNotesRequest(c echo.Context) (service.ReleaseNotesRequest, error) {
+	p := newQueryParser(c)
+	var req service.ReleaseNotesRequest
+
+	req.Scope.MilestoneID = p.int64("milestone_id")
+	from, to := p.date("from"), p.date("to")
+	hasFrom, hasTo := p.c.QueryParam("from") != "", p.c.QueryParam("to") != ""
+	switch {
+	case req.Scope.MilestoneID != nil:
+		if hasFrom || hasTo {
+			p.fail("milestone_id", "cannot be combined with from and to")
+		}
+	case !hasFrom && !hasTo:
+		p.fail("milestone_id", "or from and to are required")
+	case !hasFrom:
+		p.fail("from", "is required with to")
+	case !hasTo:
+		p.fail("to", "is required with from")
+	case from != nil && to != nil:
+		req.Scope.From, req.Scope.To = *from, to.AddDate(0, 0, 1)
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range p.values("group") {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				req.Groups = append(req.Groups, name)
+			}
+		}
+	}
+
+	for _, v := range p.values("polish") {
+		polish, err := strconv.ParseBool(v)
+		if err != nil {
+			p.fail("polish", "must be true or false")
+			continue
+		}
+		req.Polish = polish
+	}
+	return req, p.err()
+}
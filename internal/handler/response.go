@@ -8,6 +8,8 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/pagination"
+	"github.com/sumire/issues/pkg/apiclient"
 )
 
 // Envelope is the standard API response wrapper.
@@ -17,24 +19,15 @@ type Envelope struct {
 	Error *APIError       `json:"error,omitempty"`
 }
 
-// PaginationMeta holds cursor-based pagination info.
-type PaginationMeta struct {
-	NextCursor string `json:"next_cursor,omitempty"`
-	HasNext    bool   `json:"has_next"`
-}
+// PaginationMeta holds cursor-based pagination info. Cursors are opaque to
+// clients.
+type PaginationMeta = pagination.Meta
 
 // APIError represents an error in the API response.
-type APIError struct {
-	Code    string       `json:"code"`
-	Message string       `json:"message"`
-	Details []FieldError `json:"details,omitempty"`
-}
+type APIError = apiclient.Error
 
 // FieldError represents a field-level validation error.
-type FieldError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-}
+type FieldError = apiclient.FieldError
 
 // JSON writes a JSON response with the standard envelope.
 func JSON(c echo.Context, status int, data any) error {
@@ -48,6 +41,12 @@ func JSONList(c echo.Context, status int, data any, meta PaginationMeta) error {
 
 // HTTPErrorHandler is the global error handler for echo.
 func HTTPErrorHandler(err error, c echo.Context) {
+	WriteError(c, err)
+}
+
+// WriteError maps err to an API error and writes it with the standard envelope.
+// It is a no-op if the response has already been committed.
+func WriteError(c echo.Context, err error) {
 	if c.Response().Committed {
 		return
 	}
@@ -83,6 +82,26 @@ func mapError(err error) (int, APIError) {
 			Code:    "unauthorized",
 			Message: "Authentication is required",
 		}
+	case errors.Is(err, domain.ErrRestrictedLabel):
+		return http.StatusForbidden, APIError{
+			Code:    "label_restricted",
+			Message: "Only project admins may apply or remove this label",
+		}
+	case errors.Is(err, domain.ErrApprovalRequired):
+		return http.StatusConflict, APIError{
+			Code:    "approval_required",
+			Message: err.Error(),
+		}
+	case errors.Is(err, domain.ErrAttachmentInfected):
+		return http.StatusUnprocessableEntity, APIError{
+			Code:    "attachment_infected",
+			Message: err.Error(),
+		}
+	case errors.Is(err, domain.ErrProjectFrozen):
+		return http.StatusLocked, APIError{
+			Code:    "project_frozen",
+			Message: err.Error(),
+		}
 	case errors.Is(err, domain.ErrForbidden):
 		return http.StatusForbidden, APIError{
 			Code:    "forbidden",
@@ -98,7 +117,40 @@ func mapError(err error) (int, APIError) {
 			Code:    "conflict",
 			Message: "The resource already exists or conflicts with current state",
 		}
+	case errors.Is(err, errDeadlineExceeded):
+		return http.StatusGatewayTimeout, APIError{
+			Code:    "timeout",
+			Message: "The request took too long to complete",
+		}
+	case errors.Is(err, errReadOnlyToken):
+		return http.StatusForbidden, APIError{
+			Code:    "insufficient_scope",
+			Message: "This access token is read-only",
+		}
+	case errors.Is(err, errRateLimited):
+		return http.StatusTooManyRequests, APIError{
+			Code:    "rate_limited",
+			Message: "Too many requests; retry after the time in the Retry-After header",
+		}
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, APIError{
+			Code:    "precondition_failed",
+			Message: "The resource has been modified since the given time",
+		}
 	default:
+		var validationErrs domain.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			details := make([]FieldError, len(validationErrs))
+			for i, ve := range validationErrs {
+				details[i] = FieldError{Field: ve.Field, Message: ve.Message}
+			}
+			return http.StatusBadRequest, APIError{
+				Code:    "validation_error",
+				Message: "Validation failed",
+				Details: details,
+			}
+		}
+
 		var validationErr *domain.ValidationError
 		if errors.As(err, &validationErr) {
 			return http.StatusBadRequest, APIError{
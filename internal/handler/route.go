@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// RouteHandler handles notification routing rule endpoints.
+type RouteHandler struct {
+	routes *service.NotificationRouteService
+}
+
+// NewRouteHandler creates a new RouteHandler.
+func NewRouteHandler(routes *service.NotificationRouteService) *RouteHandler {
+	return &RouteHandler{routes: routes}
+}
+
+// createRouteRequest is the request body for creating a routing rule.
+type createRouteRequest struct {
+	Name    string              `json:"name" validate:"required,max=100"`
+	Match   domain.RouteMatch   `json:"match"`
+	Targets domain.RouteTargets `json:"targets"`
+	Active  *bool               `json:"active"`
+}
+
+// updateRouteRequest is the request body for partially updating a routing
+// rule. Match and targets are replaced as a whole.
+type updateRouteRequest struct {
+	Name    *string              `json:"name" validate:"omitempty,min=1,max=100"`
+	Match   *domain.RouteMatch   `json:"match"`
+	Targets *domain.RouteTargets `json:"targets"`
+	Active  *bool                `json:"active"`
+}
+
+// List returns the routing rules of the project in the path.
+func (h *RouteHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	routes, err := h.routes.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, routes)
+}
+
+// Create adds a routing rule to the project in the path.
+func (h *RouteHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createRouteRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	route := domain.NotificationRoute{
+		Name:    body.Name,
+		Match:   body.Match,
+		Targets: body.Targets,
+		Active:  true,
+	}
+	if body.Active != nil {
+		route.Active = *body.Active
+	}
+	created, err := h.routes.Create(c.Request().Context(), userID, projectID, route)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// Update changes the routing rule in the path.
+func (h *RouteHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	routeID, err := pathID(c, "rid")
+	if err != nil {
+		return err
+	}
+
+	var body updateRouteRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	route, err := h.routes.Update(c.Request().Context(), userID, projectID, routeID, domain.NotificationRoutePatch{
+		Name:    body.Name,
+		Match:   body.Match,
+		Targets: body.Targets,
+		Active:  body.Active,
+	})
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, route)
+}
+
+// Delete removes the routing rule in the path.
+func (h *RouteHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	routeID, err := pathID(c, "rid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.routes.Delete(c.Request().Context(), userID, projectID, routeID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
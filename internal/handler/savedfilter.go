@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// SavedFilterHandler handles saved filter endpoints.
+type SavedFilterHandler struct {
+	filters *service.SavedFilterService
+}
+
+// NewSavedFilterHandler creates a new SavedFilterHandler.
+func NewSavedFilterHandler(filters *service.SavedFilterService) *SavedFilterHandler {
+	return &SavedFilterHandler{filters: filters}
+}
+
+// List returns the caller's saved filters in the project in the path.
+func (h *SavedFilterHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := h.filters.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, filters)
+}
+
+// Get returns the saved filter in the path.
+func (h *SavedFilterHandler) Get(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	filterID, err := pathID(c, "fid")
+	if err != nil {
+		return err
+	}
+
+	filter, err := h.filters.Get(c.Request().Context(), userID, projectID, filterID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, filter)
+}
+
+// createSavedFilterRequest is the request body for saving a filter.
+type createSavedFilterRequest struct {
+	Name  string `json:"name" validate:"required,max=100"`
+	Query string `json:"query" validate:"max=1000"`
+}
+
+// Create saves a filter for the caller in the project in the path.
+func (h *SavedFilterHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createSavedFilterRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	filter := domain.SavedFilter{Name: body.Name, Query: body.Query}
+	created, err := h.filters.Create(c.Request().Context(), userID, projectID, filter)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// updateSavedFilterRequest is the request body for partially updating a
+// saved filter.
+type updateSavedFilterRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Query *string `json:"query" validate:"omitempty,max=1000"`
+}
+
+// Update renames the saved filter in the path or changes its expression.
+func (h *SavedFilterHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	filterID, err := pathID(c, "fid")
+	if err != nil {
+		return err
+	}
+
+	var body updateSavedFilterRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	patch := domain.SavedFilterPatch{Name: body.Name, Query: body.Query}
+	filter, err := h.filters.Update(c.Request().Context(), userID, projectID, filterID, patch)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, filter)
+}
+
+// Delete removes the saved filter in the path.
+func (h *SavedFilterHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	filterID, err := pathID(c, "fid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.filters.Delete(c.Request().Context(), userID, projectID, filterID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Issues returns a paginated list of the issues matching the saved filter
+// in the path.
+func (h *SavedFilterHandler) Issues(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	filterID, err := pathID(c, "fid")
+	if err != nil {
+		return err
+	}
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.filters.Issues(c.Request().Context(), userID, projectID, filterID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, append(page.Pinned, page.Issues...), pageMeta(page.HasNext, page.NextCursor))
+}
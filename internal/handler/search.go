@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// SearchHandler handles issue search endpoints.
+type SearchHandler struct {
+	search *service.SearchService
+}
+
+// NewSearchHandler creates a new SearchHandler.
+func NewSearchHandler(search *service.SearchService) *SearchHandler {
+	return &SearchHandler{search: search}
+}
+
+// Issues returns issues in the project matching the q parameter, best match
+// first. The cursor is an offset into the results.
+func (h *SearchHandler) Issues(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	cursor, limit, err := queryOffsetPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.search.Search(c.Request().Context(), userID, projectID, c.QueryParam("q"), cursor, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Issues, offsetPageMeta(page.HasNext, page.NextCursor))
+}
+
+// All returns issues matching the q parameter across every project the user
+// can access, best match first. The cursor is an offset into the results.
+func (h *SearchHandler) All(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	cursor, limit, err := queryOffsetPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.search.SearchAll(c.Request().Context(), userID, c.QueryParam("q"), cursor, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Hits, offsetPageMeta(page.HasNext, page.NextCursor))
+}
+
+// QuickSearch returns up to five issues in the project matching the q
+// parameter as typed so far, for issue-reference autocomplete.
+func (h *SearchHandler) QuickSearch(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := h.search.QuickSearch(c.Request().Context(), userID, projectID, c.QueryParam("q"))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, suggestions)
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// KeyCasing selects how JSON object keys are written.
+type KeyCasing string
+
+const (
+	KeyCasingSnake KeyCasing = "snake"
+	KeyCasingCamel KeyCasing = "camel"
+)
+
+// TimeFormat selects how timestamps are written.
+type TimeFormat string
+
+const (
+	TimeFormatRFC3339     TimeFormat = "rfc3339"
+	TimeFormatEpochMillis TimeFormat = "epoch_ms"
+)
+
+// SerializationProfile controls the shape of JSON responses.
+type SerializationProfile struct {
+	Casing     KeyCasing
+	TimeFormat TimeFormat
+}
+
+// ParseSerializationProfile validates configured casing and time format names.
+func ParseSerializationProfile(casing, timeFormat string) (SerializationProfile, error) {
+	p := SerializationProfile{Casing: KeyCasing(casing), TimeFormat: TimeFormat(timeFormat)}
+	if p.Casing != KeyCasingSnake && p.Casing != KeyCasingCamel {
+		return p, fmt.Errorf("unknown key casing %q", casing)
+	}
+	if p.TimeFormat != TimeFormatRFC3339 && p.TimeFormat != TimeFormatEpochMillis {
+		return p, fmt.Errorf("unknown time format %q", timeFormat)
+	}
+	return p, nil
+}
+
+// native reports whether the profile matches the structs' own JSON tags, in
+// which case no rewriting is needed.
+func (p SerializationProfile) native() bool {
+	return p.Casing == KeyCasingSnake && p.TimeFormat == TimeFormatRFC3339
+}
+
+// ProfileSerializer is an echo.JSONSerializer that rewrites the field keys
+// and timestamps of response structs according to a serialization profile. The server-wide default can
+// be overridden per request with Accept media type parameters, for example
+// "Accept: application/json; casing=camel; time=epoch_ms".
+type ProfileSerializer struct {
+	echo.DefaultJSONSerializer
+	defaults SerializationProfile
+}
+
+// NewProfileSerializer creates a ProfileSerializer with the given default profile.
+func NewProfileSerializer(defaults SerializationProfile) *ProfileSerializer {
+	return &ProfileSerializer{defaults: defaults}
+}
+
+// Serialize encodes i to the response using the negotiated profile.
+func (s *ProfileSerializer) Serialize(c echo.Context, i any, indent string) error {
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	profile := s.negotiate(c.Request().Header.Get(echo.HeaderAccept))
+	if profile.native() {
+		return s.DefaultJSONSerializer.Serialize(c, i, indent)
+	}
+
+	raw, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(c.Response())
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	return enc.Encode(profile.rewrite(v, reflect.ValueOf(i)))
+}
+
+// negotiate applies casing and time parameters from a JSON Accept entry on
+// top of the default profile. Unknown values are ignored.
+func (s *ProfileSerializer) negotiate(accept string) SerializationProfile {
+	profile := s.defaults
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || (mediaType != echo.MIMEApplicationJSON && mediaType != "*/*") {
+			continue
+		}
+		switch KeyCasing(params["casing"]) {
+		case KeyCasingSnake, KeyCasingCamel:
+			profile.Casing = KeyCasing(params["casing"])
+		}
+		switch TimeFormat(params["time"]) {
+		case TimeFormatRFC3339, TimeFormatEpochMillis:
+			profile.TimeFormat = TimeFormat(params["time"])
+		}
+		break
+	}
+	return profile
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// rewrite walks a decoded JSON value alongside the Go value it was encoded
+// from, renaming struct field keys and converting time.Time values. Maps
+// are payloads, such as issue form answers keyed by field ID, so their keys
+// are left alone, as are the values of types that encode themselves.
+func (p SerializationProfile) rewrite(v any, rv reflect.Value) any {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Type() == timeType {
+		if s, ok := v.(string); ok && p.TimeFormat == TimeFormatEpochMillis {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t.UnixMilli()
+			}
+		}
+		return v
+	}
+	if t := reflect.PointerTo(rv.Type()); t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return v
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		fields := jsonFields(rv.Type())
+		out := make(map[string]any, len(obj))
+		for k, child := range obj {
+			name := k
+			if p.Casing == KeyCasingCamel {
+				name = snakeToCamel(k)
+			}
+			if index, ok := fields[k]; ok {
+				if fv, err := rv.FieldByIndexErr(index); err == nil {
+					child = p.rewrite(child, fv)
+				}
+			}
+			out[name] = child
+		}
+		return out
+	case reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		for iter := rv.MapRange(); iter.Next(); {
+			k := fmt.Sprint(iter.Key().Interface())
+			if child, ok := obj[k]; ok {
+				obj[k] = p.rewrite(child, iter.Value())
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		list, ok := v.([]any)
+		if !ok || len(list) != rv.Len() {
+			return v
+		}
+		for i := range list {
+			list[i] = p.rewrite(list[i], rv.Index(i))
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+// jsonFieldCache holds the result of jsonFields per struct type.
+var jsonFieldCache sync.Map
+
+// jsonFields maps the JSON keys of a struct type's fields to their index,
+// including the fields promoted from embedded structs, as encoding/json
+// names them.
+func jsonFields(t reflect.Type) map[string][]int {
+	if fields, ok := jsonFieldCache.Load(t); ok {
+		return fields.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	var embedded []reflect.StructField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Index
+	}
+	// Fields of the struct itself win over promoted ones.
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			if f.IsExported() {
+				fields[f.Name] = f.Index
+			}
+			continue
+		}
+		for name, index := range jsonFields(ft) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = append(slices.Clone(f.Index), index...)
+			}
+		}
+	}
+
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+func snakeToCamel(s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+	parts := strings.Split(s, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
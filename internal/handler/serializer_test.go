@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+func TestProfileSerializer(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	ms := float64(at.UnixMilli())
+
+	type audited struct {
+		CreatedAt time.Time `json:"created_at"`
+	}
+	type record struct {
+		audited
+		DisplayName string           `json:"display_name"`
+		ClosedAt    *time.Time       `json:"closed_at"`
+		Form        domain.FormData  `json:"form"`
+		Data        domain.EventData `json:"data"`
+		Times       []time.Time      `json:"times"`
+		Hidden      string           `json:"-"`
+	}
+	value := Envelope{Data: []record{{
+		audited:     audited{CreatedAt: at},
+		DisplayName: "Ada",
+		Form:        domain.FormData{"steps_to_reproduce": "run it", "found_at": "2026-03-01T12:30:00Z"},
+		Data:        domain.EventData{"from_status": "open", "due_at": "2026-03-01T12:30:00Z"},
+		Times:       []time.Time{at},
+		Hidden:      "secret",
+	}}}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   map[string]any
+	}{
+		{
+			name:   "camel case and epoch milliseconds",
+			accept: "application/json; casing=camel; time=epoch_ms",
+			want: map[string]any{
+				"createdAt":   ms,
+				"displayName": "Ada",
+				"closedAt":    nil,
+				"form":        map[string]any{"steps_to_reproduce": "run it", "found_at": "2026-03-01T12:30:00Z"},
+				"data":        map[string]any{"from_status": "open", "due_at": "2026-03-01T12:30:00Z"},
+				"times":       []any{ms},
+			},
+		},
+		{
+			name:   "camel case only",
+			accept: "application/json; casing=camel",
+			want: map[string]any{
+				"createdAt":   "2026-03-01T12:30:00Z",
+				"displayName": "Ada",
+				"closedAt":    nil,
+				"form":        map[string]any{"steps_to_reproduce": "run it", "found_at": "2026-03-01T12:30:00Z"},
+				"data":        map[string]any{"from_status": "open", "due_at": "2026-03-01T12:30:00Z"},
+				"times":       []any{"2026-03-01T12:30:00Z"},
+			},
+		},
+		{
+			name:   "epoch milliseconds only",
+			accept: "application/json; time=epoch_ms",
+			want: map[string]any{
+				"created_at":   ms,
+				"display_name": "Ada",
+				"closed_at":    nil,
+				"form":         map[string]any{"steps_to_reproduce": "run it", "found_at": "2026-03-01T12:30:00Z"},
+				"data":         map[string]any{"from_status": "open", "due_at": "2026-03-01T12:30:00Z"},
+				"times":        []any{ms},
+			},
+		},
+	}
+	s := NewProfileSerializer(SerializationProfile{Casing: KeyCasingSnake, TimeFormat: TimeFormatRFC3339})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			if err := s.Serialize(echo.New().NewContext(req, rec), value, ""); err != nil {
+				t.Fatalf("Serialize() error = %v", err)
+			}
+
+			var got struct {
+				Data []map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body, err)
+			}
+			if len(got.Data) != 1 || !reflect.DeepEqual(got.Data[0], tt.want) {
+				t.Errorf("Serialize() data = %v, want [%v]", got.Data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/internal/slack"
+)
+
+// SlackHandler handles the Slack app's endpoints.
+type SlackHandler struct {
+	slack *service.SlackService
+}
+
+// NewSlackHandler creates a new SlackHandler.
+func NewSlackHandler(slack *service.SlackService) *SlackHandler {
+	return &SlackHandler{slack: slack}
+}
+
+// Command answers a slash command. The reply is in Slack's message format
+// rather than the API envelope.
+func (h *SlackHandler) Command(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return fmt.Errorf("%w: invalid form body", domain.ErrInvalidInput)
+	}
+
+	msg, err := h.slack.Command(c.Request().Context(), slack.ParseCommand(form))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+// Interaction answers a click on one of the app's buttons.
+func (h *SlackHandler) Interaction(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return fmt.Errorf("%w: invalid form body", domain.ErrInvalidInput)
+	}
+	in, err := slack.ParseInteraction(form)
+	if err != nil {
+		return fmt.Errorf("%w: invalid interaction payload", domain.ErrInvalidInput)
+	}
+
+	msg, err := h.slack.Interact(c.Request().Context(), *in)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, msg)
+}
+
+// linkSlackRequest is the request body for linking a Slack account.
+type linkSlackRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// Link links the Slack user a link code was issued to with the caller's
+// account.
+func (h *SlackHandler) Link(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body linkSlackRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	if err := h.slack.Link(c.Request().Context(), userID, body.Code); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
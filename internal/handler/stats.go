@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// StatsHandler handles project statistics endpoints.
+type StatsHandler struct {
+	stats *service.StatsService
+}
+
+// NewStatsHandler creates a new StatsHandler.
+func NewStatsHandler(stats *service.StatsService) *StatsHandler {
+	return &StatsHandler{stats: stats}
+}
+
+// Contributors returns per-member activity stats over the since/until window.
+func (h *StatsHandler) Contributors(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	window, err := queryWindow(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.stats.Contributors(c.Request().Context(), userID, projectID, window)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, stats)
+}
+
+// IssueCounts returns time-bucketed counts of issues created over the
+// since/until window, grouped by status, label or assignee. group_by defaults
+// to status and interval to week.
+func (h *StatsHandler) IssueCounts(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	window, err := queryWindow(c)
+	if err != nil {
+		return err
+	}
+
+	groupBy := domain.IssueGroupingStatus
+	if v := c.QueryParam("group_by"); v != "" {
+		groupBy = domain.IssueGrouping(v)
+	}
+	interval := domain.StatsIntervalWeek
+	if v := c.QueryParam("interval"); v != "" {
+		interval = domain.StatsInterval(v)
+	}
+
+	var errs domain.ValidationErrors
+	if !groupBy.Valid() {
+		errs = append(errs, &domain.ValidationError{Field: "group_by", Message: fmt.Sprintf("unknown grouping %q", groupBy)})
+	}
+	if !interval.Valid() {
+		errs = append(errs, &domain.ValidationError{Field: "interval", Message: fmt.Sprintf("unknown interval %q", interval)})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	counts, err := h.stats.IssueCounts(c.Request().Context(), userID, projectID, window, groupBy, interval)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, counts)
+}
+
+// Flow returns the daily status breakdown over the since/until window for
+// burndown and cumulative flow charts, of the whole project or of the
+// milestone_id milestone.
+func (h *StatsHandler) Flow(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	window, err := queryWindow(c)
+	if err != nil {
+		return err
+	}
+	p := newQueryParser(c)
+	milestoneID := p.int64("milestone_id")
+	if err := p.err(); err != nil {
+		return err
+	}
+
+	points, err := h.stats.Flow(c.Request().Context(), userID, projectID, milestoneID, window)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, points)
+}
+
+// CycleTimes returns lead and cycle time percentiles, in seconds, for issues
+// completed within the since/until window.
+func (h *StatsHandler) CycleTimes(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	window, err := queryWindow(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.stats.CycleTimes(c.Request().Context(), userID, projectID, window)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, stats)
+}
+
+// queryWindow reads the optional since and until query parameters.
+func queryWindow(c echo.Context) (domain.TimeWindow, error) {
+	p := newQueryParser(c)
+
+	var w domain.TimeWindow
+	if since := p.time("since"); since != nil {
+		w.Since = *since
+	}
+	if until := p.time("until"); until != nil {
+		w.Until = *until
+	}
+	if !w.Since.IsZero() && !w.Until.IsZero() && !w.Since.Before(w.Until) {
+		p.fail("since", "must be before until")
+	}
+	return w, p.err()
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// TemplateHandler handles project template endpoints.
+type TemplateHandler struct {
+	templates *service.TemplateService
+}
+
+// NewTemplateHandler creates a new TemplateHandler.
+func NewTemplateHandler(templates *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templates: templates}
+}
+
+// List returns the templates available to the caller.
+func (h *TemplateHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	templates, err := h.templates.List(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, templates)
+}
+
+// createFromTemplateRequest is the request body for creating a project from a template.
+type createFromTemplateRequest struct {
+	TemplateID     int64   `json:"template_id" validate:"required,gt=0"`
+	OrganizationID *int64  `json:"organization_id" validate:"omitempty,gt=0"`
+	Name           string  `json:"name" validate:"required,max=200"`
+	Description    *string `json:"description" validate:"omitempty,max=2000"`
+}
+
+// CreateProject creates a new project from a template.
+func (h *TemplateHandler) CreateProject(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body createFromTemplateRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	project, err := h.templates.CreateProject(c.Request().Context(), userID, body.TemplateID, body.OrganizationID, body.Name, body.Description)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, project)
+}
+
+// saveTemplateRequest is the request body for saving a project as a template.
+type saveTemplateRequest struct {
+	Name        string  `json:"name" validate:"required,max=200"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+}
+
+// SaveProject saves the project in the path as a new template.
+func (h *TemplateHandler) SaveProject(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body saveTemplateRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	template, err := h.templates.SaveProject(c.Request().Context(), userID, projectID, body.Name, body.Description)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, template)
+}
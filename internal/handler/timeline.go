@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/service"
+)
+
+// TimelineHandler handles issue timeline endpoints.
+type TimelineHandler struct {
+	timeline *service.TimelineService
+}
+
+// NewTimelineHandler creates a new TimelineHandler.
+func NewTimelineHandler(timeline *service.TimelineService) *TimelineHandler {
+	return &TimelineHandler{timeline: timeline}
+}
+
+// List returns the events and comments of the issue in the path, oldest
+// first.
+func (h *TimelineHandler) List(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	after, limit, err := queryTimelinePage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.timeline.List(c.Request().Context(), userID, projectID, issueID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Items, timelinePageMeta(page.HasNext, page.Next))
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+// AccessTokenHandler handles personal access token endpoints.
+type AccessTokenHandler struct {
+	tokens *service.AccessTokenService
+}
+
+// NewAccessTokenHandler creates a new AccessTokenHandler.
+func NewAccessTokenHandler(tokens *service.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{tokens: tokens}
+}
+
+// createdAccessToken is a new token with its secret, which is only shown
+// once.
+type createdAccessToken struct {
+	domain.AccessToken
+	Token string `json:"token"`
+}
+
+// List returns the caller's personal access tokens.
+func (h *AccessTokenHandler) List(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	tokens, err := h.tokens.List(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, tokens)
+}
+
+// Create issues a personal access token for the caller.
+func (h *AccessTokenHandler) Create(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	var body apiclient.CreateAccessTokenRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	token, secret, err := h.tokens.Create(c.Request().Context(), userID, body.Name, domain.AccessTokenScope(body.Scope), body.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, createdAccessToken{AccessToken: *token, Token: secret})
+}
+
+// Delete revokes one of the caller's personal access tokens.
+func (h *AccessTokenHandler) Delete(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+	id, err := pathID(c, "tid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.tokens.Delete(c.Request().Context(), userID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// TrashHandler handles listing and restoring deleted issues and projects.
+type TrashHandler struct {
+	trash *service.TrashService
+}
+
+// NewTrashHandler creates a new TrashHandler.
+func NewTrashHandler(trash *service.TrashService) *TrashHandler {
+	return &TrashHandler{trash: trash}
+}
+
+// Projects returns the projects in the caller's trash.
+func (h *TrashHandler) Projects(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	projects, err := h.trash.ListProjects(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, projects)
+}
+
+// RestoreProject takes the project in the path out of the trash.
+func (h *TrashHandler) RestoreProject(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	project, err := h.trash.RestoreProject(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, project)
+}
+
+// Issues returns a paginated list of the issues in the trash of the project
+// in the path.
+func (h *TrashHandler) Issues(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.trash.ListIssues(c.Request().Context(), userID, projectID, after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Issues, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// RestoreIssue takes the issue in the path out of its project's trash.
+func (h *TrashHandler) RestoreIssue(c echo.Context) error {
+	userID, projectID, issueID, err := issueRoute(c)
+	if err != nil {
+		return err
+	}
+
+	issue, err := h.trash.RestoreIssue(c.Request().Context(), userID, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, issue)
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// WebhookHandler handles webhook endpoints.
+type WebhookHandler struct {
+	webhooks *service.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(webhooks *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// Deliveries lists webhook deliveries, filtered by repeated status
+// parameters, project_id and event_type.
+func (h *WebhookHandler) Deliveries(c echo.Context) error {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	filter, err := parseWebhookDeliveryFilter(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.webhooks.ListDeliveries(c.Request().Context(), userID, filter)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Deliveries, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// List returns the webhooks of the project in the path.
+func (h *WebhookHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := h.webhooks.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, webhooks)
+}
+
+// createWebhookRequest is the request body for creating a webhook.
+type createWebhookRequest struct {
+	URL         string                    `json:"url" validate:"required,http_url,max=2048"`
+	Format      string                    `json:"format" validate:"omitempty,oneof=json teams"`
+	Template    *string                   `json:"template" validate:"omitempty,max=65536"`
+	ContentType string                    `json:"content_type" validate:"omitempty,max=255"`
+	Active      *bool                     `json:"active"`
+	Retry       domain.WebhookRetryPolicy `json:"retry"`
+}
+
+// createdWebhook is a newly created webhook with its signing secret, which
+// is not shown again.
+type createdWebhook struct {
+	*domain.Webhook
+	Secret string `json:"secret"`
+}
+
+// Create adds a webhook to the project in the path.
+func (h *WebhookHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createWebhookRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	w := domain.Webhook{
+		URL:         body.URL,
+		Format:      domain.WebhookFormat(body.Format),
+		ContentType: body.ContentType,
+		Active:      true,
+		Retry:       body.Retry,
+	}
+	if body.Template != nil && *body.Template != "" {
+		w.Template = body.Template
+	}
+	if body.Active != nil {
+		w.Active = *body.Active
+	}
+	created, err := h.webhooks.Create(c.Request().Context(), userID, projectID, w)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, createdWebhook{Webhook: created, Secret: created.Secret})
+}
+
+// updateWebhookRequest is the request body for partially updating a
+// webhook. An empty template removes it, and a retry policy replaces the
+// current one.
+type updateWebhookRequest struct {
+	URL         *string                    `json:"url" validate:"omitempty,http_url,max=2048"`
+	Format      *string                    `json:"format" validate:"omitempty,oneof=json teams"`
+	Template    *string                    `json:"template" validate:"omitempty,max=65536"`
+	ContentType *string                    `json:"content_type" validate:"omitempty,min=1,max=255"`
+	Active      *bool                      `json:"active"`
+	Retry       *domain.WebhookRetryPolicy `json:"retry"`
+}
+
+// Update changes the webhook in the path.
+func (h *WebhookHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	webhookID, err := pathID(c, "wid")
+	if err != nil {
+		return err
+	}
+
+	var body updateWebhookRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	patch := domain.WebhookPatch{
+		URL:         body.URL,
+		Format:      (*domain.WebhookFormat)(body.Format),
+		Template:    body.Template,
+		ContentType: body.ContentType,
+		Active:      body.Active,
+		Retry:       body.Retry,
+	}
+	w, err := h.webhooks.Update(c.Request().Context(), userID, projectID, webhookID, patch)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, w)
+}
+
+// Delete removes the webhook in the path.
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	webhookID, err := pathID(c, "wid")
+	if err != nil {
+		return err
+	}
+
+	if err := h.webhooks.Delete(c.Request().Context(), userID, projectID, webhookID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// WebhookDeliveries lists the deliveries of the webhook in the path,
+// filtered like Deliveries.
+func (h *WebhookHandler) WebhookDeliveries(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	webhookID, err := pathID(c, "wid")
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseWebhookDeliveryFilter(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.webhooks.ListWebhookDeliveries(c.Request().Context(), userID, projectID, webhookID, filter)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Deliveries, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// EventTypes lists the events webhooks receive, with sample payloads and
+// their JSON Schema.
+func (h *WebhookHandler) EventTypes(c echo.Context) error {
+	return JSON(c, http.StatusOK, h.webhooks.EventTypes())
+}
+
+// testWebhookRequest is the request body for testing a webhook.
+type testWebhookRequest struct {
+	EventType string `json:"event_type"`
+}
+
+// Test sends a sample payload to the webhook in the path and returns the
+// outcome. The endpoint answering with an error is reported in the result,
+// not as an error response.
+func (h *WebhookHandler) Test(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	webhookID, err := pathID(c, "wid")
+	if err != nil {
+		return err
+	}
+
+	var body testWebhookRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+
+	result, err := h.webhooks.Test(c.Request().Context(), userID, projectID, webhookID, domain.EventType(body.EventType))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, result)
+}
+
+func parseWebhookDeliveryFilter(c echo.Context) (domain.WebhookDeliveryFilter, error) {
+	p := newQueryParser(c)
+
+	var f domain.WebhookDeliveryFilter
+	for _, s := range p.values("status") {
+		status := domain.WebhookDeliveryStatus(s)
+		if !status.Valid() {
+			p.fail("status", fmt.Sprintf("unknown status %q", s))
+			continue
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	f.ProjectID = p.int64("project_id")
+	f.EventType = domain.EventType(c.QueryParam("event_type"))
+
+	page := p.page()
+	f.Cursor = p.listPosition(page)
+	f.Limit = page.Limit
+
+	return f, p.err()
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/service"
+)
+
+// WikiHandler handles project wiki endpoints.
+type WikiHandler struct {
+	wiki *service.WikiService
+}
+
+// NewWikiHandler creates a new WikiHandler.
+func NewWikiHandler(wiki *service.WikiService) *WikiHandler {
+	return &WikiHandler{wiki: wiki}
+}
+
+// List returns the pages of the wiki of the project in the path.
+func (h *WikiHandler) List(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	pages, err := h.wiki.List(c.Request().Context(), userID, projectID)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, pages)
+}
+
+// Get returns the wiki page in the path with the issues that link to it.
+func (h *WikiHandler) Get(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.wiki.Get(c.Request().Context(), userID, projectID, c.Param("slug"))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, page)
+}
+
+// createWikiPageRequest is the request body for creating a wiki page. Slug
+// defaults to one derived from the title.
+type createWikiPageRequest struct {
+	Slug    string `json:"slug" validate:"omitempty,max=100"`
+	Title   string `json:"title" validate:"required,max=200"`
+	Body    string `json:"body" validate:"max=200000"`
+	Message string `json:"message" validate:"max=500"`
+}
+
+// Create adds a page to the wiki of the project in the path.
+func (h *WikiHandler) Create(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body createWikiPageRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	page := domain.WikiPage{WikiPageSummary: domain.WikiPageSummary{Slug: body.Slug, Title: body.Title}, Body: body.Body}
+	created, err := h.wiki.Create(c.Request().Context(), userID, projectID, page, body.Message)
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusCreated, created)
+}
+
+// updateWikiPageRequest is the request body for editing a wiki page.
+type updateWikiPageRequest struct {
+	Title   *string `json:"title" validate:"omitempty,min=1,max=200"`
+	Body    *string `json:"body" validate:"omitempty,max=200000"`
+	Message string  `json:"message" validate:"max=500"`
+}
+
+// Update edits the wiki page in the path.
+func (h *WikiHandler) Update(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var body updateWikiPageRequest
+	if err := c.Bind(&body); err != nil {
+		return fmt.Errorf("%w: invalid request body", domain.ErrInvalidInput)
+	}
+	if err := c.Validate(body); err != nil {
+		return err
+	}
+
+	patch := domain.WikiPagePatch{Title: body.Title, Body: body.Body, Message: body.Message}
+	page, err := h.wiki.Update(c.Request().Context(), userID, projectID, c.Param("slug"), patch, preconditions(c))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, page)
+}
+
+// Delete removes the wiki page in the path.
+func (h *WikiHandler) Delete(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.wiki.Delete(c.Request().Context(), userID, projectID, c.Param("slug")); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Revisions returns the revisions of the wiki page in the path, newest
+// first.
+func (h *WikiHandler) Revisions(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	after, limit, err := queryPage(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.wiki.Revisions(c.Request().Context(), userID, projectID, c.Param("slug"), after, limit)
+	if err != nil {
+		return err
+	}
+	return JSONList(c, http.StatusOK, page.Revisions, pageMeta(page.HasNext, page.NextCursor))
+}
+
+// Revision returns the revision in the path of a wiki page.
+func (h *WikiHandler) Revision(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	revision, err := pathID(c, "rev")
+	if err != nil {
+		return err
+	}
+
+	result, err := h.wiki.Revision(c.Request().Context(), userID, projectID, c.Param("slug"), int(revision))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, result)
+}
+
+// Restore makes the revision in the path the content of its wiki page again.
+func (h *WikiHandler) Restore(c echo.Context) error {
+	userID, projectID, err := projectRoute(c)
+	if err != nil {
+		return err
+	}
+	revision, err := pathID(c, "rev")
+	if err != nil {
+		return err
+	}
+
+	page, err := h.wiki.Restore(c.Request().Context(), userID, projectID, c.Param("slug"), int(revision), preconditions(c))
+	if err != nil {
+		return err
+	}
+	return JSON(c, http.StatusOK, page)
+}
@@ -0,0 +1,232 @@
+// Package imaging prepares uploaded images for serving. It strips metadata
+// that photos leak, such as EXIF camera details and GPS position, and makes
+// smaller variants for the web. JPEG metadata is removed without
+// re-encoding unless the photo has to be turned upright first. JPEG, PNG
+// and GIF images get variants; WebP metadata is stripped, but the standard
+// library cannot decode WebP, so it gets no variants. GIFs carry no EXIF
+// and are kept as uploaded.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxPixels bounds the images that are decoded, so a small file that
+	// decompresses to a huge image cannot exhaust memory.
+	maxPixels = 50_000_000
+	// jpegQuality is the quality images are re-encoded at.
+	jpegQuality = 85
+)
+
+// ErrInvalid is returned for files that are not valid images of their type.
+var ErrInvalid = errors.New("invalid image")
+
+// Size is a variant to make of each image: the image scaled down to fit
+// within MaxSize pixels on its longer side.
+type Size struct {
+	Name    string
+	MaxSize int
+}
+
+// ParseSizes parses a comma-separated list of variants such as
+// "thumbnail=320,preview=1280". "none" is no variants.
+func ParseSizes(spec string) ([]Size, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "none" {
+		return nil, nil
+	}
+	var sizes []Size
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		name, size, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok || !validName(name) {
+			return nil, fmt.Errorf("invalid variant %q: want name=size, named with lowercase letters, digits and dashes", field)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate variant %q", name)
+		}
+		n, err := strconv.Atoi(size)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid variant %q: size must be a positive number of pixels", field)
+		}
+		seen[name] = true
+		sizes = append(sizes, Size{Name: name, MaxSize: n})
+	}
+	return sizes, nil
+}
+
+// validName reports whether name is usable as a variant name, which ends
+// up in storage keys and file names.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Image is an encoded image.
+type Image struct {
+	Name        string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Result is a processed upload: the original, cleaned of metadata, and its
+// variants.
+type Result struct {
+	Data     []byte
+	Variants []Image
+}
+
+// Processor processes uploaded images.
+type Processor struct {
+	strip bool
+	sizes []Size
+}
+
+// New creates a Processor that strips metadata if strip is set and makes a
+// variant of each of sizes.
+func New(strip bool, sizes []Size) *Processor {
+	return &Processor{strip: strip, sizes: sizes}
+}
+
+// Handles reports whether images of mediaType are processed.
+func Handles(mediaType string) bool {
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Process cleans an image of mediaType and makes its variants. Variants
+// are only made of images smaller than the original and within the pixel
+// limit. It returns ErrInvalid if data cannot be parsed.
+func (p *Processor) Process(mediaType string, data []byte) (*Result, error) {
+	result := &Result{Data: data}
+	orientation := 1
+	var err error
+	if mediaType == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	if p.strip {
+		switch mediaType {
+		case "image/jpeg":
+			result.Data, err = stripJPEG(data)
+		case "image/png":
+			result.Data, err = stripPNG(data)
+		case "image/webp":
+			result.Data, err = stripWebP(data)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if mediaType == "image/webp" {
+		return result, nil
+	}
+
+	needsUpright := p.strip && orientation > 1
+	if len(p.sizes) == 0 && !needsUpright {
+		return result, nil
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return result, nil
+	}
+	src, err := decode(mediaType, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	img := orient(toRGBA(src), orientation)
+
+	// Stripping the orientation would leave the photo on its side, so it is
+	// turned upright and re-encoded instead.
+	if needsUpright {
+		if result.Data, err = encode(mediaType, img); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, size := range p.sizes {
+		w, h := fit(img.Bounds().Dx(), img.Bounds().Dy(), size.MaxSize)
+		if w == img.Bounds().Dx() && h == img.Bounds().Dy() {
+			continue
+		}
+		variant := Image{Name: size.Name, Width: w, Height: h, ContentType: variantType(mediaType)}
+		if variant.Data, err = encode(variant.ContentType, resize(img, w, h)); err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, variant)
+	}
+	return result, nil
+}
+
+// decode decodes an image of mediaType.
+func decode(mediaType string, data []byte) (image.Image, error) {
+	r := bytes.NewReader(data)
+	switch mediaType {
+	case "image/jpeg":
+		return jpeg.Decode(r)
+	case "image/png":
+		return png.Decode(r)
+	case "image/gif":
+		return gif.Decode(r)
+	}
+	return nil, fmt.Errorf("cannot decode %s", mediaType)
+}
+
+// encode encodes img as mediaType. GIFs are encoded as PNG, keeping their
+// transparency without cutting them down to a palette again.
+func encode(mediaType string, img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if mediaType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// variantType is the type of the variants of an image of mediaType.
+func variantType(mediaType string) string {
+	if mediaType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// fit scales w by h down to fit within size on its longer side, keeping
+// the aspect ratio. Images that already fit are left as they are.
+func fit(w, h, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+	if w >= h {
+		return size, max(1, h*size/w)
+	}
+	return max(1, w*size/h), size
+}
@@ -0,0 +1,87 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		w, h, size   int
+		wantW, wantH int
+	}{
+		{100, 50, 200, 100, 50},
+		{400, 200, 100, 100, 50},
+		{200, 400, 100, 50, 100},
+		{1000, 1, 100, 100, 1},
+		{300, 300, 100, 100, 100},
+	}
+	for _, tt := range tests {
+		if w, h := fit(tt.w, tt.h, tt.size); w != tt.wantW || h != tt.wantH {
+			t.Errorf("fit(%d, %d, %d) = %d, %d, want %d, %d", tt.w, tt.h, tt.size, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestResizeAverages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(0, 0, color.RGBA{R: 200, A: 255})
+	src.Set(1, 0, color.RGBA{R: 100, A: 255})
+	src.Set(0, 1, color.RGBA{G: 40, A: 255})
+	src.Set(1, 1, color.RGBA{B: 80, A: 255})
+
+	got := resize(src, 1, 1).RGBAAt(0, 0)
+	want := color.RGBA{R: 75, G: 10, B: 20, A: 255}
+	if got != want {
+		t.Errorf("resize() = %v, want %v", got, want)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 2x1 image: red then blue.
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	src.SetRGBA(0, 0, red)
+	src.SetRGBA(1, 0, blue)
+
+	tests := []struct {
+		orientation int
+		w, h        int
+		first       color.RGBA // pixel at 0,0
+	}{
+		{1, 2, 1, red},
+		{2, 2, 1, blue},
+		{3, 2, 1, blue},
+		{6, 1, 2, red},
+		{8, 1, 2, blue},
+	}
+	for _, tt := range tests {
+		got := orient(src, tt.orientation)
+		if b := got.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+			t.Errorf("orient(%d) is %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.w, tt.h)
+			continue
+		}
+		if p := got.RGBAAt(0, 0); p != tt.first {
+			t.Errorf("orient(%d) pixel 0,0 = %v, want %v", tt.orientation, p, tt.first)
+		}
+	}
+}
+
+func TestParseSizes(t *testing.T) {
+	sizes, err := ParseSizes("thumbnail=320, preview=1280")
+	if err != nil {
+		t.Fatalf("ParseSizes() error = %v", err)
+	}
+	if len(sizes) != 2 || sizes[0] != (Size{Name: "thumbnail", MaxSize: 320}) || sizes[1] != (Size{Name: "preview", MaxSize: 1280}) {
+		t.Errorf("ParseSizes() = %v", sizes)
+	}
+	if sizes, err := ParseSizes("none"); err != nil || sizes != nil {
+		t.Errorf("ParseSizes(none) = %v, %v, want no sizes", sizes, err)
+	}
+	for _, spec := range []string{"thumb", "Thumb=10", "a=0", "a=1,a=2", "a=x"} {
+		if _, err := ParseSizes(spec); err == nil {
+			t.Errorf("ParseSizes(%q) error = nil, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// JPEG markers.
+const (
+	markerTEM   = 0x01
+	markerRST0  = 0xD0
+	markerRST7  = 0xD7
+	markerSOI   = 0xD8
+	markerEOI   = 0xD9
+	markerSOS   = 0xDA
+	markerAPP0  = 0xE0
+	markerAPP1  = 0xE1
+	markerAPP2  = 0xE2
+	markerAPP14 = 0xEE
+	markerAPP15 = 0xEF
+	markerCOM   = 0xFE
+)
+
+// stripJPEG removes every metadata segment from a JPEG except the JFIF
+// header, the ICC color profile and the Adobe segment needed to decode
+// CMYK images. Anything after the end of the image, such as the preview
+// images of multi-picture files, is dropped as well.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return nil, fmt.Errorf("%w: not a jpeg", ErrInvalid)
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	for i := 2; ; {
+		for i < len(data) && data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0xFF {
+			i++ // fill bytes
+		}
+		if i+2 > len(data) || data[i] != 0xFF {
+			return nil, fmt.Errorf("%w: truncated jpeg", ErrInvalid)
+		}
+		marker := data[i+1]
+		if marker == markerEOI {
+			return append(out, data[i:i+2]...), nil
+		}
+		if standaloneMarker(marker) {
+			out = append(out, data[i:i+2]...)
+			i += 2
+			continue
+		}
+		if i+4 > len(data) {
+			return nil, fmt.Errorf("%w: truncated jpeg", ErrInvalid)
+		}
+		n := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + n
+		if n < 2 || end > len(data) {
+			return nil, fmt.Errorf("%w: truncated jpeg", ErrInvalid)
+		}
+		if marker == markerSOS {
+			eoi := scanEnd(data, end)
+			if eoi < 0 {
+				return nil, fmt.Errorf("%w: truncated jpeg", ErrInvalid)
+			}
+			out = append(out, data[i:eoi]...)
+			i = eoi
+			continue
+		}
+		if keepJPEGSegment(marker, data[i+4:end]) {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+}
+
+// standaloneMarker reports whether marker stands alone, with no length or
+// payload after it.
+func standaloneMarker(marker byte) bool {
+	return marker == markerTEM || (marker >= markerRST0 && marker <= markerRST7)
+}
+
+// scanEnd returns where the entropy-coded data starting at i ends, at the
+// next marker other than a restart marker, or -1 if none follows.
+func scanEnd(data []byte, i int) int {
+	for ; i+1 < len(data); i++ {
+		if data[i] != 0xFF {
+			continue
+		}
+		next := data[i+1]
+		if next == 0x00 || next == 0xFF || (next >= markerRST0 && next <= markerRST7) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// keepJPEGSegment reports whether a segment with marker and payload is kept.
+func keepJPEGSegment(marker byte, payload []byte) bool {
+	switch {
+	case marker == markerCOM:
+		return false
+	case marker == markerAPP0, marker == markerAPP14:
+		return true
+	case marker == markerAPP2:
+		return bytes.HasPrefix(payload, []byte("ICC_PROFILE\x00"))
+	case marker >= markerAPP1 && marker <= markerAPP15:
+		return false
+	}
+	return true
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, 1 for upright,
+// or 1 if it has none.
+func jpegOrientation(data []byte) int {
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == markerSOS || marker == markerEOI {
+			break
+		}
+		n := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + n
+		if n < 2 || end > len(data) {
+			break
+		}
+		payload := data[i+4 : end]
+		if marker == markerAPP1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			return exifOrientation(payload[6:])
+		}
+		i = end
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF
+// structure.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := range entries {
+		off := ifd + 2 + e*12
+		if off+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[off:]) == 0x0112 {
+			if o := int(order.Uint16(tiff[off+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// pngMetadata are the PNG chunks that carry metadata rather than pixels.
+var pngMetadata = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// stripPNG removes the EXIF, text and timestamp chunks from a PNG.
+func stripPNG(data []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return nil, fmt.Errorf("%w: not a png", ErrInvalid)
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, signature...)
+	for i := len(signature); ; {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated png", ErrInvalid)
+		}
+		n := int(binary.BigEndian.Uint32(data[i:]))
+		typ := string(data[i+4 : i+8])
+		end := i + 12 + n
+		if n < 0 || end > len(data) {
+			return nil, fmt.Errorf("%w: truncated png", ErrInvalid)
+		}
+		if !pngMetadata[typ] {
+			out = append(out, data[i:end]...)
+		}
+		if typ == "IEND" {
+			return out, nil
+		}
+		i = end
+	}
+}
+
+// VP8X flags marking EXIF and XMP metadata.
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// stripWebP removes the EXIF and XMP chunks from a WebP. Simple WebPs have
+// no room for metadata and are returned as they are.
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: not a webp", ErrInvalid)
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated webp", ErrInvalid)
+		}
+		fourCC := string(data[i : i+4])
+		n := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + n + n%2
+		if n < 0 || end > len(data) {
+			return nil, fmt.Errorf("%w: truncated webp", ErrInvalid)
+		}
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[i:end]...)
+			if n > 0 {
+				out[start+8] &^= webpFlagEXIF | webpFlagXMP
+			}
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
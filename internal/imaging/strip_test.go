@@ -0,0 +1,181 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage returns a w by h image with a horizontal gradient.
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / max(1, w-1)), G: 64, B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// jpegSegment returns a JPEG segment with marker and payload.
+func jpegSegment(marker byte, payload []byte) []byte {
+	seg := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// exifPayload returns an APP1 EXIF payload holding only an orientation.
+func exifPayload(orientation uint16) []byte {
+	tiff := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	return tiff
+}
+
+// withSegments inserts segments into a JPEG right after its SOI marker.
+func withSegments(data []byte, segments ...[]byte) []byte {
+	out := append([]byte{}, data[:2]...)
+	for _, seg := range segments {
+		out = append(out, seg...)
+	}
+	return append(out, data[2:]...)
+}
+
+func TestStripJPEGRoundTrip(t *testing.T) {
+	data := encodeJPEG(t, testImage(64, 48))
+
+	got, err := stripJPEG(data)
+	if err != nil {
+		t.Fatalf("stripJPEG() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("stripJPEG() changed a JPEG without metadata: got %d bytes, want %d", len(got), len(data))
+	}
+}
+
+func TestStripJPEGRemovesMetadata(t *testing.T) {
+	clean := encodeJPEG(t, testImage(64, 48))
+	data := withSegments(clean,
+		jpegSegment(markerAPP1, exifPayload(1)),
+		jpegSegment(markerCOM, []byte("shot at home")),
+		jpegSegment(markerAPP2, []byte("ICC_PROFILE\x00\x01\x01profile")),
+	)
+	data = append(data, "trailing preview"...)
+
+	got, err := stripJPEG(data)
+	if err != nil {
+		t.Fatalf("stripJPEG() error = %v", err)
+	}
+	for _, leaked := range []string{"Exif", "shot at home", "trailing preview"} {
+		if bytes.Contains(got, []byte(leaked)) {
+			t.Errorf("stripJPEG() kept %q", leaked)
+		}
+	}
+	if !bytes.Contains(got, []byte("ICC_PROFILE")) {
+		t.Error("stripJPEG() dropped the ICC profile")
+	}
+	img, err := jpeg.Decode(bytes.NewReader(got))
+	if err != nil {
+		t.Fatalf("jpeg.Decode() of stripped image error = %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 48 {
+		t.Errorf("stripped image is %dx%d, want 64x48", b.Dx(), b.Dy())
+	}
+}
+
+func TestStripJPEGInvalid(t *testing.T) {
+	data := encodeJPEG(t, testImage(16, 16))
+	tests := map[string][]byte{
+		"empty":     nil,
+		"not jpeg":  []byte("GIF89a......"),
+		"truncated": data[:len(data)/2],
+		"no eoi":    data[:len(data)-2],
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := stripJPEG(data); !errors.Is(err, ErrInvalid) {
+				t.Errorf("stripJPEG() error = %v, want %v", err, ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	clean := encodeJPEG(t, testImage(8, 8))
+	if got := jpegOrientation(clean); got != 1 {
+		t.Errorf("jpegOrientation() without EXIF = %d, want 1", got)
+	}
+	rotated := withSegments(clean, jpegSegment(markerAPP1, exifPayload(6)))
+	if got := jpegOrientation(rotated); got != 6 {
+		t.Errorf("jpegOrientation() = %d, want 6", got)
+	}
+}
+
+func TestStripPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(16, 8)); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	data := buf.Bytes()
+	// Insert a tEXt chunk before IEND, the last 12 bytes.
+	text := []byte("Comment\x00secret location")
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)))
+	chunk = append(chunk, "tEXt"...)
+	chunk = append(chunk, text...)
+	chunk = append(chunk, 0, 0, 0, 0)
+	withText := append(append(append([]byte{}, data[:len(data)-12]...), chunk...), data[len(data)-12:]...)
+
+	got, err := stripPNG(withText)
+	if err != nil {
+		t.Fatalf("stripPNG() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("stripPNG() = %d bytes, want the original %d bytes", len(got), len(data))
+	}
+	if _, err := stripPNG(data[:len(data)-4]); !errors.Is(err, ErrInvalid) {
+		t.Errorf("stripPNG() of truncated PNG error = %v, want %v", err, ErrInvalid)
+	}
+}
+
+func TestProcessJPEG(t *testing.T) {
+	data := withSegments(encodeJPEG(t, testImage(80, 40)), jpegSegment(markerAPP1, exifPayload(6)))
+
+	result, err := New(true, []Size{{Name: "thumb", MaxSize: 20}}).Process("image/jpeg", data)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	upright, err := jpeg.Decode(bytes.NewReader(result.Data))
+	if err != nil {
+		t.Fatalf("jpeg.Decode() of result error = %v", err)
+	}
+	if b := upright.Bounds(); b.Dx() != 40 || b.Dy() != 80 {
+		t.Errorf("upright image is %dx%d, want 40x80", b.Dx(), b.Dy())
+	}
+	if bytes.Contains(result.Data, []byte("Exif")) {
+		t.Error("Process() kept the EXIF segment")
+	}
+	if len(result.Variants) != 1 {
+		t.Fatalf("Process() made %d variants, want 1", len(result.Variants))
+	}
+	if v := result.Variants[0]; v.Width != 10 || v.Height != 20 || v.ContentType != "image/jpeg" {
+		t.Errorf("variant = %dx%d %s, want 10x20 image/jpeg", v.Width, v.Height, v.ContentType)
+	}
+}
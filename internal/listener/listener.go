@@ -0,0 +1,59 @@
+// Package listener creates the server's TCP listener, supporting inherited
+// sockets and SO_REUSEPORT so a new binary can take over without dropping
+// connections.
+package listener
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first inherited file descriptor under the
+// systemd socket activation protocol (LISTEN_FDS / LISTEN_PID).
+const listenFDsStart = 3
+
+// Listen returns a listener for addr. If the process inherited a listening
+// socket (systemd socket activation or a supervisor using the same protocol),
+// that socket is used. Otherwise a new socket is bound, with SO_REUSEPORT when
+// reusePort is set so an old and a new process can accept concurrently during
+// a rolling restart.
+func Listen(ctx context.Context, addr string, reusePort bool) (net.Listener, error) {
+	if ln, ok, err := inherited(); ok || err != nil {
+		return ln, err
+	}
+
+	var lc net.ListenConfig
+	if reusePort {
+		lc.Control = reusePortControl
+	}
+
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	return ln, nil
+}
+
+// inherited returns the first listening socket passed by the parent process.
+func inherited() (net.Listener, bool, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, false, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, false, nil
+	}
+
+	f := os.NewFile(uintptr(listenFDsStart), "listener")
+	defer f.Close()
+
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, true, fmt.Errorf("use inherited listener: %w", err)
+	}
+	return ln, true, nil
+}
@@ -0,0 +1,109 @@
+// Package locking runs background tasks on exactly one instance of a
+// multi-replica deployment using Postgres session-level advisory locks.
+package locking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// healthInterval is how often a held lock's connection is checked. If the
+// connection is lost Postgres releases the lock, so the task must stop.
+const healthInterval = 10 * time.Second
+
+// ErrLockLost is the cancellation cause of a task whose lock connection failed.
+var ErrLockLost = errors.New("advisory lock lost")
+
+// Locker acquires named advisory locks.
+type Locker struct {
+	pool *pgxpool.Pool
+}
+
+// New creates a Locker that takes connections from pool.
+func New(pool *pgxpool.Pool) *Locker {
+	return &Locker{pool: pool}
+}
+
+// key maps a lock name to the advisory lock key space.
+func key(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// TryRun runs fn while holding the named lock and reports whether it ran.
+// If another instance holds the lock, TryRun returns false immediately.
+// fn's context is cancelled with ErrLockLost if the lock's connection fails.
+func (l *Locker) TryRun(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquire connection for lock %q: %w", name, err)
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key(name)).Scan(&locked); err != nil {
+		return false, fmt.Errorf("try lock %q: %w", name, err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan error, 1)
+	go func() { done <- fn(taskCtx) }()
+
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			// Unlock with a fresh context so a cancelled parent does not leave
+			// the lock held on a connection returned to the pool.
+			unlockCtx, unlockCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer unlockCancel()
+			if _, uerr := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, key(name)); uerr != nil {
+				// Closing the connection releases every lock it holds.
+				conn.Conn().Close(unlockCtx)
+				slog.Error("failed to release advisory lock", "lock", name, "error", uerr)
+			}
+			return true, err
+		case <-ticker.C:
+			if err := conn.Ping(taskCtx); err != nil && taskCtx.Err() == nil {
+				slog.Error("advisory lock connection failed", "lock", name, "error", err)
+				cancel(ErrLockLost)
+			}
+		}
+	}
+}
+
+// Singleton keeps trying to take the named lock every retry interval and
+// runs fn whenever it holds it, until ctx is cancelled. Use it for work such
+// as schedulers that must run on one instance at a time; if the instance
+// holding the lock goes away, another takes over within one retry interval.
+func (l *Locker) Singleton(ctx context.Context, name string, retry time.Duration, fn func(ctx context.Context) error) {
+	for {
+		ran, err := l.TryRun(ctx, name, fn)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.Error("singleton task failed", "lock", name, "error", err)
+		case ran:
+			slog.Info("singleton task stopped", "lock", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
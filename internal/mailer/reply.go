@@ -0,0 +1,89 @@
+package mailer
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// replySignatureBytes is how much of the HMAC a reply address carries.
+const replySignatureBytes = 10
+
+// ErrInvalidReplyAddress is returned for reply addresses that were not made
+// by ReplyAddress with the same secret.
+var ErrInvalidReplyAddress = errors.New("invalid reply address")
+
+// ReplyAddress returns an address at domain that identifies replies by a
+// user to a notification about an issue, as in
+// reply+42.7.<signature>@domain. The address is signed with secret, so it
+// needs no storage, and is lowercase so mail servers that fold the case of
+// local parts keep it intact.
+func ReplyAddress(secret []byte, domain string, userID, issueID int64) string {
+	claims := strconv.FormatInt(userID, 10) + "." + strconv.FormatInt(issueID, 10)
+	return "reply+" + claims + "." + replySignature(secret, claims) + "@" + domain
+}
+
+// ParseReplyAddress verifies an address made by ReplyAddress, optionally
+// with a display name, and returns the user and issue it identifies.
+func ParseReplyAddress(secret []byte, address string) (userID, issueID int64, err error) {
+	addr, err := mail.ParseAddress(address)
+	if err != nil {
+		return 0, 0, ErrInvalidReplyAddress
+	}
+	local, _, _ := strings.Cut(strings.ToLower(addr.Address), "@")
+	token, ok := strings.CutPrefix(local, "reply+")
+	if !ok {
+		return 0, 0, ErrInvalidReplyAddress
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, 0, ErrInvalidReplyAddress
+	}
+	claims := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(replySignature(secret, claims))) {
+		return 0, 0, ErrInvalidReplyAddress
+	}
+	if userID, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return 0, 0, ErrInvalidReplyAddress
+	}
+	if issueID, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return 0, 0, ErrInvalidReplyAddress
+	}
+	return userID, issueID, nil
+}
+
+func replySignature(secret []byte, claims string) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "reply:%s", claims)
+	return hex.EncodeToString(mac.Sum(nil)[:replySignatureBytes])
+}
+
+// replyHeader matches the line mail clients put above the quoted message,
+// such as "On Mon, 1 Jan 2026 at 10:00, Issues <issues@example.com> wrote:".
+var replyHeader = regexp.MustCompile(`(?i)^(on\s.+wrote:|-+\s*original message\s*-+)$`)
+
+// StripReply returns the text a user wrote in a plain-text reply, without
+// the quoted message and signature below it.
+func StripReply(text string) string {
+	var lines []string
+	s := bufio.NewScanner(strings.NewReader(text))
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if line == "--" || replyHeader.MatchString(strings.TrimSpace(line)) {
+			break
+		}
+		if strings.HasPrefix(line, ">") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,279 @@
+// Package mcp implements the server side of the Model Context Protocol over
+// the stdio transport, enough to offer tools to AI agents such as Claude
+// Code: initialization, tools/list and tools/call.
+//
+// Messages are JSON-RPC 2.0, one per line. Requests are handled in order.
+// Logs must go to stderr, as stdout carries the protocol.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+)
+
+// protocolVersions are the protocol revisions the server speaks, newest
+// first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// maxMessage bounds the size of one incoming message.
+const maxMessage = 4 << 20
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a function agents may call.
+type Tool struct {
+	Name        string
+	Description string
+	// Input is the JSON Schema of the tool's arguments, an object.
+	Input *Schema
+	// ReadOnly tells clients the tool changes nothing, so they may call it
+	// without asking.
+	ReadOnly bool
+	// Call runs the tool with its arguments. Its result is returned to the
+	// agent as JSON. An error is shown to the agent as a failed call, not a
+	// protocol error, so it can correct itself.
+	Call func(ctx context.Context, args json.RawMessage) (any, error)
+}
+
+// Typed adapts fn, which takes its arguments decoded into a T, into a
+// Tool's Call.
+func Typed[T any](fn func(ctx context.Context, args T) (any, error)) func(context.Context, json.RawMessage) (any, error) {
+	return func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var args T
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		return fn(ctx, args)
+	}
+}
+
+// Schema is the subset of JSON Schema tool inputs use.
+type Schema struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+}
+
+// Server offers tools over MCP.
+type Server struct {
+	name         string
+	version      string
+	instructions string
+	tools        []Tool
+}
+
+// NewServer creates a server that introduces itself with name and version.
+// instructions, if not empty, tell the agent how to use the tools.
+func NewServer(name, version, instructions string) *Server {
+	return &Server{name: name, version: version, instructions: instructions}
+}
+
+// AddTool offers a tool. Tools must be added before Serve.
+func (s *Server) AddTool(t Tool) {
+	s.tools = append(s.tools, t)
+}
+
+// request is an incoming request or, without an ID, notification.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Serve reads requests from r and writes responses to w until r ends or ctx
+// is done.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	send := func(resp response) error { return enc.Encode(resp) }
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxMessage)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		var line []byte
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			return err
+		case line = <-lines:
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			code := codeParseError
+			if json.Valid(line) {
+				code = codeInvalidRequest
+			}
+			if err := send(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: code, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, err := s.handle(ctx, req)
+		if len(req.ID) == 0 {
+			// Notifications get no response.
+			continue
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID, Result: result}
+		if err != nil {
+			var rerr *rpcError
+			if !errors.As(err, &rerr) {
+				rerr = &rpcError{Code: codeInvalidRequest, Message: err.Error()}
+			}
+			resp.Result, resp.Error = nil, rerr
+		}
+		if err := send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers one request.
+func (s *Server) handle(ctx context.Context, req request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		version := protocolVersions[0]
+		if slices.Contains(protocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		result := map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}
+		if s.instructions != "" {
+			result["instructions"] = s.instructions
+		}
+		return result, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": s.listTools()}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		if len(req.ID) == 0 {
+			// Unknown notifications, such as notifications/initialized and
+			// notifications/cancelled, need no handling.
+			return nil, nil
+		}
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+func (s *Server) listTools() []map[string]any {
+	tools := make([]map[string]any, 0, len(s.tools))
+	for _, t := range s.tools {
+		tool := map[string]any{
+			"name":        t.Name,
+			"description": t.Description,
+			"inputSchema": t.Input,
+		}
+		if t.ReadOnly {
+			tool["annotations"] = map[string]bool{"readOnlyHint": true}
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// callTool runs a tool and wraps its result, or error, as content for the
+// agent.
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (any, error) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == params.Name })
+	if i < 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", params.Name)}
+	}
+	if len(params.Arguments) == 0 {
+		params.Arguments = json.RawMessage("{}")
+	}
+
+	result, err := s.tools[i].Call(ctx, params.Arguments)
+	if err != nil {
+		slog.Warn("mcp tool failed", "tool", params.Name, "error", err)
+		return toolResult(err.Error(), true), nil
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolResult(fmt.Sprintf("encode result: %v", err), true), nil
+	}
+	return toolResult(string(text), false), nil
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package metrics defines the server's Prometheus metrics. They are
+// registered with the default registry and served at /metrics on the
+// internal listener, together with the Go runtime and process metrics.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// Handler serves the registered metrics in the Prometheus exposition
+// format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// HTTP metrics.
+var (
+	PanicsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "http_panics_total",
+		Help: "Handler panics recovered.",
+	})
+)
+
+// Database metrics, labelled by repository.
+var (
+	DBQueriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_queries_total",
+		Help: "Database queries issued.",
+	}, []string{"repository"})
+	DBQueryErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Database queries that failed.",
+	}, []string{"repository"})
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Time taken by database queries.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository"})
+)
+
+// ObserveQuery records a query issued by the named repository. sql.ErrNoRows
+// is an expected outcome and not counted as an error.
+func ObserveQuery(repo string, d time.Duration, err error) {
+	DBQueriesTotal.WithLabelValues(repo).Inc()
+	DBQueryDuration.WithLabelValues(repo).Observe(d.Seconds())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		DBQueryErrorsTotal.WithLabelValues(repo).Inc()
+	}
+}
+
+// Scheduled task metrics, labelled by task name.
+var (
+	TaskRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_runs_total",
+		Help: "Scheduled task runs.",
+	}, []string{"task"})
+	TaskFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_failures_total",
+		Help: "Scheduled task runs that failed.",
+	}, []string{"task"})
+	TaskSkippedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_skipped_total",
+		Help: "Scheduled task runs skipped because the previous run had not finished.",
+	}, []string{"task"})
+	TaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scheduler_duration_seconds",
+		Help:    "Time taken by scheduled task runs.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 1800},
+	}, []string{"task"})
+	TaskLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "scheduler_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful run of a scheduled task.",
+	}, []string{"task"})
+)
+
+// ObserveTask records a run of the named scheduled task.
+func ObserveTask(task string, d time.Duration, err error) {
+	TaskRunsTotal.WithLabelValues(task).Inc()
+	TaskDuration.WithLabelValues(task).Observe(d.Seconds())
+	if err != nil {
+		TaskFailuresTotal.WithLabelValues(task).Inc()
+		return
+	}
+	TaskLastSuccess.WithLabelValues(task).SetToCurrentTime()
+}
+
+// ObserveTaskSkipped records a run of the named scheduled task skipped
+// because the previous run had not finished.
+func ObserveTaskSkipped(task string) {
+	TaskSkippedTotal.WithLabelValues(task).Inc()
+}
+
+// PoolStats is a snapshot of the database connection pool.
+type PoolStats struct {
+	MaxConns                int32 `json:"max_conns"`
+	TotalConns              int32 `json:"total_conns"`
+	AcquiredConns           int32 `json:"acquired_conns"`
+	IdleConns               int32 `json:"idle_conns"`
+	ConstructingConns       int32 `json:"constructing_conns"`
+	AcquireCount            int64 `json:"acquire_count"`
+	WaitCount               int64 `json:"wait_count"`
+	WaitDurationMs          int64 `json:"wait_duration_ms"`
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+}
+
+// NewPoolStats converts pgx pool statistics. Waits are acquisitions that
+// found no idle connection.
+func NewPoolStats(s *pgxpool.Stat) PoolStats {
+	return PoolStats{
+		MaxConns:                s.MaxConns(),
+		TotalConns:              s.TotalConns(),
+		AcquiredConns:           s.AcquiredConns(),
+		IdleConns:               s.IdleConns(),
+		ConstructingConns:       s.ConstructingConns(),
+		AcquireCount:            s.AcquireCount(),
+		WaitCount:               s.EmptyAcquireCount(),
+		WaitDurationMs:          s.EmptyAcquireWaitTime().Milliseconds(),
+		CanceledAcquireCount:    s.CanceledAcquireCount(),
+		MaxIdleDestroyCount:     s.MaxIdleDestroyCount(),
+		MaxLifetimeDestroyCount: s.MaxLifetimeDestroyCount(),
+	}
+}
+
+// poolCollector reads the connection pool's statistics on each scrape.
+type poolCollector struct {
+	stats func() PoolStats
+}
+
+var (
+	poolConnsDesc = prometheus.NewDesc("db_pool_conns",
+		"Connections in the database pool by state: acquired, idle or constructing.", []string{"state"}, nil)
+	poolMaxConnsDesc = prometheus.NewDesc("db_pool_max_conns",
+		"Most connections the database pool may open.", nil, nil)
+	poolAcquiresDesc = prometheus.NewDesc("db_pool_acquires_total",
+		"Connections acquired from the database pool.", nil, nil)
+	poolWaitsDesc = prometheus.NewDesc("db_pool_waits_total",
+		"Acquisitions that found no idle connection and had to wait.", nil, nil)
+	poolWaitSecondsDesc = prometheus.NewDesc("db_pool_wait_seconds_total",
+		"Time spent waiting for a connection.", nil, nil)
+	poolCanceledDesc = prometheus.NewDesc("db_pool_canceled_acquires_total",
+		"Acquisitions cancelled before a connection was available.", nil, nil)
+	poolDestroyedDesc = prometheus.NewDesc("db_pool_destroyed_conns_total",
+		"Connections closed by the pool, by reason: max_idle or max_lifetime.", []string{"reason"}, nil)
+)
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(s.AcquiredConns), "acquired")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(s.IdleConns), "idle")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(s.ConstructingConns), "constructing")
+	ch <- prometheus.MustNewConstMetric(poolMaxConnsDesc, prometheus.GaugeValue, float64(s.MaxConns))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(s.AcquireCount))
+	ch <- prometheus.MustNewConstMetric(poolWaitsDesc, prometheus.CounterValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(poolWaitSecondsDesc, prometheus.CounterValue, float64(s.WaitDurationMs)/1000)
+	ch <- prometheus.MustNewConstMetric(poolCanceledDesc, prometheus.CounterValue, float64(s.CanceledAcquireCount))
+	ch <- prometheus.MustNewConstMetric(poolDestroyedDesc, prometheus.CounterValue, float64(s.MaxIdleDestroyCount), "max_idle")
+	ch <- prometheus.MustNewConstMetric(poolDestroyedDesc, prometheus.CounterValue, float64(s.MaxLifetimeDestroyCount), "max_lifetime")
+}
+
+// PublishPoolStats exposes pool statistics as the db_pool_* metrics. It
+// must be called at most once.
+func PublishPoolStats(stats func() PoolStats) {
+	prometheus.MustRegister(poolCollector{stats: stats})
+}
+
+// PublishAIQueueDepth exposes the number of pending AI jobs as
+// ai_queue_depth. It must be called at most once.
+func PublishAIQueueDepth(depth func() (int, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ai_queue_depth",
+		Help: "AI jobs waiting to run.",
+	}, func() float64 {
+		n, err := depth()
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(n)
+	})
+}
+
+// PublishAIWorkers exposes the AI worker pool of this instance as the
+// ai_workers and ai_workers_busy metrics. It must be called at most once.
+func PublishAIWorkers(stats func() []domain.WorkerStats) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ai_workers",
+		Help: "AI workers on this instance.",
+	}, func() float64 {
+		return float64(len(stats()))
+	})
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ai_workers_busy",
+		Help: "AI workers on this instance running a job.",
+	}, func() float64 {
+		busy := 0
+		for _, w := range stats() {
+			if w.Busy {
+				busy++
+			}
+		}
+		return float64(busy)
+	})
+}
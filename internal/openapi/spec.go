@@ -0,0 +1,327 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+// Op describes the bodies and parameters of a route.
+type Op struct {
+	Summary     string
+	Description string
+	// Request is a value of the request body type, or nil if the route
+	// takes no body.
+	Request any
+	// Response is a value of the type in the data field of the response, or
+	// nil if the route responds without a body.
+	Response any
+	// Status is the success status. It defaults to 200, or 204 without a
+	// Response.
+	Status int
+	// List marks paginated list responses, whose data is an array of
+	// Response and which take cursor and limit parameters.
+	List bool
+	// ContentType is set for responses that are not JSON, such as
+	// text/event-stream.
+	ContentType string
+	Query       []Param
+}
+
+// Param is a query parameter.
+type Param struct {
+	Name        string
+	Description string
+	// Type is a JSON Schema type; it defaults to string.
+	Type     string
+	Repeated bool
+}
+
+// Spec collects route descriptions and builds the document.
+type Spec struct {
+	info   Info
+	prefix string
+	ops    map[string]Op
+	public []string
+	hidden []string
+}
+
+// New creates a Spec for the API served under prefix, such as "/api/v1".
+// Routes outside prefix are not documented.
+func New(info Info, prefix string) *Spec {
+	return &Spec{info: info, prefix: prefix, ops: make(map[string]Op)}
+}
+
+// Describe documents the route with method and echo path, such as
+// "/projects/:pid", relative to the spec's prefix.
+func (s *Spec) Describe(method, path string, op Op) {
+	s.ops[method+" "+s.prefix+path] = op
+}
+
+// Public marks routes under the given path prefixes as not requiring a
+// token.
+func (s *Spec) Public(prefixes ...string) {
+	for _, p := range prefixes {
+		s.public = append(s.public, s.prefix+p)
+	}
+}
+
+// Hide leaves routes under the given path prefixes out of the document.
+func (s *Spec) Hide(prefixes ...string) {
+	for _, p := range prefixes {
+		s.hidden = append(s.hidden, s.prefix+p)
+	}
+}
+
+// Build documents routes. It panics if a description names a route that is
+// not registered, so descriptions cannot silently go stale.
+func (s *Spec) Build(routes []*echo.Route) *Document {
+	reg := newRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.info,
+		Servers: []Server{{URL: s.prefix}},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: reg.schemas,
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "Error",
+					Content: jsonContent(&Schema{
+						Type:       "object",
+						Properties: map[string]*Schema{"error": reg.schemaOf(reflect.TypeFor[apiclient.Error]())},
+						Required:   []string{"error"},
+					}),
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearer": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "A session access token or a personal access token (iss_pat_...)",
+				},
+			},
+		},
+	}
+
+	described := make(map[string]bool)
+	ids := make(map[string]int)
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, s.prefix+"/") || hasPrefix(route.Path, s.hidden) || route.Method == echo.RouteNotFound {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		op, ok := s.ops[key]
+		described[key] = ok
+
+		tag, method := handlerName(route.Name)
+		o := s.operation(reg, route, op, ok, method)
+		if tag != "" {
+			o.Tags = []string{tag}
+			tags[tag] = true
+		}
+		o.OperationID = lowerFirst(tag) + method
+		if o.OperationID == "" {
+			o.OperationID = strings.ToLower(route.Method) + pathID(route.Path)
+		}
+		if ids[o.OperationID]++; ids[o.OperationID] > 1 {
+			o.OperationID += fmt.Sprint(ids[o.OperationID])
+		}
+		if !hasPrefix(route.Path, s.public) {
+			o.Security = []map[string][]string{{"bearer": {}}}
+		}
+
+		path := strings.TrimPrefix(openAPIPath(route.Path), s.prefix)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = o
+	}
+
+	for key := range s.ops {
+		if _, ok := described[key]; !ok {
+			panic("openapi: description of unregistered route " + key)
+		}
+	}
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+// operation documents one route.
+func (s *Spec) operation(reg *registry, route *echo.Route, op Op, described bool, method string) *Operation {
+	o := &Operation{
+		Summary:     op.Summary,
+		Description: op.Description,
+		Responses:   map[string]*Response{"default": {Ref: "#/components/responses/Error"}},
+	}
+	if o.Summary == "" {
+		o.Summary = words(method)
+	}
+
+	for _, name := range pathParams(route.Path) {
+		schema := &Schema{Type: "integer", Format: "int64"}
+		if name == "type" {
+			schema = &Schema{Type: "string"}
+		}
+		o.Parameters = append(o.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	if op.List {
+		o.Parameters = append(o.Parameters,
+			Parameter{Name: "cursor", In: "query", Description: "next_cursor of the previous page", Schema: &Schema{Type: "string"}},
+			Parameter{Name: "limit", In: "query", Description: "page size", Schema: &Schema{Type: "integer", Minimum: float(1), Maximum: float(100)}},
+		)
+	}
+	for _, q := range op.Query {
+		schema := &Schema{Type: q.Type}
+		if schema.Type == "" {
+			schema.Type = "string"
+		}
+		if q.Repeated {
+			schema = &Schema{Type: "array", Items: schema}
+		}
+		o.Parameters = append(o.Parameters, Parameter{Name: q.Name, In: "query", Description: q.Description, Schema: schema})
+	}
+
+	if op.Request != nil {
+		o.RequestBody = &RequestBody{Required: true, Content: jsonContent(reg.schemaOf(reflect.TypeOf(op.Request)))}
+	}
+
+	status := op.Status
+	switch {
+	case !described:
+		o.Responses["2XX"] = &Response{Description: "Success"}
+		return o
+	case status == 0 && op.Response == nil && op.ContentType == "":
+		status = http.StatusNoContent
+	case status == 0:
+		status = http.StatusOK
+	}
+	res := &Response{Description: http.StatusText(status)}
+	switch {
+	case op.ContentType != "":
+		res.Content = map[string]MediaType{op.ContentType: {Schema: &Schema{Type: "string"}}}
+	case op.Response != nil:
+		data := reg.schemaOf(reflect.TypeOf(op.Response))
+		res.Content = jsonContent(envelope(reg, data, op.List))
+	}
+	o.Responses[fmt.Sprint(status)] = res
+	return o
+}
+
+// envelope is the schema of the standard response wrapper with data in its
+// data field, or a page of data if list is set.
+func envelope(reg *registry, data *Schema, list bool) *Schema {
+	if !list {
+		return &Schema{Type: "object", Properties: map[string]*Schema{"data": data}, Required: []string{"data"}}
+	}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data": {Type: "array", Items: data},
+			"meta": reg.schemaOf(reflect.TypeFor[apiclient.Meta]()),
+		},
+		Required: []string{"data", "meta"},
+	}
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+var handlerPattern = regexp.MustCompile(`\(\*(\w+)\)\.(\w+)-fm$`)
+
+// handlerName splits the name echo gives a route, the name of its handler
+// function, into a tag and method name: "(*IssueHandler).List-fm" becomes
+// "Issue" and "List". Routes served by plain functions have neither.
+func handlerName(name string) (tag, method string) {
+	m := handlerPattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", ""
+	}
+	return strings.TrimSuffix(m[1], "Handler"), m[2]
+}
+
+var paramPattern = regexp.MustCompile(`:(\w+)`)
+
+// openAPIPath turns echo path parameters into OpenAPI ones.
+func openAPIPath(path string) string {
+	return paramPattern.ReplaceAllString(path, "{$1}")
+}
+
+func pathParams(path string) []string {
+	var names []string
+	for _, m := range paramPattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// pathID derives an operation ID suffix from a path.
+func pathID(path string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		b.WriteString(exported(part))
+	}
+	return b.String()
+}
+
+// words splits a Go method name into words, keeping acronyms together:
+// "MarkAllRead" becomes "Mark all read" and "AIJobStats" "AI job stats".
+func words(name string) string {
+	r := []rune(name)
+	var b strings.Builder
+	start := 0
+	for i := 1; i <= len(r); i++ {
+		boundary := i == len(r) ||
+			unicode.IsUpper(r[i]) && (unicode.IsLower(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1]))
+		if !boundary {
+			continue
+		}
+		word := string(r[start:i])
+		if start > 0 {
+			b.WriteByte(' ')
+			if len(word) == 1 || !unicode.IsUpper(r[start+1]) {
+				word = strings.ToLower(word)
+			}
+		}
+		b.WriteString(word)
+		start = i
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func hasPrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func float(v float64) *float64 {
+	return &v
+}
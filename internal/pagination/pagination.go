@@ -0,0 +1,148 @@
+// Package pagination implements the opaque cursors and page parameters
+// shared by every list endpoint.
+//
+// A cursor records where the previous page ended: the time and ID of its
+// last item for lists ordered by time, an offset for ranked results, or
+// the time, kind and ID of its last item for lists merged from several
+// sources in time order. Clients pass the cursor back unchanged and must not rely on its
+// format.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/pkg/apiclient"
+)
+
+const (
+	// DefaultLimit is the page size used when none is requested.
+	DefaultLimit = 20
+	// MaxLimit is the largest page size a client may request.
+	MaxLimit = 100
+)
+
+// ErrInvalidCursor is returned for cursors that were not issued by Encode.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position a page starts after. The zero Cursor is the start
+// of the list.
+type Cursor struct {
+	// ID is the ID of the last item of the previous page.
+	ID int64 `json:"i,omitempty"`
+	// Offset is the number of ranked results already returned.
+	Offset int64 `json:"o,omitempty"`
+	// At is the time the last item of the previous page is ordered by, in
+	// Unix microseconds, and Kind the source it came from in a merged list.
+	At   int64  `json:"t,omitempty"`
+	Kind string `json:"k,omitempty"`
+}
+
+// After returns the cursor for a page that ended with the item id, ordered
+// by at.
+func After(at time.Time, id int64) Cursor {
+	return Cursor{ID: id, At: at.UnixMicro()}
+}
+
+// AfterItem returns the cursor for a page of a merged list that ended with
+// the item of the kind with id, created at.
+func AfterItem(at time.Time, kind string, id int64) Cursor {
+	return Cursor{ID: id, At: at.UnixMicro(), Kind: kind}
+}
+
+// Time returns the time recorded by After or AfterItem, or the zero time.
+func (c Cursor) Time() time.Time {
+	if c.At == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(c.At)
+}
+
+// AtOffset returns the cursor for ranked results starting at offset.
+func AtOffset(offset int64) Cursor {
+	return Cursor{Offset: offset}
+}
+
+// IsZero reports whether c is the start of the list.
+func (c Cursor) IsZero() bool {
+	return c == Cursor{}
+}
+
+// Encode returns the opaque form of the cursor.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode parses a cursor returned by Encode. An empty string is the start
+// of the list.
+func Decode(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID < 0 || c.Offset < 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Page is a requested page: where it starts and how many items it holds.
+type Page struct {
+	Cursor Cursor
+	Limit  int
+}
+
+// Parse reads the cursor and limit query parameters. A missing limit is
+// DefaultLimit; a limit outside 1 to MaxLimit or a malformed cursor is a
+// validation error.
+func Parse(query url.Values) (Page, error) {
+	var errs domain.ValidationErrors
+	page := Page{Limit: DefaultLimit}
+
+	cursor, err := Decode(query.Get("cursor"))
+	if err != nil {
+		errs = append(errs, &domain.ValidationError{Field: "cursor", Message: "is not a cursor returned by this API"})
+	}
+	page.Cursor = cursor
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			errs = append(errs, &domain.ValidationError{
+				Field:   "limit",
+				Message: fmt.Sprintf("must be an integer between 1 and %d", MaxLimit),
+			})
+		} else {
+			page.Limit = limit
+		}
+	}
+
+	if len(errs) > 0 {
+		return page, errs
+	}
+	return page, nil
+}
+
+// Meta is the pagination metadata of a list response.
+type Meta = apiclient.Meta
+
+// Next returns the metadata of a page, with next as the cursor of the
+// following page if there is one.
+func Next(hasNext bool, next Cursor) Meta {
+	meta := Meta{HasNext: hasNext}
+	if hasNext {
+		meta.NextCursor = next.Encode()
+	}
+	return meta
+}
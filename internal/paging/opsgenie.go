@@ -0,0 +1,88 @@
+package paging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// opsgenieAlertsURL is the Opsgenie Alert API endpoint.
+const opsgenieAlertsURL = "https://api.opsgenie.com/v2/alerts"
+
+// Opsgenie raises alerts through the Opsgenie Alert API. The dedup key is
+// the alert alias, which Opsgenie uses to deduplicate open alerts.
+type Opsgenie struct {
+	header http.Header
+	source string
+	client *http.Client
+}
+
+// NewOpsgenie creates a pager for the API integration with apiKey. Alerts
+// name source as where they came from.
+func NewOpsgenie(apiKey, source string) *Opsgenie {
+	return &Opsgenie{
+		header: http.Header{"Authorization": {"GenieKey " + apiKey}},
+		source: source,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name identifies the pager in logs.
+func (o *Opsgenie) Name() string {
+	return "opsgenie"
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+// opsgeniePriority maps a severity to an Opsgenie priority.
+var opsgeniePriority = map[Severity]string{
+	SeverityCritical: "P1",
+	SeverityError:    "P2",
+	SeverityWarning:  "P3",
+}
+
+// Trigger creates an alert, or counts another occurrence of the open alert
+// with the same alias.
+func (o *Opsgenie) Trigger(ctx context.Context, alert Alert) error {
+	details := make(map[string]string, len(alert.Details))
+	for k, v := range alert.Details {
+		details[k] = fmt.Sprint(v)
+	}
+	// Opsgenie rejects messages longer than 130 characters.
+	message := alert.Summary
+	if r := []rune(message); len(r) > 130 {
+		message = string(r[:129]) + "…"
+	}
+	body, err := json.Marshal(opsgenieAlert{
+		Message:     message,
+		Alias:       alert.DedupKey,
+		Description: alert.Link,
+		Source:      o.source,
+		Priority:    opsgeniePriority[alert.Severity],
+		Details:     details,
+	})
+	if err != nil {
+		return fmt.Errorf("encode opsgenie alert: %w", err)
+	}
+	return post(ctx, o.client, "opsgenie", opsgenieAlertsURL, o.header, body, http.StatusAccepted)
+}
+
+// Resolve closes the open alert with alias dedupKey.
+func (o *Opsgenie) Resolve(ctx context.Context, dedupKey string) error {
+	endpoint := opsgenieAlertsURL + "/" + url.PathEscape(dedupKey) + "/close?identifierType=alias"
+	body, err := json.Marshal(map[string]string{"source": o.source})
+	if err != nil {
+		return fmt.Errorf("encode opsgenie close: %w", err)
+	}
+	return post(ctx, o.client, "opsgenie", endpoint, o.header, body, http.StatusAccepted)
+}
@@ -0,0 +1,92 @@
+package paging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint.
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty raises incidents on a PagerDuty service through the Events API
+// v2.
+type PagerDuty struct {
+	routingKey string
+	source     string
+	client     *http.Client
+}
+
+// NewPagerDuty creates a pager for the service whose Events API v2
+// integration has routingKey. Alerts name source as where they came from.
+func NewPagerDuty(routingKey, source string) *PagerDuty {
+	return &PagerDuty{
+		routingKey: routingKey,
+		source:     source,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name identifies the pager in logs.
+func (p *PagerDuty) Name() string {
+	return "pagerduty"
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      Severity       `json:"severity"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Trigger opens an incident, or adds to the open one with the same key.
+func (p *PagerDuty) Trigger(ctx context.Context, alert Alert) error {
+	event := pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    alert.DedupKey,
+		Payload: &pagerDutyPayload{
+			Summary:       alert.Summary,
+			Source:        p.source,
+			Severity:      alert.Severity,
+			CustomDetails: alert.Details,
+		},
+	}
+	if alert.Link != "" {
+		event.Links = []pagerDutyLink{{Href: alert.Link, Text: "View issue"}}
+	}
+	return p.send(ctx, event)
+}
+
+// Resolve closes the incident with dedupKey. Resolving an incident that is
+// not open is accepted and does nothing.
+func (p *PagerDuty) Resolve(ctx context.Context, dedupKey string) error {
+	return p.send(ctx, pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "resolve",
+		DedupKey:    dedupKey,
+	})
+}
+
+func (p *PagerDuty) send(ctx context.Context, event pagerDutyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode pagerduty event: %w", err)
+	}
+	return post(ctx, p.client, "pagerduty", pagerDutyEventsURL, http.Header{}, body, http.StatusAccepted)
+}
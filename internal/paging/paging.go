@@ -0,0 +1,65 @@
+// Package paging raises incidents with on-call services when AI processing
+// on critical projects needs a person to look at it.
+package paging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody bounds how much of an error response is kept.
+const maxErrorBody = 1 << 10
+
+// Severity is how urgent an alert is.
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+)
+
+// Alert is an incident to raise. DedupKey identifies the incident: raising
+// an alert with the key of one that is still open updates it instead of
+// paging again, and Resolve closes it.
+type Alert struct {
+	DedupKey string
+	Summary  string
+	Severity Severity
+	Link     string
+	Details  map[string]any
+}
+
+// Pager raises and resolves incidents with an on-call service.
+type Pager interface {
+	// Name identifies the pager in logs.
+	Name() string
+	Trigger(ctx context.Context, alert Alert) error
+	Resolve(ctx context.Context, dedupKey string) error
+}
+
+// post sends a JSON body and treats any status but want as an error.
+func post(ctx context.Context, client *http.Client, service, endpoint string, header http.Header, body []byte, want int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", service, err)
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s request: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != want {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("%s returned %s: %s", service, resp.Status, bytes.TrimSpace(detail))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,133 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// apnsTokenLifetime is how long a provider token is reused. Apple rejects
+// tokens older than an hour and throttles ones refreshed too often.
+const apnsTokenLifetime = 50 * time.Minute
+
+// APNs sends messages through the Apple Push Notification service using
+// token-based authentication.
+type APNs struct {
+	client *http.Client
+	host   string
+	key    *ecdsa.PrivateKey
+	keyID  string
+	teamID string
+	topic  string
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNs creates an APNs sender from a .p8 signing key. topic is the app's
+// bundle ID. Sandbox selects the development environment.
+func NewAPNs(keyPEM []byte, keyID, teamID, topic string, sandbox bool) (*APNs, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse apns key: %w", err)
+	}
+	host := "https://api.push.apple.com"
+	if sandbox {
+		host = "https://api.sandbox.push.apple.com"
+	}
+	return &APNs{
+		client: &http.Client{Timeout: 10 * time.Second},
+		host:   host,
+		key:    key,
+		keyID:  keyID,
+		teamID: teamID,
+		topic:  topic,
+	}, nil
+}
+
+type apnsAlert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type apnsAps struct {
+	Alert apnsAlert `json:"alert"`
+	Sound string    `json:"sound"`
+}
+
+// Send implements Sender. Message data is sent as top-level payload keys.
+func (a *APNs) Send(ctx context.Context, token string, msg Message) error {
+	payload, err := fit(msg, func(m Message) ([]byte, error) {
+		body := make(map[string]any, len(m.Data)+1)
+		for k, v := range m.Data {
+			body[k] = v
+		}
+		body["aps"] = apnsAps{Alert: apnsAlert{Title: m.Title, Body: m.Body}, Sound: "default"}
+		return json.Marshal(body)
+	})
+	if err != nil {
+		return err
+	}
+
+	bearer, err := a.providerToken()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.host+"/3/device/"+token, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build apns request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+bearer)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send apns message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var e struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&e)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		e.Reason == "BadDeviceToken", e.Reason == "DeviceTokenNotForTopic", e.Reason == "Unregistered":
+		return ErrInvalidToken
+	}
+	return fmt.Errorf("apns returned %s: %s", resp.Status, e.Reason)
+}
+
+// providerToken returns a signed provider token, reusing it until it nears
+// expiry.
+func (a *APNs) providerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Since(a.issuedAt) < apnsTokenLifetime {
+		return a.token, nil
+	}
+
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": a.teamID, "iat": now.Unix()})
+	t.Header["kid"] = a.keyID
+	signed, err := t.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("sign apns token: %w", err)
+	}
+	a.token, a.issuedAt = signed, now
+	return signed, nil
+}
@@ -0,0 +1,104 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// FCM sends messages through the Firebase Cloud Messaging HTTP v1 API.
+type FCM struct {
+	client *http.Client
+	url    string
+}
+
+// NewFCM creates an FCM sender authenticated with a service account key.
+func NewFCM(ctx context.Context, credentialsJSON []byte) (*FCM, error) {
+	creds, err := google.CredentialsFromJSON(ctx, credentialsJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("parse fcm credentials: %w", err)
+	}
+	if creds.ProjectID == "" {
+		return nil, fmt.Errorf("fcm credentials have no project id")
+	}
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	client.Timeout = 10 * time.Second
+	return &FCM{
+		client: client,
+		url:    "https://fcm.googleapis.com/v1/projects/" + creds.ProjectID + "/messages:send",
+	}, nil
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send implements Sender.
+func (f *FCM) Send(ctx context.Context, token string, msg Message) error {
+	// The size limit applies to the message, not to the request around it.
+	payload, err := fit(msg, func(m Message) ([]byte, error) {
+		return json.Marshal(fcmMessage{
+			Token:        token,
+			Notification: fcmNotification{Title: m.Title, Body: m.Body},
+			Data:         m.Data,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	body := append(append([]byte(`{"message":`), payload...), '}')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build fcm request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send fcm message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var e fcmError
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&e)
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrInvalidToken
+	}
+	for _, d := range e.Error.Details {
+		if d.ErrorCode == "UNREGISTERED" {
+			return ErrInvalidToken
+		}
+	}
+	return fmt.Errorf("fcm returned %s: %s %s", resp.Status, e.Error.Status, e.Error.Message)
+}
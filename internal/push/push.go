@@ -0,0 +1,70 @@
+// Package push sends notifications to mobile devices through Firebase
+// Cloud Messaging and the Apple Push Notification service.
+package push
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+)
+
+// MaxPayloadSize is the largest payload FCM and APNs accept, in bytes.
+const MaxPayloadSize = 4096
+
+// ErrInvalidToken is returned when the push service reports that a device
+// token is no longer valid. The device should be forgotten.
+var ErrInvalidToken = errors.New("invalid device token")
+
+// ErrPayloadTooLarge is returned when a message does not fit in a payload
+// even with its title and body removed.
+var ErrPayloadTooLarge = errors.New("push payload too large")
+
+// Message is a notification shown on a device. Data is passed to the app.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers messages to devices of one platform.
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// fit encodes msg, shortening its body and then its title until the payload
+// is at most MaxPayloadSize bytes.
+func fit(msg Message, encode func(Message) ([]byte, error)) ([]byte, error) {
+	for {
+		payload, err := encode(msg)
+		if err != nil {
+			return nil, err
+		}
+		over := len(payload) - MaxPayloadSize
+		if over <= 0 {
+			return payload, nil
+		}
+		switch {
+		case msg.Body != "":
+			msg.Body = shorten(msg.Body, over)
+		case msg.Title != "":
+			msg.Title = shorten(msg.Title, over)
+		default:
+			return nil, ErrPayloadTooLarge
+		}
+	}
+}
+
+// shorten removes at least n bytes, plus room for an ellipsis, from the end
+// of s without splitting a character. JSON escaping can make the encoded
+// text longer than s, so callers re-encode and shorten again if needed.
+func shorten(s string, n int) string {
+	const ellipsis = "…"
+	cut := len(s) - n - len(ellipsis)
+	if cut <= 0 {
+		return ""
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
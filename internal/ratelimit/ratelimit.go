@@ -0,0 +1,54 @@
+// Package ratelimit provides token-bucket rate limiting backed by pluggable
+// stores, so limits can be enforced per process or shared across replicas.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit describes a token bucket: Burst tokens at most, refilled at Rate
+// tokens per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store atomically takes a token from the bucket identified by key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	Close() error
+}
+
+// take applies the token-bucket algorithm to a bucket last observed at last
+// with the given number of tokens. It returns the new token count and result.
+func take(tokens float64, last, now time.Time, limit Limit) (float64, Result) {
+	elapsed := now.Sub(last).Seconds()
+	if elapsed > 0 {
+		tokens += elapsed * limit.Rate
+	}
+	if burst := float64(limit.Burst); tokens > burst {
+		tokens = burst
+	}
+
+	if tokens < 1 {
+		wait := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+		return tokens, Result{Allowed: false, Remaining: 0, RetryAfter: wait}
+	}
+
+	tokens--
+	return tokens, Result{Allowed: true, Remaining: int(tokens)}
+}
+
+// ttl returns how long an idle bucket must be kept before it is full again
+// and can be forgotten.
+func ttl(limit Limit) time.Duration {
+	return time.Duration(float64(limit.Burst)/limit.Rate*float64(time.Second)) + time.Second
+}
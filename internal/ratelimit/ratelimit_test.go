@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTake(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 1, Burst: 3}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		last       time.Time
+		limit      Limit
+		wantTokens float64
+		want       Result
+	}{
+		{
+			name:       "full bucket",
+			tokens:     3,
+			last:       now,
+			limit:      limit,
+			wantTokens: 2,
+			want:       Result{Allowed: true, Remaining: 2},
+		},
+		{
+			name:       "last token",
+			tokens:     1,
+			last:       now,
+			limit:      limit,
+			wantTokens: 0,
+			want:       Result{Allowed: true, Remaining: 0},
+		},
+		{
+			name:       "empty bucket",
+			tokens:     0,
+			last:       now,
+			limit:      limit,
+			wantTokens: 0,
+			want:       Result{RetryAfter: time.Second},
+		},
+		{
+			name:       "partly refilled",
+			tokens:     0,
+			last:       now.Add(-500 * time.Millisecond),
+			limit:      limit,
+			wantTokens: 0.5,
+			want:       Result{RetryAfter: 500 * time.Millisecond},
+		},
+		{
+			name:       "refilled",
+			tokens:     0,
+			last:       now.Add(-2 * time.Second),
+			limit:      limit,
+			wantTokens: 1,
+			want:       Result{Allowed: true, Remaining: 1},
+		},
+		{
+			name:       "refill capped at burst",
+			tokens:     1,
+			last:       now.Add(-time.Hour),
+			limit:      limit,
+			wantTokens: 2,
+			want:       Result{Allowed: true, Remaining: 2},
+		},
+		{
+			name:       "clock went backwards",
+			tokens:     0.5,
+			last:       now.Add(time.Second),
+			limit:      limit,
+			wantTokens: 0.5,
+			want:       Result{RetryAfter: 500 * time.Millisecond},
+		},
+		{
+			name:       "slow rate",
+			tokens:     0,
+			last:       now,
+			limit:      Limit{Rate: 0.5, Burst: 1},
+			wantTokens: 0,
+			want:       Result{RetryAfter: 2 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, got := take(tt.tokens, tt.last, now, tt.limit)
+			if tokens != tt.wantTokens {
+				t.Errorf("take() tokens = %v, want %v", tokens, tt.wantTokens)
+			}
+			if got != tt.want {
+				t.Errorf("take() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		limit Limit
+		want  time.Duration
+	}{
+		{Limit{Rate: 1, Burst: 3}, 4 * time.Second},
+		{Limit{Rate: 10, Burst: 5}, 1500 * time.Millisecond},
+		{Limit{Rate: 0.5, Burst: 1}, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := ttl(tt.limit); got != tt.want {
+			t.Errorf("ttl(%+v) = %s, want %s", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(time.Hour)
+	defer s.Close()
+	// A slow rate keeps the bucket from refilling during the test.
+	limit := Limit{Rate: 0.001, Burst: 2}
+
+	for i, want := range []bool{true, true, false} {
+		res, err := s.Take(ctx, "a", limit)
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		if res.Allowed != want {
+			t.Errorf("Take() #%d allowed = %v, want %v", i+1, res.Allowed, want)
+		}
+	}
+
+	res, err := s.Take(ctx, "b", limit)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if !res.Allowed || res.Remaining != 1 {
+		t.Errorf("Take() on another key = %+v, want allowed with 1 remaining", res)
+	}
+}
+
+func TestMemoryStoreEvictsIdleBuckets(t *testing.T) {
+	s := NewMemoryStore(5 * time.Millisecond)
+	defer s.Close()
+
+	if _, err := s.Take(context.Background(), "idle", Limit{Rate: 1, Burst: 1}); err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	s.mu.Lock()
+	s.buckets["idle"].last = time.Now().Add(-time.Hour)
+	s.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		_, ok := s.buckets["idle"]
+		s.mu.Unlock()
+		if !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle bucket was not evicted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
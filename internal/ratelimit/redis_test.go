@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	m := miniredis.RunT(t)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m.SetTime(now)
+
+	s, err := NewRedisStore(ctx, "redis://"+m.Addr())
+	if err != nil {
+		t.Fatalf("NewRedisStore() error = %v", err)
+	}
+	defer s.Close()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    Result
+	}{
+		{name: "first", want: Result{Allowed: true, Remaining: 1}},
+		{name: "second", want: Result{Allowed: true, Remaining: 0}},
+		{name: "empty", want: Result{RetryAfter: time.Second}},
+		{name: "half refilled", advance: 500 * time.Millisecond, want: Result{RetryAfter: 500 * time.Millisecond}},
+		{name: "refilled", advance: time.Second, want: Result{Allowed: true, Remaining: 0}},
+		{name: "capped at burst", advance: time.Hour, want: Result{Allowed: true, Remaining: 1}},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		m.SetTime(now)
+		got, err := s.Take(ctx, "k", limit)
+		if err != nil {
+			t.Fatalf("%s: Take() error = %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: Take() = %+v, want %+v", step.name, got, step.want)
+		}
+	}
+
+	if got, want := m.TTL("ratelimit:k"), 3*time.Second; got != want {
+		t.Errorf("bucket TTL = %s, want %s", got, want)
+	}
+}
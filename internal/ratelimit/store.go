@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Store backends selectable through configuration.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// NewStore creates the store for the named backend.
+func NewStore(ctx context.Context, backend, redisURL string) (Store, error) {
+	switch backend {
+	case BackendMemory, "":
+		return NewMemoryStore(time.Minute), nil
+	case BackendRedis:
+		if redisURL == "" {
+			return nil, fmt.Errorf("REDIS_URL is required for the redis rate limit store")
+		}
+		return NewRedisStore(ctx, redisURL)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", backend)
+	}
+}
@@ -0,0 +1,204 @@
+// Package realtime delivers per-user and per-project events to the streams
+// users have open on any instance of a multi-replica deployment, through
+// Postgres LISTEN/NOTIFY.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// channel is the Postgres notification channel events travel on.
+const channel = "realtime_events"
+
+// bufferSize is how many events a stream may fall behind before it is
+// closed. Clients reconnect and resynchronize when their stream closes.
+const bufferSize = 32
+
+// projectBufferSize is bufferSize for streams of project events, which
+// arrive faster: a running AI job reports progress twice a second.
+const projectBufferSize = 256
+
+// retryInterval is how long Run waits before listening again after its
+// connection fails.
+const retryInterval = 5 * time.Second
+
+// topic is what a stream follows: a user's events or a project's.
+type topic struct {
+	project bool
+	id      int64
+}
+
+func topicOf(event domain.RealtimeEvent) topic {
+	if event.ProjectID != 0 {
+		return topic{project: true, id: event.ProjectID}
+	}
+	return topic{id: event.UserID}
+}
+
+// Hub publishes events and fans them out to local subscribers.
+type Hub struct {
+	pool *pgxpool.Pool
+
+	mu     sync.Mutex
+	subs   map[topic]map[chan domain.RealtimeEvent]struct{}
+	topics map[chan domain.RealtimeEvent][]topic
+	closed bool
+}
+
+// New creates a Hub that takes connections from pool.
+func New(pool *pgxpool.Pool) *Hub {
+	return &Hub{
+		pool:   pool,
+		subs:   make(map[topic]map[chan domain.RealtimeEvent]struct{}),
+		topics: make(map[chan domain.RealtimeEvent][]topic),
+	}
+}
+
+// Publish sends an event to every stream of its user, or of its project, on
+// every instance. Postgres limits payloads to 8000 bytes, so events carry
+// references rather than whole objects.
+func (h *Hub) Publish(ctx context.Context, event domain.RealtimeEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode realtime event: %w", err)
+	}
+	if _, err := h.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, string(payload)); err != nil {
+		return fmt.Errorf("publish realtime event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe returns a stream of the user's events and a function that ends
+// it. The stream is closed when it falls too far behind or the hub stops.
+func (h *Hub) Subscribe(userID int64) (<-chan domain.RealtimeEvent, func()) {
+	return h.subscribe(bufferSize, topic{id: userID})
+}
+
+// SubscribeProjects returns a stream of the events of the projects and a
+// function that ends it, like Subscribe.
+func (h *Hub) SubscribeProjects(projectIDs []int64) (<-chan domain.RealtimeEvent, func()) {
+	topics := make([]topic, len(projectIDs))
+	for i, id := range projectIDs {
+		topics[i] = topic{project: true, id: id}
+	}
+	return h.subscribe(projectBufferSize, topics...)
+}
+
+func (h *Hub) subscribe(size int, topics ...topic) (<-chan domain.RealtimeEvent, func()) {
+	ch := make(chan domain.RealtimeEvent, size)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	for _, t := range topics {
+		if h.subs[t] == nil {
+			h.subs[t] = make(map[chan domain.RealtimeEvent]struct{})
+		}
+		h.subs[t][ch] = struct{}{}
+	}
+	h.topics[ch] = topics
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(ch)
+	}
+}
+
+// remove drops and closes a subscription if it is still registered. The
+// caller must hold h.mu.
+func (h *Hub) remove(ch chan domain.RealtimeEvent) {
+	topics, ok := h.topics[ch]
+	if !ok {
+		return
+	}
+	for _, t := range topics {
+		delete(h.subs[t], ch)
+		if len(h.subs[t]) == 0 {
+			delete(h.subs, t)
+		}
+	}
+	delete(h.topics, ch)
+	close(ch)
+}
+
+// dispatch hands an event to the local streams of its user or project.
+func (h *Hub) dispatch(event domain.RealtimeEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[topicOf(event)] {
+		select {
+		case ch <- event:
+		default:
+			slog.Warn("realtime stream fell behind", "user_id", event.UserID, "project_id", event.ProjectID)
+			h.remove(ch)
+		}
+	}
+}
+
+// Run listens for published events and dispatches them until ctx is
+// cancelled, then closes every stream. It runs on every instance.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+	for {
+		if err := h.listen(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("realtime listener failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func (h *Hub) listen(ctx context.Context) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// The connection is closed rather than returned to the pool so that it
+	// does not stay subscribed to the channel.
+	defer func() {
+		conn.Conn().Close(context.WithoutCancel(ctx))
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, `LISTEN `+channel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		var event domain.RealtimeEvent
+		if err := json.Unmarshal([]byte(n.Payload), &event); err != nil {
+			slog.Error("malformed realtime event", "error", err)
+			continue
+		}
+		h.dispatch(event)
+	}
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.topics {
+		h.remove(ch)
+	}
+}
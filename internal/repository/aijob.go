@@ -0,0 +1,566 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const aiJobColumns = `j.id, j.issue_id, i.project_id, j.mode, j.diff, j.status, j.attempts, j.max_attempts, j.timeout_seconds,
+	j.instructions, j.resume_session_id, j.request_id, j.triggered_by, j.started_at, j.completed_at, j.error_msg,
+	j.cancel_requested_at, j.cancelled_by, j.created_at`
+
+// aiJobPausedClause matches jobs whose project p has AI processing paused,
+// individually or through the global flag. Workers must not claim them.
+const aiJobPausedClause = `(p.ai_paused_at IS NOT NULL OR EXISTS (
+		SELECT 1 FROM system_flags f WHERE f.name = '` + domain.FlagAIPaused + `' AND f.enabled))`
+
+// AIJobRepository handles AI job data access operations.
+type AIJobRepository struct {
+	db *queryDB
+}
+
+// NewAIJobRepository creates a new AIJobRepository.
+func NewAIJobRepository(pool *pgxpool.Pool) *AIJobRepository {
+	return &AIJobRepository{db: instrument(pool, "ai_job")}
+}
+
+// Create queues a job for its issue and returns it. It returns
+// domain.ErrConflict if the issue already has a pending or running job.
+func (r *AIJobRepository) Create(ctx context.Context, job domain.AIJob) (*domain.AIJob, error) {
+	var result domain.AIJob
+	err := r.db.Get(ctx, &result,
+		`WITH j AS (
+		     INSERT INTO ai_jobs (issue_id, mode, diff, timeout_seconds, instructions, resume_session_id, request_id, triggered_by)
+		     VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		     ON CONFLICT (issue_id) WHERE status IN ('pending', 'running') DO NOTHING
+		     RETURNING *
+		 )
+		 SELECT `+aiJobColumns+` FROM j JOIN issues i ON i.id = j.issue_id`,
+		job.IssueID, job.Mode, job.Diff, job.TimeoutSeconds, job.Instructions, job.ResumeSessionID, job.RequestID, job.TriggeredBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: issue %d already has an AI job in progress", domain.ErrConflict, job.IssueID)
+		}
+		return nil, fmt.Errorf("create ai job for issue %d: %w", job.IssueID, err)
+	}
+	return &result, nil
+}
+
+// FindByID retrieves an AI job by its ID.
+func (r *AIJobRepository) FindByID(ctx context.Context, id int64) (*domain.AIJob, error) {
+	var job domain.AIJob
+	err := r.db.Get(ctx, &job,
+		`SELECT `+aiJobColumns+` FROM ai_jobs j JOIN issues i ON i.id = j.issue_id WHERE j.id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find ai job by id %d: %w", id, err)
+	}
+	return &job, nil
+}
+
+// Claim marks the oldest pending job as running, counts the attempt and
+// returns it, or returns domain.ErrNotFound if there is none. Pending jobs in
+// projects with AI paused, and of issues or projects in the trash, are
+// skipped. Concurrent claims never return the same job.
+func (r *AIJobRepository) Claim(ctx context.Context) (*domain.AIJob, error) {
+	var job domain.AIJob
+	err := r.db.Get(ctx, &job,
+		`WITH j AS (
+		     UPDATE ai_jobs
+		     SET status = 'running', attempts = attempts + 1, started_at = NOW(), error_msg = NULL
+		     WHERE id = (
+		         SELECT j.id FROM ai_jobs j
+		         JOIN issues i ON i.id = j.issue_id
+		         JOIN projects p ON p.id = i.project_id
+		         WHERE j.status = 'pending' AND NOT `+aiJobPausedClause+`
+		           AND i.deleted_at IS NULL AND p.deleted_at IS NULL
+		         ORDER BY j.created_at, j.id
+		         LIMIT 1
+		         FOR UPDATE OF j SKIP LOCKED
+		     )
+		     RETURNING *
+		 )
+		 SELECT `+aiJobColumns+` FROM j JOIN issues i ON i.id = j.issue_id`)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("claim ai job: %w", err)
+	}
+	return &job, nil
+}
+
+// RequeueStuck queues again every running job whose worker has not finished
+// it within its timeout, limit if unset, plus a grace period, presuming the
+// worker lost. It returns how many jobs were queued again.
+func (r *AIJobRepository) RequeueStuck(ctx context.Context, limit time.Duration) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE ai_jobs SET status = 'pending'
+		 WHERE status = 'running' AND started_at < NOW()
+		     - (COALESCE(timeout_seconds, $1) * INTERVAL '1 second') - INTERVAL '5 minutes'`,
+		int(limit.Seconds()))
+	if err != nil {
+		return 0, fmt.Errorf("requeue stuck ai jobs: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// Release queues a running job again without counting its attempt, for a
+// worker that stopped before the job finished.
+func (r *AIJobRepository) Release(ctx context.Context, jobID int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE ai_jobs SET status = 'pending', attempts = GREATEST(attempts - 1, 0)
+		 WHERE id = $1 AND status = 'running'`, jobID)
+	if err != nil {
+		return fmt.Errorf("release ai job %d: %w", jobID, err)
+	}
+	return nil
+}
+
+// Complete marks a running job as completed.
+func (r *AIJobRepository) Complete(ctx context.Context, jobID int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE ai_jobs SET status = 'completed', completed_at = NOW(), error_msg = NULL
+		 WHERE id = $1`, jobID)
+	if err != nil {
+		return fmt.Errorf("complete ai job %d: %w", jobID, err)
+	}
+	return nil
+}
+
+// Fail records a failed attempt of a running job and returns the job's new
+// status. The job is queued again if retry is set and it has attempts left;
+// otherwise it fails for good.
+func (r *AIJobRepository) Fail(ctx context.Context, jobID int64, reason string, retry bool) (domain.JobStatus, error) {
+	var status domain.JobStatus
+	err := r.db.Get(ctx, &status,
+		`UPDATE ai_jobs
+		 SET status = CASE WHEN $3 AND attempts < max_attempts THEN 'pending' ELSE 'failed' END::job_status,
+		     completed_at = CASE WHEN $3 AND attempts < max_attempts THEN NULL ELSE NOW() END,
+		     error_msg = $2
+		 WHERE id = $1
+		 RETURNING status`,
+		jobID, reason, retry)
+	if err != nil {
+		return "", fmt.Errorf("fail ai job %d: %w", jobID, err)
+	}
+	return status, nil
+}
+
+// List returns AI jobs across all projects matching the filter, newest first,
+// starting before the cursor. It fetches one row beyond the limit so callers
+// can detect a next page.
+func (r *AIJobRepository) List(ctx context.Context, f domain.AIJobFilter) ([]domain.AIJob, error) {
+	conds := []string{"TRUE"}
+	var args []any
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		add("j.status = ANY($%d::job_status[])", statuses)
+	}
+	if f.ProjectID != nil {
+		add("i.project_id = $%d", *f.ProjectID)
+	}
+	if f.MinAge > 0 {
+		add("j.created_at <= NOW() - $%d * INTERVAL '1 second'", f.MinAge.Seconds())
+	}
+	if f.MaxAge > 0 {
+		add("j.created_at > NOW() - $%d * INTERVAL '1 second'", f.MaxAge.Seconds())
+	}
+	if !f.Cursor.IsZero() {
+		args = append(args, f.Cursor.At, f.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(j.created_at, j.id) < ($%d::timestamptz, $%d::bigint)", len(args)-1, len(args)))
+	}
+	args = append(args, f.Limit+1)
+
+	query := fmt.Sprintf(`SELECT %s FROM ai_jobs j JOIN issues i ON i.id = j.issue_id
+		 WHERE %s ORDER BY j.created_at DESC, j.id DESC LIMIT $%d`,
+		aiJobColumns, strings.Join(conds, " AND "), len(args))
+
+	jobs := []domain.AIJob{}
+	if err := r.db.Select(ctx, &jobs, query, args...); err != nil {
+		return nil, fmt.Errorf("list ai jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// QueueStats summarises the queue now and over the trailing window.
+func (r *AIJobRepository) QueueStats(ctx context.Context, window time.Duration) (*domain.AIJobQueueStats, error) {
+	var stats domain.AIJobQueueStats
+	err := r.db.Get(ctx, &stats,
+		`WITH recent AS (
+		     SELECT j.status, j.created_at, j.started_at, j.completed_at,
+		            j.started_at >= NOW() - $1 * INTERVAL '1 second' AS started_recently,
+		            j.completed_at >= NOW() - $1 * INTERVAL '1 second' AS finished_recently,
+		            `+aiJobPausedClause+` AS paused
+		     FROM ai_jobs j
+		     JOIN issues i ON i.id = j.issue_id
+		     JOIN projects p ON p.id = i.project_id
+		     WHERE j.status IN ('pending', 'running')
+		        OR j.started_at >= NOW() - $1 * INTERVAL '1 second'
+		        OR j.completed_at >= NOW() - $1 * INTERVAL '1 second'
+		 )
+		 SELECT COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+		        COUNT(*) FILTER (WHERE status = 'running') AS running,
+		        COUNT(*) FILTER (WHERE status = 'pending' AND paused) AS paused,
+		        EXISTS (SELECT 1 FROM system_flags
+		                WHERE name = '`+domain.FlagAIPaused+`' AND enabled) AS globally_paused,
+		        EXTRACT(EPOCH FROM NOW() - MIN(created_at) FILTER (WHERE status = 'pending'))::float8 AS oldest_pending_seconds,
+		        AVG(EXTRACT(EPOCH FROM started_at - created_at)) FILTER (WHERE started_recently)::float8 AS avg_wait_seconds,
+		        COUNT(*) FILTER (WHERE status = 'completed' AND finished_recently) AS completed,
+		        COUNT(*) FILTER (WHERE status = 'failed' AND finished_recently) AS failed,
+		        (COUNT(*) FILTER (WHERE status = 'failed' AND finished_recently))::float8
+		            / NULLIF(COUNT(*) FILTER (WHERE status IN ('completed', 'failed') AND finished_recently), 0)
+		            AS failure_rate
+		 FROM recent`,
+		window.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("ai job queue stats: %w", err)
+	}
+	stats.WindowSeconds = window.Seconds()
+	return &stats, nil
+}
+
+// CriticalBacklog returns the pending jobs of each project whose settings
+// mark it critical. Jobs held back by a pause are not counted.
+func (r *AIJobRepository) CriticalBacklog(ctx context.Context) ([]domain.AIJobBacklog, error) {
+	var backlog []domain.AIJobBacklog
+	err := r.db.Select(ctx, &backlog,
+		`SELECT i.project_id, COUNT(*) AS pending,
+		        EXTRACT(EPOCH FROM NOW() - MIN(j.created_at))::float8 AS oldest_pending_seconds
+		 FROM ai_jobs j
+		 JOIN issues i ON i.id = j.issue_id
+		 JOIN projects p ON p.id = i.project_id
+		 WHERE j.status = 'pending'
+		   AND COALESCE((p.settings->>'critical')::boolean, false)
+		   AND NOT `+aiJobPausedClause+`
+		 GROUP BY i.project_id
+		 ORDER BY i.project_id`)
+	if err != nil {
+		return nil, fmt.Errorf("critical ai job backlog: %w", err)
+	}
+	return backlog, nil
+}
+
+const artifactColumns = `id, job_id, name, storage_key, content_type, size_bytes, created_at`
+
+// AddArtifact records a stored artifact of a job and returns it. Adding an
+// artifact with an existing name replaces its record.
+func (r *AIJobRepository) AddArtifact(ctx context.Context, a domain.AIJobArtifact) (*domain.AIJobArtifact, error) {
+	var result domain.AIJobArtifact
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO ai_job_artifacts (job_id, name, storage_key, content_type, size_bytes)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (job_id, name)
+		 DO UPDATE SET storage_key = EXCLUDED.storage_key, content_type = EXCLUDED.content_type,
+		               size_bytes = EXCLUDED.size_bytes, created_at = NOW()
+		 RETURNING `+artifactColumns,
+		a.JobID, a.Name, a.StorageKey, a.ContentType, a.SizeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("add artifact %q to ai job %d: %w", a.Name, a.JobID, err)
+	}
+	return &result, nil
+}
+
+// ListArtifacts returns a job's artifacts ordered by name.
+func (r *AIJobRepository) ListArtifacts(ctx context.Context, jobID int64) ([]domain.AIJobArtifact, error) {
+	artifacts := []domain.AIJobArtifact{}
+	err := r.db.Select(ctx, &artifacts,
+		`SELECT `+artifactColumns+` FROM ai_job_artifacts WHERE job_id = $1 ORDER BY name`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("list artifacts of ai job %d: %w", jobID, err)
+	}
+	return artifacts, nil
+}
+
+// FindArtifact retrieves one artifact of a job.
+func (r *AIJobRepository) FindArtifact(ctx context.Context, jobID, artifactID int64) (*domain.AIJobArtifact, error) {
+	var artifact domain.AIJobArtifact
+	err := r.db.Get(ctx, &artifact,
+		`SELECT `+artifactColumns+` FROM ai_job_artifacts WHERE job_id = $1 AND id = $2`, jobID, artifactID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find artifact %d of ai job %d: %w", artifactID, jobID, err)
+	}
+	return &artifact, nil
+}
+
+// FindArtifactByID retrieves an artifact by its ID.
+func (r *AIJobRepository) FindArtifactByID(ctx context.Context, id int64) (*domain.AIJobArtifact, error) {
+	var artifact domain.AIJobArtifact
+	err := r.db.Get(ctx, &artifact,
+		`SELECT `+artifactColumns+` FROM ai_job_artifacts WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find artifact by id %d: %w", id, err)
+	}
+	return &artifact, nil
+}
+
+const runColumns = `id, job_id, issue_id, attempt, prompt, session_id, result, status,
+	triggered_by, approved_by, started_at, finished_at`
+
+// StartRun records the start of an attempt of a job and returns it.
+func (r *AIJobRepository) StartRun(ctx context.Context, run domain.AIRun) (*domain.AIRun, error) {
+	var result domain.AIRun
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO ai_runs (job_id, issue_id, attempt, prompt, session_id, triggered_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+runColumns,
+		run.JobID, run.IssueID, run.Attempt, run.Prompt, run.SessionID, run.TriggeredBy)
+	if err != nil {
+		return nil, fmt.Errorf("start run %d of ai job %d: %w", run.Attempt, run.JobID, err)
+	}
+	return &result, nil
+}
+
+// FinishRun records the outcome of a run.
+func (r *AIJobRepository) FinishRun(ctx context.Context, runID int64, status domain.JobStatus, sessionID, result *string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE ai_runs
+		 SET status = $2, session_id = COALESCE($3, session_id), result = $4, finished_at = NOW()
+		 WHERE id = $1`,
+		runID, status, sessionID, result)
+	if err != nil {
+		return fmt.Errorf("finish ai run %d: %w", runID, err)
+	}
+	return nil
+}
+
+// ListRuns returns an issue's AI runs, most recently started first,
+// starting after the position of their start time and ID. It fetches one
+// row beyond limit so callers can detect a next page.
+func (r *AIJobRepository) ListRuns(ctx context.Context, issueID int64, after domain.ListPosition, limit int) ([]domain.AIRun, error) {
+	runs := []domain.AIRun{}
+	err := r.db.Select(ctx, &runs,
+		`SELECT `+runColumns+` FROM ai_runs
+		 WHERE issue_id = $1 AND ($2::bigint = 0 OR (started_at, id) < ($3::timestamptz, $2))
+		 ORDER BY started_at DESC, id DESC
+		 LIMIT $4`, issueID, after.ID, after.At, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list ai runs for issue %d: %w", issueID, err)
+	}
+	return runs, nil
+}
+
+const reviewCommentColumns = `id, job_id, issue_id, path, line, severity, body, created_at`
+
+// AddReviewComments stores the findings of a review job in one statement.
+func (r *AIJobRepository) AddReviewComments(ctx context.Context, jobID, issueID int64, comments []domain.AIReviewComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(comments))
+	args := []any{jobID, issueID}
+	for _, c := range comments {
+		args = append(args, c.Path, c.Line, c.Severity, c.Body)
+		n := len(args)
+		values = append(values, fmt.Sprintf("($1, $2, $%d, $%d, $%d, $%d)", n-3, n-2, n-1, n))
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO ai_review_comments (job_id, issue_id, path, line, severity, body)
+		 VALUES `+strings.Join(values, ", "),
+		args...)
+	if err != nil {
+		return fmt.Errorf("add review comments for ai job %d: %w", jobID, err)
+	}
+	return nil
+}
+
+// ListReviewComments returns an issue's AI review comments, newest first,
+// starting after the position. It fetches one row beyond limit so callers can detect a next page.
+func (r *AIJobRepository) ListReviewComments(ctx context.Context, issueID int64, after domain.ListPosition, limit int) ([]domain.AIReviewComment, error) {
+	comments := []domain.AIReviewComment{}
+	err := r.db.Select(ctx, &comments,
+		`SELECT `+reviewCommentColumns+` FROM ai_review_comments
+		 WHERE issue_id = $1 AND ($2::bigint = 0 OR (created_at, id) < ($3::timestamptz, $2))
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $4`, issueID, after.ID, after.At, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list review comments for issue %d: %w", issueID, err)
+	}
+	return comments, nil
+}
+
+// LatestForIssue returns the issue's most recent job, or domain.ErrNotFound
+// if AI has never run on it.
+func (r *AIJobRepository) LatestForIssue(ctx context.Context, issueID int64) (*domain.AIJob, error) {
+	var job domain.AIJob
+	err := r.db.Get(ctx, &job,
+		`SELECT `+aiJobColumns+` FROM ai_jobs j JOIN issues i ON i.id = j.issue_id
+		 WHERE j.issue_id = $1 ORDER BY j.id DESC LIMIT 1`, issueID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find latest ai job for issue %d: %w", issueID, err)
+	}
+	return &job, nil
+}
+
+// AppendLogs adds output chunks to their jobs' logs.
+func (r *AIJobRepository) AppendLogs(ctx context.Context, logs []domain.AIJobLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	jobIDs := make([]int64, len(logs))
+	runIDs := make([]int64, len(logs))
+	streams := make([]string, len(logs))
+	data := make([]string, len(logs))
+	for i, l := range logs {
+		jobIDs[i] = l.JobID
+		runIDs[i] = l.RunID
+		streams[i] = string(l.Stream)
+		data[i] = l.Data
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO ai_job_logs (job_id, run_id, stream, data)
+		 SELECT job_id, run_id, stream::ai_log_stream, data
+		 FROM unnest($1::bigint[], $2::bigint[], $3::text[], $4::text[]) WITH ORDINALITY
+		      AS l(job_id, run_id, stream, data, n)
+		 ORDER BY n`,
+		jobIDs, runIDs, streams, data)
+	if err != nil {
+		return fmt.Errorf("append ai job logs: %w", err)
+	}
+	return nil
+}
+
+// ListLogs returns up to limit chunks of a job's log with IDs greater than
+// afterID, oldest first.
+func (r *AIJobRepository) ListLogs(ctx context.Context, jobID, afterID int64, limit int) ([]domain.AIJobLog, error) {
+	logs := []domain.AIJobLog{}
+	err := r.db.Select(ctx, &logs,
+		`SELECT id, job_id, run_id, stream, data, created_at
+		 FROM ai_job_logs
+		 WHERE job_id = $1 AND id > $2
+		 ORDER BY id
+		 LIMIT $3`, jobID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list logs of ai job %d: %w", jobID, err)
+	}
+	return logs, nil
+}
+
+// Cancel cancels a pending job outright and flags a running one for its
+// worker to stop, and returns the job. It returns domain.ErrConflict if the
+// job is neither pending nor running.
+func (r *AIJobRepository) Cancel(ctx context.Context, jobID, userID int64) (*domain.AIJob, error) {
+	var job domain.AIJob
+	err := r.db.Get(ctx, &job,
+		`WITH j AS (
+		     UPDATE ai_jobs
+		     SET status = CASE WHEN status = 'pending' THEN 'cancelled' ELSE status END::job_status,
+		         completed_at = CASE WHEN status = 'pending' THEN NOW() ELSE completed_at END,
+		         cancel_requested_at = NOW(),
+		         cancelled_by = $2
+		     WHERE id = $1 AND status IN ('pending', 'running')
+		     RETURNING *
+		 )
+		 SELECT `+aiJobColumns+` FROM j JOIN issues i ON i.id = j.issue_id`,
+		jobID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: ai job %d is not in progress", domain.ErrConflict, jobID)
+		}
+		return nil, fmt.Errorf("cancel ai job %d: %w", jobID, err)
+	}
+	return &job, nil
+}
+
+// CancelRequested reports whether a running job has been asked to stop.
+func (r *AIJobRepository) CancelRequested(ctx context.Context, jobID int64) (bool, error) {
+	var requested bool
+	err := r.db.Get(ctx, &requested,
+		`SELECT cancel_requested_at IS NOT NULL FROM ai_jobs WHERE id = $1`, jobID)
+	if err != nil {
+		return false, fmt.Errorf("check cancellation of ai job %d: %w", jobID, err)
+	}
+	return requested, nil
+}
+
+// MarkCancelled records that a worker stopped a running job on request and
+// returns who cancelled it.
+func (r *AIJobRepository) MarkCancelled(ctx context.Context, jobID int64) (*int64, error) {
+	var cancelledBy *int64
+	err := r.db.Get(ctx, &cancelledBy,
+		`UPDATE ai_jobs SET status = 'cancelled', completed_at = NOW(), error_msg = 'cancelled'
+		 WHERE id = $1
+		 RETURNING cancelled_by`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("mark ai job %d cancelled: %w", jobID, err)
+	}
+	return cancelledBy, nil
+}
+
+// Retry queues a failed or cancelled job again with its attempts reset, and
+// returns it. It returns domain.ErrConflict if the job is in another state or
+// its issue has another job in progress.
+func (r *AIJobRepository) Retry(ctx context.Context, jobID int64) (*domain.AIJob, error) {
+	var job domain.AIJob
+	err := r.db.Get(ctx, &job,
+		`WITH j AS (
+		     UPDATE ai_jobs
+		     SET status = 'pending', attempts = 0, started_at = NULL, completed_at = NULL, error_msg = NULL,
+		         cancel_requested_at = NULL, cancelled_by = NULL
+		     WHERE id = $1 AND status IN ('failed', 'cancelled')
+		     RETURNING *
+		 )
+		 SELECT `+aiJobColumns+` FROM j JOIN issues i ON i.id = j.issue_id`,
+		jobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: ai job %d has not failed or been cancelled", domain.ErrConflict, jobID)
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: issue already has an AI job in progress", domain.ErrConflict)
+		}
+		return nil, fmt.Errorf("retry ai job %d: %w", jobID, err)
+	}
+	return &job, nil
+}
+
+// ApproveRun records that a user approved the result of a completed job's
+// latest run, and returns the run. It returns domain.ErrConflict if that run
+// did not complete or is already approved.
+func (r *AIJobRepository) ApproveRun(ctx context.Context, jobID, userID int64) (*domain.AIRun, error) {
+	var run domain.AIRun
+	err := r.db.Get(ctx, &run,
+		`UPDATE ai_runs SET approved_by = $2
+		 WHERE id = (SELECT id FROM ai_runs WHERE job_id = $1 ORDER BY id DESC LIMIT 1)
+		   AND status = 'completed' AND approved_by IS NULL
+		 RETURNING `+runColumns,
+		jobID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: ai job %d has no unapproved completed run", domain.ErrConflict, jobID)
+		}
+		return nil, fmt.Errorf("approve run of ai job %d: %w", jobID, err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const attachmentColumns = `id, issue_id, name, storage_key, content_type, size_bytes, blob_sha256,
+	COALESCE((SELECT b.variants FROM attachment_blobs b WHERE b.sha256 = blob_sha256), '[]') AS variants,
+	uploaded_by, scan_status, scan_signature, scanned_by, scanned_at, created_at`
+
+// AttachmentRepository handles issue attachment data access operations.
+type AttachmentRepository struct {
+	db *queryDB
+}
+
+// NewAttachmentRepository creates a new AttachmentRepository.
+func NewAttachmentRepository(pool *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{db: instrument(pool, "attachment")}
+}
+
+// Create records an attachment with the contents in blob and returns it.
+// If a blob with the same hash is already stored, the attachment shares it
+// and gets its storage key and variants instead of blob's, and blob's
+// objects are no longer needed.
+func (r *AttachmentRepository) Create(ctx context.Context, a domain.Attachment, blob domain.AttachmentBlob) (*domain.Attachment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Updating a conflicting blob locks it, so garbage collection cannot
+	// delete it before the attachment referencing it is committed.
+	var key string
+	err = pgxscan.Get(ctx, tx, &key,
+		`INSERT INTO attachment_blobs (sha256, storage_key, size_bytes, variants)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (sha256) DO UPDATE SET sha256 = EXCLUDED.sha256
+		 RETURNING storage_key`,
+		blob.SHA256, blob.StorageKey, blob.SizeBytes, blob.Variants)
+	if err != nil {
+		return nil, fmt.Errorf("store attachment blob %s: %w", blob.SHA256, err)
+	}
+
+	var result domain.Attachment
+	err = pgxscan.Get(ctx, tx, &result,
+		`INSERT INTO issue_attachments (issue_id, name, storage_key, content_type, size_bytes, blob_sha256, uploaded_by, scan_status)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING `+attachmentColumns,
+		a.IssueID, a.Name, key, a.ContentType, a.SizeBytes, blob.SHA256, a.UploadedBy, a.ScanStatus)
+	if err != nil {
+		return nil, fmt.Errorf("add attachment %q to issue %d: %w", a.Name, a.IssueID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit attachment %q to issue %d: %w", a.Name, a.IssueID, err)
+	}
+	return &result, nil
+}
+
+// ListForIssue returns an issue's attachments, oldest first.
+func (r *AttachmentRepository) ListForIssue(ctx context.Context, issueID int64) ([]domain.Attachment, error) {
+	attachments := []domain.Attachment{}
+	err := r.db.Select(ctx, &attachments,
+		`SELECT `+attachmentColumns+` FROM issue_attachments WHERE issue_id = $1 ORDER BY id`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments of issue %d: %w", issueID, err)
+	}
+	return attachments, nil
+}
+
+// FindByID retrieves an attachment by its ID.
+func (r *AttachmentRepository) FindByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	err := r.db.Get(ctx, &attachment,
+		`SELECT `+attachmentColumns+` FROM issue_attachments WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find attachment by id %d: %w", id, err)
+	}
+	return &attachment, nil
+}
+
+// ListPendingScan returns up to limit attachments awaiting a scan, oldest
+// first.
+func (r *AttachmentRepository) ListPendingScan(ctx context.Context, limit int) ([]domain.Attachment, error) {
+	attachments := []domain.Attachment{}
+	err := r.db.Select(ctx, &attachments,
+		`SELECT `+attachmentColumns+` FROM issue_attachments
+		 WHERE scan_status = 'pending' ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments pending scan: %w", err)
+	}
+	return attachments, nil
+}
+
+// RecordScan stores the result of scanning a pending attachment and returns
+// the updated attachment. Attachments that are no longer pending are left
+// alone and reported as not found. Infected contents infect every
+// attachment sharing them, and their blob is forgotten so the contents are
+// never shared again and its object can be deleted.
+func (r *AttachmentRepository) RecordScan(ctx context.Context, id int64, result domain.ScanResult) (*domain.Attachment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var attachment domain.Attachment
+	err = pgxscan.Get(ctx, tx, &attachment,
+		`UPDATE issue_attachments
+		 SET scan_status = $2, scan_signature = $3, scanned_by = $4, scanned_at = NOW()
+		 WHERE id = $1 AND scan_status = 'pending'
+		 RETURNING `+attachmentColumns,
+		id, result.Status, result.Signature, result.Scanner)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("record scan of attachment %d: %w", id, err)
+	}
+
+	if result.Status == domain.ScanInfected && attachment.SHA256 != nil {
+		_, err = tx.Exec(ctx,
+			`UPDATE issue_attachments
+			 SET scan_status = $3, scan_signature = $4, scanned_by = $5, scanned_at = NOW()
+			 WHERE blob_sha256 = $1 AND id <> $2`,
+			*attachment.SHA256, id, result.Status, result.Signature, result.Scanner)
+		if err != nil {
+			return nil, fmt.Errorf("infect attachments sharing attachment %d: %w", id, err)
+		}
+		if _, err = tx.Exec(ctx, `DELETE FROM attachment_blobs WHERE sha256 = $1`, *attachment.SHA256); err != nil {
+			return nil, fmt.Errorf("forget blob of attachment %d: %w", id, err)
+		}
+		attachment.SHA256 = nil
+		attachment.Variants = nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit scan of attachment %d: %w", id, err)
+	}
+	return &attachment, nil
+}
+
+// DeleteUnreferencedBlobs forgets up to limit blobs no attachment has
+// referenced since before cutoff and returns them, so their objects can be
+// deleted.
+func (r *AttachmentRepository) DeleteUnreferencedBlobs(ctx context.Context, cutoff time.Time, limit int) ([]domain.AttachmentBlob, error) {
+	blobs := []domain.AttachmentBlob{}
+	err := r.db.Select(ctx, &blobs,
+		`DELETE FROM attachment_blobs
+		 WHERE sha256 IN (
+		     SELECT sha256 FROM attachment_blobs
+		     WHERE ref_count = 0 AND unreferenced_at < $1
+		     ORDER BY unreferenced_at
+		     LIMIT $2
+		     FOR UPDATE SKIP LOCKED
+		 ) AND ref_count = 0
+		 RETURNING sha256, storage_key, size_bytes, variants`, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("delete unreferenced attachment blobs: %w", err)
+	}
+	return blobs, nil
+}
+
+// Delete removes an attachment's record.
+func (r *AttachmentRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM issue_attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete attachment %d: %w", id, err)
+	}
+	n := res.RowsAffected()
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const auditColumns = `id, project_id, actor_id, action, target_type, target_id, created_at`
+
+// AuditRepository handles audit log data access operations.
+type AuditRepository struct {
+	db *queryDB
+}
+
+// NewAuditRepository creates a new AuditRepository.
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: instrument(pool, "audit")}
+}
+
+// Record appends an entry to the audit log.
+func (r *AuditRepository) Record(ctx context.Context, entry domain.AuditEntry) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO audit_logs (project_id, actor_id, action, target_type, target_id)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		entry.ProjectID, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID)
+	if err != nil {
+		return fmt.Errorf("record audit %s: %w", entry.Action, err)
+	}
+	return nil
+}
+
+// List returns a project's audit entries, newest first, starting after the
+// position. It fetches one row beyond limit so callers can detect a next page.
+func (r *AuditRepository) List(ctx context.Context, projectID int64, after domain.ListPosition, limit int) ([]domain.AuditEntry, error) {
+	entries := []domain.AuditEntry{}
+	err := r.db.Select(ctx, &entries,
+		`SELECT `+auditColumns+` FROM audit_logs
+		 WHERE project_id = $1 AND ($2::bigint = 0 OR (created_at, id) < ($3::timestamptz, $2))
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $4`, projectID, after.ID, after.At, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries for project %d: %w", projectID, err)
+	}
+	return entries, nil
+}
+
+// Each streams every audit entry in a project, newest first, calling fn for each row.
+func (r *AuditRepository) Each(ctx context.Context, projectID int64, fn func(domain.AuditEntry) error) error {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+auditColumns+` FROM audit_logs WHERE project_id = $1 ORDER BY id DESC`, projectID)
+	if err != nil {
+		return fmt.Errorf("stream audit entries for project %d: %w", projectID, err)
+	}
+	defer rows.Close()
+
+	scanner := pgxscan.NewRowScanner(rows)
+	for rows.Next() {
+		var entry domain.AuditEntry
+		if err := scanner.Scan(&entry); err != nil {
+			return fmt.Errorf("scan audit entry: %w", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const closeRequestColumns = `id, issue_id, status, note, requested_by, state, decided_by, decision_note, decided_at, created_at`
+
+// CloseRequestRepository handles issue close request data access operations.
+type CloseRequestRepository struct {
+	db *queryDB
+}
+
+// NewCloseRequestRepository creates a new CloseRequestRepository.
+func NewCloseRequestRepository(pool *pgxpool.Pool) *CloseRequestRepository {
+	return &CloseRequestRepository{db: instrument(pool, "close_request")}
+}
+
+// Create inserts a pending close request and returns it. It returns
+// domain.ErrConflict if the issue already has a pending request.
+func (r *CloseRequestRepository) Create(ctx context.Context, req domain.CloseRequest) (*domain.CloseRequest, error) {
+	var result domain.CloseRequest
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO close_requests (issue_id, status, note, requested_by)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+closeRequestColumns,
+		req.IssueID, req.Status, req.Note, req.RequestedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: the issue already has a pending close request", domain.ErrConflict)
+		}
+		return nil, fmt.Errorf("create close request for issue %d: %w", req.IssueID, err)
+	}
+	return &result, nil
+}
+
+// Pending returns an issue's pending close request.
+func (r *CloseRequestRepository) Pending(ctx context.Context, issueID int64) (*domain.CloseRequest, error) {
+	var req domain.CloseRequest
+	err := r.db.Get(ctx, &req,
+		`SELECT `+closeRequestColumns+` FROM close_requests WHERE issue_id = $1 AND state = 'pending'`, issueID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find pending close request of issue %d: %w", issueID, err)
+	}
+	return &req, nil
+}
+
+// ListForIssue returns an issue's close requests, newest first.
+func (r *CloseRequestRepository) ListForIssue(ctx context.Context, issueID int64) ([]domain.CloseRequest, error) {
+	requests := []domain.CloseRequest{}
+	err := r.db.Select(ctx, &requests,
+		`SELECT `+closeRequestColumns+` FROM close_requests WHERE issue_id = $1 ORDER BY id DESC`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list close requests of issue %d: %w", issueID, err)
+	}
+	return requests, nil
+}
+
+// Decide records the decision on a pending close request and returns it.
+// It returns domain.ErrConflict if the request was already decided.
+func (r *CloseRequestRepository) Decide(ctx context.Context, id int64, state domain.CloseRequestState, decidedBy int64, note string) (*domain.CloseRequest, error) {
+	var result domain.CloseRequest
+	err := r.db.Get(ctx, &result,
+		`UPDATE close_requests
+		 SET state = $2, decided_by = $3, decision_note = $4, decided_at = NOW()
+		 WHERE id = $1 AND state = 'pending'
+		 RETURNING `+closeRequestColumns,
+		id, state, decidedBy, note)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: the close request was already decided", domain.ErrConflict)
+		}
+		return nil, fmt.Errorf("decide close request %d: %w", id, err)
+	}
+	return &result, nil
+}
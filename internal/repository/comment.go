@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const commentColumns = `id, issue_id, author_id, body, hidden_at, hidden_by,
+		deleted_at, deleted_by, created_at, updated_at`
+
+// CommentRepository handles comment data access operations.
+type CommentRepository struct {
+	db *queryDB
+}
+
+// NewCommentRepository creates a new CommentRepository.
+func NewCommentRepository(pool *pgxpool.Pool) *CommentRepository {
+	return &CommentRepository{db: instrument(pool, "comment")}
+}
+
+// Create inserts a new comment and returns it.
+func (r *CommentRepository) Create(ctx context.Context, comment domain.Comment) (*domain.Comment, error) {
+	var result domain.Comment
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO comments (issue_id, author_id, body)
+		 VALUES ($1, $2, $3)
+		 RETURNING `+commentColumns,
+		comment.IssueID, comment.AuthorID, comment.Body,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+	return &result, nil
+}
+
+// FindByID retrieves a comment by its ID.
+func (r *CommentRepository) FindByID(ctx context.Context, id int64) (*domain.Comment, error) {
+	var comment domain.Comment
+	err := r.db.Get(ctx, &comment,
+		`SELECT `+commentColumns+` FROM comments WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find comment by id %d: %w", id, err)
+	}
+	return &comment, nil
+}
+
+// ListByIssue returns comments on an issue in creation order, starting after
+// the position. Soft-deleted comments are only included when includeDeleted
+// is set. It fetches one row beyond limit so callers can detect a next page.
+func (r *CommentRepository) ListByIssue(ctx context.Context, issueID int64, after domain.ListPosition, limit int, includeDeleted bool) ([]domain.Comment, error) {
+	comments := []domain.Comment{}
+	err := r.db.Select(ctx, &comments,
+		`SELECT `+commentColumns+` FROM comments
+		 WHERE issue_id = $1 AND ($2::bigint = 0 OR (created_at, id) > ($3::timestamptz, $2)) AND ($4 OR deleted_at IS NULL)
+		 ORDER BY created_at, id
+		 LIMIT $5`, issueID, after.ID, after.At, includeDeleted, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list comments for issue %d: %w", issueID, err)
+	}
+	return comments, nil
+}
+
+// ListForTimeline returns the comments on an issue after the position in
+// its timeline, oldest first, leaving out deleted comments. It fetches one
+// row beyond limit so callers can detect a next page.
+func (r *CommentRepository) ListForTimeline(ctx context.Context, issueID int64, after domain.TimelinePosition, limit int) ([]domain.Comment, error) {
+	comments := []domain.Comment{}
+	err := r.db.Select(ctx, &comments,
+		`SELECT `+commentColumns+` FROM comments
+		 WHERE issue_id = $1 AND deleted_at IS NULL
+		   AND (created_at, $2::text, id) > ($3::timestamptz, $4::text, $5::bigint)
+		 ORDER BY created_at, id
+		 LIMIT $6`,
+		issueID, domain.TimelineComment, after.At, after.Kind, after.ID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list timeline comments for issue %d: %w", issueID, err)
+	}
+	return comments, nil
+}
+
+// SetHidden hides a comment on behalf of a moderator, or unhides it when by is nil.
+func (r *CommentRepository) SetHidden(ctx context.Context, id int64, by *int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE comments
+		 SET hidden_at = CASE WHEN $2::bigint IS NULL THEN NULL ELSE NOW() END,
+		     hidden_by = $2
+		 WHERE id = $1`, id, by)
+	if err != nil {
+		return fmt.Errorf("set comment %d hidden: %w", id, err)
+	}
+	return nil
+}
+
+// SoftDelete marks a comment as deleted by the given user.
+func (r *CommentRepository) SoftDelete(ctx context.Context, id, by int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE comments SET deleted_at = NOW(), deleted_by = $2
+		 WHERE id = $1 AND deleted_at IS NULL`, id, by)
+	if err != nil {
+		return fmt.Errorf("delete comment %d: %w", id, err)
+	}
+	return nil
+}
+
+// Restore undoes a soft delete made at or after deletedSince. It reports
+// whether a comment was restored.
+func (r *CommentRepository) Restore(ctx context.Context, id int64, deletedSince time.Time) (bool, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE comments SET deleted_at = NULL, deleted_by = NULL
+		 WHERE id = $1 AND deleted_at >= $2`, id, deletedSince)
+	if err != nil {
+		return false, fmt.Errorf("restore comment %d: %w", id, err)
+	}
+	n := res.RowsAffected()
+	return n > 0, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// CursorRepository stores background worker progress.
+type CursorRepository struct {
+	db *queryDB
+}
+
+// NewCursorRepository creates a new CursorRepository.
+func NewCursorRepository(pool *pgxpool.Pool) *CursorRepository {
+	return &CursorRepository{db: instrument(pool, "cursor")}
+}
+
+// Get returns the named cursor's position, or the zero position if it has
+// never been set.
+func (r *CursorRepository) Get(ctx context.Context, name string) (domain.LogPosition, error) {
+	var position domain.LogPosition
+	err := r.db.QueryRow(ctx,
+		`SELECT xact_id, position FROM worker_cursors WHERE name = $1`, name).Scan(&position.Xact, &position.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.LogPosition{}, nil
+		}
+		return domain.LogPosition{}, fmt.Errorf("get cursor %q: %w", name, err)
+	}
+	return position, nil
+}
+
+// Set stores the named cursor's position.
+func (r *CursorRepository) Set(ctx context.Context, name string, position domain.LogPosition) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO worker_cursors (name, xact_id, position) VALUES ($1, $2, $3)
+		 ON CONFLICT (name) DO UPDATE
+		 SET xact_id = EXCLUDED.xact_id, position = EXCLUDED.position, updated_at = NOW()`,
+		name, position.Xact, position.ID)
+	if err != nil {
+		return fmt.Errorf("set cursor %q: %w", name, err)
+	}
+	return nil
+}
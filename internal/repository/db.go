@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/exaring/otelpgx"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/metrics"
+)
+
+// Pool sizing for the shared pgx connection pool.
+const (
+	maxConns        = 25
+	maxConnLifetime = 5 * time.Minute
+)
+
+// enumTypes are registered on every connection so that values of these
+// types can be encoded in the binary protocol, which COPY always uses.
+var enumTypes = []string{"issue_status", "notification_type"}
+
+// Open connects a pgx connection pool. Repositories query it natively,
+// scanning rows into structs by their db tags, and use its batches and COPY
+// for bulk writes.
+func Open(ctx context.Context, url string) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	cfg.MaxConns = maxConns
+	cfg.MaxConnLifetime = maxConnLifetime
+	cfg.AfterConnect = registerTypes
+	cfg.ConnConfig.Tracer = otelpgx.NewTracer(otelpgx.WithTrimSQLInSpanName())
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return pool, nil
+}
+
+// queryDB wraps the pool to record query metrics under the owning
+// repository's name. Queries inside transactions are not counted
+// individually; each transaction counts once when it begins.
+type queryDB struct {
+	pool *pgxpool.Pool
+	repo string
+}
+
+func instrument(pool *pgxpool.Pool, repo string) *queryDB {
+	return &queryDB{pool: pool, repo: repo}
+}
+
+func (db *queryDB) observe(start time.Time, err error) {
+	metrics.ObserveQuery(db.repo, time.Since(start), err)
+}
+
+// Get scans the single row of a query into dest, a struct or a single
+// column value. It returns pgx.ErrNoRows if there is no row.
+func (db *queryDB) Get(ctx context.Context, dest any, query string, args ...any) error {
+	start := time.Now()
+	err := pgxscan.Get(ctx, db.pool, dest, query, args...)
+	db.observe(start, err)
+	return err
+}
+
+// Select scans every row of a query into dest, a pointer to a slice.
+func (db *queryDB) Select(ctx context.Context, dest any, query string, args ...any) error {
+	start := time.Now()
+	err := pgxscan.Select(ctx, db.pool, dest, query, args...)
+	db.observe(start, err)
+	return err
+}
+
+func (db *queryDB) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := db.pool.Exec(ctx, query, args...)
+	db.observe(start, err)
+	return tag, err
+}
+
+func (db *queryDB) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := db.pool.Query(ctx, query, args...)
+	db.observe(start, err)
+	return rows, err
+}
+
+// QueryRow defers errors to Scan, so only the call and its latency are
+// recorded.
+func (db *queryDB) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	start := time.Now()
+	row := db.pool.QueryRow(ctx, query, args...)
+	db.observe(start, nil)
+	return row
+}
+
+func (db *queryDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	start := time.Now()
+	tx, err := db.pool.Begin(ctx)
+	db.observe(start, err)
+	return tx, err
+}
+
+// BeginFunc runs fn in a transaction, committing it if fn returns nil and
+// rolling it back otherwise.
+func (db *queryDB) BeginFunc(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	start := time.Now()
+	err := pgx.BeginFunc(ctx, db.pool, fn)
+	db.observe(start, err)
+	return err
+}
+
+func (db *queryDB) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	start := time.Now()
+	n, err := db.pool.CopyFrom(ctx, table, columns, src)
+	db.observe(start, err)
+	return n, err
+}
+
+func registerTypes(ctx context.Context, conn *pgx.Conn) error {
+	for _, name := range enumTypes {
+		t, err := conn.LoadType(ctx, name)
+		if err != nil {
+			return fmt.Errorf("load type %s: %w", name, err)
+		}
+		conn.TypeMap().RegisterType(t)
+	}
+	return nil
+}
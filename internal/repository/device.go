@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const deviceColumns = `id, user_id, platform, token, created_at, updated_at`
+
+// DeviceRepository handles push device data access operations.
+type DeviceRepository struct {
+	db *queryDB
+}
+
+// NewDeviceRepository creates a new DeviceRepository.
+func NewDeviceRepository(pool *pgxpool.Pool) *DeviceRepository {
+	return &DeviceRepository{db: instrument(pool, "device")}
+}
+
+// Register records a device for a user and returns it. A token already
+// registered is moved to the user.
+func (r *DeviceRepository) Register(ctx context.Context, d domain.Device) (*domain.Device, error) {
+	var device domain.Device
+	err := r.db.Get(ctx, &device,
+		`INSERT INTO push_devices (user_id, platform, token)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (platform, token) DO UPDATE SET user_id = EXCLUDED.user_id, updated_at = NOW()
+		 RETURNING `+deviceColumns,
+		d.UserID, d.Platform, d.Token)
+	if err != nil {
+		return nil, fmt.Errorf("register device: %w", err)
+	}
+	return &device, nil
+}
+
+// ListForUser returns a user's devices, most recently registered first.
+func (r *DeviceRepository) ListForUser(ctx context.Context, userID int64) ([]domain.Device, error) {
+	devices := []domain.Device{}
+	err := r.db.Select(ctx, &devices,
+		`SELECT `+deviceColumns+` FROM push_devices WHERE user_id = $1 ORDER BY updated_at DESC, id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list devices for user %d: %w", userID, err)
+	}
+	return devices, nil
+}
+
+// UsersWithDevices returns which of userIDs have a device registered.
+func (r *DeviceRepository) UsersWithDevices(ctx context.Context, userIDs []int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Select(ctx, &ids,
+		`SELECT DISTINCT user_id FROM push_devices WHERE user_id = ANY($1)`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("find users with devices: %w", err)
+	}
+	return ids, nil
+}
+
+// Delete removes one of a user's devices. It returns domain.ErrNotFound if
+// the user has no such device.
+func (r *DeviceRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM push_devices WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete device %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Forget removes a device whose token the push service rejected.
+func (r *DeviceRepository) Forget(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM push_devices WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("forget device %d: %w", id, err)
+	}
+	return nil
+}
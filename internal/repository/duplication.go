@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const duplicationColumns = `id, source_project_id, project_id, status, last_issue_id, copied, total,
+	requested_by, created_at, updated_at, finished_at`
+
+// openIssueClause matches issues of project $1 that are neither done nor archived.
+const openIssueClause = `project_id = $1 AND status IN ('open', 'in_progress') AND archived_at IS NULL AND deleted_at IS NULL`
+
+// DuplicationRepository handles project duplication data access operations.
+type DuplicationRepository struct {
+	db *queryDB
+}
+
+// NewDuplicationRepository creates a new DuplicationRepository.
+func NewDuplicationRepository(pool *pgxpool.Pool) *DuplicationRepository {
+	return &DuplicationRepository{db: instrument(pool, "duplication")}
+}
+
+// OpenIssueCount returns the number of issues in a project that are neither
+// done nor archived.
+func (r *DuplicationRepository) OpenIssueCount(ctx context.Context, projectID int64) (int, error) {
+	var n int
+	err := r.db.Get(ctx, &n, `SELECT COUNT(*) FROM issues WHERE `+openIssueClause, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("count open issues in project %d: %w", projectID, err)
+	}
+	return n, nil
+}
+
+// Duplicate inserts project as a copy of sourceID, with the source's labels,
+// in a single transaction. Depending on issues, the source's open issues are
+// copied in the same transaction or a running duplication is recorded for
+// them, which is returned. The duplication is nil otherwise.
+func (r *DuplicationRepository) Duplicate(ctx context.Context, sourceID int64, project domain.Project, issues domain.IssueCopy, userID int64) (*domain.Project, *domain.ProjectDuplication, error) {
+	var result domain.Project
+	var duplication *domain.ProjectDuplication
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			`INSERT INTO projects (name, key, description, owner_id, organization_id, settings)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING `+projectColumns,
+			project.Name, project.Key, project.Description, project.OwnerID, project.OrganizationID, project.Settings,
+		).Scan(&result.ID, &result.Name, &result.Key, &result.Description, &result.OwnerID,
+			&result.OrganizationID, &result.Settings, &result.NextIssueNumber, &result.AIPausedAt,
+			&result.DeletedAt, &result.DeletedBy, &result.CreatedAt, &result.UpdatedAt)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: project key %q is taken", domain.ErrConflict, *project.Key)
+			}
+			return fmt.Errorf("create project: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO labels (project_id, name, color, restricted)
+			 SELECT $2, name, color, restricted FROM labels WHERE project_id = $1`,
+			sourceID, result.ID)
+		if err != nil {
+			return fmt.Errorf("copy labels: %w", err)
+		}
+
+		switch issues {
+		case domain.IssueCopyInline:
+			_, err = copyOpenIssues(ctx, tx, sourceID, result.ID, 0, nil)
+			return err
+		case domain.IssueCopyBackground:
+			d := domain.ProjectDuplication{SourceProjectID: sourceID, ProjectID: result.ID, RequestedBy: &userID}
+			err = tx.QueryRow(ctx,
+				`INSERT INTO project_duplications (source_project_id, project_id, total, requested_by)
+				 VALUES ($1, $2, (SELECT COUNT(*) FROM issues WHERE `+openIssueClause+`), $3)
+				 RETURNING id, status, total, created_at, updated_at`,
+				sourceID, result.ID, userID,
+			).Scan(&d.ID, &d.Status, &d.Total, &d.CreatedAt, &d.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("record duplication: %w", err)
+			}
+			duplication = &d
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("duplicate project %d: %w", sourceID, err)
+	}
+	return &result, duplication, nil
+}
+
+// ForProject returns the duplication that fills a project, if there is one.
+func (r *DuplicationRepository) ForProject(ctx context.Context, projectID int64) (*domain.ProjectDuplication, error) {
+	var duplication domain.ProjectDuplication
+	err := r.db.Get(ctx, &duplication,
+		`SELECT `+duplicationColumns+` FROM project_duplications WHERE project_id = $1`, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find duplication of project %d: %w", projectID, err)
+	}
+	return &duplication, nil
+}
+
+// Running returns the running duplications, oldest first.
+func (r *DuplicationRepository) Running(ctx context.Context) ([]domain.ProjectDuplication, error) {
+	duplications := []domain.ProjectDuplication{}
+	err := r.db.Select(ctx, &duplications,
+		`SELECT `+duplicationColumns+` FROM project_duplications WHERE status = 'running' ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list running duplications: %w", err)
+	}
+	return duplications, nil
+}
+
+// CopyBatch copies up to limit more open issues of a duplication's source
+// and advances the duplication past them in a single transaction. It returns
+// the number of issues copied.
+func (r *DuplicationRepository) CopyBatch(ctx context.Context, duplication domain.ProjectDuplication, limit int) (int, error) {
+	var n int
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		ids, err := copyOpenIssues(ctx, tx, duplication.SourceProjectID, duplication.ProjectID, duplication.LastIssueID, &limit)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		n = len(ids)
+		_, err = tx.Exec(ctx,
+			`UPDATE project_duplications
+			 SET last_issue_id = $2, copied = copied + $3, updated_at = NOW()
+			 WHERE id = $1`,
+			duplication.ID, ids[len(ids)-1], n)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("copy issues for duplication %d: %w", duplication.ID, err)
+	}
+	return n, nil
+}
+
+// Finish marks a running duplication completed.
+func (r *DuplicationRepository) Finish(ctx context.Context, duplicationID int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE project_duplications
+		 SET status = 'completed', finished_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'running'`,
+		duplicationID)
+	if err != nil {
+		return fmt.Errorf("finish duplication %d: %w", duplicationID, err)
+	}
+	return nil
+}
+
+// copyOpenIssues copies the open issues of sourceID with IDs above afterID,
+// up to limit if it is set, into targetID with their labels. Labels are
+// matched by name, so the target must already have them. It returns the IDs
+// of the copied source issues in order.
+func copyOpenIssues(ctx context.Context, tx pgx.Tx, sourceID, targetID, afterID int64, limit *int) ([]int64, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id FROM issues WHERE `+openIssueClause+` AND id > $2 ORDER BY id LIMIT $3`,
+		sourceID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list open issues: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("list open issues: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, id := range ids {
+		batch.Queue(
+			`WITH copy AS (
+			     INSERT INTO issues (project_id, title, body, status, created_by, template, form_data)
+			     SELECT $2, title, body, status, created_by, template, form_data FROM issues WHERE id = $1
+			     RETURNING id)
+			 INSERT INTO issue_labels (issue_id, label_id)
+			 SELECT copy.id, target.id
+			 FROM copy, issue_labels il
+			 JOIN labels source ON source.id = il.label_id
+			 JOIN labels target ON target.project_id = $2 AND target.name = source.name
+			 WHERE il.issue_id = $1`,
+			id, targetID)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("copy issues: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const backfillColumns = `id, model, status, last_issue_id, processed, total, failures, last_error,
+	started_by, created_at, updated_at, finished_at`
+
+// EmbeddingRepository handles issue embedding data access operations.
+type EmbeddingRepository struct {
+	db *queryDB
+}
+
+// NewEmbeddingRepository creates a new EmbeddingRepository.
+func NewEmbeddingRepository(pool *pgxpool.Pool) *EmbeddingRepository {
+	return &EmbeddingRepository{db: instrument(pool, "embedding")}
+}
+
+// StartBackfill starts a backfill over every issue not in the trash and
+// returns it.
+// It returns domain.ErrConflict if a backfill is already running.
+func (r *EmbeddingRepository) StartBackfill(ctx context.Context, model string, userID int64) (*domain.EmbeddingBackfill, error) {
+	var backfill domain.EmbeddingBackfill
+	err := r.db.Get(ctx, &backfill,
+		`INSERT INTO embedding_backfills (model, total, started_by)
+		 VALUES ($1, (SELECT COUNT(*) FROM issues WHERE deleted_at IS NULL), $2)
+		 ON CONFLICT (status) WHERE status = 'running' DO NOTHING
+		 RETURNING `+backfillColumns,
+		model, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: an embedding backfill is already running", domain.ErrConflict)
+		}
+		return nil, fmt.Errorf("start embedding backfill: %w", err)
+	}
+	return &backfill, nil
+}
+
+// LatestBackfill returns the most recently started backfill.
+func (r *EmbeddingRepository) LatestBackfill(ctx context.Context) (*domain.EmbeddingBackfill, error) {
+	var backfill domain.EmbeddingBackfill
+	err := r.db.Get(ctx, &backfill,
+		`SELECT `+backfillColumns+` FROM embedding_backfills ORDER BY id DESC LIMIT 1`)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find latest embedding backfill: %w", err)
+	}
+	return &backfill, nil
+}
+
+// IssuesAfter returns up to limit issues not in the trash with IDs above
+// afterID, in ID order, with only their ID, title and body.
+func (r *EmbeddingRepository) IssuesAfter(ctx context.Context, afterID int64, limit int) ([]domain.Issue, error) {
+	issues := []domain.Issue{}
+	err := r.db.Select(ctx, &issues,
+		`SELECT id, title, body FROM issues WHERE id > $1 AND deleted_at IS NULL ORDER BY id LIMIT $2`,
+		afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list issues after %d: %w", afterID, err)
+	}
+	return issues, nil
+}
+
+// SaveBatch stores the embeddings of a batch of issues and advances the
+// backfill past them in a single transaction, so a batch is never counted
+// without its embeddings being saved.
+func (r *EmbeddingRepository) SaveBatch(ctx context.Context, backfillID int64, model string, issueIDs []int64, embeddings [][]float32) error {
+	if len(issueIDs) != len(embeddings) {
+		return fmt.Errorf("save embeddings: %d issues but %d embeddings", len(issueIDs), len(embeddings))
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for i, id := range issueIDs {
+			batch.Queue(
+				`INSERT INTO issue_embeddings (issue_id, model, embedding) VALUES ($1, $2, $3)
+				 ON CONFLICT (issue_id)
+				 DO UPDATE SET model = EXCLUDED.model, embedding = EXCLUDED.embedding, updated_at = NOW()`,
+				id, model, embeddings[i])
+		}
+		batch.Queue(
+			`UPDATE embedding_backfills
+			 SET last_issue_id = $2, processed = processed + $3, updated_at = NOW()
+			 WHERE id = $1`,
+			backfillID, issueIDs[len(issueIDs)-1], len(issueIDs))
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return fmt.Errorf("save embeddings for backfill %d: %w", backfillID, err)
+	}
+	return nil
+}
+
+// RecordFailure notes a failed batch of a backfill. The backfill keeps its
+// position and retries the batch.
+func (r *EmbeddingRepository) RecordFailure(ctx context.Context, backfillID int64, reason string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE embedding_backfills
+		 SET failures = failures + 1, last_error = $2, updated_at = NOW()
+		 WHERE id = $1`,
+		backfillID, reason)
+	if err != nil {
+		return fmt.Errorf("record failure of embedding backfill %d: %w", backfillID, err)
+	}
+	return nil
+}
+
+// FinishBackfill ends a running backfill with status. It returns
+// domain.ErrNotFound if the backfill is not running.
+func (r *EmbeddingRepository) FinishBackfill(ctx context.Context, backfillID int64, status domain.BackfillStatus) (*domain.EmbeddingBackfill, error) {
+	var backfill domain.EmbeddingBackfill
+	err := r.db.Get(ctx, &backfill,
+		`UPDATE embedding_backfills
+		 SET status = $2, finished_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'running'
+		 RETURNING `+backfillColumns,
+		backfillID, status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("finish embedding backfill %d: %w", backfillID, err)
+	}
+	return &backfill, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const eventColumns = `id, project_id, issue_id, actor_id, type, data, created_at`
+
+// EventRepository handles issue event data access operations.
+type EventRepository struct {
+	db *queryDB
+}
+
+// NewEventRepository creates a new EventRepository.
+func NewEventRepository(pool *pgxpool.Pool) *EventRepository {
+	return &EventRepository{db: instrument(pool, "event")}
+}
+
+// Record appends an issue event.
+func (r *EventRepository) Record(ctx context.Context, event domain.IssueEvent) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO issue_events (project_id, issue_id, actor_id, type, data)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		event.ProjectID, event.IssueID, event.ActorID, event.Type, event.Data)
+	if err != nil {
+		return fmt.Errorf("record event %s: %w", event.Type, err)
+	}
+	return nil
+}
+
+// ListByActor returns events performed by the user in projects they can still
+// access, newest first, starting after the position. It fetches one row
+// beyond limit so callers can detect a next page.
+func (r *EventRepository) ListByActor(ctx context.Context, userID int64, after domain.ListPosition, limit int) ([]domain.IssueEvent, error) {
+	query := `SELECT e.id, e.project_id, e.issue_id, e.actor_id, e.type, e.data, e.created_at
+		 FROM issue_events e
+		 JOIN projects p ON p.id = e.project_id
+		 LEFT JOIN project_members m ON m.project_id = p.id AND m.user_id = $1
+		 WHERE e.actor_id = $1 AND p.deleted_at IS NULL
+		   AND (p.owner_id = $1 OR (m.user_id IS NOT NULL AND NOT ` + blockedClause + `))
+		   AND ($2::bigint = 0 OR (e.created_at, e.id) < ($3::timestamptz, $2))
+		 ORDER BY e.created_at DESC, e.id DESC
+		 LIMIT $4`
+
+	events := []domain.IssueEvent{}
+	if err := r.db.Select(ctx, &events, query, userID, after.ID, after.At, limit+1); err != nil {
+		return nil, fmt.Errorf("list events for actor %d: %w", userID, err)
+	}
+	return events, nil
+}
+
+// ListAfter returns events of the given types after the position in the
+// event log, in commit order.
+func (r *EventRepository) ListAfter(ctx context.Context, after domain.LogPosition, types []domain.EventType, limit int) ([]domain.IssueEvent, error) {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+
+	events := []domain.IssueEvent{}
+	err := r.db.Select(ctx, &events,
+		`SELECT `+eventColumns+`, xact_id::text::bigint AS xact_id
+		 FROM issue_events
+		 WHERE (xact_id, id) > ($1::bigint::text::xid8, $2::bigint) AND `+settledClause+`
+		   AND type = ANY($3)
+		 ORDER BY xact_id, id
+		 LIMIT $4`, after.Xact, after.ID, names, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list events after %+v: %w", after, err)
+	}
+	return events, nil
+}
+
+// ListForTimeline returns the events of an issue after the position in its
+// timeline, oldest first. Comment events are left out, as the timeline
+// shows the comments themselves. It fetches one row beyond limit so
+// callers can detect a next page.
+func (r *EventRepository) ListForTimeline(ctx context.Context, issueID int64, after domain.TimelinePosition, limit int) ([]domain.IssueEvent, error) {
+	events := []domain.IssueEvent{}
+	err := r.db.Select(ctx, &events,
+		`SELECT `+eventColumns+` FROM issue_events
+		 WHERE issue_id = $1 AND type <> $2
+		   AND (created_at, $3::text, id) > ($4::timestamptz, $5::text, $6::bigint)
+		 ORDER BY created_at, id
+		 LIMIT $7`,
+		issueID, domain.EventCommentCreated, domain.TimelineEvent, after.At, after.Kind, after.ID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list timeline events for issue %d: %w", issueID, err)
+	}
+	return events, nil
+}
+
+// LatestPosition returns the position of the newest settled event, or the
+// zero position if there are none.
+func (r *EventRepository) LatestPosition(ctx context.Context) (domain.LogPosition, error) {
+	var position domain.LogPosition
+	err := r.db.QueryRow(ctx,
+		`SELECT xact_id::text::bigint, id FROM issue_events
+		 WHERE `+settledClause+`
+		 ORDER BY xact_id DESC, id DESC
+		 LIMIT 1`).Scan(&position.Xact, &position.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return domain.LogPosition{}, fmt.Errorf("get latest event position: %w", err)
+	}
+	return position, nil
+}
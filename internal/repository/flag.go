@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FlagRepository stores system-wide switches.
+type FlagRepository struct {
+	db *queryDB
+}
+
+// NewFlagRepository creates a new FlagRepository.
+func NewFlagRepository(pool *pgxpool.Pool) *FlagRepository {
+	return &FlagRepository{db: instrument(pool, "flag")}
+}
+
+// Enabled reports whether the named flag is set. Unknown flags are unset.
+func (r *FlagRepository) Enabled(ctx context.Context, name string) (bool, error) {
+	var enabled bool
+	err := r.db.Get(ctx, &enabled,
+		`SELECT EXISTS (SELECT 1 FROM system_flags WHERE name = $1 AND enabled)`, name)
+	if err != nil {
+		return false, fmt.Errorf("read flag %q: %w", name, err)
+	}
+	return enabled, nil
+}
+
+// Set turns the named flag on or off on behalf of the user.
+func (r *FlagRepository) Set(ctx context.Context, name string, enabled bool, userID int64) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO system_flags (name, enabled, updated_by)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (name)
+		 DO UPDATE SET enabled = EXCLUDED.enabled, updated_by = EXCLUDED.updated_by, updated_at = NOW()`,
+		name, enabled, userID)
+	if err != nil {
+		return fmt.Errorf("set flag %q: %w", name, err)
+	}
+	return nil
+}
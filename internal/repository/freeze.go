@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const freezeColumns = `id, project_id, starts_at, ends_at, reason, created_by, created_at`
+
+// FreezeRepository handles project freeze window data access operations.
+type FreezeRepository struct {
+	db *queryDB
+}
+
+// NewFreezeRepository creates a new FreezeRepository.
+func NewFreezeRepository(pool *pgxpool.Pool) *FreezeRepository {
+	return &FreezeRepository{db: instrument(pool, "freeze")}
+}
+
+// Create inserts a freeze window and returns it.
+func (r *FreezeRepository) Create(ctx context.Context, w domain.FreezeWindow) (*domain.FreezeWindow, error) {
+	var result domain.FreezeWindow
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO project_freezes (project_id, starts_at, ends_at, reason, created_by)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+freezeColumns,
+		w.ProjectID, w.StartsAt, w.EndsAt, w.Reason, w.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("create freeze window in project %d: %w", w.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// ListUpcoming returns a project's freeze windows that have not ended by
+// now, soonest first.
+func (r *FreezeRepository) ListUpcoming(ctx context.Context, projectID int64, now time.Time) ([]domain.FreezeWindow, error) {
+	windows := []domain.FreezeWindow{}
+	err := r.db.Select(ctx, &windows,
+		`SELECT `+freezeColumns+` FROM project_freezes
+		 WHERE project_id = $1 AND ends_at > $2 ORDER BY starts_at, id`, projectID, now)
+	if err != nil {
+		return nil, fmt.Errorf("list freeze windows of project %d: %w", projectID, err)
+	}
+	return windows, nil
+}
+
+// Active returns the freeze window in effect for a project at the given
+// time, the one ending last if several overlap.
+func (r *FreezeRepository) Active(ctx context.Context, projectID int64, at time.Time) (*domain.FreezeWindow, error) {
+	var window domain.FreezeWindow
+	err := r.db.Get(ctx, &window,
+		`SELECT `+freezeColumns+` FROM project_freezes
+		 WHERE project_id = $1 AND starts_at <= $2 AND ends_at > $2
+		 ORDER BY ends_at DESC LIMIT 1`, projectID, at)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find active freeze window of project %d: %w", projectID, err)
+	}
+	return &window, nil
+}
+
+// Delete removes a project's freeze window.
+func (r *FreezeRepository) Delete(ctx context.Context, projectID, id int64) error {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM project_freezes WHERE id = $1 AND project_id = $2`, id, projectID)
+	if err != nil {
+		return fmt.Errorf("delete freeze window %d: %w", id, err)
+	}
+	n := res.RowsAffected()
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
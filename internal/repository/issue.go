@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const issueColumns = `id, project_id, number, title, body, status, created_by, assignee_id,
+		milestone_id, ai_session_id, ai_result, pinned_at, closed_by, closed_at, archived_at, template, form_data,
+		deleted_at, deleted_by, created_at, updated_at`
+
+// IssueRepository handles issue data access operations.
+type IssueRepository struct {
+	db *queryDB
+}
+
+// NewIssueRepository creates a new IssueRepository.
+func NewIssueRepository(pool *pgxpool.Pool) *IssueRepository {
+	return &IssueRepository{db: instrument(pool, "issue")}
+}
+
+// List returns issues in a project matching the filter, newest first.
+// It fetches one row beyond filter.Limit so callers can detect a next page.
+func (r *IssueRepository) List(ctx context.Context, projectID int64, filter domain.IssueFilter) ([]domain.Issue, error) {
+	where, args := issueFilterClause(projectID, filter)
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(`SELECT %s FROM issues WHERE %s ORDER BY created_at DESC, id DESC LIMIT $%d`,
+		issueColumns, where, len(args))
+
+	issues := []domain.Issue{}
+	if err := r.db.Select(ctx, &issues, query, args...); err != nil {
+		return nil, fmt.Errorf("list issues for project %d: %w", projectID, err)
+	}
+	return issues, nil
+}
+
+// Each streams every issue in a project matching the filter, newest first,
+// calling fn for each row. Cursor and limit in the filter are ignored.
+func (r *IssueRepository) Each(ctx context.Context, projectID int64, filter domain.IssueFilter, fn func(domain.Issue) error) error {
+	filter.Cursor = domain.ListPosition{}
+	where, args := issueFilterClause(projectID, filter)
+
+	query := fmt.Sprintf(`SELECT %s FROM issues WHERE %s ORDER BY created_at DESC, id DESC`, issueColumns, where)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("stream issues for project %d: %w", projectID, err)
+	}
+	defer rows.Close()
+
+	scanner := pgxscan.NewRowScanner(rows)
+	for rows.Next() {
+		var issue domain.Issue
+		if err := scanner.Scan(&issue); err != nil {
+			return fmt.Errorf("scan issue: %w", err)
+		}
+		if err := fn(issue); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// issueFilterClause translates a filter into a WHERE clause and its arguments.
+func issueFilterClause(projectID int64, f domain.IssueFilter) (string, []any) {
+	conds := []string{"project_id = $1", "deleted_at IS NULL"}
+	args := []any{projectID}
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		add("status = ANY($%d::issue_status[])", statuses)
+	}
+	if len(f.Labels) > 0 {
+		// Duplicates would never let the count reach the cardinality.
+		labels := slices.Clone(f.Labels)
+		slices.Sort(labels)
+		labels = slices.Compact(labels)
+		args = append(args, labels)
+		having := ""
+		if f.LabelMode != domain.LabelModeAny {
+			having = fmt.Sprintf("GROUP BY il.issue_id HAVING COUNT(*) = cardinality($%d::text[])", len(args))
+		}
+		conds = append(conds, fmt.Sprintf(
+			`id IN (SELECT il.issue_id FROM issue_labels il JOIN labels l ON l.id = il.label_id
+			 WHERE l.project_id = $1 AND l.name = ANY($%d::text[]) %s)`, len(args), having))
+	}
+	for _, text := range f.Text {
+		add("(title ILIKE '%%' || $%[1]d || '%%' OR body ILIKE '%%' || $%[1]d || '%%')", escapeLike(text))
+	}
+	if f.AssigneeID != nil {
+		add("assignee_id = $%d", *f.AssigneeID)
+	}
+	if f.MilestoneID != nil {
+		add("milestone_id = $%d", *f.MilestoneID)
+	}
+	if f.CreatedBy != nil {
+		add("created_by = $%d", *f.CreatedBy)
+	}
+	if f.CreatedAfter != nil {
+		add("created_at >= $%d", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		add("created_at < $%d", *f.CreatedBefore)
+	}
+	if f.UpdatedAfter != nil {
+		add("updated_at >= $%d", *f.UpdatedAfter)
+	}
+	if f.UpdatedBefore != nil {
+		add("updated_at < $%d", *f.UpdatedBefore)
+	}
+	if f.HasAIResult {
+		conds = append(conds, "ai_result IS NOT NULL")
+	}
+	if f.Pinned != nil {
+		if *f.Pinned {
+			conds = append(conds, "pinned_at IS NOT NULL")
+		} else {
+			conds = append(conds, "pinned_at IS NULL")
+		}
+	}
+	if f.Archived {
+		conds = append(conds, "archived_at IS NOT NULL")
+	} else {
+		conds = append(conds, "archived_at IS NULL")
+	}
+	if !f.Cursor.IsZero() {
+		args = append(args, f.Cursor.At, f.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d::timestamptz, $%d::bigint)", len(args)-1, len(args)))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// FindByID retrieves an issue by its ID.
+func (r *IssueRepository) FindByID(ctx context.Context, id int64) (*domain.Issue, error) {
+	var issue domain.Issue
+	err := r.db.Get(ctx, &issue,
+		`SELECT `+issueColumns+` FROM issues WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find issue by id %d: %w", id, err)
+	}
+	return &issue, nil
+}
+
+// Create inserts an issue and returns it. An issue created in a done status
+// is closed as of its creation.
+func (r *IssueRepository) Create(ctx context.Context, issue domain.Issue) (*domain.Issue, error) {
+	var result domain.Issue
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO issues (project_id, title, body, status, created_by, assignee_id, closed_by, closed_at, template, form_data)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $7::bigint IS NOT NULL THEN NOW() END, $8, $9)
+		 RETURNING `+issueColumns,
+		issue.ProjectID, issue.Title, issue.Body, issue.Status, issue.CreatedBy, issue.AssigneeID, issue.ClosedBy,
+		issue.Template, issue.Form)
+	if err != nil {
+		return nil, fmt.Errorf("create issue in project %d: %w", issue.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// Clone inserts issue as a copy of sourceID and gives it the source's labels.
+// Labels are matched by name in the new issue's project; any the project
+// lacks are created like the source label. Unless restricted is set, labels
+// restricted in either project are left off.
+func (r *IssueRepository) Clone(ctx context.Context, sourceID int64, issue domain.Issue, restricted bool) (*domain.Issue, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var result domain.Issue
+	err = pgxscan.Get(ctx, tx, &result,
+		`INSERT INTO issues (project_id, title, body, status, created_by, template, form_data)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING `+issueColumns,
+		issue.ProjectID, issue.Title, issue.Body, issue.Status, issue.CreatedBy, issue.Template, issue.Form)
+	if err != nil {
+		return nil, fmt.Errorf("clone issue %d: %w", sourceID, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO labels (project_id, name, color, restricted)
+		 SELECT $2, l.name, l.color, l.restricted
+		 FROM issue_labels il JOIN labels l ON l.id = il.label_id
+		 WHERE il.issue_id = $1 AND ($3 OR NOT l.restricted)
+		 ON CONFLICT (project_id, name) DO NOTHING`,
+		sourceID, result.ProjectID, restricted)
+	if err != nil {
+		return nil, fmt.Errorf("create labels for clone of issue %d: %w", sourceID, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO issue_labels (issue_id, label_id)
+		 SELECT $3, target.id
+		 FROM issue_labels il
+		 JOIN labels source ON source.id = il.label_id
+		 JOIN labels target ON target.project_id = $2 AND target.name = source.name
+		 WHERE il.issue_id = $1 AND ($4 OR NOT (source.restricted OR target.restricted))`,
+		sourceID, result.ProjectID, result.ID, restricted)
+	if err != nil {
+		return nil, fmt.Errorf("copy labels of issue %d: %w", sourceID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit clone of issue %d: %w", sourceID, err)
+	}
+	return &result, nil
+}
+
+// SetAIResult stores the outcome of an AI run on an issue: the session to
+// resume and the agent's final message.
+func (r *IssueRepository) SetAIResult(ctx context.Context, issueID int64, sessionID, result *string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE issues SET ai_session_id = COALESCE($2, ai_session_id), ai_result = $3, updated_at = NOW()
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		issueID, sessionID, result)
+	if err != nil {
+		return fmt.Errorf("set ai result of issue %d: %w", issueID, err)
+	}
+	return nil
+}
+
+// TransitionStatus moves an issue from one status to another on behalf of
+// the system and reports whether it did; it does nothing if the issue is no
+// longer in from. Moving into a done status closes the issue without a
+// closer; moving out of one reopens it.
+func (r *IssueRepository) TransitionStatus(ctx context.Context, issueID int64, from, to domain.IssueStatus) (bool, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE issues
+		 SET status = $3,
+		     closed_by = CASE WHEN $4 THEN closed_by END,
+		     closed_at = CASE WHEN $4 THEN COALESCE(closed_at, NOW()) END,
+		     updated_at = NOW()
+		 WHERE id = $1 AND status = $2 AND deleted_at IS NULL`,
+		issueID, from, to, to.Done())
+	if err != nil {
+		return false, fmt.Errorf("move issue %d from %s to %s: %w", issueID, from, to, err)
+	}
+	n := res.RowsAffected()
+	return n > 0, nil
+}
+
+// CreateMany inserts issues with COPY and returns how many were inserted.
+// IDs and timestamps are assigned by the database and not returned.
+func (r *IssueRepository) CreateMany(ctx context.Context, issues []domain.Issue) (int64, error) {
+	n, err := r.db.CopyFrom(ctx, pgx.Identifier{"issues"},
+		[]string{"project_id", "title", "body", "status", "created_by", "assignee_id", "closed_by", "closed_at"},
+		pgx.CopyFromSlice(len(issues), func(i int) ([]any, error) {
+			is := issues[i]
+			return []any{is.ProjectID, is.Title, is.Body, string(is.Status),
+				is.CreatedBy, is.AssigneeID, is.ClosedBy, is.ClosedAt}, nil
+		}))
+	if err != nil {
+		return 0, fmt.Errorf("copy issues: %w", err)
+	}
+	return n, nil
+}
+
+// Update writes an issue's mutable fields if it satisfies pre and returns the
+// stored result. It returns domain.ErrPreconditionFailed if the issue was
+// modified after pre.UnmodifiedSince.
+func (r *IssueRepository) Update(ctx context.Context, issue domain.Issue, pre domain.Precondition) (*domain.Issue, error) {
+	var result domain.Issue
+	err := r.db.Get(ctx, &result,
+		`UPDATE issues
+		 SET title = $3, body = $4, status = $5, assignee_id = $6,
+		     closed_by = $7, closed_at = $8, archived_at = $9, updated_at = NOW()
+		 WHERE id = $1 AND project_id = $2 AND deleted_at IS NULL AND `+unmodifiedSinceClause(10)+`
+		 RETURNING `+issueColumns,
+		issue.ID, issue.ProjectID, issue.Title, issue.Body, issue.Status, issue.AssigneeID,
+		issue.ClosedBy, issue.ClosedAt, issue.ArchivedAt, pre.UnmodifiedSince)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, missingOrStale(ctx, r.db, "issues", issue.ID)
+		}
+		return nil, fmt.Errorf("update issue %d: %w", issue.ID, err)
+	}
+	return &result, nil
+}
+
+// Delete moves an issue to the trash if it satisfies pre.
+func (r *IssueRepository) Delete(ctx context.Context, projectID, issueID, by int64, pre domain.Precondition) error {
+	res, err := r.db.Exec(ctx,
+		`UPDATE issues SET deleted_at = NOW(), deleted_by = $3, pinned_at = NULL
+		 WHERE id = $1 AND project_id = $2 AND deleted_at IS NULL AND `+unmodifiedSinceClause(4),
+		issueID, projectID, by, pre.UnmodifiedSince)
+	if err != nil {
+		return fmt.Errorf("delete issue %d: %w", issueID, err)
+	}
+	if res.RowsAffected() == 0 {
+		return missingOrStale(ctx, r.db, "issues", issueID)
+	}
+	return nil
+}
+
+// ListDeleted returns the issues in a project's trash, most recently
+// deleted first, starting after the position of their deletion time and
+// ID. It fetches one row beyond limit so callers can detect a next page.
+func (r *IssueRepository) ListDeleted(ctx context.Context, projectID int64, after domain.ListPosition, limit int) ([]domain.Issue, error) {
+	issues := []domain.Issue{}
+	err := r.db.Select(ctx, &issues,
+		`SELECT `+issueColumns+` FROM issues
+		 WHERE project_id = $1 AND deleted_at IS NOT NULL
+		   AND ($2::bigint = 0 OR (deleted_at, id) < ($3::timestamptz, $2))
+		 ORDER BY deleted_at DESC, id DESC
+		 LIMIT $4`,
+		projectID, after.ID, after.At, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list deleted issues for project %d: %w", projectID, err)
+	}
+	return issues, nil
+}
+
+// FindDeleted retrieves an issue in a project's trash.
+func (r *IssueRepository) FindDeleted(ctx context.Context, projectID, issueID int64) (*domain.Issue, error) {
+	var issue domain.Issue
+	err := r.db.Get(ctx, &issue,
+		`SELECT `+issueColumns+` FROM issues WHERE id = $1 AND project_id = $2 AND deleted_at IS NOT NULL`,
+		issueID, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find deleted issue %d: %w", issueID, err)
+	}
+	return &issue, nil
+}
+
+// Restore takes an issue out of the trash and returns it.
+func (r *IssueRepository) Restore(ctx context.Context, projectID, issueID int64) (*domain.Issue, error) {
+	var issue domain.Issue
+	err := r.db.Get(ctx, &issue,
+		`UPDATE issues SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+		 WHERE id = $1 AND project_id = $2 AND deleted_at IS NOT NULL
+		 RETURNING `+issueColumns,
+		issueID, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("restore issue %d: %w", issueID, err)
+	}
+	return &issue, nil
+}
+
+// PurgeDeleted permanently removes issues that were moved to the trash
+// before cutoff and returns how many were removed.
+func (r *IssueRepository) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM issues WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted issues: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// ArchiveClosed archives and unpins issues that have been closed for longer
+// than their project's archive_after_days setting. It returns how many
+// issues were archived.
+func (r *IssueRepository) ArchiveClosed(ctx context.Context) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE issues i SET archived_at = NOW(), pinned_at = NULL
+		 FROM projects p
+		 WHERE p.id = i.project_id
+		   AND i.archived_at IS NULL AND i.closed_at IS NOT NULL AND i.deleted_at IS NULL
+		   AND COALESCE((p.settings->>'archive_after_days')::int, 0) > 0
+		   AND i.closed_at < NOW() - make_interval(days => (p.settings->>'archive_after_days')::int)`)
+	if err != nil {
+		return 0, fmt.Errorf("archive closed issues: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// Pin marks an issue as pinned unless the project already has limit pinned
+// issues. It reports whether the issue is pinned after the call, and
+// whether the call pinned it rather than finding it pinned already.
+func (r *IssueRepository) Pin(ctx context.Context, projectID, issueID int64, limit int) (pinned, changed bool, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// A project's issues are pinned one at a time, so concurrent pins cannot
+	// each count fewer than limit pinned issues and together exceed it.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('pinned_issues:' || $1::text))`, projectID); err != nil {
+		return false, false, fmt.Errorf("lock pinned issues of project %d: %w", projectID, err)
+	}
+
+	var state struct {
+		Pinned bool `db:"pinned"`
+		Count  int  `db:"count"`
+	}
+	err = pgxscan.Get(ctx, tx, &state,
+		`SELECT EXISTS (SELECT 1 FROM issues WHERE id = $1 AND project_id = $2 AND pinned_at IS NOT NULL) AS pinned,
+		        (SELECT COUNT(*) FROM issues
+		         WHERE project_id = $2 AND pinned_at IS NOT NULL AND deleted_at IS NULL) AS count`,
+		issueID, projectID)
+	if err != nil {
+		return false, false, fmt.Errorf("count pinned issues of project %d: %w", projectID, err)
+	}
+	if state.Pinned {
+		return true, false, nil
+	}
+	if state.Count >= limit {
+		return false, false, nil
+	}
+
+	res, err := tx.Exec(ctx,
+		`UPDATE issues SET pinned_at = NOW()
+		 WHERE id = $1 AND project_id = $2 AND pinned_at IS NULL AND deleted_at IS NULL`,
+		issueID, projectID)
+	if err != nil {
+		return false, false, fmt.Errorf("pin issue %d: %w", issueID, err)
+	}
+	n := res.RowsAffected()
+	if err := tx.Commit(ctx); err != nil {
+		return false, false, fmt.Errorf("commit tx: %w", err)
+	}
+	return n > 0, n > 0, nil
+}
+
+// Unpin clears an issue's pinned state. It reports whether the issue was
+// pinned.
+func (r *IssueRepository) Unpin(ctx context.Context, projectID, issueID int64) (bool, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE issues SET pinned_at = NULL WHERE id = $1 AND project_id = $2 AND pinned_at IS NOT NULL`,
+		issueID, projectID)
+	if err != nil {
+		return false, fmt.Errorf("unpin issue %d: %w", issueID, err)
+	}
+	n := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ListPinned returns the pinned issues in a project matching the filter,
+// in the order they were pinned.
+func (r *IssueRepository) ListPinned(ctx context.Context, projectID int64, filter domain.IssueFilter) ([]domain.Issue, error) {
+	pinned := true
+	filter.Pinned = &pinned
+	filter.Cursor = domain.ListPosition{}
+	where, args := issueFilterClause(projectID, filter)
+
+	query := fmt.Sprintf(`SELECT %s FROM issues WHERE %s ORDER BY pinned_at`, issueColumns, where)
+
+	issues := []domain.Issue{}
+	if err := r.db.Select(ctx, &issues, query, args...); err != nil {
+		return nil, fmt.Errorf("list pinned issues for project %d: %w", projectID, err)
+	}
+	return issues, nil
+}
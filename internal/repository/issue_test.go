@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+func TestIssueFilterClauseLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     domain.IssueFilter
+		wantLabels []string
+		wantHaving bool
+	}{
+		{"all", domain.IssueFilter{Labels: []string{"bug", "ui"}}, []string{"bug", "ui"}, true},
+		{"explicit all", domain.IssueFilter{Labels: []string{"ui", "bug"}, LabelMode: domain.LabelModeAll}, []string{"bug", "ui"}, true},
+		{"duplicates", domain.IssueFilter{Labels: []string{"bug", "bug"}}, []string{"bug"}, true},
+		{"any", domain.IssueFilter{Labels: []string{"bug", "ui", "bug"}, LabelMode: domain.LabelModeAny}, []string{"bug", "ui"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := issueFilterClause(1, tt.filter)
+			labels, ok := args[len(args)-1].([]string)
+			if !ok {
+				t.Fatalf("last argument = %#v, want the label names", args[len(args)-1])
+			}
+			if !slices.Equal(labels, tt.wantLabels) {
+				t.Errorf("labels = %q, want %q", labels, tt.wantLabels)
+			}
+			if got := strings.Contains(where, "HAVING"); got != tt.wantHaving {
+				t.Errorf("HAVING in clause = %v, want %v:\n%s", got, tt.wantHaving, where)
+			}
+		})
+	}
+}
+
+func TestIssueFilterClauseLeavesLabelsUnchanged(t *testing.T) {
+	labels := []string{"ui", "bug", "bug"}
+	issueFilterClause(1, domain.IssueFilter{Labels: labels})
+	if want := []string{"ui", "bug", "bug"}; !slices.Equal(labels, want) {
+		t.Errorf("filter labels = %q, want %q", labels, want)
+	}
+}
+
+func TestIssueFilterClauseCursor(t *testing.T) {
+	after := domain.ListPosition{At: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), ID: 42}
+	where, args := issueFilterClause(1, domain.IssueFilter{Cursor: after})
+	if !strings.Contains(where, "(created_at, id) < ($2::timestamptz, $3::bigint)") {
+		t.Errorf("clause does not start after the cursor:\n%s", where)
+	}
+	if len(args) != 3 || args[1] != after.At || args[2] != after.ID {
+		t.Errorf("args = %v, want [1 %v %d]", args, after.At, after.ID)
+	}
+
+	where, args = issueFilterClause(1, domain.IssueFilter{})
+	if strings.Contains(where, "created_at") || len(args) != 1 {
+		t.Errorf("zero cursor clause = %q with args %v, want no position condition", where, args)
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const labelColumns = `id, project_id, name, color, restricted, created_at`
+
+// LabelRepository handles label data access operations.
+type LabelRepository struct {
+	db *queryDB
+}
+
+// NewLabelRepository creates a new LabelRepository.
+func NewLabelRepository(pool *pgxpool.Pool) *LabelRepository {
+	return &LabelRepository{db: instrument(pool, "label")}
+}
+
+// ListByProject returns all labels in a project ordered by name.
+func (r *LabelRepository) ListByProject(ctx context.Context, projectID int64) ([]domain.Label, error) {
+	labels := []domain.Label{}
+	err := r.db.Select(ctx, &labels,
+		`SELECT `+labelColumns+` FROM labels WHERE project_id = $1 ORDER BY name`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list labels for project %d: %w", projectID, err)
+	}
+	return labels, nil
+}
+
+// FindByID retrieves a label by its ID.
+func (r *LabelRepository) FindByID(ctx context.Context, id int64) (*domain.Label, error) {
+	var label domain.Label
+	err := r.db.Get(ctx, &label,
+		`SELECT `+labelColumns+` FROM labels WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find label by id %d: %w", id, err)
+	}
+	return &label, nil
+}
+
+// ListForIssue returns the labels applied to an issue ordered by name.
+func (r *LabelRepository) ListForIssue(ctx context.Context, issueID int64) ([]domain.Label, error) {
+	labels := []domain.Label{}
+	err := r.db.Select(ctx, &labels,
+		`SELECT l.id, l.project_id, l.name, l.color, l.restricted, l.created_at
+		 FROM labels l JOIN issue_labels il ON il.label_id = l.id
+		 WHERE il.issue_id = $1 ORDER BY l.name`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list labels for issue %d: %w", issueID, err)
+	}
+	return labels, nil
+}
+
+// Create inserts a label and returns it. It returns domain.ErrConflict if
+// the project already has a label with that name.
+func (r *LabelRepository) Create(ctx context.Context, label domain.Label) (*domain.Label, error) {
+	var result domain.Label
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO labels (project_id, name, color, restricted) VALUES ($1, $2, $3, $4)
+		 RETURNING `+labelColumns,
+		label.ProjectID, label.Name, label.Color, label.Restricted)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: label %q already exists", domain.ErrConflict, label.Name)
+		}
+		return nil, fmt.Errorf("create label in project %d: %w", label.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// Update writes a label's name and color and returns it. It returns
+// domain.ErrConflict if another label in the project has the name.
+func (r *LabelRepository) Update(ctx context.Context, label domain.Label) (*domain.Label, error) {
+	var result domain.Label
+	err := r.db.Get(ctx, &result,
+		`UPDATE labels SET name = $2, color = $3 WHERE id = $1 RETURNING `+labelColumns,
+		label.ID, label.Name, label.Color)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: label %q already exists", domain.ErrConflict, label.Name)
+		}
+		return nil, fmt.Errorf("update label %d: %w", label.ID, err)
+	}
+	return &result, nil
+}
+
+// Delete removes a label and, by cascade, detaches it from every issue.
+func (r *LabelRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM labels WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete label %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Merge moves every issue with label sourceID to label targetID and deletes
+// the source, in a single transaction. Issues that already have the target
+// keep it once. It returns how many issues gained the target label.
+func (r *LabelRepository) Merge(ctx context.Context, sourceID, targetID int64) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx,
+		`INSERT INTO issue_labels (issue_id, label_id)
+		 SELECT issue_id, $2 FROM issue_labels WHERE label_id = $1
+		 ON CONFLICT DO NOTHING`,
+		sourceID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("move issues from label %d to %d: %w", sourceID, targetID, err)
+	}
+	moved := res.RowsAffected()
+
+	res, err = tx.Exec(ctx, `DELETE FROM labels WHERE id = $1`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("delete label %d: %w", sourceID, err)
+	}
+	if res.RowsAffected() == 0 {
+		return 0, domain.ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit label merge: %w", err)
+	}
+	return moved, nil
+}
+
+// SetRestricted marks a label restricted or not and returns it.
+func (r *LabelRepository) SetRestricted(ctx context.Context, id int64, restricted bool) (*domain.Label, error) {
+	var label domain.Label
+	err := r.db.Get(ctx, &label,
+		`UPDATE labels SET restricted = $2 WHERE id = $1 RETURNING `+labelColumns,
+		id, restricted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("set restricted for label %d: %w", id, err)
+	}
+	return &label, nil
+}
+
+// Attach applies a label to an issue and reports whether the issue lacked
+// it. Applying it twice is a no-op.
+func (r *LabelRepository) Attach(ctx context.Context, issueID, labelID int64) (bool, error) {
+	res, err := r.db.Exec(ctx,
+		`INSERT INTO issue_labels (issue_id, label_id) VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		issueID, labelID)
+	if err != nil {
+		return false, fmt.Errorf("attach label %d to issue %d: %w", labelID, issueID, err)
+	}
+	n := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Detach removes a label from an issue and reports whether the issue had
+// it. Removing a label the issue does not have is a no-op.
+func (r *LabelRepository) Detach(ctx context.Context, issueID, labelID int64) (bool, error) {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM issue_labels WHERE issue_id = $1 AND label_id = $2`,
+		issueID, labelID)
+	if err != nil {
+		return false, fmt.Errorf("detach label %d from issue %d: %w", labelID, issueID, err)
+	}
+	n := res.RowsAffected()
+	return n > 0, nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const labelSyncColumns = `id, organization_id, labels, prune, status, total, synced,
+	requested_by, created_at, updated_at, finished_at`
+
+// LabelSyncRepository handles label sync data access operations.
+type LabelSyncRepository struct {
+	db *queryDB
+}
+
+// NewLabelSyncRepository creates a new LabelSyncRepository.
+func NewLabelSyncRepository(pool *pgxpool.Pool) *LabelSyncRepository {
+	return &LabelSyncRepository{db: instrument(pool, "label_sync")}
+}
+
+// ProjectIDs returns the IDs of an organization's projects outside the trash
+// in ascending order.
+func (r *LabelSyncRepository) ProjectIDs(ctx context.Context, orgID int64) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(ctx, &ids,
+		`SELECT id FROM projects WHERE organization_id = $1 AND deleted_at IS NULL ORDER BY id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list projects of organization %d: %w", orgID, err)
+	}
+	return ids, nil
+}
+
+// Create records a running sync targeting projectIDs and returns it.
+func (r *LabelSyncRepository) Create(ctx context.Context, sync domain.LabelSync, projectIDs []int64) (*domain.LabelSync, error) {
+	var result domain.LabelSync
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = pgxscan.Get(ctx, tx, &result,
+		`INSERT INTO label_syncs (organization_id, labels, prune, total, requested_by)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+labelSyncColumns,
+		sync.OrganizationID, sync.Labels, sync.Prune, len(projectIDs), sync.RequestedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create label sync: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO label_sync_projects (sync_id, project_id)
+		 SELECT $1, unnest($2::bigint[])`,
+		result.ID, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("record label sync projects: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &result, nil
+}
+
+// FindByID retrieves a sync of an organization with its per-project report.
+func (r *LabelSyncRepository) FindByID(ctx context.Context, orgID, id int64) (*domain.LabelSync, error) {
+	var sync domain.LabelSync
+	err := r.db.Get(ctx, &sync,
+		`SELECT `+labelSyncColumns+` FROM label_syncs WHERE id = $1 AND organization_id = $2`, id, orgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find label sync by id %d: %w", id, err)
+	}
+
+	sync.Report = []domain.LabelSyncResult{}
+	err = r.db.Select(ctx, &sync.Report,
+		`SELECT project_id, changes, synced_at FROM label_sync_projects
+		 WHERE sync_id = $1 ORDER BY project_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("list label sync %d report: %w", id, err)
+	}
+	return &sync, nil
+}
+
+// ListForOrganization returns an organization's syncs, newest first.
+func (r *LabelSyncRepository) ListForOrganization(ctx context.Context, orgID int64) ([]domain.LabelSync, error) {
+	syncs := []domain.LabelSync{}
+	err := r.db.Select(ctx, &syncs,
+		`SELECT `+labelSyncColumns+` FROM label_syncs WHERE organization_id = $1 ORDER BY id DESC`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list label syncs of organization %d: %w", orgID, err)
+	}
+	return syncs, nil
+}
+
+// Running returns the running syncs, oldest first.
+func (r *LabelSyncRepository) Running(ctx context.Context) ([]domain.LabelSync, error) {
+	syncs := []domain.LabelSync{}
+	err := r.db.Select(ctx, &syncs,
+		`SELECT `+labelSyncColumns+` FROM label_syncs WHERE status = 'running' ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list running label syncs: %w", err)
+	}
+	return syncs, nil
+}
+
+// SyncNext applies a sync to the next of its projects not yet synced, in
+// project ID order, and records the changes, in a single transaction. It
+// returns false when no project is left. Projects no longer in the sync's
+// organization are skipped.
+func (r *LabelSyncRepository) SyncNext(ctx context.Context, sync domain.LabelSync) (bool, error) {
+	names := make([]string, len(sync.Labels))
+	colors := make([]string, len(sync.Labels))
+	for i, l := range sync.Labels {
+		names[i], colors[i] = l.Name, l.Color
+	}
+
+	var found bool
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		var projectID int64
+		var member bool
+		err := tx.QueryRow(ctx,
+			`SELECT sp.project_id, p.organization_id IS NOT DISTINCT FROM $2
+			 FROM label_sync_projects sp
+			 JOIN projects p ON p.id = sp.project_id
+			 WHERE sp.sync_id = $1 AND sp.synced_at IS NULL
+			 ORDER BY sp.project_id
+			 LIMIT 1
+			 FOR UPDATE OF sp`,
+			sync.ID, sync.OrganizationID,
+		).Scan(&projectID, &member)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("find next project: %w", err)
+		}
+		found = true
+
+		changes := domain.LabelSyncChanges{Created: []string{}, Recolored: []string{}, Pruned: []string{}, Skipped: !member}
+		if member {
+			if err := applyLabelSet(ctx, tx, projectID, names, colors, sync.Prune, &changes); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx,
+			`UPDATE label_sync_projects SET changes = $3, synced_at = NOW()
+			 WHERE sync_id = $1 AND project_id = $2`,
+			sync.ID, projectID, changes); err != nil {
+			return fmt.Errorf("record changes: %w", err)
+		}
+		_, err = tx.Exec(ctx,
+			`UPDATE label_syncs SET synced = synced + 1, updated_at = NOW() WHERE id = $1`, sync.ID)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("sync labels for label sync %d: %w", sync.ID, err)
+	}
+	return found, nil
+}
+
+// applyLabelSet creates the named labels missing from a project, recolors
+// those of another color and, with prune set, deletes the project's other
+// labels, noting each in changes.
+func applyLabelSet(ctx context.Context, tx pgx.Tx, projectID int64, names, colors []string, prune bool, changes *domain.LabelSyncChanges) error {
+	rows, err := tx.Query(ctx,
+		`INSERT INTO labels (project_id, name, color)
+		 SELECT $1, name, color FROM unnest($2::text[], $3::text[]) AS l(name, color)
+		 ON CONFLICT (project_id, name) DO UPDATE SET color = EXCLUDED.color
+		 WHERE labels.color <> EXCLUDED.color
+		 RETURNING name, xmax = 0`,
+		projectID, names, colors)
+	if err != nil {
+		return fmt.Errorf("upsert labels: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var inserted bool
+		if err := rows.Scan(&name, &inserted); err != nil {
+			rows.Close()
+			return fmt.Errorf("upsert labels: %w", err)
+		}
+		if inserted {
+			changes.Created = append(changes.Created, name)
+		} else {
+			changes.Recolored = append(changes.Recolored, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("upsert labels: %w", err)
+	}
+
+	if !prune {
+		return nil
+	}
+	rows, err = tx.Query(ctx,
+		`DELETE FROM labels WHERE project_id = $1 AND NOT (name = ANY($2::text[])) RETURNING name`,
+		projectID, names)
+	if err != nil {
+		return fmt.Errorf("prune labels: %w", err)
+	}
+	pruned, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("prune labels: %w", err)
+	}
+	changes.Pruned = append(changes.Pruned, pruned...)
+	return nil
+}
+
+// Finish marks a running sync completed.
+func (r *LabelSyncRepository) Finish(ctx context.Context, syncID int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE label_syncs
+		 SET status = 'completed', finished_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'running'`,
+		syncID)
+	if err != nil {
+		return fmt.Errorf("finish label sync %d: %w", syncID, err)
+	}
+	return nil
+}
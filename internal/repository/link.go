@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const issueLinkColumns = `l.id, l.source_issue_id, l.target_issue_id, l.type, l.created_by, l.created_at`
+
+// IssueLinkRepository handles issue link data access operations.
+type IssueLinkRepository struct {
+	db *queryDB
+}
+
+// NewIssueLinkRepository creates a new IssueLinkRepository.
+func NewIssueLinkRepository(pool *pgxpool.Pool) *IssueLinkRepository {
+	return &IssueLinkRepository{db: instrument(pool, "issue_link")}
+}
+
+// ListForIssue returns the links from and to an issue whose other issue is
+// not deleted, oldest first.
+func (r *IssueLinkRepository) ListForIssue(ctx context.Context, issueID int64) ([]domain.IssueLink, error) {
+	links := []domain.IssueLink{}
+	err := r.db.Select(ctx, &links,
+		`SELECT `+issueLinkColumns+` FROM issue_links l
+		 JOIN issues s ON s.id = l.source_issue_id AND s.deleted_at IS NULL
+		 JOIN issues t ON t.id = l.target_issue_id AND t.deleted_at IS NULL
+		 WHERE l.source_issue_id = $1 OR l.target_issue_id = $1
+		 ORDER BY l.id`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list links of issue %d: %w", issueID, err)
+	}
+	return links, nil
+}
+
+// FindByID retrieves a link by its ID.
+func (r *IssueLinkRepository) FindByID(ctx context.Context, id int64) (*domain.IssueLink, error) {
+	var link domain.IssueLink
+	err := r.db.Get(ctx, &link,
+		`SELECT `+issueLinkColumns+` FROM issue_links l WHERE l.id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find issue link by id %d: %w", id, err)
+	}
+	return &link, nil
+}
+
+// Create inserts a link and returns it. It returns domain.ErrConflict if the
+// issues already have a link of its type, if the target of a parent link
+// already has a parent, or if a directed link would close a cycle.
+func (r *IssueLinkRepository) Create(ctx context.Context, link domain.IssueLink) (*domain.IssueLink, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Directed links of one type are created one at a time, so two
+	// concurrent links cannot each pass the cycle check and close a cycle
+	// together.
+	if link.Type.Directed() {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('issue_links:' || $1::text))`, link.Type); err != nil {
+			return nil, fmt.Errorf("lock issue links: %w", err)
+		}
+	}
+
+	if link.Type == domain.IssueLinkParent {
+		var hasParent bool
+		err := pgxscan.Get(ctx, tx, &hasParent,
+			`SELECT EXISTS (SELECT 1 FROM issue_links WHERE target_issue_id = $1 AND type = 'parent')`, link.TargetIssueID)
+		if err != nil {
+			return nil, fmt.Errorf("check parent of issue %d: %w", link.TargetIssueID, err)
+		}
+		if hasParent {
+			return nil, fmt.Errorf("%w: issue already has a parent", domain.ErrConflict)
+		}
+	}
+	if link.Type.Directed() {
+		var cycle bool
+		err := pgxscan.Get(ctx, tx, &cycle,
+			`WITH RECURSIVE reach (id) AS (
+				SELECT $1::bigint
+				UNION
+				SELECT l.target_issue_id FROM issue_links l JOIN reach ON l.source_issue_id = reach.id
+				WHERE l.type = $3
+			 )
+			 SELECT EXISTS (SELECT 1 FROM reach WHERE id = $2)`,
+			link.TargetIssueID, link.SourceIssueID, link.Type)
+		if err != nil {
+			return nil, fmt.Errorf("check issue link cycle: %w", err)
+		}
+		if cycle {
+			return nil, fmt.Errorf("%w: link would create a cycle", domain.ErrConflict)
+		}
+	}
+
+	var result domain.IssueLink
+	err = pgxscan.Get(ctx, tx, &result,
+		`INSERT INTO issue_links AS l (source_issue_id, target_issue_id, type, created_by)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+issueLinkColumns,
+		link.SourceIssueID, link.TargetIssueID, link.Type, link.CreatedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: issues are already linked", domain.ErrConflict)
+		}
+		return nil, fmt.Errorf("link issue %d to %d: %w", link.SourceIssueID, link.TargetIssueID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit issue link: %w", err)
+	}
+	return &result, nil
+}
+
+// Delete removes a link.
+func (r *IssueLinkRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM issue_links WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete issue link %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Graph returns a project's linked issues that are not deleted, in ID
+// order, and the links between them.
+func (r *IssueLinkRepository) Graph(ctx context.Context, projectID int64) ([]domain.GraphNode, []domain.IssueLink, error) {
+	links := []domain.IssueLink{}
+	err := r.db.Select(ctx, &links,
+		`SELECT `+issueLinkColumns+` FROM issue_links l
+		 JOIN issues s ON s.id = l.source_issue_id AND s.deleted_at IS NULL
+		 JOIN issues t ON t.id = l.target_issue_id AND t.deleted_at IS NULL
+		 WHERE s.project_id = $1 AND t.project_id = $1
+		 ORDER BY l.id`, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list links of project %d: %w", projectID, err)
+	}
+
+	nodes := []domain.GraphNode{}
+	err = r.db.Select(ctx, &nodes,
+		`SELECT i.id, i.number, i.title, i.status, i.assignee_id FROM issues i
+		 WHERE i.project_id = $1 AND i.deleted_at IS NULL
+		   AND EXISTS (SELECT 1 FROM issue_links l WHERE l.source_issue_id = i.id OR l.target_issue_id = i.id)
+		 ORDER BY i.id`, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list linked issues of project %d: %w", projectID, err)
+	}
+	return nodes, links, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const milestoneColumns = `id, project_id, title, description, due_date, state, created_at, updated_at`
+
+// MilestoneRepository handles milestone data access operations.
+type MilestoneRepository struct {
+	db *queryDB
+}
+
+// NewMilestoneRepository creates a new MilestoneRepository.
+func NewMilestoneRepository(pool *pgxpool.Pool) *MilestoneRepository {
+	return &MilestoneRepository{db: instrument(pool, "milestone")}
+}
+
+// ListByProject returns a project's milestones, open ones first, each
+// ordered by due date with undated milestones last.
+func (r *MilestoneRepository) ListByProject(ctx context.Context, projectID int64) ([]domain.Milestone, error) {
+	milestones := []domain.Milestone{}
+	err := r.db.Select(ctx, &milestones,
+		`SELECT `+milestoneColumns+` FROM milestones WHERE project_id = $1
+		 ORDER BY state = 'closed', due_date NULLS LAST, id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list milestones for project %d: %w", projectID, err)
+	}
+	return milestones, nil
+}
+
+// FindByID retrieves a milestone by its ID.
+func (r *MilestoneRepository) FindByID(ctx context.Context, id int64) (*domain.Milestone, error) {
+	var milestone domain.Milestone
+	err := r.db.Get(ctx, &milestone,
+		`SELECT `+milestoneColumns+` FROM milestones WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find milestone by id %d: %w", id, err)
+	}
+	return &milestone, nil
+}
+
+// Create inserts a milestone and returns it. It returns domain.ErrConflict
+// if the project already has a milestone with that title.
+func (r *MilestoneRepository) Create(ctx context.Context, milestone domain.Milestone) (*domain.Milestone, error) {
+	var result domain.Milestone
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO milestones (project_id, title, description, due_date, state) VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+milestoneColumns,
+		milestone.ProjectID, milestone.Title, milestone.Description, milestone.DueDate, milestone.State)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: milestone %q already exists", domain.ErrConflict, milestone.Title)
+		}
+		return nil, fmt.Errorf("create milestone in project %d: %w", milestone.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// Update writes a milestone's fields and returns it. It returns
+// domain.ErrConflict if another milestone in the project has the title.
+func (r *MilestoneRepository) Update(ctx context.Context, milestone domain.Milestone) (*domain.Milestone, error) {
+	var result domain.Milestone
+	err := r.db.Get(ctx, &result,
+		`UPDATE milestones SET title = $2, description = $3, due_date = $4, state = $5, updated_at = NOW()
+		 WHERE id = $1 RETURNING `+milestoneColumns,
+		milestone.ID, milestone.Title, milestone.Description, milestone.DueDate, milestone.State)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: milestone %q already exists", domain.ErrConflict, milestone.Title)
+		}
+		return nil, fmt.Errorf("update milestone %d: %w", milestone.ID, err)
+	}
+	return &result, nil
+}
+
+// Delete removes a milestone. Its issues are left without a milestone.
+func (r *MilestoneRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM milestones WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete milestone %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Assign puts an issue in a milestone, or takes it out of its milestone if
+// milestoneID is nil.
+func (r *MilestoneRepository) Assign(ctx context.Context, issueID int64, milestoneID *int64) error {
+	res, err := r.db.Exec(ctx,
+		`UPDATE issues SET milestone_id = $2, updated_at = NOW() WHERE id = $1`, issueID, milestoneID)
+	if err != nil {
+		return fmt.Errorf("assign milestone to issue %d: %w", issueID, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Progress counts a milestone's issues by whether they are done, including
+// archived ones.
+func (r *MilestoneRepository) Progress(ctx context.Context, id int64) (*domain.MilestoneProgress, error) {
+	progress := domain.MilestoneProgress{MilestoneID: id}
+	err := r.db.Get(ctx, &progress,
+		`SELECT $1::bigint AS milestone_id,
+		        COUNT(*) FILTER (WHERE status NOT IN ('completed', 'closed')) AS open,
+		        COUNT(*) FILTER (WHERE status IN ('completed', 'closed')) AS closed
+		 FROM issues WHERE milestone_id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, fmt.Errorf("count issues in milestone %d: %w", id, err)
+	}
+	return &progress, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const reportColumns = `id, project_id, reporter_id, target_type, target_id, reason, status,
+		resolved_by, resolved_at, created_at`
+
+// ModerationRepository handles project blocks and content reports.
+type ModerationRepository struct {
+	db *queryDB
+}
+
+// NewModerationRepository creates a new ModerationRepository.
+func NewModerationRepository(pool *pgxpool.Pool) *ModerationRepository {
+	return &ModerationRepository{db: instrument(pool, "moderation")}
+}
+
+// Block bars a user from a project. Blocking an already blocked user updates the reason.
+func (r *ModerationRepository) Block(ctx context.Context, block domain.ProjectBlock) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO project_blocks (project_id, user_id, blocked_by, reason)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (project_id, user_id)
+		 DO UPDATE SET blocked_by = EXCLUDED.blocked_by, reason = EXCLUDED.reason`,
+		block.ProjectID, block.UserID, block.BlockedBy, block.Reason)
+	if err != nil {
+		return fmt.Errorf("block user %d in project %d: %w", block.UserID, block.ProjectID, err)
+	}
+	return nil
+}
+
+// Unblock lifts a user's block from a project.
+func (r *ModerationRepository) Unblock(ctx context.Context, projectID, userID int64) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM project_blocks WHERE project_id = $1 AND user_id = $2`, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("unblock user %d in project %d: %w", userID, projectID, err)
+	}
+	return nil
+}
+
+// ListBlocks returns the users blocked from a project, most recent first.
+func (r *ModerationRepository) ListBlocks(ctx context.Context, projectID int64) ([]domain.ProjectBlock, error) {
+	blocks := []domain.ProjectBlock{}
+	err := r.db.Select(ctx, &blocks,
+		`SELECT project_id, user_id, blocked_by, reason, created_at
+		 FROM project_blocks WHERE project_id = $1
+		 ORDER BY created_at DESC`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list blocks for project %d: %w", projectID, err)
+	}
+	return blocks, nil
+}
+
+// CreateReport files a new content report.
+func (r *ModerationRepository) CreateReport(ctx context.Context, report domain.ContentReport) (*domain.ContentReport, error) {
+	var result domain.ContentReport
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO content_reports (project_id, reporter_id, target_type, target_id, reason)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+reportColumns,
+		report.ProjectID, report.ReporterID, report.TargetType, report.TargetID, report.Reason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create report: %w", err)
+	}
+	return &result, nil
+}
+
+// ListReports returns a project's reports with the given status, oldest first,
+// starting after the position. It fetches one row beyond limit so callers
+// can detect a next page.
+func (r *ModerationRepository) ListReports(ctx context.Context, projectID int64, status domain.ReportStatus, after domain.ListPosition, limit int) ([]domain.ContentReport, error) {
+	reports := []domain.ContentReport{}
+	err := r.db.Select(ctx, &reports,
+		`SELECT `+reportColumns+` FROM content_reports
+		 WHERE project_id = $1 AND status = $2 AND ($3::bigint = 0 OR (created_at, id) > ($4::timestamptz, $3))
+		 ORDER BY created_at, id
+		 LIMIT $5`, projectID, status, after.ID, after.At, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list reports for project %d: %w", projectID, err)
+	}
+	return reports, nil
+}
+
+// ResolveReport closes an open report with the given outcome.
+func (r *ModerationRepository) ResolveReport(ctx context.Context, projectID, reportID int64, status domain.ReportStatus, by int64) (*domain.ContentReport, error) {
+	var result domain.ContentReport
+	err := r.db.Get(ctx, &result,
+		`UPDATE content_reports
+		 SET status = $3, resolved_by = $4, resolved_at = NOW()
+		 WHERE id = $1 AND project_id = $2 AND status = 'open'
+		 RETURNING `+reportColumns,
+		reportID, projectID, status, by,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("resolve report %d: %w", reportID, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const notificationColumns = `id, user_id, issue_id, type, title, message, read, snoozed_until, created_at`
+
+// NotificationRepository handles notification data access operations.
+type NotificationRepository struct {
+	db *queryDB
+}
+
+// NewNotificationRepository creates a new NotificationRepository.
+func NewNotificationRepository(pool *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{db: instrument(pool, "notification")}
+}
+
+// FanOut delivers a copy of n to each user with COPY and returns how many
+// notifications were inserted. n.UserID is ignored.
+func (r *NotificationRepository) FanOut(ctx context.Context, userIDs []int64, n domain.Notification) (int64, error) {
+	count, err := r.db.CopyFrom(ctx, pgx.Identifier{"notifications"},
+		[]string{"user_id", "issue_id", "type", "title", "message"},
+		pgx.CopyFromSlice(len(userIDs), func(i int) ([]any, error) {
+			return []any{userIDs[i], n.IssueID, string(n.Type), n.Title, n.Message}, nil
+		}))
+	if err != nil {
+		return 0, fmt.Errorf("fan out %s notification: %w", n.Type, err)
+	}
+	return count, nil
+}
+
+// ListForUser returns a user's notifications matching the filter, newest
+// first. It fetches one row beyond filter.Limit so callers can detect a next
+// page.
+func (r *NotificationRepository) ListForUser(ctx context.Context, userID int64, filter domain.NotificationFilter) ([]domain.Notification, error) {
+	conds := []string{"user_id = $1"}
+	args := []any{userID}
+	if filter.UnreadOnly {
+		conds = append(conds, "NOT read")
+	}
+	if filter.Snoozed {
+		conds = append(conds, "snoozed_until IS NOT NULL")
+	} else {
+		conds = append(conds, "snoozed_until IS NULL")
+	}
+	if !filter.Cursor.IsZero() {
+		args = append(args, filter.Cursor.At, filter.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d::timestamptz, $%d::bigint)", len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(
+		`SELECT `+notificationColumns+`
+		 FROM notifications
+		 WHERE %s
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $%d`, strings.Join(conds, " AND "), len(args))
+
+	notifications := []domain.Notification{}
+	if err := r.db.Select(ctx, &notifications, query, args...); err != nil {
+		return nil, fmt.Errorf("list notifications for user %d: %w", userID, err)
+	}
+	return notifications, nil
+}
+
+// MarkRead marks one of a user's notifications as read. It returns
+// domain.ErrNotFound if the user has no such notification.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int64) error {
+	res, err := r.db.Exec(ctx,
+		`UPDATE notifications SET read = TRUE WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("mark notification %d read: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns
+// how many there were.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE notifications SET read = TRUE WHERE user_id = $1 AND NOT read`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("mark notifications read for user %d: %w", userID, err)
+	}
+	n := res.RowsAffected()
+	return n, nil
+}
+
+// MarkReadUpTo marks every unread notification of a user with an ID up to
+// and including upTo as read and returns how many there were.
+func (r *NotificationRepository) MarkReadUpTo(ctx context.Context, userID, upTo int64) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE notifications SET read = TRUE WHERE user_id = $1 AND id <= $2 AND NOT read`, userID, upTo)
+	if err != nil {
+		return 0, fmt.Errorf("mark notifications up to %d read for user %d: %w", upTo, userID, err)
+	}
+	n := res.RowsAffected()
+	return n, nil
+}
+
+// UnreadCount returns how many of a user's notifications are unread, not
+// counting snoozed ones.
+func (r *NotificationRepository) UnreadCount(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.db.Get(ctx, &n,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND NOT read AND snoozed_until IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("count unread notifications for user %d: %w", userID, err)
+	}
+	return n, nil
+}
+
+// Snooze hides one of a user's notifications until the given time and
+// returns it. It returns domain.ErrNotFound if the user has no such
+// notification.
+func (r *NotificationRepository) Snooze(ctx context.Context, userID, id int64, until time.Time) (*domain.Notification, error) {
+	var n domain.Notification
+	err := r.db.Get(ctx, &n,
+		`UPDATE notifications SET snoozed_until = $3 WHERE id = $1 AND user_id = $2
+		 RETURNING `+notificationColumns,
+		id, userID, until)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("snooze notification %d: %w", id, err)
+	}
+	return &n, nil
+}
+
+// Resurface ends up to limit snoozes that have expired and returns the
+// notifications, oldest snooze first.
+func (r *NotificationRepository) Resurface(ctx context.Context, limit int) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	err := r.db.Select(ctx, &notifications,
+		`UPDATE notifications SET snoozed_until = NULL
+		 WHERE id IN (
+		     SELECT id FROM notifications
+		     WHERE snoozed_until <= NOW()
+		     ORDER BY snoozed_until
+		     LIMIT $1
+		     FOR UPDATE SKIP LOCKED)
+		 RETURNING `+notificationColumns,
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("resurface snoozed notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// preferencesRow is a notification_preferences row; quiet hours are stored
+// as two nullable columns.
+type preferencesRow struct {
+	UserID            int64             `db:"user_id"`
+	Timezone          string            `db:"timezone"`
+	QuietStart        *domain.ClockTime `db:"quiet_start"`
+	QuietEnd          *domain.ClockTime `db:"quiet_end"`
+	DoNotDisturbUntil *time.Time        `db:"do_not_disturb_until"`
+	EmailCompleted    bool              `db:"email_issue_completed"`
+	EmailFailed       bool              `db:"email_issue_failed"`
+	UpdatedAt         time.Time         `db:"updated_at"`
+}
+
+const preferencesColumns = `user_id, timezone, quiet_start, quiet_end, do_not_disturb_until,
+	email_issue_completed, email_issue_failed, updated_at`
+
+func (row preferencesRow) preferences() *domain.NotificationPreferences {
+	p := &domain.NotificationPreferences{
+		UserID:            row.UserID,
+		Timezone:          row.Timezone,
+		DoNotDisturbUntil: row.DoNotDisturbUntil,
+		Email:             domain.EmailPreferences{IssueCompleted: row.EmailCompleted, IssueFailed: row.EmailFailed},
+		UpdatedAt:         row.UpdatedAt,
+	}
+	if row.QuietStart != nil && row.QuietEnd != nil {
+		p.QuietHours = &domain.QuietHours{Start: *row.QuietStart, End: *row.QuietEnd}
+	}
+	return p
+}
+
+// Preferences returns a user's notification preferences, or the defaults if
+// the user has not set any.
+func (r *NotificationRepository) Preferences(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	var row preferencesRow
+	err := r.db.Get(ctx, &row,
+		`SELECT `+preferencesColumns+` FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p := domain.DefaultNotificationPreferences(userID)
+			return &p, nil
+		}
+		return nil, fmt.Errorf("get notification preferences for user %d: %w", userID, err)
+	}
+	return row.preferences(), nil
+}
+
+// SavePreferences replaces a user's notification preferences and returns
+// them as saved.
+func (r *NotificationRepository) SavePreferences(ctx context.Context, p domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	var start, end *domain.ClockTime
+	if p.QuietHours != nil {
+		start, end = &p.QuietHours.Start, &p.QuietHours.End
+	}
+
+	var row preferencesRow
+	err := r.db.Get(ctx, &row,
+		`INSERT INTO notification_preferences (user_id, timezone, quiet_start, quiet_end, do_not_disturb_until,
+		     email_issue_completed, email_issue_failed)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (user_id) DO UPDATE SET
+		     timezone = EXCLUDED.timezone,
+		     quiet_start = EXCLUDED.quiet_start,
+		     quiet_end = EXCLUDED.quiet_end,
+		     do_not_disturb_until = EXCLUDED.do_not_disturb_until,
+		     email_issue_completed = EXCLUDED.email_issue_completed,
+		     email_issue_failed = EXCLUDED.email_issue_failed,
+		     updated_at = NOW()
+		 RETURNING `+preferencesColumns,
+		p.UserID, p.Timezone, start, end, p.DoNotDisturbUntil, p.Email.IssueCompleted, p.Email.IssueFailed)
+	if err != nil {
+		return nil, fmt.Errorf("save notification preferences for user %d: %w", p.UserID, err)
+	}
+	return row.preferences(), nil
+}
+
+// ListAfter returns notifications after the position, in commit order.
+func (r *NotificationRepository) ListAfter(ctx context.Context, after domain.LogPosition, limit int) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	err := r.db.Select(ctx, &notifications,
+		`SELECT `+notificationColumns+`, xact_id::text::bigint AS xact_id
+		 FROM notifications
+		 WHERE (xact_id, id) > ($1::bigint::text::xid8, $2::bigint) AND `+settledClause+`
+		 ORDER BY xact_id, id
+		 LIMIT $3`, after.Xact, after.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications after %+v: %w", after, err)
+	}
+	return notifications, nil
+}
+
+// LatestPosition returns the position of the newest settled notification,
+// or the zero position if there are none.
+func (r *NotificationRepository) LatestPosition(ctx context.Context) (domain.LogPosition, error) {
+	var position domain.LogPosition
+	err := r.db.QueryRow(ctx,
+		`SELECT xact_id::text::bigint, id FROM notifications
+		 WHERE `+settledClause+`
+		 ORDER BY xact_id DESC, id DESC
+		 LIMIT 1`).Scan(&position.Xact, &position.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return domain.LogPosition{}, fmt.Errorf("get latest notification position: %w", err)
+	}
+	return position, nil
+}
+
+// QueuePush queues notifications to be pushed. Notifications already queued
+// keep their delivery time.
+func (r *NotificationRepository) QueuePush(ctx context.Context, items []domain.PushItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	times := make([]time.Time, len(items))
+	for i, item := range items {
+		ids[i] = item.NotificationID
+		times[i] = item.DeliverAt
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO push_queue (notification_id, deliver_at)
+		 SELECT * FROM unnest($1::bigint[], $2::timestamptz[])
+		 ON CONFLICT (notification_id) DO NOTHING`, ids, times)
+	if err != nil {
+		return fmt.Errorf("queue push notifications: %w", err)
+	}
+	return nil
+}
+
+// TakeDuePushes removes up to limit queued notifications that are due and
+// returns them, oldest first. Notifications read or snoozed meanwhile are
+// removed too but not returned.
+func (r *NotificationRepository) TakeDuePushes(ctx context.Context, limit int) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	err := r.db.Select(ctx, &notifications,
+		`WITH due AS (
+		     DELETE FROM push_queue
+		     WHERE notification_id IN (
+		         SELECT notification_id FROM push_queue
+		         WHERE deliver_at <= NOW()
+		         ORDER BY deliver_at
+		         LIMIT $1
+		         FOR UPDATE SKIP LOCKED)
+		     RETURNING notification_id)
+		 SELECT n.id, n.user_id, n.issue_id, n.type, n.title, n.message, n.read, n.snoozed_until, n.created_at
+		 FROM notifications n
+		 JOIN due ON due.notification_id = n.id
+		 WHERE NOT n.read AND n.snoozed_until IS NULL
+		 ORDER BY n.id`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("take due push notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// QueueEmails queues notifications to be emailed. Notifications already
+// queued keep their delivery time.
+func (r *NotificationRepository) QueueEmails(ctx context.Context, items []domain.EmailItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	times := make([]time.Time, len(items))
+	for i, item := range items {
+		ids[i] = item.NotificationID
+		times[i] = item.DeliverAt
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO email_queue (notification_id, deliver_at)
+		 SELECT * FROM unnest($1::bigint[], $2::timestamptz[])
+		 ON CONFLICT (notification_id) DO NOTHING`, ids, times)
+	if err != nil {
+		return fmt.Errorf("queue email notifications: %w", err)
+	}
+	return nil
+}
+
+// TakeDueEmails removes up to limit queued notifications that are due and
+// returns them, oldest first. Notifications read or snoozed meanwhile are
+// removed too but not returned.
+func (r *NotificationRepository) TakeDueEmails(ctx context.Context, limit int) ([]domain.Notification, error) {
+	notifications := []domain.Notification{}
+	err := r.db.Select(ctx, &notifications,
+		`WITH due AS (
+		     DELETE FROM email_queue
+		     WHERE notification_id IN (
+		         SELECT notification_id FROM email_queue
+		         WHERE deliver_at <= NOW()
+		         ORDER BY deliver_at
+		         LIMIT $1
+		         FOR UPDATE SKIP LOCKED)
+		     RETURNING notification_id)
+		 SELECT n.id, n.user_id, n.issue_id, n.type, n.title, n.message, n.read, n.snoozed_until, n.created_at
+		 FROM notifications n
+		 JOIN due ON due.notification_id = n.id
+		 WHERE NOT n.read AND n.snoozed_until IS NULL
+		 ORDER BY n.id`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("take due email notifications: %w", err)
+	}
+	return notifications, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const organizationColumns = `id, name, owner_id, defaults, created_at, updated_at`
+
+// OrganizationRepository handles organization data access operations.
+type OrganizationRepository struct {
+	db *queryDB
+}
+
+// NewOrganizationRepository creates a new OrganizationRepository.
+func NewOrganizationRepository(pool *pgxpool.Pool) *OrganizationRepository {
+	return &OrganizationRepository{db: instrument(pool, "organization")}
+}
+
+// Create inserts an organization and returns it.
+func (r *OrganizationRepository) Create(ctx context.Context, org domain.Organization) (*domain.Organization, error) {
+	var result domain.Organization
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO organizations (name, owner_id, defaults)
+		 VALUES ($1, $2, $3)
+		 RETURNING `+organizationColumns,
+		org.Name, org.OwnerID, org.Defaults,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create organization: %w", err)
+	}
+	return &result, nil
+}
+
+// FindByID retrieves an organization by its ID.
+func (r *OrganizationRepository) FindByID(ctx context.Context, id int64) (*domain.Organization, error) {
+	var org domain.Organization
+	err := r.db.Get(ctx, &org,
+		`SELECT `+organizationColumns+` FROM organizations WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find organization by id %d: %w", id, err)
+	}
+	return &org, nil
+}
+
+// ListForUser returns the organizations the user owns or belongs to, by name.
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID int64) ([]domain.Organization, error) {
+	orgs := []domain.Organization{}
+	err := r.db.Select(ctx, &orgs,
+		`SELECT `+organizationColumns+` FROM organizations
+		 WHERE owner_id = $1
+		    OR id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		 ORDER BY name, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list organizations for user %d: %w", userID, err)
+	}
+	return orgs, nil
+}
+
+// RoleOf returns the user's role in an organization. It returns
+// domain.ErrNotFound if the organization does not exist or the user is
+// neither owner nor member.
+func (r *OrganizationRepository) RoleOf(ctx context.Context, orgID, userID int64) (domain.OrganizationRole, error) {
+	var role domain.OrganizationRole
+	err := r.db.Get(ctx, &role,
+		`SELECT CASE WHEN o.owner_id = $2 THEN 'owner' ELSE m.role::text END
+		 FROM organizations o
+		 LEFT JOIN organization_members m ON m.organization_id = o.id AND m.user_id = $2
+		 WHERE o.id = $1 AND (o.owner_id = $2 OR m.user_id IS NOT NULL)`,
+		orgID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("find role in organization %d for user %d: %w", orgID, userID, err)
+	}
+	return role, nil
+}
+
+// UpdateDefaults replaces an organization's project defaults and returns it.
+func (r *OrganizationRepository) UpdateDefaults(ctx context.Context, orgID int64, defaults domain.OrganizationDefaults) (*domain.Organization, error) {
+	var org domain.Organization
+	err := r.db.Get(ctx, &org,
+		`UPDATE organizations SET defaults = $2, updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+organizationColumns,
+		orgID, defaults)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("update defaults of organization %d: %w", orgID, err)
+	}
+	return &org, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// partitionSuffix is the layout of the month suffix that
+// create_monthly_partition appends to partition names.
+const partitionSuffix = "_y2006m01"
+
+// PartitionRepository manages monthly range partitions of append-only tables.
+type PartitionRepository struct {
+	db *queryDB
+}
+
+// NewPartitionRepository creates a new PartitionRepository.
+func NewPartitionRepository(pool *pgxpool.Pool) *PartitionRepository {
+	return &PartitionRepository{db: instrument(pool, "partition")}
+}
+
+// Ensure creates the partition of table covering month if it does not
+// exist, moving any rows for that month out of the default partition.
+func (r *PartitionRepository) Ensure(ctx context.Context, table string, month time.Time) error {
+	_, err := r.db.Exec(ctx, `SELECT create_monthly_partition($1, $2)`, table, month)
+	if err != nil {
+		return fmt.Errorf("create partition of %s for %s: %w", table, month.Format("2006-01"), err)
+	}
+	return nil
+}
+
+// DropBefore detaches and drops the monthly partitions of table whose range
+// ends at or before cutoff, and returns their names. The default partition
+// is never dropped.
+func (r *PartitionRepository) DropBefore(ctx context.Context, table string, cutoff time.Time) ([]string, error) {
+	names := []string{}
+	err := r.db.Select(ctx, &names,
+		`SELECT c.relname FROM pg_inherits i
+		 JOIN pg_class c ON c.oid = i.inhrelid
+		 JOIN pg_class p ON p.oid = i.inhparent
+		 WHERE p.relname = $1
+		 ORDER BY c.relname`, table)
+	if err != nil {
+		return nil, fmt.Errorf("list partitions of %s: %w", table, err)
+	}
+
+	dropped := []string{}
+	for _, name := range names {
+		start, err := time.Parse(partitionSuffix, strings.TrimPrefix(name, table))
+		if err != nil || start.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+
+		parent := pgx.Identifier{table}.Sanitize()
+		child := pgx.Identifier{name}.Sanitize()
+		if _, err := r.db.Exec(ctx, `ALTER TABLE `+parent+` DETACH PARTITION `+child); err != nil {
+			return dropped, fmt.Errorf("detach partition %s: %w", name, err)
+		}
+		if _, err := r.db.Exec(ctx, `DROP TABLE `+child); err != nil {
+			return dropped, fmt.Errorf("drop partition %s: %w", name, err)
+		}
+		dropped = append(dropped, name)
+	}
+	return dropped, nil
+}
+
+// PruneDefault deletes the rows of table's default partition from before
+// cutoff, which no monthly partition holds and DropBefore therefore never
+// drops, and returns how many it deleted.
+func (r *PartitionRepository) PruneDefault(ctx context.Context, table string, cutoff time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.QueryRow(ctx, `SELECT prune_default_partition($1, $2)`, table, cutoff).Scan(&deleted)
+	if err != nil {
+		return 0, fmt.Errorf("prune default partition of %s: %w", table, err)
+	}
+	return deleted, nil
+}
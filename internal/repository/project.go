@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const projectColumns = `id, name, key, description, owner_id, organization_id, settings, next_issue_number, ai_paused_at,
+		deleted_at, deleted_by, created_at, updated_at`
+
+// blockedClause matches when the joined member m is blocked from project p.
+const blockedClause = `EXISTS (SELECT 1 FROM project_blocks b
+		WHERE b.project_id = p.id AND b.user_id = m.user_id)`
+
+// accessibleProjectsCTE is a common table expression named accessible
+// listing the IDs of projects the user $1 can access: projects not in the
+// trash that the user owns or is a member of without being blocked.
+const accessibleProjectsCTE = `accessible AS (
+		     SELECT p.id FROM projects p
+		     LEFT JOIN project_members m ON m.project_id = p.id AND m.user_id = $1
+		     WHERE p.deleted_at IS NULL AND (p.owner_id = $1 OR (m.user_id IS NOT NULL AND NOT ` + blockedClause + `))
+		 )`
+
+// ProjectRepository handles project data access operations.
+type ProjectRepository struct {
+	db *queryDB
+}
+
+// NewProjectRepository creates a new ProjectRepository.
+func NewProjectRepository(pool *pgxpool.Pool) *ProjectRepository {
+	return &ProjectRepository{db: instrument(pool, "project")}
+}
+
+// FindByID retrieves a project by its ID.
+func (r *ProjectRepository) FindByID(ctx context.Context, id int64) (*domain.Project, error) {
+	var project domain.Project
+	err := r.db.Get(ctx, &project,
+		`SELECT `+projectColumns+` FROM projects WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find project by id %d: %w", id, err)
+	}
+	return &project, nil
+}
+
+// ListForUser returns projects the user owns or is a member of, newest first,
+// with the user's role and the number of open issues in each project.
+// It fetches one row beyond filter.Limit so callers can detect a next page.
+func (r *ProjectRepository) ListForUser(ctx context.Context, userID int64, filter domain.ProjectFilter) ([]domain.ProjectSummary, error) {
+	conds := []string{"p.deleted_at IS NULL", "(p.owner_id = $1 OR (m.user_id IS NOT NULL AND NOT " + blockedClause + "))"}
+	args := []any{userID}
+
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(strings.ToLower(filter.Query))+"%")
+		conds = append(conds, fmt.Sprintf("LOWER(p.name) LIKE $%d", len(args)))
+	}
+	if !filter.Cursor.IsZero() {
+		args = append(args, filter.Cursor.At, filter.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(p.created_at, p.id) < ($%d::timestamptz, $%d::bigint)", len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(
+		`SELECT p.id, p.name, p.key, p.description, p.owner_id, p.organization_id, p.settings, p.next_issue_number, p.ai_paused_at,
+		        p.created_at, p.updated_at,
+		        CASE WHEN p.owner_id = $1 THEN 'owner' ELSE m.role::text END AS role,
+		        (SELECT COUNT(*) FROM issues i
+		          WHERE i.project_id = p.id AND i.status = 'open' AND i.deleted_at IS NULL) AS open_issue_count
+		 FROM projects p
+		 LEFT JOIN project_members m ON m.project_id = p.id AND m.user_id = $1
+		 WHERE %s
+		 ORDER BY p.created_at DESC, p.id DESC
+		 LIMIT $%d`, strings.Join(conds, " AND "), len(args))
+
+	projects := []domain.ProjectSummary{}
+	if err := r.db.Select(ctx, &projects, query, args...); err != nil {
+		return nil, fmt.Errorf("list projects for user %d: %w", userID, err)
+	}
+	return projects, nil
+}
+
+// RoleOf returns the user's role in a project. It returns domain.ErrNotFound
+// if the project does not exist, the user is neither owner nor member, or the
+// user is blocked from the project.
+func (r *ProjectRepository) RoleOf(ctx context.Context, projectID, userID int64) (domain.ProjectRole, error) {
+	var role domain.ProjectRole
+	err := r.db.Get(ctx, &role,
+		`SELECT CASE WHEN p.owner_id = $2 THEN 'owner' ELSE m.role::text END
+		 FROM projects p
+		 LEFT JOIN project_members m ON m.project_id = p.id AND m.user_id = $2
+		 WHERE p.id = $1 AND p.deleted_at IS NULL AND (p.owner_id = $2 OR (m.user_id IS NOT NULL AND NOT `+blockedClause+`))`,
+		projectID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("find role in project %d for user %d: %w", projectID, userID, err)
+	}
+	return role, nil
+}
+
+// ProjectIDsForUser returns the IDs of the projects the user owns or is a
+// member of and not blocked from, in ascending order.
+func (r *ProjectRepository) ProjectIDsForUser(ctx context.Context, userID int64) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(ctx, &ids,
+		`SELECT p.id
+		 FROM projects p
+		 LEFT JOIN project_members m ON m.project_id = p.id AND m.user_id = $1
+		 WHERE p.deleted_at IS NULL AND (p.owner_id = $1 OR (m.user_id IS NOT NULL AND NOT `+blockedClause+`))
+		 ORDER BY p.id`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("list project ids for user %d: %w", userID, err)
+	}
+	return ids, nil
+}
+
+// MemberIDs returns the IDs of the project's owner and its members who are
+// not blocked.
+func (r *ProjectRepository) MemberIDs(ctx context.Context, projectID int64) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(ctx, &ids,
+		`SELECT owner_id FROM projects WHERE id = $1
+		 UNION
+		 SELECT m.user_id FROM project_members m
+		 JOIN projects p ON p.id = m.project_id
+		 WHERE m.project_id = $1 AND NOT `+blockedClause,
+		projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list members of project %d: %w", projectID, err)
+	}
+	return ids, nil
+}
+
+// Members returns the project's owner and its members who are not blocked,
+// with their roles.
+func (r *ProjectRepository) Members(ctx context.Context, projectID int64) ([]domain.ProjectMember, error) {
+	members := []domain.ProjectMember{}
+	err := r.db.Select(ctx, &members,
+		`SELECT owner_id AS user_id, 'owner' AS role FROM projects WHERE id = $1
+		 UNION ALL
+		 SELECT m.user_id, m.role::text FROM project_members m
+		 JOIN projects p ON p.id = m.project_id
+		 WHERE m.project_id = $1 AND m.user_id <> p.owner_id AND NOT `+blockedClause,
+		projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list members of project %d: %w", projectID, err)
+	}
+	return members, nil
+}
+
+// AddMembers adds users to a project with the given roles and reports, for
+// each distinct user, whether they were added. The owner, existing members
+// and users blocked from the project are left as they are.
+func (r *ProjectRepository) AddMembers(ctx context.Context, projectID int64, members []domain.ProjectMember) ([]domain.MemberResult, error) {
+	ids := make([]int64, len(members))
+	roles := make([]string, len(members))
+	for i, m := range members {
+		ids[i], roles[i] = m.UserID, string(m.Role)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`WITH input AS (
+		     SELECT DISTINCT ON (user_id) user_id, role::project_role AS role
+		     FROM unnest($2::bigint[], $3::text[]) AS t(user_id, role)),
+		 owner AS (SELECT owner_id FROM projects WHERE id = $1),
+		 blocked AS (SELECT user_id FROM project_blocks WHERE project_id = $1),
+		 added AS (
+		     INSERT INTO project_members (project_id, user_id, role)
+		     SELECT $1, i.user_id, i.role FROM input i
+		     WHERE i.user_id NOT IN (SELECT owner_id FROM owner)
+		       AND i.user_id NOT IN (SELECT user_id FROM blocked)
+		     ON CONFLICT (project_id, user_id) DO NOTHING
+		     RETURNING user_id)
+		 SELECT i.user_id,
+		        CASE WHEN i.user_id IN (SELECT owner_id FROM owner) THEN 'owner'
+		             WHEN i.user_id IN (SELECT user_id FROM blocked) THEN 'blocked'
+		             WHEN a.user_id IS NOT NULL THEN 'added'
+		             ELSE 'already_member' END AS status
+		 FROM input i LEFT JOIN added a ON a.user_id = i.user_id`,
+		projectID, ids, roles)
+	if err != nil {
+		return nil, fmt.Errorf("add members to project %d: %w", projectID, err)
+	}
+	return scanMemberResults(rows)
+}
+
+// RemoveMembers removes users from a project and reports, for each distinct
+// user, whether they were removed. The owner cannot be removed.
+func (r *ProjectRepository) RemoveMembers(ctx context.Context, projectID int64, userIDs []int64) ([]domain.MemberResult, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH input AS (SELECT DISTINCT unnest($2::bigint[]) AS user_id),
+		 owner AS (SELECT owner_id FROM projects WHERE id = $1),
+		 removed AS (
+		     DELETE FROM project_members m USING input i
+		     WHERE m.project_id = $1 AND m.user_id = i.user_id
+		       AND m.user_id NOT IN (SELECT owner_id FROM owner)
+		     RETURNING m.user_id)
+		 SELECT i.user_id,
+		        CASE WHEN i.user_id IN (SELECT owner_id FROM owner) THEN 'owner'
+		             WHEN r.user_id IS NOT NULL THEN 'removed'
+		             ELSE 'not_member' END AS status
+		 FROM input i LEFT JOIN removed r ON r.user_id = i.user_id`,
+		projectID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("remove members from project %d: %w", projectID, err)
+	}
+	return scanMemberResults(rows)
+}
+
+func scanMemberResults(rows pgx.Rows) ([]domain.MemberResult, error) {
+	defer rows.Close()
+	results := []domain.MemberResult{}
+	for rows.Next() {
+		var result domain.MemberResult
+		if err := rows.Scan(&result.UserID, &result.Status); err != nil {
+			return nil, fmt.Errorf("scan member result: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// Create inserts a project together with its initial labels in a single
+// transaction and returns the created project. Labels are sent as one batch.
+func (r *ProjectRepository) Create(ctx context.Context, project domain.Project, labels []domain.LabelSpec) (*domain.Project, error) {
+	var result domain.Project
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			`INSERT INTO projects (name, key, description, owner_id, organization_id, settings)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING `+projectColumns,
+			project.Name, project.Key, project.Description, project.OwnerID, project.OrganizationID, project.Settings,
+		).Scan(&result.ID, &result.Name, &result.Key, &result.Description, &result.OwnerID,
+			&result.OrganizationID, &result.Settings, &result.NextIssueNumber, &result.AIPausedAt,
+			&result.DeletedAt, &result.DeletedBy, &result.CreatedAt, &result.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("create project: %w", err)
+		}
+
+		batch := &pgx.Batch{}
+		for _, l := range labels {
+			batch.Queue(
+				`INSERT INTO labels (project_id, name, color) VALUES ($1, $2, $3)
+				 ON CONFLICT (project_id, name) DO NOTHING`,
+				result.ID, l.Name, l.Color)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("create labels: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Update writes a project's name, key, description and settings if it satisfies pre and
+// returns the stored result. It returns domain.ErrPreconditionFailed if the
+// project was modified after pre.UnmodifiedSince.
+func (r *ProjectRepository) Update(ctx context.Context, project domain.Project, pre domain.Precondition) (*domain.Project, error) {
+	var result domain.Project
+	err := r.db.Get(ctx, &result,
+		`UPDATE projects SET name = $2, key = $3, description = $4, settings = $5, updated_at = NOW()
+		 WHERE id = $1 AND deleted_at IS NULL AND `+unmodifiedSinceClause(6)+`
+		 RETURNING `+projectColumns,
+		project.ID, project.Name, project.Key, project.Description, project.Settings, pre.UnmodifiedSince)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, missingOrStale(ctx, r.db, "projects", project.ID)
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: project key %q is taken", domain.ErrConflict, *project.Key)
+		}
+		return nil, fmt.Errorf("update project %d: %w", project.ID, err)
+	}
+	return &result, nil
+}
+
+// SetNextIssueNumber sets the number the project's next issue gets if the
+// project satisfies pre. Numbers can be lowered, but not to one already in
+// use: it returns domain.ErrConflict if an issue has number n or higher.
+// updated_at is left to the Update that follows.
+func (r *ProjectRepository) SetNextIssueNumber(ctx context.Context, id, n int64, pre domain.Precondition) error {
+	res, err := r.db.Exec(ctx,
+		`UPDATE projects SET next_issue_number = $2
+		 WHERE id = $1 AND deleted_at IS NULL AND `+unmodifiedSinceClause(3)+`
+		   AND NOT EXISTS (SELECT 1 FROM issues WHERE project_id = $1 AND number >= $2)`,
+		id, n, pre.UnmodifiedSince)
+	if err != nil {
+		return fmt.Errorf("set next issue number of project %d: %w", id, err)
+	}
+	if res.RowsAffected() > 0 {
+		return nil
+	}
+
+	var highest int64
+	if err := r.db.Get(ctx, &highest,
+		`SELECT COALESCE(MAX(number), 0) FROM issues WHERE project_id = $1`, id); err != nil {
+		return fmt.Errorf("find highest issue number of project %d: %w", id, err)
+	}
+	if highest >= n {
+		return fmt.Errorf("%w: issue numbers up to %d are in use", domain.ErrConflict, highest)
+	}
+	return missingOrStale(ctx, r.db, "projects", id)
+}
+
+// SetAIPaused pauses or resumes AI processing for a project and returns it.
+// Pausing an already paused project keeps the original pause time.
+func (r *ProjectRepository) SetAIPaused(ctx context.Context, id int64, paused bool) (*domain.Project, error) {
+	var project domain.Project
+	err := r.db.Get(ctx, &project,
+		`UPDATE projects
+		 SET ai_paused_at = CASE WHEN $2 THEN COALESCE(ai_paused_at, NOW()) END
+		 WHERE id = $1 AND deleted_at IS NULL
+		 RETURNING `+projectColumns,
+		id, paused)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("set ai paused for project %d: %w", id, err)
+	}
+	return &project, nil
+}
+
+// Delete moves a project, and with it everything in it, to the trash if it
+// satisfies pre.
+func (r *ProjectRepository) Delete(ctx context.Context, id, by int64, pre domain.Precondition) error {
+	res, err := r.db.Exec(ctx,
+		`UPDATE projects SET deleted_at = NOW(), deleted_by = $2
+		 WHERE id = $1 AND deleted_at IS NULL AND `+unmodifiedSinceClause(3),
+		id, by, pre.UnmodifiedSince)
+	if err != nil {
+		return fmt.Errorf("delete project %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return missingOrStale(ctx, r.db, "projects", id)
+	}
+	return nil
+}
+
+// ListDeleted returns the projects the user owns that are in the trash,
+// most recently deleted first.
+func (r *ProjectRepository) ListDeleted(ctx context.Context, ownerID int64) ([]domain.Project, error) {
+	projects := []domain.Project{}
+	err := r.db.Select(ctx, &projects,
+		`SELECT `+projectColumns+` FROM projects
+		 WHERE owner_id = $1 AND deleted_at IS NOT NULL
+		 ORDER BY deleted_at DESC, id DESC`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list deleted projects for user %d: %w", ownerID, err)
+	}
+	return projects, nil
+}
+
+// FindDeleted retrieves a project in the trash.
+func (r *ProjectRepository) FindDeleted(ctx context.Context, id int64) (*domain.Project, error) {
+	var project domain.Project
+	err := r.db.Get(ctx, &project,
+		`SELECT `+projectColumns+` FROM projects WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find deleted project %d: %w", id, err)
+	}
+	return &project, nil
+}
+
+// Restore takes a project out of the trash and returns it. Issues trashed
+// on their own before the project stay in its trash.
+func (r *ProjectRepository) Restore(ctx context.Context, id int64) (*domain.Project, error) {
+	var project domain.Project
+	err := r.db.Get(ctx, &project,
+		`UPDATE projects SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+		 WHERE id = $1 AND deleted_at IS NOT NULL
+		 RETURNING `+projectColumns, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("restore project %d: %w", id, err)
+	}
+	return &project, nil
+}
+
+// PurgeDeleted permanently removes projects, and by cascade everything in
+// them, that were moved to the trash before cutoff. It returns how many
+// projects were removed.
+func (r *ProjectRepository) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM projects WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted projects: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// maxRecentItems bounds how many recently viewed items are kept per user.
+const maxRecentItems = 50
+
+// QuickAccessRepository handles recently viewed and starred items.
+type QuickAccessRepository struct {
+	db *queryDB
+}
+
+// NewQuickAccessRepository creates a new QuickAccessRepository.
+func NewQuickAccessRepository(pool *pgxpool.Pool) *QuickAccessRepository {
+	return &QuickAccessRepository{db: instrument(pool, "quickaccess")}
+}
+
+// RecordView marks an item as viewed now and trims the user's history.
+func (r *QuickAccessRepository) RecordView(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO recent_items (user_id, item_type, item_id)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, item_type, item_id) DO UPDATE SET viewed_at = NOW()`,
+		userID, itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("record view: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`DELETE FROM recent_items
+		 WHERE user_id = $1 AND (item_type, item_id) NOT IN (
+		     SELECT item_type, item_id FROM recent_items
+		     WHERE user_id = $1 ORDER BY viewed_at DESC LIMIT $2)`,
+		userID, maxRecentItems)
+	if err != nil {
+		return fmt.Errorf("trim recent items: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListRecent returns the user's recently viewed items, most recent first.
+func (r *QuickAccessRepository) ListRecent(ctx context.Context, userID int64, limit int) ([]domain.QuickAccessItem, error) {
+	items := []domain.QuickAccessItem{}
+	err := r.db.Select(ctx, &items, quickAccessQuery("recent_items", "viewed_at"), userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list recent items: %w", err)
+	}
+	return items, nil
+}
+
+// Star adds an item to the user's starred items. Starring twice is a no-op.
+func (r *QuickAccessRepository) Star(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO stars (user_id, item_type, item_id)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT DO NOTHING`,
+		userID, itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("star %s %d: %w", itemType, itemID, err)
+	}
+	return nil
+}
+
+// Unstar removes an item from the user's starred items.
+func (r *QuickAccessRepository) Unstar(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM stars WHERE user_id = $1 AND item_type = $2 AND item_id = $3`,
+		userID, itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("unstar %s %d: %w", itemType, itemID, err)
+	}
+	return nil
+}
+
+// ListStarred returns the user's starred items, most recently starred first.
+func (r *QuickAccessRepository) ListStarred(ctx context.Context, userID int64, limit int) ([]domain.QuickAccessItem, error) {
+	items := []domain.QuickAccessItem{}
+	err := r.db.Select(ctx, &items, quickAccessQuery("stars", "created_at"), userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list starred items: %w", err)
+	}
+	return items, nil
+}
+
+// quickAccessQuery builds a listing over a quick-access table, resolving each
+// entry to its project or issue. Entries whose target is gone or in the
+// trash, or whose project the user can no longer access, are skipped.
+func quickAccessQuery(table, timeColumn string) string {
+	return fmt.Sprintf(
+		`WITH `+accessibleProjectsCTE+`
+		 SELECT t.item_type, t.item_id,
+		        COALESCE(p.id, i.project_id) AS project_id,
+		        COALESCE(p.name, i.title) AS title,
+		        t.%[2]s AS at
+		 FROM %[1]s t
+		 LEFT JOIN projects p ON t.item_type = 'project' AND p.id = t.item_id
+		     AND p.id IN (SELECT id FROM accessible)
+		 LEFT JOIN issues i ON t.item_type = 'issue' AND i.id = t.item_id AND i.deleted_at IS NULL
+		     AND i.project_id IN (SELECT id FROM accessible)
+		 WHERE t.user_id = $1 AND (p.id IS NOT NULL OR i.id IS NOT NULL)
+		 ORDER BY t.%[2]s DESC
+		 LIMIT $2`, table, timeColumn)
+}
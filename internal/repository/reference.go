@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// visibleProjectClause matches when project p is visible to the user bound
+// to placeholder n: the owner or an unblocked member.
+func visibleProjectClause(n int) string {
+	return fmt.Sprintf(`(p.owner_id = $%[1]d OR EXISTS (SELECT 1 FROM project_members m
+			WHERE m.project_id = p.id AND m.user_id = $%[1]d AND NOT `+blockedClause+`))`, n)
+}
+
+// ReferenceRepository handles issue reference data access operations.
+type ReferenceRepository struct {
+	db *queryDB
+}
+
+// NewReferenceRepository creates a new ReferenceRepository.
+func NewReferenceRepository(pool *pgxpool.Pool) *ReferenceRepository {
+	return &ReferenceRepository{db: instrument(pool, "reference")}
+}
+
+// Replace sets the references made by an issue's title and body, or by one
+// of its comments if commentID is set, to the issues keys resolve to. Keys
+// naming no existing issue in the keyed project, and self-references, are
+// ignored.
+func (r *ReferenceRepository) Replace(ctx context.Context, sourceIssueID int64, commentID *int64, keys []domain.IssueKey) error {
+	projectKeys := make([]string, len(keys))
+	numbers := make([]int64, len(keys))
+	for i, k := range keys {
+		projectKeys[i] = k.ProjectKey
+		numbers[i] = k.Number
+	}
+
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM issue_references
+			 WHERE source_issue_id = $1 AND comment_id IS NOT DISTINCT FROM $2`,
+			sourceIssueID, commentID); err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO issue_references (source_issue_id, comment_id, target_issue_id)
+			 SELECT DISTINCT $1::bigint, $2::bigint, i.id
+			 FROM unnest($3::text[], $4::bigint[]) AS k(project_key, number)
+			 JOIN projects p ON p.key = k.project_key AND p.deleted_at IS NULL
+			 JOIN issues i ON i.project_id = p.id AND i.number = k.number AND i.deleted_at IS NULL
+			 WHERE i.id <> $1`,
+			sourceIssueID, commentID, projectKeys, numbers)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("replace references from issue %d: %w", sourceIssueID, err)
+	}
+	return nil
+}
+
+// References returns the issues an issue references, in its body or in
+// comments that are neither deleted nor hidden, limited to projects the
+// viewer can see.
+func (r *ReferenceRepository) References(ctx context.Context, issueID, viewerID int64) ([]domain.IssueReference, error) {
+	return r.list(ctx, "source_issue_id", "target_issue_id", issueID, viewerID)
+}
+
+// ReferencedBy returns the issues that reference an issue, in their bodies
+// or in comments that are neither deleted nor hidden, limited to projects
+// the viewer can see.
+func (r *ReferenceRepository) ReferencedBy(ctx context.Context, issueID, viewerID int64) ([]domain.IssueReference, error) {
+	return r.list(ctx, "target_issue_id", "source_issue_id", issueID, viewerID)
+}
+
+// list returns the issues on the other side of references whose from column
+// is issueID.
+func (r *ReferenceRepository) list(ctx context.Context, from, to string, issueID, viewerID int64) ([]domain.IssueReference, error) {
+	refs := []domain.IssueReference{}
+	err := r.db.Select(ctx, &refs,
+		`SELECT DISTINCT ON (i.id, r.comment_id)
+		        i.id AS issue_id, i.project_id, p.key AS project_key, i.number, i.title, i.status, r.comment_id
+		 FROM issue_references r
+		 JOIN issues i ON i.id = r.`+to+` AND i.deleted_at IS NULL
+		 JOIN projects p ON p.id = i.project_id AND p.deleted_at IS NULL
+		 LEFT JOIN comments c ON c.id = r.comment_id
+		 WHERE r.`+from+` = $1
+		   AND (r.comment_id IS NULL OR (c.deleted_at IS NULL AND c.hidden_at IS NULL))
+		   AND `+visibleProjectClause(2)+`
+		 ORDER BY i.id, r.comment_id`,
+		issueID, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("list references of issue %d: %w", issueID, err)
+	}
+	return refs, nil
+}
+
+// ReplaceWikiLinks sets the wiki pages linked from an issue's title and
+// body, or from one of its comments if commentID is set, to slugs.
+func (r *ReferenceRepository) ReplaceWikiLinks(ctx context.Context, issueID int64, commentID *int64, slugs []string) error {
+	err := r.db.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM wiki_links WHERE issue_id = $1 AND comment_id IS NOT DISTINCT FROM $2`,
+			issueID, commentID); err != nil {
+			return err
+		}
+		if len(slugs) == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO wiki_links (issue_id, comment_id, slug)
+			 SELECT $1::bigint, $2::bigint, s.slug FROM unnest($3::text[]) AS s(slug)`,
+			issueID, commentID, slugs)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("replace wiki links from issue %d: %w", issueID, err)
+	}
+	return nil
+}
+
+// WikiPages returns the existing pages of its project's wiki that an issue
+// links to, in its body or in comments that are neither deleted nor hidden,
+// ordered by title.
+func (r *ReferenceRepository) WikiPages(ctx context.Context, issueID int64) ([]domain.WikiPageSummary, error) {
+	pages := []domain.WikiPageSummary{}
+	err := r.db.Select(ctx, &pages,
+		`SELECT `+wikiSummaryColumns+` FROM wiki_pages w
+		 WHERE w.project_id = (SELECT project_id FROM issues WHERE id = $1)
+		   AND EXISTS (SELECT 1 FROM wiki_links l
+			LEFT JOIN comments c ON c.id = l.comment_id
+			WHERE l.issue_id = $1 AND l.slug = w.slug
+			  AND (l.comment_id IS NULL OR (c.deleted_at IS NULL AND c.hidden_at IS NULL)))
+		 ORDER BY w.title, w.id`,
+		issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list wiki pages linked from issue %d: %w", issueID, err)
+	}
+	return pages, nil
+}
+
+// WikiLinkedFrom returns the issues of a project that link to the wiki page
+// with slug, in their bodies or in comments that are neither deleted nor
+// hidden.
+func (r *ReferenceRepository) WikiLinkedFrom(ctx context.Context, projectID int64, slug string) ([]domain.IssueReference, error) {
+	refs := []domain.IssueReference{}
+	err := r.db.Select(ctx, &refs,
+		`SELECT DISTINCT ON (i.id, l.comment_id)
+		        i.id AS issue_id, i.project_id, p.key AS project_key, i.number, i.title, i.status, l.comment_id
+		 FROM wiki_links l
+		 JOIN issues i ON i.id = l.issue_id AND i.project_id = $1 AND i.deleted_at IS NULL
+		 JOIN projects p ON p.id = i.project_id
+		 LEFT JOIN comments c ON c.id = l.comment_id
+		 WHERE l.slug = $2
+		   AND (l.comment_id IS NULL OR (c.deleted_at IS NULL AND c.hidden_at IS NULL))
+		 ORDER BY i.id, l.comment_id`,
+		projectID, slug)
+	if err != nil {
+		return nil, fmt.Errorf("list issues linking to wiki page %q: %w", slug, err)
+	}
+	return refs, nil
+}
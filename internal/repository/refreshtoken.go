@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// RefreshTokenRepository records the refresh tokens that may still be used.
+type RefreshTokenRepository struct {
+	db *queryDB
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository.
+func NewRefreshTokenRepository(pool *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: instrument(pool, "refresh_token")}
+}
+
+// Create records a refresh token issued to a user.
+func (r *RefreshTokenRepository) Create(ctx context.Context, id string, userID int64, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, expires_at) VALUES ($1, $2, $3)`,
+		id, userID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("create refresh token for user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// Consume deletes a user's refresh token if it has not expired by now. It
+// returns domain.ErrNotFound for unknown, already used and expired tokens,
+// so of two concurrent uses only one succeeds.
+func (r *RefreshTokenRepository) Consume(ctx context.Context, id string, userID int64, now time.Time) error {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM refresh_tokens WHERE id = $1 AND user_id = $2 AND expires_at > $3`,
+		id, userID, now)
+	if err != nil {
+		return fmt.Errorf("consume refresh token of user %d: %w", userID, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeExpired deletes every refresh token that expired before cutoff and
+// returns how many were deleted.
+func (r *RefreshTokenRepository) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM refresh_tokens WHERE expires_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge expired refresh tokens: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
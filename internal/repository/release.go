@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// ReleaseRepository reads the completed issues release notes are built from.
+type ReleaseRepository struct {
+	db *queryDB
+}
+
+// NewReleaseRepository creates a new ReleaseRepository.
+func NewReleaseRepository(pool *pgxpool.Pool) *ReleaseRepository {
+	return &ReleaseRepository{db: instrument(pool, "release")}
+}
+
+// Completed returns a project's completed issues in scope with their label
+// names, in the order they were completed. Issues closed without being
+// completed are left out.
+func (r *ReleaseRepository) Completed(ctx context.Context, projectID int64, scope domain.ReleaseScope) ([]domain.ReleaseIssue, error) {
+	where := `i.closed_at >= $2 AND i.closed_at < $3`
+	args := []any{projectID, scope.From, scope.To}
+	if scope.MilestoneID != nil {
+		where = `i.milestone_id = $2`
+		args = []any{projectID, *scope.MilestoneID}
+	}
+
+	issues := []domain.ReleaseIssue{}
+	err := r.db.Select(ctx, &issues,
+		`SELECT i.id, p.key AS project_key, i.number, i.title, i.closed_at
+		 FROM issues i JOIN projects p ON p.id = i.project_id
+		 WHERE i.project_id = $1 AND i.status = 'completed' AND i.deleted_at IS NULL AND `+where+`
+		 ORDER BY i.closed_at, i.id`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("list completed issues of project %d: %w", projectID, err)
+	}
+	if len(issues) == 0 {
+		return issues, nil
+	}
+
+	ids := make([]int64, len(issues))
+	index := make(map[int64]int, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+		index[issue.ID] = i
+	}
+	var labels []struct {
+		IssueID int64  `db:"issue_id"`
+		Name    string `db:"name"`
+	}
+	err = r.db.Select(ctx, &labels,
+		`SELECT il.issue_id, l.name FROM issue_labels il JOIN labels l ON l.id = il.label_id
+		 WHERE il.issue_id = ANY($1) ORDER BY l.name`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("list labels of completed issues: %w", err)
+	}
+	for _, l := range labels {
+		i := index[l.IssueID]
+		issues[i].Labels = append(issues[i].Labels, l.Name)
+	}
+	return issues, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const routeColumns = `id, project_id, name, match, targets, active, created_by, created_at, updated_at`
+
+// RouteRepository handles notification routing rule data access operations.
+type RouteRepository struct {
+	db *queryDB
+}
+
+// NewRouteRepository creates a new RouteRepository.
+func NewRouteRepository(pool *pgxpool.Pool) *RouteRepository {
+	return &RouteRepository{db: instrument(pool, "route")}
+}
+
+// ListByProject returns a project's routing rules, oldest first.
+func (r *RouteRepository) ListByProject(ctx context.Context, projectID int64) ([]domain.NotificationRoute, error) {
+	routes := []domain.NotificationRoute{}
+	err := r.db.Select(ctx, &routes,
+		`SELECT `+routeColumns+` FROM notification_routes WHERE project_id = $1 ORDER BY id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list notification routes of project %d: %w", projectID, err)
+	}
+	return routes, nil
+}
+
+// ListActive returns a project's active routing rules, oldest first.
+func (r *RouteRepository) ListActive(ctx context.Context, projectID int64) ([]domain.NotificationRoute, error) {
+	routes := []domain.NotificationRoute{}
+	err := r.db.Select(ctx, &routes,
+		`SELECT `+routeColumns+` FROM notification_routes WHERE project_id = $1 AND active ORDER BY id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list active notification routes of project %d: %w", projectID, err)
+	}
+	return routes, nil
+}
+
+// FindByID retrieves a project's routing rule by its ID.
+func (r *RouteRepository) FindByID(ctx context.Context, projectID, id int64) (*domain.NotificationRoute, error) {
+	var route domain.NotificationRoute
+	err := r.db.Get(ctx, &route,
+		`SELECT `+routeColumns+` FROM notification_routes WHERE id = $1 AND project_id = $2`, id, projectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find notification route by id %d: %w", id, err)
+	}
+	return &route, nil
+}
+
+// Create inserts a routing rule and returns it.
+func (r *RouteRepository) Create(ctx context.Context, route domain.NotificationRoute) (*domain.NotificationRoute, error) {
+	var result domain.NotificationRoute
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO notification_routes (project_id, name, match, targets, active, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING `+routeColumns,
+		route.ProjectID, route.Name, route.Match, route.Targets, route.Active, route.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("create notification route in project %d: %w", route.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// Update saves a routing rule's name, conditions, targets and state.
+func (r *RouteRepository) Update(ctx context.Context, route domain.NotificationRoute) (*domain.NotificationRoute, error) {
+	var result domain.NotificationRoute
+	err := r.db.Get(ctx, &result,
+		`UPDATE notification_routes
+		 SET name = $3, match = $4, targets = $5, active = $6, updated_at = NOW()
+		 WHERE id = $1 AND project_id = $2
+		 RETURNING `+routeColumns,
+		route.ID, route.ProjectID, route.Name, route.Match, route.Targets, route.Active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("update notification route %d: %w", route.ID, err)
+	}
+	return &result, nil
+}
+
+// Delete removes a project's routing rule.
+func (r *RouteRepository) Delete(ctx context.Context, projectID, id int64) error {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM notification_routes WHERE id = $1 AND project_id = $2`, id, projectID)
+	if err != nil {
+		return fmt.Errorf("delete notification route %d: %w", id, err)
+	}
+	n := res.RowsAffected()
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const savedFilterColumns = `id, project_id, user_id, name, query, created_at, updated_at`
+
+// SavedFilterRepository handles saved filter data access operations.
+type SavedFilterRepository struct {
+	db *queryDB
+}
+
+// NewSavedFilterRepository creates a new SavedFilterRepository.
+func NewSavedFilterRepository(pool *pgxpool.Pool) *SavedFilterRepository {
+	return &SavedFilterRepository{db: instrument(pool, "saved_filter")}
+}
+
+// ListForUser returns a user's saved filters in a project ordered by name.
+func (r *SavedFilterRepository) ListForUser(ctx context.Context, projectID, userID int64) ([]domain.SavedFilter, error) {
+	filters := []domain.SavedFilter{}
+	err := r.db.Select(ctx, &filters,
+		`SELECT `+savedFilterColumns+` FROM saved_filters WHERE project_id = $1 AND user_id = $2 ORDER BY name`,
+		projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list saved filters of user %d in project %d: %w", userID, projectID, err)
+	}
+	return filters, nil
+}
+
+// FindByID retrieves a saved filter by its ID.
+func (r *SavedFilterRepository) FindByID(ctx context.Context, id int64) (*domain.SavedFilter, error) {
+	var filter domain.SavedFilter
+	err := r.db.Get(ctx, &filter,
+		`SELECT `+savedFilterColumns+` FROM saved_filters WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find saved filter by id %d: %w", id, err)
+	}
+	return &filter, nil
+}
+
+// Create inserts a saved filter and returns it. It returns
+// domain.ErrConflict if the user already has a filter with that name in
+// the project.
+func (r *SavedFilterRepository) Create(ctx context.Context, filter domain.SavedFilter) (*domain.SavedFilter, error) {
+	var result domain.SavedFilter
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO saved_filters (project_id, user_id, name, query) VALUES ($1, $2, $3, $4)
+		 RETURNING `+savedFilterColumns,
+		filter.ProjectID, filter.UserID, filter.Name, filter.Query)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: filter %q already exists", domain.ErrConflict, filter.Name)
+		}
+		return nil, fmt.Errorf("create saved filter in project %d: %w", filter.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// Update writes a saved filter's name and query and returns it. It returns
+// domain.ErrConflict if another of the user's filters has the name.
+func (r *SavedFilterRepository) Update(ctx context.Context, filter domain.SavedFilter) (*domain.SavedFilter, error) {
+	var result domain.SavedFilter
+	err := r.db.Get(ctx, &result,
+		`UPDATE saved_filters SET name = $2, query = $3, updated_at = NOW() WHERE id = $1
+		 RETURNING `+savedFilterColumns,
+		filter.ID, filter.Name, filter.Query)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: filter %q already exists", domain.ErrConflict, filter.Name)
+		}
+		return nil, fmt.Errorf("update saved filter %d: %w", filter.ID, err)
+	}
+	return &result, nil
+}
+
+// Delete removes a saved filter.
+func (r *SavedFilterRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM saved_filters WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete saved filter %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// SearchRepository is the default issue search index, backed by the issues
+// table's full-text search vector, and the queue of changes that feeds
+// external search engines.
+type SearchRepository struct {
+	db *queryDB
+}
+
+// NewSearchRepository creates a new SearchRepository.
+func NewSearchRepository(pool *pgxpool.Pool) *SearchRepository {
+	return &SearchRepository{db: instrument(pool, "search")}
+}
+
+// Search returns the IDs of issues in a project matching query, best match
+// first. query uses web search syntax: quoted phrases, OR and -exclusions.
+func (r *SearchRepository) Search(ctx context.Context, projectID int64, query string, offset, limit int) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(ctx, &ids,
+		`SELECT id FROM issues, websearch_to_tsquery('simple', $2) q
+		 WHERE project_id = $1 AND deleted_at IS NULL AND search_vector @@ q
+		 ORDER BY ts_rank(search_vector, q) DESC, id DESC
+		 OFFSET $3 LIMIT $4`,
+		projectID, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search issues in project %d: %w", projectID, err)
+	}
+	return ids, nil
+}
+
+// SearchAccessible returns issues in the projects the user can access whose
+// title, body or visible comments match query, best match first. A match in
+// the issue itself outranks an equally good match in a comment. query uses
+// the same syntax as Search.
+func (r *SearchRepository) SearchAccessible(ctx context.Context, userID int64, query string, offset, limit int) ([]domain.SearchHit, error) {
+	hits := []domain.SearchHit{}
+	err := r.db.Select(ctx, &hits,
+		`WITH q AS (
+		     SELECT websearch_to_tsquery('simple', $2) AS q
+		 ), `+accessibleProjectsCTE+`, matches AS (
+		     SELECT i.id AS issue_id, ts_rank(i.search_vector, q.q) AS rank
+		     FROM issues i, q
+		     WHERE i.project_id IN (SELECT id FROM accessible) AND i.deleted_at IS NULL AND i.search_vector @@ q.q
+		     UNION ALL
+		     SELECT c.issue_id, ts_rank(c.search_vector, q.q) * 0.5
+		     FROM comments c JOIN issues i ON i.id = c.issue_id, q
+		     WHERE i.project_id IN (SELECT id FROM accessible) AND i.deleted_at IS NULL
+		       AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+		       AND c.search_vector @@ q.q
+		 ), ranked AS (
+		     SELECT issue_id, MAX(rank) AS rank FROM matches GROUP BY issue_id
+		 )
+		 SELECT i.id AS issue_id, i.project_id, i.number, i.title, i.status, r.rank,
+		        ts_headline('simple', i.title || ' ' || COALESCE(i.body, ''), q.q,
+		                    'MaxFragments=1, MaxWords=30, MinWords=10') AS snippet
+		 FROM ranked r JOIN issues i ON i.id = r.issue_id, q
+		 ORDER BY r.rank DESC, i.id DESC
+		 OFFSET $3 LIMIT $4`,
+		userID, query, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search issues for user %d: %w", userID, err)
+	}
+	return hits, nil
+}
+
+// Apply does nothing: the search vector is a generated column and always
+// reflects the stored issue.
+func (r *SearchRepository) Apply(ctx context.Context, issues []domain.Issue, removed []int64) error {
+	return nil
+}
+
+// PendingChanges returns up to limit queued search changes, oldest first.
+func (r *SearchRepository) PendingChanges(ctx context.Context, limit int) ([]domain.SearchChange, error) {
+	changes := []domain.SearchChange{}
+	err := r.db.Select(ctx, &changes,
+		`SELECT id, issue_id FROM search_index_queue ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list pending search changes: %w", err)
+	}
+	return changes, nil
+}
+
+// AckChanges removes queued search changes up to and including upToID.
+func (r *SearchRepository) AckChanges(ctx context.Context, upToID int64) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM search_index_queue WHERE id <= $1`, upToID); err != nil {
+		return fmt.Errorf("ack search changes up to %d: %w", upToID, err)
+	}
+	return nil
+}
+
+// FindIssues returns the issues with the given IDs that still exist outside
+// the trash, in no particular order.
+func (r *SearchRepository) FindIssues(ctx context.Context, ids []int64) ([]domain.Issue, error) {
+	issues := []domain.Issue{}
+	err := r.db.Select(ctx, &issues,
+		`SELECT `+issueColumns+` FROM issues WHERE id = ANY($1) AND deleted_at IS NULL`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find issues for search: %w", err)
+	}
+	return issues, nil
+}
+
+// Suggest returns up to limit issues in a project whose title starts with
+// or resembles prefix: prefix matches first, then by trigram similarity.
+// If number is set, the issue with that number comes first.
+func (r *SearchRepository) Suggest(ctx context.Context, projectID int64, prefix string, number *int64, limit int) ([]domain.IssueSuggestion, error) {
+	suggestions := []domain.IssueSuggestion{}
+	err := r.db.Select(ctx, &suggestions,
+		`SELECT id, number, title, status FROM issues
+		 WHERE project_id = $1 AND deleted_at IS NULL
+		   AND (number = $3 OR title ILIKE $2 || '%' OR title % $4)
+		 ORDER BY number = $3 DESC NULLS LAST, title ILIKE $2 || '%' DESC, similarity(title, $4) DESC, id DESC
+		 LIMIT $5`,
+		projectID, escapeLike(prefix), number, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("suggest issues in project %d: %w", projectID, err)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// SlackRepository handles Slack account link data access operations.
+type SlackRepository struct {
+	db *queryDB
+}
+
+// NewSlackRepository creates a new SlackRepository.
+func NewSlackRepository(pool *pgxpool.Pool) *SlackRepository {
+	return &SlackRepository{db: instrument(pool, "slack")}
+}
+
+// FindUser returns the account a Slack user is linked to. It returns
+// domain.ErrNotFound if the Slack user has not linked an account.
+func (r *SlackRepository) FindUser(ctx context.Context, teamID, slackUserID string) (int64, error) {
+	var userID int64
+	err := r.db.Get(ctx, &userID,
+		`SELECT user_id FROM slack_accounts WHERE team_id = $1 AND slack_user_id = $2`,
+		teamID, slackUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, fmt.Errorf("find account for slack user %s/%s: %w", teamID, slackUserID, err)
+	}
+	return userID, nil
+}
+
+// Link links a Slack user to an account, replacing any earlier link of that
+// Slack user.
+func (r *SlackRepository) Link(ctx context.Context, teamID, slackUserID string, userID int64) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO slack_accounts (team_id, slack_user_id, user_id) VALUES ($1, $2, $3)
+		 ON CONFLICT (team_id, slack_user_id) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = NOW()`,
+		teamID, slackUserID, userID)
+	if err != nil {
+		return fmt.Errorf("link slack user %s/%s: %w", teamID, slackUserID, err)
+	}
+	return nil
+}
+
+// ProjectIDByKey returns the ID of the project with the given key. It
+// returns domain.ErrNotFound if no project has the key.
+func (r *SlackRepository) ProjectIDByKey(ctx context.Context, key string) (int64, error) {
+	var id int64
+	err := r.db.Get(ctx, &id, `SELECT id FROM projects WHERE key = $1 AND deleted_at IS NULL`, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, fmt.Errorf("find project by key %q: %w", key, err)
+	}
+	return id, nil
+}
+
+// IssueByKey returns the IDs of the issue a key such as KEY-123 names and
+// of its project. It returns domain.ErrNotFound if there is no such issue.
+func (r *SlackRepository) IssueByKey(ctx context.Context, key domain.IssueKey) (projectID, issueID int64, err error) {
+	row := r.db.QueryRow(ctx,
+		`SELECT i.project_id, i.id
+		 FROM issues i JOIN projects p ON p.id = i.project_id
+		 WHERE p.key = $1 AND i.number = $2 AND p.deleted_at IS NULL AND i.deleted_at IS NULL`,
+		key.ProjectKey, key.Number)
+	if err := row.Scan(&projectID, &issueID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, 0, domain.ErrNotFound
+		}
+		return 0, 0, fmt.Errorf("find issue %s-%d: %w", key.ProjectKey, key.Number, err)
+	}
+	return projectID, issueID, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes LIKE wildcards so s matches literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// settledClause matches rows of a table with an xact_id column whose
+// inserting transaction ended before the oldest one still running began.
+// Any row committed later belongs to a newer transaction, so a reader
+// following (xact_id, id) never passes a row that has yet to commit.
+const settledClause = `xact_id < pg_snapshot_xmin(pg_current_snapshot())`
+
+// unmodifiedSinceClause matches rows whose updated_at is not after the
+// timestamp bound to placeholder n, or every row if that argument is NULL.
+func unmodifiedSinceClause(n int) string {
+	return fmt.Sprintf("($%[1]d::timestamptz IS NULL OR date_trunc('second', updated_at) <= $%[1]d)", n)
+}
+
+// missingOrStale explains why a conditional write on table matched no rows:
+// domain.ErrNotFound if the row is gone or in the trash,
+// domain.ErrPreconditionFailed if it exists but failed the precondition.
+// table must be a trusted identifier with a deleted_at column.
+func missingOrStale(ctx context.Context, db pgxscan.Querier, table string, id int64) error {
+	var exists bool
+	if err := pgxscan.Get(ctx, db, &exists,
+		`SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, id); err != nil {
+		return fmt.Errorf("check %s %d: %w", table, id, err)
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+	return domain.ErrPreconditionFailed
+}
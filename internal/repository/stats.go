@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// StatsRepository runs aggregate queries for project statistics.
+type StatsRepository struct {
+	db *queryDB
+}
+
+// NewStatsRepository creates a new StatsRepository.
+func NewStatsRepository(pool *pgxpool.Pool) *StatsRepository {
+	return &StatsRepository{db: instrument(pool, "stats")}
+}
+
+// Contributors returns activity counts for every owner and member of a
+// project within the window, most active first.
+func (r *StatsRepository) Contributors(ctx context.Context, projectID int64, window domain.TimeWindow) ([]domain.ContributorStats, error) {
+	stats := []domain.ContributorStats{}
+	err := r.db.Select(ctx, &stats,
+		`WITH members AS (
+		     SELECT owner_id AS user_id FROM projects WHERE id = $1
+		     UNION
+		     SELECT user_id FROM project_members WHERE project_id = $1
+		 ),
+		 created AS (
+		     SELECT created_by AS user_id, COUNT(*) AS n FROM issues
+		     WHERE project_id = $1 AND deleted_at IS NULL AND created_at >= $2 AND created_at < $3
+		     GROUP BY created_by
+		 ),
+		 closed AS (
+		     SELECT closed_by AS user_id, COUNT(*) AS n FROM issues
+		     WHERE project_id = $1 AND deleted_at IS NULL AND closed_at >= $2 AND closed_at < $3
+		     GROUP BY closed_by
+		 ),
+		 commented AS (
+		     SELECT c.author_id AS user_id, COUNT(*) AS n
+		     FROM comments c JOIN issues i ON i.id = c.issue_id
+		     WHERE i.project_id = $1 AND i.deleted_at IS NULL AND c.created_at >= $2 AND c.created_at < $3
+		     GROUP BY c.author_id
+		 ),
+		 triggered AS (
+		     SELECT j.triggered_by AS user_id, COUNT(*) AS n
+		     FROM ai_jobs j JOIN issues i ON i.id = j.issue_id
+		     WHERE i.project_id = $1 AND i.deleted_at IS NULL AND j.created_at >= $2 AND j.created_at < $3
+		     GROUP BY j.triggered_by
+		 )
+		 SELECT u.id AS user_id, u.display_name,
+		        COALESCE(created.n, 0) AS issues_created,
+		        COALESCE(closed.n, 0) AS issues_closed,
+		        COALESCE(commented.n, 0) AS comments,
+		        COALESCE(triggered.n, 0) AS ai_jobs_triggered
+		 FROM members m
+		 JOIN users u ON u.id = m.user_id
+		 LEFT JOIN created ON created.user_id = u.id
+		 LEFT JOIN closed ON closed.user_id = u.id
+		 LEFT JOIN commented ON commented.user_id = u.id
+		 LEFT JOIN triggered ON triggered.user_id = u.id
+		 ORDER BY COALESCE(created.n, 0) + COALESCE(closed.n, 0)
+		        + COALESCE(commented.n, 0) + COALESCE(triggered.n, 0) DESC, u.id`,
+		projectID, window.Since, window.Until)
+	if err != nil {
+		return nil, fmt.Errorf("contributor stats for project %d: %w", projectID, err)
+	}
+	return stats, nil
+}
+
+// issueGroupKeys maps each grouping to the SQL expression for its key.
+var issueGroupKeys = map[domain.IssueGrouping]string{
+	domain.IssueGroupingStatus:   `i.status::text`,
+	domain.IssueGroupingLabel:    `l.name`,
+	domain.IssueGroupingAssignee: `i.assignee_id::text`,
+}
+
+// IssueCounts counts the issues created in a project within the window per
+// UTC time bucket and group key, ordered by bucket then key. Archived issues
+// are included.
+func (r *StatsRepository) IssueCounts(ctx context.Context, projectID int64, window domain.TimeWindow, groupBy domain.IssueGrouping, interval domain.StatsInterval) ([]domain.IssueCount, error) {
+	key, ok := issueGroupKeys[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown grouping %q", domain.ErrInvalidInput, groupBy)
+	}
+
+	var join string
+	if groupBy == domain.IssueGroupingLabel {
+		join = `LEFT JOIN issue_labels il ON il.issue_id = i.id
+		 LEFT JOIN labels l ON l.id = il.label_id`
+	}
+
+	counts := []domain.IssueCount{}
+	err := r.db.Select(ctx, &counts,
+		`SELECT date_trunc($4, i.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+		        `+key+` AS key, COUNT(*) AS count
+		 FROM issues i
+		 `+join+`
+		 WHERE i.project_id = $1 AND i.deleted_at IS NULL AND i.created_at >= $2 AND i.created_at < $3
+		 GROUP BY 1, 2
+		 ORDER BY 1, 2 NULLS LAST`,
+		projectID, window.Since, window.Until, string(interval))
+	if err != nil {
+		return nil, fmt.Errorf("issue counts for project %d: %w", projectID, err)
+	}
+	return counts, nil
+}
+
+// Flow returns the status breakdown of a project's issues at the end of each
+// UTC day in the window, replayed from status change events. An issue's
+// status on a day is the target of its last change before the day ended or,
+// if it had not changed yet, the origin of its next change or else its
+// current status. Issues count from the day they were created. A non-nil
+// milestoneID limits the replay to the issues now in that milestone.
+func (r *StatsRepository) Flow(ctx context.Context, projectID int64, milestoneID *int64, window domain.TimeWindow) ([]domain.FlowPoint, error) {
+	points := []domain.FlowPoint{}
+	err := r.db.Select(ctx, &points,
+		`WITH days AS (
+		     SELECT day, day + INTERVAL '1 day' AS day_end
+		     FROM generate_series(
+		         date_trunc('day', $2::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		         $3::timestamptz - INTERVAL '1 microsecond',
+		         INTERVAL '1 day') AS day
+		 ),
+		 statuses AS (
+		     SELECT d.day, COALESCE(prev.status, next.status, i.status::text) AS status
+		     FROM days d
+		     JOIN issues i ON i.project_id = $1 AND i.deleted_at IS NULL AND i.created_at < d.day_end
+		          AND ($5::bigint IS NULL OR i.milestone_id = $5)
+		     LEFT JOIN LATERAL (
+		         SELECT e.data->>'to' AS status FROM issue_events e
+		         WHERE e.issue_id = i.id AND e.type = $4 AND e.created_at < d.day_end
+		         ORDER BY e.created_at DESC, e.id DESC LIMIT 1
+		     ) prev ON true
+		     LEFT JOIN LATERAL (
+		         SELECT e.data->>'from' AS status FROM issue_events e
+		         WHERE e.issue_id = i.id AND e.type = $4 AND e.created_at >= d.day_end
+		         ORDER BY e.created_at, e.id LIMIT 1
+		     ) next ON prev.status IS NULL
+		 )
+		 SELECT d.day,
+		        COUNT(s.status) FILTER (WHERE s.status = 'open') AS open,
+		        COUNT(s.status) FILTER (WHERE s.status = 'in_progress') AS in_progress,
+		        COUNT(s.status) FILTER (WHERE s.status = 'completed') AS completed,
+		        COUNT(s.status) FILTER (WHERE s.status = 'closed') AS closed,
+		        COUNT(s.status) FILTER (WHERE s.status IN ('open', 'in_progress')) AS remaining,
+		        COUNT(s.status) FILTER (WHERE s.status IN ('completed', 'closed')) AS done,
+		        COUNT(s.status) AS scope
+		 FROM days d
+		 LEFT JOIN statuses s ON s.day = d.day
+		 GROUP BY d.day
+		 ORDER BY d.day`,
+		projectID, window.Since, window.Until, domain.EventStatusChanged, milestoneID)
+	if err != nil {
+		return nil, fmt.Errorf("flow for project %d: %w", projectID, err)
+	}
+	return points, nil
+}
+
+// CycleTimes computes lead and cycle time percentiles, in seconds, for the
+// issues in a project whose last move to completed falls within the window.
+func (r *StatsRepository) CycleTimes(ctx context.Context, projectID int64, window domain.TimeWindow) (*domain.CycleTimeStats, error) {
+	var row struct {
+		LeadCount  int      `db:"lead_count"`
+		LeadP50    *float64 `db:"lead_p50"`
+		LeadP75    *float64 `db:"lead_p75"`
+		LeadP90    *float64 `db:"lead_p90"`
+		LeadP95    *float64 `db:"lead_p95"`
+		CycleCount int      `db:"cycle_count"`
+		CycleP50   *float64 `db:"cycle_p50"`
+		CycleP75   *float64 `db:"cycle_p75"`
+		CycleP90   *float64 `db:"cycle_p90"`
+		CycleP95   *float64 `db:"cycle_p95"`
+	}
+	err := r.db.Get(ctx, &row,
+		`WITH completed AS (
+		     SELECT DISTINCT ON (e.issue_id) e.issue_id, e.created_at AS completed_at
+		     FROM issue_events e
+		     WHERE e.project_id = $1 AND e.type = $4 AND e.data->>'to' = 'completed'
+		       AND e.created_at >= $2 AND e.created_at < $3
+		     ORDER BY e.issue_id, e.created_at DESC
+		 ),
+		 durations AS (
+		     SELECT EXTRACT(EPOCH FROM c.completed_at - i.created_at) AS lead,
+		            EXTRACT(EPOCH FROM c.completed_at - started.at) AS cycle
+		     FROM completed c
+		     JOIN issues i ON i.id = c.issue_id AND i.deleted_at IS NULL
+		     LEFT JOIN LATERAL (
+		         SELECT MIN(e.created_at) AS at FROM issue_events e
+		         WHERE e.issue_id = c.issue_id AND e.type = $4
+		           AND e.data->>'to' = 'in_progress' AND e.created_at <= c.completed_at
+		     ) started ON true
+		 )
+		 SELECT COUNT(lead) AS lead_count,
+		        percentile_cont(0.50) WITHIN GROUP (ORDER BY lead) AS lead_p50,
+		        percentile_cont(0.75) WITHIN GROUP (ORDER BY lead) AS lead_p75,
+		        percentile_cont(0.90) WITHIN GROUP (ORDER BY lead) AS lead_p90,
+		        percentile_cont(0.95) WITHIN GROUP (ORDER BY lead) AS lead_p95,
+		        COUNT(cycle) AS cycle_count,
+		        percentile_cont(0.50) WITHIN GROUP (ORDER BY cycle) AS cycle_p50,
+		        percentile_cont(0.75) WITHIN GROUP (ORDER BY cycle) AS cycle_p75,
+		        percentile_cont(0.90) WITHIN GROUP (ORDER BY cycle) AS cycle_p90,
+		        percentile_cont(0.95) WITHIN GROUP (ORDER BY cycle) AS cycle_p95
+		 FROM durations`,
+		projectID, window.Since, window.Until, domain.EventStatusChanged)
+	if err != nil {
+		return nil, fmt.Errorf("cycle times for project %d: %w", projectID, err)
+	}
+
+	return &domain.CycleTimeStats{
+		LeadTime: domain.DurationPercentiles{
+			Count: row.LeadCount, P50: row.LeadP50, P75: row.LeadP75, P90: row.LeadP90, P95: row.LeadP95,
+		},
+		CycleTime: domain.DurationPercentiles{
+			Count: row.CycleCount, P50: row.CycleP50, P75: row.CycleP75, P90: row.CycleP90, P95: row.CycleP95,
+		},
+	}, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const templateColumns = `id, owner_id, name, description, definition, created_at`
+
+// TemplateRepository handles project template data access operations.
+type TemplateRepository struct {
+	db *queryDB
+}
+
+// NewTemplateRepository creates a new TemplateRepository.
+func NewTemplateRepository(pool *pgxpool.Pool) *TemplateRepository {
+	return &TemplateRepository{db: instrument(pool, "template")}
+}
+
+// ListForUser returns the built-in templates followed by the user's own.
+func (r *TemplateRepository) ListForUser(ctx context.Context, userID int64) ([]domain.ProjectTemplate, error) {
+	templates := []domain.ProjectTemplate{}
+	err := r.db.Select(ctx, &templates,
+		`SELECT `+templateColumns+` FROM project_templates
+		 WHERE owner_id IS NULL OR owner_id = $1
+		 ORDER BY owner_id NULLS FIRST, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list templates for user %d: %w", userID, err)
+	}
+	return templates, nil
+}
+
+// FindByID retrieves a template by its ID.
+func (r *TemplateRepository) FindByID(ctx context.Context, id int64) (*domain.ProjectTemplate, error) {
+	var template domain.ProjectTemplate
+	err := r.db.Get(ctx, &template,
+		`SELECT `+templateColumns+` FROM project_templates WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find template by id %d: %w", id, err)
+	}
+	return &template, nil
+}
+
+// Create inserts a user-owned template and returns it.
+func (r *TemplateRepository) Create(ctx context.Context, template domain.ProjectTemplate) (*domain.ProjectTemplate, error) {
+	var result domain.ProjectTemplate
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO project_templates (owner_id, name, description, definition)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+templateColumns,
+		template.OwnerID, template.Name, template.Description, template.Definition,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create template: %w", err)
+	}
+	return &result, nil
+}
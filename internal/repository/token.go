@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const accessTokenColumns = `id, user_id, name, scope, expires_at, last_used_at, created_at`
+
+// AccessTokenRepository handles personal access token data access operations.
+type AccessTokenRepository struct {
+	db *queryDB
+}
+
+// NewAccessTokenRepository creates a new AccessTokenRepository.
+func NewAccessTokenRepository(pool *pgxpool.Pool) *AccessTokenRepository {
+	return &AccessTokenRepository{db: instrument(pool, "access_token")}
+}
+
+// ListByUser returns a user's tokens, newest first.
+func (r *AccessTokenRepository) ListByUser(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	tokens := []domain.AccessToken{}
+	err := r.db.Select(ctx, &tokens,
+		`SELECT `+accessTokenColumns+` FROM personal_access_tokens
+		 WHERE user_id = $1
+		 ORDER BY id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list access tokens of user %d: %w", userID, err)
+	}
+	return tokens, nil
+}
+
+// Create stores a token by the hash of its secret and returns it.
+func (r *AccessTokenRepository) Create(ctx context.Context, token domain.AccessToken, hash []byte) (*domain.AccessToken, error) {
+	var result domain.AccessToken
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO personal_access_tokens (user_id, name, scope, token_hash, expires_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+accessTokenColumns,
+		token.UserID, token.Name, token.Scope, hash, token.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("create access token for user %d: %w", token.UserID, err)
+	}
+	return &result, nil
+}
+
+// Delete revokes one of a user's tokens.
+func (r *AccessTokenRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete access token %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeExpired deletes every token that expired before cutoff and returns
+// how many were deleted.
+func (r *AccessTokenRepository) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`DELETE FROM personal_access_tokens WHERE expires_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge expired access tokens: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// Authenticate returns the unexpired token with hash and records that it
+// was used at now, at most once a minute to spare a write on every request.
+// It returns domain.ErrNotFound for unknown and expired tokens.
+func (r *AccessTokenRepository) Authenticate(ctx context.Context, hash []byte, now time.Time) (*domain.AccessToken, error) {
+	var token domain.AccessToken
+	err := r.db.Get(ctx, &token,
+		`WITH token AS (
+		     SELECT `+accessTokenColumns+` FROM personal_access_tokens
+		     WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > $2)
+		 ), touched AS (
+		     UPDATE personal_access_tokens p SET last_used_at = $2
+		     FROM token t
+		     WHERE p.id = t.id AND (t.last_used_at IS NULL OR t.last_used_at < $2 - INTERVAL '1 minute')
+		 )
+		 SELECT `+accessTokenColumns+` FROM token`, hash, now)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("authenticate access token: %w", err)
+	}
+	return &token, nil
+}
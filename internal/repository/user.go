@@ -2,33 +2,33 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/sumire/issues/internal/domain"
 )
 
 // UserRepository handles user data access operations.
 type UserRepository struct {
-	db *sqlx.DB
+	db *queryDB
 }
 
 // NewUserRepository creates a new UserRepository.
-func NewUserRepository(db *sqlx.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
+	return &UserRepository{db: instrument(pool, "user")}
 }
 
 // FindByID retrieves a user by their ID.
 func (r *UserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
 	var user domain.User
-	err := r.db.GetContext(ctx, &user,
-		`SELECT id, provider, provider_id, email, display_name, avatar_url, created_at, updated_at
+	err := r.db.Get(ctx, &user,
+		`SELECT id, provider, provider_id, email, display_name, avatar_url, is_admin, created_at, updated_at
 		 FROM users WHERE id = $1`, id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
 		return nil, fmt.Errorf("find user by id %d: %w", id, err)
@@ -39,11 +39,11 @@ func (r *UserRepository) FindByID(ctx context.Context, id int64) (*domain.User,
 // FindByProviderID retrieves a user by their OAuth provider and provider ID.
 func (r *UserRepository) FindByProviderID(ctx context.Context, provider domain.AuthProvider, providerID string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.GetContext(ctx, &user,
-		`SELECT id, provider, provider_id, email, display_name, avatar_url, created_at, updated_at
+	err := r.db.Get(ctx, &user,
+		`SELECT id, provider, provider_id, email, display_name, avatar_url, is_admin, created_at, updated_at
 		 FROM users WHERE provider = $1 AND provider_id = $2`, provider, providerID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
 		return nil, fmt.Errorf("find user by provider %s/%s: %w", provider, providerID, err)
@@ -55,7 +55,7 @@ func (r *UserRepository) FindByProviderID(ctx context.Context, provider domain.A
 // Returns the created or updated user.
 func (r *UserRepository) Upsert(ctx context.Context, user domain.User) (*domain.User, error) {
 	var result domain.User
-	err := r.db.QueryRowxContext(ctx,
+	err := r.db.Get(ctx, &result,
 		`INSERT INTO users (provider, provider_id, email, display_name, avatar_url)
 		 VALUES ($1, $2, $3, $4, $5)
 		 ON CONFLICT (provider, provider_id)
@@ -63,11 +63,24 @@ func (r *UserRepository) Upsert(ctx context.Context, user domain.User) (*domain.
 		               display_name = EXCLUDED.display_name,
 		               avatar_url = EXCLUDED.avatar_url,
 		               updated_at = NOW()
-		 RETURNING id, provider, provider_id, email, display_name, avatar_url, created_at, updated_at`,
+		 RETURNING id, provider, provider_id, email, display_name, avatar_url, is_admin, created_at, updated_at`,
 		user.Provider, user.ProviderID, user.Email, user.DisplayName, user.AvatarURL,
-	).StructScan(&result)
+	)
 	if err != nil {
 		return nil, fmt.Errorf("upsert user: %w", err)
 	}
 	return &result, nil
 }
+
+// FindByEmails returns the users whose email matches one of emails, ignoring
+// case. Several accounts may share an email.
+func (r *UserRepository) FindByEmails(ctx context.Context, emails []string) ([]domain.User, error) {
+	users := []domain.User{}
+	err := r.db.Select(ctx, &users,
+		`SELECT id, provider, provider_id, email, display_name, avatar_url, is_admin, created_at, updated_at
+		 FROM users WHERE LOWER(email) = ANY($1) AND provider <> 'system'`, emails)
+	if err != nil {
+		return nil, fmt.Errorf("find users by email: %w", err)
+	}
+	return users, nil
+}
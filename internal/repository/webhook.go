@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	webhookColumns = `id, project_id, url, secret, format, template, content_type, active, retry_policy,
+	failing_since, disabled_at, created_by, created_at, updated_at`
+	webhookDeliveryColumns = `id, event_id, event_type, project_id, webhook_id, url, body, content_type,
+	status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at`
+)
+
+// WebhookRepository handles webhook and webhook delivery data access
+// operations.
+type WebhookRepository struct {
+	db *queryDB
+}
+
+// NewWebhookRepository creates a new WebhookRepository.
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: instrument(pool, "webhook")}
+}
+
+// ListByProject returns a project's webhooks, oldest first.
+func (r *WebhookRepository) ListByProject(ctx context.Context, projectID int64) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+	err := r.db.Select(ctx, &webhooks,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE project_id = $1 ORDER BY id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks for project %d: %w", projectID, err)
+	}
+	return webhooks, nil
+}
+
+// ListActive returns the active webhooks of the given projects.
+func (r *WebhookRepository) ListActive(ctx context.Context, projectIDs []int64) ([]domain.Webhook, error) {
+	webhooks := []domain.Webhook{}
+	err := r.db.Select(ctx, &webhooks,
+		`SELECT `+webhookColumns+` FROM webhooks
+		 WHERE project_id = ANY($1::bigint[]) AND active ORDER BY id`, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list active webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// FindByID retrieves a webhook by its ID.
+func (r *WebhookRepository) FindByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := r.db.Get(ctx, &webhook,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find webhook by id %d: %w", id, err)
+	}
+	return &webhook, nil
+}
+
+// Create inserts a webhook and returns it.
+func (r *WebhookRepository) Create(ctx context.Context, webhook domain.Webhook) (*domain.Webhook, error) {
+	var result domain.Webhook
+	err := r.db.Get(ctx, &result,
+		`INSERT INTO webhooks (project_id, url, secret, format, template, content_type, active, retry_policy, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING `+webhookColumns,
+		webhook.ProjectID, webhook.URL, webhook.Secret, webhook.Format, webhook.Template, webhook.ContentType,
+		webhook.Active, webhook.Retry, webhook.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("create webhook in project %d: %w", webhook.ProjectID, err)
+	}
+	return &result, nil
+}
+
+// Update writes a webhook's endpoint, format, template, retry policy and
+// state and returns it. Reactivating a webhook clears its failure record.
+func (r *WebhookRepository) Update(ctx context.Context, webhook domain.Webhook) (*domain.Webhook, error) {
+	var result domain.Webhook
+	err := r.db.Get(ctx, &result,
+		`UPDATE webhooks
+		 SET url = $2, format = $3, template = $4, content_type = $5, active = $6, retry_policy = $7,
+		     failing_since = CASE WHEN $6 AND NOT active THEN NULL ELSE failing_since END,
+		     disabled_at = CASE WHEN $6 THEN NULL ELSE disabled_at END,
+		     updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+webhookColumns,
+		webhook.ID, webhook.URL, webhook.Format, webhook.Template, webhook.ContentType, webhook.Active, webhook.Retry)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("update webhook %d: %w", webhook.ID, err)
+	}
+	return &result, nil
+}
+
+// Delete removes a webhook along with its deliveries.
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue records pending deliveries. A delivery for an event that the
+// same webhook already has one for is skipped, so enqueueing an event twice
+// sends it once. The partitioned table cannot enforce this with a unique
+// index, so callers must not enqueue concurrently.
+func (r *WebhookRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	eventIDs := make([]int64, len(deliveries))
+	types := make([]string, len(deliveries))
+	projectIDs := make([]int64, len(deliveries))
+	webhookIDs := make([]*int64, len(deliveries))
+	urls := make([]string, len(deliveries))
+	bodies := make([]string, len(deliveries))
+	contentTypes := make([]string, len(deliveries))
+	for i, d := range deliveries {
+		eventIDs[i] = d.EventID
+		types[i] = string(d.EventType)
+		projectIDs[i] = d.ProjectID
+		webhookIDs[i] = d.WebhookID
+		urls[i] = d.URL
+		bodies[i] = d.Body
+		contentTypes[i] = d.ContentType
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO webhook_deliveries (event_id, event_type, project_id, webhook_id, url, body, content_type)
+		 SELECT d.event_id, d.event_type, d.project_id, d.webhook_id, d.url, d.body, d.content_type
+		 FROM unnest($1::bigint[], $2::text[], $3::bigint[], $4::bigint[], $5::text[], $6::text[], $7::text[])
+		      AS d(event_id, event_type, project_id, webhook_id, url, body, content_type)
+		 JOIN projects p ON p.id = d.project_id
+		 WHERE NOT EXISTS (
+		     SELECT 1 FROM webhook_deliveries x
+		     WHERE x.event_id = d.event_id AND x.webhook_id IS NOT DISTINCT FROM d.webhook_id)`,
+		eventIDs, types, projectIDs, webhookIDs, urls, bodies, contentTypes)
+	if err != nil {
+		return fmt.Errorf("enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Due returns up to limit pending deliveries whose next attempt is due,
+// longest waiting first, with the signing secret and retry policy of their
+// webhook. It returns deliveries that have failed before if retries is set,
+// and deliveries never attempted otherwise.
+func (r *WebhookRepository) Due(ctx context.Context, limit int, retries bool) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+	err := r.db.Select(ctx, &deliveries,
+		`SELECT d.id, d.event_id, d.event_type, d.project_id, d.webhook_id, d.url, d.body,
+		        d.content_type, d.status, d.attempts, d.next_attempt_at, d.last_status_code,
+		        d.last_error, d.delivered_at, d.created_at, d.updated_at,
+		        COALESCE(w.secret, '') AS secret, COALESCE(w.retry_policy, '{}') AS retry_policy
+		 FROM webhook_deliveries d
+		 LEFT JOIN webhooks w ON w.id = d.webhook_id
+		 WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND (d.attempts > 0) = $2
+		 ORDER BY d.next_attempt_at
+		 LIMIT $1`, limit, retries)
+	if err != nil {
+		return nil, fmt.Errorf("list due webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt saves the outcome of an attempt to send a delivery.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id int64, a domain.WebhookAttempt) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4,
+		     next_attempt_at = $5,
+		     delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END,
+		     updated_at = NOW()
+		 WHERE id = $1`,
+		id, a.Status, a.StatusCode, a.Error, a.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("record webhook delivery %d attempt: %w", id, err)
+	}
+	return nil
+}
+
+// RecordOutcome tracks whether a webhook's endpoint is failing: a delivered
+// attempt ends a run of failures and a failed one starts one if none is
+// under way. An active webhook whose failures began at least disableAfter
+// ago is deactivated, and returned; otherwise RecordOutcome returns nil.
+func (r *WebhookRepository) RecordOutcome(ctx context.Context, id int64, delivered bool, disableAfter time.Duration) (*domain.Webhook, error) {
+	// NOW() is fixed for the statement, so disabled_at = NOW() in RETURNING
+	// tells whether this update is the one that deactivated the webhook.
+	var result struct {
+		domain.Webhook
+		Disabled bool `db:"disabled"`
+	}
+	err := r.db.Get(ctx, &result,
+		`UPDATE webhooks
+		 SET failing_since = CASE WHEN $2 THEN NULL ELSE COALESCE(failing_since, NOW()) END,
+		     active = active AND ($2 OR failing_since IS NULL OR failing_since > NOW() - make_interval(secs => $3)),
+		     disabled_at = CASE
+		         WHEN active AND NOT $2 AND failing_since <= NOW() - make_interval(secs => $3) THEN NOW()
+		         ELSE disabled_at
+		     END
+		 WHERE id = $1 AND NOT ($2 AND failing_since IS NULL)
+		 RETURNING `+webhookColumns+`, disabled_at IS NOT DISTINCT FROM NOW() AS disabled`,
+		id, delivered, disableAfter.Seconds())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("record webhook %d outcome: %w", id, err)
+	}
+	if !result.Disabled {
+		return nil, nil
+	}
+	return &result.Webhook, nil
+}
+
+// List returns deliveries matching the filter, newest first. It fetches one
+// row beyond filter.Limit so callers can detect a next page.
+func (r *WebhookRepository) List(ctx context.Context, f domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	conds := []string{"TRUE"}
+	var args []any
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		add("status = ANY($%d::webhook_delivery_status[])", statuses)
+	}
+	if f.ProjectID != nil {
+		add("project_id = $%d", *f.ProjectID)
+	}
+	if f.WebhookID != nil {
+		add("webhook_id = $%d", *f.WebhookID)
+	}
+	if f.EventType != "" {
+		add("event_type = $%d", string(f.EventType))
+	}
+	if !f.Cursor.IsZero() {
+		args = append(args, f.Cursor.At, f.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d::timestamptz, $%d::bigint)", len(args)-1, len(args)))
+	}
+	args = append(args, f.Limit+1)
+
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries
+		 WHERE %s ORDER BY created_at DESC, id DESC LIMIT $%d`,
+		webhookDeliveryColumns, strings.Join(conds, " AND "), len(args))
+
+	deliveries := []domain.WebhookDelivery{}
+	if err := r.db.Select(ctx, &deliveries, query, args...); err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	wikiSummaryColumns  = `w.id, w.project_id, w.slug, w.title, w.revision, w.updated_by, w.created_at, w.updated_at`
+	wikiPageColumns     = wikiSummaryColumns + `, w.body, w.created_by`
+	wikiRevisionColumns = `page_id, revision, title, message, author_id, created_at`
+)
+
+// WikiRepository handles wiki page data access operations.
+type WikiRepository struct {
+	db *queryDB
+}
+
+// NewWikiRepository creates a new WikiRepository.
+func NewWikiRepository(pool *pgxpool.Pool) *WikiRepository {
+	return &WikiRepository{db: instrument(pool, "wiki")}
+}
+
+// ListByProject returns the pages of a project's wiki ordered by title.
+func (r *WikiRepository) ListByProject(ctx context.Context, projectID int64) ([]domain.WikiPageSummary, error) {
+	pages := []domain.WikiPageSummary{}
+	err := r.db.Select(ctx, &pages,
+		`SELECT `+wikiSummaryColumns+` FROM wiki_pages w WHERE w.project_id = $1 ORDER BY w.title, w.id`,
+		projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list wiki pages of project %d: %w", projectID, err)
+	}
+	return pages, nil
+}
+
+// FindBySlug retrieves a page of a project's wiki by its slug.
+func (r *WikiRepository) FindBySlug(ctx context.Context, projectID int64, slug string) (*domain.WikiPage, error) {
+	var page domain.WikiPage
+	err := r.db.Get(ctx, &page,
+		`SELECT `+wikiPageColumns+` FROM wiki_pages w WHERE w.project_id = $1 AND w.slug = $2`,
+		projectID, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find wiki page %q in project %d: %w", slug, projectID, err)
+	}
+	return &page, nil
+}
+
+// Create inserts a page as its first revision and returns it. It returns
+// domain.ErrConflict if the project's wiki already has a page with the slug.
+func (r *WikiRepository) Create(ctx context.Context, page domain.WikiPage, message string) (*domain.WikiPage, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var result domain.WikiPage
+	err = pgxscan.Get(ctx, tx, &result,
+		`INSERT INTO wiki_pages AS w (project_id, slug, title, body, created_by, updated_by)
+		 VALUES ($1, $2, $3, $4, $5, $5)
+		 RETURNING `+wikiPageColumns,
+		page.ProjectID, page.Slug, page.Title, page.Body, page.CreatedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: wiki page %q already exists", domain.ErrConflict, page.Slug)
+		}
+		return nil, fmt.Errorf("create wiki page %q in project %d: %w", page.Slug, page.ProjectID, err)
+	}
+	if err := insertWikiRevision(ctx, tx, &result, message); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit wiki page %q: %w", page.Slug, err)
+	}
+	return &result, nil
+}
+
+// Update writes a page's title and body as its next revision if it
+// satisfies pre, and returns the stored result. It returns
+// domain.ErrPreconditionFailed if the page was modified after
+// pre.UnmodifiedSince.
+func (r *WikiRepository) Update(ctx context.Context, page domain.WikiPage, message string, pre domain.Precondition) (*domain.WikiPage, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var result domain.WikiPage
+	err = pgxscan.Get(ctx, tx, &result,
+		`UPDATE wiki_pages w
+		 SET title = $2, body = $3, updated_by = $4, revision = revision + 1, updated_at = NOW()
+		 WHERE id = $1 AND `+unmodifiedSinceClause(5)+`
+		 RETURNING `+wikiPageColumns,
+		page.ID, page.Title, page.Body, page.UpdatedBy, pre.UnmodifiedSince)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			var exists bool
+			if err := pgxscan.Get(ctx, tx, &exists, `SELECT EXISTS (SELECT 1 FROM wiki_pages WHERE id = $1)`, page.ID); err != nil {
+				return nil, fmt.Errorf("check wiki page %d: %w", page.ID, err)
+			}
+			if !exists {
+				return nil, domain.ErrNotFound
+			}
+			return nil, domain.ErrPreconditionFailed
+		}
+		return nil, fmt.Errorf("update wiki page %d: %w", page.ID, err)
+	}
+	if err := insertWikiRevision(ctx, tx, &result, message); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit wiki page %d: %w", page.ID, err)
+	}
+	return &result, nil
+}
+
+// insertWikiRevision records a page's current content as its revision.
+func insertWikiRevision(ctx context.Context, tx pgx.Tx, page *domain.WikiPage, message string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO wiki_revisions (page_id, revision, title, body, message, author_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		page.ID, page.Revision, page.Title, page.Body, message, page.UpdatedBy)
+	if err != nil {
+		return fmt.Errorf("record revision %d of wiki page %d: %w", page.Revision, page.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a page and its revisions.
+func (r *WikiRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM wiki_pages WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete wiki page %d: %w", id, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Revisions returns a page's revisions after the position of their
+// creation time and revision number, newest first. The zero position
+// starts at the newest. It fetches one row beyond limit so callers can
+// detect a further page.
+func (r *WikiRepository) Revisions(ctx context.Context, pageID int64, after domain.ListPosition, limit int) ([]domain.WikiRevisionSummary, error) {
+	revisions := []domain.WikiRevisionSummary{}
+	err := r.db.Select(ctx, &revisions,
+		`SELECT `+wikiRevisionColumns+` FROM wiki_revisions
+		 WHERE page_id = $1 AND ($2::bigint = 0 OR (created_at, revision) < ($3::timestamptz, $2))
+		 ORDER BY created_at DESC, revision DESC
+		 LIMIT $4`,
+		pageID, after.ID, after.At, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions of wiki page %d: %w", pageID, err)
+	}
+	return revisions, nil
+}
+
+// Revision retrieves one revision of a page.
+func (r *WikiRepository) Revision(ctx context.Context, pageID int64, revision int) (*domain.WikiRevision, error) {
+	var result domain.WikiRevision
+	err := r.db.Get(ctx, &result,
+		`SELECT `+wikiRevisionColumns+`, body FROM wiki_revisions WHERE page_id = $1 AND revision = $2`,
+		pageID, revision)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("find revision %d of wiki page %d: %w", revision, pageID, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,166 @@
+// Package scheduler runs periodic maintenance tasks, such as purging the
+// trash or sweeping webhook retries, on a fixed schedule. A run of a task
+// that is still going when its next run is due is skipped rather than
+// started alongside it, and every run is counted in Prometheus metrics.
+//
+// The scheduler itself does not coordinate between replicas; run it under
+// locking.Locker.Singleton so tasks run on one instance at a time.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sumire/issues/internal/metrics"
+)
+
+// Task is a unit of periodic work.
+type Task struct {
+	// Name identifies the task in logs and metrics.
+	Name string
+	// Schedule is how often the task runs.
+	Schedule Schedule
+	// Timeout bounds a single run. Zero leaves runs unbounded.
+	Timeout time.Duration
+	// Run does the work. Errors are logged and counted; the task keeps its
+	// schedule regardless.
+	Run func(ctx context.Context) error
+}
+
+// Schedule is a fixed interval between runs, optionally aligned to the
+// wall clock.
+type Schedule struct {
+	// Every is the time between runs.
+	Every time.Duration
+	// Aligned runs the task on multiples of Every since the Unix epoch,
+	// such as the top of every hour, instead of Every after the scheduler
+	// starts.
+	Aligned bool
+}
+
+// ParseSchedule parses a cron-like schedule: "@hourly", "@daily",
+// "@every <duration>" or a bare duration such as "15m". "@hourly" and
+// "@daily" are aligned to the wall clock in UTC.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "@hourly":
+		return Schedule{Every: time.Hour, Aligned: true}, nil
+	case spec == "@daily" || spec == "@midnight":
+		return Schedule{Every: 24 * time.Hour, Aligned: true}, nil
+	case strings.HasPrefix(spec, "@every "):
+		spec = strings.TrimSpace(strings.TrimPrefix(spec, "@every "))
+	}
+	every, err := time.ParseDuration(spec)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule %q", spec)
+	}
+	if every <= 0 {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: interval must be positive", spec)
+	}
+	return Schedule{Every: every}, nil
+}
+
+// next returns when a task on the schedule should next run after now.
+func (s Schedule) next(now time.Time) time.Time {
+	if !s.Aligned {
+		return now.Add(s.Every)
+	}
+	return now.Truncate(s.Every).Add(s.Every)
+}
+
+// Scheduler runs tasks on their schedules.
+type Scheduler struct {
+	tasks []Task
+}
+
+// New creates a Scheduler with no tasks.
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add schedules a task. Tasks must be added before Run.
+func (s *Scheduler) Add(task Task) {
+	s.tasks = append(s.tasks, task)
+}
+
+// Run runs every task on its schedule until ctx is cancelled, then waits
+// for runs in progress to return. Unaligned tasks first run immediately.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, task := range s.tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, task)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// loop runs a task each time it is due, skipping runs that would overlap
+// one still in progress.
+func (s *Scheduler) loop(ctx context.Context, task Task) {
+	var running sync.WaitGroup
+	defer running.Wait()
+	busy := make(chan struct{}, 1)
+
+	due := time.Now()
+	if task.Schedule.Aligned {
+		due = task.Schedule.next(due)
+	}
+	for {
+		timer := time.NewTimer(time.Until(due))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		due = task.Schedule.next(time.Now())
+
+		select {
+		case busy <- struct{}{}:
+		default:
+			metrics.ObserveTaskSkipped(task.Name)
+			slog.Warn("scheduled task still running; run skipped", "task", task.Name)
+			continue
+		}
+		running.Add(1)
+		go func() {
+			defer func() {
+				<-busy
+				running.Done()
+			}()
+			runOnce(ctx, task)
+		}()
+	}
+}
+
+// runOnce runs a task, recording how it went.
+func runOnce(ctx context.Context, task Task) {
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return task.Run(ctx)
+	}()
+	metrics.ObserveTask(task.Name, time.Since(start), err)
+	if err != nil && ctx.Err() == nil {
+		slog.Error("scheduled task failed", "task", task.Name, "error", err)
+	}
+}
@@ -0,0 +1,144 @@
+// Package search provides external search engine backends for issues.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// Meilisearch indexes and searches issues in a Meilisearch index, for
+// deployments whose issue volume outgrows Postgres full-text search.
+type Meilisearch struct {
+	url    string
+	apiKey string
+	index  string
+	client *http.Client
+}
+
+// NewMeilisearch creates a Meilisearch backend for the index at url.
+func NewMeilisearch(url, apiKey, index string) *Meilisearch {
+	return &Meilisearch{
+		url:    strings.TrimRight(url, "/"),
+		apiKey: apiKey,
+		index:  index,
+		client: &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+}
+
+// document is how an issue is stored in the index.
+type document struct {
+	ID        int64  `json:"id"`
+	ProjectID int64  `json:"project_id"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Status    string `json:"status"`
+	Archived  bool   `json:"archived"`
+}
+
+// Setup makes project_id filterable, which Search relies on. Meilisearch
+// applies settings asynchronously.
+func (m *Meilisearch) Setup(ctx context.Context) error {
+	settings := map[string]any{
+		"filterableAttributes": []string{"project_id", "status", "archived"},
+		"searchableAttributes": []string{"title", "body"},
+	}
+	return m.do(ctx, http.MethodPatch, "/indexes/"+m.index+"/settings", settings, nil)
+}
+
+// Search returns the IDs of issues in a project matching query, best match
+// first.
+func (m *Meilisearch) Search(ctx context.Context, projectID int64, query string, offset, limit int) ([]int64, error) {
+	req := map[string]any{
+		"q":                    query,
+		"filter":               fmt.Sprintf("project_id = %d", projectID),
+		"offset":               offset,
+		"limit":                limit,
+		"attributesToRetrieve": []string{"id"},
+	}
+	var resp struct {
+		Hits []struct {
+			ID int64 `json:"id"`
+		} `json:"hits"`
+	}
+	if err := m.do(ctx, http.MethodPost, "/indexes/"+m.index+"/search", req, &resp); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(resp.Hits))
+	for i, h := range resp.Hits {
+		ids[i] = h.ID
+	}
+	return ids, nil
+}
+
+// Apply adds or replaces issues in the index and removes deleted ones.
+func (m *Meilisearch) Apply(ctx context.Context, issues []domain.Issue, removed []int64) error {
+	if len(issues) > 0 {
+		docs := make([]document, len(issues))
+		for i, issue := range issues {
+			docs[i] = document{
+				ID:        issue.ID,
+				ProjectID: issue.ProjectID,
+				Title:     issue.Title,
+				Status:    string(issue.Status),
+				Archived:  issue.ArchivedAt != nil,
+			}
+			if issue.Body != nil {
+				docs[i].Body = *issue.Body
+			}
+		}
+		if err := m.do(ctx, http.MethodPost, "/indexes/"+m.index+"/documents?primaryKey=id", docs, nil); err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		if err := m.do(ctx, http.MethodPost, "/indexes/"+m.index+"/documents/delete-batch", removed, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// do sends a JSON request and decodes the JSON response into out, if set.
+func (m *Meilisearch) do(ctx context.Context, method, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode meilisearch request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, m.url+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build meilisearch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("meilisearch %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("meilisearch %s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode meilisearch response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// EventStore defines the issue event data access interface consumed by services.
+type EventStore interface {
+	Record(ctx context.Context, event domain.IssueEvent) error
+	ListByActor(ctx context.Context, userID int64, after domain.ListPosition, limit int) ([]domain.IssueEvent, error)
+	ListAfter(ctx context.Context, after domain.LogPosition, types []domain.EventType, limit int) ([]domain.IssueEvent, error)
+}
+
+// ActivityService serves per-user activity feeds.
+type ActivityService struct {
+	events EventStore
+}
+
+// NewActivityService creates a new ActivityService.
+func NewActivityService(events EventStore) *ActivityService {
+	return &ActivityService{events: events}
+}
+
+// ActivityPage is a single page of a user's activity feed.
+type ActivityPage struct {
+	Events     []domain.IssueEvent
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// Feed returns the user's own recent actions across their projects.
+func (s *ActivityService) Feed(ctx context.Context, userID int64, after domain.ListPosition, limit int) (*ActivityPage, error) {
+	limit = clampPageSize(limit)
+	events, err := s.events.ListByActor(ctx, userID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list activity: %w", err)
+	}
+
+	page := &ActivityPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.HasNext = true
+		last := page.Events[len(page.Events)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// recordEvent appends an issue event. Failures are logged rather than
+// returned so that activity tracking never fails the primary action.
+func recordEvent(ctx context.Context, events EventStore, event domain.IssueEvent) {
+	if err := events.Record(ctx, event); err != nil {
+		slog.Error("failed to record issue event",
+			"type", event.Type,
+			"issue_id", event.IssueID,
+			"error", err,
+		)
+	}
+}
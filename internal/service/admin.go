@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const defaultQueueStatsWindow = 24 * time.Hour
+
+// AIJobStore defines the AI job data access interface consumed by services.
+type AIJobStore interface {
+	Create(ctx context.Context, job domain.AIJob) (*domain.AIJob, error)
+	FindByID(ctx context.Context, id int64) (*domain.AIJob, error)
+	ListArtifacts(ctx context.Context, jobID int64) ([]domain.AIJobArtifact, error)
+	FindArtifact(ctx context.Context, jobID, artifactID int64) (*domain.AIJobArtifact, error)
+	FindArtifactByID(ctx context.Context, id int64) (*domain.AIJobArtifact, error)
+	ListRuns(ctx context.Context, issueID int64, after domain.ListPosition, limit int) ([]domain.AIRun, error)
+	ListReviewComments(ctx context.Context, issueID int64, after domain.ListPosition, limit int) ([]domain.AIReviewComment, error)
+	List(ctx context.Context, filter domain.AIJobFilter) ([]domain.AIJob, error)
+	QueueStats(ctx context.Context, window time.Duration) (*domain.AIJobQueueStats, error)
+	LatestForIssue(ctx context.Context, issueID int64) (*domain.AIJob, error)
+	ListLogs(ctx context.Context, jobID, afterID int64, limit int) ([]domain.AIJobLog, error)
+	Cancel(ctx context.Context, jobID, userID int64) (*domain.AIJob, error)
+	Retry(ctx context.Context, jobID int64) (*domain.AIJob, error)
+	ApproveRun(ctx context.Context, jobID, userID int64) (*domain.AIRun, error)
+	RequeueStuck(ctx context.Context, limit time.Duration) (int64, error)
+}
+
+// FlagStore defines the system flag data access interface consumed by services.
+type FlagStore interface {
+	Enabled(ctx context.Context, name string) (bool, error)
+	Set(ctx context.Context, name string, enabled bool, userID int64) error
+}
+
+// WorkerPool is the resizable AI worker pool of this instance.
+type WorkerPool interface {
+	Size() int
+	Resize(n int) error
+	Stats() []domain.WorkerStats
+}
+
+// AdminService exposes operational views and controls to system administrators.
+type AdminService struct {
+	users   UserStore
+	jobs    AIJobStore
+	flags   FlagStore
+	workers WorkerPool
+}
+
+// AdminOption configures an AdminService.
+type AdminOption func(*AdminService)
+
+// WithWorkerPool lets administrators inspect and resize this instance's AI
+// worker pool. Without it the instance is treated as running no workers.
+func WithWorkerPool(p WorkerPool) AdminOption {
+	return func(s *AdminService) {
+		s.workers = p
+	}
+}
+
+// NewAdminService creates a new AdminService.
+func NewAdminService(users UserStore, jobs AIJobStore, flags FlagStore, opts ...AdminOption) *AdminService {
+	s := &AdminService{users: users, jobs: jobs, flags: flags}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AIJobPage is a single page of AI jobs.
+type AIJobPage struct {
+	Jobs       []domain.AIJob
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// ListAIJobs returns AI jobs across all projects matching the filter.
+func (s *AdminService) ListAIJobs(ctx context.Context, userID int64, filter domain.AIJobFilter) (*AIJobPage, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+
+	filter.Limit = clampPageSize(filter.Limit)
+	jobs, err := s.jobs.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list ai jobs: %w", err)
+	}
+
+	page := &AIJobPage{Jobs: jobs}
+	if len(jobs) > filter.Limit {
+		page.Jobs = jobs[:filter.Limit]
+		page.HasNext = true
+		last := page.Jobs[len(page.Jobs)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// AIJobStats returns queue depth, wait and failure figures over the trailing
+// window, which defaults to 24 hours.
+func (s *AdminService) AIJobStats(ctx context.Context, userID int64, window time.Duration) (*domain.AIJobQueueStats, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	if window <= 0 {
+		window = defaultQueueStatsWindow
+	}
+	return s.jobs.QueueStats(ctx, window)
+}
+
+// Workers returns the queue depth and this instance's worker pool status.
+func (s *AdminService) Workers(ctx context.Context, userID int64) (*domain.WorkerPoolStatus, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	return s.workerStatus(ctx)
+}
+
+// ResizeWorkers changes the number of AI workers on this instance. Other
+// instances keep their own pool sizes.
+func (s *AdminService) ResizeWorkers(ctx context.Context, userID int64, n int) (*domain.WorkerPoolStatus, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	if s.workers == nil {
+		return nil, fmt.Errorf("%w: AI workers are not running on this instance", domain.ErrConflict)
+	}
+	if err := s.workers.Resize(n); err != nil {
+		return nil, err
+	}
+	return s.workerStatus(ctx)
+}
+
+// SetAIPaused pauses or resumes AI processing in every project. Queued jobs
+// stay queued while paused.
+func (s *AdminService) SetAIPaused(ctx context.Context, userID int64, paused bool) error {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return err
+	}
+	if err := s.flags.Set(ctx, domain.FlagAIPaused, paused, userID); err != nil {
+		return err
+	}
+	slog.Info("global ai processing toggled", "paused", paused, "user_id", userID)
+	return nil
+}
+
+// QueueDepth returns the number of pending AI jobs.
+func (s *AdminService) QueueDepth(ctx context.Context) (int, error) {
+	stats, err := s.jobs.QueueStats(ctx, defaultQueueStatsWindow)
+	if err != nil {
+		return 0, err
+	}
+	return stats.Pending, nil
+}
+
+func (s *AdminService) workerStatus(ctx context.Context) (*domain.WorkerPoolStatus, error) {
+	stats, err := s.jobs.QueueStats(ctx, defaultQueueStatsWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.WorkerPoolStatus{
+		QueueDepth: stats.Pending,
+		Running:    stats.Running,
+		Workers:    []domain.WorkerStats{},
+	}
+	if s.workers == nil {
+		return status, nil
+	}
+
+	status.Size = s.workers.Size()
+	status.Workers = s.workers.Stats()
+	for _, w := range status.Workers {
+		status.Utilization += w.Utilization
+	}
+	if len(status.Workers) > 0 {
+		status.Utilization /= float64(len(status.Workers))
+	}
+	return status, nil
+}
+
+// authorizeSystemAdmin verifies the user is a system administrator.
+func authorizeSystemAdmin(ctx context.Context, users UserStore, userID int64) error {
+	user, err := users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return domain.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// AIBot posts AI job results as comments from the system AI bot account.
+type AIBot struct {
+	users    UserStore
+	comments CommentStore
+	events   EventStore
+
+	mu    sync.Mutex
+	botID int64
+}
+
+// NewAIBot creates a new AIBot.
+func NewAIBot(users UserStore, comments CommentStore, events EventStore) *AIBot {
+	return &AIBot{users: users, comments: comments, events: events}
+}
+
+// PostResult comments on the job's issue with the run's summary and links to
+// its artifacts, such as logs and patches.
+func (b *AIBot) PostResult(ctx context.Context, job domain.AIJob, summary string, artifacts []domain.AIJobArtifact) (*domain.Comment, error) {
+	botID, err := b.id(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := b.comments.Create(ctx, domain.Comment{
+		IssueID:  job.IssueID,
+		AuthorID: botID,
+		Body:     resultComment(job, summary, artifacts),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("post ai result for job %d: %w", job.ID, err)
+	}
+
+	recordEvent(ctx, b.events, domain.IssueEvent{
+		ProjectID: job.ProjectID,
+		IssueID:   job.IssueID,
+		ActorID:   &botID,
+		Type:      domain.EventCommentCreated,
+		Data:      domain.EventData{"comment_id": comment.ID, "job_id": job.ID},
+	})
+	return comment, nil
+}
+
+// id returns the bot's user ID, looking it up once.
+func (b *AIBot) id(ctx context.Context) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.botID == 0 {
+		bot, err := b.users.FindByProviderID(ctx, domain.AuthProviderSystem, domain.AIBotProviderID)
+		if err != nil {
+			return 0, fmt.Errorf("find ai bot user: %w", err)
+		}
+		b.botID = bot.ID
+	}
+	return b.botID, nil
+}
+
+// resultComment renders the Markdown body of a result comment.
+func resultComment(job domain.AIJob, summary string, artifacts []domain.AIJobArtifact) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**AI run #%d %s**\n\n", job.ID, job.Status)
+	if s := strings.TrimSpace(summary); s != "" {
+		b.WriteString(s)
+		b.WriteString("\n\n")
+	}
+	if len(artifacts) > 0 {
+		b.WriteString("Artifacts:\n")
+		for _, a := range artifacts {
+			fmt.Fprintf(&b, "- [%s](/api/v1/projects/%d/ai-jobs/%d/artifacts/%d)\n", a.Name, job.ProjectID, job.ID, a.ID)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/signedurl"
+	"github.com/sumire/issues/internal/storage"
+)
+
+// ObjectStore defines the object storage interface consumed by services.
+type ObjectStore interface {
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// AIJobService handles running AI on issues.
+type AIJobService struct {
+	projects   ProjectStore
+	issues     IssueStore
+	jobs       AIJobStore
+	events     EventStore
+	objects    ObjectStore
+	freezes    FreezeStore
+	urls       *signedurl.Signer
+	maxTimeout time.Duration
+}
+
+// AIJobOption configures an AIJobService.
+type AIJobOption func(*AIJobService)
+
+// WithAIFreezeWindows makes AI runs respect the project's freeze windows.
+func WithAIFreezeWindows(freezes FreezeStore) AIJobOption {
+	return func(s *AIJobService) {
+		s.freezes = freezes
+	}
+}
+
+// WithArtifactURLs lets users get signed download URLs for artifacts.
+func WithArtifactURLs(urls *signedurl.Signer) AIJobOption {
+	return func(s *AIJobService) {
+		s.urls = urls
+	}
+}
+
+// NewAIJobService creates a new AIJobService. maxTimeout bounds how long any
+// single run may take.
+func NewAIJobService(projects ProjectStore, issues IssueStore, jobs AIJobStore, events EventStore, objects ObjectStore,
+	maxTimeout time.Duration, opts ...AIJobOption) *AIJobService {
+	s := &AIJobService{
+		projects:   projects,
+		issues:     issues,
+		jobs:       jobs,
+		events:     events,
+		objects:    objects,
+		maxTimeout: maxTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RunOptions adjusts a single AI run.
+type RunOptions struct {
+	// Timeout limits the run. Zero uses the server limit.
+	Timeout time.Duration
+	// Instructions are appended to the prompt, for example to iterate on an
+	// earlier result.
+	Instructions string
+	// Resume continues the issue's previous AI session.
+	Resume bool
+}
+
+// Run queues an AI job for an issue. Any project member may run AI on an
+// unarchived issue that has no job in progress, including to re-run it with
+// further instructions. While the project is frozen only admins may run AI.
+func (s *AIJobService) Run(ctx context.Context, userID, projectID, issueID int64, opts RunOptions) (*domain.AIJob, error) {
+	return s.enqueue(ctx, userID, projectID, issueID, domain.AIJob{Mode: domain.AIJobModeImplement}, opts)
+}
+
+// Review queues an AI job that reviews diff, a change made for the issue,
+// and stores its findings as review comments on the issue. The same rules as
+// Run apply.
+func (s *AIJobService) Review(ctx context.Context, userID, projectID, issueID int64, diff string, opts RunOptions) (*domain.AIJob, error) {
+	if strings.TrimSpace(diff) == "" {
+		return nil, &domain.ValidationError{Field: "diff", Message: "is required"}
+	}
+	return s.enqueue(ctx, userID, projectID, issueID, domain.AIJob{Mode: domain.AIJobModeReview, Diff: &diff}, opts)
+}
+
+// enqueue completes job for the issue from opts and queues it.
+func (s *AIJobService) enqueue(ctx context.Context, userID, projectID, issueID int64, job domain.AIJob, opts RunOptions) (*domain.AIJob, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := enforceFreeze(ctx, s.freezes, role, projectID); err != nil {
+		return nil, err
+	}
+	issue, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: archived issues cannot be run", domain.ErrConflict)
+	}
+
+	job.IssueID = issueID
+	job.TriggeredBy = &userID
+	if opts.Timeout > 0 {
+		if opts.Timeout > s.maxTimeout {
+			return nil, &domain.ValidationError{
+				Field:   "timeout_seconds",
+				Message: fmt.Sprintf("must not exceed %d", int(s.maxTimeout.Seconds())),
+			}
+		}
+		seconds := int(opts.Timeout.Seconds())
+		job.TimeoutSeconds = &seconds
+	}
+	if opts.Instructions != "" {
+		job.Instructions = &opts.Instructions
+	}
+	if opts.Resume {
+		if issue.AISessionID == nil {
+			return nil, &domain.ValidationError{Field: "resume", Message: "issue has no previous AI session"}
+		}
+		job.ResumeSessionID = issue.AISessionID
+	}
+	if trace, ok := TraceFrom(ctx); ok && trace.RequestID != "" {
+		job.RequestID = &trace.RequestID
+	}
+
+	created, err := s.jobs.Create(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	recordEvent(ctx, s.events, domain.IssueEvent{
+		ProjectID: projectID,
+		IssueID:   issueID,
+		ActorID:   &userID,
+		Type:      domain.EventAIRun,
+		Data:      domain.EventData{"job_id": created.ID, "mode": string(created.Mode)},
+	})
+	return created, nil
+}
+
+// Cancel stops the issue's latest AI job. A pending job is cancelled at
+// once; a running job is flagged, and its worker kills the agent and marks it
+// cancelled within a few seconds. The returned job shows which happened.
+func (s *AIJobService) Cancel(ctx context.Context, userID, projectID, issueID int64) (*domain.AIJob, error) {
+	job, err := s.latestJob(ctx, userID, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Status.CanTransition(domain.JobStatusCancelled) {
+		return nil, fmt.Errorf("%w: a %s job cannot be cancelled", domain.ErrConflict, job.Status)
+	}
+
+	cancelled, err := s.jobs.Cancel(ctx, job.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cancelled.Status == domain.JobStatusCancelled {
+		recordEvent(ctx, s.events, domain.IssueEvent{
+			ProjectID: projectID,
+			IssueID:   issueID,
+			ActorID:   &userID,
+			Type:      domain.EventAIJobCancelled,
+			Data:      domain.EventData{"job_id": cancelled.ID, "mode": string(cancelled.Mode)},
+		})
+	}
+	return cancelled, nil
+}
+
+// Retry queues the issue's latest AI job again if it failed or was
+// cancelled, with its attempts reset. Freezes apply as for Run.
+func (s *AIJobService) Retry(ctx context.Context, userID, projectID, issueID int64) (*domain.AIJob, error) {
+	job, err := s.latestJob(ctx, userID, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Status.Retryable() {
+		return nil, fmt.Errorf("%w: a %s job cannot be retried", domain.ErrConflict, job.Status)
+	}
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := enforceFreeze(ctx, s.freezes, role, projectID); err != nil {
+		return nil, err
+	}
+
+	retried, err := s.jobs.Retry(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	recordEvent(ctx, s.events, domain.IssueEvent{
+		ProjectID: projectID,
+		IssueID:   issueID,
+		ActorID:   &userID,
+		Type:      domain.EventAIRun,
+		Data:      domain.EventData{"job_id": retried.ID, "mode": string(retried.Mode), "retry": true},
+	})
+	return retried, nil
+}
+
+// Latest returns the most recent AI job on an issue.
+func (s *AIJobService) Latest(ctx context.Context, userID, projectID, issueID int64) (*domain.AIJob, error) {
+	return s.latestJob(ctx, userID, projectID, issueID)
+}
+
+// Approve records that the user approved the result of a completed job.
+// Only its latest run can be approved, once.
+func (s *AIJobService) Approve(ctx context.Context, userID, projectID, jobID int64) (*domain.AIRun, error) {
+	job, err := s.findJob(ctx, userID, projectID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusCompleted {
+		return nil, fmt.Errorf("%w: a %s job cannot be approved", domain.ErrConflict, job.Status)
+	}
+	return s.jobs.ApproveRun(ctx, jobID, userID)
+}
+
+// latestJob returns the most recent AI job on an issue the user can access.
+func (s *AIJobService) latestJob(ctx context.Context, userID, projectID, issueID int64) (*domain.AIJob, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	return s.jobs.LatestForIssue(ctx, issueID)
+}
+
+// AIRunPage is a single page of an issue's AI runs.
+type AIRunPage struct {
+	Runs       []domain.AIRun
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// Runs returns a page of the AI runs on an issue, newest first.
+func (s *AIJobService) Runs(ctx context.Context, userID, projectID, issueID int64, after domain.ListPosition, limit int) (*AIRunPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	runs, err := s.jobs.ListRuns(ctx, issueID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list ai runs: %w", err)
+	}
+
+	page := &AIRunPage{Runs: runs}
+	if len(runs) > limit {
+		page.Runs = runs[:limit]
+		page.HasNext = true
+		last := page.Runs[len(page.Runs)-1]
+		page.NextCursor = domain.ListPosition{At: last.StartedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// ReviewCommentPage is a single page of an issue's AI review comments.
+type ReviewCommentPage struct {
+	Comments   []domain.AIReviewComment
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// ReviewComments returns a page of the AI review comments on an issue,
+// newest first.
+func (s *AIJobService) ReviewComments(ctx context.Context, userID, projectID, issueID int64, after domain.ListPosition, limit int) (*ReviewCommentPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	comments, err := s.jobs.ListReviewComments(ctx, issueID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list review comments: %w", err)
+	}
+
+	page := &ReviewCommentPage{Comments: comments}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		page.HasNext = true
+		last := page.Comments[len(page.Comments)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// RequeueStuck queues again the running jobs whose worker has not reported
+// back well past their timeout, and returns how many there were.
+func (s *AIJobService) RequeueStuck(ctx context.Context) (int64, error) {
+	return s.jobs.RequeueStuck(ctx, s.maxTimeout)
+}
+
+// Artifacts lists the files a job collected from its workspace.
+func (s *AIJobService) Artifacts(ctx context.Context, userID, projectID, jobID int64) ([]domain.AIJobArtifact, error) {
+	if _, err := s.findJob(ctx, userID, projectID, jobID); err != nil {
+		return nil, err
+	}
+	return s.jobs.ListArtifacts(ctx, jobID)
+}
+
+// OpenArtifact returns an artifact and its contents. The caller must close
+// the reader.
+func (s *AIJobService) OpenArtifact(ctx context.Context, userID, projectID, jobID, artifactID int64) (*domain.AIJobArtifact, io.ReadCloser, error) {
+	if _, err := s.findJob(ctx, userID, projectID, jobID); err != nil {
+		return nil, nil, err
+	}
+	artifact, err := s.jobs.FindArtifact(ctx, jobID, artifactID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.openArtifact(ctx, artifact)
+}
+
+// ArtifactURL returns a short-lived URL downloading an artifact of a job the
+// user can access. Storages that sign URLs serve the file directly.
+func (s *AIJobService) ArtifactURL(ctx context.Context, userID, projectID, jobID, artifactID int64) (*domain.SignedURL, error) {
+	if _, err := s.findJob(ctx, userID, projectID, jobID); err != nil {
+		return nil, err
+	}
+	if s.urls == nil {
+		return nil, fmt.Errorf("%w: artifact download links are not enabled", domain.ErrConflict)
+	}
+	artifact, err := s.jobs.FindArtifact(ctx, jobID, artifactID)
+	if err != nil {
+		return nil, err
+	}
+
+	u, expires, err := s.urls.ObjectURL(ctx, s.objects, artifact.StorageKey, path.Base(artifact.Name), signedurl.KindArtifact, artifact.ID, "")
+	if err != nil {
+		return nil, fmt.Errorf("sign artifact %d url: %w", artifactID, err)
+	}
+	return &domain.SignedURL{URL: u, ExpiresAt: expires}, nil
+}
+
+// OpenSignedArtifact returns an artifact and its contents for a URL made by
+// ArtifactURL. The caller must close the reader.
+func (s *AIJobService) OpenSignedArtifact(ctx context.Context, artifactID, expires int64, signature string) (*domain.AIJobArtifact, io.ReadCloser, error) {
+	if s.urls == nil {
+		return nil, nil, domain.ErrNotFound
+	}
+	if err := s.urls.Verify(signedurl.KindArtifact, artifactID, "", expires, signature); err != nil {
+		return nil, nil, fmt.Errorf("%w: download %v", domain.ErrForbidden, err)
+	}
+	artifact, err := s.jobs.FindArtifactByID(ctx, artifactID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.openArtifact(ctx, artifact)
+}
+
+// openArtifact opens the stored contents of an artifact.
+func (s *AIJobService) openArtifact(ctx context.Context, artifact *domain.AIJobArtifact) (*domain.AIJobArtifact, io.ReadCloser, error) {
+	r, err := s.objects.Open(ctx, artifact.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, domain.ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("open artifact %d: %w", artifact.ID, err)
+	}
+	return artifact, r, nil
+}
+
+const (
+	// logPollInterval is how often FollowLogs checks for new output.
+	logPollInterval = time.Second
+	logBatchSize    = 500
+)
+
+// FollowLogs streams the output of an AI job on an issue, jobID or else the
+// issue's latest, starting after the log chunk afterID. emit is called on
+// every poll, with no chunks if nothing new was written, until the job has
+// finished and all its output has been emitted; the finished job is then
+// returned. It stops early when ctx is cancelled or emit fails.
+func (s *AIJobService) FollowLogs(ctx context.Context, userID, projectID, issueID int64, jobID *int64, afterID int64,
+	emit func([]domain.AIJobLog) error) (*domain.AIJob, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	var job *domain.AIJob
+	var err error
+	if jobID != nil {
+		job, err = s.jobs.FindByID(ctx, *jobID)
+		if err == nil && job.IssueID != issueID {
+			err = domain.ErrNotFound
+		}
+	} else {
+		job, err = s.jobs.LatestForIssue(ctx, issueID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for {
+		// Read the job before its logs so that output written just before
+		// it finished is not missed.
+		current, err := s.jobs.FindByID(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := s.jobs.ListLogs(ctx, job.ID, afterID, logBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := emit(logs); err != nil {
+			return nil, err
+		}
+		if len(logs) > 0 {
+			afterID = logs[len(logs)-1].ID
+		}
+		if len(logs) == logBatchSize {
+			continue
+		}
+		if current.Done() {
+			return current, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// findJob returns a job in a project the user can access.
+func (s *AIJobService) findJob(ctx context.Context, userID, projectID, jobID int64) (*domain.AIJob, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	job, err := s.jobs.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.ProjectID != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
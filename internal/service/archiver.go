@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Archiver periodically archives issues that have been closed for longer
+// than their project's archive_after_days setting. Archived issues drop out
+// of default listings but stay retrievable with the archived filter.
+type Archiver struct {
+	issues   IssueStore
+	interval time.Duration
+}
+
+// NewArchiver creates an Archiver that runs every interval.
+func NewArchiver(issues IssueStore, interval time.Duration) *Archiver {
+	return &Archiver{issues: issues, interval: interval}
+}
+
+// Run archives issues until ctx is cancelled. It must run on a single
+// instance at a time.
+func (a *Archiver) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := a.issues.ArchiveClosed(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.Error("issue archival failed", "error", err)
+		case n > 0:
+			slog.Info("issues archived", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,537 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/antivirus"
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/imaging"
+	"github.com/sumire/issues/internal/signedurl"
+	"github.com/sumire/issues/internal/storage"
+)
+
+const (
+	defaultAttachmentMaxBytes = 25 << 20
+	maxAttachmentName         = 255
+	attachmentBlobBatch       = 100
+)
+
+// AttachmentStore defines the attachment data access interface consumed by
+// AttachmentService.
+type AttachmentStore interface {
+	Create(ctx context.Context, a domain.Attachment, blob domain.AttachmentBlob) (*domain.Attachment, error)
+	ListForIssue(ctx context.Context, issueID int64) ([]domain.Attachment, error)
+	FindByID(ctx context.Context, id int64) (*domain.Attachment, error)
+	Delete(ctx context.Context, id int64) error
+	ListPendingScan(ctx context.Context, limit int) ([]domain.Attachment, error)
+	RecordScan(ctx context.Context, id int64, result domain.ScanResult) (*domain.Attachment, error)
+	DeleteUnreferencedBlobs(ctx context.Context, cutoff time.Time, limit int) ([]domain.AttachmentBlob, error)
+}
+
+// AttachmentObjectStore defines the object storage interface consumed by
+// AttachmentService.
+type AttachmentObjectStore interface {
+	ObjectStore
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentService handles files attached to issues.
+type AttachmentService struct {
+	projects    ProjectStore
+	issues      IssueStore
+	attachments AttachmentStore
+	objects     AttachmentObjectStore
+	urls        *signedurl.Signer
+	scanner     antivirus.Scanner
+	images      *imaging.Processor
+	maxBytes    int64
+}
+
+// AttachmentOption configures an AttachmentService.
+type AttachmentOption func(*AttachmentService)
+
+// WithAttachmentLimit sets the largest file that may be attached.
+func WithAttachmentLimit(maxBytes int64) AttachmentOption {
+	return func(s *AttachmentService) {
+		if maxBytes > 0 {
+			s.maxBytes = maxBytes
+		}
+	}
+}
+
+// WithAttachmentScanner quarantines uploads until scanner has found them
+// clean, and rejects infected ones.
+func WithAttachmentScanner(scanner antivirus.Scanner) AttachmentOption {
+	return func(s *AttachmentService) {
+		s.scanner = scanner
+	}
+}
+
+// WithImageProcessor passes uploaded images through images, which strips
+// their metadata and makes their variants.
+func WithImageProcessor(images *imaging.Processor) AttachmentOption {
+	return func(s *AttachmentService) {
+		s.images = images
+	}
+}
+
+// NewAttachmentService creates a new AttachmentService. Download URLs are
+// signed by urls unless objects can sign URLs itself.
+func NewAttachmentService(projects ProjectStore, issues IssueStore, attachments AttachmentStore, objects AttachmentObjectStore,
+	urls *signedurl.Signer, opts ...AttachmentOption) *AttachmentService {
+	s := &AttachmentService{
+		projects:    projects,
+		issues:      issues,
+		attachments: attachments,
+		objects:     objects,
+		urls:        urls,
+		maxBytes:    defaultAttachmentMaxBytes,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Upload stores r as a file named name on an issue the user can access.
+// The type is sniffed from the contents and must be one of
+// domain.AttachmentTypes; files larger than the limit are rejected.
+// Images are processed before they are stored, if a processor is set.
+// Contents already stored for another attachment are shared rather than
+// stored again. With a
+// scanner, infected files are rejected with domain.ErrAttachmentInfected,
+// and files that could not be scanned yet stay quarantined as pending.
+func (s *AttachmentService) Upload(ctx context.Context, userID, projectID, issueID int64, name string, r io.Reader) (*domain.Attachment, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	name, err := attachmentName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	if n == 0 {
+		return nil, &domain.ValidationError{Field: "file", Message: "file is empty"}
+	}
+	contentType := http.DetectContentType(head[:n])
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !slices.Contains(domain.AttachmentTypes, mediaType) {
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("files of type %s are not accepted", mediaType)}
+	}
+
+	src := io.LimitReader(io.MultiReader(bytes.NewReader(head[:n]), r), s.maxBytes+1)
+	var images *imaging.Result
+	if s.images != nil && imaging.Handles(mediaType) {
+		if images, err = s.processImage(mediaType, src); err != nil {
+			return nil, err
+		}
+		src = bytes.NewReader(images.Data)
+	}
+
+	key, err := attachmentKey()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	body := &countingReader{r: io.TeeReader(src, hash)}
+	if err := s.objects.Put(ctx, key, body); err != nil {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	if body.n > s.maxBytes {
+		s.deleteObject(ctx, key)
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("file must be at most %d bytes", s.maxBytes)}
+	}
+	blob := domain.AttachmentBlob{
+		SHA256:     hex.EncodeToString(hash.Sum(nil)),
+		StorageKey: key,
+		SizeBytes:  body.n,
+	}
+	if images != nil {
+		if blob.Variants, err = s.putVariants(ctx, key, images.Variants); err != nil {
+			s.deleteObject(ctx, key)
+			return nil, err
+		}
+	}
+
+	status := domain.ScanUnscanned
+	if s.scanner != nil {
+		status = domain.ScanPending
+	}
+	attachment, err := s.attachments.Create(ctx, domain.Attachment{
+		IssueID:     issueID,
+		Name:        name,
+		ContentType: contentType,
+		SizeBytes:   body.n,
+		UploadedBy:  &userID,
+		ScanStatus:  status,
+	}, blob)
+	if err != nil {
+		s.deleteBlob(ctx, blob)
+		return nil, err
+	}
+	if attachment.StorageKey != key {
+		// The contents were already stored; the new copy is redundant.
+		s.deleteBlob(ctx, blob)
+	}
+	if s.scanner == nil {
+		return attachment, nil
+	}
+
+	scanned, err := s.scan(ctx, attachment)
+	if err != nil {
+		slog.Warn("attachment scan failed; left quarantined", "attachment_id", attachment.ID, "error", err)
+		return attachment, nil
+	}
+	if scanned.ScanStatus == domain.ScanInfected {
+		return nil, infectedError(scanned)
+	}
+	return scanned, nil
+}
+
+// ScanPending scans up to limit quarantined attachments whose scan failed
+// at upload and returns how many were scanned.
+func (s *AttachmentService) ScanPending(ctx context.Context, limit int) (int, error) {
+	if s.scanner == nil {
+		return 0, nil
+	}
+	pending, err := s.attachments.ListPendingScan(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	scanned := 0
+	for i := range pending {
+		a, err := s.scan(ctx, &pending[i])
+		if err != nil {
+			if ctx.Err() != nil {
+				return scanned, nil
+			}
+			slog.Warn("attachment scan failed", "attachment_id", pending[i].ID, "error", err)
+			continue
+		}
+		if a.ScanStatus == domain.ScanInfected {
+			slog.Warn("infected attachment rejected", "attachment_id", a.ID, "signature", *a.ScanSignature)
+		}
+		scanned++
+	}
+	return scanned, nil
+}
+
+// CollectBlobs deletes the stored contents no attachment has referenced
+// for at least grace and returns how many were deleted. Grace should be at
+// least as long as download URLs stay valid, since storages that sign
+// their own URLs serve objects without checking for an attachment.
+func (s *AttachmentService) CollectBlobs(ctx context.Context, grace time.Duration) (int, error) {
+	collected := 0
+	for {
+		blobs, err := s.attachments.DeleteUnreferencedBlobs(ctx, time.Now().Add(-grace), attachmentBlobBatch)
+		if err != nil {
+			return collected, err
+		}
+		for _, blob := range blobs {
+			s.deleteBlob(ctx, blob)
+		}
+		collected += len(blobs)
+		if len(blobs) < attachmentBlobBatch || ctx.Err() != nil {
+			return collected, nil
+		}
+	}
+}
+
+// List returns the files attached to an issue the user can access.
+func (s *AttachmentService) List(ctx context.Context, userID, projectID, issueID int64) ([]domain.Attachment, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	return s.attachments.ListForIssue(ctx, issueID)
+}
+
+// URL returns a short-lived URL downloading an attachment of an issue the
+// user can access, or its variant if variant is set. Storages that sign
+// URLs serve the file directly.
+func (s *AttachmentService) URL(ctx context.Context, userID, projectID, issueID, attachmentID int64, variant string) (*domain.SignedURL, error) {
+	attachment, err := s.find(ctx, userID, projectID, issueID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkDownloadable(attachment); err != nil {
+		return nil, err
+	}
+	served, err := servedFile(attachment, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	u, expires, err := s.urls.ObjectURL(ctx, s.objects, served.StorageKey, served.Name, signedurl.KindAttachment, attachment.ID, variant)
+	if err != nil {
+		return nil, fmt.Errorf("sign attachment %d url: %w", attachmentID, err)
+	}
+	return &domain.SignedURL{URL: u, ExpiresAt: expires}, nil
+}
+
+// OpenSigned returns an attachment and its contents for a URL made by URL.
+// For a variant, the attachment returned describes the variant's file. The
+// caller must close the reader.
+func (s *AttachmentService) OpenSigned(ctx context.Context, attachmentID int64, variant string, expires int64, signature string) (*domain.Attachment, io.ReadCloser, error) {
+	if err := s.urls.Verify(signedurl.KindAttachment, attachmentID, variant, expires, signature); err != nil {
+		return nil, nil, fmt.Errorf("%w: download %v", domain.ErrForbidden, err)
+	}
+	attachment, err := s.attachments.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkDownloadable(attachment); err != nil {
+		return nil, nil, err
+	}
+	served, err := servedFile(attachment, variant)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := s.objects.Open(ctx, served.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, domain.ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("open attachment %d: %w", attachmentID, err)
+	}
+	return served, r, nil
+}
+
+// Delete removes an attachment from an issue. Uploaders may delete their own
+// files and project admins any.
+func (s *AttachmentService) Delete(ctx context.Context, userID, projectID, issueID, attachmentID int64) error {
+	attachment, err := s.find(ctx, userID, projectID, issueID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.UploadedBy == nil || *attachment.UploadedBy != userID {
+		if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+			return err
+		}
+	}
+	if err := s.attachments.Delete(ctx, attachmentID); err != nil {
+		return err
+	}
+	// Shared contents are deleted by CollectBlobs once unreferenced.
+	if attachment.SHA256 == nil {
+		s.deleteObject(ctx, attachment.StorageKey)
+	}
+	return nil
+}
+
+// find returns an attachment of an issue the user can access.
+func (s *AttachmentService) find(ctx context.Context, userID, projectID, issueID, attachmentID int64) (*domain.Attachment, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachments.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.IssueID != issueID {
+		return nil, domain.ErrNotFound
+	}
+	return attachment, nil
+}
+
+// scan scans a pending attachment's contents and records the result. The
+// contents of infected attachments are deleted, and every attachment
+// sharing them is marked infected too.
+func (s *AttachmentService) scan(ctx context.Context, attachment *domain.Attachment) (*domain.Attachment, error) {
+	r, err := s.objects.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("open attachment %d: %w", attachment.ID, err)
+	}
+	verdict, err := s.scanner.Scan(ctx, r)
+	r.Close()
+	if err != nil {
+		return nil, fmt.Errorf("scan attachment %d: %w", attachment.ID, err)
+	}
+
+	result := domain.ScanResult{Status: domain.ScanClean, Scanner: s.scanner.Name()}
+	if verdict.Infected {
+		result.Status = domain.ScanInfected
+		result.Signature = &verdict.Signature
+	}
+	scanned, err := s.attachments.RecordScan(ctx, attachment.ID, result)
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Infected {
+		s.deleteBlob(ctx, domain.AttachmentBlob{StorageKey: attachment.StorageKey, Variants: attachment.Variants})
+	}
+	return scanned, nil
+}
+
+// checkDownloadable rejects attachments that are quarantined or infected.
+func checkDownloadable(attachment *domain.Attachment) error {
+	switch {
+	case attachment.Downloadable():
+		return nil
+	case attachment.ScanStatus == domain.ScanInfected:
+		return infectedError(attachment)
+	default:
+		return fmt.Errorf("%w: attachment is quarantined until it has been scanned", domain.ErrConflict)
+	}
+}
+
+// infectedError describes the malware found in an attachment.
+func infectedError(attachment *domain.Attachment) error {
+	signature := "malware"
+	if attachment.ScanSignature != nil {
+		signature = *attachment.ScanSignature
+	}
+	return fmt.Errorf("%w: %s contains %s", domain.ErrAttachmentInfected, attachment.Name, signature)
+}
+
+// processImage reads an image of mediaType from r, failing if it exceeds
+// the limit, and processes it.
+func (s *AttachmentService) processImage(mediaType string, r io.Reader) (*imaging.Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("file must be at most %d bytes", s.maxBytes)}
+	}
+	result, err := s.images.Process(mediaType, data)
+	if errors.Is(err, imaging.ErrInvalid) {
+		return nil, &domain.ValidationError{Field: "file", Message: fmt.Sprintf("file is not a valid %s image", mediaType)}
+	}
+	return result, err
+}
+
+// putVariants stores the variants of the image stored under key.
+func (s *AttachmentService) putVariants(ctx context.Context, key string, images []imaging.Image) (domain.AttachmentVariants, error) {
+	variants := make(domain.AttachmentVariants, 0, len(images))
+	for _, img := range images {
+		if err := s.objects.Put(ctx, variantKey(key, img.Name), bytes.NewReader(img.Data)); err != nil {
+			for _, v := range variants {
+				s.deleteObject(ctx, variantKey(key, v.Name))
+			}
+			return nil, fmt.Errorf("upload attachment variant %s: %w", img.Name, err)
+		}
+		variants = append(variants, domain.AttachmentVariant{
+			Name:        img.Name,
+			ContentType: img.ContentType,
+			Width:       img.Width,
+			Height:      img.Height,
+			SizeBytes:   int64(len(img.Data)),
+		})
+	}
+	return variants, nil
+}
+
+// servedFile returns the attachment itself, or a copy describing its
+// variant if variant is set.
+func servedFile(attachment *domain.Attachment, variant string) (*domain.Attachment, error) {
+	if variant == "" {
+		return attachment, nil
+	}
+	v, ok := attachment.Variants.Find(variant)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	served := *attachment
+	served.StorageKey = variantKey(attachment.StorageKey, v.Name)
+	served.ContentType = v.ContentType
+	served.SizeBytes = v.SizeBytes
+	served.Name = variantName(attachment.Name, v)
+	return &served, nil
+}
+
+// deleteBlob removes stored contents and their variants.
+func (s *AttachmentService) deleteBlob(ctx context.Context, blob domain.AttachmentBlob) {
+	s.deleteObject(ctx, blob.StorageKey)
+	for _, v := range blob.Variants {
+		s.deleteObject(ctx, variantKey(blob.StorageKey, v.Name))
+	}
+}
+
+// deleteObject removes a stored file whose record is gone or was never
+// made. Failures only leave an orphaned object behind and are logged.
+func (s *AttachmentService) deleteObject(ctx context.Context, key string) {
+	if err := s.objects.Delete(ctx, key); err != nil {
+		slog.Error("failed to delete attachment object", "key", key, "error", err)
+	}
+}
+
+// attachmentName reduces an uploaded file name to its base name.
+func attachmentName(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "." || name == "/" || name == "" {
+		return "", &domain.ValidationError{Field: "file", Message: "file name is required"}
+	}
+	if len(name) > maxAttachmentName {
+		return "", &domain.ValidationError{Field: "file", Message: fmt.Sprintf("file name must be at most %d bytes", maxAttachmentName)}
+	}
+	return name, nil
+}
+
+// attachmentKey returns a new, unguessable storage key for attachment
+// contents. Contents may be shared across issues and projects, so the key
+// names neither.
+func attachmentKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate attachment key: %w", err)
+	}
+	return "attachments/blobs/" + hex.EncodeToString(b), nil
+}
+
+// variantKey returns the storage key of a variant of the contents stored
+// under key.
+func variantKey(key, variant string) string {
+	return key + "-" + variant
+}
+
+// variantName returns the file name a variant of a file named name is
+// downloaded as, as in photo-thumbnail.jpg.
+func variantName(name string, v domain.AttachmentVariant) string {
+	ext := ".png"
+	if v.ContentType == "image/jpeg" {
+		ext = ".jpg"
+	}
+	return strings.TrimSuffix(name, path.Ext(name)) + "-" + v.Name + ext
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
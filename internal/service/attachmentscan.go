@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// attachmentScanBatch bounds how many quarantined attachments are scanned
+// per tick.
+const attachmentScanBatch = 50
+
+// AttachmentRescanner periodically retries scanning attachments left
+// quarantined because the scanner was unavailable when they were uploaded.
+type AttachmentRescanner struct {
+	attachments *AttachmentService
+	interval    time.Duration
+}
+
+// NewAttachmentRescanner creates an AttachmentRescanner that runs every
+// interval.
+func NewAttachmentRescanner(attachments *AttachmentService, interval time.Duration) *AttachmentRescanner {
+	return &AttachmentRescanner{attachments: attachments, interval: interval}
+}
+
+// Run scans quarantined attachments until ctx is cancelled. It must run on
+// a single instance at a time.
+func (r *AttachmentRescanner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.attachments.ScanPending(ctx, attachmentScanBatch)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.Error("attachment rescan failed", "error", err)
+		case n > 0:
+			slog.Info("quarantined attachments scanned", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
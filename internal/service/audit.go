@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// AuditStore defines the audit log interface consumed by services.
+type AuditStore interface {
+	Record(ctx context.Context, entry domain.AuditEntry) error
+	List(ctx context.Context, projectID int64, after domain.ListPosition, limit int) ([]domain.AuditEntry, error)
+	Each(ctx context.Context, projectID int64, fn func(domain.AuditEntry) error) error
+}
+
+// AuditService exposes a project's audit log to its admins.
+type AuditService struct {
+	projects ProjectStore
+	audit    AuditStore
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(projects ProjectStore, audit AuditStore) *AuditService {
+	return &AuditService{projects: projects, audit: audit}
+}
+
+// AuditPage is a single page of audit entries.
+type AuditPage struct {
+	Entries    []domain.AuditEntry
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// List returns a page of the project's audit log.
+func (s *AuditService) List(ctx context.Context, userID, projectID int64, after domain.ListPosition, limit int) (*AuditPage, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	entries, err := s.audit.List(ctx, projectID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+
+	page := &AuditPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.HasNext = true
+		last := page.Entries[len(page.Entries)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// Export streams the project's entire audit log to fn.
+func (s *AuditService) Export(ctx context.Context, userID, projectID int64, fn func(domain.AuditEntry) error) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	return s.audit.Each(ctx, projectID, fn)
+}
+
+// recordAudit appends an administrative action to the project's audit log.
+func recordAudit(ctx context.Context, audit AuditStore, projectID, actorID int64, action domain.AuditAction, target domain.AuditTarget, targetID int64) error {
+	err := audit.Record(ctx, domain.AuditEntry{
+		ProjectID:  projectID,
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: target,
+		TargetID:   targetID,
+	})
+	if err != nil {
+		return fmt.Errorf("record audit: %w", err)
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -22,6 +25,13 @@ type UserStore interface {
 	Upsert(ctx context.Context, user domain.User) (*domain.User, error)
 }
 
+// RefreshTokenStore defines the refresh token data access interface consumed by AuthService.
+type RefreshTokenStore interface {
+	Create(ctx context.Context, id string, userID int64, expiresAt time.Time) error
+	Consume(ctx context.Context, id string, userID int64, now time.Time) error
+	PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
 // AuthConfig holds OAuth configuration.
 type AuthConfig struct {
 	GoogleClientID     string
@@ -34,17 +44,19 @@ type AuthConfig struct {
 
 // AuthService handles authentication logic.
 type AuthService struct {
-	users     UserStore
-	jwtSecret []byte
-	google    *oauth2.Config
-	github    *oauth2.Config
+	users         UserStore
+	refreshTokens RefreshTokenStore
+	jwtSecret     []byte
+	google        *oauth2.Config
+	github        *oauth2.Config
 }
 
 // NewAuthService creates a new AuthService.
-func NewAuthService(users UserStore, cfg AuthConfig) *AuthService {
+func NewAuthService(users UserStore, refreshTokens RefreshTokenStore, cfg AuthConfig) *AuthService {
 	return &AuthService{
-		users:     users,
-		jwtSecret: []byte(cfg.JWTSecret),
+		users:         users,
+		refreshTokens: refreshTokens,
+		jwtSecret:     []byte(cfg.JWTSecret),
 		google: &oauth2.Config{
 			ClientID:     cfg.GoogleClientID,
 			ClientSecret: cfg.GoogleClientSecret,
@@ -101,7 +113,7 @@ func (s *AuthService) GoogleCallback(ctx context.Context, code string) (*domain.
 		return nil, nil, fmt.Errorf("upsert google user: %w", err)
 	}
 
-	pair, err := s.generateTokenPair(user.ID)
+	pair, err := s.generateTokenPair(ctx, user.ID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -132,7 +144,7 @@ func (s *AuthService) GitHubCallback(ctx context.Context, code string) (*domain.
 		return nil, nil, fmt.Errorf("upsert github user: %w", err)
 	}
 
-	pair, err := s.generateTokenPair(user.ID)
+	pair, err := s.generateTokenPair(ctx, user.ID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,7 +183,8 @@ func (s *AuthService) ValidateToken(tokenString string) (int64, error) {
 }
 
 // RefreshAccessToken validates a refresh token and returns a new token pair.
-func (s *AuthService) RefreshAccessToken(refreshToken string) (*TokenPair, error) {
+// The refresh token is consumed: using it again is refused.
+func (s *AuthService) RefreshAccessToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
 	token, err := jwt.Parse(refreshToken, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
@@ -196,8 +209,25 @@ func (s *AuthService) RefreshAccessToken(refreshToken string) (*TokenPair, error
 	if !ok {
 		return nil, domain.ErrUnauthorized
 	}
+	tokenID, _ := claims["jti"].(string)
+	if tokenID == "" {
+		return nil, domain.ErrUnauthorized
+	}
+
+	userID := int64(userIDFloat)
+	if err := s.refreshTokens.Consume(ctx, tokenID, userID, time.Now()); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+	return s.generateTokenPair(ctx, userID)
+}
 
-	return s.generateTokenPair(int64(userIDFloat))
+// PurgeExpiredRefreshTokens deletes every refresh token that has expired
+// and returns how many were deleted. Expired tokens are already refused.
+func (s *AuthService) PurgeExpiredRefreshTokens(ctx context.Context) (int64, error) {
+	return s.refreshTokens.PurgeExpired(ctx, time.Now())
 }
 
 // GetUser retrieves a user by ID.
@@ -205,7 +235,9 @@ func (s *AuthService) GetUser(ctx context.Context, userID int64) (*domain.User,
 	return s.users.FindByID(ctx, userID)
 }
 
-func (s *AuthService) generateTokenPair(userID int64) (*TokenPair, error) {
+// generateTokenPair issues an access token and a refresh token, recording
+// the refresh token so it can be used once.
+func (s *AuthService) generateTokenPair(ctx context.Context, userID int64) (*TokenPair, error) {
 	now := time.Now()
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -219,16 +251,26 @@ func (s *AuthService) generateTokenPair(userID int64) (*TokenPair, error) {
 		return nil, fmt.Errorf("sign access token: %w", err)
 	}
 
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate refresh token ID: %w", err)
+	}
+	refreshID := hex.EncodeToString(raw)
+	refreshExpires := now.Add(7 * 24 * time.Hour)
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub":  userID,
 		"type": "refresh",
+		"jti":  refreshID,
 		"iat":  now.Unix(),
-		"exp":  now.Add(7 * 24 * time.Hour).Unix(),
+		"exp":  refreshExpires.Unix(),
 	})
 	refreshStr, err := refreshToken.SignedString(s.jwtSecret)
 	if err != nil {
 		return nil, fmt.Errorf("sign refresh token: %w", err)
 	}
+	if err := s.refreshTokens.Create(ctx, refreshID, userID, refreshExpires); err != nil {
+		return nil, err
+	}
 
 	return &TokenPair{
 		AccessToken:  accessStr,
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// memRefreshTokens is a RefreshTokenStore in memory.
+type memRefreshTokens struct {
+	mu     sync.Mutex
+	tokens map[string]refreshTokenRow
+}
+
+type refreshTokenRow struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+func (m *memRefreshTokens) Create(_ context.Context, id string, userID int64, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tokens == nil {
+		m.tokens = make(map[string]refreshTokenRow)
+	}
+	m.tokens[id] = refreshTokenRow{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (m *memRefreshTokens) Consume(_ context.Context, id string, userID int64, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row, ok := m.tokens[id]
+	if !ok || row.userID != userID || !row.expiresAt.After(now) {
+		return domain.ErrNotFound
+	}
+	delete(m.tokens, id)
+	return nil
+}
+
+func (m *memRefreshTokens) PurgeExpired(_ context.Context, cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for id, row := range m.tokens {
+		if row.expiresAt.Before(cutoff) {
+			delete(m.tokens, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestRefreshAccessTokenRotates(t *testing.T) {
+	ctx := context.Background()
+	store := &memRefreshTokens{}
+	auth := NewAuthService(nil, store, AuthConfig{JWTSecret: "secret"})
+
+	first, err := auth.generateTokenPair(ctx, 7)
+	if err != nil {
+		t.Fatalf("generateTokenPair() error = %v", err)
+	}
+	second, err := auth.RefreshAccessToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshAccessToken() error = %v", err)
+	}
+	if userID, err := auth.ValidateToken(second.AccessToken); err != nil || userID != 7 {
+		t.Errorf("ValidateToken(refreshed access token) = %d, %v, want 7", userID, err)
+	}
+
+	if _, err := auth.RefreshAccessToken(ctx, first.RefreshToken); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("RefreshAccessToken(used token) error = %v, want %v", err, domain.ErrUnauthorized)
+	}
+	if _, err := auth.RefreshAccessToken(ctx, second.RefreshToken); err != nil {
+		t.Errorf("RefreshAccessToken(new token) error = %v", err)
+	}
+}
+
+func TestRefreshAccessTokenRefusesUnrecorded(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"no jti", jwt.MapClaims{"sub": 7, "type": "refresh", "exp": now.Add(time.Hour).Unix()}},
+		{"unknown jti", jwt.MapClaims{"sub": 7, "type": "refresh", "jti": "unknown", "exp": now.Add(time.Hour).Unix()}},
+		{"access token", jwt.MapClaims{"sub": 7, "type": "access", "jti": "unknown", "exp": now.Add(time.Hour).Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := NewAuthService(nil, &memRefreshTokens{}, AuthConfig{JWTSecret: "secret"})
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatalf("sign token: %v", err)
+			}
+			if _, err := auth.RefreshAccessToken(context.Background(), token); !errors.Is(err, domain.ErrUnauthorized) {
+				t.Errorf("RefreshAccessToken() error = %v, want %v", err, domain.ErrUnauthorized)
+			}
+		})
+	}
+}
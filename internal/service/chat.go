@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/chat"
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	chatCursor      = "chat_relay"
+	chatBatchSize   = 100
+	chatMaxAttempts = 5
+)
+
+// chatEvents are the issue events posted to chat rooms.
+var chatEvents = []domain.EventType{
+	domain.EventIssueCreated,
+	domain.EventStatusChanged,
+	domain.EventAIRun,
+	domain.EventAIJobCompleted,
+	domain.EventAIJobFailed,
+	domain.EventAIJobCancelled,
+}
+
+// ChatEventSource defines the issue event interface consumed by ChatRelay.
+type ChatEventSource interface {
+	ListAfter(ctx context.Context, after domain.LogPosition, types []domain.EventType, limit int) ([]domain.IssueEvent, error)
+	LatestPosition(ctx context.Context) (domain.LogPosition, error)
+}
+
+// ChatRelay posts issue and AI job events to chat rooms. An event that a
+// notifier fails to post is tried again on the next poll, up to
+// chatMaxAttempts times, before it is skipped; notifiers that already posted
+// it may receive it again.
+type ChatRelay struct {
+	events    ChatEventSource
+	cursors   CursorStore
+	notifiers []chat.Notifier
+	linkBase  string
+	interval  time.Duration
+	failures  map[int64]int
+}
+
+// NewChatRelay creates a ChatRelay that polls every interval. Messages link
+// to issues in the web app at linkBase.
+func NewChatRelay(events ChatEventSource, cursors CursorStore, notifiers []chat.Notifier, linkBase string, interval time.Duration) *ChatRelay {
+	return &ChatRelay{
+		events:    events,
+		cursors:   cursors,
+		notifiers: notifiers,
+		linkBase:  strings.TrimRight(linkBase, "/"),
+		interval:  interval,
+		failures:  make(map[int64]int),
+	}
+}
+
+// Run relays events until ctx is cancelled. It must run on a single instance
+// at a time. On its first run it starts from the newest event rather than
+// posting the whole history.
+func (r *ChatRelay) Run(ctx context.Context) error {
+	position, err := r.cursors.Get(ctx, chatCursor)
+	if err != nil {
+		return err
+	}
+	if position.IsZero() {
+		if position, err = r.events.LatestPosition(ctx); err != nil {
+			return err
+		}
+		if err := r.cursors.Set(ctx, chatCursor, position); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if position, err = r.relay(ctx, position); err != nil && ctx.Err() == nil {
+			slog.Error("chat relay failed", "position", position, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay posts events after position and returns the new position. It stops
+// at the first event that must be retried.
+func (r *ChatRelay) relay(ctx context.Context, position domain.LogPosition) (domain.LogPosition, error) {
+	for {
+		events, err := r.events.ListAfter(ctx, position, chatEvents, chatBatchSize)
+		if err != nil {
+			return position, fmt.Errorf("list events: %w", err)
+		}
+		if len(events) == 0 {
+			return position, nil
+		}
+
+		for _, e := range events {
+			if !r.post(ctx, e) {
+				return position, r.cursors.Set(ctx, chatCursor, position)
+			}
+			position = e.Position()
+		}
+		if err := r.cursors.Set(ctx, chatCursor, position); err != nil {
+			return position, err
+		}
+		if len(events) < chatBatchSize {
+			return position, nil
+		}
+	}
+}
+
+// post sends an event to every notifier. It reports false if the event
+// should be tried again.
+func (r *ChatRelay) post(ctx context.Context, e domain.IssueEvent) bool {
+	msg := r.message(e)
+	failed := false
+	for _, n := range r.notifiers {
+		if err := n.Notify(ctx, msg); err != nil {
+			failed = true
+			slog.Warn("chat notification failed",
+				"notifier", n.Name(),
+				"event_id", e.ID,
+				"attempt", r.failures[e.ID]+1,
+				"error", err,
+			)
+		}
+	}
+	if !failed {
+		delete(r.failures, e.ID)
+		return true
+	}
+
+	r.failures[e.ID]++
+	if r.failures[e.ID] < chatMaxAttempts {
+		return false
+	}
+	slog.Error("chat notification dropped", "event_id", e.ID, "attempts", r.failures[e.ID])
+	delete(r.failures, e.ID)
+	return true
+}
+
+// message builds the chat message for an event.
+func (r *ChatRelay) message(e domain.IssueEvent) chat.Message {
+	msg := chat.Message{
+		ID:      strconv.FormatInt(e.ID, 10),
+		Summary: chat.Summary(e.Type, e.IssueID, e.Data),
+		Facts:   []chat.Fact{{Label: "Project", Value: fmt.Sprintf("#%d", e.ProjectID)}},
+	}
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		msg.Facts = append(msg.Facts, chat.Fact{Label: k, Value: fmt.Sprint(e.Data[k])})
+	}
+	if r.linkBase != "" {
+		msg.Link = fmt.Sprintf("%s/projects/%d/issues/%d", r.linkBase, e.ProjectID, e.IssueID)
+	}
+	return msg
+}
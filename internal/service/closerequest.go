@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const maxCloseRequestNote = 2000
+
+// CloseRequestStore defines the close request data access interface
+// consumed by CloseRequestService.
+type CloseRequestStore interface {
+	Create(ctx context.Context, req domain.CloseRequest) (*domain.CloseRequest, error)
+	Pending(ctx context.Context, issueID int64) (*domain.CloseRequest, error)
+	ListForIssue(ctx context.Context, issueID int64) ([]domain.CloseRequest, error)
+	Decide(ctx context.Context, id int64, state domain.CloseRequestState, decidedBy int64, note string) (*domain.CloseRequest, error)
+}
+
+// ProjectMemberLister lists project members with their roles.
+type ProjectMemberLister interface {
+	Members(ctx context.Context, projectID int64) ([]domain.ProjectMember, error)
+}
+
+// IssueStatusSetter moves issues between statuses on behalf of a user,
+// applying the same rules as an issue update.
+type IssueStatusSetter interface {
+	SetStatus(ctx context.Context, userID, projectID, issueID int64, status domain.IssueStatus, pre domain.Precondition) (*domain.Issue, error)
+}
+
+// CloseRequestService runs the approval workflow of projects whose issues
+// may only be closed by users with a given role. Other members request the
+// close, approvers are notified, and approving the request closes the issue.
+type CloseRequestService struct {
+	projects      ProjectStore
+	members       ProjectMemberLister
+	issues        IssueStore
+	statuses      IssueStatusSetter
+	requests      CloseRequestStore
+	notifications NotificationStore
+}
+
+// NewCloseRequestService creates a new CloseRequestService.
+func NewCloseRequestService(projects ProjectStore, members ProjectMemberLister, issues IssueStore, statuses IssueStatusSetter,
+	requests CloseRequestStore, notifications NotificationStore) *CloseRequestService {
+	return &CloseRequestService{
+		projects:      projects,
+		members:       members,
+		issues:        issues,
+		statuses:      statuses,
+		requests:      requests,
+		notifications: notifications,
+	}
+}
+
+// Request asks for an open issue to be moved to status, a done status, and
+// notifies the project's approvers. Any project member may request a close
+// in a project with a close approval rule; an issue has at most one pending
+// request.
+func (s *CloseRequestService) Request(ctx context.Context, userID, projectID, issueID int64, status domain.IssueStatus, note string) (*domain.CloseRequest, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	issue, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.approvalRole(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Done() {
+		return nil, &domain.ValidationError{Field: "status", Message: "must be completed or closed"}
+	}
+	if !issue.Status.CanTransitionTo(status) {
+		return nil, &domain.ValidationError{
+			Field:   "status",
+			Message: fmt.Sprintf("cannot move an issue from %s to %s", issue.Status, status),
+		}
+	}
+	if len(note) > maxCloseRequestNote {
+		return nil, &domain.ValidationError{Field: "note", Message: fmt.Sprintf("must be at most %d characters", maxCloseRequestNote)}
+	}
+
+	req, err := s.requests.Create(ctx, domain.CloseRequest{
+		IssueID:     issueID,
+		Status:      status,
+		Note:        note,
+		RequestedBy: &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyApprovers(ctx, userID, projectID, required, issue)
+	return req, nil
+}
+
+// List returns an issue's close requests, newest first.
+func (s *CloseRequestService) List(ctx context.Context, userID, projectID, issueID int64) ([]domain.CloseRequest, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	return s.requests.ListForIssue(ctx, issueID)
+}
+
+// Approve moves the issue to the requested status on the approver's behalf
+// and marks its pending close request approved. Only users with the
+// project's approving role may approve.
+func (s *CloseRequestService) Approve(ctx context.Context, userID, projectID, issueID int64, note string) (*domain.CloseRequest, error) {
+	req, issue, err := s.pending(ctx, userID, projectID, issueID, note)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.statuses.SetStatus(ctx, userID, projectID, issueID, req.Status, domain.Precondition{}); err != nil {
+		return nil, err
+	}
+	decided, err := s.requests.Decide(ctx, req.ID, domain.CloseRequestApproved, userID, note)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyRequester(ctx, decided, issue, domain.NotificationCloseApproved, "Close request approved")
+	return decided, nil
+}
+
+// Reject marks the issue's pending close request rejected, leaving the
+// issue as it is. Only users with the project's approving role may reject.
+func (s *CloseRequestService) Reject(ctx context.Context, userID, projectID, issueID int64, note string) (*domain.CloseRequest, error) {
+	req, issue, err := s.pending(ctx, userID, projectID, issueID, note)
+	if err != nil {
+		return nil, err
+	}
+	decided, err := s.requests.Decide(ctx, req.ID, domain.CloseRequestRejected, userID, note)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyRequester(ctx, decided, issue, domain.NotificationCloseRejected, "Close request rejected")
+	return decided, nil
+}
+
+// pending returns an issue's pending close request for an approver to
+// decide on.
+func (s *CloseRequestService) pending(ctx context.Context, userID, projectID, issueID int64, note string) (*domain.CloseRequest, *domain.Issue, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	issue, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+	required, err := s.approvalRole(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !role.Satisfies(required) {
+		return nil, nil, domain.ErrForbidden
+	}
+	if len(note) > maxCloseRequestNote {
+		return nil, nil, &domain.ValidationError{Field: "note", Message: fmt.Sprintf("must be at most %d characters", maxCloseRequestNote)}
+	}
+	req, err := s.requests.Pending(ctx, issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, issue, nil
+}
+
+// approvalRole returns the role the project requires to close issues. It
+// returns domain.ErrConflict if the project has no close approval rule.
+func (s *CloseRequestService) approvalRole(ctx context.Context, projectID int64) (domain.ProjectRole, error) {
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if project.Settings.CloseApproval == "" {
+		return "", fmt.Errorf("%w: closing issues in this project does not require approval", domain.ErrConflict)
+	}
+	return project.Settings.CloseApproval, nil
+}
+
+// notifyApprovers tells the project members who may approve a close request
+// about it. Failures are logged; the request stands.
+func (s *CloseRequestService) notifyApprovers(ctx context.Context, requesterID, projectID int64, required domain.ProjectRole, issue *domain.Issue) {
+	members, err := s.members.Members(ctx, projectID)
+	if err != nil {
+		slog.Error("failed to list close request approvers", "project_id", projectID, "error", err)
+		return
+	}
+	approvers := make([]int64, 0, len(members))
+	for _, m := range members {
+		if m.UserID != requesterID && m.Role.Satisfies(required) {
+			approvers = append(approvers, m.UserID)
+		}
+	}
+	if len(approvers) == 0 {
+		return
+	}
+
+	_, err = s.notifications.FanOut(ctx, approvers, domain.Notification{
+		IssueID: &issue.ID,
+		Type:    domain.NotificationCloseRequested,
+		Title:   "Close requested",
+		Message: issue.Title,
+	})
+	if err != nil {
+		slog.Error("failed to notify close request approvers", "issue_id", issue.ID, "error", err)
+	}
+}
+
+// notifyRequester tells the requester of a close request about the
+// decision on it. Failures are logged.
+func (s *CloseRequestService) notifyRequester(ctx context.Context, req *domain.CloseRequest, issue *domain.Issue, typ domain.NotificationType, title string) {
+	if req.RequestedBy == nil {
+		return
+	}
+	_, err := s.notifications.FanOut(ctx, []int64{*req.RequestedBy}, domain.Notification{
+		IssueID: &issue.ID,
+		Type:    typ,
+		Title:   title,
+		Message: issue.Title,
+	})
+	if err != nil {
+		slog.Error("failed to notify close requester", "issue_id", issue.ID, "error", err)
+	}
+}
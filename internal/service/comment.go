@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// CommentStore defines the comment data access interface consumed by services.
+type CommentStore interface {
+	Create(ctx context.Context, comment domain.Comment) (*domain.Comment, error)
+	FindByID(ctx context.Context, id int64) (*domain.Comment, error)
+	ListByIssue(ctx context.Context, issueID int64, after domain.ListPosition, limit int, includeDeleted bool) ([]domain.Comment, error)
+	SetHidden(ctx context.Context, id int64, by *int64) error
+	SoftDelete(ctx context.Context, id, by int64) error
+	Restore(ctx context.Context, id int64, deletedSince time.Time) (bool, error)
+}
+
+const defaultRestoreWindow = 24 * time.Hour
+
+// CommentService handles issue comment business logic.
+type CommentService struct {
+	projects      ProjectStore
+	issues        IssueStore
+	comments      CommentStore
+	events        EventStore
+	refs          ReferenceStore
+	restoreWindow time.Duration
+}
+
+// CommentOption configures a CommentService.
+type CommentOption func(*CommentService)
+
+// WithRestoreWindow sets how long after deletion a comment can be restored.
+func WithRestoreWindow(d time.Duration) CommentOption {
+	return func(s *CommentService) {
+		if d > 0 {
+			s.restoreWindow = d
+		}
+	}
+}
+
+// NewCommentService creates a new CommentService.
+func NewCommentService(projects ProjectStore, issues IssueStore, comments CommentStore, events EventStore, refs ReferenceStore, opts ...CommentOption) *CommentService {
+	s := &CommentService{
+		projects:      projects,
+		issues:        issues,
+		comments:      comments,
+		events:        events,
+		refs:          refs,
+		restoreWindow: defaultRestoreWindow,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CommentPage is a single page of comments.
+type CommentPage struct {
+	Comments   []domain.Comment
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// List returns a page of comments on an issue. Hidden comments are masked
+// for everyone except project admins. When includeDeleted is set, deleted
+// comments are returned as tombstones to keep threads readable.
+func (s *CommentService) List(ctx context.Context, userID, projectID, issueID int64, after domain.ListPosition, limit int, includeDeleted bool) (*CommentPage, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	comments, err := s.comments.ListByIssue(ctx, issueID, after, limit, includeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+
+	page := &CommentPage{Comments: comments}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		page.HasNext = true
+		last := page.Comments[len(page.Comments)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+
+	for i, c := range page.Comments {
+		page.Comments[i] = presentComment(role, c)
+	}
+	return page, nil
+}
+
+// presentComment returns a comment as a member with role sees it: admins
+// see hidden comments, flagged as hidden, and everyone else their mask.
+func presentComment(role domain.ProjectRole, c domain.Comment) domain.Comment {
+	if role.CanAdmin() && !c.Deleted() {
+		c.Hidden = c.HiddenAt != nil
+		return c
+	}
+	return c.Masked()
+}
+
+// Create adds a comment to an issue.
+func (s *CommentService) Create(ctx context.Context, userID, projectID, issueID int64, body string) (*domain.Comment, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.comments.Create(ctx, domain.Comment{
+		IssueID:  issueID,
+		AuthorID: userID,
+		Body:     body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %w", err)
+	}
+
+	recordEvent(ctx, s.events, domain.IssueEvent{
+		ProjectID: projectID,
+		IssueID:   issueID,
+		ActorID:   &userID,
+		Type:      domain.EventCommentCreated,
+		Data:      domain.EventData{"comment_id": comment.ID},
+	})
+	syncReferences(ctx, s.refs, issueID, &comment.ID, comment.Body)
+	return comment, nil
+}
+
+// Delete soft-deletes a comment. Authors may delete their own comments and
+// project admins may delete any comment.
+func (s *CommentService) Delete(ctx context.Context, userID, projectID, commentID int64) error {
+	comment, err := s.authorizeAuthorOrAdmin(ctx, userID, projectID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.Deleted() {
+		return nil
+	}
+	if err := s.comments.SoftDelete(ctx, commentID, userID); err != nil {
+		return fmt.Errorf("delete comment: %w", err)
+	}
+	return nil
+}
+
+// Restore undoes a comment deletion within the restore window. Authors may
+// restore their own comments and project admins may restore any comment.
+func (s *CommentService) Restore(ctx context.Context, userID, projectID, commentID int64) error {
+	comment, err := s.authorizeAuthorOrAdmin(ctx, userID, projectID, commentID)
+	if err != nil {
+		return err
+	}
+	if !comment.Deleted() {
+		return nil
+	}
+
+	restored, err := s.comments.Restore(ctx, commentID, time.Now().Add(-s.restoreWindow))
+	if err != nil {
+		return fmt.Errorf("restore comment: %w", err)
+	}
+	if !restored {
+		return fmt.Errorf("%w: restore window of %s has passed", domain.ErrConflict, s.restoreWindow)
+	}
+	return nil
+}
+
+func (s *CommentService) authorizeAuthorOrAdmin(ctx context.Context, userID, projectID, commentID int64) (*domain.Comment, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := findCommentInProject(ctx, s.issues, s.comments, projectID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID && !role.CanAdmin() {
+		return nil, domain.ErrForbidden
+	}
+	return comment, nil
+}
+
+// findCommentInProject loads a comment and verifies it belongs to an issue in the project.
+func findCommentInProject(ctx context.Context, issues IssueStore, comments CommentStore, projectID, commentID int64) (*domain.Comment, error) {
+	comment, err := comments.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, issues, projectID, comment.IssueID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// maxDeviceTokenLength bounds device tokens. FCM registration tokens are the
+// longest in practice, at a few hundred bytes.
+const maxDeviceTokenLength = 4096
+
+// DeviceStore defines the push device data access interface consumed by services.
+type DeviceStore interface {
+	Register(ctx context.Context, d domain.Device) (*domain.Device, error)
+	ListForUser(ctx context.Context, userID int64) ([]domain.Device, error)
+	UsersWithDevices(ctx context.Context, userIDs []int64) ([]int64, error)
+	Delete(ctx context.Context, userID, id int64) error
+	Forget(ctx context.Context, id int64) error
+}
+
+// DeviceService manages the devices users receive push notifications on.
+type DeviceService struct {
+	devices DeviceStore
+}
+
+// NewDeviceService creates a new DeviceService.
+func NewDeviceService(devices DeviceStore) *DeviceService {
+	return &DeviceService{devices: devices}
+}
+
+// Register records a device token for the user. Registering a token again
+// refreshes it, and moves it to the user if it belonged to someone else.
+func (s *DeviceService) Register(ctx context.Context, userID int64, platform domain.PushPlatform, token string) (*domain.Device, error) {
+	if !platform.Valid() {
+		return nil, &domain.ValidationError{Field: "platform", Message: "must be fcm or apns"}
+	}
+	if len(token) > maxDeviceTokenLength {
+		return nil, &domain.ValidationError{Field: "token", Message: "is too long"}
+	}
+	return s.devices.Register(ctx, domain.Device{UserID: userID, Platform: platform, Token: token})
+}
+
+// List returns the user's devices.
+func (s *DeviceService) List(ctx context.Context, userID int64) ([]domain.Device, error) {
+	return s.devices.ListForUser(ctx, userID)
+}
+
+// Unregister removes one of the user's devices.
+func (s *DeviceService) Unregister(ctx context.Context, userID, id int64) error {
+	return s.devices.Delete(ctx, userID, id)
+}
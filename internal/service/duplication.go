@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// DuplicationStore defines the project duplication data access interface
+// consumed by DuplicationService.
+type DuplicationStore interface {
+	OpenIssueCount(ctx context.Context, projectID int64) (int, error)
+	Duplicate(ctx context.Context, sourceID int64, project domain.Project, issues domain.IssueCopy, userID int64) (*domain.Project, *domain.ProjectDuplication, error)
+	ForProject(ctx context.Context, projectID int64) (*domain.ProjectDuplication, error)
+	Running(ctx context.Context) ([]domain.ProjectDuplication, error)
+	CopyBatch(ctx context.Context, duplication domain.ProjectDuplication, limit int) (int, error)
+	Finish(ctx context.Context, duplicationID int64) error
+}
+
+// DuplicationService duplicates projects and copies the open issues of large
+// ones in the background.
+type DuplicationService struct {
+	projects     ProjectStore
+	orgs         OrganizationStore
+	duplications DuplicationStore
+	batchSize    int
+	interval     time.Duration
+}
+
+// NewDuplicationService creates a new DuplicationService. Projects with at
+// most batchSize open issues are duplicated at once; larger ones have their
+// issues copied batchSize at a time, every interval.
+func NewDuplicationService(projects ProjectStore, orgs OrganizationStore, duplications DuplicationStore, batchSize int, interval time.Duration) *DuplicationService {
+	return &DuplicationService{
+		projects:     projects,
+		orgs:         orgs,
+		duplications: duplications,
+		batchSize:    batchSize,
+		interval:     interval,
+	}
+}
+
+// Duplicate creates a project owned by the user in the source project's
+// organization, with the source's description, settings and labels. Settings
+// include its issue templates and automation rules. If withIssues is set,
+// its open issues are copied too; the returned duplication tracks the copy
+// when it runs in the background. Only admins of the source may duplicate it.
+func (s *DuplicationService) Duplicate(ctx context.Context, userID, projectID int64, name string, key *string, withIssues bool) (*domain.Project, *domain.ProjectDuplication, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, nil, err
+	}
+	if key != nil && !domain.ValidProjectKey(*key) {
+		return nil, nil, invalidProjectKey()
+	}
+	source, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.OrganizationID != nil {
+		if _, err := s.orgs.RoleOf(ctx, *source.OrganizationID, userID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	issues := domain.IssueCopyNone
+	if withIssues {
+		open, err := s.duplications.OpenIssueCount(ctx, projectID)
+		if err != nil {
+			return nil, nil, err
+		}
+		issues = domain.IssueCopyInline
+		if open > s.batchSize {
+			issues = domain.IssueCopyBackground
+		}
+	}
+
+	project := domain.Project{
+		Name:           name,
+		Key:            key,
+		Description:    source.Description,
+		OwnerID:        userID,
+		OrganizationID: source.OrganizationID,
+		Settings:       source.Settings,
+	}
+	created, duplication, err := s.duplications.Duplicate(ctx, projectID, project, issues, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if duplication != nil {
+		slog.Info("project duplication started", "duplication_id", duplication.ID,
+			"source_project_id", projectID, "project_id", created.ID, "total", duplication.Total)
+	}
+	return created, duplication, nil
+}
+
+// Duplication returns the duplication filling a project the user can access.
+func (s *DuplicationService) Duplication(ctx context.Context, userID, projectID int64) (*domain.ProjectDuplication, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.duplications.ForProject(ctx, projectID)
+}
+
+// Run copies a batch of issues for each running duplication every interval
+// until ctx is cancelled. It must run on a single instance at a time.
+func (s *DuplicationService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.step(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// step copies the next batch of every running duplication and completes
+// those with nothing left to copy. A failed batch is retried next step.
+func (s *DuplicationService) step(ctx context.Context) {
+	duplications, err := s.duplications.Running(ctx)
+	if err != nil {
+		slog.Error("project duplication lookup failed", "error", err)
+		return
+	}
+
+	for _, d := range duplications {
+		n, err := s.duplications.CopyBatch(ctx, d, s.batchSize)
+		if err != nil {
+			slog.Warn("project duplication batch will be retried", "duplication_id", d.ID, "after_issue_id", d.LastIssueID, "error", err)
+			continue
+		}
+		if n > 0 {
+			continue
+		}
+		if err := s.duplications.Finish(ctx, d.ID); err != nil {
+			slog.Error("project duplication completion failed", "duplication_id", d.ID, "error", err)
+			continue
+		}
+		slog.Info("project duplication completed", "duplication_id", d.ID, "copied", d.Copied)
+	}
+}
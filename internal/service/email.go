@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/mailer"
+)
+
+const (
+	emailCursor    = "email_dispatch"
+	emailBatchSize = 500
+)
+
+// EmailQueue defines the notification data access interface consumed by EmailDispatcher.
+type EmailQueue interface {
+	ListAfter(ctx context.Context, after domain.LogPosition, limit int) ([]domain.Notification, error)
+	LatestPosition(ctx context.Context) (domain.LogPosition, error)
+	QueueEmails(ctx context.Context, items []domain.EmailItem) error
+	TakeDueEmails(ctx context.Context, limit int) ([]domain.Notification, error)
+}
+
+// EmailDispatcher emails new notifications to users who turned email on
+// for their type in their preferences. Like pushes, an email that falls in
+// the user's quiet hours or do-not-disturb is held until it ends, is dropped
+// if the user reads or snoozes the notification meanwhile, and is sent at
+// most once.
+type EmailDispatcher struct {
+	notifications EmailQueue
+	preferences   *NotificationService
+	users         UserStore
+	issues        IssueStore
+	cursors       CursorStore
+	sender        mailer.Sender
+	linkBase      string
+	interval      time.Duration
+	replySecret   []byte
+	replyDomain   string
+}
+
+// EmailOption configures an EmailDispatcher.
+type EmailOption func(*EmailDispatcher)
+
+// WithReplyTo makes emails about an issue reply to a signed address at
+// domain, so that replies can be posted as comments by EmailReplyService.
+func WithReplyTo(secret []byte, domain string) EmailOption {
+	return func(d *EmailDispatcher) {
+		d.replySecret = secret
+		d.replyDomain = domain
+	}
+}
+
+// NewEmailDispatcher creates an EmailDispatcher that polls every interval.
+// Emails link to issues in the web app at linkBase.
+func NewEmailDispatcher(notifications EmailQueue, preferences *NotificationService, users UserStore, issues IssueStore,
+	cursors CursorStore, sender mailer.Sender, linkBase string, interval time.Duration, opts ...EmailOption) *EmailDispatcher {
+	d := &EmailDispatcher{
+		notifications: notifications,
+		preferences:   preferences,
+		users:         users,
+		issues:        issues,
+		cursors:       cursors,
+		sender:        sender,
+		linkBase:      strings.TrimRight(linkBase, "/"),
+		interval:      interval,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run emails notifications until ctx is cancelled. It must run on a single
+// instance at a time. On its first run it starts from the newest
+// notification rather than emailing the whole history.
+func (d *EmailDispatcher) Run(ctx context.Context) error {
+	position, err := d.cursors.Get(ctx, emailCursor)
+	if err != nil {
+		return err
+	}
+	if position.IsZero() {
+		if position, err = d.notifications.LatestPosition(ctx); err != nil {
+			return err
+		}
+		if err := d.cursors.Set(ctx, emailCursor, position); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if position, err = d.queue(ctx, position); err != nil && ctx.Err() == nil {
+			slog.Error("email queueing failed", "position", position, "error", err)
+		}
+		if err := d.sendDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("email delivery failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// queue schedules every new notification its user wants emailed and
+// returns the new position.
+func (d *EmailDispatcher) queue(ctx context.Context, position domain.LogPosition) (domain.LogPosition, error) {
+	for {
+		notifications, err := d.notifications.ListAfter(ctx, position, emailBatchSize)
+		if err != nil {
+			return position, fmt.Errorf("list notifications: %w", err)
+		}
+		if len(notifications) == 0 {
+			return position, nil
+		}
+
+		preferences := make(map[int64]*domain.NotificationPreferences)
+		now := time.Now()
+		var items []domain.EmailItem
+		for _, n := range notifications {
+			p, ok := preferences[n.UserID]
+			if !ok {
+				if p, err = d.preferences.Preferences(ctx, n.UserID); err != nil {
+					return position, err
+				}
+				preferences[n.UserID] = p
+			}
+			if p.Email.Wants(n.Type) {
+				items = append(items, domain.EmailItem{NotificationID: n.ID, DeliverAt: p.DeliverAt(now)})
+			}
+		}
+		if err := d.notifications.QueueEmails(ctx, items); err != nil {
+			return position, err
+		}
+
+		position = notifications[len(notifications)-1].Position()
+		if err := d.cursors.Set(ctx, emailCursor, position); err != nil {
+			return position, err
+		}
+		if len(notifications) < emailBatchSize {
+			return position, nil
+		}
+	}
+}
+
+// sendDue emails every queued notification that is due.
+func (d *EmailDispatcher) sendDue(ctx context.Context) error {
+	for {
+		due, err := d.notifications.TakeDueEmails(ctx, emailBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, n := range due {
+			if err := d.send(ctx, n); err != nil {
+				slog.Warn("email failed", "notification_id", n.ID, "user_id", n.UserID, "error", err)
+			}
+		}
+		if len(due) < emailBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// notificationEmail is the data the notification email templates are
+// rendered with.
+type notificationEmail struct {
+	Title    string
+	Message  string
+	IssueURL string
+	// Reason tells the recipient why they got the email.
+	Reason string
+}
+
+// send renders and emails one notification to its user.
+func (d *EmailDispatcher) send(ctx context.Context, n domain.Notification) error {
+	name := string(n.Type) + ".html"
+	if !mailer.Has(name) {
+		return nil
+	}
+	user, err := d.users.FindByID(ctx, n.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+
+	data := notificationEmail{
+		Title:   n.Title,
+		Message: n.Message,
+		Reason:  "You are receiving this because you turned on email for these notifications. Change this in your notification preferences.",
+	}
+	if n.IssueID != nil && d.linkBase != "" {
+		if issue, err := d.issues.FindByID(ctx, *n.IssueID); err == nil {
+			data.IssueURL = fmt.Sprintf("%s/projects/%d/issues/%d", d.linkBase, issue.ProjectID, issue.ID)
+		}
+	}
+	html, err := mailer.Render(name, data)
+	if err != nil {
+		return err
+	}
+	text := n.Title + "\n\n" + n.Message
+	if data.IssueURL != "" {
+		text += "\n\n" + data.IssueURL
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: n.Title + ": " + n.Message,
+		HTML:    html,
+		Text:    text,
+	}
+	if n.IssueID != nil && d.replyDomain != "" {
+		msg.ReplyTo = mailer.ReplyAddress(d.replySecret, d.replyDomain, n.UserID, *n.IssueID)
+	}
+	return d.sender.Send(ctx, msg)
+}
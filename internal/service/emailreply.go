@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/mailer"
+)
+
+// CommentCreator posts comments on behalf of users, applying the same
+// rules as the comments API.
+type CommentCreator interface {
+	Create(ctx context.Context, userID, projectID, issueID int64, body string) (*domain.Comment, error)
+}
+
+// InboundEmail is an email received at a reply address, as handed over by
+// the mail relay.
+type InboundEmail struct {
+	From string
+	To   []string
+	Text string
+}
+
+// EmailReplyService posts replies to notification emails as comments on
+// the issue the email was about.
+type EmailReplyService struct {
+	users    UserStore
+	issues   IssueStore
+	comments CommentCreator
+	secret   []byte
+}
+
+// NewEmailReplyService creates an EmailReplyService that accepts reply
+// addresses signed with secret.
+func NewEmailReplyService(users UserStore, issues IssueStore, comments CommentCreator, secret []byte) *EmailReplyService {
+	return &EmailReplyService{users: users, issues: issues, comments: comments, secret: secret}
+}
+
+// Receive posts the text of a reply as a comment by the user its reply
+// address was made for. The reply must come from that user's email
+// address, so a leaked reply address cannot be used by others, and the
+// user must still be able to comment on the issue.
+func (s *EmailReplyService) Receive(ctx context.Context, email InboundEmail) (*domain.Comment, error) {
+	var userID, issueID int64
+	err := mailer.ErrInvalidReplyAddress
+	for _, to := range email.To {
+		if userID, issueID, err = mailer.ParseReplyAddress(s.secret, to); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: no valid reply address among the recipients", domain.ErrForbidden)
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrForbidden
+		}
+		return nil, err
+	}
+	from, err := mail.ParseAddress(email.From)
+	if err != nil || !strings.EqualFold(from.Address, user.Email) {
+		return nil, fmt.Errorf("%w: the reply was not sent from the notified address", domain.ErrForbidden)
+	}
+
+	issue, err := s.issues.FindByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	body := mailer.StripReply(email.Text)
+	if body == "" {
+		return nil, &domain.ValidationError{Field: "text", Message: "the reply is empty"}
+	}
+	return s.comments.Create(ctx, userID, issue.ProjectID, issueID, body)
+}
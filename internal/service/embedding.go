@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// Embedder computes embeddings of texts with one model.
+type Embedder interface {
+	Model() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbeddingStore defines the embedding data access interface consumed by services.
+type EmbeddingStore interface {
+	StartBackfill(ctx context.Context, model string, userID int64) (*domain.EmbeddingBackfill, error)
+	LatestBackfill(ctx context.Context) (*domain.EmbeddingBackfill, error)
+	IssuesAfter(ctx context.Context, afterID int64, limit int) ([]domain.Issue, error)
+	SaveBatch(ctx context.Context, backfillID int64, model string, issueIDs []int64, embeddings [][]float32) error
+	RecordFailure(ctx context.Context, backfillID int64, reason string) error
+	FinishBackfill(ctx context.Context, backfillID int64, status domain.BackfillStatus) (*domain.EmbeddingBackfill, error)
+}
+
+// EmbeddingService lets administrators backfill issue embeddings and runs
+// the backfill in the background.
+type EmbeddingService struct {
+	users      UserStore
+	embeddings EmbeddingStore
+	embedder   Embedder
+	batchSize  int
+	interval   time.Duration
+}
+
+// NewEmbeddingService creates a new EmbeddingService. The backfill embeds at
+// most batchSize issues every interval, to stay within the provider's rate
+// limits.
+func NewEmbeddingService(users UserStore, embeddings EmbeddingStore, embedder Embedder, batchSize int, interval time.Duration) *EmbeddingService {
+	return &EmbeddingService{
+		users:      users,
+		embeddings: embeddings,
+		embedder:   embedder,
+		batchSize:  batchSize,
+		interval:   interval,
+	}
+}
+
+// StartBackfill starts computing embeddings for every existing issue. Only
+// one backfill runs at a time.
+func (s *EmbeddingService) StartBackfill(ctx context.Context, userID int64) (*domain.EmbeddingBackfill, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	backfill, err := s.embeddings.StartBackfill(ctx, s.embedder.Model(), userID)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("embedding backfill started", "backfill_id", backfill.ID, "total", backfill.Total, "user_id", userID)
+	return backfill, nil
+}
+
+// Backfill returns the most recent backfill and its progress.
+func (s *EmbeddingService) Backfill(ctx context.Context, userID int64) (*domain.EmbeddingBackfill, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	return s.embeddings.LatestBackfill(ctx)
+}
+
+// CancelBackfill stops the running backfill. Embeddings already computed
+// are kept.
+func (s *EmbeddingService) CancelBackfill(ctx context.Context, userID int64) (*domain.EmbeddingBackfill, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	backfill, err := s.embeddings.LatestBackfill(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if backfill.Status != domain.BackfillStatusRunning {
+		return nil, fmt.Errorf("%w: no embedding backfill is running", domain.ErrConflict)
+	}
+	return s.embeddings.FinishBackfill(ctx, backfill.ID, domain.BackfillStatusCancelled)
+}
+
+// Run processes the running backfill, one batch per interval, until ctx is
+// cancelled. It must run on a single instance at a time.
+func (s *EmbeddingService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.step(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// step embeds the next batch of the running backfill, if any. Backfills
+// started for another model are left to an instance that has it.
+func (s *EmbeddingService) step(ctx context.Context) {
+	backfill, err := s.embeddings.LatestBackfill(ctx)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			slog.Error("embedding backfill lookup failed", "error", err)
+		}
+		return
+	}
+	if backfill.Status != domain.BackfillStatusRunning || backfill.Model != s.embedder.Model() {
+		return
+	}
+
+	issues, err := s.embeddings.IssuesAfter(ctx, backfill.LastIssueID, s.batchSize)
+	if err != nil {
+		slog.Error("embedding backfill batch failed", "backfill_id", backfill.ID, "error", err)
+		return
+	}
+	if len(issues) == 0 {
+		if _, err := s.embeddings.FinishBackfill(ctx, backfill.ID, domain.BackfillStatusCompleted); err != nil {
+			slog.Error("embedding backfill completion failed", "backfill_id", backfill.ID, "error", err)
+			return
+		}
+		slog.Info("embedding backfill completed", "backfill_id", backfill.ID, "processed", backfill.Processed)
+		return
+	}
+
+	ids := make([]int64, len(issues))
+	texts := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+		texts[i] = embeddingText(issue)
+	}
+
+	embeddings, err := s.embedder.Embed(ctx, texts)
+	if err == nil {
+		err = s.embeddings.SaveBatch(ctx, backfill.ID, backfill.Model, ids, embeddings)
+	}
+	if err != nil {
+		slog.Warn("embedding backfill batch will be retried", "backfill_id", backfill.ID, "after_issue_id", backfill.LastIssueID, "error", err)
+		if err := s.embeddings.RecordFailure(ctx, backfill.ID, err.Error()); err != nil {
+			slog.Error("embedding backfill failure not recorded", "backfill_id", backfill.ID, "error", err)
+		}
+	}
+}
+
+// embeddingText is the text embedded for an issue: its title and body.
+func embeddingText(issue domain.Issue) string {
+	if issue.Body == nil || strings.TrimSpace(*issue.Body) == "" {
+		return issue.Title
+	}
+	return issue.Title + "\n\n" + strings.TrimSpace(*issue.Body)
+}
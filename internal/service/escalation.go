@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/paging"
+)
+
+const (
+	escalationCursor      = "escalation"
+	escalationBatchSize   = 100
+	escalationMaxAttempts = 5
+)
+
+// escalationEvents are the events that open and close AI job failure
+// incidents. Failed events are only recorded once a job has no attempts left.
+var escalationEvents = []domain.EventType{
+	domain.EventAIJobCompleted,
+	domain.EventAIJobFailed,
+}
+
+// EscalationEventSource defines the issue event interface consumed by Escalator.
+type EscalationEventSource interface {
+	ListAfter(ctx context.Context, after domain.LogPosition, types []domain.EventType, limit int) ([]domain.IssueEvent, error)
+	LatestPosition(ctx context.Context) (domain.LogPosition, error)
+}
+
+// AIBacklogSource defines the AI job queue interface consumed by Escalator.
+type AIBacklogSource interface {
+	CriticalBacklog(ctx context.Context) ([]domain.AIJobBacklog, error)
+}
+
+// Escalator pages the on-call rotation about AI processing on critical
+// projects. A job that fails for good opens an incident keyed by its issue,
+// which the next job to complete on the issue resolves. A project whose
+// queue stays at or above depth jobs for longer than after opens an
+// incident keyed by the project, resolved once the queue drops below depth.
+//
+// Which queues have been paged is kept in memory, so a queue incident open
+// when the process stops has to be resolved by hand.
+type Escalator struct {
+	events   EscalationEventSource
+	projects ProjectStore
+	backlog  AIBacklogSource
+	cursors  CursorStore
+	pagers   []paging.Pager
+	linkBase string
+	interval time.Duration
+	depth    int
+	after    time.Duration
+
+	failures map[int64]int
+	deep     map[int64]time.Time
+	paged    map[int64]bool
+}
+
+// NewEscalator creates an Escalator that polls every interval. A depth of
+// zero disables queue alerts. Alerts link to issues in the web app at
+// linkBase.
+func NewEscalator(events EscalationEventSource, projects ProjectStore, backlog AIBacklogSource, cursors CursorStore, pagers []paging.Pager, linkBase string, interval time.Duration, depth int, after time.Duration) *Escalator {
+	return &Escalator{
+		events:   events,
+		projects: projects,
+		backlog:  backlog,
+		cursors:  cursors,
+		pagers:   pagers,
+		linkBase: strings.TrimRight(linkBase, "/"),
+		interval: interval,
+		depth:    depth,
+		after:    after,
+		failures: make(map[int64]int),
+		deep:     make(map[int64]time.Time),
+		paged:    make(map[int64]bool),
+	}
+}
+
+// Run escalates until ctx is cancelled. It must run on a single instance at
+// a time. On its first run it starts from the newest event rather than
+// paging about past failures.
+func (e *Escalator) Run(ctx context.Context) error {
+	position, err := e.cursors.Get(ctx, escalationCursor)
+	if err != nil {
+		return err
+	}
+	if position.IsZero() {
+		if position, err = e.events.LatestPosition(ctx); err != nil {
+			return err
+		}
+		if err := e.cursors.Set(ctx, escalationCursor, position); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if position, err = e.escalateFailures(ctx, position); err != nil && ctx.Err() == nil {
+			slog.Error("ai failure escalation failed", "position", position, "error", err)
+		}
+		if e.depth > 0 {
+			if err := e.checkQueues(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				slog.Error("ai queue escalation failed", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// escalateFailures handles events after position and returns the new
+// position. It stops at the first event that must be retried.
+func (e *Escalator) escalateFailures(ctx context.Context, position domain.LogPosition) (domain.LogPosition, error) {
+	for {
+		events, err := e.events.ListAfter(ctx, position, escalationEvents, escalationBatchSize)
+		if err != nil {
+			return position, fmt.Errorf("list events: %w", err)
+		}
+		if len(events) == 0 {
+			return position, nil
+		}
+
+		critical := make(map[int64]*domain.Project)
+		for _, ev := range events {
+			project, ok := critical[ev.ProjectID]
+			if !ok {
+				if project, err = e.criticalProject(ctx, ev.ProjectID); err != nil {
+					return position, fmt.Errorf("find project: %w", err)
+				}
+				critical[ev.ProjectID] = project
+			}
+			if project != nil && !e.escalate(ctx, ev, project) {
+				return position, e.cursors.Set(ctx, escalationCursor, position)
+			}
+			position = ev.Position()
+		}
+		if err := e.cursors.Set(ctx, escalationCursor, position); err != nil {
+			return position, err
+		}
+		if len(events) < escalationBatchSize {
+			return position, nil
+		}
+	}
+}
+
+// criticalProject returns the project if it is marked critical, or nil if
+// it is not or no longer exists.
+func (e *Escalator) criticalProject(ctx context.Context, projectID int64) (*domain.Project, error) {
+	project, err := e.projects.FindByID(ctx, projectID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !project.Settings.Critical {
+		return nil, nil
+	}
+	return project, nil
+}
+
+// escalate triggers or resolves the incident for an event's issue. It
+// reports false if the event should be tried again.
+func (e *Escalator) escalate(ctx context.Context, ev domain.IssueEvent, project *domain.Project) bool {
+	key := fmt.Sprintf("issues-ai-failed-%d", ev.IssueID)
+	var send func(paging.Pager) error
+	if ev.Type == domain.EventAIJobFailed {
+		alert := paging.Alert{
+			DedupKey: key,
+			Summary: fmt.Sprintf("AI job %v on issue #%d in %s failed: %v",
+				ev.Data["job_id"], ev.IssueID, project.Name, ev.Data["error"]),
+			Severity: paging.SeverityError,
+			Link:     e.issueLink(ev.ProjectID, ev.IssueID),
+			Details: map[string]any{
+				"project_id": ev.ProjectID,
+				"issue_id":   ev.IssueID,
+				"job_id":     ev.Data["job_id"],
+				"mode":       ev.Data["mode"],
+			},
+		}
+		send = func(p paging.Pager) error { return p.Trigger(ctx, alert) }
+	} else {
+		send = func(p paging.Pager) error { return p.Resolve(ctx, key) }
+	}
+
+	failed := false
+	for _, p := range e.pagers {
+		if err := send(p); err != nil {
+			failed = true
+			slog.Warn("page failed",
+				"pager", p.Name(),
+				"event_id", ev.ID,
+				"attempt", e.failures[ev.ID]+1,
+				"error", err,
+			)
+		}
+	}
+	if !failed {
+		delete(e.failures, ev.ID)
+		return true
+	}
+
+	e.failures[ev.ID]++
+	if e.failures[ev.ID] < escalationMaxAttempts {
+		return false
+	}
+	slog.Error("page dropped", "event_id", ev.ID, "attempts", e.failures[ev.ID])
+	delete(e.failures, ev.ID)
+	return true
+}
+
+// checkQueues pages about critical projects whose queue has been at or
+// above the depth threshold for longer than allowed, and resolves the
+// incidents of queues that have drained.
+func (e *Escalator) checkQueues(ctx context.Context, now time.Time) error {
+	backlog, err := e.backlog.CriticalBacklog(ctx)
+	if err != nil {
+		return fmt.Errorf("ai job backlog: %w", err)
+	}
+
+	deep := make(map[int64]bool, len(backlog))
+	for _, b := range backlog {
+		if b.Pending < e.depth {
+			continue
+		}
+		deep[b.ProjectID] = true
+		since, ok := e.deep[b.ProjectID]
+		if !ok {
+			e.deep[b.ProjectID] = now
+			continue
+		}
+		if e.paged[b.ProjectID] || now.Sub(since) < e.after {
+			continue
+		}
+		if e.pageQueue(ctx, b, now.Sub(since)) {
+			e.paged[b.ProjectID] = true
+		}
+	}
+
+	for projectID := range e.deep {
+		if deep[projectID] {
+			continue
+		}
+		if e.paged[projectID] && !e.resolveQueue(ctx, projectID) {
+			continue
+		}
+		delete(e.deep, projectID)
+		delete(e.paged, projectID)
+	}
+	return nil
+}
+
+// pageQueue triggers the queue incident of a project. It reports whether
+// every pager accepted it.
+func (e *Escalator) pageQueue(ctx context.Context, b domain.AIJobBacklog, held time.Duration) bool {
+	name := fmt.Sprintf("project #%d", b.ProjectID)
+	if project, err := e.projects.FindByID(ctx, b.ProjectID); err == nil {
+		name = project.Name
+	}
+	alert := paging.Alert{
+		DedupKey: queueDedupKey(b.ProjectID),
+		Summary: fmt.Sprintf("%d AI jobs queued in %s for over %s",
+			b.Pending, name, held.Round(time.Minute)),
+		Severity: paging.SeverityWarning,
+		Details: map[string]any{
+			"project_id":             b.ProjectID,
+			"pending":                b.Pending,
+			"threshold":              e.depth,
+			"oldest_pending_seconds": b.OldestPendingSeconds,
+		},
+	}
+	if e.linkBase != "" {
+		alert.Link = fmt.Sprintf("%s/projects/%d", e.linkBase, b.ProjectID)
+	}
+
+	ok := true
+	for _, p := range e.pagers {
+		if err := p.Trigger(ctx, alert); err != nil {
+			ok = false
+			slog.Warn("page failed", "pager", p.Name(), "project_id", b.ProjectID, "error", err)
+		}
+	}
+	return ok
+}
+
+// resolveQueue resolves the queue incident of a project. It reports whether
+// every pager accepted it.
+func (e *Escalator) resolveQueue(ctx context.Context, projectID int64) bool {
+	ok := true
+	for _, p := range e.pagers {
+		if err := p.Resolve(ctx, queueDedupKey(projectID)); err != nil {
+			ok = false
+			slog.Warn("resolve failed", "pager", p.Name(), "project_id", projectID, "error", err)
+		}
+	}
+	return ok
+}
+
+func queueDedupKey(projectID int64) string {
+	return fmt.Sprintf("issues-ai-queue-%d", projectID)
+}
+
+// issueLink returns the web app URL of an issue, or "" without a link base.
+func (e *Escalator) issueLink(projectID, issueID int64) string {
+	if e.linkBase == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/projects/%d/issues/%d", e.linkBase, projectID, issueID)
+}
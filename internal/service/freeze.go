@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const maxFreezeReason = 500
+
+// FreezeStore defines the freeze window data access interface consumed by
+// FreezeService and the services that enforce freezes.
+type FreezeStore interface {
+	Create(ctx context.Context, w domain.FreezeWindow) (*domain.FreezeWindow, error)
+	ListUpcoming(ctx context.Context, projectID int64, now time.Time) ([]domain.FreezeWindow, error)
+	Active(ctx context.Context, projectID int64, at time.Time) (*domain.FreezeWindow, error)
+	Delete(ctx context.Context, projectID, id int64) error
+}
+
+// FreezeService schedules project freeze windows, during which only project
+// admins may change issue statuses or run AI.
+type FreezeService struct {
+	projects ProjectStore
+	freezes  FreezeStore
+	audit    AuditStore
+}
+
+// NewFreezeService creates a new FreezeService.
+func NewFreezeService(projects ProjectStore, freezes FreezeStore, audit AuditStore) *FreezeService {
+	return &FreezeService{projects: projects, freezes: freezes, audit: audit}
+}
+
+// Create schedules a freeze window. Only project admins may schedule
+// freezes, and a window must end after it starts and in the future.
+func (s *FreezeService) Create(ctx context.Context, userID, projectID int64, w domain.FreezeWindow) (*domain.FreezeWindow, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if !w.EndsAt.After(w.StartsAt) {
+		return nil, &domain.ValidationError{Field: "ends_at", Message: "must be after starts_at"}
+	}
+	if !w.EndsAt.After(time.Now()) {
+		return nil, &domain.ValidationError{Field: "ends_at", Message: "must be in the future"}
+	}
+	if len(w.Reason) > maxFreezeReason {
+		return nil, &domain.ValidationError{Field: "reason", Message: "must be at most 500 characters"}
+	}
+
+	w.ProjectID = projectID
+	w.CreatedBy = &userID
+	created, err := s.freezes.Create(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditFreezeScheduled, domain.AuditTargetFreeze, created.ID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// List returns a project's current and upcoming freeze windows to any
+// member, soonest first.
+func (s *FreezeService) List(ctx context.Context, userID, projectID int64) ([]domain.FreezeWindow, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.freezes.ListUpcoming(ctx, projectID, time.Now())
+}
+
+// Delete cancels a freeze window, ending it early if it is in effect. Only
+// project admins may cancel freezes.
+func (s *FreezeService) Delete(ctx context.Context, userID, projectID, freezeID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if err := s.freezes.Delete(ctx, projectID, freezeID); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditFreezeCancelled, domain.AuditTargetFreeze, freezeID)
+}
+
+// enforceFreeze returns a domain.FrozenError when the project is frozen and
+// the user is not a project admin. It is a no-op without a freeze store.
+func enforceFreeze(ctx context.Context, freezes FreezeStore, role domain.ProjectRole, projectID int64) error {
+	if freezes == nil || role.CanAdmin() {
+		return nil
+	}
+	window, err := freezes.Active(ctx, projectID, time.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &domain.FrozenError{Window: *window}
+}
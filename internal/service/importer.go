@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// MaxImportSize bounds the number of issues accepted by a single import.
+const MaxImportSize = 10000
+
+// NotificationStore defines the notification data access interface consumed by services.
+type NotificationStore interface {
+	FanOut(ctx context.Context, userIDs []int64, n domain.Notification) (int64, error)
+}
+
+// ImportService bulk-loads issues into projects.
+type ImportService struct {
+	projects      ProjectStore
+	issues        IssueStore
+	notifications NotificationStore
+}
+
+// NewImportService creates a new ImportService.
+func NewImportService(projects ProjectStore, issues IssueStore, notifications NotificationStore) *ImportService {
+	return &ImportService{projects: projects, issues: issues, notifications: notifications}
+}
+
+// Import creates issues in a project in bulk and notifies the other project
+// members once. Only project admins may import. Issues without a status are
+// opened; issues imported as done are recorded as closed by the importer.
+// Imports do not produce per-issue activity events.
+func (s *ImportService) Import(ctx context.Context, userID, projectID int64, issues []domain.Issue) (int64, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return 0, err
+	}
+	if len(issues) == 0 {
+		return 0, &domain.ValidationError{Field: "issues", Message: "must not be empty"}
+	}
+	if len(issues) > MaxImportSize {
+		return 0, &domain.ValidationError{
+			Field:   "issues",
+			Message: fmt.Sprintf("must contain at most %d issues", MaxImportSize),
+		}
+	}
+	if err := s.checkAssignees(ctx, projectID, issues); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for i := range issues {
+		issue := &issues[i]
+		issue.ProjectID = projectID
+		issue.CreatedBy = &userID
+		if issue.Status == "" {
+			issue.Status = domain.IssueStatusOpen
+		}
+		if issue.Status.Done() {
+			issue.ClosedBy = &userID
+			issue.ClosedAt = &now
+		}
+	}
+
+	n, err := s.issues.CreateMany(ctx, issues)
+	if err != nil {
+		return 0, fmt.Errorf("import issues: %w", err)
+	}
+
+	s.notifyMembers(ctx, userID, projectID, n)
+	return n, nil
+}
+
+// checkAssignees verifies every assignee can access the project.
+func (s *ImportService) checkAssignees(ctx context.Context, projectID int64, issues []domain.Issue) error {
+	checked := make(map[int64]bool)
+	for _, issue := range issues {
+		if issue.AssigneeID == nil || checked[*issue.AssigneeID] {
+			continue
+		}
+		id := *issue.AssigneeID
+		if _, err := authorizeProject(ctx, s.projects, id, projectID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return &domain.ValidationError{
+					Field:   "assignee_id",
+					Message: fmt.Sprintf("user %d is not a member of the project", id),
+				}
+			}
+			return err
+		}
+		checked[id] = true
+	}
+	return nil
+}
+
+// notifyMembers tells every other member about the import. Failures are
+// logged rather than returned since the issues are already stored.
+func (s *ImportService) notifyMembers(ctx context.Context, userID, projectID, count int64) {
+	members, err := s.projects.MemberIDs(ctx, projectID)
+	if err != nil {
+		slog.Error("failed to list members for import notification", "project_id", projectID, "error", err)
+		return
+	}
+
+	recipients := make([]int64, 0, len(members))
+	for _, id := range members {
+		if id != userID {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	_, err = s.notifications.FanOut(ctx, recipients, domain.Notification{
+		Type:    domain.NotificationIssueCreated,
+		Title:   "Issues imported",
+		Message: fmt.Sprintf("%d issues were imported into the project", count),
+	})
+	if err != nil {
+		slog.Error("failed to send import notifications", "project_id", projectID, "error", err)
+	}
+}
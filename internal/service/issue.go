@@ -0,0 +1,512 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/pagination"
+)
+
+const (
+	defaultPageSize = pagination.DefaultLimit
+	maxPageSize     = pagination.MaxLimit
+
+	defaultPinLimit = 3
+)
+
+// IssueStore defines the issue data access interface consumed by IssueService.
+type IssueStore interface {
+	FindByID(ctx context.Context, id int64) (*domain.Issue, error)
+	List(ctx context.Context, projectID int64, filter domain.IssueFilter) ([]domain.Issue, error)
+	ListPinned(ctx context.Context, projectID int64, filter domain.IssueFilter) ([]domain.Issue, error)
+	Each(ctx context.Context, projectID int64, filter domain.IssueFilter, fn func(domain.Issue) error) error
+	Create(ctx context.Context, issue domain.Issue) (*domain.Issue, error)
+	Clone(ctx context.Context, sourceID int64, issue domain.Issue, restricted bool) (*domain.Issue, error)
+	CreateMany(ctx context.Context, issues []domain.Issue) (int64, error)
+	ArchiveClosed(ctx context.Context) (int64, error)
+	Pin(ctx context.Context, projectID, issueID int64, limit int) (pinned, changed bool, err error)
+	Unpin(ctx context.Context, projectID, issueID int64) (bool, error)
+	Update(ctx context.Context, issue domain.Issue, pre domain.Precondition) (*domain.Issue, error)
+	Delete(ctx context.Context, projectID, issueID, by int64, pre domain.Precondition) error
+}
+
+// IssueService handles issue business logic.
+type IssueService struct {
+	projects ProjectStore
+	issues   IssueStore
+	audit    AuditStore
+	events   EventStore
+	refs     ReferenceStore
+	freezes  FreezeStore
+	pinLimit int
+}
+
+// IssueOption configures an IssueService.
+type IssueOption func(*IssueService)
+
+// WithPinLimit sets the maximum number of pinned issues per project.
+func WithPinLimit(n int) IssueOption {
+	return func(s *IssueService) {
+		if n > 0 {
+			s.pinLimit = n
+		}
+	}
+}
+
+// WithFreezeWindows makes status changes respect the project's freeze
+// windows.
+func WithFreezeWindows(freezes FreezeStore) IssueOption {
+	return func(s *IssueService) {
+		s.freezes = freezes
+	}
+}
+
+// NewIssueService creates a new IssueService.
+func NewIssueService(projects ProjectStore, issues IssueStore, audit AuditStore, events EventStore, refs ReferenceStore, opts ...IssueOption) *IssueService {
+	s := &IssueService{
+		projects: projects,
+		issues:   issues,
+		audit:    audit,
+		events:   events,
+		refs:     refs,
+		pinLimit: defaultPinLimit,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// IssuePage is a single page of issues. Pinned issues are only populated on
+// the first page and are excluded from Issues.
+type IssuePage struct {
+	Pinned     []domain.Issue
+	Issues     []domain.Issue
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// List returns a page of issues in a project the user can access.
+func (s *IssueService) List(ctx context.Context, userID, projectID int64, filter domain.IssueFilter) (*IssuePage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	filter.Limit = clampPageSize(filter.Limit)
+
+	page := &IssuePage{Pinned: []domain.Issue{}}
+	if filter.Cursor.IsZero() && filter.Pinned == nil {
+		pinned, err := s.issues.ListPinned(ctx, projectID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("list pinned issues: %w", err)
+		}
+		page.Pinned = pinned
+	}
+
+	if filter.Pinned == nil {
+		unpinned := false
+		filter.Pinned = &unpinned
+	}
+
+	issues, err := s.issues.List(ctx, projectID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list issues: %w", err)
+	}
+
+	page.Issues = issues
+	if len(issues) > filter.Limit {
+		page.Issues = issues[:filter.Limit]
+		page.HasNext = true
+		last := page.Issues[len(page.Issues)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// Create creates an issue in a project. Any project member may create
+// issues; the status defaults to open. An issue naming a Template that is a
+// form has its Form answers validated and rendered into the body, followed
+// by any body given.
+func (s *IssueService) Create(ctx context.Context, userID, projectID int64, issue domain.Issue) (*domain.Issue, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if issue.Template != nil || issue.Form != nil {
+		if err := s.fillForm(ctx, projectID, &issue); err != nil {
+			return nil, err
+		}
+	}
+
+	issue.ProjectID = projectID
+	issue.CreatedBy = &userID
+	if issue.Status == "" {
+		issue.Status = domain.IssueStatusOpen
+	}
+	if issue.Status.Done() {
+		issue.ClosedBy = &userID
+	}
+
+	created, err := s.issues.Create(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+
+	recordEvent(ctx, s.events, domain.IssueEvent{
+		ProjectID: projectID,
+		IssueID:   created.ID,
+		ActorID:   &userID,
+		Type:      domain.EventIssueCreated,
+	})
+	syncReferences(ctx, s.refs, created.ID, nil, created.Title, bodyText(created.Body))
+	return created, nil
+}
+
+// fillForm validates the answers to the issue's form and renders them into
+// its body.
+func (s *IssueService) fillForm(ctx context.Context, projectID int64, issue *domain.Issue) error {
+	if issue.Template == nil {
+		return &domain.ValidationError{Field: "template", Message: "is required with form"}
+	}
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	tmpl, ok := project.Settings.IssueTemplate(*issue.Template)
+	if !ok {
+		return &domain.ValidationError{Field: "template", Message: fmt.Sprintf("unknown template %q", *issue.Template)}
+	}
+	if len(tmpl.Fields) == 0 {
+		if issue.Form != nil {
+			return &domain.ValidationError{Field: "form", Message: fmt.Sprintf("template %q has no form", tmpl.Name)}
+		}
+		return nil
+	}
+
+	data, err := tmpl.Answer(issue.Form)
+	if err != nil {
+		return err
+	}
+	body := tmpl.Render(data)
+	if extra := strings.TrimSpace(bodyText(issue.Body)); extra != "" {
+		body += "\n" + extra + "\n"
+	}
+	issue.Form = data
+	issue.Body = &body
+	return nil
+}
+
+// Clone copies an issue's title, body and labels into a new open issue,
+// either in the same project or in targetProjectID. Comments and history are
+// not copied. The user must be a member of both projects, and restricted
+// labels are only copied for admins of both.
+func (s *IssueService) Clone(ctx context.Context, userID, projectID, issueID int64, targetProjectID *int64) (*domain.Issue, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	source, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, admin := projectID, role.CanAdmin()
+	if targetProjectID != nil && *targetProjectID != projectID {
+		target = *targetProjectID
+		targetRole, err := authorizeProject(ctx, s.projects, userID, target)
+		if err != nil {
+			return nil, err
+		}
+		admin = admin && targetRole.CanAdmin()
+	}
+
+	clone, err := s.issues.Clone(ctx, source.ID, domain.Issue{
+		ProjectID: target,
+		Title:     source.Title,
+		Body:      source.Body,
+		Status:    domain.IssueStatusOpen,
+		CreatedBy: &userID,
+		Template:  source.Template,
+		Form:      source.Form,
+	}, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	recordEvent(ctx, s.events, domain.IssueEvent{
+		ProjectID: target,
+		IssueID:   clone.ID,
+		ActorID:   &userID,
+		Type:      domain.EventIssueCreated,
+	})
+	syncReferences(ctx, s.refs, clone.ID, nil, clone.Title, bodyText(clone.Body))
+	return clone, nil
+}
+
+// Get returns an issue in a project the user can access, with the issues it
+// references and that reference it in projects the user can also see, and
+// the wiki pages it links to.
+func (s *IssueService) Get(ctx context.Context, userID, projectID, issueID int64) (*domain.IssueDetail, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	issue, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.IssueDetail{Issue: *issue}
+	if detail.References, err = s.refs.References(ctx, issueID, userID); err != nil {
+		return nil, err
+	}
+	if detail.ReferencedBy, err = s.refs.ReferencedBy(ctx, issueID, userID); err != nil {
+		return nil, err
+	}
+	if detail.WikiPages, err = s.refs.WikiPages(ctx, issueID); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// Export streams every issue matching the filter to fn, for bulk downloads.
+func (s *IssueService) Export(ctx context.Context, userID, projectID int64, filter domain.IssueFilter, fn func(domain.Issue) error) error {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	return s.issues.Each(ctx, projectID, filter, fn)
+}
+
+// Update applies a partial update to an issue. Any project member may edit
+// issues. Status changes must be allowed transitions and are reserved to
+// project admins while the project is frozen. In projects with a close
+// approval rule, only users with the approving role may move an issue into a
+// done status; others must request the close. Moving an issue into a
+// done status records who closed it and when; moving it out again clears
+// that and unarchives the issue. Status changes are recorded as events,
+// which notify subscribers and fire webhooks; other changes are recorded as
+// issue.updated events.
+func (s *IssueService) Update(ctx context.Context, userID, projectID, issueID int64, patch domain.IssuePatch, pre domain.Precondition) (*domain.Issue, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := *current
+	if patch.Title != nil {
+		issue.Title = *patch.Title
+	}
+	if patch.Body != nil {
+		issue.Body = patch.Body
+	}
+	if patch.AssigneeID != nil {
+		issue.AssigneeID = patch.AssigneeID
+	}
+	if patch.Status != nil && *patch.Status != current.Status {
+		if !current.Status.CanTransitionTo(*patch.Status) {
+			return nil, &domain.ValidationError{
+				Field:   "status",
+				Message: fmt.Sprintf("cannot move an issue from %s to %s", current.Status, *patch.Status),
+			}
+		}
+		if err := enforceFreeze(ctx, s.freezes, role, projectID); err != nil {
+			return nil, err
+		}
+		if patch.Status.Done() && !current.Status.Done() {
+			if err := s.authorizeClose(ctx, role, projectID); err != nil {
+				return nil, err
+			}
+		}
+		issue = issue.WithStatus(*patch.Status)
+		switch {
+		case issue.Status.Done() && !current.Status.Done():
+			now := issue.UpdatedAt
+			issue.ClosedBy = &userID
+			issue.ClosedAt = &now
+		case !issue.Status.Done():
+			issue.ClosedBy = nil
+			issue.ClosedAt = nil
+			issue.ArchivedAt = nil
+		}
+	}
+
+	updated, err := s.issues.Update(ctx, issue, pre)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.Title != current.Title || bodyText(updated.Body) != bodyText(current.Body) {
+		syncReferences(ctx, s.refs, issueID, nil, updated.Title, bodyText(updated.Body))
+	}
+	if fields := changedFields(current, updated); len(fields) > 0 {
+		recordEvent(ctx, s.events, domain.IssueEvent{
+			ProjectID: projectID,
+			IssueID:   issueID,
+			ActorID:   &userID,
+			Type:      domain.EventIssueUpdated,
+			Data:      domain.EventData{"fields": fields},
+		})
+	}
+	if !equalID(updated.AssigneeID, current.AssigneeID) {
+		recordEvent(ctx, s.events, domain.IssueEvent{
+			ProjectID: projectID,
+			IssueID:   issueID,
+			ActorID:   &userID,
+			Type:      domain.EventIssueAssigned,
+			Data:      domain.EventData{"from": current.AssigneeID, "to": updated.AssigneeID},
+		})
+	}
+	if updated.Status != current.Status {
+		recordEvent(ctx, s.events, domain.IssueEvent{
+			ProjectID: projectID,
+			IssueID:   issueID,
+			ActorID:   &userID,
+			Type:      domain.EventStatusChanged,
+			Data:      domain.EventData{"from": current.Status, "to": updated.Status},
+		})
+	}
+	return updated, nil
+}
+
+// changedFields names the fields other than status and assignee an update
+// changed.
+func changedFields(before, after *domain.Issue) []string {
+	var fields []string
+	if after.Title != before.Title {
+		fields = append(fields, "title")
+	}
+	if bodyText(after.Body) != bodyText(before.Body) {
+		fields = append(fields, "body")
+	}
+	return fields
+}
+
+func equalID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// authorizeClose checks the user's role against the project's close
+// approval rule, if it has one.
+func (s *IssueService) authorizeClose(ctx context.Context, role domain.ProjectRole, projectID int64) error {
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	required := project.Settings.CloseApproval
+	if required == "" || role.Satisfies(required) {
+		return nil
+	}
+	return fmt.Errorf("%w: closing issues in this project requires approval by a project %s; request the close instead",
+		domain.ErrApprovalRequired, required)
+}
+
+// SetStatus moves an issue to status, as Update does.
+func (s *IssueService) SetStatus(ctx context.Context, userID, projectID, issueID int64, status domain.IssueStatus, pre domain.Precondition) (*domain.Issue, error) {
+	return s.Update(ctx, userID, projectID, issueID, domain.IssuePatch{Status: &status}, pre)
+}
+
+// Delete moves an issue to the project's trash. Only its creator or a
+// project admin may delete it.
+func (s *IssueService) Delete(ctx context.Context, userID, projectID, issueID int64, pre domain.Precondition) error {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return err
+	}
+	issue, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	if !role.CanAdmin() && (issue.CreatedBy == nil || *issue.CreatedBy != userID) {
+		return domain.ErrForbidden
+	}
+
+	if err := s.issues.Delete(ctx, projectID, issueID, userID, pre); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditIssueDeleted, domain.AuditTargetIssue, issueID)
+}
+
+// Pin pins an issue to the top of its project's issue list.
+// Only project admins may pin, and at most pinLimit issues can be pinned.
+func (s *IssueService) Pin(ctx context.Context, userID, projectID, issueID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	issue, err := findIssueInProject(ctx, s.issues, projectID, issueID)
+	if err != nil {
+		return err
+	}
+	if issue.ArchivedAt != nil {
+		return fmt.Errorf("%w: archived issues cannot be pinned", domain.ErrConflict)
+	}
+
+	pinned, changed, err := s.issues.Pin(ctx, projectID, issueID, s.pinLimit)
+	if err != nil {
+		return fmt.Errorf("pin issue: %w", err)
+	}
+	if !pinned {
+		return fmt.Errorf("%w: project already has %d pinned issues", domain.ErrConflict, s.pinLimit)
+	}
+	if !changed {
+		return nil
+	}
+
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditIssuePinned, domain.AuditTargetIssue, issueID)
+}
+
+// Unpin removes an issue from its project's pinned issues.
+func (s *IssueService) Unpin(ctx context.Context, userID, projectID, issueID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return err
+	}
+
+	unpinned, err := s.issues.Unpin(ctx, projectID, issueID)
+	if err != nil {
+		return fmt.Errorf("unpin issue: %w", err)
+	}
+	if !unpinned {
+		return nil
+	}
+
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditIssueUnpinned, domain.AuditTargetIssue, issueID)
+}
+
+// bodyText returns an issue body, or "" if it has none.
+func bodyText(body *string) string {
+	if body == nil {
+		return ""
+	}
+	return *body
+}
+
+// findIssueInProject loads an issue and verifies it belongs to the project.
+func findIssueInProject(ctx context.Context, issues IssueStore, projectID, issueID int64) (*domain.Issue, error) {
+	issue, err := issues.FindByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.ProjectID != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return issue, nil
+}
+
+func clampPageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
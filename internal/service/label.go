@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// LabelStore defines the label data access interface consumed by services.
+type LabelStore interface {
+	ListByProject(ctx context.Context, projectID int64) ([]domain.Label, error)
+	ListForIssue(ctx context.Context, issueID int64) ([]domain.Label, error)
+	FindByID(ctx context.Context, id int64) (*domain.Label, error)
+	Create(ctx context.Context, label domain.Label) (*domain.Label, error)
+	Update(ctx context.Context, label domain.Label) (*domain.Label, error)
+	Delete(ctx context.Context, id int64) error
+	Merge(ctx context.Context, sourceID, targetID int64) (int64, error)
+	SetRestricted(ctx context.Context, id int64, restricted bool) (*domain.Label, error)
+	Attach(ctx context.Context, issueID, labelID int64) (bool, error)
+	Detach(ctx context.Context, issueID, labelID int64) (bool, error)
+}
+
+// LabelService handles project labels and applying them to issues.
+type LabelService struct {
+	projects ProjectStore
+	issues   IssueStore
+	labels   LabelStore
+	events   EventStore
+	audit    AuditStore
+}
+
+// NewLabelService creates a new LabelService.
+func NewLabelService(projects ProjectStore, issues IssueStore, labels LabelStore, events EventStore, audit AuditStore) *LabelService {
+	return &LabelService{projects: projects, issues: issues, labels: labels, events: events, audit: audit}
+}
+
+// List returns the labels of a project the user can access.
+func (s *LabelService) List(ctx context.Context, userID, projectID int64) ([]domain.Label, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.labels.ListByProject(ctx, projectID)
+}
+
+// ListForIssue returns the labels applied to an issue in a project the user
+// can access.
+func (s *LabelService) ListForIssue(ctx context.Context, userID, projectID, issueID int64) ([]domain.Label, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	return s.labels.ListForIssue(ctx, issueID)
+}
+
+// Create adds a label to a project. Only project admins may manage labels.
+func (s *LabelService) Create(ctx context.Context, userID, projectID int64, label domain.Label) (*domain.Label, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	label.ProjectID = projectID
+	return s.labels.Create(ctx, label)
+}
+
+// Update renames or recolors a label. Only project admins may manage labels.
+func (s *LabelService) Update(ctx context.Context, userID, projectID, labelID int64, patch domain.LabelPatch) (*domain.Label, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	label, err := s.findLabelInProject(ctx, projectID, labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		label.Name = *patch.Name
+	}
+	if patch.Color != nil {
+		label.Color = *patch.Color
+	}
+	return s.labels.Update(ctx, *label)
+}
+
+// Delete removes a label from a project and from every issue that has it.
+// Only project admins may manage labels.
+func (s *LabelService) Delete(ctx context.Context, userID, projectID, labelID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := s.findLabelInProject(ctx, projectID, labelID); err != nil {
+		return err
+	}
+	return s.labels.Delete(ctx, labelID)
+}
+
+// Rename gives a label a new name on every issue that has it, and records
+// it in the audit log. It returns domain.ErrConflict if another label has
+// the name; Merge combines the two instead. Only project admins may manage
+// labels.
+func (s *LabelService) Rename(ctx context.Context, userID, projectID, labelID int64, name string) (*domain.Label, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	label, err := s.findLabelInProject(ctx, projectID, labelID)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &domain.ValidationError{Field: "name", Message: "is required"}
+	}
+	if name == label.Name {
+		return label, nil
+	}
+
+	label.Name = name
+	if label, err = s.labels.Update(ctx, *label); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditLabelRenamed, domain.AuditTargetLabel, labelID); err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// Merge moves every issue with one label to another and deletes the first,
+// atomically, and records it in the audit log against the label kept. The
+// kept label's name, color and restriction are unchanged. Only project
+// admins may manage labels.
+func (s *LabelService) Merge(ctx context.Context, userID, projectID, sourceID, targetID int64) (*domain.LabelMerge, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if sourceID == targetID {
+		return nil, &domain.ValidationError{Field: "into", Message: "must be a different label"}
+	}
+	if _, err := s.findLabelInProject(ctx, projectID, sourceID); err != nil {
+		return nil, err
+	}
+	target, err := s.findLabelInProject(ctx, projectID, targetID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ValidationError{Field: "into", Message: "is not a label of this project"}
+		}
+		return nil, err
+	}
+
+	moved, err := s.labels.Merge(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditLabelMerged, domain.AuditTargetLabel, targetID); err != nil {
+		return nil, err
+	}
+	return &domain.LabelMerge{Label: *target, IssuesMoved: moved}, nil
+}
+
+// SetRestricted restricts a label to project admins or lifts the
+// restriction. Only project admins may do this.
+func (s *LabelService) SetRestricted(ctx context.Context, userID, projectID, labelID int64, restricted bool) (*domain.Label, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	label, err := s.findLabelInProject(ctx, projectID, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if label.Restricted == restricted {
+		return label, nil
+	}
+
+	if label, err = s.labels.SetRestricted(ctx, labelID, restricted); err != nil {
+		return nil, err
+	}
+	action := domain.AuditLabelUnrestricted
+	if restricted {
+		action = domain.AuditLabelRestricted
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, action, domain.AuditTargetLabel, labelID); err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// Attach applies a label to an issue. Any project member may apply labels,
+// except restricted ones, which need a project admin.
+func (s *LabelService) Attach(ctx context.Context, userID, projectID, issueID, labelID int64) error {
+	label, err := s.authorizeLabel(ctx, userID, projectID, issueID, labelID)
+	if err != nil {
+		return err
+	}
+	attached, err := s.labels.Attach(ctx, issueID, labelID)
+	if err != nil {
+		return err
+	}
+	if attached {
+		s.recordLabelEvent(ctx, userID, projectID, issueID, domain.EventLabelAdded, label)
+	}
+	return nil
+}
+
+// Detach removes a label from an issue, with the same rules as Attach.
+func (s *LabelService) Detach(ctx context.Context, userID, projectID, issueID, labelID int64) error {
+	label, err := s.authorizeLabel(ctx, userID, projectID, issueID, labelID)
+	if err != nil {
+		return err
+	}
+	detached, err := s.labels.Detach(ctx, issueID, labelID)
+	if err != nil {
+		return err
+	}
+	if detached {
+		s.recordLabelEvent(ctx, userID, projectID, issueID, domain.EventLabelRemoved, label)
+	}
+	return nil
+}
+
+// recordLabelEvent records that the user added a label to an issue or
+// removed it.
+func (s *LabelService) recordLabelEvent(ctx context.Context, userID, projectID, issueID int64, typ domain.EventType, label *domain.Label) {
+	recordEvent(ctx, s.events, domain.IssueEvent{
+		ProjectID: projectID,
+		IssueID:   issueID,
+		ActorID:   &userID,
+		Type:      typ,
+		Data:      domain.EventData{"label_id": label.ID, "name": label.Name, "color": label.Color},
+	})
+}
+
+// authorizeLabel verifies the user may change whether the issue has the
+// label and returns the label. It returns domain.ErrRestrictedLabel if the
+// label is restricted and the user is not a project admin.
+func (s *LabelService) authorizeLabel(ctx context.Context, userID, projectID, issueID, labelID int64) (*domain.Label, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	label, err := s.findLabelInProject(ctx, projectID, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if label.Restricted && !role.CanAdmin() {
+		return nil, domain.ErrRestrictedLabel
+	}
+	return label, nil
+}
+
+// findLabelInProject loads a label and verifies it belongs to the project.
+func (s *LabelService) findLabelInProject(ctx context.Context, projectID, labelID int64) (*domain.Label, error) {
+	label, err := s.labels.FindByID(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if label.ProjectID != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return label, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// LabelSyncStore defines the label sync data access interface consumed by
+// LabelSyncService.
+type LabelSyncStore interface {
+	ProjectIDs(ctx context.Context, orgID int64) ([]int64, error)
+	Create(ctx context.Context, sync domain.LabelSync, projectIDs []int64) (*domain.LabelSync, error)
+	FindByID(ctx context.Context, orgID, id int64) (*domain.LabelSync, error)
+	ListForOrganization(ctx context.Context, orgID int64) ([]domain.LabelSync, error)
+	Running(ctx context.Context) ([]domain.LabelSync, error)
+	SyncNext(ctx context.Context, sync domain.LabelSync) (bool, error)
+	Finish(ctx context.Context, syncID int64) error
+}
+
+// LabelSyncService pushes organizations' default labels to their projects
+// in the background.
+type LabelSyncService struct {
+	orgs      OrganizationStore
+	syncs     LabelSyncStore
+	batchSize int
+	interval  time.Duration
+}
+
+// NewLabelSyncService creates a new LabelSyncService. Each running sync
+// handles up to batchSize projects every interval.
+func NewLabelSyncService(orgs OrganizationStore, syncs LabelSyncStore, batchSize int, interval time.Duration) *LabelSyncService {
+	return &LabelSyncService{orgs: orgs, syncs: syncs, batchSize: batchSize, interval: interval}
+}
+
+// Start begins syncing the organization's default labels to the given
+// projects of the organization, or to all of them if projectIDs is empty.
+// With prune set, labels outside the defaults are deleted from the
+// projects. Only organization admins may start a sync.
+func (s *LabelSyncService) Start(ctx context.Context, userID, orgID int64, projectIDs []int64, prune bool) (*domain.LabelSync, error) {
+	role, err := s.orgs.RoleOf(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanAdmin() {
+		return nil, domain.ErrForbidden
+	}
+	org, err := s.orgs.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(org.Defaults.Labels) == 0 {
+		// Pruning to an empty set would delete every label.
+		return nil, &domain.ValidationError{Field: "labels", Message: "the organization has no default labels to sync"}
+	}
+
+	all, err := s.syncs.ProjectIDs(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	targets := all
+	if len(projectIDs) > 0 {
+		targets = make([]int64, 0, len(projectIDs))
+		for _, id := range projectIDs {
+			if !slices.Contains(all, id) {
+				return nil, &domain.ValidationError{Field: "project_ids", Message: "every project must belong to the organization"}
+			}
+			if !slices.Contains(targets, id) {
+				targets = append(targets, id)
+			}
+		}
+	}
+
+	sync, err := s.syncs.Create(ctx, domain.LabelSync{
+		OrganizationID: orgID,
+		Labels:         org.Defaults.Labels,
+		Prune:          prune,
+		RequestedBy:    &userID,
+	}, targets)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("label sync started", "label_sync_id", sync.ID, "organization_id", orgID, "total", sync.Total, "prune", prune)
+	return sync, nil
+}
+
+// Get returns a sync of an organization the user belongs to, with what it
+// did to each project so far.
+func (s *LabelSyncService) Get(ctx context.Context, userID, orgID, syncID int64) (*domain.LabelSync, error) {
+	if _, err := s.orgs.RoleOf(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.syncs.FindByID(ctx, orgID, syncID)
+}
+
+// List returns the syncs of an organization the user belongs to, newest
+// first, without their reports.
+func (s *LabelSyncService) List(ctx context.Context, userID, orgID int64) ([]domain.LabelSync, error) {
+	if _, err := s.orgs.RoleOf(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.syncs.ListForOrganization(ctx, orgID)
+}
+
+// Run advances every running sync every interval until ctx is cancelled.
+// It must run on a single instance at a time.
+func (s *LabelSyncService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.step(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// step syncs up to batchSize more projects of every running sync and
+// completes those with none left. A failed project is retried next step.
+func (s *LabelSyncService) step(ctx context.Context) {
+	syncs, err := s.syncs.Running(ctx)
+	if err != nil {
+		slog.Error("label sync lookup failed", "error", err)
+		return
+	}
+
+	for _, sync := range syncs {
+		done := false
+		for range s.batchSize {
+			found, err := s.syncs.SyncNext(ctx, sync)
+			if err != nil {
+				slog.Warn("label sync will be retried", "label_sync_id", sync.ID, "error", err)
+				break
+			}
+			if !found {
+				done = true
+				break
+			}
+		}
+		if !done {
+			continue
+		}
+		if err := s.syncs.Finish(ctx, sync.ID); err != nil {
+			slog.Error("label sync completion failed", "label_sync_id", sync.ID, "error", err)
+			continue
+		}
+		slog.Info("label sync completed", "label_sync_id", sync.ID, "organization_id", sync.OrganizationID)
+	}
+}
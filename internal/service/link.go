@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	// defaultGraphDepth is how many links from the root an issue graph
+	// reaches unless asked otherwise.
+	defaultGraphDepth = 3
+	// maxGraphDepth is the furthest an issue graph reaches from its root.
+	maxGraphDepth = 10
+	// maxGraphNodes bounds the issues in one issue graph.
+	maxGraphNodes = 500
+)
+
+// IssueLinkStore defines the issue link data access interface consumed by
+// IssueLinkService.
+type IssueLinkStore interface {
+	ListForIssue(ctx context.Context, issueID int64) ([]domain.IssueLink, error)
+	FindByID(ctx context.Context, id int64) (*domain.IssueLink, error)
+	Create(ctx context.Context, link domain.IssueLink) (*domain.IssueLink, error)
+	Delete(ctx context.Context, id int64) error
+	Graph(ctx context.Context, projectID int64) ([]domain.GraphNode, []domain.IssueLink, error)
+}
+
+// IssueLinkService handles the blocks, relates and parent relationships
+// between issues of a project, and the graph they form. Any project member
+// may manage them.
+type IssueLinkService struct {
+	projects ProjectStore
+	issues   IssueStore
+	links    IssueLinkStore
+}
+
+// NewIssueLinkService creates a new IssueLinkService.
+func NewIssueLinkService(projects ProjectStore, issues IssueStore, links IssueLinkStore) *IssueLinkService {
+	return &IssueLinkService{projects: projects, issues: issues, links: links}
+}
+
+// List returns the links from and to an issue in a project the user can
+// access.
+func (s *IssueLinkService) List(ctx context.Context, userID, projectID, issueID int64) ([]domain.IssueLink, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	return s.links.ListForIssue(ctx, issueID)
+}
+
+// Create links an issue to another issue of its project: the issue blocks
+// the target, relates to it, or is its parent.
+func (s *IssueLinkService) Create(ctx context.Context, userID, projectID, issueID int64, typ domain.IssueLinkType, targetID int64) (*domain.IssueLink, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if !typ.Valid() {
+		return nil, &domain.ValidationError{Field: "type", Message: "must be blocks, relates or parent"}
+	}
+	if targetID == issueID {
+		return nil, &domain.ValidationError{Field: "target_id", Message: "must be another issue"}
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, targetID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, &domain.ValidationError{Field: "target_id", Message: "is not an issue of this project"}
+		}
+		return nil, err
+	}
+
+	link := domain.IssueLink{SourceIssueID: issueID, TargetIssueID: targetID, Type: typ, CreatedBy: &userID}
+	if !typ.Directed() && link.SourceIssueID > link.TargetIssueID {
+		link.SourceIssueID, link.TargetIssueID = link.TargetIssueID, link.SourceIssueID
+	}
+	return s.links.Create(ctx, link)
+}
+
+// Delete removes a link from or to an issue in a project the user can
+// access.
+func (s *IssueLinkService) Delete(ctx context.Context, userID, projectID, issueID, linkID int64) error {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return err
+	}
+	link, err := s.links.FindByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link.SourceIssueID != issueID && link.TargetIssueID != issueID {
+		return domain.ErrNotFound
+	}
+	return s.links.Delete(ctx, linkID)
+}
+
+// Graph returns the part of the issue graph of a project the user can
+// access that q selects. A zero q.Depth reaches defaultGraphDepth links.
+func (s *IssueLinkService) Graph(ctx context.Context, userID, projectID int64, q domain.GraphQuery) (*domain.IssueGraph, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	var root *domain.Issue
+	if q.RootID != nil {
+		var err error
+		if root, err = findIssueInProject(ctx, s.issues, projectID, *q.RootID); err != nil {
+			return nil, err
+		}
+	}
+	if q.Depth <= 0 {
+		q.Depth = defaultGraphDepth
+	}
+	q.Depth = min(q.Depth, maxGraphDepth)
+	q.MaxNodes = maxGraphNodes
+
+	nodes, links, err := s.links.Graph(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if root != nil && !slices.ContainsFunc(nodes, func(n domain.GraphNode) bool { return n.ID == root.ID }) {
+		// An issue without links is a graph of its own.
+		nodes = append(nodes, domain.GraphNode{
+			ID:         root.ID,
+			Number:     root.Number,
+			Title:      root.Title,
+			Status:     root.Status,
+			AssigneeID: root.AssigneeID,
+		})
+	}
+	graph := domain.BuildIssueGraph(nodes, links, q)
+	return &graph, nil
+}
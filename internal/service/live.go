@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// ProjectSubscriber streams the events of projects.
+type ProjectSubscriber interface {
+	SubscribeProjects(projectIDs []int64) (<-chan domain.RealtimeEvent, func())
+}
+
+// MembershipStore lists the projects users belong to.
+type MembershipStore interface {
+	ProjectIDsForUser(ctx context.Context, userID int64) ([]int64, error)
+}
+
+// LiveService streams changes in the projects users belong to, so open
+// views update without polling.
+type LiveService struct {
+	projects    MembershipStore
+	subscribers ProjectSubscriber
+}
+
+// NewLiveService creates a new LiveService.
+func NewLiveService(projects MembershipStore, subscribers ProjectSubscriber) *LiveService {
+	return &LiveService{projects: projects, subscribers: subscribers}
+}
+
+// Projects returns the IDs of the projects the user owns or is a member
+// of, in ascending order.
+func (s *LiveService) Projects(ctx context.Context, userID int64) ([]int64, error) {
+	return s.projects.ProjectIDsForUser(ctx, userID)
+}
+
+// Watch opens a stream of events in the projects the user belongs to and
+// returns those projects. Projects joined later are not covered: callers
+// compare Projects with them from time to time and reopen the stream.
+func (s *LiveService) Watch(ctx context.Context, userID int64) (projectIDs []int64, events <-chan domain.RealtimeEvent, stop func(), err error) {
+	projectIDs, err = s.Projects(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	events, stop = s.subscribers.SubscribeProjects(projectIDs)
+	return projectIDs, events, stop, nil
+}
+
+// publishingEvents is an EventStore that also pushes the events it records
+// to the streams watching their project.
+type publishingEvents struct {
+	EventStore
+	publisher Publisher
+}
+
+// PublishEvents wraps events so that recording an event also publishes it
+// as an issue.changed, comment.added or ai.progress realtime event. Failing
+// to publish is logged and does not fail the recording.
+func PublishEvents(events EventStore, publisher Publisher) EventStore {
+	return &publishingEvents{EventStore: events, publisher: publisher}
+}
+
+func (e *publishingEvents) Record(ctx context.Context, event domain.IssueEvent) error {
+	if err := e.EventStore.Record(ctx, event); err != nil {
+		return err
+	}
+	if err := e.publish(ctx, event); err != nil {
+		slog.Error("failed to publish issue event",
+			"type", event.Type,
+			"issue_id", event.IssueID,
+			"error", err,
+		)
+	}
+	return nil
+}
+
+func (e *publishingEvents) publish(ctx context.Context, event domain.IssueEvent) error {
+	typ, data := liveEvent(event)
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", typ, err)
+	}
+	return e.publisher.Publish(ctx, domain.RealtimeEvent{ProjectID: event.ProjectID, Type: typ, Data: payload})
+}
+
+// liveEvent returns the realtime event type and payload an issue event is
+// published as.
+func liveEvent(event domain.IssueEvent) (domain.RealtimeEventType, any) {
+	switch event.Type {
+	case domain.EventAIRun, domain.EventAIJobCompleted, domain.EventAIJobFailed, domain.EventAIJobCancelled:
+		jobID, _ := event.Data["job_id"].(int64)
+		return domain.RealtimeAIProgress, domain.AIProgress{
+			IssueID: event.IssueID,
+			JobID:   jobID,
+			Event:   event.Type,
+			Data:    event.Data,
+		}
+	}
+
+	typ := domain.RealtimeIssueChanged
+	if event.Type == domain.EventCommentCreated {
+		typ = domain.RealtimeCommentAdded
+	}
+	return typ, domain.IssueChange{
+		IssueID: event.IssueID,
+		ActorID: event.ActorID,
+		Event:   event.Type,
+		Data:    event.Data,
+	}
+}
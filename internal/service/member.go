@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// MemberStore defines the project membership data access interface consumed
+// by MemberService.
+type MemberStore interface {
+	Members(ctx context.Context, projectID int64) ([]domain.ProjectMember, error)
+	AddMembers(ctx context.Context, projectID int64, members []domain.ProjectMember) ([]domain.MemberResult, error)
+	RemoveMembers(ctx context.Context, projectID int64, userIDs []int64) ([]domain.MemberResult, error)
+}
+
+// UserDirectory looks users up by email.
+type UserDirectory interface {
+	FindByEmails(ctx context.Context, emails []string) ([]domain.User, error)
+}
+
+// MemberService adds and removes project members in bulk.
+type MemberService struct {
+	projects ProjectStore
+	members  MemberStore
+	users    UserDirectory
+	audit    AuditStore
+}
+
+// NewMemberService creates a new MemberService.
+func NewMemberService(projects ProjectStore, members MemberStore, users UserDirectory, audit AuditStore) *MemberService {
+	return &MemberService{projects: projects, members: members, users: users, audit: audit}
+}
+
+// Add adds the users with the given emails to a project with role, and, if
+// fromProjectID is set, every member of that project with their role there.
+// Its owner joins as an admin. It returns a result per email and per copied
+// member. Only project admins may add members, and copying requires access
+// to the other project.
+func (s *MemberService) Add(ctx context.Context, userID, projectID int64, emails []string, role domain.ProjectRole, fromProjectID *int64) ([]domain.MemberResult, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = domain.ProjectRoleMember
+	}
+	if role != domain.ProjectRoleMember && role != domain.ProjectRoleAdmin {
+		return nil, &domain.ValidationError{Field: "role", Message: "must be admin or member"}
+	}
+
+	entries, err := s.lookup(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	var members []domain.ProjectMember
+	for _, e := range entries {
+		if e.UserID != nil {
+			members = append(members, domain.ProjectMember{UserID: *e.UserID, Role: role})
+		}
+	}
+
+	if fromProjectID != nil {
+		if _, err := authorizeProject(ctx, s.projects, userID, *fromProjectID); err != nil {
+			return nil, err
+		}
+		copied, err := s.members.Members(ctx, *fromProjectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range copied {
+			if m.Role == domain.ProjectRoleOwner {
+				m.Role = domain.ProjectRoleAdmin
+			}
+			members = append(members, m)
+			entries = append(entries, domain.MemberResult{UserID: &m.UserID})
+		}
+	}
+
+	if len(members) == 0 {
+		return entries, nil
+	}
+	results, err := s.members.AddMembers(ctx, projectID, members)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, userID, projectID, entries, results, domain.MemberStatusAdded, domain.AuditMemberAdded)
+}
+
+// Remove removes the users with the given emails from a project and returns
+// a result per email. Only project admins may remove members.
+func (s *MemberService) Remove(ctx context.Context, userID, projectID int64, emails []string) ([]domain.MemberResult, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.lookup(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for _, e := range entries {
+		if e.UserID != nil {
+			ids = append(ids, *e.UserID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return entries, nil
+	}
+	results, err := s.members.RemoveMembers(ctx, projectID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, userID, projectID, entries, results, domain.MemberStatusRemoved, domain.AuditMemberRemoved)
+}
+
+// lookup returns an entry per account matching each distinct email, in the
+// order given. Emails matching no account get an unknown_user result.
+func (s *MemberService) lookup(ctx context.Context, emails []string) ([]domain.MemberResult, error) {
+	seen := make(map[string]bool, len(emails))
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" && !seen[email] {
+			seen[email] = true
+			normalized = append(normalized, email)
+		}
+	}
+	if len(normalized) == 0 {
+		return []domain.MemberResult{}, nil
+	}
+
+	users, err := s.users.FindByEmails(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	byEmail := make(map[string][]int64, len(users))
+	for _, u := range users {
+		email := strings.ToLower(u.Email)
+		byEmail[email] = append(byEmail[email], u.ID)
+	}
+
+	entries := make([]domain.MemberResult, 0, len(normalized))
+	for _, email := range normalized {
+		ids := byEmail[email]
+		if len(ids) == 0 {
+			entries = append(entries, domain.MemberResult{Email: email, Status: domain.MemberStatusUnknownUser})
+			continue
+		}
+		for _, id := range ids {
+			entries = append(entries, domain.MemberResult{Email: email, UserID: &id})
+		}
+	}
+	return entries, nil
+}
+
+// resolve fills in each entry's status from the per-user results and audits
+// every user whose membership changed.
+func (s *MemberService) resolve(ctx context.Context, userID, projectID int64, entries, results []domain.MemberResult, changed domain.MemberStatus, action domain.AuditAction) ([]domain.MemberResult, error) {
+	statuses := make(map[int64]domain.MemberStatus, len(results))
+	for _, r := range results {
+		statuses[*r.UserID] = r.Status
+		if r.Status != changed {
+			continue
+		}
+		if err := recordAudit(ctx, s.audit, projectID, userID, action, domain.AuditTargetUser, *r.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, e := range entries {
+		if e.UserID != nil {
+			entries[i].Status = statuses[*e.UserID]
+		}
+	}
+	return entries, nil
+}
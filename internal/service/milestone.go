@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// MilestoneStore defines the milestone data access interface consumed by
+// services.
+type MilestoneStore interface {
+	ListByProject(ctx context.Context, projectID int64) ([]domain.Milestone, error)
+	FindByID(ctx context.Context, id int64) (*domain.Milestone, error)
+	Create(ctx context.Context, milestone domain.Milestone) (*domain.Milestone, error)
+	Update(ctx context.Context, milestone domain.Milestone) (*domain.Milestone, error)
+	Delete(ctx context.Context, id int64) error
+	Assign(ctx context.Context, issueID int64, milestoneID *int64) error
+	Progress(ctx context.Context, id int64) (*domain.MilestoneProgress, error)
+}
+
+// MilestoneService handles project milestones and putting issues in them.
+type MilestoneService struct {
+	projects   ProjectStore
+	issues     IssueStore
+	milestones MilestoneStore
+}
+
+// NewMilestoneService creates a new MilestoneService.
+func NewMilestoneService(projects ProjectStore, issues IssueStore, milestones MilestoneStore) *MilestoneService {
+	return &MilestoneService{projects: projects, issues: issues, milestones: milestones}
+}
+
+// List returns the milestones of a project the user can access.
+func (s *MilestoneService) List(ctx context.Context, userID, projectID int64) ([]domain.Milestone, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.milestones.ListByProject(ctx, projectID)
+}
+
+// Get returns a milestone of a project the user can access.
+func (s *MilestoneService) Get(ctx context.Context, userID, projectID, milestoneID int64) (*domain.Milestone, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.findMilestoneInProject(ctx, projectID, milestoneID)
+}
+
+// Create adds an open milestone to a project. Only project admins may
+// manage milestones.
+func (s *MilestoneService) Create(ctx context.Context, userID, projectID int64, milestone domain.Milestone) (*domain.Milestone, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	milestone.ProjectID = projectID
+	milestone.Title = strings.TrimSpace(milestone.Title)
+	milestone.State = domain.MilestoneOpen
+	if milestone.Title == "" {
+		return nil, &domain.ValidationError{Field: "title", Message: "is required"}
+	}
+	return s.milestones.Create(ctx, milestone)
+}
+
+// Update edits a milestone, including closing or reopening it. Only
+// project admins may manage milestones.
+func (s *MilestoneService) Update(ctx context.Context, userID, projectID, milestoneID int64, patch domain.MilestonePatch) (*domain.Milestone, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	milestone, err := s.findMilestoneInProject(ctx, projectID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Title != nil {
+		milestone.Title = strings.TrimSpace(*patch.Title)
+		if milestone.Title == "" {
+			return nil, &domain.ValidationError{Field: "title", Message: "is required"}
+		}
+	}
+	if patch.Description != nil {
+		milestone.Description = *patch.Description
+	}
+	if patch.ClearDueDate {
+		milestone.DueDate = nil
+	} else if patch.DueDate != nil {
+		milestone.DueDate = patch.DueDate
+	}
+	if patch.State != nil {
+		if !patch.State.Valid() {
+			return nil, &domain.ValidationError{Field: "state", Message: "must be open or closed"}
+		}
+		milestone.State = *patch.State
+	}
+	return s.milestones.Update(ctx, *milestone)
+}
+
+// Delete removes a milestone from a project, leaving its issues without a
+// milestone. Only project admins may manage milestones.
+func (s *MilestoneService) Delete(ctx context.Context, userID, projectID, milestoneID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := s.findMilestoneInProject(ctx, projectID, milestoneID); err != nil {
+		return err
+	}
+	return s.milestones.Delete(ctx, milestoneID)
+}
+
+// Progress counts the open and closed issues in a milestone of a project
+// the user can access.
+func (s *MilestoneService) Progress(ctx context.Context, userID, projectID, milestoneID int64) (*domain.MilestoneProgress, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findMilestoneInProject(ctx, projectID, milestoneID); err != nil {
+		return nil, err
+	}
+	return s.milestones.Progress(ctx, milestoneID)
+}
+
+// Assign puts an issue in a milestone of its project, moving it out of any
+// other. Any project member may do this, but only for open milestones.
+func (s *MilestoneService) Assign(ctx context.Context, userID, projectID, issueID, milestoneID int64) error {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return err
+	}
+	milestone, err := s.findMilestoneInProject(ctx, projectID, milestoneID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return &domain.ValidationError{Field: "milestone_id", Message: "is not a milestone of this project"}
+		}
+		return err
+	}
+	if milestone.State == domain.MilestoneClosed {
+		return fmt.Errorf("%w: milestone %q is closed", domain.ErrConflict, milestone.Title)
+	}
+	return s.milestones.Assign(ctx, issueID, &milestoneID)
+}
+
+// Unassign takes an issue out of its milestone. Any project member may do
+// this.
+func (s *MilestoneService) Unassign(ctx context.Context, userID, projectID, issueID int64) error {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return err
+	}
+	return s.milestones.Assign(ctx, issueID, nil)
+}
+
+// findMilestoneInProject loads a milestone and verifies it belongs to the
+// project.
+func (s *MilestoneService) findMilestoneInProject(ctx context.Context, projectID, milestoneID int64) (*domain.Milestone, error) {
+	milestone, err := s.milestones.FindByID(ctx, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if milestone.ProjectID != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return milestone, nil
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// ModerationStore defines the block and report data access interface consumed by ModerationService.
+type ModerationStore interface {
+	Block(ctx context.Context, block domain.ProjectBlock) error
+	Unblock(ctx context.Context, projectID, userID int64) error
+	ListBlocks(ctx context.Context, projectID int64) ([]domain.ProjectBlock, error)
+	CreateReport(ctx context.Context, report domain.ContentReport) (*domain.ContentReport, error)
+	ListReports(ctx context.Context, projectID int64, status domain.ReportStatus, after domain.ListPosition, limit int) ([]domain.ContentReport, error)
+	ResolveReport(ctx context.Context, projectID, reportID int64, status domain.ReportStatus, by int64) (*domain.ContentReport, error)
+}
+
+// ModerationService handles project-level moderation: hiding comments,
+// blocking users and reviewing content reports.
+type ModerationService struct {
+	projects   ProjectStore
+	issues     IssueStore
+	comments   CommentStore
+	moderation ModerationStore
+	audit      AuditStore
+}
+
+// NewModerationService creates a new ModerationService.
+func NewModerationService(projects ProjectStore, issues IssueStore, comments CommentStore, moderation ModerationStore, audit AuditStore) *ModerationService {
+	return &ModerationService{
+		projects:   projects,
+		issues:     issues,
+		comments:   comments,
+		moderation: moderation,
+		audit:      audit,
+	}
+}
+
+// SetCommentHidden hides or unhides a comment. The comment is kept and only
+// masked in responses.
+func (s *ModerationService) SetCommentHidden(ctx context.Context, userID, projectID, commentID int64, hidden bool) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := findCommentInProject(ctx, s.issues, s.comments, projectID, commentID); err != nil {
+		return err
+	}
+
+	var by *int64
+	action := domain.AuditCommentShown
+	if hidden {
+		by = &userID
+		action = domain.AuditCommentHidden
+	}
+
+	if err := s.comments.SetHidden(ctx, commentID, by); err != nil {
+		return fmt.Errorf("set comment hidden: %w", err)
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, action, domain.AuditTargetComment, commentID)
+}
+
+// Block bars a user from a project. The project owner cannot be blocked.
+func (s *ModerationService) Block(ctx context.Context, userID, projectID, targetID int64, reason *string) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if targetID == project.OwnerID || targetID == userID {
+		return fmt.Errorf("%w: cannot block the project owner or yourself", domain.ErrInvalidInput)
+	}
+
+	err = s.moderation.Block(ctx, domain.ProjectBlock{
+		ProjectID: projectID,
+		UserID:    targetID,
+		BlockedBy: userID,
+		Reason:    reason,
+	})
+	if err != nil {
+		return fmt.Errorf("block user: %w", err)
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditUserBlocked, domain.AuditTargetUser, targetID)
+}
+
+// Unblock lifts a user's block from a project.
+func (s *ModerationService) Unblock(ctx context.Context, userID, projectID, targetID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if err := s.moderation.Unblock(ctx, projectID, targetID); err != nil {
+		return fmt.Errorf("unblock user: %w", err)
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditUserUnblocked, domain.AuditTargetUser, targetID)
+}
+
+// ListBlocks returns the users blocked from a project.
+func (s *ModerationService) ListBlocks(ctx context.Context, userID, projectID int64) ([]domain.ProjectBlock, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.moderation.ListBlocks(ctx, projectID)
+}
+
+// Report files a content report against an issue or comment in the project.
+func (s *ModerationService) Report(ctx context.Context, userID, projectID int64, target domain.AuditTarget, targetID int64, reason string) (*domain.ContentReport, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch target {
+	case domain.AuditTargetIssue:
+		_, err = findIssueInProject(ctx, s.issues, projectID, targetID)
+	case domain.AuditTargetComment:
+		_, err = findCommentInProject(ctx, s.issues, s.comments, projectID, targetID)
+	default:
+		return nil, &domain.ValidationError{Field: "target_type", Message: "must be issue or comment"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.moderation.CreateReport(ctx, domain.ContentReport{
+		ProjectID:  projectID,
+		ReporterID: userID,
+		TargetType: target,
+		TargetID:   targetID,
+		Reason:     reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create report: %w", err)
+	}
+	return report, nil
+}
+
+// ReportPage is a single page of content reports.
+type ReportPage struct {
+	Reports    []domain.ContentReport
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// ListReports returns the project's review queue for the given status.
+func (s *ModerationService) ListReports(ctx context.Context, userID, projectID int64, status domain.ReportStatus, after domain.ListPosition, limit int) (*ReportPage, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	reports, err := s.moderation.ListReports(ctx, projectID, status, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+
+	page := &ReportPage{Reports: reports}
+	if len(reports) > limit {
+		page.Reports = reports[:limit]
+		page.HasNext = true
+		last := page.Reports[len(page.Reports)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// ResolveReport closes an open report as resolved or dismissed.
+func (s *ModerationService) ResolveReport(ctx context.Context, userID, projectID, reportID int64, status domain.ReportStatus) (*domain.ContentReport, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if status != domain.ReportStatusResolved && status != domain.ReportStatusDismissed {
+		return nil, &domain.ValidationError{Field: "status", Message: "must be resolved or dismissed"}
+	}
+
+	report, err := s.moderation.ResolveReport(ctx, projectID, reportID, status, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditReportResolved, domain.AuditTargetReport, reportID); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
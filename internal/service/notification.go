@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// NotificationReader defines the notification inbox interface consumed by NotificationService.
+type NotificationReader interface {
+	ListForUser(ctx context.Context, userID int64, filter domain.NotificationFilter) ([]domain.Notification, error)
+	UnreadCount(ctx context.Context, userID int64) (int, error)
+	MarkRead(ctx context.Context, userID, id int64) error
+	MarkReadUpTo(ctx context.Context, userID, upTo int64) (int64, error)
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+	Snooze(ctx context.Context, userID, id int64, until time.Time) (*domain.Notification, error)
+	Resurface(ctx context.Context, limit int) ([]domain.Notification, error)
+	Preferences(ctx context.Context, userID int64) (*domain.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, p domain.NotificationPreferences) (*domain.NotificationPreferences, error)
+}
+
+// Publisher pushes events to the streams open on any instance.
+type Publisher interface {
+	Publish(ctx context.Context, event domain.RealtimeEvent) error
+}
+
+// Broadcaster pushes events to the streams users have open on any instance.
+type Broadcaster interface {
+	Publisher
+	Subscribe(userID int64) (<-chan domain.RealtimeEvent, func())
+}
+
+// NotificationService serves users their in-app notifications and keeps
+// their read state in sync across devices.
+type NotificationService struct {
+	notifications NotificationReader
+	broadcaster   Broadcaster
+}
+
+// NewNotificationService creates a new NotificationService.
+func NewNotificationService(notifications NotificationReader, broadcaster Broadcaster) *NotificationService {
+	return &NotificationService{notifications: notifications, broadcaster: broadcaster}
+}
+
+// NotificationPage is a single page of notifications.
+type NotificationPage struct {
+	Notifications []domain.Notification
+	NextCursor    domain.ListPosition
+	HasNext       bool
+}
+
+// List returns a page of the user's notifications, newest first.
+func (s *NotificationService) List(ctx context.Context, userID int64, filter domain.NotificationFilter) (*NotificationPage, error) {
+	filter.Limit = clampPageSize(filter.Limit)
+	notifications, err := s.notifications.ListForUser(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+
+	page := &NotificationPage{Notifications: notifications}
+	if len(notifications) > filter.Limit {
+		page.Notifications = notifications[:filter.Limit]
+		page.HasNext = true
+		last := page.Notifications[len(page.Notifications)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// MarkRead marks one of the user's notifications as read.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int64) error {
+	if err := s.notifications.MarkRead(ctx, userID, id); err != nil {
+		return err
+	}
+	s.publishRead(ctx, userID, domain.NotificationsRead{IDs: []int64{id}})
+	return nil
+}
+
+// MarkReadUpTo marks the user's notifications with IDs up to and including
+// upTo as read, so a client can clear everything it has seen without
+// touching notifications that arrived since. It returns how many were unread.
+func (s *NotificationService) MarkReadUpTo(ctx context.Context, userID, upTo int64) (int64, error) {
+	n, err := s.notifications.MarkReadUpTo(ctx, userID, upTo)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		s.publishRead(ctx, userID, domain.NotificationsRead{UpTo: &upTo})
+	}
+	return n, nil
+}
+
+// MarkAllRead marks all of the user's notifications as read and returns how
+// many were unread.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	n, err := s.notifications.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		s.publishRead(ctx, userID, domain.NotificationsRead{All: true})
+	}
+	return n, nil
+}
+
+// Snooze hides one of the user's notifications for d. It comes back, and is
+// pushed to the user's streams again, once the snooze expires.
+func (s *NotificationService) Snooze(ctx context.Context, userID, id int64, d time.Duration) (*domain.Notification, error) {
+	n, err := s.notifications.Snooze(ctx, userID, id, time.Now().Add(d))
+	if err != nil {
+		return nil, err
+	}
+	s.publishChange(ctx, domain.RealtimeNotificationSnoozed, *n)
+	return n, nil
+}
+
+// resurfaceBatchSize is how many expired snoozes Resurface ends per query.
+const resurfaceBatchSize = 500
+
+// Resurface ends every expired snooze, pushes the notifications to their
+// users' streams again and returns how many there were.
+func (s *NotificationService) Resurface(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		due, err := s.notifications.Resurface(ctx, resurfaceBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, n := range due {
+			s.publishChange(ctx, domain.RealtimeNotificationResurfaced, n)
+		}
+		total += len(due)
+		if len(due) < resurfaceBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Preferences returns the user's notification preferences.
+func (s *NotificationService) Preferences(ctx context.Context, userID int64) (*domain.NotificationPreferences, error) {
+	return s.notifications.Preferences(ctx, userID)
+}
+
+// UpdatePreferences replaces the user's notification preferences. An empty
+// timezone means UTC.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID int64, p domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	p.UserID = userID
+	if p.Timezone == "" {
+		p.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return nil, &domain.ValidationError{Field: "timezone", Message: "must be an IANA time zone such as Asia/Tokyo"}
+	}
+	if q := p.QuietHours; q != nil {
+		if !q.Start.Valid() || !q.End.Valid() {
+			return nil, &domain.ValidationError{Field: "quiet_hours", Message: "start and end must be times of day"}
+		}
+		if q.Start == q.End {
+			return nil, &domain.ValidationError{Field: "quiet_hours", Message: "start and end must differ"}
+		}
+	}
+	return s.notifications.SavePreferences(ctx, p)
+}
+
+// DeliverAt returns when an email or push notification to the user may be
+// sent if it is ready now, honoring their do-not-disturb and quiet hours.
+// In-app notifications are stored and streamed regardless.
+func (s *NotificationService) DeliverAt(ctx context.Context, userID int64, now time.Time) (time.Time, error) {
+	p, err := s.notifications.Preferences(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return p.DeliverAt(now), nil
+}
+
+// Watch opens a stream of the user's notification events and returns their
+// unread count as of when it opened. stop must be called once the caller is
+// done.
+func (s *NotificationService) Watch(ctx context.Context, userID int64) (unread int, events <-chan domain.RealtimeEvent, stop func(), err error) {
+	// Subscribe before counting so no change between the two is missed.
+	events, stop = s.broadcaster.Subscribe(userID)
+	if unread, err = s.notifications.UnreadCount(ctx, userID); err != nil {
+		stop()
+		return 0, nil, nil, err
+	}
+	return unread, events, stop, nil
+}
+
+// publishRead tells the user's other devices that notifications were read,
+// with the unread count that remains. Failures are logged: the read state
+// is already saved and clients resynchronize when they reconnect.
+func (s *NotificationService) publishRead(ctx context.Context, userID int64, read domain.NotificationsRead) {
+	if err := s.broadcastRead(ctx, userID, read); err != nil {
+		slog.Error("failed to publish notification read state", "user_id", userID, "error", err)
+	}
+}
+
+func (s *NotificationService) broadcastRead(ctx context.Context, userID int64, read domain.NotificationsRead) error {
+	unread, err := s.notifications.UnreadCount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	read.UnreadCount = unread
+	event, err := realtimeEvent(userID, domain.RealtimeNotificationsRead, read)
+	if err != nil {
+		return err
+	}
+	return s.broadcaster.Publish(ctx, event)
+}
+
+// publishChange tells the user's devices that a notification was snoozed or
+// resurfaced. Failures are logged like those of publishRead.
+func (s *NotificationService) publishChange(ctx context.Context, typ domain.RealtimeEventType, n domain.Notification) {
+	if err := s.broadcastChange(ctx, typ, n); err != nil {
+		slog.Error("failed to publish notification change", "user_id", n.UserID, "notification_id", n.ID, "error", err)
+	}
+}
+
+func (s *NotificationService) broadcastChange(ctx context.Context, typ domain.RealtimeEventType, n domain.Notification) error {
+	unread, err := s.notifications.UnreadCount(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	event, err := realtimeEvent(n.UserID, typ, domain.NotificationChange{Notification: n, UnreadCount: unread})
+	if err != nil {
+		return err
+	}
+	return s.broadcaster.Publish(ctx, event)
+}
+
+func realtimeEvent(userID int64, typ domain.RealtimeEventType, data any) (domain.RealtimeEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.RealtimeEvent{}, fmt.Errorf("encode %s event: %w", typ, err)
+	}
+	return domain.RealtimeEvent{UserID: userID, Type: typ, Data: payload}, nil
+}
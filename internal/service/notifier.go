@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	notifierCursor    = "notification_fanout"
+	notifierBatchSize = 500
+)
+
+// notifiableEvents are the issue events that produce notifications.
+var notifiableEvents = []domain.EventType{domain.EventIssueCreated, domain.EventStatusChanged, domain.EventAIJobFailed}
+
+// CursorStore defines the worker progress interface consumed by background workers.
+type CursorStore interface {
+	Get(ctx context.Context, name string) (domain.LogPosition, error)
+	Set(ctx context.Context, name string, position domain.LogPosition) error
+}
+
+// Notifier turns issue events into notifications for project members. It
+// runs in the background so that request handlers only record the event,
+// however many members a project has. Projects' routing rules are evaluated
+// here too. Delivery is at least once: a crash between fan-out and saving
+// the cursor repeats the last batch.
+type Notifier struct {
+	events        EventStore
+	projects      ProjectStore
+	issues        IssueStore
+	notifications NotificationStore
+	cursors       CursorStore
+	interval      time.Duration
+
+	routes RouteLister
+	labels IssueLabelLister
+	router *NotificationRouter
+}
+
+// RouteLister lists the routing rules in effect for a project.
+type RouteLister interface {
+	ListActive(ctx context.Context, projectID int64) ([]domain.NotificationRoute, error)
+}
+
+// IssueLabelLister lists the labels applied to an issue.
+type IssueLabelLister interface {
+	ListForIssue(ctx context.Context, issueID int64) ([]domain.Label, error)
+}
+
+// NotifierOption configures a Notifier.
+type NotifierOption func(*Notifier)
+
+// WithRouting makes the Notifier also send each notification to the targets
+// of its project's routing rules that match it.
+func WithRouting(routes RouteLister, labels IssueLabelLister, router *NotificationRouter) NotifierOption {
+	return func(n *Notifier) {
+		n.routes = routes
+		n.labels = labels
+		n.router = router
+	}
+}
+
+// NewNotifier creates a Notifier that polls for new events every interval.
+func NewNotifier(events EventStore, projects ProjectStore, issues IssueStore, notifications NotificationStore, cursors CursorStore,
+	interval time.Duration, opts ...NotifierOption) *Notifier {
+	n := &Notifier{
+		events:        events,
+		projects:      projects,
+		issues:        issues,
+		notifications: notifications,
+		cursors:       cursors,
+		interval:      interval,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Run delivers notifications until ctx is cancelled. It must run on a single
+// instance at a time.
+func (n *Notifier) Run(ctx context.Context) error {
+	position, err := n.cursors.Get(ctx, notifierCursor)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		if position, err = n.drain(ctx, position); err != nil && ctx.Err() == nil {
+			slog.Error("notification fan-out failed", "position", position, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain delivers every pending event in batches and returns the new position.
+func (n *Notifier) drain(ctx context.Context, position domain.LogPosition) (domain.LogPosition, error) {
+	for {
+		events, err := n.events.ListAfter(ctx, position, notifiableEvents, notifierBatchSize)
+		if err != nil {
+			return position, fmt.Errorf("list events: %w", err)
+		}
+		if len(events) == 0 {
+			return position, nil
+		}
+
+		members := make(map[int64][]int64)
+		routes := make(map[int64][]domain.NotificationRoute)
+		for _, e := range events {
+			if err := n.deliver(ctx, e, members, routes); err != nil {
+				return position, fmt.Errorf("deliver event %d: %w", e.ID, err)
+			}
+			position = e.Position()
+		}
+
+		if err := n.cursors.Set(ctx, notifierCursor, position); err != nil {
+			return position, err
+		}
+		if len(events) < notifierBatchSize {
+			return position, nil
+		}
+	}
+}
+
+// deliver notifies every project member except the actor about one event,
+// and routes it by the project's routing rules. members and routes cache
+// member lists and rules per project for the current batch.
+func (n *Notifier) deliver(ctx context.Context, e domain.IssueEvent, members map[int64][]int64, routes map[int64][]domain.NotificationRoute) error {
+	issue, err := n.issues.FindByID(ctx, e.IssueID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	notification, ok := notificationFor(e, issue)
+	if !ok {
+		return nil
+	}
+	if err := n.route(ctx, notification, issue, routes); err != nil {
+		return err
+	}
+
+	ids, ok := members[e.ProjectID]
+	if !ok {
+		if ids, err = n.projects.MemberIDs(ctx, e.ProjectID); err != nil {
+			return err
+		}
+		members[e.ProjectID] = ids
+	}
+
+	recipients := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if e.ActorID == nil || id != *e.ActorID {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	_, err = n.notifications.FanOut(ctx, recipients, notification)
+	return err
+}
+
+// route sends a notification to the targets of the matching routing rules
+// of the issue's project. Delivery failures are logged by the router.
+func (n *Notifier) route(ctx context.Context, notification domain.Notification, issue *domain.Issue, cache map[int64][]domain.NotificationRoute) error {
+	if n.router == nil {
+		return nil
+	}
+	routes, ok := cache[issue.ProjectID]
+	if !ok {
+		var err error
+		if routes, err = n.routes.ListActive(ctx, issue.ProjectID); err != nil {
+			return err
+		}
+		cache[issue.ProjectID] = routes
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+
+	labels, err := n.labels.ListForIssue(ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	n.router.Route(ctx, routes, notification, issue, names)
+	return nil
+}
+
+// notificationFor describes the notification an event produces, if any.
+func notificationFor(e domain.IssueEvent, issue *domain.Issue) (domain.Notification, bool) {
+	n := domain.Notification{IssueID: &issue.ID, Message: issue.Title}
+	switch e.Type {
+	case domain.EventIssueCreated:
+		n.Type = domain.NotificationIssueCreated
+		n.Title = "New issue"
+	case domain.EventStatusChanged:
+		if e.Data["to"] != string(domain.IssueStatusCompleted) {
+			return n, false
+		}
+		n.Type = domain.NotificationIssueCompleted
+		n.Title = "Issue completed"
+	case domain.EventAIJobFailed:
+		n.Type = domain.NotificationIssueFailed
+		n.Title = "AI run failed"
+	default:
+		return n, false
+	}
+	return n, true
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// OrganizationStore defines the organization data access interface consumed by services.
+type OrganizationStore interface {
+	Create(ctx context.Context, org domain.Organization) (*domain.Organization, error)
+	FindByID(ctx context.Context, id int64) (*domain.Organization, error)
+	ListForUser(ctx context.Context, userID int64) ([]domain.Organization, error)
+	RoleOf(ctx context.Context, orgID, userID int64) (domain.OrganizationRole, error)
+	UpdateDefaults(ctx context.Context, orgID int64, defaults domain.OrganizationDefaults) (*domain.Organization, error)
+}
+
+// OrganizationService handles organizations and the defaults their projects inherit.
+type OrganizationService struct {
+	orgs OrganizationStore
+}
+
+// NewOrganizationService creates a new OrganizationService.
+func NewOrganizationService(orgs OrganizationStore) *OrganizationService {
+	return &OrganizationService{orgs: orgs}
+}
+
+// Create creates an organization owned by the user.
+func (s *OrganizationService) Create(ctx context.Context, userID int64, name string) (*domain.Organization, error) {
+	org, err := s.orgs.Create(ctx, domain.Organization{Name: name, OwnerID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("create organization: %w", err)
+	}
+	return org, nil
+}
+
+// List returns the organizations the user belongs to.
+func (s *OrganizationService) List(ctx context.Context, userID int64) ([]domain.Organization, error) {
+	return s.orgs.ListForUser(ctx, userID)
+}
+
+// Get returns an organization the user belongs to.
+func (s *OrganizationService) Get(ctx context.Context, userID, orgID int64) (*domain.Organization, error) {
+	if _, err := s.orgs.RoleOf(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.orgs.FindByID(ctx, orgID)
+}
+
+// UpdateDefaults replaces the labels and settings new projects in the
+// organization inherit. Only organization admins may change them. Existing
+// projects keep the settings they were created with.
+func (s *OrganizationService) UpdateDefaults(ctx context.Context, userID, orgID int64, defaults domain.OrganizationDefaults) (*domain.Organization, error) {
+	role, err := s.orgs.RoleOf(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanAdmin() {
+		return nil, domain.ErrForbidden
+	}
+	if defaults.Settings.AI != nil {
+		if err := defaults.Settings.AI.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateIssueTemplates(defaults.Settings.IssueTemplates); err != nil {
+		return nil, err
+	}
+	return s.orgs.UpdateDefaults(ctx, orgID, defaults)
+}
+
+// inheritDefaults applies an organization's defaults beneath a new project's
+// own settings and labels. Members of the organization may create projects in it.
+func inheritDefaults(ctx context.Context, orgs OrganizationStore, userID, orgID int64, project *domain.Project, labels []domain.LabelSpec) ([]domain.LabelSpec, error) {
+	if _, err := orgs.RoleOf(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	org, err := orgs.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	project.OrganizationID = &org.ID
+	project.Settings = project.Settings.Inherit(org.Defaults.Settings)
+	return domain.MergeLabels(org.Defaults.Labels, labels), nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// partitionLookahead is how many months beyond the current one have
+// partitions created in advance, so inserts never fall into the default
+// partition.
+const partitionLookahead = 2
+
+// PartitionStore defines the partition management interface consumed by PartitionMaintainer.
+type PartitionStore interface {
+	Ensure(ctx context.Context, table string, month time.Time) error
+	DropBefore(ctx context.Context, table string, cutoff time.Time) ([]string, error)
+	PruneDefault(ctx context.Context, table string, cutoff time.Time) (int64, error)
+}
+
+// PartitionMaintainer keeps monthly partitions of high-volume tables ahead
+// of time and drops partitions past their retention period, along with rows
+// of the same age left in the default partition.
+type PartitionMaintainer struct {
+	partitions PartitionStore
+	retention  map[string]time.Duration
+	interval   time.Duration
+}
+
+// NewPartitionMaintainer creates a PartitionMaintainer for the tables in
+// retention, keyed by table name. A zero retention keeps data forever.
+func NewPartitionMaintainer(partitions PartitionStore, retention map[string]time.Duration, interval time.Duration) *PartitionMaintainer {
+	return &PartitionMaintainer{partitions: partitions, retention: retention, interval: interval}
+}
+
+// Run maintains partitions until ctx is cancelled. It must run on a single
+// instance at a time.
+func (m *PartitionMaintainer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		for table, retention := range m.retention {
+			m.maintain(ctx, table, retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *PartitionMaintainer) maintain(ctx context.Context, table string, retention time.Duration) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= partitionLookahead; i++ {
+		if err := m.partitions.Ensure(ctx, table, month.AddDate(0, i, 0)); err != nil {
+			slog.Error("partition creation failed", "table", table, "error", err)
+			return
+		}
+	}
+
+	if retention <= 0 {
+		return
+	}
+	cutoff := now.Add(-retention)
+	dropped, err := m.partitions.DropBefore(ctx, table, cutoff)
+	if err != nil {
+		slog.Error("partition retention failed", "table", table, "error", err)
+	}
+	if len(dropped) > 0 {
+		slog.Info("partitions dropped", "table", table, "partitions", dropped)
+	}
+
+	pruned, err := m.partitions.PruneDefault(ctx, table, cutoff)
+	if err != nil {
+		slog.Error("default partition retention failed", "table", table, "error", err)
+	}
+	if pruned > 0 {
+		slog.Info("default partition pruned", "table", table, "rows", pruned)
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingPartitions is a PartitionStore that records retention calls.
+type recordingPartitions struct {
+	ensured   int
+	dropErr   error
+	dropCuts  []time.Time
+	pruneCuts []time.Time
+}
+
+func (p *recordingPartitions) Ensure(context.Context, string, time.Time) error {
+	p.ensured++
+	return nil
+}
+
+func (p *recordingPartitions) DropBefore(_ context.Context, _ string, cutoff time.Time) ([]string, error) {
+	p.dropCuts = append(p.dropCuts, cutoff)
+	return nil, p.dropErr
+}
+
+func (p *recordingPartitions) PruneDefault(_ context.Context, _ string, cutoff time.Time) (int64, error) {
+	p.pruneCuts = append(p.pruneCuts, cutoff)
+	return 0, nil
+}
+
+func TestPartitionMaintainerRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention time.Duration
+		dropErr   error
+		wantPrune bool
+	}{
+		{name: "kept forever", retention: 0},
+		{name: "retention", retention: 90 * 24 * time.Hour, wantPrune: true},
+		{name: "drop fails", retention: 90 * 24 * time.Hour, dropErr: errors.New("boom"), wantPrune: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &recordingPartitions{dropErr: tt.dropErr}
+			m := NewPartitionMaintainer(store, nil, time.Hour)
+			m.maintain(context.Background(), "audit_logs", tt.retention)
+
+			if store.ensured != partitionLookahead+1 {
+				t.Errorf("ensured %d partitions, want %d", store.ensured, partitionLookahead+1)
+			}
+			if !tt.wantPrune {
+				if len(store.dropCuts)+len(store.pruneCuts) != 0 {
+					t.Errorf("retention ran with no retention period")
+				}
+				return
+			}
+			if len(store.pruneCuts) != 1 || len(store.dropCuts) != 1 {
+				t.Fatalf("DropBefore called %d times, PruneDefault %d times, want 1 each", len(store.dropCuts), len(store.pruneCuts))
+			}
+			if !store.pruneCuts[0].Equal(store.dropCuts[0]) {
+				t.Errorf("pruned default partition before %v, dropped partitions before %v", store.pruneCuts[0], store.dropCuts[0])
+			}
+		})
+	}
+}
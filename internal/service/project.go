@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// ProjectStore defines the project data access interface consumed by services.
+type ProjectStore interface {
+	FindByID(ctx context.Context, id int64) (*domain.Project, error)
+	ListForUser(ctx context.Context, userID int64, filter domain.ProjectFilter) ([]domain.ProjectSummary, error)
+	RoleOf(ctx context.Context, projectID, userID int64) (domain.ProjectRole, error)
+	MemberIDs(ctx context.Context, projectID int64) ([]int64, error)
+	Create(ctx context.Context, project domain.Project, labels []domain.LabelSpec) (*domain.Project, error)
+	Update(ctx context.Context, project domain.Project, pre domain.Precondition) (*domain.Project, error)
+	Delete(ctx context.Context, id, by int64, pre domain.Precondition) error
+	SetAIPaused(ctx context.Context, id int64, paused bool) (*domain.Project, error)
+	SetNextIssueNumber(ctx context.Context, id, n int64, pre domain.Precondition) error
+}
+
+// ProjectService handles project business logic.
+type ProjectService struct {
+	projects ProjectStore
+	orgs     OrganizationStore
+	audit    AuditStore
+}
+
+// NewProjectService creates a new ProjectService.
+func NewProjectService(projects ProjectStore, orgs OrganizationStore, audit AuditStore) *ProjectService {
+	return &ProjectService{projects: projects, orgs: orgs, audit: audit}
+}
+
+// Create creates a project owned by the user from the project's name, key and
+// description. If orgID is set, the project joins that organization and
+// starts from its default labels and settings.
+func (s *ProjectService) Create(ctx context.Context, userID int64, orgID *int64, project domain.Project) (*domain.Project, error) {
+	if project.Key != nil && !domain.ValidProjectKey(*project.Key) {
+		return nil, invalidProjectKey()
+	}
+	project.OwnerID = userID
+
+	var labels []domain.LabelSpec
+	if orgID != nil {
+		var err error
+		if labels, err = inheritDefaults(ctx, s.orgs, userID, *orgID, &project, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.projects.Create(ctx, project, labels)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Get returns a project the user owns or is a member of.
+func (s *ProjectService) Get(ctx context.Context, userID, projectID int64) (*domain.Project, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.projects.FindByID(ctx, projectID)
+}
+
+// ProjectPage is a single page of projects.
+type ProjectPage struct {
+	Projects   []domain.ProjectSummary
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// List returns a page of projects the user owns or is a member of.
+func (s *ProjectService) List(ctx context.Context, userID int64, filter domain.ProjectFilter) (*ProjectPage, error) {
+	filter.Limit = clampPageSize(filter.Limit)
+
+	projects, err := s.projects.ListForUser(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
+
+	page := &ProjectPage{Projects: projects}
+	if len(projects) > filter.Limit {
+		page.Projects = projects[:filter.Limit]
+		page.HasNext = true
+		last := page.Projects[len(page.Projects)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// Update applies a partial update to a project. Only project admins may edit it.
+// Issue numbering can restart at any number above those already in use.
+func (s *ProjectService) Update(ctx context.Context, userID, projectID int64, patch domain.ProjectPatch, pre domain.Precondition) (*domain.Project, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		project.Name = *patch.Name
+	}
+	if patch.Key != nil {
+		switch {
+		case *patch.Key == "":
+			project.Key = nil
+		case domain.ValidProjectKey(*patch.Key):
+			project.Key = patch.Key
+		default:
+			return nil, invalidProjectKey()
+		}
+	}
+	if patch.Description != nil {
+		project.Description = patch.Description
+	}
+	if patch.ArchiveAfterDays != nil {
+		project.Settings.ArchiveAfterDays = *patch.ArchiveAfterDays
+	}
+	if patch.AI != nil {
+		if err := patch.AI.Validate(); err != nil {
+			return nil, err
+		}
+		project.Settings.AI = patch.AI
+	}
+	if patch.Critical != nil {
+		project.Settings.Critical = *patch.Critical
+	}
+	if patch.CloseApproval != nil {
+		switch *patch.CloseApproval {
+		case "", domain.ProjectRoleAdmin, domain.ProjectRoleOwner:
+			project.Settings.CloseApproval = *patch.CloseApproval
+		default:
+			return nil, &domain.ValidationError{Field: "close_approval", Message: "must be admin, owner or empty"}
+		}
+	}
+	if patch.IssueTemplates != nil {
+		if err := validateIssueTemplates(*patch.IssueTemplates); err != nil {
+			return nil, err
+		}
+		project.Settings.IssueTemplates = *patch.IssueTemplates
+	}
+	if n := patch.NextIssueNumber; n != nil && *n != project.NextIssueNumber {
+		if *n < 1 {
+			return nil, &domain.ValidationError{Field: "next_issue_number", Message: "must be at least 1"}
+		}
+		if err := s.projects.SetNextIssueNumber(ctx, projectID, *n, pre); err != nil {
+			return nil, err
+		}
+	}
+	return s.projects.Update(ctx, *project, pre)
+}
+
+// Delete moves a project and everything in it to the owner's trash. Only the
+// owner may delete it.
+func (s *ProjectService) Delete(ctx context.Context, userID, projectID int64, pre domain.Precondition) error {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return err
+	}
+	if role != domain.ProjectRoleOwner {
+		return domain.ErrForbidden
+	}
+	if err := s.projects.Delete(ctx, projectID, userID, pre); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditProjectDeleted, domain.AuditTargetProject, projectID)
+}
+
+// SetAIPaused pauses or resumes AI processing for a project. Queued jobs stay
+// queued while paused and are picked up again on resume. Only project admins
+// may do this.
+func (s *ProjectService) SetAIPaused(ctx context.Context, userID, projectID int64, paused bool) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	current, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if (current.AIPausedAt != nil) == paused {
+		return nil
+	}
+
+	if _, err := s.projects.SetAIPaused(ctx, projectID, paused); err != nil {
+		return err
+	}
+	action := domain.AuditAIResumed
+	if paused {
+		action = domain.AuditAIPaused
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, action, domain.AuditTargetProject, projectID)
+}
+
+// validateIssueTemplates checks a project's issue templates, whose names
+// must be unique.
+func validateIssueTemplates(templates []domain.IssueTemplate) error {
+	names := make(map[string]bool, len(templates))
+	for _, t := range templates {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		if names[t.Name] {
+			return &domain.ValidationError{Field: "issue_templates.name", Message: fmt.Sprintf("duplicate template %q", t.Name)}
+		}
+		names[t.Name] = true
+	}
+	return nil
+}
+
+// authorizeProject returns the user's role in a project. Projects the user
+// cannot access are reported as not found so their existence is not leaked.
+func authorizeProject(ctx context.Context, projects ProjectStore, userID, projectID int64) (domain.ProjectRole, error) {
+	return projects.RoleOf(ctx, projectID, userID)
+}
+
+// authorizeAdmin verifies the user can administer the project.
+func authorizeAdmin(ctx context.Context, projects ProjectStore, userID, projectID int64) error {
+	role, err := authorizeProject(ctx, projects, userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !role.CanAdmin() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func invalidProjectKey() error {
+	return &domain.ValidationError{
+		Field:   "key",
+		Message: "must be 2 to 10 upper-case letters and digits, starting with a letter",
+	}
+}
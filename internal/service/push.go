@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/push"
+)
+
+const (
+	pushCursor    = "push_dispatch"
+	pushBatchSize = 500
+)
+
+// PushQueue defines the notification data access interface consumed by PushDispatcher.
+type PushQueue interface {
+	ListAfter(ctx context.Context, after domain.LogPosition, limit int) ([]domain.Notification, error)
+	LatestPosition(ctx context.Context) (domain.LogPosition, error)
+	QueuePush(ctx context.Context, items []domain.PushItem) error
+	TakeDuePushes(ctx context.Context, limit int) ([]domain.Notification, error)
+}
+
+// PushDispatcher pushes new notifications to their users' devices. A
+// notification that arrives during the user's quiet hours or do-not-disturb
+// is held until it ends, and is dropped if the user reads or snoozes it
+// meanwhile. Pushes are sent at most once.
+type PushDispatcher struct {
+	notifications PushQueue
+	devices       DeviceStore
+	preferences   *NotificationService
+	cursors       CursorStore
+	senders       map[domain.PushPlatform]push.Sender
+	interval      time.Duration
+}
+
+// NewPushDispatcher creates a PushDispatcher that polls every interval and
+// sends through the sender of each device's platform. Devices of platforms
+// without a sender are skipped.
+func NewPushDispatcher(notifications PushQueue, devices DeviceStore, preferences *NotificationService, cursors CursorStore,
+	senders map[domain.PushPlatform]push.Sender, interval time.Duration) *PushDispatcher {
+	return &PushDispatcher{
+		notifications: notifications,
+		devices:       devices,
+		preferences:   preferences,
+		cursors:       cursors,
+		senders:       senders,
+		interval:      interval,
+	}
+}
+
+// Run pushes notifications until ctx is cancelled. It must run on a single
+// instance at a time. On its first run it starts from the newest
+// notification rather than pushing the whole history.
+func (d *PushDispatcher) Run(ctx context.Context) error {
+	position, err := d.cursors.Get(ctx, pushCursor)
+	if err != nil {
+		return err
+	}
+	if position.IsZero() {
+		if position, err = d.notifications.LatestPosition(ctx); err != nil {
+			return err
+		}
+		if err := d.cursors.Set(ctx, pushCursor, position); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if position, err = d.queue(ctx, position); err != nil && ctx.Err() == nil {
+			slog.Error("push queueing failed", "position", position, "error", err)
+		}
+		if err := d.sendDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("push delivery failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// queue schedules every new notification of a user with a device and
+// returns the new position.
+func (d *PushDispatcher) queue(ctx context.Context, position domain.LogPosition) (domain.LogPosition, error) {
+	for {
+		notifications, err := d.notifications.ListAfter(ctx, position, pushBatchSize)
+		if err != nil {
+			return position, fmt.Errorf("list notifications: %w", err)
+		}
+		if len(notifications) == 0 {
+			return position, nil
+		}
+
+		userIDs := make([]int64, 0, len(notifications))
+		for _, n := range notifications {
+			userIDs = append(userIDs, n.UserID)
+		}
+		withDevices, err := d.devices.UsersWithDevices(ctx, userIDs)
+		if err != nil {
+			return position, err
+		}
+		deliverAt := make(map[int64]time.Time, len(withDevices))
+		now := time.Now()
+		for _, id := range withDevices {
+			if deliverAt[id], err = d.preferences.DeliverAt(ctx, id, now); err != nil {
+				return position, err
+			}
+		}
+
+		var items []domain.PushItem
+		for _, n := range notifications {
+			if at, ok := deliverAt[n.UserID]; ok {
+				items = append(items, domain.PushItem{NotificationID: n.ID, DeliverAt: at})
+			}
+		}
+		if err := d.notifications.QueuePush(ctx, items); err != nil {
+			return position, err
+		}
+
+		position = notifications[len(notifications)-1].Position()
+		if err := d.cursors.Set(ctx, pushCursor, position); err != nil {
+			return position, err
+		}
+		if len(notifications) < pushBatchSize {
+			return position, nil
+		}
+	}
+}
+
+// sendDue pushes every queued notification that is due.
+func (d *PushDispatcher) sendDue(ctx context.Context) error {
+	for {
+		due, err := d.notifications.TakeDuePushes(ctx, pushBatchSize)
+		if err != nil {
+			return err
+		}
+
+		devices := make(map[int64][]domain.Device)
+		for _, n := range due {
+			if _, ok := devices[n.UserID]; !ok {
+				if devices[n.UserID], err = d.devices.ListForUser(ctx, n.UserID); err != nil {
+					return err
+				}
+			}
+			for _, device := range devices[n.UserID] {
+				d.send(ctx, device, n)
+			}
+		}
+
+		if len(due) < pushBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// send pushes a notification to one device, forgetting the device if the
+// push service rejects its token. Other failures are logged.
+func (d *PushDispatcher) send(ctx context.Context, device domain.Device, n domain.Notification) {
+	sender, ok := d.senders[device.Platform]
+	if !ok {
+		return
+	}
+
+	msg := push.Message{
+		Title: n.Title,
+		Body:  n.Message,
+		Data: map[string]string{
+			"notification_id": strconv.FormatInt(n.ID, 10),
+			"type":            string(n.Type),
+		},
+	}
+	if n.IssueID != nil {
+		msg.Data["issue_id"] = strconv.FormatInt(*n.IssueID, 10)
+	}
+
+	err := sender.Send(ctx, device.Token, msg)
+	switch {
+	case err == nil:
+	case errors.Is(err, push.ErrInvalidToken):
+		slog.Info("forgetting device with invalid push token", "device_id", device.ID, "user_id", device.UserID)
+		if err := d.devices.Forget(ctx, device.ID); err != nil {
+			slog.Error("failed to forget device", "device_id", device.ID, "error", err)
+		}
+	default:
+		slog.Warn("push failed",
+			"device_id", device.ID,
+			"platform", device.Platform,
+			"notification_id", n.ID,
+			"error", err,
+		)
+	}
+}
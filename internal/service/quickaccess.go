@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// QuickAccessStore defines the recent/starred data access interface consumed by QuickAccessService.
+type QuickAccessStore interface {
+	RecordView(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error
+	ListRecent(ctx context.Context, userID int64, limit int) ([]domain.QuickAccessItem, error)
+	Star(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error
+	Unstar(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error
+	ListStarred(ctx context.Context, userID int64, limit int) ([]domain.QuickAccessItem, error)
+}
+
+// QuickAccessService tracks recently viewed and starred projects and issues.
+type QuickAccessService struct {
+	projects ProjectStore
+	issues   IssueStore
+	items    QuickAccessStore
+}
+
+// NewQuickAccessService creates a new QuickAccessService.
+func NewQuickAccessService(projects ProjectStore, issues IssueStore, items QuickAccessStore) *QuickAccessService {
+	return &QuickAccessService{projects: projects, issues: issues, items: items}
+}
+
+// RecordView records that the user viewed a project or issue.
+func (s *QuickAccessService) RecordView(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	if err := s.authorizeItem(ctx, userID, itemType, itemID); err != nil {
+		return err
+	}
+	if err := s.items.RecordView(ctx, userID, itemType, itemID); err != nil {
+		return fmt.Errorf("record view: %w", err)
+	}
+	return nil
+}
+
+// Recent returns the user's recently viewed items.
+func (s *QuickAccessService) Recent(ctx context.Context, userID int64, limit int) ([]domain.QuickAccessItem, error) {
+	return s.items.ListRecent(ctx, userID, clampPageSize(limit))
+}
+
+// Star adds a project or issue to the user's starred items.
+func (s *QuickAccessService) Star(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	if err := s.authorizeItem(ctx, userID, itemType, itemID); err != nil {
+		return err
+	}
+	return s.items.Star(ctx, userID, itemType, itemID)
+}
+
+// Unstar removes a project or issue from the user's starred items.
+func (s *QuickAccessService) Unstar(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	if !itemType.Valid() {
+		return &domain.ValidationError{Field: "item_type", Message: "must be project or issue"}
+	}
+	return s.items.Unstar(ctx, userID, itemType, itemID)
+}
+
+// Starred returns the user's starred items.
+func (s *QuickAccessService) Starred(ctx context.Context, userID int64, limit int) ([]domain.QuickAccessItem, error) {
+	return s.items.ListStarred(ctx, userID, clampPageSize(limit))
+}
+
+// authorizeItem verifies the item exists and the user can access its project.
+func (s *QuickAccessService) authorizeItem(ctx context.Context, userID int64, itemType domain.ItemType, itemID int64) error {
+	projectID := itemID
+	switch itemType {
+	case domain.ItemTypeProject:
+	case domain.ItemTypeIssue:
+		issue, err := s.issues.FindByID(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		projectID = issue.ProjectID
+	default:
+		return &domain.ValidationError{Field: "item_type", Message: "must be project or issue"}
+	}
+
+	_, err := authorizeProject(ctx, s.projects, userID, projectID)
+	return err
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// ReferenceStore defines the issue reference data access interface consumed by services.
+type ReferenceStore interface {
+	Replace(ctx context.Context, sourceIssueID int64, commentID *int64, keys []domain.IssueKey) error
+	References(ctx context.Context, issueID, viewerID int64) ([]domain.IssueReference, error)
+	ReferencedBy(ctx context.Context, issueID, viewerID int64) ([]domain.IssueReference, error)
+	ReplaceWikiLinks(ctx context.Context, issueID int64, commentID *int64, slugs []string) error
+	WikiPages(ctx context.Context, issueID int64) ([]domain.WikiPageSummary, error)
+	WikiLinkedFrom(ctx context.Context, projectID int64, slug string) ([]domain.IssueReference, error)
+}
+
+// syncReferences records the issue references and wiki links in texts as
+// made by the issue, or by one of its comments if commentID is set. Failures
+// are logged rather than returned so that a missing backlink never fails the
+// edit that made it.
+func syncReferences(ctx context.Context, refs ReferenceStore, issueID int64, commentID *int64, texts ...string) {
+	if err := refs.Replace(ctx, issueID, commentID, domain.ParseReferences(texts...)); err != nil {
+		slog.Error("failed to record issue references", "issue_id", issueID, "error", err)
+	}
+	if err := refs.ReplaceWikiLinks(ctx, issueID, commentID, domain.ParseWikiLinks(texts...)); err != nil {
+		slog.Error("failed to record wiki links", "issue_id", issueID, "error", err)
+	}
+}
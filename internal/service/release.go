@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// maxReleaseRange is the longest period release notes may cover.
+const maxReleaseRange = 366 * 24 * time.Hour
+
+// ReleaseStore defines the completed issue data access interface consumed
+// by ReleaseService.
+type ReleaseStore interface {
+	Completed(ctx context.Context, projectID int64, scope domain.ReleaseScope) ([]domain.ReleaseIssue, error)
+}
+
+// Completer answers writing prompts with the AI backend.
+type Completer interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// ReleaseService generates release notes from completed issues.
+type ReleaseService struct {
+	projects   ProjectStore
+	milestones MilestoneStore
+	releases   ReleaseStore
+	completer  Completer
+}
+
+// NewReleaseService creates a new ReleaseService that polishes notes with
+// completer on request.
+func NewReleaseService(projects ProjectStore, milestones MilestoneStore, releases ReleaseStore, completer Completer) *ReleaseService {
+	return &ReleaseService{projects: projects, milestones: milestones, releases: releases, completer: completer}
+}
+
+// ReleaseNotesRequest selects the issues release notes cover and how they
+// are written. Groups are the labels to section the notes by, in order; see
+// domain.GroupReleaseNotes.
+type ReleaseNotesRequest struct {
+	Scope  domain.ReleaseScope
+	Groups []string
+	Polish bool
+}
+
+// ReleaseNotesResult is generated release notes as Markdown. Polished
+// reports whether the AI backend rewrote them.
+type ReleaseNotesResult struct {
+	Markdown string
+	Polished bool
+}
+
+// Generate writes release notes for a project the user can access from the
+// issues completed in a milestone or a period. If polishing is requested
+// but the AI backend fails, the plain notes are returned.
+func (s *ReleaseService) Generate(ctx context.Context, userID, projectID int64, req ReleaseNotesRequest) (*ReleaseNotesResult, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	var title string
+	if req.Scope.MilestoneID != nil {
+		milestone, err := s.milestones.FindByID(ctx, *req.Scope.MilestoneID)
+		if errors.Is(err, domain.ErrNotFound) || (err == nil && milestone.ProjectID != projectID) {
+			return nil, &domain.ValidationError{Field: "milestone_id", Message: "is not a milestone of this project"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		title = milestone.Title
+	} else {
+		if !req.Scope.To.After(req.Scope.From) {
+			return nil, &domain.ValidationError{Field: "to", Message: "must not be before from"}
+		}
+		if req.Scope.To.Sub(req.Scope.From) > maxReleaseRange {
+			return nil, &domain.ValidationError{Field: "to", Message: "must be within a year of from"}
+		}
+		title = fmt.Sprintf("Changes from %s to %s",
+			req.Scope.From.Format(time.DateOnly), req.Scope.To.AddDate(0, 0, -1).Format(time.DateOnly))
+	}
+
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Polish && project.AIPausedAt != nil {
+		return nil, fmt.Errorf("%w: AI is paused for this project", domain.ErrConflict)
+	}
+
+	issues, err := s.releases.Completed(ctx, projectID, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+	notes := domain.GroupReleaseNotes(title, issues, req.Groups)
+	result := &ReleaseNotesResult{Markdown: notes.Markdown()}
+	if !req.Polish || len(notes.Sections) == 0 {
+		return result, nil
+	}
+
+	polished, err := s.completer.Complete(ctx, releasePolishPrompt(project.Name, result.Markdown))
+	if err != nil || strings.TrimSpace(polished) == "" {
+		slog.Warn("release notes not polished", "project_id", projectID, "error", err)
+		return result, nil
+	}
+	result.Markdown = strings.TrimSpace(polished) + "\n"
+	result.Polished = true
+	return result, nil
+}
+
+// releasePolishPrompt asks the AI backend to rewrite plain release notes.
+func releasePolishPrompt(project, notes string) string {
+	return `Rewrite the following release notes for the project "` + project + `" so they read well for its users. ` +
+		`Keep the Markdown structure: the title, one section per heading and one bullet per issue. ` +
+		`Reword each bullet as a short description of the change, keep its issue reference in parentheses, ` +
+		`and do not add, drop or merge issues. Reply with the Markdown only.
+
+` + notes
+}
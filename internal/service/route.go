@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/mailer"
+	"github.com/sumire/issues/internal/slack"
+)
+
+const (
+	maxRouteName    = 100
+	maxRouteTargets = 20
+	maxRouteLabels  = 20
+)
+
+// NotificationRouteStore defines the routing rule data access interface
+// consumed by NotificationRouteService.
+type NotificationRouteStore interface {
+	ListByProject(ctx context.Context, projectID int64) ([]domain.NotificationRoute, error)
+	FindByID(ctx context.Context, projectID, id int64) (*domain.NotificationRoute, error)
+	Create(ctx context.Context, route domain.NotificationRoute) (*domain.NotificationRoute, error)
+	Update(ctx context.Context, route domain.NotificationRoute) (*domain.NotificationRoute, error)
+	Delete(ctx context.Context, projectID, id int64) error
+}
+
+// NotificationRouteService manages project notification routing rules.
+type NotificationRouteService struct {
+	projects ProjectStore
+	routes   NotificationRouteStore
+	audit    AuditStore
+}
+
+// NewNotificationRouteService creates a new NotificationRouteService.
+func NewNotificationRouteService(projects ProjectStore, routes NotificationRouteStore, audit AuditStore) *NotificationRouteService {
+	return &NotificationRouteService{projects: projects, routes: routes, audit: audit}
+}
+
+// List returns a project's routing rules. Only project admins may manage
+// routing rules.
+func (s *NotificationRouteService) List(ctx context.Context, userID, projectID int64) ([]domain.NotificationRoute, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.routes.ListByProject(ctx, projectID)
+}
+
+// Create adds a routing rule to a project.
+func (s *NotificationRouteService) Create(ctx context.Context, userID, projectID int64, route domain.NotificationRoute) (*domain.NotificationRoute, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	route.ProjectID = projectID
+	route.CreatedBy = &userID
+	if err := validateRoute(&route); err != nil {
+		return nil, err
+	}
+
+	created, err := s.routes.Create(ctx, route)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditRouteCreated, domain.AuditTargetRoute, created.ID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Update partially updates a project's routing rule.
+func (s *NotificationRouteService) Update(ctx context.Context, userID, projectID, routeID int64, patch domain.NotificationRoutePatch) (*domain.NotificationRoute, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	route, err := s.routes.FindByID(ctx, projectID, routeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		route.Name = *patch.Name
+	}
+	if patch.Match != nil {
+		route.Match = *patch.Match
+	}
+	if patch.Targets != nil {
+		route.Targets = *patch.Targets
+	}
+	if patch.Active != nil {
+		route.Active = *patch.Active
+	}
+	if err := validateRoute(route); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.routes.Update(ctx, *route)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditRouteUpdated, domain.AuditTargetRoute, routeID); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete removes a project's routing rule.
+func (s *NotificationRouteService) Delete(ctx context.Context, userID, projectID, routeID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if err := s.routes.Delete(ctx, projectID, routeID); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditRouteDeleted, domain.AuditTargetRoute, routeID)
+}
+
+// validateRoute checks a routing rule and normalizes its name, labels and
+// targets.
+func validateRoute(route *domain.NotificationRoute) error {
+	var errs domain.ValidationErrors
+	route.Name = strings.TrimSpace(route.Name)
+	if route.Name == "" || len(route.Name) > maxRouteName {
+		errs = append(errs, &domain.ValidationError{Field: "name", Message: fmt.Sprintf("must be 1 to %d characters", maxRouteName)})
+	}
+	for _, t := range route.Match.Types {
+		if !slices.Contains(domain.RoutableNotificationTypes, t) {
+			errs = append(errs, &domain.ValidationError{Field: "match.types", Message: fmt.Sprintf("unknown notification type %q", t)})
+		}
+	}
+	route.Match.Labels = trimAll(route.Match.Labels)
+	if len(route.Match.Labels) > maxRouteLabels {
+		errs = append(errs, &domain.ValidationError{Field: "match.labels", Message: fmt.Sprintf("must list at most %d labels", maxRouteLabels)})
+	}
+
+	route.Targets.Emails = trimAll(route.Targets.Emails)
+	route.Targets.SlackChannels = trimAll(route.Targets.SlackChannels)
+	switch {
+	case route.Targets.Empty():
+		errs = append(errs, &domain.ValidationError{Field: "targets", Message: "must name an email address or a Slack channel"})
+	case len(route.Targets.Emails)+len(route.Targets.SlackChannels) > maxRouteTargets:
+		errs = append(errs, &domain.ValidationError{Field: "targets", Message: fmt.Sprintf("must name at most %d targets", maxRouteTargets)})
+	}
+	for _, email := range route.Targets.Emails {
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			errs = append(errs, &domain.ValidationError{Field: "targets.emails", Message: fmt.Sprintf("%q is not an email address", email)})
+		}
+	}
+	for _, channel := range route.Targets.SlackChannels {
+		if strings.ContainsAny(channel, " \t\r\n") {
+			errs = append(errs, &domain.ValidationError{Field: "targets.slack_channels", Message: fmt.Sprintf("%q is not a channel ID or #name", channel)})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// trimAll trims every string and drops empty ones.
+func trimAll(values []string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SlackPoster posts messages to Slack channels.
+type SlackPoster interface {
+	Post(ctx context.Context, channel string, msg *slack.Message) error
+}
+
+// NotificationRouter sends notifications to the targets of the routing
+// rules they match. Targets of a kind without a sender are skipped.
+type NotificationRouter struct {
+	email    mailer.Sender
+	slack    SlackPoster
+	linkBase string
+}
+
+// NewNotificationRouter creates a NotificationRouter. Either sender may be
+// nil. Messages link to issues in the web app at linkBase.
+func NewNotificationRouter(email mailer.Sender, slack SlackPoster, linkBase string) *NotificationRouter {
+	return &NotificationRouter{email: email, slack: slack, linkBase: strings.TrimRight(linkBase, "/")}
+}
+
+// Route sends a notification about an issue to the targets of every rule
+// it matches. Failures are logged; routing is best effort.
+func (r *NotificationRouter) Route(ctx context.Context, routes []domain.NotificationRoute, n domain.Notification, issue *domain.Issue, labels []string) {
+	var link string
+	if r.linkBase != "" {
+		link = fmt.Sprintf("%s/projects/%d/issues/%d", r.linkBase, issue.ProjectID, issue.ID)
+	}
+	for _, route := range routes {
+		if !route.Match.Matches(n.Type, labels) {
+			continue
+		}
+		if r.email != nil {
+			for _, to := range route.Targets.Emails {
+				if err := r.sendEmail(ctx, route, n, link, to); err != nil {
+					slog.Warn("routed email failed", "route_id", route.ID, "issue_id", issue.ID, "error", err)
+				}
+			}
+		}
+		if r.slack != nil {
+			text := fmt.Sprintf("*%s*: %s", n.Title, slackEscape(n.Message))
+			if link != "" {
+				text = fmt.Sprintf("*%s*: <%s|%s>", n.Title, link, slackEscape(n.Message))
+			}
+			for _, channel := range route.Targets.SlackChannels {
+				msg := &slack.Message{Text: n.Title + ": " + n.Message, Blocks: []slack.Block{slack.Section(text)}}
+				if err := r.slack.Post(ctx, channel, msg); err != nil {
+					slog.Warn("routed slack message failed", "route_id", route.ID, "issue_id", issue.ID, "channel", channel, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// sendEmail emails a routed notification to one address.
+func (r *NotificationRouter) sendEmail(ctx context.Context, route domain.NotificationRoute, n domain.Notification, link, to string) error {
+	name := string(n.Type) + ".html"
+	if !mailer.Has(name) {
+		return nil
+	}
+	html, err := mailer.Render(name, notificationEmail{
+		Title:    n.Title,
+		Message:  n.Message,
+		IssueURL: link,
+		Reason:   fmt.Sprintf("You are receiving this because of the project's notification routing rule %q.", route.Name),
+	})
+	if err != nil {
+		return err
+	}
+	return r.email.Send(ctx, mailer.Message{
+		To:      to,
+		Subject: n.Title + ": " + n.Message,
+		HTML:    html,
+		Text:    n.Title + "\n\n" + n.Message + "\n\n" + link,
+	})
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control
+// characters.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
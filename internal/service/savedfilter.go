@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/filterquery"
+)
+
+// SavedFilterStore defines the saved filter data access interface consumed
+// by services.
+type SavedFilterStore interface {
+	ListForUser(ctx context.Context, projectID, userID int64) ([]domain.SavedFilter, error)
+	FindByID(ctx context.Context, id int64) (*domain.SavedFilter, error)
+	Create(ctx context.Context, filter domain.SavedFilter) (*domain.SavedFilter, error)
+	Update(ctx context.Context, filter domain.SavedFilter) (*domain.SavedFilter, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// IssueLister lists a project's issues for a user, applying the same rules
+// as the issues API.
+type IssueLister interface {
+	List(ctx context.Context, userID, projectID int64, filter domain.IssueFilter) (*IssuePage, error)
+}
+
+// SavedFilterService handles users' saved issue views. Saved filters are
+// private to the user who saved them.
+type SavedFilterService struct {
+	projects ProjectStore
+	filters  SavedFilterStore
+	issues   IssueLister
+}
+
+// NewSavedFilterService creates a new SavedFilterService.
+func NewSavedFilterService(projects ProjectStore, filters SavedFilterStore, issues IssueLister) *SavedFilterService {
+	return &SavedFilterService{projects: projects, filters: filters, issues: issues}
+}
+
+// List returns the user's saved filters in a project.
+func (s *SavedFilterService) List(ctx context.Context, userID, projectID int64) ([]domain.SavedFilter, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.filters.ListForUser(ctx, projectID, userID)
+}
+
+// Get returns one of the user's saved filters in a project.
+func (s *SavedFilterService) Get(ctx context.Context, userID, projectID, filterID int64) (*domain.SavedFilter, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.findOwnFilter(ctx, userID, projectID, filterID)
+}
+
+// Create saves a named filter expression for the user in a project. The
+// expression is rejected if it does not parse.
+func (s *SavedFilterService) Create(ctx context.Context, userID, projectID int64, filter domain.SavedFilter) (*domain.SavedFilter, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	filter.ProjectID = projectID
+	filter.UserID = userID
+	filter.Name = strings.TrimSpace(filter.Name)
+	filter.Query = strings.TrimSpace(filter.Query)
+	if err := validateSavedFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.filters.Create(ctx, filter)
+}
+
+// Update renames one of the user's saved filters or changes its
+// expression.
+func (s *SavedFilterService) Update(ctx context.Context, userID, projectID, filterID int64, patch domain.SavedFilterPatch) (*domain.SavedFilter, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	filter, err := s.findOwnFilter(ctx, userID, projectID, filterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil {
+		filter.Name = strings.TrimSpace(*patch.Name)
+	}
+	if patch.Query != nil {
+		filter.Query = strings.TrimSpace(*patch.Query)
+	}
+	if err := validateSavedFilter(*filter); err != nil {
+		return nil, err
+	}
+	return s.filters.Update(ctx, *filter)
+}
+
+// Delete removes one of the user's saved filters.
+func (s *SavedFilterService) Delete(ctx context.Context, userID, projectID, filterID int64) error {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := s.findOwnFilter(ctx, userID, projectID, filterID); err != nil {
+		return err
+	}
+	return s.filters.Delete(ctx, filterID)
+}
+
+// Issues returns a page of the issues matching one of the user's saved
+// filters, listed like the project's issue list.
+func (s *SavedFilterService) Issues(ctx context.Context, userID, projectID, filterID int64, after domain.ListPosition, limit int) (*IssuePage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	saved, err := s.findOwnFilter(ctx, userID, projectID, filterID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := filterquery.Parse(saved.Query, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter.Cursor = after
+	filter.Limit = limit
+	return s.issues.List(ctx, userID, projectID, filter)
+}
+
+// findOwnFilter loads a saved filter and verifies it is the user's and in
+// the project. Other users' filters are reported as not found.
+func (s *SavedFilterService) findOwnFilter(ctx context.Context, userID, projectID, filterID int64) (*domain.SavedFilter, error) {
+	filter, err := s.filters.FindByID(ctx, filterID)
+	if err != nil {
+		return nil, err
+	}
+	if filter.ProjectID != projectID || filter.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return filter, nil
+}
+
+// validateSavedFilter checks that a saved filter is named and its
+// expression parses.
+func validateSavedFilter(f domain.SavedFilter) error {
+	if f.Name == "" {
+		return &domain.ValidationError{Field: "name", Message: "is required"}
+	}
+	_, err := filterquery.Parse(f.Query, f.UserID)
+	return err
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	searchFeedBatchSize = 500
+	quickSearchLimit    = 5
+)
+
+// Indexer finds issues matching a full-text query. Apply brings the index
+// up to date with changed issues and removes deleted ones.
+type Indexer interface {
+	Search(ctx context.Context, projectID int64, query string, offset, limit int) ([]int64, error)
+	Apply(ctx context.Context, issues []domain.Issue, removed []int64) error
+}
+
+// SearchStore defines the search data access interface consumed by services.
+type SearchStore interface {
+	PendingChanges(ctx context.Context, limit int) ([]domain.SearchChange, error)
+	AckChanges(ctx context.Context, upToID int64) error
+	FindIssues(ctx context.Context, ids []int64) ([]domain.Issue, error)
+	Suggest(ctx context.Context, projectID int64, prefix string, number *int64, limit int) ([]domain.IssueSuggestion, error)
+	SearchAccessible(ctx context.Context, userID int64, query string, offset, limit int) ([]domain.SearchHit, error)
+}
+
+// SearchService searches issues and keeps the search index fed with
+// changes.
+type SearchService struct {
+	projects ProjectStore
+	store    SearchStore
+	indexer  Indexer
+	interval time.Duration
+}
+
+// NewSearchService creates a new SearchService that feeds changes to indexer
+// every interval.
+func NewSearchService(projects ProjectStore, store SearchStore, indexer Indexer, interval time.Duration) *SearchService {
+	return &SearchService{projects: projects, store: store, indexer: indexer, interval: interval}
+}
+
+// SearchPage is a single page of search results. NextCursor is the offset of
+// the next page.
+type SearchPage struct {
+	Issues     []domain.Issue
+	NextCursor int64
+	HasNext    bool
+}
+
+// Search returns a page of issues in a project matching query, best match
+// first, starting at offset.
+func (s *SearchService) Search(ctx context.Context, userID, projectID int64, query string, offset int64, limit int) (*SearchPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, &domain.ValidationError{Field: "q", Message: "is required"}
+	}
+
+	limit = clampPageSize(limit)
+	ids, err := s.indexer.Search(ctx, projectID, query, int(offset), limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("search issues: %w", err)
+	}
+
+	page := &SearchPage{Issues: []domain.Issue{}}
+	if len(ids) > limit {
+		ids = ids[:limit]
+		page.HasNext = true
+		page.NextCursor = offset + int64(limit)
+	}
+	if len(ids) == 0 {
+		return page, nil
+	}
+
+	issues, err := s.store.FindIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	// An external index may briefly lag behind deletes and moves, so results
+	// are checked against the stored issues and kept in ranked order.
+	byID := make(map[int64]domain.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	for _, id := range ids {
+		if issue, ok := byID[id]; ok && issue.ProjectID == projectID {
+			page.Issues = append(page.Issues, issue)
+		}
+	}
+	return page, nil
+}
+
+// SearchHitPage is a single page of search hits across projects.
+// NextCursor is the offset of the next page.
+type SearchHitPage struct {
+	Hits       []domain.SearchHit
+	NextCursor int64
+	HasNext    bool
+}
+
+// SearchAll returns a page of issues matching query in every project the
+// user can access, including issues matched by their comments, best match
+// first, starting at offset. It always searches Postgres, since external
+// indexes hold issues only.
+func (s *SearchService) SearchAll(ctx context.Context, userID int64, query string, offset int64, limit int) (*SearchHitPage, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, &domain.ValidationError{Field: "q", Message: "is required"}
+	}
+
+	limit = clampPageSize(limit)
+	hits, err := s.store.SearchAccessible(ctx, userID, query, int(offset), limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SearchHitPage{Hits: hits}
+	if len(hits) > limit {
+		page.Hits = hits[:limit]
+		page.HasNext = true
+		page.NextCursor = offset + int64(limit)
+	}
+	return page, nil
+}
+
+// QuickSearch returns the few issues in a project that best match what the
+// user has typed so far, for autocomplete. A query like "#12" or "12" also
+// matches the issue with that number.
+func (s *SearchService) QuickSearch(ctx context.Context, userID, projectID int64, query string) ([]domain.IssueSuggestion, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []domain.IssueSuggestion{}, nil
+	}
+
+	var number *int64
+	if n, err := strconv.ParseInt(strings.TrimPrefix(query, "#"), 10, 64); err == nil && n > 0 {
+		number = &n
+	}
+	return s.store.Suggest(ctx, projectID, query, number, quickSearchLimit)
+}
+
+// Run feeds queued issue changes to the indexer until ctx is cancelled. It
+// must run on a single instance at a time. Delivery is at least once: a
+// crash before acknowledging a batch applies it again.
+func (s *SearchService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.drain(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("search indexing failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain applies every queued change in batches.
+func (s *SearchService) drain(ctx context.Context) error {
+	for {
+		changes, err := s.store.PendingChanges(ctx, searchFeedBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		seen := make(map[int64]bool, len(changes))
+		ids := make([]int64, 0, len(changes))
+		for _, c := range changes {
+			if !seen[c.IssueID] {
+				seen[c.IssueID] = true
+				ids = append(ids, c.IssueID)
+			}
+		}
+
+		issues, err := s.store.FindIssues(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			delete(seen, issue.ID)
+		}
+		removed := make([]int64, 0, len(seen))
+		for id := range seen {
+			removed = append(removed, id)
+		}
+
+		if err := s.indexer.Apply(ctx, issues, removed); err != nil {
+			return fmt.Errorf("apply search changes: %w", err)
+		}
+		if err := s.store.AckChanges(ctx, changes[len(changes)-1].ID); err != nil {
+			return err
+		}
+		if len(changes) < searchFeedBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/slack"
+)
+
+const (
+	slackLinkTTL         = 15 * time.Minute
+	slackMaxTitle        = 500
+	slackActionApproveAI = "approve_ai_job"
+	slackUsage           = "Usage:\n" +
+		"• `/issues create KEY Title of the issue` creates an issue in the project with key KEY\n" +
+		"• `/issues ai KEY-123` shows the latest AI job on an issue\n" +
+		"• `/issues link` links your Slack account"
+)
+
+// SlackAccountStore defines the Slack account link data access interface consumed by SlackService.
+type SlackAccountStore interface {
+	FindUser(ctx context.Context, teamID, slackUserID string) (int64, error)
+	Link(ctx context.Context, teamID, slackUserID string, userID int64) error
+	ProjectIDByKey(ctx context.Context, key string) (int64, error)
+	IssueByKey(ctx context.Context, key domain.IssueKey) (projectID, issueID int64, err error)
+}
+
+// SlackService answers the Slack app's slash commands and buttons. Slack
+// users act as the account they have linked, with that account's access.
+type SlackService struct {
+	accounts SlackAccountStore
+	issues   *IssueService
+	aiJobs   *AIJobService
+	secret   []byte
+	linkURL  string
+}
+
+// NewSlackService creates a new SlackService. Link codes are signed with
+// secret and redeemed in the web app at frontendURL.
+func NewSlackService(accounts SlackAccountStore, issues *IssueService, aiJobs *AIJobService, secret []byte, frontendURL string) *SlackService {
+	return &SlackService{
+		accounts: accounts,
+		issues:   issues,
+		aiJobs:   aiJobs,
+		secret:   secret,
+		linkURL:  strings.TrimRight(frontendURL, "/") + "/slack/link",
+	}
+}
+
+// Command runs a slash command and returns the reply. Failures the user can
+// act on, such as an unknown project, are replied to rather than returned.
+func (s *SlackService) Command(ctx context.Context, cmd slack.Command) (*slack.Message, error) {
+	sub, args, _ := strings.Cut(strings.TrimSpace(cmd.Text), " ")
+	args = strings.TrimSpace(args)
+
+	if sub == "link" {
+		return s.linkMessage(cmd.TeamID, cmd.UserID), nil
+	}
+	if sub == "" || sub == "help" {
+		return slack.Ephemeral(slackUsage), nil
+	}
+
+	userID, err := s.accounts.FindUser(ctx, cmd.TeamID, cmd.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.linkMessage(cmd.TeamID, cmd.UserID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var msg *slack.Message
+	switch sub {
+	case "create":
+		msg, err = s.createIssue(ctx, userID, args)
+	case "ai":
+		msg, err = s.showAIJob(ctx, userID, args)
+	default:
+		msg = slack.Ephemeral(fmt.Sprintf("Unknown command %q.\n%s", sub, slackUsage))
+	}
+	return slackReply(msg, err)
+}
+
+// Interact handles a click on one of the app's buttons and returns the
+// message that replaces the one clicked.
+func (s *SlackService) Interact(ctx context.Context, in slack.Interaction) (*slack.Message, error) {
+	if len(in.Actions) == 0 {
+		return slack.Ephemeral("Nothing to do."), nil
+	}
+	userID, err := s.accounts.FindUser(ctx, in.Team.ID, in.User.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.linkMessage(in.Team.ID, in.User.ID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	action := in.Actions[0]
+	switch action.ActionID {
+	case slackActionApproveAI:
+		msg, err := s.approveAIJob(ctx, userID, action.Value)
+		return slackReply(msg, err)
+	}
+	return slack.Ephemeral("This button is no longer supported."), nil
+}
+
+// Link links the Slack user a link code was issued to with the user's
+// account.
+func (s *SlackService) Link(ctx context.Context, userID int64, code string) error {
+	teamID, slackUserID, err := slack.ParseLinkCode(s.secret, code, time.Now())
+	if err != nil {
+		return &domain.ValidationError{Field: "code", Message: "is invalid or has expired"}
+	}
+	return s.accounts.Link(ctx, teamID, slackUserID, userID)
+}
+
+// createIssue handles "create KEY title".
+func (s *SlackService) createIssue(ctx context.Context, userID int64, args string) (*slack.Message, error) {
+	key, title, _ := strings.Cut(args, " ")
+	title = strings.TrimSpace(title)
+	if key == "" || title == "" {
+		return slack.Ephemeral("Usage: `/issues create KEY Title of the issue`"), nil
+	}
+	if utf8.RuneCountInString(title) > slackMaxTitle {
+		return slack.Ephemeral(fmt.Sprintf("Issue titles can be at most %d characters.", slackMaxTitle)), nil
+	}
+	projectID, err := s.accounts.ProjectIDByKey(ctx, strings.ToUpper(key))
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := s.issues.Create(ctx, userID, projectID, domain.Issue{Title: title})
+	if err != nil {
+		return nil, err
+	}
+	return slack.InChannel(fmt.Sprintf("Created %s-%d: %s", strings.ToUpper(key), issue.Number, issue.Title)), nil
+}
+
+// showAIJob handles "ai KEY-123". A completed job gets an approve button.
+func (s *SlackService) showAIJob(ctx context.Context, userID int64, args string) (*slack.Message, error) {
+	refs := domain.ParseReferences(strings.ToUpper(args))
+	if len(refs) == 0 {
+		return slack.Ephemeral("Usage: `/issues ai KEY-123`"), nil
+	}
+	ref := refs[0]
+	projectID, issueID, err := s.accounts.IssueByKey(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.aiJobs.Latest(ctx, userID, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Latest AI job on %s-%d: *%s* (%s)", ref.ProjectKey, ref.Number, job.Status, job.Mode)
+	msg := slack.Ephemeral(text)
+	msg.Blocks = []slack.Block{slack.Section(text)}
+	if job.Status == domain.JobStatusCompleted {
+		msg.Blocks = append(msg.Blocks, slack.Buttons(slack.Button{
+			Text:     "Approve result",
+			ActionID: slackActionApproveAI,
+			Value:    strconv.FormatInt(projectID, 10) + ":" + strconv.FormatInt(job.ID, 10),
+			Style:    "primary",
+		}))
+	}
+	return msg, nil
+}
+
+// approveAIJob handles the approve button, whose value is "projectID:jobID".
+func (s *SlackService) approveAIJob(ctx context.Context, userID int64, value string) (*slack.Message, error) {
+	rawProject, rawJob, _ := strings.Cut(value, ":")
+	projectID, err := strconv.ParseInt(rawProject, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed button value", domain.ErrInvalidInput)
+	}
+	jobID, err := strconv.ParseInt(rawJob, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed button value", domain.ErrInvalidInput)
+	}
+
+	if _, err := s.aiJobs.Approve(ctx, userID, projectID, jobID); err != nil {
+		return nil, err
+	}
+	msg := slack.Ephemeral(fmt.Sprintf("Approved the result of AI job %d.", jobID))
+	msg.ReplaceOriginal = true
+	return msg, nil
+}
+
+// linkMessage asks the Slack user to link their account.
+func (s *SlackService) linkMessage(teamID, slackUserID string) *slack.Message {
+	code := slack.LinkCode(s.secret, teamID, slackUserID, time.Now().Add(slackLinkTTL))
+	link := s.linkURL + "?code=" + url.QueryEscape(code)
+	return slack.Ephemeral(fmt.Sprintf("Link your Slack account to use /issues: <%s|open this link> within %d minutes.",
+		link, int(slackLinkTTL.Minutes())))
+}
+
+// slackReply turns errors the user can act on into replies. Other errors
+// are returned.
+func slackReply(msg *slack.Message, err error) (*slack.Message, error) {
+	if err == nil {
+		return msg, nil
+	}
+	var ve *domain.ValidationError
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return slack.Ephemeral("Not found, or you do not have access to it."), nil
+	case errors.Is(err, domain.ErrForbidden):
+		return slack.Ephemeral("You are not allowed to do that."), nil
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrInvalidInput):
+		return slack.Ephemeral(err.Error()), nil
+	case errors.As(err, &ve):
+		return slack.Ephemeral(ve.Error()), nil
+	}
+	return nil, err
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SnoozeScheduler periodically ends expired notification snoozes so the
+// notifications reappear and are pushed to their users again.
+type SnoozeScheduler struct {
+	notifications *NotificationService
+	interval      time.Duration
+}
+
+// NewSnoozeScheduler creates a SnoozeScheduler that runs every interval.
+func NewSnoozeScheduler(notifications *NotificationService, interval time.Duration) *SnoozeScheduler {
+	return &SnoozeScheduler{notifications: notifications, interval: interval}
+}
+
+// Run resurfaces notifications until ctx is cancelled. It must run on a
+// single instance at a time.
+func (s *SnoozeScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.notifications.Resurface(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.Error("notification resurfacing failed", "error", err)
+		case n > 0:
+			slog.Info("snoozed notifications resurfaced", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	defaultStatsWindow = 30 * 24 * time.Hour
+	maxFlowWindow      = 366 * 24 * time.Hour
+	statsCacheTTL      = 5 * time.Minute
+	statsCacheSize     = 1024
+)
+
+// StatsStore defines the aggregate query interface consumed by StatsService.
+type StatsStore interface {
+	Contributors(ctx context.Context, projectID int64, window domain.TimeWindow) ([]domain.ContributorStats, error)
+	IssueCounts(ctx context.Context, projectID int64, window domain.TimeWindow, groupBy domain.IssueGrouping, interval domain.StatsInterval) ([]domain.IssueCount, error)
+	Flow(ctx context.Context, projectID int64, milestoneID *int64, window domain.TimeWindow) ([]domain.FlowPoint, error)
+	CycleTimes(ctx context.Context, projectID int64, window domain.TimeWindow) (*domain.CycleTimeStats, error)
+}
+
+type windowKey struct {
+	projectID int64
+	since     time.Time
+	until     time.Time
+}
+
+type flowKey struct {
+	windowKey
+	milestoneID int64
+}
+
+type issueCountsKey struct {
+	windowKey
+	groupBy  domain.IssueGrouping
+	interval domain.StatsInterval
+}
+
+// StatsService computes project statistics, caching results briefly since
+// the underlying aggregates are expensive.
+type StatsService struct {
+	projects     ProjectStore
+	milestones   MilestoneStore
+	stats        StatsStore
+	contributors *ttlCache[windowKey, []domain.ContributorStats]
+	issueCounts  *ttlCache[issueCountsKey, []domain.IssueCount]
+	flow         *ttlCache[flowKey, []domain.FlowPoint]
+	cycleTimes   *ttlCache[windowKey, *domain.CycleTimeStats]
+}
+
+// NewStatsService creates a new StatsService.
+func NewStatsService(projects ProjectStore, milestones MilestoneStore, stats StatsStore) *StatsService {
+	return &StatsService{
+		projects:     projects,
+		milestones:   milestones,
+		stats:        stats,
+		contributors: newTTLCache[windowKey, []domain.ContributorStats](statsCacheTTL, statsCacheSize),
+		issueCounts:  newTTLCache[issueCountsKey, []domain.IssueCount](statsCacheTTL, statsCacheSize),
+		flow:         newTTLCache[flowKey, []domain.FlowPoint](statsCacheTTL, statsCacheSize),
+		cycleTimes:   newTTLCache[windowKey, *domain.CycleTimeStats](statsCacheTTL, statsCacheSize),
+	}
+}
+
+// Contributors returns per-member activity stats for a project. A zero Since
+// defaults to 30 days before Until, and a zero Until defaults to now. Bounds
+// are truncated to the minute so repeated requests share cache entries.
+func (s *StatsService) Contributors(ctx context.Context, userID, projectID int64, window domain.TimeWindow) ([]domain.ContributorStats, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	window = normalizeWindow(window)
+	key := windowKey{projectID: projectID, since: window.Since, until: window.Until}
+	if stats, ok := s.contributors.get(key); ok {
+		return stats, nil
+	}
+
+	stats, err := s.stats.Contributors(ctx, projectID, window)
+	if err != nil {
+		return nil, fmt.Errorf("contributor stats: %w", err)
+	}
+	s.contributors.set(key, stats)
+	return stats, nil
+}
+
+// IssueCounts returns counts of issues created in a project per time bucket
+// and group, for dashboards. The window defaults as in Contributors.
+func (s *StatsService) IssueCounts(ctx context.Context, userID, projectID int64, window domain.TimeWindow, groupBy domain.IssueGrouping, interval domain.StatsInterval) ([]domain.IssueCount, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	window = normalizeWindow(window)
+	key := issueCountsKey{
+		windowKey: windowKey{projectID: projectID, since: window.Since, until: window.Until},
+		groupBy:   groupBy,
+		interval:  interval,
+	}
+	if counts, ok := s.issueCounts.get(key); ok {
+		return counts, nil
+	}
+
+	counts, err := s.stats.IssueCounts(ctx, projectID, window, groupBy, interval)
+	if err != nil {
+		return nil, fmt.Errorf("issue counts: %w", err)
+	}
+	s.issueCounts.set(key, counts)
+	return counts, nil
+}
+
+// Flow returns a project's daily status breakdown for burndown, burnup and
+// cumulative flow charts, limited to the issues of a milestone when
+// milestoneID is set. The window defaults as in Contributors and may span
+// at most a year.
+func (s *StatsService) Flow(ctx context.Context, userID, projectID int64, milestoneID *int64, window domain.TimeWindow) ([]domain.FlowPoint, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	window = normalizeWindow(window)
+	if window.Until.Sub(window.Since) > maxFlowWindow {
+		return nil, &domain.ValidationError{Field: "since", Message: "window must not exceed 366 days"}
+	}
+
+	key := flowKey{windowKey: windowKey{projectID: projectID, since: window.Since, until: window.Until}}
+	if milestoneID != nil {
+		milestone, err := s.milestones.FindByID(ctx, *milestoneID)
+		if errors.Is(err, domain.ErrNotFound) || (err == nil && milestone.ProjectID != projectID) {
+			return nil, &domain.ValidationError{Field: "milestone_id", Message: "is not a milestone of this project"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		key.milestoneID = milestone.ID
+	}
+	if points, ok := s.flow.get(key); ok {
+		return points, nil
+	}
+
+	points, err := s.stats.Flow(ctx, projectID, milestoneID, window)
+	if err != nil {
+		return nil, fmt.Errorf("flow: %w", err)
+	}
+	s.flow.set(key, points)
+	return points, nil
+}
+
+// CycleTimes returns lead and cycle time percentiles for issues completed in
+// a project within the window. The window defaults as in Contributors.
+func (s *StatsService) CycleTimes(ctx context.Context, userID, projectID int64, window domain.TimeWindow) (*domain.CycleTimeStats, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	window = normalizeWindow(window)
+	key := windowKey{projectID: projectID, since: window.Since, until: window.Until}
+	if stats, ok := s.cycleTimes.get(key); ok {
+		return stats, nil
+	}
+
+	stats, err := s.stats.CycleTimes(ctx, projectID, window)
+	if err != nil {
+		return nil, fmt.Errorf("cycle times: %w", err)
+	}
+	s.cycleTimes.set(key, stats)
+	return stats, nil
+}
+
+func normalizeWindow(w domain.TimeWindow) domain.TimeWindow {
+	if w.Until.IsZero() {
+		w.Until = time.Now()
+	}
+	if w.Since.IsZero() {
+		w.Since = w.Until.Add(-defaultStatsWindow)
+	}
+	return domain.TimeWindow{
+		Since: w.Since.UTC().Truncate(time.Minute),
+		Until: w.Until.UTC().Truncate(time.Minute),
+	}
+}
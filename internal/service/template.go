@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// TemplateStore defines the project template data access interface consumed by TemplateService.
+type TemplateStore interface {
+	ListForUser(ctx context.Context, userID int64) ([]domain.ProjectTemplate, error)
+	FindByID(ctx context.Context, id int64) (*domain.ProjectTemplate, error)
+	Create(ctx context.Context, template domain.ProjectTemplate) (*domain.ProjectTemplate, error)
+}
+
+// TemplateService handles project templates.
+type TemplateService struct {
+	projects  ProjectStore
+	labels    LabelStore
+	templates TemplateStore
+	orgs      OrganizationStore
+}
+
+// NewTemplateService creates a new TemplateService.
+func NewTemplateService(projects ProjectStore, labels LabelStore, templates TemplateStore, orgs OrganizationStore) *TemplateService {
+	return &TemplateService{projects: projects, labels: labels, templates: templates, orgs: orgs}
+}
+
+// List returns the built-in templates and the user's saved templates.
+func (s *TemplateService) List(ctx context.Context, userID int64) ([]domain.ProjectTemplate, error) {
+	return s.templates.ListForUser(ctx, userID)
+}
+
+// CreateProject creates a project owned by the user from a template, applying
+// its labels, issue templates and automation rules. If orgID is set, the
+// project joins that organization and inherits its defaults wherever the
+// template leaves a setting unset.
+func (s *TemplateService) CreateProject(ctx context.Context, userID, templateID int64, orgID *int64, name string, description *string) (*domain.Project, error) {
+	template, err := s.findVisible(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	project := domain.Project{
+		Name:        name,
+		Description: description,
+		OwnerID:     userID,
+		Settings:    template.Definition.Settings(),
+	}
+	labels := template.Definition.Labels
+	if orgID != nil {
+		if labels, err = inheritDefaults(ctx, s.orgs, userID, *orgID, &project, labels); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.projects.Create(ctx, project, labels)
+	if err != nil {
+		return nil, fmt.Errorf("create project from template %d: %w", templateID, err)
+	}
+	return created, nil
+}
+
+// SaveProject saves an existing project's labels and settings as a new
+// template owned by the user. Only project admins may do so.
+func (s *TemplateService) SaveProject(ctx context.Context, userID, projectID int64, name string, description *string) (*domain.ProjectTemplate, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projects.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := s.labels.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list labels: %w", err)
+	}
+
+	specs := make([]domain.LabelSpec, len(labels))
+	for i, l := range labels {
+		specs[i] = domain.LabelSpec{Name: l.Name, Color: l.Color}
+	}
+
+	template, err := s.templates.Create(ctx, domain.ProjectTemplate{
+		OwnerID:     &userID,
+		Name:        name,
+		Description: description,
+		Definition: domain.TemplateDefinition{
+			Labels:          specs,
+			IssueTemplates:  project.Settings.IssueTemplates,
+			AutomationRules: project.Settings.AutomationRules,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save project %d as template: %w", projectID, err)
+	}
+	return template, nil
+}
+
+// findVisible loads a template that is built in or owned by the user.
+func (s *TemplateService) findVisible(ctx context.Context, userID, templateID int64) (*domain.ProjectTemplate, error) {
+	template, err := s.templates.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !template.BuiltIn() && *template.OwnerID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return template, nil
+}
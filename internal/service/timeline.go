@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// TimelineEventStore defines the issue event data access interface consumed
+// by TimelineService.
+type TimelineEventStore interface {
+	ListForTimeline(ctx context.Context, issueID int64, after domain.TimelinePosition, limit int) ([]domain.IssueEvent, error)
+}
+
+// TimelineCommentStore defines the comment data access interface consumed
+// by TimelineService.
+type TimelineCommentStore interface {
+	ListForTimeline(ctx context.Context, issueID int64, after domain.TimelinePosition, limit int) ([]domain.Comment, error)
+}
+
+// TimelineService serves issue timelines: an issue's events and comments
+// merged in the order they happened.
+type TimelineService struct {
+	projects ProjectStore
+	issues   IssueStore
+	events   TimelineEventStore
+	comments TimelineCommentStore
+}
+
+// NewTimelineService creates a new TimelineService.
+func NewTimelineService(projects ProjectStore, issues IssueStore, events TimelineEventStore, comments TimelineCommentStore) *TimelineService {
+	return &TimelineService{projects: projects, issues: issues, events: events, comments: comments}
+}
+
+// TimelinePage is a single page of an issue's timeline.
+type TimelinePage struct {
+	Items   []domain.TimelineItem
+	Next    domain.TimelinePosition
+	HasNext bool
+}
+
+// List returns the timeline of an issue the user can access after the
+// given position, oldest first. Hidden comments are masked for members who
+// may not see them, and deleted comments are left out.
+func (s *TimelineService) List(ctx context.Context, userID, projectID, issueID int64, after domain.TimelinePosition, limit int) (*TimelinePage, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findIssueInProject(ctx, s.issues, projectID, issueID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	events, err := s.events.ListForTimeline(ctx, issueID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list timeline: %w", err)
+	}
+	comments, err := s.comments.ListForTimeline(ctx, issueID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list timeline: %w", err)
+	}
+
+	// Both sources hold up to limit+1 items in timeline order, so merging
+	// them up to limit+1 items tells whether there is a next page.
+	items := make([]domain.TimelineItem, 0, limit+1)
+	for len(items) <= limit && (len(events) > 0 || len(comments) > 0) {
+		var item domain.TimelineItem
+		if len(comments) == 0 || (len(events) > 0 && eventItem(events[0]).Position().Before(commentItem(comments[0]).Position())) {
+			item, events = eventItem(events[0]), events[1:]
+		} else {
+			c := presentComment(role, comments[0])
+			item, comments = commentItem(c), comments[1:]
+		}
+		items = append(items, item)
+	}
+
+	page := &TimelinePage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasNext = true
+		page.Next = page.Items[len(page.Items)-1].Position()
+	}
+	return page, nil
+}
+
+func eventItem(e domain.IssueEvent) domain.TimelineItem {
+	return domain.TimelineItem{Kind: domain.TimelineEvent, CreatedAt: e.CreatedAt, Event: &e}
+}
+
+func commentItem(c domain.Comment) domain.TimelineItem {
+	return domain.TimelineItem{Kind: domain.TimelineComment, CreatedAt: c.CreatedAt, Comment: &c}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// maxAccessTokens bounds how many tokens one user may hold.
+const maxAccessTokens = 50
+
+// AccessTokenStore defines the personal access token data access interface consumed by AccessTokenService.
+type AccessTokenStore interface {
+	ListByUser(ctx context.Context, userID int64) ([]domain.AccessToken, error)
+	Create(ctx context.Context, token domain.AccessToken, hash []byte) (*domain.AccessToken, error)
+	Delete(ctx context.Context, userID, id int64) error
+	Authenticate(ctx context.Context, hash []byte, now time.Time) (*domain.AccessToken, error)
+	PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// AccessTokenService manages the personal access tokens scripts and the CLI
+// authenticate with. A token acts as its user with that user's access.
+type AccessTokenService struct {
+	tokens AccessTokenStore
+}
+
+// NewAccessTokenService creates a new AccessTokenService.
+func NewAccessTokenService(tokens AccessTokenStore) *AccessTokenService {
+	return &AccessTokenService{tokens: tokens}
+}
+
+// List returns the caller's tokens.
+func (s *AccessTokenService) List(ctx context.Context, userID int64) ([]domain.AccessToken, error) {
+	return s.tokens.ListByUser(ctx, userID)
+}
+
+// Create issues a token for the caller and returns it with its secret, which
+// cannot be retrieved again. An empty scope creates a write token; a nil
+// expiresAt creates a token that does not expire.
+func (s *AccessTokenService) Create(ctx context.Context, userID int64, name string, scope domain.AccessTokenScope, expiresAt *time.Time) (*domain.AccessToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", &domain.ValidationError{Field: "name", Message: "is required"}
+	}
+	if scope == "" {
+		scope = domain.AccessTokenScopeWrite
+	}
+	if !scope.Valid() {
+		return nil, "", &domain.ValidationError{Field: "scope", Message: fmt.Sprintf("unknown scope %q", scope)}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", &domain.ValidationError{Field: "expires_at", Message: "must be in the future"}
+	}
+	existing, err := s.tokens.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxAccessTokens {
+		return nil, "", fmt.Errorf("%w: at most %d access tokens per user", domain.ErrConflict, maxAccessTokens)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("generate access token: %w", err)
+	}
+	secret := domain.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token, err := s.tokens.Create(ctx, domain.AccessToken{UserID: userID, Name: name, Scope: scope, ExpiresAt: expiresAt}, hashAccessToken(secret))
+	if err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// Delete revokes one of the caller's tokens.
+func (s *AccessTokenService) Delete(ctx context.Context, userID, tokenID int64) error {
+	return s.tokens.Delete(ctx, userID, tokenID)
+}
+
+// Authenticate returns the token a secret belongs to. Unknown and expired
+// tokens are domain.ErrUnauthorized.
+func (s *AccessTokenService) Authenticate(ctx context.Context, secret string) (*domain.AccessToken, error) {
+	token, err := s.tokens.Authenticate(ctx, hashAccessToken(secret), time.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrUnauthorized
+	}
+	return token, err
+}
+
+// hashAccessToken returns the form a token is stored and looked up in.
+func hashAccessToken(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// PurgeExpired deletes every token that has expired and returns how many
+// were deleted. Expired tokens already fail to authenticate.
+func (s *AccessTokenService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.tokens.PurgeExpired(ctx, time.Now())
+}
@@ -0,0 +1,22 @@
+package service
+
+import "context"
+
+type traceKey struct{}
+
+// Trace identifies the request and user that initiated an operation.
+type Trace struct {
+	RequestID string
+	UserID    int64
+}
+
+// WithTrace returns a copy of ctx carrying the given trace.
+func WithTrace(ctx context.Context, t Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// TraceFrom returns the trace stored in ctx, if any.
+func TraceFrom(ctx context.Context) (Trace, bool) {
+	t, ok := ctx.Value(traceKey{}).(Trace)
+	return t, ok
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// IssueTrashStore defines the data access interface for trashed issues
+// consumed by TrashService.
+type IssueTrashStore interface {
+	ListDeleted(ctx context.Context, projectID int64, after domain.ListPosition, limit int) ([]domain.Issue, error)
+	FindDeleted(ctx context.Context, projectID, issueID int64) (*domain.Issue, error)
+	Restore(ctx context.Context, projectID, issueID int64) (*domain.Issue, error)
+	PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ProjectTrashStore defines the data access interface for trashed projects
+// consumed by TrashService.
+type ProjectTrashStore interface {
+	ListDeleted(ctx context.Context, ownerID int64) ([]domain.Project, error)
+	FindDeleted(ctx context.Context, id int64) (*domain.Project, error)
+	Restore(ctx context.Context, id int64) (*domain.Project, error)
+	PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// TrashService lists and restores deleted issues and projects, and purges
+// them for good once the retention period has passed.
+type TrashService struct {
+	projects      ProjectStore
+	trashProjects ProjectTrashStore
+	trashIssues   IssueTrashStore
+	audit         AuditStore
+	retention     time.Duration
+}
+
+// NewTrashService creates a new TrashService keeping deleted items for
+// retention, or 30 days if it is not positive.
+func NewTrashService(projects ProjectStore, trashProjects ProjectTrashStore, trashIssues IssueTrashStore, audit AuditStore,
+	retention time.Duration) *TrashService {
+	if retention <= 0 {
+		retention = defaultTrashRetention
+	}
+	return &TrashService{
+		projects:      projects,
+		trashProjects: trashProjects,
+		trashIssues:   trashIssues,
+		audit:         audit,
+		retention:     retention,
+	}
+}
+
+// ListIssues returns a page of the issues in the trash of a project the user
+// can access, most recently deleted first.
+func (s *TrashService) ListIssues(ctx context.Context, userID, projectID int64, after domain.ListPosition, limit int) (*IssuePage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	issues, err := s.trashIssues.ListDeleted(ctx, projectID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &IssuePage{Issues: issues}
+	if len(issues) > limit {
+		page.Issues = issues[:limit]
+		page.HasNext = true
+		last := page.Issues[len(page.Issues)-1]
+		page.NextCursor = domain.ListPosition{At: *last.DeletedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// RestoreIssue takes an issue out of a project's trash. Those who could
+// delete it, its creator and project admins, may restore it.
+func (s *TrashService) RestoreIssue(ctx context.Context, userID, projectID, issueID int64) (*domain.Issue, error) {
+	role, err := authorizeProject(ctx, s.projects, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := s.trashIssues.FindDeleted(ctx, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanAdmin() && (issue.CreatedBy == nil || *issue.CreatedBy != userID) {
+		return nil, domain.ErrForbidden
+	}
+
+	restored, err := s.trashIssues.Restore(ctx, projectID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditIssueRestored, domain.AuditTargetIssue, issueID); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// ListProjects returns the projects in the user's trash, most recently
+// deleted first.
+func (s *TrashService) ListProjects(ctx context.Context, userID int64) ([]domain.Project, error) {
+	return s.trashProjects.ListDeleted(ctx, userID)
+}
+
+// RestoreProject takes a project out of its owner's trash. Only the owner
+// may restore it; to everyone else it does not exist.
+func (s *TrashService) RestoreProject(ctx context.Context, userID, projectID int64) (*domain.Project, error) {
+	project, err := s.trashProjects.FindDeleted(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.OwnerID != userID {
+		return nil, domain.ErrNotFound
+	}
+
+	restored, err := s.trashProjects.Restore(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditProjectRestored, domain.AuditTargetProject, projectID); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// Purge permanently removes issues and projects that have been in the trash
+// for longer than the retention period. It returns how many of each were
+// removed.
+func (s *TrashService) Purge(ctx context.Context) (issues, projects int64, err error) {
+	cutoff := time.Now().Add(-s.retention)
+	if issues, err = s.trashIssues.PurgeDeleted(ctx, cutoff); err != nil {
+		return 0, 0, fmt.Errorf("purge trash: %w", err)
+	}
+	if projects, err = s.trashProjects.PurgeDeleted(ctx, cutoff); err != nil {
+		return issues, 0, fmt.Errorf("purge trash: %w", err)
+	}
+	return issues, projects, nil
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/sumire/issues/internal/domain"
+	"github.com/sumire/issues/internal/webhook"
+)
+
+// WebhookStore defines the project webhook data access interface consumed by WebhookService.
+type WebhookStore interface {
+	ListByProject(ctx context.Context, projectID int64) ([]domain.Webhook, error)
+	FindByID(ctx context.Context, id int64) (*domain.Webhook, error)
+	Create(ctx context.Context, webhook domain.Webhook) (*domain.Webhook, error)
+	Update(ctx context.Context, webhook domain.Webhook) (*domain.Webhook, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// WebhookDeliveryStore defines the webhook delivery log interface consumed by WebhookService.
+type WebhookDeliveryStore interface {
+	List(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+}
+
+// WebhookTester sends sample payloads to webhooks.
+type WebhookTester interface {
+	Test(ctx context.Context, webhook domain.Webhook, typ domain.EventType) (*domain.WebhookTestResult, error)
+}
+
+// WebhookService manages project webhooks and lets administrators inspect
+// their deliveries.
+type WebhookService struct {
+	users      UserStore
+	projects   ProjectStore
+	webhooks   WebhookStore
+	deliveries WebhookDeliveryStore
+	audit      AuditStore
+	tester     WebhookTester
+}
+
+// NewWebhookService creates a new WebhookService.
+func NewWebhookService(users UserStore, projects ProjectStore, webhooks WebhookStore, deliveries WebhookDeliveryStore, audit AuditStore, tester WebhookTester) *WebhookService {
+	return &WebhookService{users: users, projects: projects, webhooks: webhooks, deliveries: deliveries, audit: audit, tester: tester}
+}
+
+// EventTypes returns the catalog of events webhooks receive.
+func (s *WebhookService) EventTypes() []webhook.EventType {
+	return webhook.Catalog()
+}
+
+// List returns a project's webhooks. Only project admins may manage
+// webhooks.
+func (s *WebhookService) List(ctx context.Context, userID, projectID int64) ([]domain.Webhook, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.webhooks.ListByProject(ctx, projectID)
+}
+
+// Create adds a webhook to a project with a newly generated signing secret,
+// which is only ever returned here. Only project admins may manage webhooks.
+func (s *WebhookService) Create(ctx context.Context, userID, projectID int64, w domain.Webhook) (*domain.Webhook, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if w.Format == "" {
+		w.Format = domain.WebhookFormatJSON
+	}
+	if w.ContentType == "" {
+		w.ContentType = webhook.ContentTypeJSON
+	}
+	if err := validateWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate webhook secret: %w", err)
+	}
+	w.ProjectID = projectID
+	w.Secret = hex.EncodeToString(secret)
+	w.CreatedBy = &userID
+
+	created, err := s.webhooks.Create(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditWebhookCreated, domain.AuditTargetWebhook, created.ID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Update changes a webhook's endpoint, template or state. Only project
+// admins may manage webhooks.
+func (s *WebhookService) Update(ctx context.Context, userID, projectID, webhookID int64, patch domain.WebhookPatch) (*domain.Webhook, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	w, err := s.findWebhookInProject(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.URL != nil {
+		w.URL = *patch.URL
+	}
+	if patch.Format != nil {
+		w.Format = *patch.Format
+	}
+	if patch.Template != nil {
+		w.Template = patch.Template
+		if *patch.Template == "" {
+			w.Template = nil
+		}
+	}
+	if patch.ContentType != nil {
+		w.ContentType = *patch.ContentType
+	}
+	if patch.Active != nil {
+		w.Active = *patch.Active
+	}
+	if patch.Retry != nil {
+		w.Retry = *patch.Retry
+	}
+	if err := validateWebhook(ctx, *w); err != nil {
+		return nil, err
+	}
+
+	if w, err = s.webhooks.Update(ctx, *w); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, projectID, userID, domain.AuditWebhookUpdated, domain.AuditTargetWebhook, webhookID); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Delete removes a webhook and its delivery log. Only project admins may
+// manage webhooks.
+func (s *WebhookService) Delete(ctx context.Context, userID, projectID, webhookID int64) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	if _, err := s.findWebhookInProject(ctx, projectID, webhookID); err != nil {
+		return err
+	}
+	if err := s.webhooks.Delete(ctx, webhookID); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, projectID, userID, domain.AuditWebhookDeleted, domain.AuditTargetWebhook, webhookID)
+}
+
+// Test sends a sample payload of an event type to a webhook, issue.created
+// if typ is empty, so integrators can check their receiver. Inactive
+// webhooks can be tested too. Only project admins may manage webhooks.
+func (s *WebhookService) Test(ctx context.Context, userID, projectID, webhookID int64, typ domain.EventType) (*domain.WebhookTestResult, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	w, err := s.findWebhookInProject(ctx, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if typ == "" {
+		typ = domain.EventIssueCreated
+	}
+	if !webhook.Known(typ) {
+		return nil, &domain.ValidationError{Field: "event_type", Message: fmt.Sprintf("unknown event type %q", typ)}
+	}
+	return s.tester.Test(ctx, *w, typ)
+}
+
+// ListWebhookDeliveries returns a project webhook's deliveries, newest
+// first. Only project admins may manage webhooks.
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, userID, projectID, webhookID int64, filter domain.WebhookDeliveryFilter) (*WebhookDeliveryPage, error) {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findWebhookInProject(ctx, projectID, webhookID); err != nil {
+		return nil, err
+	}
+	filter.ProjectID = &projectID
+	filter.WebhookID = &webhookID
+	return s.listDeliveries(ctx, filter)
+}
+
+// WebhookDeliveryPage is a single page of webhook deliveries.
+type WebhookDeliveryPage struct {
+	Deliveries []domain.WebhookDelivery
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// ListDeliveries returns webhook deliveries matching the filter, newest
+// first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID int64, filter domain.WebhookDeliveryFilter) (*WebhookDeliveryPage, error) {
+	if err := authorizeSystemAdmin(ctx, s.users, userID); err != nil {
+		return nil, err
+	}
+	return s.listDeliveries(ctx, filter)
+}
+
+func (s *WebhookService) listDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) (*WebhookDeliveryPage, error) {
+	filter.Limit = clampPageSize(filter.Limit)
+	deliveries, err := s.deliveries.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+
+	page := &WebhookDeliveryPage{Deliveries: deliveries}
+	if len(deliveries) > filter.Limit {
+		page.Deliveries = deliveries[:filter.Limit]
+		page.HasNext = true
+		last := page.Deliveries[len(page.Deliveries)-1]
+		page.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// findWebhookInProject loads a webhook and verifies it belongs to the
+// project.
+func (s *WebhookService) findWebhookInProject(ctx context.Context, projectID, webhookID int64) (*domain.Webhook, error) {
+	w, err := s.webhooks.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if w.ProjectID != projectID {
+		return nil, domain.ErrNotFound
+	}
+	return w, nil
+}
+
+// Bounds of a webhook's retry policy.
+const (
+	maxWebhookAttempts       = 20
+	maxWebhookBackoffSeconds = 24 * 60 * 60
+)
+
+// validateWebhook checks that a webhook's endpoint is publicly reachable,
+// its retry policy and that its template renders a body of its content
+// type. Webhooks without a template send JSON payloads or Teams messages, so
+// they must use a JSON content type.
+func validateWebhook(ctx context.Context, w domain.Webhook) error {
+	if err := webhook.CheckURL(ctx, w.URL); err != nil {
+		return &domain.ValidationError{Field: "url", Message: err.Error()}
+	}
+	if err := validateRetryPolicy(w.Retry); err != nil {
+		return err
+	}
+	if !w.Format.Valid() {
+		return &domain.ValidationError{Field: "format", Message: fmt.Sprintf("unknown format %q", w.Format)}
+	}
+	if w.Format == domain.WebhookFormatTeams && w.Template != nil {
+		return &domain.ValidationError{Field: "template", Message: "cannot be used with the teams format"}
+	}
+	if w.Template == nil {
+		if w.ContentType != webhook.ContentTypeJSON {
+			return &domain.ValidationError{Field: "content_type", Message: "requires a template unless it is " + webhook.ContentTypeJSON}
+		}
+		return nil
+	}
+	return webhook.ValidateTemplate(*w.Template, w.ContentType)
+}
+
+// validateRetryPolicy checks that the fields a retry policy sets are in
+// range.
+func validateRetryPolicy(p domain.WebhookRetryPolicy) error {
+	var errs domain.ValidationErrors
+	if p.MaxAttempts != nil && (*p.MaxAttempts < 1 || *p.MaxAttempts > maxWebhookAttempts) {
+		errs = append(errs, &domain.ValidationError{
+			Field:   "retry.max_attempts",
+			Message: fmt.Sprintf("must be between 1 and %d", maxWebhookAttempts),
+		})
+	}
+	if p.BackoffSeconds != nil && (*p.BackoffSeconds < 1 || *p.BackoffSeconds > maxWebhookBackoffSeconds) {
+		errs = append(errs, &domain.ValidationError{
+			Field:   "retry.backoff_seconds",
+			Message: fmt.Sprintf("must be between 1 and %d", maxWebhookBackoffSeconds),
+		})
+	}
+	if p.Jitter != nil && (*p.Jitter < 0 || *p.Jitter > 1) {
+		errs = append(errs, &domain.ValidationError{Field: "retry.jitter", Message: "must be between 0 and 1"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// WebhookAlerter notifies a project's admins when one of its webhooks is
+// deactivated because its endpoint kept failing.
+type WebhookAlerter struct {
+	members       ProjectMemberLister
+	notifications NotificationStore
+}
+
+// NewWebhookAlerter creates a new WebhookAlerter.
+func NewWebhookAlerter(members ProjectMemberLister, notifications NotificationStore) *WebhookAlerter {
+	return &WebhookAlerter{members: members, notifications: notifications}
+}
+
+// WebhookDisabled notifies the admins of the webhook's project. Failures
+// are logged.
+func (a *WebhookAlerter) WebhookDisabled(ctx context.Context, w domain.Webhook) {
+	members, err := a.members.Members(ctx, w.ProjectID)
+	if err != nil {
+		slog.Error("failed to list webhook project admins", "project_id", w.ProjectID, "error", err)
+		return
+	}
+	admins := make([]int64, 0, len(members))
+	for _, m := range members {
+		if m.Role.CanAdmin() {
+			admins = append(admins, m.UserID)
+		}
+	}
+	if len(admins) == 0 {
+		return
+	}
+
+	_, err = a.notifications.FanOut(ctx, admins, domain.Notification{
+		Type:    domain.NotificationWebhookDisabled,
+		Title:   "Webhook disabled",
+		Message: fmt.Sprintf("%s kept failing and was deactivated", w.URL),
+	})
+	if err != nil {
+		slog.Error("failed to notify webhook project admins", "webhook_id", w.ID, "error", err)
+	}
+}
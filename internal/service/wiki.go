@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// WikiStore defines the wiki page data access interface consumed by
+// WikiService.
+type WikiStore interface {
+	ListByProject(ctx context.Context, projectID int64) ([]domain.WikiPageSummary, error)
+	FindBySlug(ctx context.Context, projectID int64, slug string) (*domain.WikiPage, error)
+	Create(ctx context.Context, page domain.WikiPage, message string) (*domain.WikiPage, error)
+	Update(ctx context.Context, page domain.WikiPage, message string, pre domain.Precondition) (*domain.WikiPage, error)
+	Delete(ctx context.Context, id int64) error
+	Revisions(ctx context.Context, pageID int64, after domain.ListPosition, limit int) ([]domain.WikiRevisionSummary, error)
+	Revision(ctx context.Context, pageID int64, revision int) (*domain.WikiRevision, error)
+}
+
+// WikiService handles project wikis. Any project member may read and edit
+// pages; only project admins may delete them.
+type WikiService struct {
+	projects ProjectStore
+	wiki     WikiStore
+	refs     ReferenceStore
+}
+
+// NewWikiService creates a new WikiService.
+func NewWikiService(projects ProjectStore, wiki WikiStore, refs ReferenceStore) *WikiService {
+	return &WikiService{projects: projects, wiki: wiki, refs: refs}
+}
+
+// WikiRevisionPage is a single page of a wiki page's revisions.
+type WikiRevisionPage struct {
+	Revisions  []domain.WikiRevisionSummary
+	NextCursor domain.ListPosition
+	HasNext    bool
+}
+
+// List returns the pages of the wiki of a project the user can access.
+func (s *WikiService) List(ctx context.Context, userID, projectID int64) ([]domain.WikiPageSummary, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	return s.wiki.ListByProject(ctx, projectID)
+}
+
+// Get returns a wiki page of a project the user can access, with the
+// project's issues that link to it.
+func (s *WikiService) Get(ctx context.Context, userID, projectID int64, slug string) (*domain.WikiPageDetail, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	page, err := s.wiki.FindBySlug(ctx, projectID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &domain.WikiPageDetail{WikiPage: *page}
+	if detail.LinkedFrom, err = s.refs.WikiLinkedFrom(ctx, projectID, slug); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// Create adds a page to a project's wiki. Without a slug, one is derived
+// from the title.
+func (s *WikiService) Create(ctx context.Context, userID, projectID int64, page domain.WikiPage, message string) (*domain.WikiPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	page.ProjectID = projectID
+	page.Title = strings.TrimSpace(page.Title)
+	if page.Title == "" {
+		return nil, &domain.ValidationError{Field: "title", Message: "is required"}
+	}
+	if page.Slug == "" {
+		if page.Slug = domain.WikiSlug(page.Title); page.Slug == "" {
+			return nil, &domain.ValidationError{Field: "slug", Message: "is required when the title has no letters or digits"}
+		}
+	} else if !domain.ValidWikiSlug(page.Slug) {
+		return nil, invalidWikiSlug()
+	}
+	page.CreatedBy = &userID
+	return s.wiki.Create(ctx, page, message)
+}
+
+// Update edits a wiki page of a project the user can access, recording the
+// edit as a new revision. An edit that changes nothing records none.
+func (s *WikiService) Update(ctx context.Context, userID, projectID int64, slug string, patch domain.WikiPagePatch, pre domain.Precondition) (*domain.WikiPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	page, err := s.wiki.FindBySlug(ctx, projectID, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(ctx, userID, page, patch, pre)
+}
+
+// Restore makes an earlier revision of a wiki page its content again, as a
+// new revision.
+func (s *WikiService) Restore(ctx context.Context, userID, projectID int64, slug string, revision int, pre domain.Precondition) (*domain.WikiPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	page, err := s.wiki.FindBySlug(ctx, projectID, slug)
+	if err != nil {
+		return nil, err
+	}
+	old, err := s.wiki.Revision(ctx, page.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(ctx, userID, page, domain.WikiPagePatch{
+		Title:   &old.Title,
+		Body:    &old.Body,
+		Message: fmt.Sprintf("Restore revision %d", revision),
+	}, pre)
+}
+
+// update applies patch to page as an edit by the user.
+func (s *WikiService) update(ctx context.Context, userID int64, page *domain.WikiPage, patch domain.WikiPagePatch, pre domain.Precondition) (*domain.WikiPage, error) {
+	edited := *page
+	if patch.Title != nil {
+		edited.Title = strings.TrimSpace(*patch.Title)
+		if edited.Title == "" {
+			return nil, &domain.ValidationError{Field: "title", Message: "is required"}
+		}
+	}
+	if patch.Body != nil {
+		edited.Body = *patch.Body
+	}
+	if edited.Title == page.Title && edited.Body == page.Body {
+		return page, nil
+	}
+	edited.UpdatedBy = &userID
+	return s.wiki.Update(ctx, edited, patch.Message, pre)
+}
+
+// Delete removes a wiki page and its history. Links to it are kept, so a
+// page written again under the slug picks them up. Only project admins may
+// delete pages.
+func (s *WikiService) Delete(ctx context.Context, userID, projectID int64, slug string) error {
+	if err := authorizeAdmin(ctx, s.projects, userID, projectID); err != nil {
+		return err
+	}
+	page, err := s.wiki.FindBySlug(ctx, projectID, slug)
+	if err != nil {
+		return err
+	}
+	return s.wiki.Delete(ctx, page.ID)
+}
+
+// Revisions returns a page of a wiki page's revisions, newest first.
+func (s *WikiService) Revisions(ctx context.Context, userID, projectID int64, slug string, after domain.ListPosition, limit int) (*WikiRevisionPage, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	page, err := s.wiki.FindBySlug(ctx, projectID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = clampPageSize(limit)
+	revisions, err := s.wiki.Revisions(ctx, page.ID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &WikiRevisionPage{Revisions: revisions}
+	if len(revisions) > limit {
+		result.Revisions = revisions[:limit]
+		result.HasNext = true
+		last := result.Revisions[len(result.Revisions)-1]
+		result.NextCursor = domain.ListPosition{At: last.CreatedAt, ID: int64(last.Revision)}
+	}
+	return result, nil
+}
+
+// Revision returns one revision of a wiki page.
+func (s *WikiService) Revision(ctx context.Context, userID, projectID int64, slug string, revision int) (*domain.WikiRevision, error) {
+	if _, err := authorizeProject(ctx, s.projects, userID, projectID); err != nil {
+		return nil, err
+	}
+	page, err := s.wiki.FindBySlug(ctx, projectID, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.wiki.Revision(ctx, page.ID, revision)
+}
+
+func invalidWikiSlug() error {
+	return &domain.ValidationError{
+		Field:   "slug",
+		Message: "must be lowercase letters and digits in words joined by dashes, at most 100 characters",
+	}
+}
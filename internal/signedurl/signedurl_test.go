@@ -0,0 +1,61 @@
+package signedurl
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDeriveKey(t *testing.T) {
+	a, err := DeriveKey([]byte("jwt secret"))
+	if err != nil {
+		t.Fatalf("DeriveKey() error = %v", err)
+	}
+	again, _ := DeriveKey([]byte("jwt secret"))
+	other, _ := DeriveKey([]byte("other secret"))
+
+	if len(a) != 32 {
+		t.Errorf("len(DeriveKey()) = %d, want 32", len(a))
+	}
+	if !bytes.Equal(a, again) {
+		t.Error("DeriveKey() is not deterministic")
+	}
+	if bytes.Equal(a, other) {
+		t.Error("DeriveKey() gives the same key for different secrets")
+	}
+	if bytes.Contains(a, []byte("jwt secret")) {
+		t.Error("DeriveKey() key contains the secret")
+	}
+}
+
+func TestSignerRejectsOtherKey(t *testing.T) {
+	derived, _ := DeriveKey([]byte("jwt secret"))
+	signer := New(derived, "/api/v1", time.Minute)
+	jwtSigner := New([]byte("jwt secret"), "/api/v1", time.Minute)
+
+	link, _ := jwtSigner.URL(KindAttachment, 42, "")
+	if err := verifyLink(t, signer, link); !errors.Is(err, ErrInvalid) {
+		t.Errorf("verify link signed with the JWT key: error = %v, want %v", err, ErrInvalid)
+	}
+	link, _ = signer.URL(KindAttachment, 42, "")
+	if err := verifyLink(t, signer, link); err != nil {
+		t.Errorf("verify link signed with the derived key: error = %v", err)
+	}
+}
+
+// verifyLink checks a link to attachment 42 made by URL with signer.
+func verifyLink(t *testing.T, signer *Signer, link string) error {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parse link %q: %v", link, err)
+	}
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if err != nil {
+		t.Fatalf("link %q expires: %v", link, err)
+	}
+	return signer.Verify(KindAttachment, 42, "", expires, u.Query().Get("signature"))
+}
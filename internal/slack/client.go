@@ -0,0 +1,66 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// postMessageURL is the Web API method that posts a message to a channel.
+const postMessageURL = "https://slack.com/api/chat.postMessage"
+
+// Client calls the Slack Web API with a bot token.
+type Client struct {
+	token  string
+	client *http.Client
+}
+
+// NewClient creates a client that acts as the bot token belongs to.
+func NewClient(token string) *Client {
+	return &Client{token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// postMessage is the body of a chat.postMessage call.
+type postMessage struct {
+	Channel string  `json:"channel"`
+	Text    string  `json:"text"`
+	Blocks  []Block `json:"blocks,omitempty"`
+}
+
+// Post posts msg to a channel, given by ID or as "#name". The bot must be a
+// member of the channel.
+func (c *Client) Post(ctx context.Context, channel string, msg *Message) error {
+	body, err := json.Marshal(postMessage{Channel: channel, Text: msg.Text, Blocks: msg.Blocks})
+	if err != nil {
+		return fmt.Errorf("encode slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postMessageURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build slack request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The Web API answers 200 with ok false for most failures.
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err != nil {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack rejected message to %s: %s", channel, result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLinkCode is returned for link codes that were tampered with or
+// have expired.
+var ErrInvalidLinkCode = errors.New("invalid or expired slack link code")
+
+// LinkCode returns a code that links a Slack user to whichever account
+// redeems it before expires. The code is signed with secret, so it needs no
+// storage.
+func LinkCode(secret []byte, teamID, slackUserID string, expires time.Time) string {
+	claims := teamID + ":" + slackUserID + ":" + strconv.FormatInt(expires.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(claims))
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(claims)) + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+// ParseLinkCode verifies a link code and returns the Slack user it links.
+func ParseLinkCode(secret []byte, code string, now time.Time) (teamID, slackUserID string, err error) {
+	enc := base64.RawURLEncoding
+	encodedClaims, encodedSig, ok := strings.Cut(code, ".")
+	if !ok {
+		return "", "", ErrInvalidLinkCode
+	}
+	claims, err := enc.DecodeString(encodedClaims)
+	if err != nil {
+		return "", "", ErrInvalidLinkCode
+	}
+	sig, err := enc.DecodeString(encodedSig)
+	if err != nil {
+		return "", "", ErrInvalidLinkCode
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(claims)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return "", "", ErrInvalidLinkCode
+	}
+
+	parts := strings.Split(string(claims), ":")
+	if len(parts) != 3 {
+		return "", "", ErrInvalidLinkCode
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || now.After(time.Unix(expires, 0)) {
+		return "", "", ErrInvalidLinkCode
+	}
+	return parts[0], parts[1], nil
+}
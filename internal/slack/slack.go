@@ -0,0 +1,152 @@
+// Package slack verifies and parses requests Slack sends to the app's slash
+// command and interactivity endpoints, builds the messages sent back, and
+// posts messages to channels as the app's bot.
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Headers Slack signs requests with.
+const (
+	HeaderTimestamp = "X-Slack-Request-Timestamp"
+	HeaderSignature = "X-Slack-Signature"
+)
+
+// maxSkew is how old a request may be before it is rejected as a replay.
+const maxSkew = 5 * time.Minute
+
+// ErrInvalidSignature is returned for requests that Slack did not sign, or
+// that are too old.
+var ErrInvalidSignature = errors.New("invalid slack signature")
+
+// Verify checks a request's signature: the HMAC-SHA256, with the app's
+// signing secret, of "v0:", the timestamp header, ":" and the raw body.
+func Verify(secret []byte, timestamp, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > maxSkew || skew < -maxSkew {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Command is a slash command invocation.
+type Command struct {
+	TeamID      string
+	UserID      string
+	Command     string
+	Text        string
+	ResponseURL string
+}
+
+// ParseCommand reads a slash command from its form-encoded request body.
+func ParseCommand(form url.Values) Command {
+	return Command{
+		TeamID:      form.Get("team_id"),
+		UserID:      form.Get("user_id"),
+		Command:     form.Get("command"),
+		Text:        form.Get("text"),
+		ResponseURL: form.Get("response_url"),
+	}
+}
+
+// Interaction is a click on a button in a message the app sent.
+type Interaction struct {
+	Type string `json:"type"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Actions     []Action `json:"actions"`
+	ResponseURL string   `json:"response_url"`
+}
+
+// Action is a single interactive element that was used.
+type Action struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
+
+// ParseInteraction reads an interaction from its form-encoded request body,
+// which carries it as JSON in the payload field.
+func ParseInteraction(form url.Values) (*Interaction, error) {
+	var in Interaction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &in); err != nil {
+		return nil, fmt.Errorf("decode slack interaction: %w", err)
+	}
+	return &in, nil
+}
+
+// Message is a reply to a command or interaction. Ephemeral messages are
+// only shown to the user who sent the command.
+type Message struct {
+	ResponseType    string  `json:"response_type,omitempty"`
+	Text            string  `json:"text"`
+	Blocks          []Block `json:"blocks,omitempty"`
+	ReplaceOriginal bool    `json:"replace_original,omitempty"`
+}
+
+// Block is a Block Kit layout block.
+type Block map[string]any
+
+// Ephemeral returns a plain text reply only the sender sees.
+func Ephemeral(text string) *Message {
+	return &Message{ResponseType: "ephemeral", Text: text}
+}
+
+// InChannel returns a plain text reply everyone in the channel sees.
+func InChannel(text string) *Message {
+	return &Message{ResponseType: "in_channel", Text: text}
+}
+
+// Section returns a block of mrkdwn text.
+func Section(text string) Block {
+	return Block{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": text}}
+}
+
+// Buttons returns a block of buttons.
+func Buttons(buttons ...Button) Block {
+	elements := make([]map[string]any, len(buttons))
+	for i, b := range buttons {
+		elements[i] = map[string]any{
+			"type":      "button",
+			"text":      map[string]any{"type": "plain_text", "text": b.Text},
+			"action_id": b.ActionID,
+			"value":     b.Value,
+		}
+		if b.Style != "" {
+			elements[i]["style"] = b.Style
+		}
+	}
+	return Block{"type": "actions", "elements": elements}
+}
+
+// Button is a message button. Clicking it sends an interaction with its
+// action ID and value. Style is empty, "primary" or "danger".
+type Button struct {
+	Text     string
+	ActionID string
+	Value    string
+	Style    string
+}
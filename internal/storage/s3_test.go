@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 serves path-style object requests for a single bucket from memory.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		f.t.Errorf("%s %s: Authorization = %q, want a SigV4 signature", r.Method, r.URL.Path, r.Header.Get("Authorization"))
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/bucket/")
+	if !ok {
+		f.t.Errorf("%s %s: path outside the bucket", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3(t *testing.T) *S3 {
+	t.Helper()
+	srv := httptest.NewServer(&fakeS3{t: t, objects: map[string][]byte{}})
+	t.Cleanup(srv.Close)
+
+	s, err := NewS3(S3Config{
+		Bucket:          "bucket",
+		Region:          "us-east-1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		Endpoint:        srv.URL,
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	return s
+}
+
+func TestS3Objects(t *testing.T) {
+	ctx := context.Background()
+	s := newTestS3(t)
+
+	if err := s.Put(ctx, "a/b.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	rc, err := s.Open(ctx, "a/b.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != "hello" {
+		t.Errorf("Open() = %q, want hello", body)
+	}
+
+	if err := s.Delete(ctx, "a/b.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Open(ctx, "a/b.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3SignedURL(t *testing.T) {
+	s := newTestS3(t)
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "valid", ttl: time.Hour},
+		{name: "maximum", ttl: maxPresignTTL},
+		{name: "zero", ttl: 0, wantErr: true},
+		{name: "too long", ttl: maxPresignTTL + time.Second, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := s.SignedURL(context.Background(), "a/b.txt", "report.pdf", tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignedURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			u, err := url.Parse(raw)
+			if err != nil {
+				t.Fatalf("parse %q: %v", raw, err)
+			}
+			q := u.Query()
+			if u.Path != "/bucket/a/b.txt" {
+				t.Errorf("path = %q, want /bucket/a/b.txt", u.Path)
+			}
+			if got, want := q.Get("X-Amz-Expires"), strconv.Itoa(int(tt.ttl.Seconds())); got != want {
+				t.Errorf("X-Amz-Expires = %q, want %q", got, want)
+			}
+			if got := q.Get("response-content-disposition"); got != `attachment; filename="report.pdf"` {
+				t.Errorf("response-content-disposition = %q", got)
+			}
+			if q.Get("X-Amz-Signature") == "" {
+				t.Error("X-Amz-Signature is missing")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package storage keeps binary objects such as AI job artifacts and issue
+// attachments outside the database, on local disk or in S3.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores objects under slash-separated keys.
+type Storage interface {
+	// Put stores the contents of r under key, replacing any existing object.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns the object stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing object
+	// is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Signer is implemented by storages that can hand out URLs downloading an
+// object directly, without going through the server.
+type Signer interface {
+	// SignedURL returns a URL downloading the object stored under key as an
+	// attachment named filename, valid for ttl.
+	SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+}
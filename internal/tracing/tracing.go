@@ -0,0 +1,64 @@
+// Package tracing sets up OpenTelemetry tracing. Requests, queries and AI
+// jobs record spans through the global tracer provider, which exports them
+// to a collector over OTLP/HTTP. Spans are linked with W3C trace context,
+// so traces continue across services that propagate the traceparent header.
+//
+// Tracing is off until Setup is called; spans started before then record
+// nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup turns tracing on, exporting spans of the named service to the
+// collector at endpoint, a base URL such as http://localhost:4318 as in
+// OTEL_EXPORTER_OTLP_ENDPOINT. Spans are posted to its /v1/traces path.
+// The returned function turns tracing off and sends the spans still
+// queued, waiting until they are sent or its context is done.
+func Setup(ctx context.Context, endpoint, service string) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimRight(endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, fmt.Errorf("create span exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// SetError records err on span and marks the span as failed. A nil err
+// does nothing.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Traceparent returns the W3C traceparent header value of the span in ctx,
+// or an empty string if no span is recording.
+func Traceparent(ctx context.Context) string {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned when a project webhook's endpoint is, or
+// resolves to, an address on the server's own networks.
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// forbiddenPrefixes are the ranges project webhooks may not reach beyond
+// those netip.Addr classifies as loopback, private, link-local, multicast or
+// unspecified: "this network", carrier-grade NAT (where some clouds serve
+// instance metadata), benchmarking and reserved addresses.
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// PublicAddr reports whether a project webhook may send to addr. Loopback,
+// private, link-local (including the 169.254.169.254 metadata service),
+// multicast and reserved addresses are refused.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, p := range forbiddenPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL reports whether rawURL is an HTTP(S) URL whose host resolves to
+// public addresses only. It catches mistakes early; the dialer of
+// NewGuardedClient enforces the same rule on every connection, so a host
+// that later resolves elsewhere is refused too.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("must be an http or https URL")
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil {
+		if !PublicAddr(addr) {
+			return ErrForbiddenAddress
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !PublicAddr(addr) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// NewGuardedClient returns an HTTP client that refuses to connect to
+// addresses PublicAddr rejects. The check runs on the resolved address of
+// each connection, so it also covers redirects and hosts whose DNS records
+// change after the webhook was saved. Proxies from the environment are not
+// used, since they would connect on the client's behalf.
+func NewGuardedClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   guardConn,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   timeout,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// guardConn is a net.Dialer Control function that refuses connections to
+// addresses PublicAddr rejects.
+func guardConn(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("parse dial address %q: %w", address, err)
+	}
+	if !PublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("dial %s: %w", addrPort.Addr(), ErrForbiddenAddress)
+	}
+	return nil
+}
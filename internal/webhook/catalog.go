@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// EventType describes an event webhooks receive, with a sample payload and
+// a JSON Schema of the payload.
+type EventType struct {
+	Type        domain.EventType `json:"type"`
+	Description string           `json:"description"`
+	Sample      Payload          `json:"sample"`
+	Schema      map[string]any   `json:"schema"`
+}
+
+// eventField is a field of an event's data.
+type eventField struct {
+	name     string
+	kind     string
+	required bool
+	sample   any
+}
+
+// eventDoc documents one event type.
+type eventDoc struct {
+	description string
+	fields      []eventField
+}
+
+// eventDocs documents every type in Events.
+var eventDocs = map[domain.EventType]eventDoc{
+	domain.EventIssueCreated: {
+		description: "An issue was created, or copied into the project.",
+	},
+	domain.EventStatusChanged: {
+		description: "An issue's status changed. job_id is set when an AI job changed it.",
+		fields: []eventField{
+			{name: "from", kind: "string", required: true, sample: string(domain.IssueStatusOpen)},
+			{name: "to", kind: "string", required: true, sample: string(domain.IssueStatusCompleted)},
+			{name: "job_id", kind: "integer"},
+		},
+	},
+	domain.EventAIRun: {
+		description: "An AI job was queued for an issue. retry is set when it retries a finished job.",
+		fields: []eventField{
+			{name: "job_id", kind: "integer", required: true, sample: 42},
+			{name: "mode", kind: "string", required: true, sample: "implement"},
+			{name: "retry", kind: "boolean"},
+		},
+	},
+	domain.EventAIJobCompleted: {
+		description: "An AI job finished successfully.",
+		fields: []eventField{
+			{name: "job_id", kind: "integer", required: true, sample: 42},
+			{name: "mode", kind: "string", required: true, sample: "implement"},
+		},
+	},
+	domain.EventAIJobFailed: {
+		description: "An AI job failed.",
+		fields: []eventField{
+			{name: "job_id", kind: "integer", required: true, sample: 42},
+			{name: "mode", kind: "string", required: true, sample: "implement"},
+			{name: "error", kind: "string", required: true, sample: "exit status 1"},
+		},
+	},
+	domain.EventAIJobCancelled: {
+		description: "An AI job was cancelled.",
+		fields: []eventField{
+			{name: "job_id", kind: "integer", required: true, sample: 42},
+			{name: "mode", kind: "string", required: true, sample: "implement"},
+		},
+	},
+}
+
+// Catalog returns every event type webhooks receive, in the order of
+// Events.
+func Catalog() []EventType {
+	catalog := make([]EventType, len(Events))
+	for i, typ := range Events {
+		catalog[i] = EventType{
+			Type:        typ,
+			Description: eventDocs[typ].description,
+			Sample:      Sample(typ),
+			Schema:      schema(typ),
+		}
+	}
+	return catalog
+}
+
+// Known reports whether webhooks receive events of the type.
+func Known(typ domain.EventType) bool {
+	_, ok := eventDocs[typ]
+	return ok
+}
+
+// Sample returns an example payload of an event type.
+func Sample(typ domain.EventType) Payload {
+	data := domain.EventData{}
+	for _, f := range eventDocs[typ].fields {
+		if f.sample != nil {
+			data[f.name] = f.sample
+		}
+	}
+	actorID := int64(1)
+	return Payload{
+		ID:         1,
+		Type:       typ,
+		ProjectID:  1,
+		IssueID:    1,
+		ActorID:    &actorID,
+		Data:       data,
+		OccurredAt: time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// schema returns the JSON Schema of an event type's payload.
+func schema(typ domain.EventType) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for _, f := range eventDocs[typ].fields {
+		properties[f.name] = map[string]any{"type": f.kind}
+		if f.required {
+			required = append(required, f.name)
+		}
+	}
+
+	return map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"id":          map[string]any{"type": "integer"},
+			"type":        map[string]any{"const": string(typ)},
+			"project_id":  map[string]any{"type": "integer"},
+			"issue_id":    map[string]any{"type": "integer"},
+			"actor_id":    map[string]any{"type": "integer"},
+			"occurred_at": map[string]any{"type": "string", "format": "date-time"},
+			"data": map[string]any{
+				"type":       "object",
+				"properties": properties,
+				"required":   required,
+			},
+		},
+		"required": []string{"id", "type", "project_id", "issue_id", "data", "occurred_at"},
+	}
+}
@@ -0,0 +1,577 @@
+// Package webhook sends issue lifecycle and AI job events to external
+// endpoints as signed requests, retrying failed deliveries with exponential
+// backoff and jitter and keeping a log of every delivery. Project webhooks
+// can override the retry policy, and are deactivated once their endpoint
+// has failed continuously for a day. Events go to the endpoint
+// configured for the installation, as JSON, and to each active webhook of
+// their project, rendered through the webhook's template if it has one or
+// as a Microsoft Teams card for webhooks in the teams format. Run sends
+// each delivery the first time; failed deliveries wait for SweepRetries.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+const (
+	cursorName     = "webhook_dispatch"
+	batchSize      = 100
+	maxDrainBody   = 64 << 10
+	testDeliveryID = "test"
+)
+
+// errUnreachable is reported for deliveries whose endpoint could not be
+// connected to or did not answer in time.
+var errUnreachable = errors.New("endpoint could not be reached")
+
+// Events are the issue events sent to the webhook endpoint.
+var Events = []domain.EventType{
+	domain.EventIssueCreated,
+	domain.EventStatusChanged,
+	domain.EventAIRun,
+	domain.EventAIJobCompleted,
+	domain.EventAIJobFailed,
+	domain.EventAIJobCancelled,
+}
+
+// EventSource reads the issue event log.
+type EventSource interface {
+	ListAfter(ctx context.Context, after domain.LogPosition, types []domain.EventType, limit int) ([]domain.IssueEvent, error)
+	LatestPosition(ctx context.Context) (domain.LogPosition, error)
+}
+
+// WebhookStore reads project webhooks and tracks whether their endpoints
+// are failing.
+type WebhookStore interface {
+	ListActive(ctx context.Context, projectIDs []int64) ([]domain.Webhook, error)
+	RecordOutcome(ctx context.Context, id int64, delivered bool, disableAfter time.Duration) (*domain.Webhook, error)
+}
+
+// Alerter is told about webhooks deactivated because their endpoint kept
+// failing.
+type Alerter interface {
+	WebhookDisabled(ctx context.Context, w domain.Webhook)
+}
+
+// DeliveryStore keeps the delivery log.
+type DeliveryStore interface {
+	Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	Due(ctx context.Context, limit int, retries bool) ([]domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, id int64, attempt domain.WebhookAttempt) error
+}
+
+// CursorStore keeps the dispatcher's position in the event log.
+type CursorStore interface {
+	Get(ctx context.Context, name string) (domain.LogPosition, error)
+	Set(ctx context.Context, name string, position domain.LogPosition) error
+}
+
+// Payload is the JSON body of a delivery, and the data a webhook template
+// renders.
+type Payload struct {
+	ID         int64            `json:"id"`
+	Type       domain.EventType `json:"type"`
+	ProjectID  int64            `json:"project_id"`
+	IssueID    int64            `json:"issue_id"`
+	ActorID    *int64           `json:"actor_id,omitempty"`
+	Data       domain.EventData `json:"data"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// Dispatcher turns issue events into deliveries and sends them. Events are
+// recorded in the delivery log before they are sent, so a delivery survives
+// restarts and is sent at least once.
+type Dispatcher struct {
+	url          string
+	secret       []byte
+	events       EventSource
+	webhooks     WebhookStore
+	deliveries   DeliveryStore
+	cursors      CursorStore
+	client       *http.Client
+	hookClient   *http.Client
+	interval     time.Duration
+	maxAttempts  int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	jitter       float64
+	disableAfter time.Duration
+	alerter      Alerter
+	concurrency  int
+	linkBase     string
+}
+
+// Option configures a Dispatcher.
+type Option func(*Dispatcher)
+
+// WithClient sets the HTTP client deliveries to the installation's endpoint
+// are sent with. Project webhooks always use a client from NewGuardedClient.
+func WithClient(c *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = c
+	}
+}
+
+// WithInterval sets how often the dispatcher looks for new events.
+func WithInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.interval = interval
+	}
+}
+
+// WithMaxAttempts sets how many times a delivery is attempted before it is
+// marked failed.
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which doubles with each
+// further attempt up to limit.
+func WithBackoff(base, limit time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.backoff = base
+		d.maxBackoff = limit
+	}
+}
+
+// WithJitter sets the fraction of each retry delay, from 0 to 1, that is
+// randomized, for webhooks that do not set their own.
+func WithJitter(f float64) Option {
+	return func(d *Dispatcher) {
+		d.jitter = f
+	}
+}
+
+// WithDisableAfter sets how long a project webhook's endpoint may fail
+// continuously before the webhook is deactivated.
+func WithDisableAfter(after time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.disableAfter = after
+	}
+}
+
+// WithAlerter sets who is told about webhooks deactivated for failing.
+func WithAlerter(a Alerter) Option {
+	return func(d *Dispatcher) {
+		d.alerter = a
+	}
+}
+
+// WithConcurrency sets how many deliveries are sent at once.
+func WithConcurrency(n int) Option {
+	return func(d *Dispatcher) {
+		d.concurrency = n
+	}
+}
+
+// WithLinkBase sets the web app URL that messages for chat tools, such as
+// Teams cards, link issues to.
+func WithLinkBase(url string) Option {
+	return func(d *Dispatcher) {
+		d.linkBase = url
+	}
+}
+
+// New creates a Dispatcher that sends every event to url signed with secret,
+// and each project's events to its webhooks. An empty url sends events to
+// project webhooks only.
+func New(url string, secret []byte, events EventSource, webhooks WebhookStore, deliveries DeliveryStore, cursors CursorStore, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		url:          url,
+		secret:       secret,
+		events:       events,
+		webhooks:     webhooks,
+		deliveries:   deliveries,
+		cursors:      cursors,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		hookClient:   NewGuardedClient(10 * time.Second),
+		interval:     5 * time.Second,
+		maxAttempts:  8,
+		backoff:      30 * time.Second,
+		maxBackoff:   6 * time.Hour,
+		jitter:       0.2,
+		disableAfter: 24 * time.Hour,
+		concurrency:  4,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run dispatches events until ctx is cancelled. It must run on a single
+// instance at a time. On its first run it starts from the newest event
+// rather than sending the whole history.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	position, err := d.cursors.Get(ctx, cursorName)
+	if err != nil {
+		return err
+	}
+	if position.IsZero() {
+		if position, err = d.events.LatestPosition(ctx); err != nil {
+			return err
+		}
+		if err := d.cursors.Set(ctx, cursorName, position); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if position, err = d.enqueue(ctx, position); err != nil && ctx.Err() == nil {
+			slog.Error("webhook enqueue failed", "position", position, "error", err)
+		}
+		if err := d.sendDue(ctx, false); err != nil && ctx.Err() == nil {
+			slog.Error("webhook delivery failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueue records deliveries for every new event and returns the new
+// position.
+func (d *Dispatcher) enqueue(ctx context.Context, position domain.LogPosition) (domain.LogPosition, error) {
+	for {
+		events, err := d.events.ListAfter(ctx, position, Events, batchSize)
+		if err != nil {
+			return position, fmt.Errorf("list events: %w", err)
+		}
+		if len(events) == 0 {
+			return position, nil
+		}
+
+		hooks, err := d.projectWebhooks(ctx, events)
+		if err != nil {
+			return position, err
+		}
+
+		var deliveries []domain.WebhookDelivery
+		for _, e := range events {
+			payload := Payload{
+				ID:         e.ID,
+				Type:       e.Type,
+				ProjectID:  e.ProjectID,
+				IssueID:    e.IssueID,
+				ActorID:    e.ActorID,
+				Data:       e.Data,
+				OccurredAt: e.CreatedAt,
+			}
+			if d.url != "" {
+				body, err := json.Marshal(payload)
+				if err != nil {
+					return position, fmt.Errorf("encode event %d: %w", e.ID, err)
+				}
+				deliveries = append(deliveries, domain.WebhookDelivery{
+					EventID:     e.ID,
+					EventType:   e.Type,
+					ProjectID:   e.ProjectID,
+					URL:         d.url,
+					Body:        string(body),
+					ContentType: ContentTypeJSON,
+				})
+			}
+			for _, h := range hooks[e.ProjectID] {
+				body, err := d.render(h, payload)
+				if err != nil {
+					slog.Warn("webhook payload not rendered",
+						"webhook_id", h.ID,
+						"event_id", e.ID,
+						"error", err,
+					)
+					continue
+				}
+				deliveries = append(deliveries, domain.WebhookDelivery{
+					EventID:     e.ID,
+					EventType:   e.Type,
+					ProjectID:   e.ProjectID,
+					WebhookID:   &h.ID,
+					URL:         h.URL,
+					Body:        string(body),
+					ContentType: h.ContentType,
+				})
+			}
+		}
+		if err := d.deliveries.Enqueue(ctx, deliveries); err != nil {
+			return position, err
+		}
+
+		position = events[len(events)-1].Position()
+		if err := d.cursors.Set(ctx, cursorName, position); err != nil {
+			return position, err
+		}
+		if len(events) < batchSize {
+			return position, nil
+		}
+	}
+}
+
+// hook is an active project webhook with its parsed template.
+type hook struct {
+	domain.Webhook
+	tmpl *Template
+}
+
+// render returns the body of a delivery of the payload to the webhook.
+func (d *Dispatcher) render(h *hook, p Payload) ([]byte, error) {
+	switch {
+	case h.Format == domain.WebhookFormatTeams:
+		return teamsCard(p, d.linkBase)
+	case h.tmpl != nil:
+		return h.tmpl.Render(p)
+	}
+	return json.Marshal(p)
+}
+
+// projectWebhooks returns the active webhooks of the events' projects, keyed
+// by project. Webhooks whose template no longer parses are left out.
+func (d *Dispatcher) projectWebhooks(ctx context.Context, events []domain.IssueEvent) (map[int64][]*hook, error) {
+	seen := make(map[int64]bool)
+	var projectIDs []int64
+	for _, e := range events {
+		if !seen[e.ProjectID] {
+			seen[e.ProjectID] = true
+			projectIDs = append(projectIDs, e.ProjectID)
+		}
+	}
+
+	webhooks, err := d.webhooks.ListActive(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+
+	hooks := make(map[int64][]*hook)
+	for _, w := range webhooks {
+		h := &hook{Webhook: w}
+		if w.Template != nil {
+			if h.tmpl, err = ParseTemplate(*w.Template, w.ContentType); err != nil {
+				slog.Warn("webhook template not parsed", "webhook_id", w.ID, "error", err)
+				continue
+			}
+		}
+		hooks[w.ProjectID] = append(hooks[w.ProjectID], h)
+	}
+	return hooks, nil
+}
+
+// SweepRetries attempts again every failed delivery whose retry is due. It
+// must run on a single instance at a time.
+func (d *Dispatcher) SweepRetries(ctx context.Context) error {
+	return d.sendDue(ctx, true)
+}
+
+// sendDue sends every delivery that is due, a batch at a time: retries of
+// failed deliveries if retries is set, first attempts otherwise.
+func (d *Dispatcher) sendDue(ctx context.Context, retries bool) error {
+	for {
+		due, err := d.deliveries.Due(ctx, batchSize, retries)
+		if err != nil {
+			return fmt.Errorf("list due deliveries: %w", err)
+		}
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, d.concurrency)
+		for _, delivery := range due {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				attempt := d.attempt(ctx, delivery)
+				if err := d.deliveries.RecordAttempt(ctx, delivery.ID, attempt); err != nil {
+					slog.Error("webhook attempt not recorded", "delivery_id", delivery.ID, "error", err)
+				}
+				if delivery.WebhookID != nil {
+					d.recordOutcome(ctx, *delivery.WebhookID, attempt.Status == domain.WebhookDeliveryDelivered)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(due) < batchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// attempt sends a delivery once and returns the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery domain.WebhookDelivery) domain.WebhookAttempt {
+	code, err := d.send(ctx, delivery)
+	if err == nil {
+		return domain.WebhookAttempt{Status: domain.WebhookDeliveryDelivered, StatusCode: &code}
+	}
+
+	msg := err.Error()
+	attempt := domain.WebhookAttempt{Status: domain.WebhookDeliveryFailed, Error: &msg}
+	if code != 0 {
+		attempt.StatusCode = &code
+	}
+	maxAttempts := d.maxAttempts
+	if delivery.Retry.MaxAttempts != nil {
+		maxAttempts = *delivery.Retry.MaxAttempts
+	}
+	if attempts := delivery.Attempts + 1; attempts < maxAttempts {
+		next := time.Now().Add(d.retryDelay(attempts, delivery.Retry))
+		attempt.Status = domain.WebhookDeliveryPending
+		attempt.NextAttemptAt = &next
+	}
+	slog.Warn("webhook delivery attempt failed",
+		"delivery_id", delivery.ID,
+		"event_id", delivery.EventID,
+		"status", attempt.Status,
+		"error", err,
+	)
+	return attempt
+}
+
+// recordOutcome tracks whether a project webhook's endpoint is failing, and
+// raises an alert if it has failed for so long that it was deactivated.
+func (d *Dispatcher) recordOutcome(ctx context.Context, webhookID int64, delivered bool) {
+	disabled, err := d.webhooks.RecordOutcome(ctx, webhookID, delivered, d.disableAfter)
+	if err != nil {
+		slog.Error("webhook outcome not recorded", "webhook_id", webhookID, "error", err)
+		return
+	}
+	if disabled == nil {
+		return
+	}
+	slog.Warn("webhook deactivated after failing continuously",
+		"webhook_id", webhookID,
+		"failing_since", disabled.FailingSince,
+	)
+	if d.alerter != nil {
+		d.alerter.WebhookDisabled(ctx, *disabled)
+	}
+}
+
+// Test sends a sample payload of an event type to a webhook right away,
+// rendered and signed as a real delivery would be, and reports the outcome.
+// Test deliveries are not recorded or retried, and carry the delivery ID
+// "test".
+func (d *Dispatcher) Test(ctx context.Context, w domain.Webhook, typ domain.EventType) (*domain.WebhookTestResult, error) {
+	h := &hook{Webhook: w}
+	if w.Template != nil {
+		tmpl, err := ParseTemplate(*w.Template, w.ContentType)
+		if err != nil {
+			return nil, &domain.ValidationError{Field: "template", Message: err.Error()}
+		}
+		h.tmpl = tmpl
+	}
+	body, err := d.render(h, Sample(typ))
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "template", Message: err.Error()}
+	}
+
+	start := time.Now()
+	code, err := d.send(ctx, domain.WebhookDelivery{
+		EventType:   typ,
+		WebhookID:   &w.ID,
+		URL:         w.URL,
+		Body:        string(body),
+		ContentType: w.ContentType,
+		Secret:      w.Secret,
+	})
+	result := &domain.WebhookTestResult{
+		EventType: typ,
+		Body:      string(body),
+		Delivered: err == nil,
+		Duration:  time.Since(start).Milliseconds(),
+	}
+	if code != 0 {
+		result.StatusCode = &code
+	}
+	if err != nil {
+		msg := err.Error()
+		result.Error = &msg
+	}
+	return result, nil
+}
+
+// retryDelay returns how long to wait after the given number of failed
+// attempts, under the dispatcher's defaults overridden by policy. The
+// jittered part of the delay is spread evenly around it.
+func (d *Dispatcher) retryDelay(attempts int, policy domain.WebhookRetryPolicy) time.Duration {
+	delay, jitter := d.backoff, d.jitter
+	if policy.BackoffSeconds != nil {
+		delay = time.Duration(*policy.BackoffSeconds) * time.Second
+	}
+	if policy.Jitter != nil {
+		jitter = *policy.Jitter
+	}
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, d.maxBackoff)
+	return delay + time.Duration(float64(delay)*jitter*(rand.Float64()-0.5))
+}
+
+// send posts a delivery and returns the response status code. Any status
+// outside 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	secret := d.secret
+	if delivery.WebhookID != nil {
+		secret = []byte(delivery.Secret)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", delivery.ContentType)
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	if delivery.ID == 0 {
+		req.Header.Set(HeaderDelivery, testDeliveryID)
+	} else {
+		req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	}
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	client := d.client
+	if delivery.WebhookID != nil {
+		client = d.hookClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The cause may describe the network behind the endpoint, so it is
+		// logged rather than reported to the user who configured it.
+		slog.Info("webhook endpoint unreachable", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "error", err)
+		if errors.Is(err, ErrForbiddenAddress) {
+			return 0, ErrForbiddenAddress
+		}
+		return 0, errUnreachable
+	}
+	defer resp.Body.Close()
+
+	// Only the status is reported: the response body is the endpoint's and
+	// is never passed back to the user who configured the webhook either.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
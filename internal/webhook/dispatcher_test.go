@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+func TestSendDoesNotReturnResponseBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal secret"))
+	}))
+	defer srv.Close()
+
+	d := New(srv.URL, []byte("secret"), nil, nil, nil, nil)
+	code, err := d.send(context.Background(), domain.WebhookDelivery{
+		ID:          1,
+		EventType:   domain.EventIssueCreated,
+		URL:         srv.URL,
+		Body:        "{}",
+		ContentType: ContentTypeJSON,
+	})
+	if code != http.StatusInternalServerError {
+		t.Errorf("send() code = %d, want %d", code, http.StatusInternalServerError)
+	}
+	if err == nil || strings.Contains(err.Error(), "internal secret") {
+		t.Errorf("send() error = %v, want a generic error", err)
+	}
+}
+
+func TestSendHidesTransportErrors(t *testing.T) {
+	hookID := int64(1)
+	d := New("", nil, nil, nil, nil, nil)
+	_, err := d.send(context.Background(), domain.WebhookDelivery{
+		ID:          1,
+		EventType:   domain.EventIssueCreated,
+		WebhookID:   &hookID,
+		URL:         "http://10.0.0.1:6379/",
+		Body:        "{}",
+		ContentType: ContentTypeJSON,
+	})
+	if err != ErrForbiddenAddress {
+		t.Errorf("send() error = %v, want %v", err, ErrForbiddenAddress)
+	}
+}
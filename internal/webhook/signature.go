@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers sent with every delivery. Receivers verify a delivery by computing
+// the HMAC-SHA256 of the timestamp, a dot and the body with the shared
+// secret, and comparing it with the signature after its "sha256=" prefix.
+// Rejecting old timestamps guards against replays.
+const (
+	HeaderEvent     = "X-Issues-Event"
+	HeaderDelivery  = "X-Issues-Delivery"
+	HeaderTimestamp = "X-Issues-Timestamp"
+	HeaderSignature = "X-Issues-Signature"
+)
+
+// Sign returns the signature header value for body sent at timestamp, in
+// seconds since the Unix epoch.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for body sent at timestamp.
+func Verify(secret []byte, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
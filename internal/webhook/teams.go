@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sumire/issues/internal/chat"
+)
+
+// adaptiveCardType is the attachment content type Teams renders as an
+// Adaptive Card.
+const adaptiveCardType = "application/vnd.microsoft.card.adaptive"
+
+// teamsMessage is the body a Teams incoming webhook accepts.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+	Actions []map[string]any `json:"actions,omitempty"`
+}
+
+type fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsCard renders a payload as a Teams message with an Adaptive Card
+// summarising the event. If linkBase is set, the card links to the issue in
+// the web app served there.
+func teamsCard(p Payload, linkBase string) ([]byte, error) {
+	facts := []fact{
+		{Title: "Project", Value: fmt.Sprintf("#%d", p.ProjectID)},
+		{Title: "Issue", Value: fmt.Sprintf("#%d", p.IssueID)},
+	}
+	keys := make([]string, 0, len(p.Data))
+	for k := range p.Data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		facts = append(facts, fact{Title: k, Value: fmt.Sprint(p.Data[k])})
+	}
+
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]any{
+			{"type": "TextBlock", "text": chat.Summary(p.Type, p.IssueID, p.Data), "size": "Medium", "weight": "Bolder", "wrap": true},
+			{"type": "TextBlock", "text": p.OccurredAt.UTC().Format("2006-01-02 15:04 MST"), "isSubtle": true, "spacing": "None"},
+			{"type": "FactSet", "facts": facts},
+		},
+	}
+	if linkBase != "" {
+		card.Actions = []map[string]any{{
+			"type":  "Action.OpenUrl",
+			"title": "View issue",
+			"url":   fmt.Sprintf("%s/projects/%d/issues/%d", strings.TrimRight(linkBase, "/"), p.ProjectID, p.IssueID),
+		}}
+	}
+
+	return json.Marshal(teamsMessage{
+		Type:        "message",
+		Attachments: []teamsAttachment{{ContentType: adaptiveCardType, Content: card}},
+	})
+}
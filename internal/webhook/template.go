@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sumire/issues/internal/domain"
+)
+
+// MaxBodySize is the largest body a template may render.
+const MaxBodySize = 64 << 10
+
+// ContentTypeJSON is the content type of deliveries without a template.
+const ContentTypeJSON = "application/json"
+
+var errBodyTooLarge = fmt.Errorf("rendered body exceeds %d bytes", MaxBodySize)
+
+// funcs are the functions available to payload templates.
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"default": func(fallback, v any) any {
+		if v == nil || v == "" {
+			return fallback
+		}
+		return v
+	},
+	"rfc3339": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"unix": func(t time.Time) int64 {
+		return t.Unix()
+	},
+}
+
+// Template renders payloads into the body a receiver expects. Templates use
+// text/template syntax with the Payload as dot, so {{.Type}} is the event
+// type and {{.Data.status}} a field of the event data.
+type Template struct {
+	tmpl        *template.Template
+	contentType string
+}
+
+// ParseTemplate parses a payload template whose output is sent with the
+// given content type. Missing map keys render as the zero value rather than
+// "<no value>".
+func ParseTemplate(text, contentType string) (*Template, error) {
+	tmpl, err := template.New("payload").Funcs(funcs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{tmpl: tmpl, contentType: contentType}, nil
+}
+
+// Render renders a payload. Output for a JSON content type must be valid
+// JSON.
+func (t *Template) Render(p Payload) ([]byte, error) {
+	w := &limitedBuffer{limit: MaxBodySize}
+	if err := t.tmpl.Execute(w, p); err != nil {
+		if errors.Is(err, errBodyTooLarge) {
+			return nil, errBodyTooLarge
+		}
+		return nil, err
+	}
+	body := w.Bytes()
+	if isJSON(t.contentType) && !json.Valid(body) {
+		return nil, errors.New("rendered body is not valid JSON")
+	}
+	return body, nil
+}
+
+// ValidateTemplate checks that a template parses and renders a sample
+// payload into a body that fits the content type.
+func ValidateTemplate(text, contentType string) error {
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return &domain.ValidationError{Field: "content_type", Message: "is not a valid media type"}
+	}
+	tmpl, err := ParseTemplate(text, contentType)
+	if err != nil {
+		return &domain.ValidationError{Field: "template", Message: err.Error()}
+	}
+	if _, err := tmpl.Render(Sample(domain.EventStatusChanged)); err != nil {
+		return &domain.ValidationError{Field: "template", Message: err.Error()}
+	}
+	return nil
+}
+
+// isJSON reports whether a content type is JSON or a +json suffix type.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == ContentTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// limitedBuffer is a buffer that fails writes past its limit, so a runaway
+// template stops rendering instead of growing without bound.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errBodyTooLarge
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,3 @@
+ALTER TABLE ai_jobs
+    DROP COLUMN IF EXISTS triggered_by,
+    DROP COLUMN IF EXISTS request_id;
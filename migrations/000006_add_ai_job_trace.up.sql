@@ -0,0 +1,3 @@
+ALTER TABLE ai_jobs
+    ADD COLUMN request_id   TEXT,
+    ADD COLUMN triggered_by BIGINT REFERENCES users(id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_issues_has_ai_result;
+DROP INDEX IF EXISTS idx_issues_updated_at;
+DROP INDEX IF EXISTS idx_issues_created_at;
+DROP INDEX IF EXISTS idx_issues_created_by;
+DROP INDEX IF EXISTS idx_issues_assignee;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS assignee_id,
+    DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,9 @@
+ALTER TABLE issues
+    ADD COLUMN created_by  BIGINT REFERENCES users(id),
+    ADD COLUMN assignee_id BIGINT REFERENCES users(id);
+
+CREATE INDEX idx_issues_assignee ON issues (project_id, assignee_id);
+CREATE INDEX idx_issues_created_by ON issues (project_id, created_by);
+CREATE INDEX idx_issues_created_at ON issues (project_id, created_at);
+CREATE INDEX idx_issues_updated_at ON issues (project_id, updated_at);
+CREATE INDEX idx_issues_has_ai_result ON issues (project_id) WHERE ai_result IS NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TYPE project_role AS ENUM ('admin', 'member');
+
+CREATE TABLE project_members (
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES users(id),
+    role        project_role NOT NULL DEFAULT 'member',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX idx_project_members_user_id ON project_members (user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stars;
+DROP TABLE IF EXISTS recent_items;
+DROP TYPE IF EXISTS item_type;
@@ -0,0 +1,21 @@
+CREATE TYPE item_type AS ENUM ('project', 'issue');
+
+CREATE TABLE recent_items (
+    user_id    BIGINT NOT NULL REFERENCES users(id),
+    item_type  item_type NOT NULL,
+    item_id    BIGINT NOT NULL,
+    viewed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, item_type, item_id)
+);
+
+CREATE INDEX idx_recent_items_user_viewed ON recent_items (user_id, viewed_at DESC);
+
+CREATE TABLE stars (
+    user_id    BIGINT NOT NULL REFERENCES users(id),
+    item_type  item_type NOT NULL,
+    item_id    BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, item_type, item_id)
+);
+
+CREATE INDEX idx_stars_user_created ON stars (user_id, created_at DESC);
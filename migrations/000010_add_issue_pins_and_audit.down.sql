@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_logs;
+DROP INDEX IF EXISTS idx_issues_pinned;
+ALTER TABLE issues DROP COLUMN IF EXISTS pinned_at;
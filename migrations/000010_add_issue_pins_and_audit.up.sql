@@ -0,0 +1,15 @@
+ALTER TABLE issues ADD COLUMN pinned_at TIMESTAMPTZ;
+
+CREATE INDEX idx_issues_pinned ON issues (project_id, pinned_at) WHERE pinned_at IS NOT NULL;
+
+CREATE TABLE audit_logs (
+    id          BIGSERIAL PRIMARY KEY,
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor_id    BIGINT NOT NULL REFERENCES users(id),
+    action      TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id   BIGINT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_project_created ON audit_logs (project_id, created_at DESC);
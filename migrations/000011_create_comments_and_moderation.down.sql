@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS content_reports;
+DROP TYPE IF EXISTS report_status;
+DROP TABLE IF EXISTS project_blocks;
+DROP TABLE IF EXISTS comments;
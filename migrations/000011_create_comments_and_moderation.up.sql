@@ -0,0 +1,38 @@
+CREATE TABLE comments (
+    id          BIGSERIAL PRIMARY KEY,
+    issue_id    BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    author_id   BIGINT NOT NULL REFERENCES users(id),
+    body        TEXT NOT NULL,
+    hidden_at   TIMESTAMPTZ,
+    hidden_by   BIGINT REFERENCES users(id),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_comments_issue_id ON comments (issue_id, id);
+
+CREATE TABLE project_blocks (
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES users(id),
+    blocked_by  BIGINT NOT NULL REFERENCES users(id),
+    reason      TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE TYPE report_status AS ENUM ('open', 'resolved', 'dismissed');
+
+CREATE TABLE content_reports (
+    id           BIGSERIAL PRIMARY KEY,
+    project_id   BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    reporter_id  BIGINT NOT NULL REFERENCES users(id),
+    target_type  TEXT NOT NULL,
+    target_id    BIGINT NOT NULL,
+    reason       TEXT NOT NULL,
+    status       report_status NOT NULL DEFAULT 'open',
+    resolved_by  BIGINT REFERENCES users(id),
+    resolved_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_content_reports_queue ON content_reports (project_id, status, id);
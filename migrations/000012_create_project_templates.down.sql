@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS project_templates;
+DROP TABLE IF EXISTS labels;
+ALTER TABLE projects DROP COLUMN IF EXISTS settings;
@@ -0,0 +1,58 @@
+ALTER TABLE projects ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE labels (
+    id          BIGSERIAL PRIMARY KEY,
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    color       TEXT NOT NULL DEFAULT '#888888',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE TABLE project_templates (
+    id          BIGSERIAL PRIMARY KEY,
+    owner_id    BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    description TEXT,
+    definition  JSONB NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_project_templates_owner ON project_templates (owner_id);
+
+-- Built-in templates have no owner and are visible to every user.
+INSERT INTO project_templates (name, description, definition) VALUES
+(
+    'Software development',
+    'Bug and feature tracking with AI triage',
+    '{
+        "labels": [
+            {"name": "bug", "color": "#d73a4a"},
+            {"name": "feature", "color": "#a2eeef"},
+            {"name": "docs", "color": "#0075ca"},
+            {"name": "chore", "color": "#cfd3d7"}
+        ],
+        "issue_templates": [
+            {"name": "Bug report", "title": "Bug: ", "body": "## Steps to reproduce\n\n## Expected\n\n## Actual\n"},
+            {"name": "Feature request", "title": "Feature: ", "body": "## Problem\n\n## Proposal\n"}
+        ],
+        "automation_rules": [
+            {"trigger": "issue.created", "action": "add_label", "value": "bug", "match": "Bug:"}
+        ]
+    }'
+),
+(
+    'Operations',
+    'Incident and maintenance tracking',
+    '{
+        "labels": [
+            {"name": "incident", "color": "#b60205"},
+            {"name": "maintenance", "color": "#fbca04"},
+            {"name": "postmortem", "color": "#5319e7"}
+        ],
+        "issue_templates": [
+            {"name": "Incident", "title": "Incident: ", "body": "## Impact\n\n## Timeline\n\n## Mitigation\n"}
+        ],
+        "automation_rules": []
+    }'
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_ai_jobs_triggered_by;
+DROP INDEX IF EXISTS idx_comments_author;
+DROP INDEX IF EXISTS idx_issues_closed_by;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS closed_by;
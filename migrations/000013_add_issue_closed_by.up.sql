@@ -0,0 +1,7 @@
+ALTER TABLE issues
+    ADD COLUMN closed_by BIGINT REFERENCES users(id),
+    ADD COLUMN closed_at TIMESTAMPTZ;
+
+CREATE INDEX idx_issues_closed_by ON issues (project_id, closed_by, closed_at) WHERE closed_by IS NOT NULL;
+CREATE INDEX idx_comments_author ON comments (author_id, created_at);
+CREATE INDEX idx_ai_jobs_triggered_by ON ai_jobs (triggered_by, created_at) WHERE triggered_by IS NOT NULL;
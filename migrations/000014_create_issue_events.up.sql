@@ -0,0 +1,12 @@
+CREATE TABLE issue_events (
+    id          BIGSERIAL PRIMARY KEY,
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id    BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    actor_id    BIGINT REFERENCES users(id),
+    type        TEXT NOT NULL,
+    data        JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_issue_events_issue ON issue_events (issue_id, id);
+CREATE INDEX idx_issue_events_actor ON issue_events (actor_id, id DESC);
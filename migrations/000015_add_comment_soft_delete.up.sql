@@ -0,0 +1,3 @@
+ALTER TABLE comments
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by BIGINT REFERENCES users(id);
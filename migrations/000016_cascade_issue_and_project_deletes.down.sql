@@ -0,0 +1,14 @@
+ALTER TABLE notifications
+    DROP CONSTRAINT notifications_issue_id_fkey,
+    ADD CONSTRAINT notifications_issue_id_fkey
+        FOREIGN KEY (issue_id) REFERENCES issues(id);
+
+ALTER TABLE ai_jobs
+    DROP CONSTRAINT ai_jobs_issue_id_fkey,
+    ADD CONSTRAINT ai_jobs_issue_id_fkey
+        FOREIGN KEY (issue_id) REFERENCES issues(id);
+
+ALTER TABLE issues
+    DROP CONSTRAINT issues_project_id_fkey,
+    ADD CONSTRAINT issues_project_id_fkey
+        FOREIGN KEY (project_id) REFERENCES projects(id);
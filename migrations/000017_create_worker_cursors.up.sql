@@ -0,0 +1,7 @@
+-- Background workers record how far through an append-only table they have
+-- processed, so they resume where they left off after a restart.
+CREATE TABLE worker_cursors (
+    name        TEXT PRIMARY KEY,
+    position    BIGINT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_issues_archivable;
+DROP INDEX IF EXISTS idx_issues_archived;
+DROP INDEX IF EXISTS idx_issues_active;
+DROP INDEX IF EXISTS idx_issues_status;
+CREATE INDEX idx_issues_status ON issues (project_id, status);
+
+ALTER TABLE issues DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,8 @@
+ALTER TABLE issues ADD COLUMN archived_at TIMESTAMPTZ;
+
+-- Default listings only see unarchived issues, so their indexes skip archived rows.
+DROP INDEX idx_issues_status;
+CREATE INDEX idx_issues_status ON issues (project_id, status) WHERE archived_at IS NULL;
+CREATE INDEX idx_issues_active ON issues (project_id, id) WHERE archived_at IS NULL;
+CREATE INDEX idx_issues_archived ON issues (project_id, id) WHERE archived_at IS NOT NULL;
+CREATE INDEX idx_issues_archivable ON issues (closed_at) WHERE archived_at IS NULL AND closed_at IS NOT NULL;
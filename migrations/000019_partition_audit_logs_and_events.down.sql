@@ -0,0 +1,40 @@
+-- issue_events
+ALTER TABLE issue_events RENAME TO issue_events_partitioned;
+ALTER TABLE issue_events_partitioned RENAME CONSTRAINT issue_events_pkey TO issue_events_partitioned_pkey;
+
+CREATE TABLE issue_events (
+    id          BIGINT PRIMARY KEY DEFAULT nextval('issue_events_id_seq'),
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id    BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    actor_id    BIGINT REFERENCES users(id),
+    type        TEXT NOT NULL,
+    data        JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER SEQUENCE issue_events_id_seq OWNED BY issue_events.id;
+INSERT INTO issue_events SELECT * FROM issue_events_partitioned;
+DROP TABLE issue_events_partitioned;
+CREATE INDEX idx_issue_events_issue ON issue_events (issue_id, id);
+CREATE INDEX idx_issue_events_actor ON issue_events (actor_id, id DESC);
+
+-- audit_logs
+ALTER TABLE audit_logs RENAME TO audit_logs_partitioned;
+ALTER TABLE audit_logs_partitioned RENAME CONSTRAINT audit_logs_pkey TO audit_logs_partitioned_pkey;
+
+CREATE TABLE audit_logs (
+    id          BIGINT PRIMARY KEY DEFAULT nextval('audit_logs_id_seq'),
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor_id    BIGINT NOT NULL REFERENCES users(id),
+    action      TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id   BIGINT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER SEQUENCE audit_logs_id_seq OWNED BY audit_logs.id;
+INSERT INTO audit_logs SELECT * FROM audit_logs_partitioned;
+DROP TABLE audit_logs_partitioned;
+CREATE INDEX idx_audit_logs_project_created ON audit_logs (project_id, created_at DESC);
+
+DROP FUNCTION IF EXISTS create_monthly_partition(TEXT, DATE);
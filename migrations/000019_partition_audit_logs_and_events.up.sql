@@ -0,0 +1,68 @@
+-- audit_logs and issue_events are partitioned by month on created_at so that
+-- retention is a cheap DETACH + DROP. Rows from before this migration live in
+-- each table's default partition. The partition maintenance job creates
+-- partitions ahead of time with create_monthly_partition.
+
+CREATE FUNCTION create_monthly_partition(parent TEXT, month DATE) RETURNS VOID AS $$
+DECLARE
+    start_at DATE := date_trunc('month', month);
+    name     TEXT := format('%s_y%sm%s', parent, to_char(start_at, 'YYYY'), to_char(start_at, 'MM'));
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        name, parent, start_at, start_at + INTERVAL '1 month');
+END;
+$$ LANGUAGE plpgsql;
+
+-- audit_logs
+ALTER TABLE audit_logs RENAME TO audit_logs_legacy;
+ALTER TABLE audit_logs_legacy RENAME CONSTRAINT audit_logs_pkey TO audit_logs_legacy_pkey;
+ALTER INDEX idx_audit_logs_project_created RENAME TO idx_audit_logs_legacy_project_created;
+
+CREATE TABLE audit_logs (
+    id          BIGINT NOT NULL DEFAULT nextval('audit_logs_id_seq'),
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor_id    BIGINT NOT NULL REFERENCES users(id),
+    action      TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id   BIGINT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE audit_logs_id_seq OWNED BY audit_logs.id;
+CREATE INDEX idx_audit_logs_project_created ON audit_logs (project_id, created_at DESC);
+CREATE TABLE audit_logs_default PARTITION OF audit_logs DEFAULT;
+SELECT create_monthly_partition('audit_logs', CURRENT_DATE);
+SELECT create_monthly_partition('audit_logs', (CURRENT_DATE + INTERVAL '1 month')::date);
+
+INSERT INTO audit_logs SELECT * FROM audit_logs_legacy;
+DROP TABLE audit_logs_legacy;
+
+-- issue_events
+ALTER TABLE issue_events RENAME TO issue_events_legacy;
+ALTER TABLE issue_events_legacy RENAME CONSTRAINT issue_events_pkey TO issue_events_legacy_pkey;
+ALTER INDEX idx_issue_events_issue RENAME TO idx_issue_events_legacy_issue;
+ALTER INDEX idx_issue_events_actor RENAME TO idx_issue_events_legacy_actor;
+
+CREATE TABLE issue_events (
+    id          BIGINT NOT NULL DEFAULT nextval('issue_events_id_seq'),
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    issue_id    BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    actor_id    BIGINT REFERENCES users(id),
+    type        TEXT NOT NULL,
+    data        JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE issue_events_id_seq OWNED BY issue_events.id;
+CREATE INDEX idx_issue_events_issue ON issue_events (issue_id, id);
+CREATE INDEX idx_issue_events_actor ON issue_events (actor_id, id DESC);
+CREATE INDEX idx_issue_events_id ON issue_events (id);
+CREATE TABLE issue_events_default PARTITION OF issue_events DEFAULT;
+SELECT create_monthly_partition('issue_events', CURRENT_DATE);
+SELECT create_monthly_partition('issue_events', (CURRENT_DATE + INTERVAL '1 month')::date);
+
+INSERT INTO issue_events SELECT * FROM issue_events_legacy;
+DROP TABLE issue_events_legacy;
@@ -0,0 +1,23 @@
+CREATE TYPE organization_role AS ENUM ('admin', 'member');
+
+CREATE TABLE organizations (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    owner_id    BIGINT NOT NULL REFERENCES users(id),
+    defaults    JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE organization_members (
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id         BIGINT NOT NULL REFERENCES users(id),
+    role            organization_role NOT NULL DEFAULT 'member',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members (user_id);
+
+ALTER TABLE projects ADD COLUMN organization_id BIGINT REFERENCES organizations(id) ON DELETE SET NULL;
+CREATE INDEX idx_projects_organization_id ON projects (organization_id) WHERE organization_id IS NOT NULL;
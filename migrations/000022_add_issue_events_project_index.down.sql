@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_issue_events_project_type;
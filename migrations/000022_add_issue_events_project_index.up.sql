@@ -0,0 +1 @@
+CREATE INDEX idx_issue_events_project_type ON issue_events (project_id, type, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ai_jobs_active;
+DROP INDEX IF EXISTS idx_ai_jobs_created;
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
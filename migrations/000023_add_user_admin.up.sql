@@ -0,0 +1,6 @@
+-- System administrators can use the /admin endpoints. There is no API to
+-- grant the flag; operators set it directly in the database.
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_ai_jobs_created ON ai_jobs (created_at DESC);
+CREATE INDEX idx_ai_jobs_active ON ai_jobs (status) WHERE status IN ('pending', 'running');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS system_flags;
+ALTER TABLE projects DROP COLUMN IF EXISTS ai_paused_at;
@@ -0,0 +1,10 @@
+-- Pausing AI processing leaves jobs queued but stops workers claiming them,
+-- either for one project or, through the ai_paused flag, everywhere.
+ALTER TABLE projects ADD COLUMN ai_paused_at TIMESTAMPTZ;
+
+CREATE TABLE system_flags (
+    name        TEXT PRIMARY KEY,
+    enabled     BOOLEAN NOT NULL,
+    updated_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ai_jobs_active_issue;
+ALTER TABLE ai_jobs DROP COLUMN IF EXISTS timeout_seconds;
@@ -0,0 +1,5 @@
+-- A job may ask for a shorter run than the server-wide limit; NULL uses the limit.
+ALTER TABLE ai_jobs ADD COLUMN timeout_seconds INT CHECK (timeout_seconds > 0);
+
+-- At most one job per issue may be queued or running.
+CREATE UNIQUE INDEX idx_ai_jobs_active_issue ON ai_jobs (issue_id) WHERE status IN ('pending', 'running');
@@ -0,0 +1,10 @@
+CREATE TABLE ai_job_artifacts (
+    id           BIGSERIAL PRIMARY KEY,
+    job_id       BIGINT NOT NULL REFERENCES ai_jobs(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    storage_key  TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes   BIGINT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (job_id, name)
+);
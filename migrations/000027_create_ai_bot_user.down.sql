@@ -0,0 +1,3 @@
+DELETE FROM comments WHERE author_id IN (SELECT id FROM users WHERE provider = 'system' AND provider_id = 'ai-bot');
+UPDATE issue_events SET actor_id = NULL WHERE actor_id IN (SELECT id FROM users WHERE provider = 'system' AND provider_id = 'ai-bot');
+DELETE FROM users WHERE provider = 'system' AND provider_id = 'ai-bot';
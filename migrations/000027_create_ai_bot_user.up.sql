@@ -0,0 +1,5 @@
+-- The AI bot authors comments summarising AI job results. It cannot sign in
+-- because no OAuth provider issues the system provider.
+INSERT INTO users (provider, provider_id, email, display_name)
+VALUES ('system', 'ai-bot', 'ai-bot@system.invalid', 'AI bot')
+ON CONFLICT (provider, provider_id) DO NOTHING;
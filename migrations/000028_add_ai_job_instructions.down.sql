@@ -0,0 +1,3 @@
+ALTER TABLE ai_jobs
+    DROP COLUMN IF EXISTS resume_session_id,
+    DROP COLUMN IF EXISTS instructions;
@@ -0,0 +1,3 @@
+ALTER TABLE ai_jobs
+    ADD COLUMN instructions      TEXT,
+    ADD COLUMN resume_session_id TEXT;
@@ -0,0 +1,18 @@
+-- Each attempt of an AI job is kept as a run so users can compare attempts.
+CREATE TABLE ai_runs (
+    id           BIGSERIAL PRIMARY KEY,
+    job_id       BIGINT NOT NULL REFERENCES ai_jobs(id) ON DELETE CASCADE,
+    issue_id     BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    attempt      INT NOT NULL,
+    prompt       TEXT NOT NULL,
+    session_id   TEXT,
+    result       TEXT,
+    status       job_status NOT NULL DEFAULT 'running',
+    triggered_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    approved_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    started_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ,
+    UNIQUE (job_id, attempt)
+);
+
+CREATE INDEX idx_ai_runs_issue ON ai_runs (issue_id, id DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ai_review_comments;
+ALTER TABLE ai_jobs
+    DROP COLUMN IF EXISTS diff,
+    DROP COLUMN IF EXISTS mode;
+DROP TYPE IF EXISTS ai_job_mode;
@@ -0,0 +1,20 @@
+-- Review jobs ask the agent to review a submitted diff instead of
+-- implementing the issue; their findings are stored as review comments.
+CREATE TYPE ai_job_mode AS ENUM ('implement', 'review');
+
+ALTER TABLE ai_jobs
+    ADD COLUMN mode ai_job_mode NOT NULL DEFAULT 'implement',
+    ADD COLUMN diff TEXT;
+
+CREATE TABLE ai_review_comments (
+    id         BIGSERIAL PRIMARY KEY,
+    job_id     BIGINT NOT NULL REFERENCES ai_jobs(id) ON DELETE CASCADE,
+    issue_id   BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    path       TEXT NOT NULL,
+    line       INT,
+    severity   TEXT NOT NULL,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ai_review_comments_issue ON ai_review_comments (issue_id, id DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS embedding_backfills;
+DROP TYPE IF EXISTS backfill_status;
+DROP TABLE IF EXISTS issue_embeddings;
@@ -0,0 +1,27 @@
+-- Issue embeddings back semantic search. Backfills compute them for
+-- existing issues in batches, keeping their position so they can resume.
+CREATE TABLE issue_embeddings (
+    issue_id   BIGINT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE,
+    model      TEXT NOT NULL,
+    embedding  REAL[] NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TYPE backfill_status AS ENUM ('running', 'completed', 'cancelled');
+
+CREATE TABLE embedding_backfills (
+    id            BIGSERIAL PRIMARY KEY,
+    model         TEXT NOT NULL,
+    status        backfill_status NOT NULL DEFAULT 'running',
+    last_issue_id BIGINT NOT NULL DEFAULT 0,
+    processed     INT NOT NULL DEFAULT 0,
+    total         INT NOT NULL,
+    failures      INT NOT NULL DEFAULT 0,
+    last_error    TEXT,
+    started_by    BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_embedding_backfills_running ON embedding_backfills (status) WHERE status = 'running';
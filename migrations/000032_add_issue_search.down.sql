@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS issues_search_change ON issues;
+DROP FUNCTION IF EXISTS queue_issue_search_change();
+DROP TABLE IF EXISTS search_index_queue;
+DROP INDEX IF EXISTS idx_issues_search;
+ALTER TABLE issues DROP COLUMN IF EXISTS search_vector;
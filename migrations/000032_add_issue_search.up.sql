@@ -0,0 +1,30 @@
+-- Full-text search over issue titles and bodies. The search vector is kept
+-- current by Postgres; external search engines are fed from
+-- search_index_queue, which records every change to a searchable field.
+ALTER TABLE issues ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(body, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_issues_search ON issues USING GIN (search_vector);
+
+CREATE TABLE search_index_queue (
+    id         BIGSERIAL PRIMARY KEY,
+    issue_id   BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE FUNCTION queue_issue_search_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO search_index_queue (issue_id) VALUES (OLD.id);
+    ELSE
+        INSERT INTO search_index_queue (issue_id) VALUES (NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER issues_search_change
+    AFTER INSERT OR DELETE OR UPDATE OF title, body, status, archived_at ON issues
+    FOR EACH ROW EXECUTE FUNCTION queue_issue_search_change();
@@ -0,0 +1,4 @@
+-- Trigram index on issue titles for typeahead quick search.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_issues_title_trgm ON issues USING GIN (title gin_trgm_ops);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS issue_references;
+DROP INDEX IF EXISTS idx_projects_key;
+ALTER TABLE projects DROP COLUMN IF EXISTS key;
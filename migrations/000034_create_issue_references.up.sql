@@ -0,0 +1,18 @@
+-- Projects get a short key so their issues can be referenced as KEY-123
+-- from issue bodies and comments in any project.
+ALTER TABLE projects ADD COLUMN key TEXT CHECK (key ~ '^[A-Z][A-Z0-9]{1,9}$');
+CREATE UNIQUE INDEX idx_projects_key ON projects (key) WHERE key IS NOT NULL;
+
+-- A reference from an issue's title or body has no comment_id; one from a
+-- comment records the comment so it disappears with it.
+CREATE TABLE issue_references (
+    id              BIGSERIAL PRIMARY KEY,
+    source_issue_id BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    comment_id      BIGINT REFERENCES comments(id) ON DELETE CASCADE,
+    target_issue_id BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_issue_references_source
+    ON issue_references (source_issue_id, COALESCE(comment_id, 0), target_issue_id);
+CREATE INDEX idx_issue_references_target ON issue_references (target_issue_id);
@@ -0,0 +1,3 @@
+-- Notification listings page through all of a user's notifications, newest
+-- first; the existing index only covers unread ones.
+CREATE INDEX idx_notifications_user ON notifications (user_id, id DESC);
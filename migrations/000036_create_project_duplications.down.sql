@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_duplications;
+DROP TYPE IF EXISTS duplication_status;
@@ -0,0 +1,19 @@
+-- Duplicating a project with many open issues copies them in the
+-- background, in batches, keeping its position so it can resume.
+CREATE TYPE duplication_status AS ENUM ('running', 'completed');
+
+CREATE TABLE project_duplications (
+    id                BIGSERIAL PRIMARY KEY,
+    source_project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    project_id        BIGINT NOT NULL UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    status            duplication_status NOT NULL DEFAULT 'running',
+    last_issue_id     BIGINT NOT NULL DEFAULT 0,
+    copied            INT NOT NULL DEFAULT 0,
+    total             INT NOT NULL,
+    requested_by      BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at       TIMESTAMPTZ
+);
+
+CREATE INDEX idx_project_duplications_running ON project_duplications (id) WHERE status = 'running';
@@ -0,0 +1 @@
+ALTER TABLE labels DROP COLUMN IF EXISTS restricted;
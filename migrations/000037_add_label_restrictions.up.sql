@@ -0,0 +1,2 @@
+-- Restricted labels can only be applied or removed by project admins.
+ALTER TABLE labels ADD COLUMN restricted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_snoozed;
+ALTER TABLE notifications DROP COLUMN IF EXISTS snoozed_until;
@@ -0,0 +1,5 @@
+-- Snoozed notifications are hidden until snoozed_until, when the scheduler
+-- clears it and pushes them to the user again.
+ALTER TABLE notifications ADD COLUMN snoozed_until TIMESTAMPTZ;
+
+CREATE INDEX idx_notifications_snoozed ON notifications (snoozed_until) WHERE snoozed_until IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Quiet hours are minutes since midnight in the user's timezone. A window
+-- whose start is after its end spans midnight.
+CREATE TABLE notification_preferences (
+    user_id               BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone              TEXT NOT NULL DEFAULT 'UTC',
+    quiet_start           SMALLINT CHECK (quiet_start BETWEEN 0 AND 1439),
+    quiet_end             SMALLINT CHECK (quiet_end BETWEEN 0 AND 1439),
+    do_not_disturb_until  TIMESTAMPTZ,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((quiet_start IS NULL) = (quiet_end IS NULL))
+);
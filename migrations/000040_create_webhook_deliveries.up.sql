@@ -0,0 +1,24 @@
+-- Every issue event sent to the webhook endpoint gets a delivery row that
+-- keeps the payload, so retries send the same body, and the outcome of the
+-- latest attempt.
+CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'delivered', 'failed');
+
+CREATE TABLE webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    event_id         BIGINT NOT NULL UNIQUE,
+    event_type       TEXT NOT NULL,
+    project_id       BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    url              TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    status           webhook_delivery_status NOT NULL DEFAULT 'pending',
+    attempts         INT NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ DEFAULT NOW(),
+    last_status_code INT,
+    last_error       TEXT,
+    delivered_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_project ON webhook_deliveries (project_id, id);
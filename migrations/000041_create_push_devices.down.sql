@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS push_queue;
+DROP TABLE IF EXISTS push_devices;
+DROP TYPE IF EXISTS push_platform;
@@ -0,0 +1,24 @@
+-- Devices registered for push notifications. A token belongs to one user at
+-- a time: registering it again moves it to the new user.
+CREATE TYPE push_platform AS ENUM ('fcm', 'apns');
+
+CREATE TABLE push_devices (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform   push_platform NOT NULL,
+    token      TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (platform, token)
+);
+
+CREATE INDEX idx_push_devices_user ON push_devices (user_id);
+
+-- Notifications waiting to be pushed, held until deliver_at when they fall
+-- in the user's quiet hours.
+CREATE TABLE push_queue (
+    notification_id BIGINT PRIMARY KEY REFERENCES notifications(id) ON DELETE CASCADE,
+    deliver_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_push_queue_deliver_at ON push_queue (deliver_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ai_job_logs;
+DROP TYPE IF EXISTS ai_log_stream;
@@ -0,0 +1,14 @@
+-- Output of AI runs, appended in chunks while Claude Code runs so that it
+-- can be followed live.
+CREATE TYPE ai_log_stream AS ENUM ('stdout', 'stderr');
+
+CREATE TABLE ai_job_logs (
+    id         BIGSERIAL PRIMARY KEY,
+    job_id     BIGINT NOT NULL REFERENCES ai_jobs(id) ON DELETE CASCADE,
+    run_id     BIGINT NOT NULL REFERENCES ai_runs(id) ON DELETE CASCADE,
+    stream     ai_log_stream NOT NULL,
+    data       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ai_job_logs_job ON ai_job_logs (job_id, id);
@@ -0,0 +1,8 @@
+UPDATE ai_jobs SET status = 'failed' WHERE status = 'cancelled';
+UPDATE ai_runs SET status = 'failed' WHERE status = 'cancelled';
+
+ALTER TABLE ai_jobs
+    DROP COLUMN IF EXISTS cancelled_by,
+    DROP COLUMN IF EXISTS cancel_requested_at;
+
+-- Postgres cannot drop an enum value; 'cancelled' stays in job_status unused.
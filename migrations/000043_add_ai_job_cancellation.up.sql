@@ -0,0 +1,8 @@
+-- Cancelling a pending job takes effect at once. A running job is flagged
+-- and its worker, which polls the flag, stops the agent and marks it
+-- cancelled.
+ALTER TYPE job_status ADD VALUE IF NOT EXISTS 'cancelled';
+
+ALTER TABLE ai_jobs
+    ADD COLUMN cancel_requested_at TIMESTAMPTZ,
+    ADD COLUMN cancelled_by        BIGINT REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,10 @@
+DELETE FROM webhook_deliveries WHERE webhook_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook;
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_event_id_key UNIQUE (event_id);
+ALTER TABLE webhook_deliveries RENAME COLUMN body TO payload;
+ALTER TABLE webhook_deliveries ALTER COLUMN payload TYPE JSONB USING payload::jsonb;
+ALTER TABLE webhook_deliveries DROP COLUMN content_type, DROP COLUMN webhook_id;
+
+DROP TABLE IF EXISTS webhooks;
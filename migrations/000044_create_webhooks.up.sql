@@ -0,0 +1,29 @@
+-- Project webhooks send events for one project to their own endpoint.
+-- A webhook with a template renders each payload through it, so receivers
+-- get the body shape they expect.
+CREATE TABLE webhooks (
+    id           BIGSERIAL PRIMARY KEY,
+    project_id   BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    url          TEXT NOT NULL,
+    secret       TEXT NOT NULL,
+    template     TEXT,
+    content_type TEXT NOT NULL DEFAULT 'application/json',
+    active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by   BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_project ON webhooks (project_id);
+
+-- Deliveries keep the rendered body rather than the event payload. A NULL
+-- webhook_id is a delivery to the endpoint configured with WEBHOOK_URL.
+ALTER TABLE webhook_deliveries
+    ADD COLUMN webhook_id BIGINT REFERENCES webhooks(id) ON DELETE CASCADE,
+    ADD COLUMN content_type TEXT NOT NULL DEFAULT 'application/json';
+ALTER TABLE webhook_deliveries ALTER COLUMN payload TYPE TEXT USING payload::text;
+ALTER TABLE webhook_deliveries RENAME COLUMN payload TO body;
+ALTER TABLE webhook_deliveries DROP CONSTRAINT webhook_deliveries_event_id_key;
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (event_id, COALESCE(webhook_id, 0));
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id) WHERE webhook_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_comments_search;
+ALTER TABLE comments DROP COLUMN IF EXISTS search_vector;
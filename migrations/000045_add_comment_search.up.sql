@@ -0,0 +1,7 @@
+-- Comments are searchable alongside issue titles and bodies, so a search
+-- finds an issue by what was said in its discussion.
+ALTER TABLE comments ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', body)
+) STORED;
+
+CREATE INDEX idx_comments_search ON comments USING GIN (search_vector);
@@ -0,0 +1,2 @@
+ALTER TABLE webhooks DROP COLUMN IF EXISTS format;
+DROP TYPE IF EXISTS webhook_format;
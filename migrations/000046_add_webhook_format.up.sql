@@ -0,0 +1,5 @@
+-- Webhooks in the teams format are sent to a Microsoft Teams incoming
+-- webhook as Adaptive Cards rather than as JSON payloads.
+CREATE TYPE webhook_format AS ENUM ('json', 'teams');
+
+ALTER TABLE webhooks ADD COLUMN format webhook_format NOT NULL DEFAULT 'json';
@@ -0,0 +1,11 @@
+-- Slack users linked to accounts, so slash commands and buttons act as the
+-- account that sent them.
+CREATE TABLE slack_accounts (
+    team_id       TEXT NOT NULL,
+    slack_user_id TEXT NOT NULL,
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, slack_user_id)
+);
+
+CREATE INDEX idx_slack_accounts_user ON slack_accounts (user_id);
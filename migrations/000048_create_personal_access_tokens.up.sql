@@ -0,0 +1,13 @@
+-- Long-lived tokens for scripts and the CLI. Only a SHA-256 hash of each
+-- token is stored; the token itself is shown once when it is created.
+CREATE TABLE personal_access_tokens (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    token_hash   BYTEA NOT NULL UNIQUE,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_personal_access_tokens_user ON personal_access_tokens (user_id);
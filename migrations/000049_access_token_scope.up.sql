@@ -0,0 +1,4 @@
+-- A token's scope limits what it may do: read tokens may only read, write
+-- tokens act with their user's full access. Existing tokens keep full access.
+ALTER TABLE personal_access_tokens
+    ADD COLUMN scope TEXT NOT NULL DEFAULT 'write' CHECK (scope IN ('read', 'write'));
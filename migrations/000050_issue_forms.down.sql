@@ -0,0 +1,3 @@
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS form_data,
+    DROP COLUMN IF EXISTS template;
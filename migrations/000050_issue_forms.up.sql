@@ -0,0 +1,5 @@
+-- Issues created from an issue form keep the form's name and the answers as
+-- structured data, next to the Markdown body rendered from them.
+ALTER TABLE issues
+    ADD COLUMN template  TEXT,
+    ADD COLUMN form_data JSONB;
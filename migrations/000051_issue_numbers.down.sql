@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS issues_assign_number ON issues;
+DROP FUNCTION IF EXISTS assign_issue_number();
+DROP INDEX IF EXISTS idx_issues_project_number;
+ALTER TABLE issues DROP COLUMN IF EXISTS number;
+ALTER TABLE projects DROP COLUMN IF EXISTS next_issue_number;
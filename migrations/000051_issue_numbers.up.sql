@@ -0,0 +1,30 @@
+-- Issues are numbered per project, as in KEY-123. Existing issues keep their
+-- IDs as numbers, so references already written still resolve to the same
+-- issues; each project continues from its highest number. Numbers are
+-- assigned on insert from projects.next_issue_number, whose row lock
+-- serializes concurrent inserts into a project.
+ALTER TABLE projects ADD COLUMN next_issue_number BIGINT NOT NULL DEFAULT 1
+    CHECK (next_issue_number > 0);
+
+ALTER TABLE issues ADD COLUMN number BIGINT;
+UPDATE issues SET number = id;
+ALTER TABLE issues ALTER COLUMN number SET NOT NULL;
+CREATE UNIQUE INDEX idx_issues_project_number ON issues (project_id, number);
+
+UPDATE projects p
+SET next_issue_number = COALESCE((SELECT MAX(i.number) FROM issues i WHERE i.project_id = p.id), 0) + 1;
+
+CREATE FUNCTION assign_issue_number() RETURNS trigger AS $$
+BEGIN
+    IF NEW.number IS NULL THEN
+        UPDATE projects SET next_issue_number = next_issue_number + 1
+        WHERE id = NEW.project_id
+        RETURNING next_issue_number - 1 INTO NEW.number;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER issues_assign_number
+    BEFORE INSERT ON issues
+    FOR EACH ROW EXECUTE FUNCTION assign_issue_number();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS label_sync_projects;
+DROP TABLE IF EXISTS label_syncs;
+DROP TYPE IF EXISTS label_sync_status;
@@ -0,0 +1,29 @@
+-- A label sync pushes an organization's default labels to its projects in
+-- the background, one project at a time, recording what changed in each.
+CREATE TYPE label_sync_status AS ENUM ('running', 'completed');
+
+CREATE TABLE label_syncs (
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    labels          JSONB NOT NULL,
+    prune           BOOLEAN NOT NULL DEFAULT FALSE,
+    status          label_sync_status NOT NULL DEFAULT 'running',
+    total           INT NOT NULL,
+    synced          INT NOT NULL DEFAULT 0,
+    requested_by    BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_label_syncs_organization ON label_syncs (organization_id, id);
+CREATE INDEX idx_label_syncs_running ON label_syncs (id) WHERE status = 'running';
+
+-- The projects a sync targets. changes is set once the project is synced.
+CREATE TABLE label_sync_projects (
+    sync_id    BIGINT NOT NULL REFERENCES label_syncs(id) ON DELETE CASCADE,
+    project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    changes    JSONB,
+    synced_at  TIMESTAMPTZ,
+    PRIMARY KEY (sync_id, project_id)
+);
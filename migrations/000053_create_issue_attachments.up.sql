@@ -0,0 +1,12 @@
+CREATE TABLE issue_attachments (
+    id           BIGSERIAL PRIMARY KEY,
+    issue_id     BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    storage_key  TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size_bytes   BIGINT NOT NULL,
+    uploaded_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_issue_attachments_issue ON issue_attachments (issue_id, id);
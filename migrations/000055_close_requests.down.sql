@@ -0,0 +1,7 @@
+DELETE FROM notifications WHERE type IN ('close_requested', 'close_approved', 'close_rejected');
+
+DROP TABLE IF EXISTS close_requests;
+DROP TYPE IF EXISTS close_request_state;
+
+-- Postgres cannot drop an enum value; the close_* notification types stay
+-- in notification_type unused.
@@ -0,0 +1,24 @@
+-- Projects can require approval to close issues. Members without the
+-- approving role request the close, and an approver approves or rejects it.
+CREATE TYPE close_request_state AS ENUM ('pending', 'approved', 'rejected');
+
+CREATE TABLE close_requests (
+    id            BIGSERIAL PRIMARY KEY,
+    issue_id      BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    status        issue_status NOT NULL,
+    note          TEXT NOT NULL DEFAULT '',
+    requested_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    state         close_request_state NOT NULL DEFAULT 'pending',
+    decided_by    BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    decided_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- An issue has at most one pending close request.
+CREATE UNIQUE INDEX idx_close_requests_pending ON close_requests (issue_id) WHERE state = 'pending';
+CREATE INDEX idx_close_requests_issue ON close_requests (issue_id, id);
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'close_requested';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'close_approved';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'close_rejected';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS email_queue;
+
+ALTER TABLE notification_preferences
+    DROP COLUMN IF EXISTS email_issue_failed,
+    DROP COLUMN IF EXISTS email_issue_completed;
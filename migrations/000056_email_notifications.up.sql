@@ -0,0 +1,13 @@
+-- Users opt in to being emailed about completed issues and failed AI runs.
+ALTER TABLE notification_preferences
+    ADD COLUMN email_issue_completed BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN email_issue_failed    BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Notifications waiting to be emailed, held until deliver_at when they fall
+-- in the user's quiet hours.
+CREATE TABLE email_queue (
+    notification_id BIGINT PRIMARY KEY REFERENCES notifications(id) ON DELETE CASCADE,
+    deliver_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_email_queue_deliver_at ON email_queue (deliver_at);
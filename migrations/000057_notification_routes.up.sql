@@ -0,0 +1,16 @@
+-- Routing rules send a project's notifications that match their conditions,
+-- such as failed AI runs on issues labelled infra, to email addresses and
+-- Slack channels outside the project's members.
+CREATE TABLE notification_routes (
+    id         BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    match      JSONB NOT NULL DEFAULT '{}',
+    targets    JSONB NOT NULL DEFAULT '{}',
+    active     BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notification_routes_project ON notification_routes (project_id);
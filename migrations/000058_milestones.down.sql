@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_issues_milestone;
+ALTER TABLE issues DROP COLUMN IF EXISTS milestone_id;
+DROP TABLE IF EXISTS milestones;
@@ -0,0 +1,17 @@
+-- Milestones group a project's issues towards a target date. An issue is in
+-- at most one milestone; deleting a milestone leaves its issues unassigned.
+CREATE TABLE milestones (
+    id          BIGSERIAL PRIMARY KEY,
+    project_id  BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    due_date    DATE,
+    state       TEXT NOT NULL DEFAULT 'open' CHECK (state IN ('open', 'closed')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, title)
+);
+
+ALTER TABLE issues ADD COLUMN milestone_id BIGINT REFERENCES milestones(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_milestone ON issues (milestone_id) WHERE milestone_id IS NOT NULL;
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type = 'webhook_disabled';
+
+ALTER TABLE webhooks
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS failing_since,
+    DROP COLUMN IF EXISTS retry_policy;
+
+-- Postgres cannot drop an enum value; the webhook_disabled notification
+-- type stays in notification_type unused.
@@ -0,0 +1,10 @@
+-- Webhooks can override how their failed deliveries are retried. Keys left
+-- out of retry_policy use the installation's defaults. failing_since is
+-- when the endpoint's current run of failed attempts began; endpoints that
+-- keep failing are disabled at disabled_at.
+ALTER TABLE webhooks
+    ADD COLUMN retry_policy  JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN failing_since TIMESTAMPTZ,
+    ADD COLUMN disabled_at   TIMESTAMPTZ;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'webhook_disabled';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_issue_attachments_pending;
+
+ALTER TABLE issue_attachments
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scanned_by,
+    DROP COLUMN IF EXISTS scan_signature,
+    DROP COLUMN IF EXISTS scan_status;
@@ -0,0 +1,12 @@
+-- Attachments are scanned for malware when a scanner is configured. Pending
+-- attachments are quarantined until scanned; infected ones keep their
+-- record, with the signature found, but lose their contents. Attachments
+-- uploaded before scanning existed stay unscanned.
+ALTER TABLE issue_attachments
+    ADD COLUMN scan_status    TEXT NOT NULL DEFAULT 'unscanned'
+        CHECK (scan_status IN ('unscanned', 'pending', 'clean', 'infected')),
+    ADD COLUMN scan_signature TEXT,
+    ADD COLUMN scanned_by     TEXT,
+    ADD COLUMN scanned_at     TIMESTAMPTZ;
+
+CREATE INDEX idx_issue_attachments_pending ON issue_attachments (id) WHERE scan_status = 'pending';
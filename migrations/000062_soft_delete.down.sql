@@ -0,0 +1,15 @@
+-- Trashed rows would reappear once the columns are gone, so they are
+-- removed for good first.
+DELETE FROM issues WHERE deleted_at IS NOT NULL;
+DELETE FROM projects WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_issues_deleted;
+DROP INDEX IF EXISTS idx_projects_deleted;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,13 @@
+-- Deleting an issue or project moves it to the trash instead of removing
+-- it. Trashed rows are hidden everywhere, can be restored by the people who
+-- could delete them, and are purged once the retention period has passed.
+ALTER TABLE projects
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE issues
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_projects_deleted ON projects (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_issues_deleted ON issues (project_id, deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Attachments sharing a blob keep sharing its object, so storage keys are
+-- no longer made unique again.
+DROP TRIGGER IF EXISTS issue_attachments_count_blob_refs ON issue_attachments;
+DROP FUNCTION IF EXISTS count_attachment_blob_refs();
+
+DROP INDEX IF EXISTS idx_issue_attachments_blob;
+ALTER TABLE issue_attachments DROP COLUMN IF EXISTS blob_sha256;
+
+DROP TABLE IF EXISTS attachment_blobs;
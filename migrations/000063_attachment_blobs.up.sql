@@ -0,0 +1,43 @@
+-- Attachment contents are stored once per distinct file, keyed by their
+-- SHA-256, and shared by every attachment with the same contents. Triggers
+-- keep each blob's reference count, including when attachments go with
+-- their issue; blobs left unreferenced are deleted by garbage collection.
+-- Attachments uploaded before deduplication keep their own object and no
+-- blob.
+CREATE TABLE attachment_blobs (
+    sha256          TEXT PRIMARY KEY,
+    storage_key     TEXT NOT NULL UNIQUE,
+    size_bytes      BIGINT NOT NULL,
+    ref_count       INT NOT NULL DEFAULT 0 CHECK (ref_count >= 0),
+    unreferenced_at TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachment_blobs_unreferenced ON attachment_blobs (unreferenced_at)
+    WHERE ref_count = 0;
+
+ALTER TABLE issue_attachments
+    DROP CONSTRAINT issue_attachments_storage_key_key,
+    ADD COLUMN blob_sha256 TEXT REFERENCES attachment_blobs(sha256) ON DELETE SET NULL;
+
+CREATE INDEX idx_issue_attachments_blob ON issue_attachments (blob_sha256)
+    WHERE blob_sha256 IS NOT NULL;
+
+CREATE FUNCTION count_attachment_blob_refs() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE attachment_blobs SET ref_count = ref_count + 1, unreferenced_at = NULL
+        WHERE sha256 = NEW.blob_sha256;
+    ELSE
+        UPDATE attachment_blobs
+        SET ref_count = ref_count - 1,
+            unreferenced_at = CASE WHEN ref_count = 1 THEN NOW() END
+        WHERE sha256 = OLD.blob_sha256;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER issue_attachments_count_blob_refs
+    AFTER INSERT OR DELETE ON issue_attachments
+    FOR EACH ROW EXECUTE FUNCTION count_attachment_blob_refs();
@@ -0,0 +1 @@
+ALTER TABLE attachment_blobs DROP COLUMN IF EXISTS variants;
@@ -0,0 +1,4 @@
+-- Image attachments get smaller variants for the web, made once per blob
+-- and stored next to it under its storage key with the variant name
+-- appended.
+ALTER TABLE attachment_blobs ADD COLUMN variants JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS wiki_links;
+DROP TABLE IF EXISTS wiki_revisions;
+DROP TABLE IF EXISTS wiki_pages;
@@ -0,0 +1,40 @@
+-- Each project has a wiki of Markdown pages addressed by slug. A page keeps
+-- its current content; every edit is also kept as a numbered revision.
+CREATE TABLE wiki_pages (
+    id         BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    slug       TEXT NOT NULL CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$' AND length(slug) <= 100),
+    title      TEXT NOT NULL,
+    body       TEXT NOT NULL DEFAULT '',
+    revision   INTEGER NOT NULL DEFAULT 1,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, slug)
+);
+
+CREATE TABLE wiki_revisions (
+    page_id    BIGINT NOT NULL REFERENCES wiki_pages(id) ON DELETE CASCADE,
+    revision   INTEGER NOT NULL,
+    title      TEXT NOT NULL,
+    body       TEXT NOT NULL,
+    message    TEXT NOT NULL DEFAULT '',
+    author_id  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (page_id, revision)
+);
+
+-- Links such as [[release-process]] from an issue's title and body, or from
+-- one of its comments, to a page of the issue's project. They are kept by
+-- slug so that linking a page before it is written works.
+CREATE TABLE wiki_links (
+    id         BIGSERIAL PRIMARY KEY,
+    issue_id   BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    comment_id BIGINT REFERENCES comments(id) ON DELETE CASCADE,
+    slug       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_wiki_links_source ON wiki_links (issue_id, COALESCE(comment_id, 0), slug);
+CREATE INDEX idx_wiki_links_slug ON wiki_links (slug);
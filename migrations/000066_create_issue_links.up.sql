@@ -0,0 +1,17 @@
+-- Typed relationships between issues of a project. The source blocks, or is
+-- the parent of, the target; relates links are stored once, from the issue
+-- with the lower ID.
+CREATE TABLE issue_links (
+    id              BIGSERIAL PRIMARY KEY,
+    source_issue_id BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    target_issue_id BIGINT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    type            TEXT NOT NULL CHECK (type IN ('blocks', 'relates', 'parent')),
+    created_by      BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (source_issue_id <> target_issue_id),
+    UNIQUE (source_issue_id, target_issue_id, type)
+);
+
+-- An issue has at most one parent.
+CREATE UNIQUE INDEX idx_issue_links_parent ON issue_links (target_issue_id) WHERE type = 'parent';
+CREATE INDEX idx_issue_links_target ON issue_links (target_issue_id);
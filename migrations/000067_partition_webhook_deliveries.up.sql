@@ -0,0 +1,49 @@
+-- webhook_deliveries is partitioned by month on created_at like audit_logs
+-- and issue_events, so old deliveries are dropped a partition at a time.
+-- Unique indexes of a partitioned table must contain created_at, so the
+-- one-delivery-per-event rule is kept by the enqueue query instead, which
+-- only the webhook dispatcher singleton runs.
+ALTER TABLE webhook_deliveries RENAME TO webhook_deliveries_legacy;
+ALTER TABLE webhook_deliveries_legacy RENAME CONSTRAINT webhook_deliveries_pkey TO webhook_deliveries_legacy_pkey;
+ALTER INDEX idx_webhook_deliveries_due RENAME TO idx_webhook_deliveries_legacy_due;
+ALTER INDEX idx_webhook_deliveries_project RENAME TO idx_webhook_deliveries_legacy_project;
+ALTER INDEX idx_webhook_deliveries_event RENAME TO idx_webhook_deliveries_legacy_event;
+ALTER INDEX idx_webhook_deliveries_webhook RENAME TO idx_webhook_deliveries_legacy_webhook;
+
+CREATE TABLE webhook_deliveries (
+    id               BIGINT NOT NULL DEFAULT nextval('webhook_deliveries_id_seq'),
+    event_id         BIGINT NOT NULL,
+    event_type       TEXT NOT NULL,
+    project_id       BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    webhook_id       BIGINT REFERENCES webhooks(id) ON DELETE CASCADE,
+    url              TEXT NOT NULL,
+    body             TEXT NOT NULL,
+    content_type     TEXT NOT NULL DEFAULT 'application/json',
+    status           webhook_delivery_status NOT NULL DEFAULT 'pending',
+    attempts         INT NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ DEFAULT NOW(),
+    last_status_code INT,
+    last_error       TEXT,
+    delivered_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE webhook_deliveries_id_seq OWNED BY webhook_deliveries.id;
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_project ON webhook_deliveries (project_id, id);
+CREATE INDEX idx_webhook_deliveries_event ON webhook_deliveries (event_id);
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id) WHERE webhook_id IS NOT NULL;
+CREATE TABLE webhook_deliveries_default PARTITION OF webhook_deliveries DEFAULT;
+SELECT create_monthly_partition('webhook_deliveries', CURRENT_DATE);
+SELECT create_monthly_partition('webhook_deliveries', (CURRENT_DATE + INTERVAL '1 month')::date);
+
+INSERT INTO webhook_deliveries (id, event_id, event_type, project_id, webhook_id, url, body, content_type,
+                                status, attempts, next_attempt_at, last_status_code, last_error,
+                                delivered_at, created_at, updated_at)
+SELECT id, event_id, event_type, project_id, webhook_id, url, body, content_type,
+       status, attempts, next_attempt_at, last_status_code, last_error,
+       delivered_at, created_at, updated_at
+FROM webhook_deliveries_legacy;
+DROP TABLE webhook_deliveries_legacy;
@@ -0,0 +1,12 @@
+-- Refresh tokens are recorded by the ID in their jti claim. Refreshing
+-- consumes the token and records its replacement, so each can be used once
+-- and a token that is not recorded is refused. Expired rows are purged by
+-- the maintenance scheduler.
+CREATE TABLE refresh_tokens (
+    id         TEXT PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_expires ON refresh_tokens (expires_at);
@@ -0,0 +1,31 @@
+DROP INDEX idx_webhook_deliveries_project;
+DROP INDEX idx_webhook_deliveries_webhook;
+CREATE INDEX idx_webhook_deliveries_project ON webhook_deliveries (project_id, id);
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id) WHERE webhook_id IS NOT NULL;
+
+DROP INDEX idx_ai_runs_issue;
+DROP INDEX idx_ai_review_comments_issue;
+CREATE INDEX idx_ai_runs_issue ON ai_runs (issue_id, id DESC);
+CREATE INDEX idx_ai_review_comments_issue ON ai_review_comments (issue_id, id DESC);
+
+DROP INDEX idx_audit_logs_project_created;
+CREATE INDEX idx_audit_logs_project_created ON audit_logs (project_id, created_at DESC);
+
+DROP INDEX idx_issue_events_actor;
+CREATE INDEX idx_issue_events_actor ON issue_events (actor_id, id DESC);
+
+DROP INDEX idx_notifications_user;
+CREATE INDEX idx_notifications_user ON notifications (user_id, id DESC);
+
+DROP INDEX idx_content_reports_queue;
+CREATE INDEX idx_content_reports_queue ON content_reports (project_id, status, id);
+
+DROP INDEX idx_comments_issue_id;
+CREATE INDEX idx_comments_issue_id ON comments (issue_id, id);
+
+DROP INDEX idx_issues_active;
+DROP INDEX idx_issues_archived;
+DROP INDEX idx_issues_deleted;
+CREATE INDEX idx_issues_active ON issues (project_id, id) WHERE archived_at IS NULL;
+CREATE INDEX idx_issues_archived ON issues (project_id, id) WHERE archived_at IS NOT NULL;
+CREATE INDEX idx_issues_deleted ON issues (project_id, deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,34 @@
+-- List endpoints page by the time and ID of the last item returned, so
+-- their indexes order by time, then ID, after the columns a list is
+-- scoped to.
+DROP INDEX idx_issues_active;
+DROP INDEX idx_issues_archived;
+DROP INDEX idx_issues_deleted;
+CREATE INDEX idx_issues_active ON issues (project_id, created_at DESC, id DESC) WHERE archived_at IS NULL;
+CREATE INDEX idx_issues_archived ON issues (project_id, created_at DESC, id DESC) WHERE archived_at IS NOT NULL;
+CREATE INDEX idx_issues_deleted ON issues (project_id, deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_comments_issue_id;
+CREATE INDEX idx_comments_issue_id ON comments (issue_id, created_at, id);
+
+DROP INDEX idx_content_reports_queue;
+CREATE INDEX idx_content_reports_queue ON content_reports (project_id, status, created_at, id);
+
+DROP INDEX idx_notifications_user;
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC, id DESC);
+
+DROP INDEX idx_issue_events_actor;
+CREATE INDEX idx_issue_events_actor ON issue_events (actor_id, created_at DESC, id DESC);
+
+DROP INDEX idx_audit_logs_project_created;
+CREATE INDEX idx_audit_logs_project_created ON audit_logs (project_id, created_at DESC, id DESC);
+
+DROP INDEX idx_ai_runs_issue;
+DROP INDEX idx_ai_review_comments_issue;
+CREATE INDEX idx_ai_runs_issue ON ai_runs (issue_id, started_at DESC, id DESC);
+CREATE INDEX idx_ai_review_comments_issue ON ai_review_comments (issue_id, created_at DESC, id DESC);
+
+DROP INDEX idx_webhook_deliveries_project;
+DROP INDEX idx_webhook_deliveries_webhook;
+CREATE INDEX idx_webhook_deliveries_project ON webhook_deliveries (project_id, created_at DESC, id DESC);
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC, id DESC) WHERE webhook_id IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE worker_cursors DROP COLUMN xact_id;
+
+DROP INDEX idx_notifications_xact;
+ALTER TABLE notifications DROP COLUMN xact_id;
+
+DROP INDEX idx_issue_events_xact;
+ALTER TABLE issue_events DROP COLUMN xact_id;
@@ -0,0 +1,14 @@
+-- Workers that tail issue_events and notifications read them in commit
+-- order: by the transaction that inserted a row, then by ID. IDs are handed
+-- out on insert, so a row with a lower ID can commit after a higher one has
+-- been read. Rows written before this migration share transaction 0 and
+-- keep their ID order, so the cursors saved so far stay valid.
+ALTER TABLE issue_events ADD COLUMN xact_id xid8 NOT NULL DEFAULT '0';
+ALTER TABLE issue_events ALTER COLUMN xact_id SET DEFAULT pg_current_xact_id();
+CREATE INDEX idx_issue_events_xact ON issue_events (xact_id, id);
+
+ALTER TABLE notifications ADD COLUMN xact_id xid8 NOT NULL DEFAULT '0';
+ALTER TABLE notifications ALTER COLUMN xact_id SET DEFAULT pg_current_xact_id();
+CREATE INDEX idx_notifications_xact ON notifications (xact_id, id);
+
+ALTER TABLE worker_cursors ADD COLUMN xact_id BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+DROP FUNCTION prune_default_partition(TEXT, TIMESTAMPTZ);
+
+CREATE OR REPLACE FUNCTION create_monthly_partition(parent TEXT, month DATE) RETURNS VOID AS $$
+DECLARE
+    start_at DATE := date_trunc('month', month);
+    name     TEXT := format('%s_y%sm%s', parent, to_char(start_at, 'YYYY'), to_char(start_at, 'MM'));
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        name, parent, start_at, start_at + INTERVAL '1 month');
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION default_partition(TEXT);
@@ -0,0 +1,73 @@
+-- Rows whose month had no partition yet land in a table's default
+-- partition. create_monthly_partition now moves such rows into the month's
+-- partition as it creates it, since Postgres refuses to create a partition
+-- whose range the default partition already holds rows for, and
+-- prune_default_partition lets retention delete old rows that are still
+-- there.
+
+-- default_partition returns the default partition of parent and the column
+-- it is partitioned on, or NULLs if it has no default partition.
+CREATE FUNCTION default_partition(parent TEXT, OUT child REGCLASS, OUT key TEXT) AS $$
+    SELECT c.oid::regclass, a.attname::text
+    FROM pg_inherits i
+    JOIN pg_class c ON c.oid = i.inhrelid
+    JOIN pg_partitioned_table pt ON pt.partrelid = i.inhparent
+    JOIN pg_attribute a ON a.attrelid = pt.partrelid AND a.attnum = pt.partattrs[0]
+    WHERE i.inhparent = parent::regclass
+      AND pg_get_expr(c.relpartbound, c.oid) = 'DEFAULT';
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION create_monthly_partition(parent TEXT, month DATE) RETURNS VOID AS $$
+DECLARE
+    start_at DATE := date_trunc('month', month);
+    end_at   DATE := date_trunc('month', month) + INTERVAL '1 month';
+    name     TEXT := format('%s_y%sm%s', parent, to_char(start_at, 'YYYY'), to_char(start_at, 'MM'));
+    def      RECORD;
+    stranded BOOLEAN;
+BEGIN
+    IF to_regclass(quote_ident(name)) IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    SELECT * INTO def FROM default_partition(parent);
+    IF def.child IS NOT NULL THEN
+        EXECUTE format('SELECT EXISTS (SELECT 1 FROM %s WHERE %I >= %L AND %I < %L)',
+            def.child, def.key, start_at, def.key, end_at) INTO stranded;
+    END IF;
+
+    IF NOT coalesce(stranded, false) THEN
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+            name, parent, start_at, end_at);
+        RETURN;
+    END IF;
+
+    -- Detaching the default partition locks parent until the transaction
+    -- ends, so no row can slip into the default partition meanwhile.
+    EXECUTE format('ALTER TABLE %I DETACH PARTITION %s', parent, def.child);
+    EXECUTE format(
+        'CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        name, parent, start_at, end_at);
+    EXECUTE format(
+        'WITH moved AS (DELETE FROM %s WHERE %I >= %L AND %I < %L RETURNING *) INSERT INTO %I SELECT * FROM moved',
+        def.child, def.key, start_at, def.key, end_at, name);
+    EXECUTE format('ALTER TABLE %I ATTACH PARTITION %s DEFAULT', parent, def.child);
+END;
+$$ LANGUAGE plpgsql;
+
+-- prune_default_partition deletes the rows of parent's default partition
+-- from before cutoff and returns how many it deleted.
+CREATE FUNCTION prune_default_partition(parent TEXT, cutoff TIMESTAMPTZ) RETURNS BIGINT AS $$
+DECLARE
+    def     RECORD;
+    deleted BIGINT;
+BEGIN
+    SELECT * INTO def FROM default_partition(parent);
+    IF def.child IS NULL THEN
+        RETURN 0;
+    END IF;
+    EXECUTE format('DELETE FROM %s WHERE %I < %L', def.child, def.key, cutoff);
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+    RETURN deleted;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,236 @@
+// Package apiclient is a Go client for the issues HTTP API, together with
+// the request and response types the server and its clients share, so other
+// Go services can integrate without hand-rolling HTTP calls.
+//
+//	client := apiclient.NewClient("https://issues.example.com", token, apiclient.WithRetries(3))
+//	for issue, err := range client.Issues(ctx, projectID, apiclient.IssueListOptions{Statuses: []string{"open"}}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(issue.ID, issue.Title)
+//	}
+//
+// Resource types mirror the JSON the server returns with its default
+// serialization profile (snake_case keys and RFC 3339 times). Fields are
+// only ever added to them within an APIVersion.
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIVersion is the version of the HTTP API the client speaks; requests go
+// to /api/{APIVersion}.
+const APIVersion = "v1"
+
+// Version is the version of this package, sent in the User-Agent header.
+const Version = "1.0.0"
+
+// Response is the envelope every JSON response is wrapped in.
+type Response[T any] struct {
+	Data  T      `json:"data"`
+	Meta  *Meta  `json:"meta,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// Meta holds cursor-based pagination info. Cursors are opaque to clients:
+// pass NextCursor back as the cursor parameter to get the next page.
+type Meta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+}
+
+// Error is an error response. Status is the HTTP status it came with and is
+// only set by the client.
+type Error struct {
+	Status  int          `json:"-"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError represents a field-level validation error.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	for _, d := range e.Details {
+		msg += fmt.Sprintf("; %s: %s", d.Field, d.Message)
+	}
+	if e.Status != 0 {
+		return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), msg)
+	}
+	return msg
+}
+
+// CreateIssueRequest is the request body for creating an issue. To fill in
+// an issue form, name its template and give the answers in Form by field
+// ID: strings, or lists of options for checkboxes. The body is then
+// rendered from the answers, with Body appended.
+type CreateIssueRequest struct {
+	Title      string         `json:"title" validate:"required,max=500"`
+	Body       *string        `json:"body,omitempty" validate:"omitempty,max=65536"`
+	Status     string         `json:"status,omitempty"`
+	AssigneeID *int64         `json:"assignee_id,omitempty" validate:"omitempty,gt=0"`
+	Template   string         `json:"template,omitempty" validate:"max=200"`
+	Form       map[string]any `json:"form,omitempty"`
+}
+
+// RunAIRequest is the request body for running AI on an issue.
+type RunAIRequest struct {
+	TimeoutSeconds *int   `json:"timeout_seconds,omitempty" validate:"omitempty,gt=0"`
+	Instructions   string `json:"instructions,omitempty" validate:"max=4000"`
+	Resume         bool   `json:"resume,omitempty"`
+}
+
+// CreateAccessTokenRequest is the request body for creating a personal
+// access token. Scope is "read", for tokens that may only make GET
+// requests, or "write", the default. A token without ExpiresAt does not
+// expire.
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scope     string     `json:"scope,omitempty" validate:"omitempty,oneof=read write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// UpdateIssueRequest is the request body for partially updating an issue.
+// Nil fields are left unchanged.
+type UpdateIssueRequest struct {
+	Title      *string `json:"title,omitempty" validate:"omitempty,min=1,max=500"`
+	Body       *string `json:"body,omitempty" validate:"omitempty,max=65536"`
+	Status     *string `json:"status,omitempty"`
+	AssigneeID *int64  `json:"assignee_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// UpdateIssueStatusRequest is the request body for moving an issue to
+// another status. Open issues move to in_progress or closed, in_progress
+// issues to open, completed or closed, and completed or closed issues only
+// back to open.
+type UpdateIssueStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=open in_progress completed closed"`
+}
+
+// CreateCommentRequest is the request body for creating a comment.
+type CreateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=65536"`
+}
+
+// User is an account.
+type User struct {
+	ID          int64     `json:"id"`
+	Provider    string    `json:"provider"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   *string   `json:"avatar_url,omitempty"`
+	IsAdmin     bool      `json:"is_admin"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Project is a project. Settings is left encoded, as its shape grows with
+// the server's features.
+type Project struct {
+	ID              int64           `json:"id"`
+	Name            string          `json:"name"`
+	Key             *string         `json:"key,omitempty"`
+	Description     *string         `json:"description,omitempty"`
+	OwnerID         int64           `json:"owner_id"`
+	OrganizationID  *int64          `json:"organization_id,omitempty"`
+	Settings        json.RawMessage `json:"settings,omitempty"`
+	NextIssueNumber int64           `json:"next_issue_number"`
+	AIPausedAt      *time.Time      `json:"ai_paused_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// ProjectSummary is a project in a listing, with the caller's role in it.
+type ProjectSummary struct {
+	Project
+	Role           string `json:"role"`
+	OpenIssueCount int    `json:"open_issue_count"`
+}
+
+// Issue is an issue in a project. Number identifies it within the project,
+// after the project key; ID identifies it in API paths.
+type Issue struct {
+	ID          int64          `json:"id"`
+	ProjectID   int64          `json:"project_id"`
+	Number      int64          `json:"number"`
+	Title       string         `json:"title"`
+	Body        *string        `json:"body,omitempty"`
+	Status      string         `json:"status"`
+	CreatedBy   *int64         `json:"created_by,omitempty"`
+	AssigneeID  *int64         `json:"assignee_id,omitempty"`
+	MilestoneID *int64         `json:"milestone_id,omitempty"`
+	AIResult    *string        `json:"ai_result,omitempty"`
+	PinnedAt    *time.Time     `json:"pinned_at,omitempty"`
+	ClosedAt    *time.Time     `json:"closed_at,omitempty"`
+	ArchivedAt  *time.Time     `json:"archived_at,omitempty"`
+	Template    *string        `json:"template,omitempty"`
+	Form        map[string]any `json:"form,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Comment is a comment on an issue. Deleted comments keep only a
+// Tombstone.
+type Comment struct {
+	ID        int64      `json:"id"`
+	IssueID   int64      `json:"issue_id"`
+	AuthorID  int64      `json:"author_id"`
+	Body      string     `json:"body"`
+	Hidden    bool       `json:"hidden"`
+	Tombstone *string    `json:"tombstone,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// AIJob is a queued or finished AI job on an issue.
+type AIJob struct {
+	ID          int64      `json:"id"`
+	IssueID     int64      `json:"issue_id"`
+	ProjectID   int64      `json:"project_id"`
+	Mode        string     `json:"mode"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ErrorMsg    *string    `json:"error_msg,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j AIJob) Done() bool {
+	return j.Status == "completed" || j.Status == "failed" || j.Status == "cancelled"
+}
+
+// AIJobLog is a chunk of output from an AI job.
+type AIJobLog struct {
+	ID        int64     `json:"id"`
+	JobID     int64     `json:"job_id"`
+	RunID     int64     `json:"run_id"`
+	Stream    string    `json:"stream"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccessToken is a personal access token. Token is only set in the
+// response that created it.
+type AccessToken struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	Token      string     `json:"token,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,431 @@
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody bounds how much of a non-JSON error response is kept.
+const maxErrorBody = 1 << 10
+
+const (
+	// retryBase is the delay before the first retry, doubled for each one
+	// after it.
+	retryBase = 500 * time.Millisecond
+	// maxRetryDelay caps the delay between retries, including one asked for
+	// with Retry-After.
+	maxRetryDelay = 30 * time.Second
+)
+
+// Client calls the API as the user a token belongs to. It is safe for
+// concurrent use.
+type Client struct {
+	baseURL   string
+	token     string
+	userAgent string
+	retries   int
+	http      *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with. It should not
+// have a timeout shorter than the log streams it is used to follow.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.http = c
+	}
+}
+
+// WithRetries retries a request up to n times when it is rate limited, when
+// the server is briefly unavailable, or, for requests that are safe to
+// repeat, when the connection fails. Retries back off exponentially and
+// honor Retry-After. The default is no retries.
+func WithRetries(n int) Option {
+	return func(cl *Client) {
+		cl.retries = n
+	}
+}
+
+// WithUserAgent sets the User-Agent requests are sent with, so the server's
+// logs can tell integrations apart.
+func WithUserAgent(ua string) Option {
+	return func(cl *Client) {
+		cl.userAgent = ua
+	}
+}
+
+// NewClient creates a client for the server at baseURL, such as
+// https://issues.example.com, authenticating with token. token is normally a
+// personal access token, created with POST /api/v1/me/tokens; session access
+// tokens work too but expire within minutes.
+func NewClient(baseURL, token string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:   strings.TrimRight(baseURL, "/") + "/api/" + APIVersion,
+		token:     token,
+		userAgent: "issues-go/" + Version,
+		http:      http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Me returns the user the client's token belongs to.
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var resp Response[User]
+	if err := c.do(ctx, http.MethodGet, "/auth/me", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// ProjectListOptions narrows ListProjects. Zero-valued fields are not
+// applied.
+type ProjectListOptions struct {
+	Query  string
+	Cursor string
+	Limit  int
+}
+
+// ListProjects returns a page of the projects the user owns or is a member
+// of.
+func (c *Client) ListProjects(ctx context.Context, opts ProjectListOptions) ([]ProjectSummary, *Meta, error) {
+	q := url.Values{}
+	if opts.Query != "" {
+		q.Set("q", opts.Query)
+	}
+	setPage(q, opts.Cursor, opts.Limit)
+
+	var resp Response[[]ProjectSummary]
+	if err := c.do(ctx, http.MethodGet, "/projects?"+q.Encode(), nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Data, resp.Meta, nil
+}
+
+// Projects iterates over every project ListProjects would return, fetching
+// pages as needed. opts.Cursor sets where it starts. The iteration stops
+// after yielding an error.
+func (c *Client) Projects(ctx context.Context, opts ProjectListOptions) iter.Seq2[ProjectSummary, error] {
+	return paginate(opts.Cursor, func(cursor string) ([]ProjectSummary, *Meta, error) {
+		opts.Cursor = cursor
+		return c.ListProjects(ctx, opts)
+	})
+}
+
+// GetProject returns a project.
+func (c *Client) GetProject(ctx context.Context, projectID int64) (*Project, error) {
+	var resp Response[Project]
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d", projectID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// IssueListOptions narrows ListIssues. Zero-valued fields are not applied.
+// Issues must have all Labels, or one of them when LabelMode is "any".
+type IssueListOptions struct {
+	Statuses   []string
+	Labels     []string
+	LabelMode  string
+	AssigneeID int64
+	Cursor     string
+	Limit      int
+}
+
+// ListIssues returns a page of issues in a project, pinned issues first.
+func (c *Client) ListIssues(ctx context.Context, projectID int64, opts IssueListOptions) ([]Issue, *Meta, error) {
+	q := url.Values{}
+	for _, s := range opts.Statuses {
+		q.Add("status", s)
+	}
+	if len(opts.Labels) > 0 {
+		q.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	if opts.LabelMode != "" {
+		q.Set("label_mode", opts.LabelMode)
+	}
+	if opts.AssigneeID != 0 {
+		q.Set("assignee", strconv.FormatInt(opts.AssigneeID, 10))
+	}
+	setPage(q, opts.Cursor, opts.Limit)
+
+	var resp Response[[]Issue]
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/issues?%s", projectID, q.Encode()), nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Data, resp.Meta, nil
+}
+
+// Issues iterates over every issue ListIssues would return, fetching pages
+// as needed. opts.Cursor sets where it starts. The iteration stops after
+// yielding an error.
+func (c *Client) Issues(ctx context.Context, projectID int64, opts IssueListOptions) iter.Seq2[Issue, error] {
+	return paginate(opts.Cursor, func(cursor string) ([]Issue, *Meta, error) {
+		opts.Cursor = cursor
+		return c.ListIssues(ctx, projectID, opts)
+	})
+}
+
+// SearchIssues returns a page of the issues in a project matching query,
+// best match first.
+func (c *Client) SearchIssues(ctx context.Context, projectID int64, query, cursor string, limit int) ([]Issue, *Meta, error) {
+	q := url.Values{"q": {query}}
+	setPage(q, cursor, limit)
+
+	var resp Response[[]Issue]
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/issues/search?%s", projectID, q.Encode()), nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Data, resp.Meta, nil
+}
+
+// GetIssue returns an issue.
+func (c *Client) GetIssue(ctx context.Context, projectID, issueID int64) (*Issue, error) {
+	var resp Response[Issue]
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/issues/%d", projectID, issueID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// CreateIssue creates an issue in a project.
+func (c *Client) CreateIssue(ctx context.Context, projectID int64, req CreateIssueRequest) (*Issue, error) {
+	var resp Response[Issue]
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/issues", projectID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// UpdateIssue partially updates an issue.
+func (c *Client) UpdateIssue(ctx context.Context, projectID, issueID int64, req UpdateIssueRequest) (*Issue, error) {
+	var resp Response[Issue]
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/projects/%d/issues/%d", projectID, issueID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// SetIssueStatus moves an issue to status. The server rejects transitions
+// its status machine does not allow with a validation error.
+func (c *Client) SetIssueStatus(ctx context.Context, projectID, issueID int64, status string) (*Issue, error) {
+	var resp Response[Issue]
+	path := fmt.Sprintf("/projects/%d/issues/%d/status", projectID, issueID)
+	if err := c.do(ctx, http.MethodPatch, path, UpdateIssueStatusRequest{Status: status}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// ListComments returns a page of the comments on an issue, oldest first.
+func (c *Client) ListComments(ctx context.Context, projectID, issueID int64, cursor string, limit int) ([]Comment, *Meta, error) {
+	q := url.Values{}
+	setPage(q, cursor, limit)
+
+	var resp Response[[]Comment]
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/issues/%d/comments?%s", projectID, issueID, q.Encode()), nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Data, resp.Meta, nil
+}
+
+// Comments iterates over every comment on an issue, oldest first. The
+// iteration stops after yielding an error.
+func (c *Client) Comments(ctx context.Context, projectID, issueID int64) iter.Seq2[Comment, error] {
+	return paginate("", func(cursor string) ([]Comment, *Meta, error) {
+		return c.ListComments(ctx, projectID, issueID, cursor, 0)
+	})
+}
+
+// CreateComment comments on an issue.
+func (c *Client) CreateComment(ctx context.Context, projectID, issueID int64, req CreateCommentRequest) (*Comment, error) {
+	var resp Response[Comment]
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/issues/%d/comments", projectID, issueID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// RunAI queues an AI job for an issue.
+func (c *Client) RunAI(ctx context.Context, projectID, issueID int64, req RunAIRequest) (*AIJob, error) {
+	var resp Response[AIJob]
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/issues/%d/ai/run", projectID, issueID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// paginate iterates over the items of the pages fetch returns, starting at
+// cursor and following next cursors until the last page.
+func paginate[T any](cursor string, fetch func(cursor string) ([]T, *Meta, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, meta, err := fetch(cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if meta == nil || !meta.HasNext || meta.NextCursor == "" {
+				return
+			}
+			cursor = meta.NextCursor
+		}
+	}
+}
+
+func setPage(q url.Values, cursor string, limit int) {
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	if limit != 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+// do sends a request with body encoded as JSON, if it is not nil, and
+// decodes the response into out, retrying as configured. Error responses
+// are returned as *Error.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		payload = b
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, payload)
+		if err == nil && resp.StatusCode < 300 {
+			err = json.NewDecoder(resp.Body).Decode(out)
+			resp.Body.Close()
+			if err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+			return nil
+		}
+
+		var retryAfter string
+		if err == nil {
+			retryAfter = resp.Header.Get("Retry-After")
+			err = responseError(resp)
+			resp.Body.Close()
+		}
+		if attempt >= c.retries || !retryable(method, err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryDelay(attempt, retryAfter)):
+		}
+	}
+}
+
+// send sends one attempt of a request.
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return resp, nil
+}
+
+// retryable reports whether a request that failed with err may be sent
+// again. Rate-limited requests were never handled, so any method is
+// retried; after other failures the request may have taken effect, so only
+// idempotent methods are.
+func retryable(method string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusTooManyRequests {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// retryDelay is how long to wait before retry number attempt+1: the
+// server's Retry-After if it sent one, or else an exponential backoff with
+// jitter.
+func retryDelay(attempt int, retryAfter string) time.Duration {
+	if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+		return min(time.Duration(secs)*time.Second, maxRetryDelay)
+	}
+	d := min(retryBase<<attempt, maxRetryDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("User-Agent", c.userAgent)
+	return req, nil
+}
+
+// responseError reads the error of a failed response. Responses that are
+// not the API's error envelope, such as those of a proxy, become an Error
+// holding the start of the body.
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*maxErrorBody))
+	var env Response[json.RawMessage]
+	if err := json.Unmarshal(body, &env); err != nil || env.Error == nil {
+		if len(body) > maxErrorBody {
+			body = body[:maxErrorBody]
+		}
+		return &Error{Status: resp.StatusCode, Code: "http_error", Message: string(bytes.TrimSpace(body))}
+	}
+	env.Error.Status = resp.StatusCode
+	return env.Error
+}
@@ -0,0 +1,150 @@
+package apiclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reconnectDelay is how long FollowLogs waits before resuming a dropped
+// stream.
+const reconnectDelay = 2 * time.Second
+
+// errStreamEnded is returned by readStream when the server closed the stream
+// without an end event.
+var errStreamEnded = errors.New("log stream ended")
+
+// FollowLogs calls fn with the output of an issue's latest AI job, or of
+// jobID if it is not zero, as the job produces it, and returns the job once
+// it has finished. A dropped stream is resumed after the last chunk
+// received. It stops with fn's error if fn fails.
+func (c *Client) FollowLogs(ctx context.Context, projectID, issueID, jobID int64, fn func(AIJobLog) error) (*AIJob, error) {
+	path := fmt.Sprintf("/projects/%d/issues/%d/ai/logs/stream", projectID, issueID)
+	if jobID != 0 {
+		path += "?job_id=" + strconv.FormatInt(jobID, 10)
+	}
+
+	var lastID int64
+	for {
+		job, err := c.streamLogs(ctx, path, &lastID, fn)
+		if job != nil || err == nil {
+			return job, err
+		}
+		// Only a stream that ends or breaks midway is resumed; errors from
+		// the server, from fn and in the stream's content are returned.
+		var stop stopError
+		if errors.As(err, &stop) {
+			return nil, stop.err
+		}
+		var apiErr *Error
+		if errors.As(err, &apiErr) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// stopError marks an error that ends FollowLogs rather than resuming the
+// stream.
+type stopError struct{ err error }
+
+func (e stopError) Error() string { return e.err.Error() }
+
+// streamLogs reads one connection of a log stream. It advances lastID past
+// each chunk handed to fn.
+func (c *Client) streamLogs(ctx context.Context, path string, lastID *int64, fn func(AIJobLog) error) (*AIJob, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastID != 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(*lastID, 10))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("open log stream: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var job *AIJob
+	err = readStream(resp.Body, func(event, data string) error {
+		switch event {
+		case "log":
+			var l AIJobLog
+			if err := json.Unmarshal([]byte(data), &l); err != nil {
+				return stopError{fmt.Errorf("decode log chunk: %w", err)}
+			}
+			if err := fn(l); err != nil {
+				return stopError{err}
+			}
+			*lastID = l.ID
+		case "end":
+			job = new(AIJob)
+			if err := json.Unmarshal([]byte(data), job); err != nil {
+				return stopError{fmt.Errorf("decode finished job: %w", err)}
+			}
+			return io.EOF
+		}
+		return nil
+	})
+	if errors.Is(err, io.EOF) && job != nil {
+		return job, nil
+	}
+	return nil, err
+}
+
+// readStream calls fn with each server-sent event on r until fn returns an
+// error or r ends. Comments, used as heartbeats, are skipped.
+func readStream(r io.Reader, fn func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if event == "" {
+					event = "message"
+				}
+				if err := fn(event, strings.Join(data, "\n")); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read log stream: %w", err)
+	}
+	return errStreamEnded
+}